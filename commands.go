@@ -0,0 +1,371 @@
+package main
+
+import (
+	"camera-dashboard-go/internal/config"
+	"camera-dashboard-go/internal/helpers"
+	"camera-dashboard-go/pkg/capture"
+	_ "embed"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// =============================================================================
+// Commands
+// =============================================================================
+// The commands besides run: selftest, record and config validate/dump.
+// They print to stdout and return an error for a non-zero exit status.
+// record and selftest capture through pkg/capture, with the camera
+// settings from the config but without the UI.
+// =============================================================================
+
+// configTemplate is the commented config.ini that config dump fills in.
+//
+//go:embed config.ini
+var configTemplate []byte
+
+// captureOptions maps the config's camera settings to capture options.
+func captureOptions(cfg *config.Config) capture.Options {
+	return capture.Options{
+		Width:          cfg.CaptureWidth,
+		Height:         cfg.CaptureHeight,
+		FPS:            cfg.CaptureFPS,
+		Format:         cfg.CaptureFormat,
+		MaxCameras:     cfg.CameraSlotCount,
+		Disabled:       cfg.DisabledCameras,
+		PerCamera:      cameraOptions(cfg.CameraSections),
+		NetworkCameras: cfg.NetworkCameras,
+		CSICameras:     cfg.CSICameras,
+		Simulate:       cfg.SimulateSources,
+		Controls:       cfg.CameraControls,
+		NoTestPattern:  !cfg.TestPattern,
+	}
+}
+
+// cameraOptions maps [camera.<name>] sections to per-camera options.
+func cameraOptions(sections []config.CameraSection) []capture.CameraOptions {
+	opts := make([]capture.CameraOptions, len(sections))
+	for i, cs := range sections {
+		opts[i] = capture.CameraOptions{Match: cs.Match, Width: cs.Width, Height: cs.Height, FPS: cs.FPS, Format: cs.Format}
+	}
+	return opts
+}
+
+// selftest checks what the dashboard needs on this machine and prints
+// one PASS/WARN/FAIL line per check. It fails if any check failed.
+func selftest(cfg *config.Config, loadErr error, timeout time.Duration, simulate string) error {
+	failed := 0
+	report := func(status, check, detail string) {
+		fmt.Printf("%-4s  %-14s %s\n", status, check, detail)
+		if status == "FAIL" {
+			failed++
+		}
+	}
+
+	if loadErr != nil {
+		report("FAIL", "config", loadErr.Error())
+		cfg = config.DefaultConfig()
+	} else {
+		for _, w := range cfg.Warnings {
+			report("WARN", "config", w.String())
+		}
+		ok, warnings := cfg.Validate()
+		if ok {
+			report("PASS", "config", cfg.Path)
+		} else {
+			report("FAIL", "config", cfg.Path+": validation failed")
+		}
+		for _, w := range warnings {
+			report("WARN", "config", w)
+		}
+	}
+	cfg.PrepareStorage()
+	addSimulateSources(cfg, simulate)
+
+	tools := []string{"ffmpeg"}
+	if runtime.GOOS == "linux" {
+		tools = append(tools, "v4l2-ctl") // Camera discovery
+	}
+	for _, tool := range tools {
+		if path, err := exec.LookPath(tool); err != nil {
+			report("FAIL", tool, "not found in PATH")
+		} else {
+			report("PASS", tool, path)
+		}
+	}
+
+	if runtimeName := helpers.DetectContainer(); runtimeName != "" {
+		report("PASS", "container", runtimeName)
+		for _, issue := range helpers.CheckDeviceAccess(false) {
+			report("FAIL", "container", issue.Problem+" - pass "+issue.Fix)
+		}
+	}
+
+	for _, dir := range []struct{ name, path string }{
+		{"snapshots", cfg.SnapshotDir},
+		{"clips", cfg.ClipsDir},
+	} {
+		if dir.path == "" {
+			continue
+		}
+		if err := checkWritable(dir.path); err != nil {
+			report("WARN", dir.name, err.Error())
+		} else {
+			report("PASS", dir.name, dir.path)
+		}
+	}
+
+	selftestCameras(cfg, timeout, report)
+
+	if failed > 0 {
+		return fmt.Errorf("%d checks failed", failed)
+	}
+	fmt.Println("\nAll checks passed.")
+	return nil
+}
+
+// selftestCameras starts every camera and reports whether each delivers
+// a live picture within timeout.
+func selftestCameras(cfg *config.Config, timeout time.Duration, report func(status, check, detail string)) {
+	m := capture.NewManager(captureOptions(cfg))
+	if err := m.Start(); err != nil {
+		report("FAIL", "cameras", err.Error())
+		return
+	}
+	defer m.Stop()
+	cams := m.Cameras()
+	if len(cams) == 0 {
+		report("FAIL", "cameras", "none found")
+		return
+	}
+
+	live := func(id string) bool {
+		return !m.SignalLost(id) && m.FrameBuffer(id).GetFrameCount() > 0
+	}
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		all := true
+		for _, cam := range cams {
+			all = all && live(cam.ID)
+		}
+		if all {
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	time.Sleep(2 * time.Second) // Long enough for a frame rate
+
+	for _, cam := range cams {
+		check := "camera " + cam.ID
+		if !live(cam.ID) {
+			report("FAIL", check, fmt.Sprintf("%s (%s): no picture within %v", cam.Name, cam.Path, timeout))
+			continue
+		}
+		fb := m.FrameBuffer(cam.ID)
+		size := fb.Read().Bounds().Size()
+		report("PASS", check, fmt.Sprintf("%s (%s): %dx%d, %.1f FPS", cam.Name, cam.Path, size.X, size.Y, fb.GetActualFPS()))
+	}
+}
+
+// checkWritable creates dir if needed and writes a file in it.
+func checkWritable(dir string) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(dir, ".selftest-*")
+	if err != nil {
+		return err
+	}
+	f.Close()
+	return os.Remove(f.Name())
+}
+
+// recordFlags are the record command's flags.
+type recordFlags struct {
+	duration time.Duration
+	out      string
+	cameras  string
+	simulate string
+}
+
+func (f *recordFlags) register(fs *flag.FlagSet) {
+	fs.DurationVar(&f.duration, "duration", 0, "Stop after this long (0 = until Ctrl-C)")
+	fs.StringVar(&f.out, "out", "recordings", "Directory for the recordings")
+	fs.StringVar(&f.cameras, "cameras", "", "Comma-separated camera IDs to record (default: all)")
+	fs.StringVar(&f.simulate, "simulate", "", "Record these simulated cameras instead (see run -simulate)")
+}
+
+// recordCameras writes each camera's JPEG frames, as captured, to
+// <out>/<time>_<camera>.mjpeg until the duration passes or SIGINT/SIGTERM.
+func recordCameras(cfg *config.Config, flags recordFlags) error {
+	o := captureOptions(cfg)
+	o.ClipWindow = 2 * time.Second // Drained twice a second below
+	m := capture.NewManager(o)
+	if err := m.Start(); err != nil {
+		return err
+	}
+	defer m.Stop()
+
+	var cams []capture.Camera
+	only := make(map[string]bool)
+	for _, id := range strings.Split(flags.cameras, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			only[id] = true
+		}
+	}
+	for _, cam := range m.Cameras() {
+		if len(only) == 0 || only[cam.ID] {
+			cams = append(cams, cam)
+		}
+	}
+	if len(cams) == 0 {
+		return fmt.Errorf("no cameras to record")
+	}
+	if err := os.MkdirAll(flags.out, 0o755); err != nil {
+		return err
+	}
+
+	type recording struct {
+		cam    capture.Camera
+		path   string
+		file   *os.File
+		last   time.Time
+		frames int
+	}
+	stamp := time.Now().Format("20060102_150405")
+	recs := make([]*recording, 0, len(cams))
+	defer func() {
+		for _, r := range recs {
+			r.file.Close()
+		}
+	}()
+	for _, cam := range cams {
+		path := filepath.Join(flags.out, stamp+"_"+cam.ID+".mjpeg")
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+		if err != nil {
+			return err
+		}
+		recs = append(recs, &recording{cam: cam, path: path, file: f})
+		fmt.Printf("Recording %s (%s) to %s\n", cam.ID, cam.Name, path)
+	}
+
+	drain := func() error {
+		for _, r := range recs {
+			for _, frame := range m.ClipFrames(r.cam.ID) {
+				if !frame.At.After(r.last) {
+					continue
+				}
+				if _, err := r.file.Write(frame.JPEG); err != nil {
+					return fmt.Errorf("%s: %w", r.path, err)
+				}
+				r.last = frame.At
+				r.frames++
+			}
+		}
+		return nil
+	}
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(sigCh)
+	var done <-chan time.Time
+	if flags.duration > 0 {
+		done = time.After(flags.duration)
+	}
+	ticker := time.NewTicker(500 * time.Millisecond)
+	defer ticker.Stop()
+	start := time.Now()
+loop:
+	for {
+		select {
+		case <-ticker.C:
+			if err := drain(); err != nil {
+				return err
+			}
+		case <-done:
+			break loop
+		case <-sigCh:
+			break loop
+		}
+	}
+	if err := drain(); err != nil {
+		return err
+	}
+
+	elapsed := time.Since(start).Round(time.Second)
+	for _, r := range recs {
+		if err := r.file.Close(); err != nil {
+			return fmt.Errorf("%s: %w", r.path, err)
+		}
+		if r.frames == 0 {
+			os.Remove(r.path)
+			fmt.Printf("%s: no frames\n", r.cam.ID)
+			continue
+		}
+		fmt.Printf("%s: %d frames in %v -> %s\n", r.cam.ID, r.frames, elapsed, r.path)
+	}
+	return nil
+}
+
+// validateConfig prints every setting's effective value, what the
+// dashboard ignores in the file and the validation warnings. It fails when the file
+// can't be read or a setting stops the dashboard.
+func validateConfig(path string) error {
+	in, err := config.Inspect(path)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("# %s\n", in.Config.Path)
+	section := ""
+	for _, s := range in.Settings {
+		if s.Section != section {
+			section = s.Section
+			fmt.Printf("\n[%s]\n", section)
+		}
+		line := s.Key + " = " + s.Value
+		source := "file"
+		if s.Env != "" {
+			source = s.Env
+		}
+		switch {
+		case !s.Set:
+			line = fmt.Sprintf("%-40s # default", line)
+		case s.Adjusted():
+			line = fmt.Sprintf("%-40s # %s has %q", line, source, s.Raw)
+		case s.Env != "":
+			line = fmt.Sprintf("%-40s # %s", line, s.Env)
+		}
+		fmt.Println(line)
+	}
+	fmt.Println()
+
+	for _, w := range in.Config.Warnings {
+		fmt.Printf("WARNING: %s: %s\n", in.Config.Path, w)
+	}
+	ok, warnings := in.Config.Validate()
+	for _, w := range warnings {
+		fmt.Printf("WARNING: %s\n", w)
+	}
+	if !ok {
+		return fmt.Errorf("%s: validation failed", in.Config.Path)
+	}
+	fmt.Printf("%s: OK\n", in.Config.Path)
+	return nil
+}
+
+// dumpConfig prints config.ini, comments included, with the effective
+// value of every setting, for provisioning another unit.
+func dumpConfig(path string) error {
+	in, err := config.Inspect(path)
+	if err != nil {
+		return err
+	}
+	return in.WriteINI(os.Stdout, configTemplate)
+}
@@ -0,0 +1,169 @@
+// Package alert plays warning tones through ALSA (aplay) or the beep
+// utility.
+package alert
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// =============================================================================
+// Alert tones
+// =============================================================================
+// An alert is a short sequence of tones written as FREQ:MS pairs, e.g.
+// "880:150 660:250" (a frequency of 0 is a pause). Two backends:
+//   aplay  the sequence is synthesized here as a 16-bit mono WAV and
+//          piped to `aplay` (alsa-utils), on a given ALSA device
+//   beep   the sequence is handed to the `beep` utility (PC speaker or
+//          a pwm-beeper / gpio-beeper input device)
+// Playing blocks until the sequence is over.
+// =============================================================================
+
+const (
+	sampleRate = 22050
+	fadeMS     = 5 // Ramp at each tone's start and end, against clicks
+	maxToneMS  = 5000
+	maxFreqHz  = 20000
+)
+
+// Tone is one step of an alert.
+type Tone struct {
+	FreqHz int // 0 = silence
+	MS     int
+}
+
+// ParseTones parses "FREQ:MS FREQ:MS ..." (commas also separate).
+func ParseTones(spec string) ([]Tone, error) {
+	var tones []Tone
+	for _, field := range strings.FieldsFunc(spec, func(r rune) bool { return r == ',' || r == ' ' || r == '\t' }) {
+		f, ms, ok := strings.Cut(field, ":")
+		if !ok {
+			return nil, fmt.Errorf("tone %q: want FREQ:MS", field)
+		}
+		freq, err := strconv.Atoi(f)
+		if err != nil || freq < 0 || freq > maxFreqHz {
+			return nil, fmt.Errorf("tone %q: frequency must be 0..%d Hz", field, maxFreqHz)
+		}
+		d, err := strconv.Atoi(ms)
+		if err != nil || d <= 0 || d > maxToneMS {
+			return nil, fmt.Errorf("tone %q: duration must be 1..%d ms", field, maxToneMS)
+		}
+		tones = append(tones, Tone{FreqHz: freq, MS: d})
+	}
+	if len(tones) == 0 {
+		return nil, fmt.Errorf("no tones")
+	}
+	return tones, nil
+}
+
+// Player plays tone sequences.
+type Player struct {
+	backend string // "aplay" or "beep"
+	device  string // ALSA device for aplay ("" = default)
+	volume  int    // 0-100, aplay only
+}
+
+// NewPlayer returns a player for backend "aplay" or "beep".
+func NewPlayer(backend, device string, volume int) *Player {
+	return &Player{backend: backend, device: device, volume: volume}
+}
+
+// Play plays tones and waits for them to finish.
+func (p *Player) Play(tones []Tone) error {
+	if p.backend == "beep" {
+		out, err := exec.Command("beep", beepArgs(tones)...).CombinedOutput()
+		if err != nil {
+			return fmt.Errorf("beep: %v: %s", err, bytes.TrimSpace(out))
+		}
+		return nil
+	}
+	args := []string{"-q"}
+	if p.device != "" {
+		args = append(args, "-D", p.device)
+	}
+	cmd := exec.Command("aplay", append(args, "-")...)
+	cmd.Stdin = bytes.NewReader(synthWAV(tones, p.volume))
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("aplay: %v: %s", err, bytes.TrimSpace(out))
+	}
+	return nil
+}
+
+// beepArgs returns beep's arguments for tones: each tone is "-f F -l MS"
+// with "-n" between them; a pause becomes the previous tone's delay.
+func beepArgs(tones []Tone) []string {
+	var args []string
+	lead := 0 // Pause before the first tone
+	for _, t := range tones {
+		if t.FreqHz == 0 {
+			if len(args) == 0 {
+				lead += t.MS
+			} else {
+				args = append(args, "-D", strconv.Itoa(t.MS))
+			}
+			continue
+		}
+		if len(args) > 0 {
+			args = append(args, "-n")
+		}
+		args = append(args, "-f", strconv.Itoa(t.FreqHz), "-l", strconv.Itoa(t.MS))
+	}
+	if lead > 0 && len(args) > 0 {
+		// beep has no leading pause; start with a silent tone
+		args = append([]string{"-f", "1", "-l", strconv.Itoa(lead), "-n"}, args...)
+	}
+	return args
+}
+
+// synthWAV renders tones as a 16-bit mono PCM WAV at volume (0-100).
+func synthWAV(tones []Tone, volume int) []byte {
+	if volume < 0 {
+		volume = 0
+	}
+	if volume > 100 {
+		volume = 100
+	}
+	amplitude := float64(volume) / 100 * math.MaxInt16
+	fade := sampleRate * fadeMS / 1000
+
+	var samples []int16
+	for _, t := range tones {
+		n := sampleRate * t.MS / 1000
+		for i := 0; i < n; i++ {
+			if t.FreqHz == 0 {
+				samples = append(samples, 0)
+				continue
+			}
+			gain := 1.0
+			if i < fade {
+				gain = float64(i) / float64(fade)
+			} else if n-i < fade {
+				gain = float64(n-i) / float64(fade)
+			}
+			v := amplitude * gain * math.Sin(2*math.Pi*float64(t.FreqHz)*float64(i)/sampleRate)
+			samples = append(samples, int16(v))
+		}
+	}
+
+	dataSize := uint32(len(samples) * 2)
+	var buf bytes.Buffer
+	buf.WriteString("RIFF")
+	binary.Write(&buf, binary.LittleEndian, 36+dataSize)
+	buf.WriteString("WAVEfmt ")
+	binary.Write(&buf, binary.LittleEndian, uint32(16))           // fmt chunk size
+	binary.Write(&buf, binary.LittleEndian, uint16(1))            // PCM
+	binary.Write(&buf, binary.LittleEndian, uint16(1))            // Mono
+	binary.Write(&buf, binary.LittleEndian, uint32(sampleRate))   // Sample rate
+	binary.Write(&buf, binary.LittleEndian, uint32(sampleRate*2)) // Byte rate
+	binary.Write(&buf, binary.LittleEndian, uint16(2))            // Block align
+	binary.Write(&buf, binary.LittleEndian, uint16(16))           // Bits per sample
+	buf.WriteString("data")
+	binary.Write(&buf, binary.LittleEndian, dataSize)
+	binary.Write(&buf, binary.LittleEndian, samples)
+	return buf.Bytes()
+}
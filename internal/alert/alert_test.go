@@ -0,0 +1,68 @@
+package alert
+
+import (
+	"encoding/binary"
+	"reflect"
+	"testing"
+)
+
+func TestParseTones(t *testing.T) {
+	got, err := ParseTones(" 880:150, 0:50 660:250 ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Tone{{880, 150}, {0, 50}, {660, 250}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseTones() = %v, want %v", got, want)
+	}
+	for _, bad := range []string{"", "880", "880:0", "loud:100", "30000:100", "440:9000"} {
+		if _, err := ParseTones(bad); err == nil {
+			t.Errorf("ParseTones(%q) should fail", bad)
+		}
+	}
+}
+
+func TestBeepArgs(t *testing.T) {
+	got := beepArgs([]Tone{{0, 40}, {880, 150}, {0, 50}, {660, 250}})
+	want := []string{"-f", "1", "-l", "40", "-n", "-f", "880", "-l", "150", "-D", "50", "-n", "-f", "660", "-l", "250"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("beepArgs() = %q, want %q", got, want)
+	}
+}
+
+func TestSynthWAV(t *testing.T) {
+	wav := synthWAV([]Tone{{1000, 100}, {0, 100}}, 50)
+	samples := sampleRate * 200 / 1000
+	if len(wav) != 44+samples*2 {
+		t.Fatalf("WAV is %d bytes, want %d", len(wav), 44+samples*2)
+	}
+	if string(wav[0:4]) != "RIFF" || string(wav[8:16]) != "WAVEfmt " || string(wav[36:40]) != "data" {
+		t.Errorf("bad WAV header %q", wav[:44])
+	}
+	if size := binary.LittleEndian.Uint32(wav[40:44]); size != uint32(samples*2) {
+		t.Errorf("data size = %d, want %d", size, samples*2)
+	}
+
+	// Tone at about half scale, silence after it, no click at the start
+	peak := 0
+	for i := 0; i < samples/2; i++ {
+		v := int(int16(binary.LittleEndian.Uint16(wav[44+2*i:])))
+		if v < 0 {
+			v = -v
+		}
+		if v > peak {
+			peak = v
+		}
+	}
+	if peak < 16000 || peak > 16384 {
+		t.Errorf("tone peak = %d, want about 16384", peak)
+	}
+	if first := int16(binary.LittleEndian.Uint16(wav[46:])); first > 1000 || first < -1000 {
+		t.Errorf("second sample = %d, want faded in", first)
+	}
+	for i := samples / 2; i < samples; i++ {
+		if v := binary.LittleEndian.Uint16(wav[44+2*i:]); v != 0 {
+			t.Fatalf("sample %d = %d in the pause", i, int16(v))
+		}
+	}
+}
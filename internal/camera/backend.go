@@ -0,0 +1,58 @@
+package camera
+
+import (
+	"io"
+	"log"
+)
+
+// =============================================================================
+// Capture backends
+// =============================================================================
+// Normally cameras are discovered with v4l2-ctl (FFmpeg device lists on
+// desktops) and captured by an FFmpeg or rpicam-vid process.
+// Settings.Backend replaces both, so integration tests can drive
+// discovery, stalls and unplugs from Go (see internal/testsupport). The
+// UI's hotplug polling asks the backend too (Present, Discover) instead
+// of looking at /dev.
+//
+// A backend stream is an MJPEG byte stream like FFmpeg's stdout and goes
+// through the same parser, frame skipping, decoder and FrameBuffer. Read
+// should return within a frame interval or so even when no frame comes
+// (a stall), so the worker sees read timeouts and can stop; io.EOF ends
+// the stream like FFmpeg exiting. Stop closes the stream.
+// =============================================================================
+
+// Backend discovers cameras and opens their streams.
+type Backend interface {
+	// Discover returns the cameras present now.
+	Discover(s Settings) ([]Camera, error)
+	// Open starts cam's MJPEG stream at the given capture mode.
+	Open(cam Camera, width, height, fps int) (io.ReadCloser, error)
+	// Present reports whether the camera at devicePath is plugged in.
+	Present(devicePath string) bool
+}
+
+// tryBackendCapture captures from Settings.Backend until the stream ends
+// or the worker stops.
+func (cw *CaptureWorker) tryBackendCapture() bool {
+	w, h := cw.GetResolution()
+	stream, err := cw.settings.Backend.Open(cw.camera, w, h, cw.GetMaxFPS())
+	if err != nil {
+		log.Printf("[Capture] Camera %s: Failed to open stream: %v", cw.camera.DeviceID, err)
+		return false
+	}
+	cw.ffmpegMu.Lock()
+	cw.stream = stream
+	cw.ffmpegMu.Unlock()
+	defer func() {
+		cw.ffmpegMu.Lock()
+		stream.Close()
+		cw.stream = nil
+		cw.ffmpegMu.Unlock()
+	}()
+
+	log.Printf("[Capture] Camera %s: Backend stream opened - %dx%d @ %d FPS", cw.camera.DeviceID, w, h, cw.GetMaxFPS())
+	format := "backend"
+	cw.format.Store(&format)
+	return cw.readStream(stream, format)
+}
@@ -0,0 +1,107 @@
+package camera
+
+import (
+	"log"
+	"math/rand"
+	"time"
+)
+
+// =============================================================================
+// Reconnect backoff
+// =============================================================================
+// A worker whose camera can't be opened shows a test pattern and retries.
+// Retrying at a fixed rate, walking every input variant each time, keeps
+// hammering a flaky USB hub that may just need a moment to settle. So:
+//
+//   - The wait between attempts starts at reconnectBaseDelay and doubles
+//     with each consecutive failure up to Settings.ReconnectCooldown
+//     ([camera] failed_camera_cooldown_sec), spread by reconnectJitter so
+//     cameras on one hub don't all retry at the same instant.
+//   - Each camera gets Settings.ReconnectBudget full attempts. Once spent,
+//     the wait is always the full cooldown and an attempt tries one variant
+//     only: the format that last delivered a frame, or the configured one.
+//
+// A stream that delivers a frame refills the budget and resets the wait.
+// =============================================================================
+
+const (
+	reconnectBaseDelay       = 2 * time.Second
+	defaultReconnectCooldown = 30 * time.Second
+	defaultReconnectBudget   = 5
+	reconnectJitter          = 0.2 // +/- share of the delay
+)
+
+// reconnectDelay returns the wait before the next attempt after failures
+// consecutive failed ones out of budget; r is a uniform random number in
+// [0, 1).
+func reconnectDelay(failures, budget int, cooldown time.Duration, r float64) time.Duration {
+	if cooldown <= 0 {
+		cooldown = defaultReconnectCooldown
+	}
+	d := reconnectBaseDelay
+	for i := 1; i < failures && d < cooldown; i++ {
+		d *= 2
+	}
+	if failures >= budget {
+		d = cooldown
+	}
+	if d > cooldown {
+		d = cooldown
+	}
+	return time.Duration(float64(d) * (1 - reconnectJitter + 2*reconnectJitter*r))
+}
+
+// preferredVariant narrows a spent camera's input variants to the one
+// whose format last delivered a frame (good), or the first.
+func preferredVariant(devicePath string, variants [][]string, good string) [][]string {
+	for _, args := range variants {
+		if good != "" && streamFormat(devicePath, args) == good {
+			return [][]string{args}
+		}
+	}
+	if len(variants) > 1 {
+		return variants[:1]
+	}
+	return variants
+}
+
+// SetReconnectCooldown changes the longest wait between reconnect
+// attempts (config reload); it applies from the next attempt.
+func (cw *CaptureWorker) SetReconnectCooldown(d time.Duration) {
+	cw.reconnectCooldown.Store(int64(d))
+}
+
+// reconnectWait is the wait before the next reconnect attempt.
+func (cw *CaptureWorker) reconnectWait() time.Duration {
+	return reconnectDelay(cw.reconnectFailures, cw.reconnectBudget(), time.Duration(cw.reconnectCooldown.Load()), rand.Float64())
+}
+
+// reconnectBudget is the number of full attempts before narrowing.
+func (cw *CaptureWorker) reconnectBudget() int {
+	if cw.settings.ReconnectBudget <= 0 {
+		return defaultReconnectBudget
+	}
+	return cw.settings.ReconnectBudget
+}
+
+// budgetSpent reports whether the camera has used up its full attempts.
+func (cw *CaptureWorker) budgetSpent() bool {
+	return cw.reconnectFailures >= cw.reconnectBudget()
+}
+
+// reconnectFailed counts a failed attempt against the budget.
+func (cw *CaptureWorker) reconnectFailed() {
+	cw.reconnectFailures++
+	if cw.budgetSpent() && !cw.reconnectSpentLogged {
+		cw.reconnectSpentLogged = true
+		log.Printf("[Capture] Camera %s: %d attempts failed, retrying one format every ~%s",
+			cw.camera.DeviceID, cw.reconnectFailures, time.Duration(cw.reconnectCooldown.Load()))
+	}
+}
+
+// reconnected refills the budget once a stream delivers a frame.
+func (cw *CaptureWorker) reconnected(format string) {
+	cw.reconnectFailures = 0
+	cw.reconnectSpentLogged = false
+	cw.goodFormat = format
+}
@@ -0,0 +1,94 @@
+package camera
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestReconnectDelay(t *testing.T) {
+	cooldown := 30 * time.Second
+	for _, tc := range []struct {
+		failures int
+		want     time.Duration
+	}{
+		{0, 2 * time.Second},
+		{1, 2 * time.Second},
+		{2, 4 * time.Second},
+		{4, 16 * time.Second},
+		{5, 30 * time.Second},
+		{50, 30 * time.Second},
+	} {
+		if got := reconnectDelay(tc.failures, 10, cooldown, 0.5); got != tc.want {
+			t.Errorf("reconnectDelay(%d) = %v, want %v", tc.failures, got, tc.want)
+		}
+	}
+
+	// Jitter spreads the delay by +/- 20%
+	if got, want := reconnectDelay(3, 10, cooldown, 0), 6400*time.Millisecond; got != want {
+		t.Errorf("low jitter = %v, want %v", got, want)
+	}
+	if got, want := reconnectDelay(3, 10, cooldown, 0.999), 9600*time.Millisecond; got < 9590*time.Millisecond || got > want {
+		t.Errorf("high jitter = %v, want just under %v", got, want)
+	}
+
+	// A spent budget waits the full cooldown
+	if got := reconnectDelay(2, 2, cooldown, 0.5); got != cooldown {
+		t.Errorf("spent budget = %v, want %v", got, cooldown)
+	}
+
+	// A cooldown below the base delay caps the first wait too
+	if got := reconnectDelay(1, 10, time.Second, 0.5); got != time.Second {
+		t.Errorf("short cooldown = %v, want 1s", got)
+	}
+	if got := reconnectDelay(9, 10, 0, 0.5); got != defaultReconnectCooldown {
+		t.Errorf("zero cooldown = %v, want default %v", got, defaultReconnectCooldown)
+	}
+}
+
+func TestPreferredVariant(t *testing.T) {
+	mjpeg := []string{"-f", "v4l2", "-input_format", "mjpeg", "-i", "/dev/video0"}
+	yuyv := []string{"-f", "v4l2", "-input_format", "yuyv422", "-i", "/dev/video0"}
+	auto := []string{"-f", "v4l2", "-i", "/dev/video0"}
+	variants := [][]string{mjpeg, yuyv, auto}
+
+	if got := preferredVariant("/dev/video0", variants, "yuyv"); !reflect.DeepEqual(got, [][]string{yuyv}) {
+		t.Errorf("good yuyv: got %v", got)
+	}
+	if got := preferredVariant("/dev/video0", variants, ""); !reflect.DeepEqual(got, [][]string{mjpeg}) {
+		t.Errorf("no good format: got %v, want the first variant", got)
+	}
+	if got := preferredVariant("/dev/video0", variants, "csi"); !reflect.DeepEqual(got, [][]string{mjpeg}) {
+		t.Errorf("unknown good format: got %v, want the first variant", got)
+	}
+}
+
+func TestCaptureWorker_ReconnectBudget(t *testing.T) {
+	cw := NewCaptureWorkerWithBuffer(Camera{DeviceID: "video0"}, NewFrameBuffer(),
+		Settings{FPS: 10, ReconnectBudget: 3, ReconnectCooldown: 20 * time.Second})
+	for i := 0; i < 3; i++ {
+		if cw.budgetSpent() {
+			t.Fatalf("budget spent after %d failures, want 3", i)
+		}
+		cw.reconnectFailed()
+	}
+	if !cw.budgetSpent() {
+		t.Fatal("budget not spent after 3 failures")
+	}
+	if d := cw.reconnectWait(); d < 16*time.Second || d > 24*time.Second {
+		t.Errorf("wait = %v, want about the 20s cooldown", d)
+	}
+
+	cw.SetReconnectCooldown(5 * time.Second)
+	if d := cw.reconnectWait(); d > 6*time.Second {
+		t.Errorf("wait after SetReconnectCooldown = %v, want about 5s", d)
+	}
+
+	cw.reconnected("yuyv")
+	if cw.budgetSpent() || cw.goodFormat != "yuyv" {
+		t.Errorf("after a frame: spent = %v, goodFormat = %q", cw.budgetSpent(), cw.goodFormat)
+	}
+	if d := cw.reconnectWait(); d > 3*time.Second {
+		t.Errorf("wait after a frame = %v, want the base delay", d)
+	}
+}
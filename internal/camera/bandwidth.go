@@ -0,0 +1,290 @@
+package camera
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+)
+
+// =============================================================================
+// USB bandwidth scheduler
+// =============================================================================
+// Every MJPEG camera on a USB 2.0 bus shares that bus's bandwidth (about
+// 35 MB/s in practice). When the cameras together get close to it, the
+// bus starts dropping isochronous packets and streams fail one after
+// another. With [bandwidth] enabled the scheduler measures what each
+// camera actually sends (MJPEG frame sizes vary with the scene: a busy
+// street at dusk is several times a parked-car view) and acts before
+// that happens:
+//
+//   - A bus above bandwidthHigh of the budget steps its least important
+//     camera down one level: half the capture frame rate, down to
+//     min_fps, then the configured resolution tiers.
+//   - A bus below bandwidthLow for bandwidthRecoverHold steps its most
+//     important limited camera back up, if doubling that camera's share
+//     still stays under bandwidthHigh.
+//
+// Each change restarts one FFmpeg process, so a bus is left alone for
+// bandwidthSettle afterwards while its rates settle. Importance is the
+// [bandwidth] priority list, then slot order. Network and CSI cameras
+// don't use the USB bus and are not scheduled.
+// =============================================================================
+
+const (
+	bandwidthInterval    = 5 * time.Second  // Measurement period
+	bandwidthHigh        = 0.90             // Step down above this share of the budget
+	bandwidthLow         = 0.70             // Step up below this share...
+	bandwidthRecoverHold = 30 * time.Second // ...held this long
+	bandwidthSettle      = 15 * time.Second // Pause on a bus after a change
+)
+
+// bandwidthStep is one capture mode a camera can be limited to (0 = the
+// camera's own value).
+type bandwidthStep struct {
+	fps, w, h int
+}
+
+// bandwidthSteps returns the capture modes from full (index 0) down:
+// halving fps to minFPS, then the resolution tiers smaller than w x h.
+func bandwidthSteps(fps, w, h, minFPS int, tiers [][2]int) []bandwidthStep {
+	steps := []bandwidthStep{{}}
+	limit := fps
+	for limit > minFPS {
+		limit /= 2
+		if limit < minFPS {
+			limit = minFPS
+		}
+		steps = append(steps, bandwidthStep{fps: limit})
+	}
+	for _, t := range tiers {
+		if t[0] > 0 && t[1] > 0 && t[0]*t[1] < w*h {
+			steps = append(steps, bandwidthStep{fps: limit, w: t[0], h: t[1]})
+		}
+	}
+	return steps
+}
+
+// bandwidthCamera is the scheduler's view of one USB camera.
+type bandwidthCamera struct {
+	id        string
+	bus       int
+	priority  int     // Lower = more important
+	rate      float64 // Measured bytes per second
+	level     int     // Index into steps
+	steps     []bandwidthStep
+	lastBytes uint64
+	worker    *CaptureWorker
+}
+
+// BandwidthScheduler keeps the cameras on each USB 2.0 bus under the
+// bandwidth budget.
+type BandwidthScheduler struct {
+	manager  *Manager
+	settings Settings
+
+	mu          sync.Mutex
+	cameras     map[string]*bandwidthCamera // By device ID
+	lastTick    time.Time
+	coolSince   map[int]time.Time // Per bus; zero when not below bandwidthLow
+	settleUntil map[int]time.Time // Per bus
+	warned      map[int]bool      // Over budget with nothing left to lower
+
+	stopCh chan struct{}
+	wg     sync.WaitGroup
+}
+
+// NewBandwidthScheduler creates a scheduler for the manager's cameras.
+func NewBandwidthScheduler(m *Manager, s Settings) *BandwidthScheduler {
+	return &BandwidthScheduler{
+		manager:     m,
+		settings:    s,
+		cameras:     make(map[string]*bandwidthCamera),
+		coolSince:   make(map[int]time.Time),
+		settleUntil: make(map[int]time.Time),
+		warned:      make(map[int]bool),
+		stopCh:      make(chan struct{}),
+	}
+}
+
+// Start measures and balances every bandwidthInterval until Stop.
+func (b *BandwidthScheduler) Start() {
+	log.Printf("[Bandwidth] Scheduler on: budget %.1f MB/s per USB 2.0 bus, min %d FPS",
+		b.settings.BandwidthBudget/1e6, b.settings.BandwidthMinFPS)
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+		ticker := time.NewTicker(bandwidthInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-b.stopCh:
+				return
+			case now := <-ticker.C:
+				b.tick(now)
+			}
+		}
+	}()
+}
+
+// Stop ends the scheduler. Limits already applied stay with the workers.
+func (b *BandwidthScheduler) Stop() {
+	close(b.stopCh)
+	b.wg.Wait()
+}
+
+// tick measures every camera and balances each bus.
+func (b *BandwidthScheduler) tick(now time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.refresh(now)
+	buses := make(map[int][]*bandwidthCamera)
+	for _, c := range b.cameras {
+		buses[c.bus] = append(buses[c.bus], c)
+	}
+	for bus, cams := range buses {
+		cam, delta := b.balance(bus, cams, now)
+		if cam == nil {
+			continue
+		}
+		cam.level += delta
+		step := cam.steps[cam.level]
+		total := 0.0
+		for _, c := range cams {
+			total += c.rate
+		}
+		direction := "over"
+		if delta < 0 {
+			direction = "under"
+		}
+		log.Printf("[Bandwidth] Bus %d %s budget (%.1f of %.1f MB/s): camera %s to level %d/%d (%s)",
+			bus, direction, total/1e6, b.settings.BandwidthBudget/1e6, cam.id, cam.level, len(cam.steps)-1, step)
+		if err := cam.worker.SetBandwidthLimit(step.fps, step.w, step.h); err != nil {
+			log.Printf("[Bandwidth] WARNING: camera %s: %v", cam.id, err)
+		}
+	}
+}
+
+// refresh picks up the manager's current workers and measures their
+// rates since the last tick.
+func (b *BandwidthScheduler) refresh(now time.Time) {
+	elapsed := now.Sub(b.lastTick).Seconds()
+	b.lastTick = now
+
+	cameras := b.manager.GetCameras()
+	workers := b.manager.GetWorkers()
+	seen := make(map[string]bool)
+	for i, cam := range cameras {
+		if i >= len(workers) || workers[i] == nil || !cam.HasDeviceNode() || IsNetworkSource(cam.DevicePath) {
+			continue
+		}
+		if _, ok := csiIndex(cam.DevicePath); ok {
+			continue
+		}
+		worker := workers[i]
+		c := b.cameras[cam.DeviceID]
+		if c == nil || c.worker != worker {
+			// New camera, or recreated by a hotplug reinit
+			topo := ProbeUSBTopology(cam.DevicePath)
+			if topo == nil || !topo.IsUSB2Bus() {
+				continue
+			}
+			w, h := worker.GetResolution()
+			c = &bandwidthCamera{
+				id:        cam.DeviceID,
+				bus:       topo.Bus,
+				steps:     bandwidthSteps(worker.GetMaxFPS(), w, h, b.settings.BandwidthMinFPS, b.settings.BandwidthTiers),
+				lastBytes: worker.BytesRead(),
+				worker:    worker,
+			}
+			b.cameras[cam.DeviceID] = c
+		} else if elapsed > 0 {
+			bytes := worker.BytesRead()
+			c.rate = float64(bytes-c.lastBytes) / elapsed
+			c.lastBytes = bytes
+		}
+		c.priority = b.priority(cam, i)
+		seen[cam.DeviceID] = true
+	}
+	for id := range b.cameras {
+		if !seen[id] {
+			delete(b.cameras, id)
+		}
+	}
+}
+
+// priority ranks cam: its position in the priority list, else after all
+// listed cameras in slot order.
+func (b *BandwidthScheduler) priority(cam Camera, slot int) int {
+	for i, entry := range b.settings.BandwidthPriority {
+		if MatchesCamera(entry, cam) {
+			return i
+		}
+	}
+	return len(b.settings.BandwidthPriority) + slot
+}
+
+// balance decides one change on bus: the camera to move and the level
+// delta (+1 = lower its bandwidth, -1 = restore one step), or nil.
+func (b *BandwidthScheduler) balance(bus int, cams []*bandwidthCamera, now time.Time) (*bandwidthCamera, int) {
+	if now.Before(b.settleUntil[bus]) {
+		return nil, 0
+	}
+	total := 0.0
+	for _, c := range cams {
+		total += c.rate
+	}
+	budget := b.settings.BandwidthBudget
+
+	// Least important first
+	sort.Slice(cams, func(i, j int) bool { return cams[i].priority > cams[j].priority })
+
+	switch {
+	case total > bandwidthHigh*budget:
+		b.coolSince[bus] = time.Time{}
+		for _, c := range cams {
+			if c.level < len(c.steps)-1 {
+				b.settleUntil[bus] = now.Add(bandwidthSettle)
+				b.warned[bus] = false
+				return c, 1
+			}
+		}
+		if !b.warned[bus] {
+			log.Printf("[Bandwidth] WARNING: bus %d at %.1f of %.1f MB/s with every camera at its lowest level",
+				bus, total/1e6, budget/1e6)
+			b.warned[bus] = true
+		}
+	case total < bandwidthLow*budget:
+		if b.coolSince[bus].IsZero() {
+			b.coolSince[bus] = now
+		}
+		if now.Sub(b.coolSince[bus]) < bandwidthRecoverHold {
+			return nil, 0
+		}
+		for i := len(cams) - 1; i >= 0; i-- {
+			c := cams[i]
+			if c.level > 0 && total+c.rate < bandwidthHigh*budget {
+				b.coolSince[bus] = time.Time{}
+				b.settleUntil[bus] = now.Add(bandwidthSettle)
+				return c, -1
+			}
+		}
+	default:
+		b.coolSince[bus] = time.Time{}
+	}
+	return nil, 0
+}
+
+// String formats the step for logs.
+func (s bandwidthStep) String() string {
+	switch {
+	case s.fps == 0:
+		return "full"
+	case s.w == 0:
+		return fmt.Sprintf("%d FPS", s.fps)
+	default:
+		return fmt.Sprintf("%dx%d @ %d FPS", s.w, s.h, s.fps)
+	}
+}
@@ -0,0 +1,54 @@
+package camera
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestBandwidthSteps(t *testing.T) {
+	got := bandwidthSteps(30, 640, 480, 10, [][2]int{{800, 600}, {480, 360}, {320, 240}})
+	want := []bandwidthStep{{}, {fps: 15}, {fps: 10}, {fps: 10, w: 480, h: 360}, {fps: 10, w: 320, h: 240}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("bandwidthSteps() = %v, want %v", got, want)
+	}
+	if got := bandwidthSteps(10, 640, 480, 10, nil); len(got) != 1 {
+		t.Errorf("already at min FPS without tiers: %v, want only the full step", got)
+	}
+}
+
+func TestBandwidthScheduler_Balance(t *testing.T) {
+	const mb = 1e6
+	b := NewBandwidthScheduler(nil, Settings{BandwidthBudget: 30 * mb})
+	steps := bandwidthSteps(30, 640, 480, 10, nil)
+	front := &bandwidthCamera{id: "video0", priority: 0, rate: 15 * mb, steps: steps}
+	rear := &bandwidthCamera{id: "video2", priority: 1, rate: 14 * mb, steps: steps}
+	cams := []*bandwidthCamera{front, rear}
+	now := time.Unix(0, 0)
+
+	// Over 90%: the least important camera steps down first
+	if c, d := b.balance(1, cams, now); c != rear || d != 1 {
+		t.Fatalf("over budget: got %v %+d, want video2 +1", c, d)
+	}
+	rear.level = 1
+	if c, _ := b.balance(1, cams, now.Add(time.Second)); c != nil {
+		t.Errorf("within the settle time: got %s, want no change", c.id)
+	}
+	// Rear camera exhausted: the next one steps down
+	rear.level = len(steps) - 1
+	if c, d := b.balance(1, cams, now.Add(bandwidthSettle)); c != front || d != 1 {
+		t.Errorf("rear at its lowest level: got %v %+d, want video0 +1", c, d)
+	}
+	front.level = 1
+
+	// Quiet bus: the most important camera comes back after the hold,
+	// as long as doubling its share stays under the budget
+	front.rate, rear.rate = 6*mb, 4*mb
+	start := now.Add(2 * bandwidthSettle)
+	if c, _ := b.balance(1, cams, start); c != nil {
+		t.Errorf("cool just now: got %s, want no change before the hold", c.id)
+	}
+	if c, d := b.balance(1, cams, start.Add(bandwidthRecoverHold)); c != front || d != -1 {
+		t.Errorf("cool for the hold: got %v %+d, want video0 -1", c, d)
+	}
+}
@@ -0,0 +1,170 @@
+package camera
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// =============================================================================
+// Capability cache
+// =============================================================================
+// `v4l2-ctl --list-formats-ext` is slow (hundreds of ms per camera) and
+// can fail with EBUSY while FFmpeg holds the device. The parsed format
+// list is cached in a JSON file keyed by USB vendor:product:serial, so
+// rediscovery (startup, hotplug) only probes cameras it hasn't seen.
+//
+// Only the raw probe result is cached; the resolution/FPS actually used
+// is still derived from current settings on every discovery.
+// =============================================================================
+
+// capabilityProbe is the settings-independent result of probing a camera.
+type capabilityProbe struct {
+	Resolutions []ResolutionPreset `json:"resolutions"` // MJPEG discrete sizes
+	MaxFPS      int                `json:"max_fps"`     // Highest MJPEG frame rate
+	ProbedAt    time.Time          `json:"probed_at"`
+}
+
+type capsCacheFile struct {
+	Version int                         `json:"version"`
+	Cameras map[string]*capabilityProbe `json:"cameras"`
+}
+
+const capsCacheVersion = 1
+
+// capsCacheMu serialises cache file reads/writes within the process.
+var capsCacheMu sync.Mutex
+
+// loadCapsCache reads the cache file; a missing or unreadable file yields
+// an empty cache.
+func loadCapsCache(path string) *capsCacheFile {
+	cache := &capsCacheFile{Version: capsCacheVersion, Cameras: make(map[string]*capabilityProbe)}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Printf("[Discovery] WARNING: cannot read capability cache %s: %v", path, err)
+		}
+		return cache
+	}
+	var loaded capsCacheFile
+	if err := json.Unmarshal(data, &loaded); err != nil || loaded.Version != capsCacheVersion || loaded.Cameras == nil {
+		log.Printf("[Discovery] Ignoring stale or invalid capability cache %s", path)
+		return cache
+	}
+	return &loaded
+}
+
+// saveCapsCache writes the cache atomically (temp file + rename).
+func saveCapsCache(path string, cache *capsCacheFile) error {
+	data, err := json.MarshalIndent(cache, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// cachedProbe returns the probe for devicePath, from the cache when the
+// device has a USB identity already recorded, otherwise by running
+// probe and recording the result. cachePath "" disables caching.
+func cachedProbe(cachePath, devicePath string, probe func(string) (*capabilityProbe, error)) (*capabilityProbe, error) {
+	key := ""
+	if cachePath != "" {
+		key = usbIdentity(devicePath)
+	}
+	if key == "" {
+		return probe(devicePath)
+	}
+
+	capsCacheMu.Lock()
+	cache := loadCapsCache(cachePath)
+	capsCacheMu.Unlock()
+
+	if p, ok := cache.Cameras[key]; ok {
+		log.Printf("[Discovery] %s: using cached capabilities for %s", devicePath, key)
+		return p, nil
+	}
+
+	p, err := probe(devicePath)
+	if err != nil {
+		return nil, err
+	}
+	if len(p.Resolutions) == 0 {
+		return p, nil // Don't cache empty results; likely a transient failure
+	}
+
+	capsCacheMu.Lock()
+	defer capsCacheMu.Unlock()
+	cache = loadCapsCache(cachePath) // Reload in case another discovery wrote meanwhile
+	cache.Cameras[key] = p
+	if err := saveCapsCache(cachePath, cache); err != nil {
+		log.Printf("[Discovery] WARNING: cannot write capability cache %s: %v", cachePath, err)
+	}
+	return p, nil
+}
+
+// InvalidateCapabilityCache deletes the cache file so every camera is
+// re-probed on the next discovery.
+func InvalidateCapabilityCache(cachePath string) error {
+	if cachePath == "" {
+		return nil
+	}
+	capsCacheMu.Lock()
+	defer capsCacheMu.Unlock()
+	if err := os.Remove(cachePath); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// sysfsRoot is overridden in tests.
+var sysfsRoot = "/sys"
+
+// usbIdentity returns "vendor:product:serial" for a /dev/videoN device.
+// Returns "" for non-USB devices. serial may be empty for cameras
+// without one.
+func usbIdentity(devicePath string) string {
+	d := ReadUSBDescriptor(devicePath)
+	if d.VendorID == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s:%s:%s", d.VendorID, d.ProductID, d.Serial)
+}
+
+// usbDeviceDir resolves the sysfs directory of the USB device that owns
+// a /dev/videoN node by walking up from its interface directory to the
+// first ancestor with idVendor/idProduct. Returns "" for non-USB devices.
+func usbDeviceDir(devicePath string) string {
+	devLink := filepath.Join(sysfsRoot, "class", "video4linux", filepath.Base(devicePath), "device")
+	dir, err := filepath.EvalSymlinks(devLink)
+	if err != nil {
+		return ""
+	}
+
+	for i := 0; i < 4 && dir != "/" && dir != "."; i++ {
+		if readSysfsAttr(filepath.Join(dir, "idVendor")) != "" && readSysfsAttr(filepath.Join(dir, "idProduct")) != "" {
+			return dir
+		}
+		dir = filepath.Dir(dir)
+	}
+	return ""
+}
+
+func readSysfsAttr(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
@@ -0,0 +1,172 @@
+package camera
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const sampleFormats = `ioctl: VIDIOC_ENUM_FMT
+	Type: Video Capture
+
+	[0]: 'MJPG' (Motion-JPEG, compressed)
+		Size: Discrete 1280x720
+			Interval: Discrete 0.033s (30.000 fps)
+		Size: Discrete 640x480
+			Interval: Discrete 0.033s (30.000 fps)
+			Interval: Discrete 0.017s (60.000 fps)
+	[1]: 'YUYV' (YUYV 4:2:2)
+		Size: Discrete 640x480
+			Interval: Discrete 0.008s (120.000 fps)
+`
+
+func TestParseFormatList(t *testing.T) {
+	p := parseFormatList(sampleFormats)
+	if len(p.Resolutions) != 2 {
+		t.Fatalf("resolutions = %v, want 2 MJPEG sizes", p.Resolutions)
+	}
+	if p.Resolutions[0].Width != 1280 || p.Resolutions[1].Height != 480 {
+		t.Errorf("resolutions = %v", p.Resolutions)
+	}
+	if p.MaxFPS != 60 {
+		t.Errorf("MaxFPS = %d, want 60 (YUYV rates ignored)", p.MaxFPS)
+	}
+}
+
+// fakeUSBCamera builds a minimal sysfs tree for videoN under root.
+func fakeUSBCamera(t *testing.T, root, video, vendor, product, serial string) {
+	t.Helper()
+	usbDev := filepath.Join(root, "devices", "usb1", "1-1")
+	iface := filepath.Join(usbDev, "1-1:1.0")
+	if err := os.MkdirAll(iface, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(filepath.Join(usbDev, "idVendor"), []byte(vendor+"\n"), 0o644)
+	os.WriteFile(filepath.Join(usbDev, "idProduct"), []byte(product+"\n"), 0o644)
+	if serial != "" {
+		os.WriteFile(filepath.Join(usbDev, "serial"), []byte(serial+"\n"), 0o644)
+	}
+	classDir := filepath.Join(root, "class", "video4linux", video)
+	if err := os.MkdirAll(classDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(iface, filepath.Join(classDir, "device")); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func withSysfsRoot(t *testing.T, root string) {
+	t.Helper()
+	old := sysfsRoot
+	sysfsRoot = root
+	t.Cleanup(func() { sysfsRoot = old })
+}
+
+func TestUSBIdentity(t *testing.T) {
+	root := t.TempDir()
+	fakeUSBCamera(t, root, "video0", "046d", "0825", "ABC123")
+	withSysfsRoot(t, root)
+
+	if got := usbIdentity("/dev/video0"); got != "046d:0825:ABC123" {
+		t.Errorf("usbIdentity = %q, want 046d:0825:ABC123", got)
+	}
+	if got := usbIdentity("/dev/video9"); got != "" {
+		t.Errorf("usbIdentity for missing device = %q, want empty", got)
+	}
+}
+
+func TestCachedProbe(t *testing.T) {
+	root := t.TempDir()
+	fakeUSBCamera(t, root, "video0", "046d", "0825", "")
+	withSysfsRoot(t, root)
+	cachePath := filepath.Join(t.TempDir(), "caps.json")
+
+	calls := 0
+	probe := func(string) (*capabilityProbe, error) {
+		calls++
+		return parseFormatList(sampleFormats), nil
+	}
+
+	for i := 0; i < 3; i++ {
+		p, err := cachedProbe(cachePath, "/dev/video0", probe)
+		if err != nil {
+			t.Fatalf("cachedProbe error: %v", err)
+		}
+		if p.MaxFPS != 60 || len(p.Resolutions) != 2 {
+			t.Fatalf("probe = %+v", p)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("v4l2 probe ran %d times, want 1", calls)
+	}
+
+	if err := InvalidateCapabilityCache(cachePath); err != nil {
+		t.Fatalf("InvalidateCapabilityCache error: %v", err)
+	}
+	cachedProbe(cachePath, "/dev/video0", probe)
+	if calls != 2 {
+		t.Errorf("probe after invalidate ran %d times total, want 2", calls)
+	}
+}
+
+func TestCachedProbe_EmptyResultNotCached(t *testing.T) {
+	root := t.TempDir()
+	fakeUSBCamera(t, root, "video0", "046d", "0825", "")
+	withSysfsRoot(t, root)
+	cachePath := filepath.Join(t.TempDir(), "caps.json")
+
+	calls := 0
+	probe := func(string) (*capabilityProbe, error) {
+		calls++
+		return &capabilityProbe{}, nil
+	}
+	cachedProbe(cachePath, "/dev/video0", probe)
+	cachedProbe(cachePath, "/dev/video0", probe)
+	if calls != 2 {
+		t.Errorf("empty probe should be retried, ran %d times", calls)
+	}
+}
+
+func TestCachedProbe_Disabled(t *testing.T) {
+	calls := 0
+	probe := func(string) (*capabilityProbe, error) {
+		calls++
+		return parseFormatList(sampleFormats), nil
+	}
+	cachedProbe("", "/dev/video0", probe)
+	cachedProbe("", "/dev/video0", probe)
+	if calls != 2 {
+		t.Errorf("with caching disabled probe ran %d times, want 2", calls)
+	}
+}
+
+func TestIsDeviceDisabled(t *testing.T) {
+	root := t.TempDir()
+	fakeUSBCamera(t, root, "video2", "046d", "0825", "ABC123")
+	withSysfsRoot(t, root)
+
+	tests := []struct {
+		disabled []string
+		want     bool
+	}{
+		{nil, false},
+		{[]string{"/dev/video2"}, true},
+		{[]string{"video2"}, true},
+		{[]string{"046d:0825:ABC123"}, true},
+		{[]string{"046d:0825:OTHER", "/dev/video0"}, false},
+	}
+	for _, tc := range tests {
+		if got := IsDeviceDisabled(tc.disabled, "/dev/video2"); got != tc.want {
+			t.Errorf("IsDeviceDisabled(%q) = %v, want %v", tc.disabled, got, tc.want)
+		}
+	}
+
+	withSerial := Camera{DevicePath: "/dev/video2", USB: USBDescriptor{VendorID: "046d", ProductID: "0825", Serial: "ABC123"}}
+	if got := DisableKey(withSerial); got != "046d:0825:ABC123" {
+		t.Errorf("DisableKey with serial = %q", got)
+	}
+	withSerial.USB.Serial = ""
+	if got := DisableKey(withSerial); got != "/dev/video2" {
+		t.Errorf("DisableKey without serial = %q, want device path", got)
+	}
+}
@@ -2,12 +2,17 @@ package camera
 
 import (
 	"bytes"
+	"camera-dashboard-go/internal/clock"
+	"camera-dashboard-go/internal/helpers"
+	"errors"
 	"fmt"
 	"image"
 	"image/jpeg"
 	"io"
 	"log"
 	"os/exec"
+	"path/filepath"
+	"runtime"
 	"strconv"
 	"sync"
 	"sync/atomic"
@@ -21,30 +26,69 @@ type CaptureWorker struct {
 	running  atomic.Bool
 	stopCh   chan struct{}
 	wg       sync.WaitGroup // Tracks capture goroutine for clean shutdown
+	clock    clock.Clock    // Frame limiting and stream health (Settings.Clock)
 
 	// Frame output
 	frameBuffer *FrameBuffer // Buffer mode for decoupled capture/render
+	clip        *ClipBuffer  // Recent JPEGs for clips and replay (nil unless [clips] or [replay] is on)
 
 	// FFmpeg capture
 	ffmpegCmd *exec.Cmd
+	stream    io.Closer // Settings.Backend stream, instead of ffmpegCmd (see backend.go)
 	ffmpegMu  sync.Mutex
 
-	// Capture settings - use camera's max capabilities, never restart
-	targetFPS  atomic.Int32 // Effective FPS (controls frame skipping)
-	captureFPS int          // FFmpeg capture rate (from camera capabilities)
-	captureW   int          // Capture width (from camera capabilities)
-	captureH   int          // Capture height (from camera capabilities)
+	// Capture settings - use camera's max capabilities; only an adaptive
+	// resolution tier change (SetResolution) or a bandwidth limit
+	// (SetBandwidthLimit) restarts FFmpeg
+	targetFPS    atomic.Int32 // Effective FPS (controls frame skipping)
+	requestedFPS atomic.Int32 // Last SetFPS; targetFPS is this capped at captureFPS
+	captureFPS   int          // FFmpeg capture rate (capabilities, or a bandwidth limit)
+	captureW     int          // Capture width (capabilities, or a lower tier)
+	captureH     int          // Capture height (capabilities, or a lower tier)
+	baseW        int          // Capability resolution, restored at tier 0
+	baseH        int
+	baseFPS      int // Capability frame rate, restored without a bandwidth limit
+	tierW, tierH int // Adaptive resolution cap (0 = none); guarded by restartMu
+	limitFPS     int // Bandwidth limits (0 = none); guarded by restartMu
+	limitW       int
+	limitH       int
+	restartMu    sync.Mutex // Serializes Restart, SetResolution and SetBandwidthLimit
 
 	// Frame skipping - skip decoding to reduce CPU when target FPS < capture FPS
 	frameSkipCounter atomic.Uint64
+	hidden           atomic.Bool // Off screen: decode at settings.HiddenFPS (see SetHidden)
 
 	// Stats
-	lastFrameTime atomic.Int64
+	lastFrameTime atomic.Int64 // Monotonic nanos since processStart (see clock.go)
+	heartbeat     atomic.Int64 // Updated every loop iteration, even without frames
 	frameCount    atomic.Uint64
 	errorCount    atomic.Uint32
 	skippedFrames atomic.Uint64
+	bytesRead     atomic.Uint64 // MJPEG bytes received from the camera, never reset
+
+	// First-frame latency: Start -> first decoded camera frame, per session
+	sessionStart      atomic.Int64 // monoNow() at Start
+	firstFrameLatency atomic.Int64 // Nanoseconds; 0 = no real frame yet this session
+
+	// Stream health and format fallback (see streamhealth.go)
+	health       streamHealth
+	format       atomic.Pointer[string] // Active input format; nil = not streaming yet
+	fallback     atomic.Bool            // Capturing YUYV because MJPEG was corrupt
+	lastFallback time.Time              // Last fallback check; capture goroutine only
+
+	signalLost atomic.Bool // In recovery mode since the last real frame (see SignalLost)
+
+	// Reconnect backoff (see backoff.go); all but the cooldown are capture goroutine only
+	reconnectCooldown    atomic.Int64 // Longest wait between attempts, nanoseconds
+	reconnectFailures    int          // Consecutive failed attempts
+	reconnectSpentLogged bool
+	goodFormat           string // Format of the last stream that delivered a frame
 }
 
+// errFrameTimeout is returned when a complete frame doesn't arrive in
+// time (vibration, USB hiccups).
+var errFrameTimeout = errors.New("frame read timeout")
+
 // NewCaptureWorkerWithBuffer creates a capture worker using FrameBuffer
 func NewCaptureWorkerWithBuffer(camera Camera, buffer *FrameBuffer, s Settings) *CaptureWorker {
 	capW := camera.Capabilities.MaxWidth
@@ -67,11 +111,21 @@ func NewCaptureWorkerWithBuffer(camera Camera, buffer *FrameBuffer, s Settings)
 		settings:    s,
 		frameBuffer: buffer,
 		stopCh:      make(chan struct{}),
+		clock:       clock.Or(s.Clock),
+		health:      streamHealth{window: s.HealthWindow},
 		captureW:    capW,
 		captureH:    capH,
+		baseW:       capW,
+		baseH:       capH,
+		baseFPS:     capFPS,
 		captureFPS:  capFPS,
 	}
 	cw.targetFPS.Store(int32(capFPS))
+	cw.requestedFPS.Store(int32(capFPS))
+	cw.reconnectCooldown.Store(int64(s.ReconnectCooldown))
+	if s.ClipWindow > 0 {
+		cw.clip = NewClipBuffer(s.ClipWindow)
+	}
 	log.Printf("[Capture] %s: Vehicle mode - %dx%d @ %d FPS (buffer, fixed)", camera.DeviceID, capW, capH, capFPS)
 	return cw
 }
@@ -83,9 +137,16 @@ func (cw *CaptureWorker) SetFPS(fps int) {
 	if fps < 5 {
 		fps = 5
 	}
+	cw.requestedFPS.Store(int32(fps))
+	cw.updateTargetFPS()
+}
+
+// updateTargetFPS caps the requested FPS at the capture rate.
+func (cw *CaptureWorker) updateTargetFPS() {
+	fps := int(cw.requestedFPS.Load())
 	// Limit to camera's max FPS
-	if fps > cw.captureFPS {
-		fps = cw.captureFPS
+	if max := cw.GetMaxFPS(); fps > max {
+		fps = max
 	}
 	oldFPS := cw.targetFPS.Swap(int32(fps))
 	if oldFPS != int32(fps) {
@@ -93,21 +154,112 @@ func (cw *CaptureWorker) SetFPS(fps int) {
 	}
 }
 
+// SetHidden marks the camera as off screen (another camera fullscreen).
+// A hidden worker still reads every frame from FFmpeg, so the stream
+// stays in sync and clips keep the full rate, but decodes only
+// Settings.HiddenFPS of them.
+func (cw *CaptureWorker) SetHidden(hidden bool) {
+	if cw.hidden.Swap(hidden) != hidden && cw.settings.HiddenFPS > 0 {
+		if hidden {
+			log.Printf("[Capture] %s: Off screen, decoding at %d FPS", cw.camera.DeviceID, cw.settings.HiddenFPS)
+		} else {
+			log.Printf("[Capture] %s: On screen, decoding at %d FPS", cw.camera.DeviceID, cw.targetFPS.Load())
+		}
+	}
+}
+
+// hiddenInterval returns the decode interval while hidden, or 0 when the
+// worker is visible or the throttle wouldn't lower targetFPS.
+func (cw *CaptureWorker) hiddenInterval(targetFPS int) time.Duration {
+	fps := cw.settings.HiddenFPS
+	if !cw.hidden.Load() || fps <= 0 || fps >= targetFPS {
+		return 0
+	}
+	return time.Second / time.Duration(fps)
+}
+
 // GetFPS returns current FPS setting
 func (cw *CaptureWorker) GetFPS() int {
 	return int(cw.targetFPS.Load())
 }
 
-// GetMaxFPS returns the camera's maximum FPS
+// GetMaxFPS returns the capture rate: the camera's maximum FPS, or its
+// bandwidth limit
 func (cw *CaptureWorker) GetMaxFPS() int {
+	cw.ffmpegMu.Lock()
+	defer cw.ffmpegMu.Unlock()
 	return cw.captureFPS
 }
 
 // GetResolution returns current capture resolution
 func (cw *CaptureWorker) GetResolution() (int, int) {
+	cw.ffmpegMu.Lock()
+	defer cw.ffmpegMu.Unlock()
 	return cw.captureW, cw.captureH
 }
 
+// SetResolution caps the capture resolution at width x height (adaptive
+// resolution tier). The camera's own resolution is used when it is not
+// larger than the cap. A running worker restarts FFmpeg when the size
+// changes.
+func (cw *CaptureWorker) SetResolution(width, height int) error {
+	cw.restartMu.Lock()
+	defer cw.restartMu.Unlock()
+	cw.tierW, cw.tierH = width, height
+	return cw.applyCaptureLimits()
+}
+
+// SetBandwidthLimit caps the capture rate at fps and the resolution at
+// width x height (0 = no limit) to save USB bandwidth; see bandwidth.go.
+// It combines with the adaptive resolution cap: the smaller size wins.
+// A running worker restarts FFmpeg when the capture mode changes.
+func (cw *CaptureWorker) SetBandwidthLimit(fps, width, height int) error {
+	cw.restartMu.Lock()
+	defer cw.restartMu.Unlock()
+	cw.limitFPS, cw.limitW, cw.limitH = fps, width, height
+	return cw.applyCaptureLimits()
+}
+
+// applyCaptureLimits switches to the capture mode the resolution and
+// bandwidth caps (and a format fallback) allow. The caller holds restartMu.
+func (cw *CaptureWorker) applyCaptureLimits() error {
+	w, h := cw.baseW, cw.baseH
+	caps := [][2]int{{cw.tierW, cw.tierH}, {cw.limitW, cw.limitH}}
+	if cw.fallback.Load() {
+		caps = append(caps, [2]int{cw.settings.FallbackWidth, cw.settings.FallbackHeight})
+	}
+	for _, c := range caps {
+		if c[0] > 0 && c[1] > 0 && c[0]*c[1] < w*h {
+			w, h = c[0], c[1]
+		}
+	}
+	fps := cw.baseFPS
+	if cw.limitFPS > 0 && cw.limitFPS < fps {
+		fps = cw.limitFPS
+	}
+
+	curW, curH := cw.GetResolution()
+	curFPS := cw.GetMaxFPS()
+	if curW == w && curH == h && curFPS == fps {
+		return nil
+	}
+	if !cw.running.Load() {
+		cw.setCaptureMode(w, h, fps)
+		return nil
+	}
+	log.Printf("[Capture] %s: Capture %dx%d @ %d FPS -> %dx%d @ %d FPS (restart)",
+		cw.camera.DeviceID, curW, curH, curFPS, w, h, fps)
+	return cw.restart(func() { cw.setCaptureMode(w, h, fps) })
+}
+
+// setCaptureMode sets the FFmpeg capture size and rate (worker stopped).
+func (cw *CaptureWorker) setCaptureMode(w, h, fps int) {
+	cw.ffmpegMu.Lock()
+	cw.captureW, cw.captureH, cw.captureFPS = w, h, fps
+	cw.ffmpegMu.Unlock()
+	cw.updateTargetFPS()
+}
+
 // Start begins capturing frames from camera
 func (cw *CaptureWorker) Start() error {
 	if cw.running.Load() {
@@ -115,6 +267,9 @@ func (cw *CaptureWorker) Start() error {
 	}
 
 	cw.running.Store(true)
+	cw.heartbeat.Store(monoNow())
+	cw.sessionStart.Store(monoNow())
+	cw.firstFrameLatency.Store(0)
 	cw.wg.Add(1)
 	go func() {
 		defer cw.wg.Done()
@@ -145,6 +300,9 @@ func (cw *CaptureWorker) Stop() {
 		cw.ffmpegCmd.Process.Kill()
 		cw.ffmpegCmd.Wait() // Reap zombie process
 	}
+	if cw.stream != nil {
+		cw.stream.Close()
+	}
 	cw.ffmpegMu.Unlock()
 
 	// Wait for capture goroutine to fully exit (with timeout)
@@ -164,10 +322,22 @@ func (cw *CaptureWorker) Stop() {
 // Restart stops the worker and starts it again with a fresh stopCh
 // Used for hot-plug recovery without recreating the entire manager
 func (cw *CaptureWorker) Restart() error {
+	cw.restartMu.Lock()
+	defer cw.restartMu.Unlock()
 	log.Printf("[Capture] %s: Restarting worker...", cw.camera.DeviceID)
+	return cw.restart(nil)
+}
+
+// restart stops the worker, runs apply (if any) while it is stopped and
+// starts it again. The caller holds restartMu.
+func (cw *CaptureWorker) restart(apply func()) error {
+	totals.restarts.Add(1)
 
 	// Stop waits for goroutine to fully exit
 	cw.Stop()
+	if apply != nil {
+		apply()
+	}
 
 	// Reset stopCh (old one is closed)
 	cw.stopCh = make(chan struct{})
@@ -188,7 +358,7 @@ func (cw *CaptureWorker) GetStats() (frameCount uint64, fps float64, errors uint
 
 	lastTime := cw.lastFrameTime.Load()
 	if lastTime > 0 {
-		elapsed := time.Since(time.Unix(0, lastTime))
+		elapsed := time.Since(monoToTime(lastTime))
 		if elapsed < 2*time.Second && frameCount > 0 {
 			// Estimate FPS based on frame count and time
 			fps = float64(cw.targetFPS.Load())
@@ -197,10 +367,127 @@ func (cw *CaptureWorker) GetStats() (frameCount uint64, fps float64, errors uint
 	return
 }
 
+// FirstFrameLatency returns how long this session took from Start to the
+// first decoded camera frame (test patterns don't count). ok is false
+// until that frame arrives.
+func (cw *CaptureWorker) FirstFrameLatency() (latency time.Duration, ok bool) {
+	n := cw.firstFrameLatency.Load()
+	return time.Duration(n), n > 0
+}
+
+// SignalLost reports whether the worker has no real camera picture: it
+// is retrying in recovery mode (test pattern, or nothing with
+// Settings.NoTestPattern) and no real frame has arrived since.
+func (cw *CaptureWorker) SignalLost() bool {
+	return cw.signalLost.Load()
+}
+
+// recordFirstFrame stores the first-frame latency and warns if it exceeds
+// the configured threshold - slow first frames are an early sign of a
+// failing USB hub or cable.
+func (cw *CaptureWorker) recordFirstFrame() {
+	latency := monoNow() - cw.sessionStart.Load()
+	if latency <= 0 {
+		latency = 1
+	}
+	cw.firstFrameLatency.Store(latency)
+
+	d := time.Duration(latency)
+	if warn := cw.settings.FirstFrameWarn; warn > 0 && d > warn {
+		log.Printf("[Capture] Camera %s: WARNING: first frame took %.2fs (threshold %.1fs) - check USB hub/cable",
+			cw.camera.DeviceID, d.Seconds(), warn.Seconds())
+	} else {
+		log.Printf("[Capture] Camera %s: first frame after %.2fs", cw.camera.DeviceID, d.Seconds())
+	}
+}
+
+// reapplyControls sets the configured image controls again once the
+// stream is running, for cameras that reset them on every stream start.
+// v4l2-ctl runs in the background so capture isn't held up.
+func (cw *CaptureWorker) reapplyControls() {
+	if !shouldReapplyControls(cw.settings, cw.camera) {
+		return
+	}
+	log.Printf("[Controls] Camera %s: reapplying [controls] on connect", cw.camera.DeviceID)
+	go NewControls(cw.camera.DevicePath).Apply(cw.settings.Controls)
+}
+
+// BytesRead returns the MJPEG bytes received from the camera so far.
+func (cw *CaptureWorker) BytesRead() uint64 {
+	return cw.bytesRead.Load()
+}
+
+// StreamHealth returns the stream's score over the health window and the
+// active input format.
+func (cw *CaptureWorker) StreamHealth() StreamHealth {
+	s := cw.health.counts(cw.clock.Now())
+	if f := cw.format.Load(); f != nil {
+		s.Format = *f
+	}
+	s.Fallback = cw.fallback.Load()
+	return s
+}
+
+// checkFallback switches a corrupt MJPEG stream to YUYV at the fallback
+// resolution (see streamhealth.go). It returns true when the capture
+// process must be restarted; the capture loop then rebuilds the FFmpeg
+// arguments.
+func (cw *CaptureWorker) checkFallback(now time.Time) bool {
+	if cw.settings.FallbackCorruptRate <= 0 || now.Sub(cw.lastFallback) < time.Second {
+		return false
+	}
+	cw.lastFallback = now
+	s := cw.StreamHealth()
+	if !s.shouldFallback(cw.settings.FallbackCorruptRate) {
+		return false
+	}
+	w, h := cw.GetResolution()
+	if fw, fh := cw.settings.FallbackWidth, cw.settings.FallbackHeight; fw > 0 && fh > 0 && fw*fh < w*h {
+		w, h = fw, fh
+	}
+	log.Printf("[Capture] Camera %s: WARNING: corrupt MJPEG (%.0f%% of %d frames: %d decode errors, %d timeouts, %d resyncs) - falling back to YUYV at %dx%d",
+		cw.camera.DeviceID, s.CorruptRate*100, s.attempts(), s.DecodeErrors, s.Timeouts, s.Resyncs, w, h)
+	cw.fallback.Store(true)
+	cw.setCaptureMode(w, h, cw.GetMaxFPS())
+	cw.health.reset()
+	return true
+}
+
+// ClipFrames returns the buffered JPEG frames of the last [clips] window,
+// oldest first, or nil when clips are off.
+func (cw *CaptureWorker) ClipFrames() []ClipFrame {
+	if cw.clip == nil {
+		return nil
+	}
+	return cw.clip.Frames()
+}
+
+// LastHeartbeat returns when the capture goroutine last went round one of
+// its loops, or the zero Time if the worker isn't running. Unlike the
+// last frame time this keeps advancing through read timeouts and test
+// pattern fallback, so a stale value means the goroutine itself is stuck.
+func (cw *CaptureWorker) LastHeartbeat() time.Time {
+	if !cw.running.Load() {
+		return time.Time{}
+	}
+	return monoToTime(cw.heartbeat.Load())
+}
+
 // captureLoop runs the main capture loop using FFmpeg
 // Implements automatic recovery: if camera disconnects or FFmpeg fails,
 // falls back to test patterns which periodically try to reconnect
 func (cw *CaptureWorker) captureLoop() {
+	if cpus := cw.settings.CaptureCPUs; len(cpus) > 0 {
+		// Deliberately never unlocked: when a locked goroutine exits, Go
+		// terminates its thread, so the narrowed affinity can't leak to
+		// other goroutines via the thread pool.
+		runtime.LockOSThread()
+		if err := helpers.PinCurrentThread(cpus); err != nil {
+			log.Printf("[Capture] Camera %s: WARNING: failed to pin to CPUs %v: %v",
+				cw.camera.DeviceID, cpus, err)
+		}
+	}
+
 	defer func() {
 		cw.ffmpegMu.Lock()
 		if cw.ffmpegCmd != nil && cw.ffmpegCmd.Process != nil {
@@ -211,6 +498,8 @@ func (cw *CaptureWorker) captureLoop() {
 
 	// Main capture loop with recovery
 	for cw.running.Load() {
+		cw.heartbeat.Store(monoNow())
+
 		// Try real camera capture
 		realCameraWorking := cw.tryRealCameraCapture()
 
@@ -234,17 +523,31 @@ func (cw *CaptureWorker) captureLoop() {
 	}
 }
 
-// tryRealCameraCapture attempts to capture from real camera using FFmpeg
-func (cw *CaptureWorker) tryRealCameraCapture() bool {
+// tryRealCameraCapture attempts to capture from real camera using FFmpeg.
+// A failure counts against the reconnect budget (see backoff.go).
+func (cw *CaptureWorker) tryRealCameraCapture() (ok bool) {
+	defer func() {
+		if !ok && cw.running.Load() {
+			cw.reconnectFailed()
+		}
+	}()
+	if cw.settings.Backend != nil {
+		return cw.tryBackendCapture()
+	}
 	videoSize := fmt.Sprintf("%dx%d", cw.captureW, cw.captureH)
 	fps := cw.captureFPS
 	format := cw.settings.Format
+	if cw.fallback.Load() {
+		format = "yuyv"
+	}
 
 	log.Printf("[Capture] Camera %s: Vehicle mode - %s @ %d FPS (%s, fixed)",
 		cw.camera.DeviceID, videoSize, fps, format)
 
-	// Build format list based on configured format
-	// The configured format is tried first, then fallbacks
+	// Build format list from the platform's input variants (capture_v4l2.go,
+	// capture_avfoundation.go, capture_dshow.go) or the stream variants for
+	// network cameras (network.go). The configured format is tried first,
+	// then fallbacks
 	var formats [][]string
 
 	// Common FFmpeg args for all formats
@@ -262,38 +565,41 @@ func (cw *CaptureWorker) tryRealCameraCapture() bool {
 		return args
 	}
 
-	fpsStr := fmt.Sprintf("%d", fps)
-
-	// Primary format from config
-	if format == "mjpeg" {
-		formats = append(formats, buildArgs(
-			"-f", "v4l2", "-input_format", "mjpeg", "-video_size", videoSize,
-			"-framerate", fpsStr, "-i", cw.camera.DevicePath))
-		// YUYV fallback
-		formats = append(formats, buildArgs(
-			"-f", "v4l2", "-input_format", "yuyv422", "-video_size", videoSize,
-			"-framerate", fpsStr, "-i", cw.camera.DevicePath))
-	} else if format == "yuyv" {
-		// YUYV first if configured
-		formats = append(formats, buildArgs(
-			"-f", "v4l2", "-input_format", "yuyv422", "-video_size", videoSize,
-			"-framerate", fpsStr, "-i", cw.camera.DevicePath))
-		// MJPEG fallback
-		formats = append(formats, buildArgs(
-			"-f", "v4l2", "-input_format", "mjpeg", "-video_size", videoSize,
-			"-framerate", fpsStr, "-i", cw.camera.DevicePath))
-	}
-
-	// Auto format detection as last resort
-	formats = append(formats, buildArgs(
-		"-f", "v4l2", "-video_size", videoSize,
-		"-framerate", fpsStr, "-i", cw.camera.DevicePath))
+	program := "ffmpeg"
+	if source, ok := simSource(cw.camera.DevicePath); ok {
+		if simPlaysInProcess(source) {
+			return cw.trySimulatedCapture(source)
+		}
+		formats = [][]string{buildArgs(simVideoInput(source, videoSize, fmt.Sprintf("%d", fps))...)}
+	} else if index, ok := csiIndex(cw.camera.DevicePath); ok {
+		// Pi camera module: rpicam-vid encodes MJPEG itself, no FFmpeg
+		program = rpicamVidPath()
+		if program == "" {
+			return false
+		}
+		formats = csiCaptureArgs(index, cw.captureW, cw.captureH, fps)
+	} else {
+		inputs := inputVariants(cw.camera.DevicePath, videoSize, fmt.Sprintf("%d", fps), format)
+		if IsNetworkSource(cw.camera.DevicePath) {
+			inputs = networkInputVariants(cw.camera.DevicePath, videoSize, fmt.Sprintf("%d", fps))
+		}
+		for _, input := range inputs {
+			formats = append(formats, buildArgs(input...))
+		}
+	}
+
+	if cw.budgetSpent() {
+		formats = preferredVariant(cw.camera.DevicePath, formats, cw.goodFormat)
+	}
 
 	for _, args := range formats {
 		if !cw.running.Load() {
 			return false // Shutting down, don't try more formats
 		}
-		if cw.tryFFmpegCapture(args) {
+		if f := cw.settings.Faults; f != nil && f.deviceRemoved(cw.camera.DeviceID) {
+			return false // Simulated unplug - behave as if the device can't be opened
+		}
+		if cw.tryPipeCapture(program, args) {
 			return true
 		}
 	}
@@ -301,13 +607,15 @@ func (cw *CaptureWorker) tryRealCameraCapture() bool {
 	return false
 }
 
-// tryFFmpegCapture tries to capture with specific FFmpeg arguments
+// tryPipeCapture tries to capture from program (FFmpeg, or rpicam-vid for
+// CSI cameras) writing an MJPEG stream to stdout with the given arguments
 // NEVER restarts - FFmpeg runs at camera's max settings, frame skipping handles FPS
-func (cw *CaptureWorker) tryFFmpegCapture(args []string) bool {
-	log.Printf("[Capture] Camera %s: Trying FFmpeg with args: %v", cw.camera.DeviceID, args)
+func (cw *CaptureWorker) tryPipeCapture(program string, args []string) bool {
+	log.Printf("[Capture] Camera %s: Trying %s with args: %v",
+		cw.camera.DeviceID, filepath.Base(program), redactArgs(args, cw.camera.DevicePath))
 
 	cw.ffmpegMu.Lock()
-	cw.ffmpegCmd = exec.Command("ffmpeg", args...)
+	cw.ffmpegCmd = captureCommand(program, args, cw.settings)
 	cw.ffmpegCmd.Stderr = nil // Suppress FFmpeg stderr output
 
 	stdout, err := cw.ffmpegCmd.StdoutPipe()
@@ -319,7 +627,7 @@ func (cw *CaptureWorker) tryFFmpegCapture(args []string) bool {
 
 	if err := cw.ffmpegCmd.Start(); err != nil {
 		cw.ffmpegMu.Unlock()
-		log.Printf("[Capture] Camera %s: Failed to start FFmpeg: %v", cw.camera.DeviceID, err)
+		log.Printf("[Capture] Camera %s: Failed to start %s: %v", cw.camera.DeviceID, filepath.Base(program), err)
 		return false
 	}
 	cw.ffmpegMu.Unlock()
@@ -334,14 +642,32 @@ func (cw *CaptureWorker) tryFFmpegCapture(args []string) bool {
 		cw.ffmpegMu.Unlock()
 	}()
 
-	log.Printf("[Capture] Camera %s: FFmpeg started - %dx%d @ %d FPS (PID: %d)",
-		cw.camera.DeviceID, cw.captureW, cw.captureH, cw.captureFPS, cw.ffmpegCmd.Process.Pid)
+	log.Printf("[Capture] Camera %s: %s started - %dx%d @ %d FPS (PID: %d)",
+		cw.camera.DeviceID, filepath.Base(program), cw.captureW, cw.captureH, cw.captureFPS, cw.ffmpegCmd.Process.Pid)
+	format := streamFormat(cw.camera.DevicePath, args)
+	cw.format.Store(&format)
+	return cw.readStream(stdout, format)
+}
 
+// readStream reads, rate-limits, decodes and publishes the frames of an
+// MJPEG stream (the capture process's stdout, or a simulated source)
+// until it ends or the worker stops. It returns false when the stream
+// ended on its own.
+func (cw *CaptureWorker) readStream(stdout io.Reader, format string) bool {
 	// Pre-allocate read buffer for efficiency
-	readBuffer := make([]byte, 8192)    // Larger buffer for fewer syscalls
-	frameData := make([]byte, 0, 65536) // Pre-allocate typical JPEG size
-
-	lastProcessedTime := time.Now()
+	readBuffer := make([]byte, 8192) // Larger buffer for fewer syscalls
+	parser := newMJPEGParser()
+
+	lastProcessedTime := cw.clock.Now()
+	var lastDecodeTime time.Time
+	streaming := false // First frame of this process seen
+
+	// Frame tracing (see trace.go): timing is nil when it's off
+	var timing *frameTiming
+	var decoded uint64
+	if cw.settings.TraceEvery > 0 {
+		timing = &frameTiming{}
+	}
 
 	// Read frames from FFmpeg output - FFmpeg controls the rate
 	// NO RESTART LOGIC - frame skipping handles FPS adaptation
@@ -350,6 +676,8 @@ func (cw *CaptureWorker) tryFFmpegCapture(args []string) bool {
 		case <-cw.stopCh:
 			return true
 		default:
+			cw.heartbeat.Store(monoNow())
+
 			// Re-read targetFPS each iteration so SetFPS() changes take effect
 			targetFPS := int(cw.targetFPS.Load())
 			if targetFPS <= 0 {
@@ -358,7 +686,7 @@ func (cw *CaptureWorker) tryFFmpegCapture(args []string) bool {
 			minFrameInterval := time.Second / time.Duration(targetFPS)
 
 			// Read raw JPEG bytes (must read to stay in sync with stream)
-			jpegData, err := cw.readMJPEGFrameRaw(stdout, readBuffer, &frameData)
+			jpegData, err := cw.readMJPEGFrameRaw(stdout, readBuffer, parser, timing)
 			if err != nil {
 				if err == io.EOF {
 					log.Printf("[Capture] Camera %s: FFmpeg stream ended", cw.camera.DeviceID)
@@ -366,32 +694,86 @@ func (cw *CaptureWorker) tryFFmpegCapture(args []string) bool {
 				}
 				// Timeout or other error - skip this frame, don't freeze
 				cw.errorCount.Add(1)
-				// Clear frameData to resync on next frame
-				frameData = frameData[:0]
+				if errors.Is(err, errFrameTimeout) {
+					cw.health.record(healthTimeout, cw.clock.Now())
+				}
+				// A partial frame stays with the parser for the next read
+				if cw.checkFallback(cw.clock.Now()) {
+					return true // Restart with the fallback format
+				}
 				continue
 			}
+			cw.bytesRead.Add(uint64(len(jpegData))) // Every frame crossed the bus, decoded or not
 
 			// Time-based frame limiting: only process if enough time has passed
 			// This handles cameras that ignore FPS request and send at max rate
-			now := time.Now()
+			now := cw.clock.Now()
 			elapsed := now.Sub(lastProcessedTime)
 			if elapsed < minFrameInterval {
 				// Skip this frame - haven't waited long enough
 				cw.skippedFrames.Add(1)
+				totals.dropped.Add(1)
 				continue
 			}
 			lastProcessedTime = now
 
+			// Off screen: keep the clip at the full rate, decode rarely
+			if interval := cw.hiddenInterval(targetFPS); interval > 0 && now.Sub(lastDecodeTime) < interval {
+				if cw.clip != nil {
+					cw.clip.Add(jpegData)
+				}
+				cw.skippedFrames.Add(1)
+				totals.dropped.Add(1)
+				continue
+			}
+			lastDecodeTime = now
+
+			if f := cw.settings.Faults; f != nil {
+				jpegData = f.maybeCorrupt(jpegData)
+			}
+
 			// Decode JPEG to image
+			var decodeStart time.Time
+			if timing != nil {
+				decodeStart = time.Now()
+			}
 			frame := cw.decodeJPEG(jpegData)
 			if frame == nil {
 				cw.errorCount.Add(1)
+				totals.dropped.Add(1)
+				totals.decodeErrors.Add(1)
+				cw.health.record(healthDecodeError, now)
+				if cw.checkFallback(now) {
+					return true // Restart with the fallback format
+				}
 				continue
 			}
+			cw.health.record(healthFrame, now)
+
+			var trace *FrameTrace
+			if timing != nil {
+				if decoded++; decoded%uint64(cw.settings.TraceEvery) == 0 {
+					trace = &FrameTrace{Camera: cw.camera.DeviceID, Read: timing.read, Parse: timing.parse, Decode: time.Since(decodeStart)}
+				}
+			}
+
+			if cw.clip != nil {
+				cw.clip.Add(jpegData)
+			}
 
 			// Update stats
 			cw.frameCount.Add(1)
-			cw.lastFrameTime.Store(time.Now().UnixNano())
+			totals.decoded.Add(1)
+			cw.lastFrameTime.Store(monoNow())
+			if cw.firstFrameLatency.Load() == 0 {
+				cw.recordFirstFrame()
+			}
+			if !streaming {
+				streaming = true
+				cw.signalLost.Store(false)
+				cw.reconnected(format)
+				cw.reapplyControls()
+			}
 
 			count := cw.frameCount.Load()
 			if count%150 == 1 { // Log every 150 frames (~10 sec at 15fps)
@@ -401,21 +783,97 @@ func (cw *CaptureWorker) tryFFmpegCapture(args []string) bool {
 					cw.camera.DeviceID, count, bounds.Dx(), bounds.Dy(), targetFPS, skipped)
 			}
 
+			if f := cw.settings.Faults; f != nil {
+				if d := f.frameDelay(); d > 0 {
+					time.Sleep(d)
+				}
+			}
+
 			// Send frame - prefer FrameBuffer if available
-			cw.sendFrame(frame)
+			cw.sendFrame(frame, trace)
 		}
 	}
 
 	return true
 }
 
-// readMJPEGFrameRaw reads raw JPEG bytes from stream without decoding
-// Returns the raw JPEG data and any error. Caller decides whether to decode.
-// Has built-in timeout to prevent blocking during camera issues (vibration, USB hiccups)
-func (cw *CaptureWorker) readMJPEGFrameRaw(reader io.Reader, buffer []byte, frameData *[]byte) ([]byte, error) {
-	// Reset frame data slice (keep capacity)
-	*frameData = (*frameData)[:0]
+// processCPUs is the CPU affinity the process started with, read before
+// any capture thread is pinned (nil if unknown).
+var processCPUs, _ = helpers.ThreadCPUs()
+
+// Wrapper binaries are resolved once; empty if not installed.
+var (
+	tasksetOnce sync.Once
+	tasksetPath string
+	niceOnce    sync.Once
+	nicePath    string
+)
+
+// lookPathOnce resolves name once, warning (once) if it's missing.
+func lookPathOnce(once *sync.Once, path *string, name, missing string) string {
+	once.Do(func() {
+		p, err := exec.LookPath(name)
+		if err != nil {
+			log.Printf("[Capture] WARNING: %s not found, %s", name, missing)
+			return
+		}
+		*path = p
+	})
+	return *path
+}
+
+// ffmpegCommand builds the FFmpeg command, wrapped in taskset -c when CPU
+// pinning is configured and nice -n when FFmpegNice is set. The wrappers
+// exec FFmpeg, so the PID (and Kill) still refer to FFmpeg itself, and
+// every FFmpeg thread inherits the affinity and priority from the start.
+// FFmpeg is started from the capture goroutine, whose thread is pinned to
+// CaptureCPUs; without FFmpegCPUs it would inherit that pinning, so it is
+// put back on the CPUs the process started with instead.
+func ffmpegCommand(args []string, s Settings) *exec.Cmd {
+	return captureCommand("ffmpeg", args, s)
+}
 
+// captureCommand builds a capture subprocess with the same taskset/nice
+// wrapping as FFmpeg (rpicam-vid for CSI cameras shares the CPU budget).
+func captureCommand(program string, args []string, s Settings) *exec.Cmd {
+	argv := []string{program}
+	if s.FFmpegNice != 0 {
+		if p := lookPathOnce(&niceOnce, &nicePath, "nice", "FFmpeg priority will not be changed"); p != "" {
+			argv = append([]string{p, "-n", strconv.Itoa(s.FFmpegNice)}, argv...)
+		}
+	}
+	cpus := s.FFmpegCPUs
+	if len(cpus) == 0 && len(s.CaptureCPUs) > 0 {
+		cpus = processCPUs
+	}
+	if len(cpus) > 0 {
+		if p := lookPathOnce(&tasksetOnce, &tasksetPath, "taskset", "FFmpeg will not be pinned (install util-linux)"); p != "" {
+			argv = append([]string{p, "-c", helpers.FormatCPUList(cpus)}, argv...)
+		}
+	}
+	return exec.Command(argv[0], append(argv[1:], args...)...)
+}
+
+// killFFmpeg kills the running FFmpeg process without stopping the
+// worker, so the capture loop sees the stream end and recovers. Used by
+// fault injection; tryPipeCapture reaps the process.
+func (cw *CaptureWorker) killFFmpeg() {
+	cw.ffmpegMu.Lock()
+	defer cw.ffmpegMu.Unlock()
+	if cw.ffmpegCmd != nil && cw.ffmpegCmd.Process != nil {
+		cw.ffmpegCmd.Process.Kill()
+	}
+	if cw.stream != nil {
+		cw.stream.Close()
+	}
+}
+
+// readMJPEGFrameRaw reads the next raw JPEG frame from stream without
+// decoding it; parser (see mjpeg.go) holds bytes between calls, so a
+// frame that straddles a timeout is completed by the next call.
+// Has built-in timeout to prevent blocking during camera issues (vibration, USB hiccups)
+// timing, if not nil, receives the frame's read and parse time.
+func (cw *CaptureWorker) readMJPEGFrameRaw(reader io.Reader, buffer []byte, parser *mjpegParser, timing *frameTiming) ([]byte, error) {
 	// Timeout for reading a complete frame (prevents freeze during vibration)
 	// Scale with FPS: at 30fps a frame is ~33ms, at 5fps ~200ms; add generous margin
 	fps := int(cw.targetFPS.Load())
@@ -427,85 +885,47 @@ func (cw *CaptureWorker) readMJPEGFrameRaw(reader io.Reader, buffer []byte, fram
 		frameTimeout = 150 * time.Millisecond
 	}
 	frameStart := time.Now()
+	if timing != nil {
+		*timing = frameTiming{}
+	}
 
-	// Find SOI marker (0xFFD8)
-	foundSOI := false
-	for !foundSOI {
-		// Check timeout
-		if time.Since(frameStart) > frameTimeout {
-			*frameData = (*frameData)[:0]
-			return nil, fmt.Errorf("timeout finding SOI marker")
+	for {
+		var stageStart time.Time
+		if timing != nil {
+			stageStart = time.Now()
 		}
-
-		n, err := reader.Read(buffer)
-		if err != nil {
-			return nil, err
+		frame := parser.next()
+		for n := parser.takeResyncs(); n > 0; n-- {
+			cw.health.record(healthResync, cw.clock.Now())
 		}
-
-		*frameData = append(*frameData, buffer[:n]...)
-
-		// Look for SOI marker
-		for i := 0; i < len(*frameData)-1; i++ {
-			if (*frameData)[i] == 0xFF && (*frameData)[i+1] == 0xD8 {
-				*frameData = (*frameData)[i:]
-				foundSOI = true
-				break
-			}
+		if timing != nil {
+			timing.parse += time.Since(stageStart)
 		}
-
-		// Prevent runaway buffer growth
-		if len(*frameData) > 100000 {
-			*frameData = (*frameData)[len(*frameData)-10000:]
+		if frame != nil {
+			return frame, nil
 		}
-	}
 
-	// Find EOI marker (0xFFD9)
-	// Track last-scanned position to avoid O(n^2) rescanning on each Read()
-	scanFrom := 1
-	for {
 		// Check timeout
 		if time.Since(frameStart) > frameTimeout {
-			*frameData = (*frameData)[:0]
-			return nil, fmt.Errorf("timeout finding EOI marker")
-		}
-
-		if len(*frameData) > 10 {
-			for i := scanFrom; i < len(*frameData); i++ {
-				if (*frameData)[i-1] == 0xFF && (*frameData)[i] == 0xD9 {
-					// Found complete frame - copy the JPEG data
-					jpegData := make([]byte, i+1)
-					copy(jpegData, (*frameData)[:i+1])
-
-					// Keep remaining data for next frame
-					if i+1 < len(*frameData) {
-						remaining := (*frameData)[i+1:]
-						*frameData = append((*frameData)[:0], remaining...)
-					} else {
-						*frameData = (*frameData)[:0]
-					}
-
-					return jpegData, nil
-				}
-			}
-			// Next time, start scanning from where we left off minus 1
-			// (minus 1 because the EOI marker spans two bytes)
-			scanFrom = len(*frameData) - 1
-			if scanFrom < 1 {
-				scanFrom = 1
-			}
+			return nil, fmt.Errorf("%w waiting for a complete frame", errFrameTimeout)
 		}
 
-		// Read more data
+		if timing != nil {
+			stageStart = time.Now()
+		}
 		n, err := reader.Read(buffer)
+		if timing != nil {
+			timing.read += time.Since(stageStart)
+		}
 		if err != nil {
 			return nil, err
 		}
-
-		*frameData = append(*frameData, buffer[:n]...)
-
-		if len(*frameData) > 200000 {
-			*frameData = (*frameData)[:0]
-			return nil, io.EOF
+		if timing != nil {
+			stageStart = time.Now()
+		}
+		parser.feed(buffer[:n])
+		if timing != nil {
+			timing.parse += time.Since(stageStart)
 		}
 	}
 }
@@ -521,25 +941,32 @@ func (cw *CaptureWorker) decodeJPEG(jpegData []byte) image.Image {
 }
 
 // runTestPatternLoop generates test patterns when real camera is unavailable
+// (or, with Settings.NoTestPattern, nothing: SignalLost reports the state)
 // Periodically attempts to reconnect to the real camera
 func (cw *CaptureWorker) runTestPatternLoop() {
-	log.Printf("[Capture] Camera %s: Using test pattern mode (real camera unavailable)", cw.camera.DeviceID)
+	cw.signalLost.Store(true)
+	if cw.settings.NoTestPattern {
+		log.Printf("[Capture] Camera %s: Signal lost (real camera unavailable, test pattern off)", cw.camera.DeviceID)
+	} else {
+		log.Printf("[Capture] Camera %s: Using test pattern mode (real camera unavailable)", cw.camera.DeviceID)
+	}
 
-	// Try to reconnect to real camera every 10 seconds
-	retryTicker := time.NewTicker(10 * time.Second)
-	defer retryTicker.Stop()
+	// Try to reconnect to real camera with backoff (see backoff.go)
+	retryTimer := time.NewTimer(cw.reconnectWait())
+	defer retryTimer.Stop()
 
 	retryCount := 0
 	lastRetryLog := time.Time{}
 
 	for cw.running.Load() {
+		cw.heartbeat.Store(monoNow())
 		frameInterval := time.Second / time.Duration(cw.GetFPS())
 
 		select {
 		case <-cw.stopCh:
 			return
 
-		case <-retryTicker.C:
+		case <-retryTimer.C:
 			// Attempt to reconnect to real camera
 			retryCount++
 
@@ -555,14 +982,19 @@ func (cw *CaptureWorker) runTestPatternLoop() {
 					cw.camera.DeviceID, retryCount)
 				return // Exit test pattern loop - real camera is working
 			}
+			retryTimer.Reset(cw.reconnectWait())
 
 		default:
+			if cw.settings.NoTestPattern {
+				time.Sleep(frameInterval) // Nothing to show; keep beating until the next retry
+				continue
+			}
 			frame := cw.generateTestFrame(int(cw.frameCount.Load()))
 			cw.frameCount.Add(1)
-			cw.lastFrameTime.Store(time.Now().UnixNano())
+			cw.lastFrameTime.Store(monoNow())
 
 			// Send frame
-			cw.sendFrame(frame)
+			cw.sendFrame(frame, nil)
 
 			time.Sleep(frameInterval)
 		}
@@ -570,8 +1002,13 @@ func (cw *CaptureWorker) runTestPatternLoop() {
 }
 
 // sendFrame sends frame to FrameBuffer
-func (cw *CaptureWorker) sendFrame(frame image.Image) {
-	if cw.frameBuffer != nil {
+func (cw *CaptureWorker) sendFrame(frame image.Image, trace *FrameTrace) {
+	if cw.frameBuffer == nil {
+		return
+	}
+	if trace != nil {
+		cw.frameBuffer.WriteTraced(frame, trace)
+	} else {
 		cw.frameBuffer.Write(frame)
 	}
 }
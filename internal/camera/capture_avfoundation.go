@@ -0,0 +1,32 @@
+//go:build darwin
+
+package camera
+
+import (
+	"log"
+	"os/exec"
+)
+
+// inputVariants returns the FFmpeg avfoundation input arguments to try.
+// devicePath is the avfoundation video device index. Most built-in
+// cameras reject FFmpeg's default 29.97 FPS, so the fallbacks ask for a
+// plain 30 FPS and then let the camera pick its own size.
+func inputVariants(devicePath, videoSize, fps, format string) [][]string {
+	return [][]string{
+		{"-f", "avfoundation", "-video_size", videoSize, "-framerate", fps, "-i", devicePath},
+		{"-f", "avfoundation", "-video_size", videoSize, "-framerate", "30", "-i", devicePath},
+		{"-f", "avfoundation", "-framerate", "30", "-i", devicePath},
+	}
+}
+
+// discoverDesktopCameras lists avfoundation video devices through FFmpeg.
+func discoverDesktopCameras(s Settings) ([]Camera, bool) {
+	// -list_devices always exits non-zero; the list is on stderr
+	output, _ := exec.Command("ffmpeg", "-hide_banner", "-f", "avfoundation",
+		"-list_devices", "true", "-i", "").CombinedOutput()
+	devices := parseAVFoundationDevices(string(output))
+	if len(devices) == 0 {
+		log.Println("[Discovery] No avfoundation cameras found (is ffmpeg installed?)")
+	}
+	return desktopCameras(devices, s), true
+}
@@ -0,0 +1,37 @@
+//go:build windows
+
+package camera
+
+import (
+	"log"
+	"os/exec"
+)
+
+// inputVariants returns the FFmpeg dshow input arguments to try.
+// devicePath is the dshow input specifier ("video=Integrated Camera").
+// -vcodec before -i asks the camera for MJPEG; the last variant lets
+// the driver pick format and size.
+func inputVariants(devicePath, videoSize, fps, format string) [][]string {
+	mjpeg := []string{"-f", "dshow", "-rtbufsize", "64M", "-vcodec", "mjpeg",
+		"-video_size", videoSize, "-framerate", fps, "-i", devicePath}
+	raw := []string{"-f", "dshow", "-rtbufsize", "64M",
+		"-video_size", videoSize, "-framerate", fps, "-i", devicePath}
+
+	variants := [][]string{mjpeg, raw}
+	if format == "yuyv" {
+		variants = [][]string{raw, mjpeg}
+	}
+	return append(variants, []string{"-f", "dshow", "-rtbufsize", "64M", "-i", devicePath})
+}
+
+// discoverDesktopCameras lists DirectShow video devices through FFmpeg.
+func discoverDesktopCameras(s Settings) ([]Camera, bool) {
+	// -list_devices always exits non-zero; the list is on stderr
+	output, _ := exec.Command("ffmpeg", "-hide_banner", "-list_devices", "true",
+		"-f", "dshow", "-i", "dummy").CombinedOutput()
+	devices := parseDShowDevices(string(output))
+	if len(devices) == 0 {
+		log.Println("[Discovery] No DirectShow cameras found (is ffmpeg on PATH?)")
+	}
+	return desktopCameras(devices, s), true
+}
@@ -0,0 +1,91 @@
+package camera
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCaptureWorker_FirstFrameLatency(t *testing.T) {
+	cw := NewCaptureWorkerWithBuffer(Camera{DeviceID: "video0"}, NewFrameBuffer(),
+		Settings{Width: 64, Height: 48, FPS: 10, FirstFrameWarn: time.Millisecond})
+
+	if _, ok := cw.FirstFrameLatency(); ok {
+		t.Fatal("latency should be unknown before the first frame")
+	}
+
+	cw.sessionStart.Store(monoNow())
+	time.Sleep(5 * time.Millisecond)
+	cw.recordFirstFrame()
+
+	d, ok := cw.FirstFrameLatency()
+	if !ok || d < 5*time.Millisecond || d > time.Second {
+		t.Errorf("FirstFrameLatency() = (%v, %v), want ~5ms", d, ok)
+	}
+}
+
+func TestCaptureWorker_SetResolutionCapsAtCameraSize(t *testing.T) {
+	cw := NewCaptureWorkerWithBuffer(Camera{DeviceID: "video0"}, NewFrameBuffer(),
+		Settings{Width: 640, Height: 480, FPS: 10})
+
+	steps := []struct{ capW, capH, wantW, wantH int }{
+		{320, 240, 320, 240},
+		{1280, 720, 640, 480}, // Never above the camera's own size
+		{320, 240, 320, 240},
+		{0, 0, 640, 480}, // No cap
+	}
+	for _, s := range steps {
+		if err := cw.SetResolution(s.capW, s.capH); err != nil {
+			t.Fatalf("SetResolution(%d, %d): %v", s.capW, s.capH, err)
+		}
+		if w, h := cw.GetResolution(); w != s.wantW || h != s.wantH {
+			t.Errorf("cap %dx%d: resolution %dx%d, want %dx%d", s.capW, s.capH, w, h, s.wantW, s.wantH)
+		}
+	}
+}
+
+func TestCaptureWorker_SetBandwidthLimit(t *testing.T) {
+	cw := NewCaptureWorkerWithBuffer(Camera{DeviceID: "video0"}, NewFrameBuffer(),
+		Settings{Width: 640, Height: 480, FPS: 30})
+	cw.SetFPS(20)
+
+	if err := cw.SetBandwidthLimit(10, 0, 0); err != nil {
+		t.Fatal(err)
+	}
+	if fps, target := cw.GetMaxFPS(), cw.GetFPS(); fps != 10 || target != 10 {
+		t.Errorf("limit 10 FPS: capture %d, target %d; want 10, 10", fps, target)
+	}
+
+	// The smaller of the tier and bandwidth caps wins
+	cw.SetResolution(480, 360)
+	cw.SetBandwidthLimit(10, 320, 240)
+	if w, h := cw.GetResolution(); w != 320 || h != 240 {
+		t.Errorf("tier 480x360, limit 320x240: resolution %dx%d, want 320x240", w, h)
+	}
+	cw.SetBandwidthLimit(0, 0, 0)
+	if w, h := cw.GetResolution(); w != 480 || h != 360 {
+		t.Errorf("limit lifted: resolution %dx%d, want the tier 480x360", w, h)
+	}
+	if fps, target := cw.GetMaxFPS(), cw.GetFPS(); fps != 30 || target != 20 {
+		t.Errorf("limit lifted: capture %d, target %d; want 30, the requested 20", fps, target)
+	}
+}
+
+func TestCaptureWorker_HiddenInterval(t *testing.T) {
+	cw := NewCaptureWorkerWithBuffer(Camera{DeviceID: "video0"}, NewFrameBuffer(),
+		Settings{Width: 640, Height: 480, FPS: 15, HiddenFPS: 2})
+
+	if d := cw.hiddenInterval(15); d != 0 {
+		t.Errorf("visible: interval %v, want 0", d)
+	}
+	cw.SetHidden(true)
+	if d := cw.hiddenInterval(15); d != 500*time.Millisecond {
+		t.Errorf("hidden: interval %v, want 500ms", d)
+	}
+	if d := cw.hiddenInterval(2); d != 0 {
+		t.Errorf("hidden at target 2 FPS: interval %v, want 0 (already that slow)", d)
+	}
+	cw.settings.HiddenFPS = 0
+	if d := cw.hiddenInterval(15); d != 0 {
+		t.Errorf("throttle off: interval %v, want 0", d)
+	}
+}
@@ -0,0 +1,29 @@
+//go:build !darwin && !windows
+
+package camera
+
+// inputVariants returns the FFmpeg v4l2 input arguments to try, in order:
+// the configured format, the other format, then FFmpeg's auto-detection.
+func inputVariants(devicePath, videoSize, fps, format string) [][]string {
+	mjpeg := []string{"-f", "v4l2", "-input_format", "mjpeg", "-video_size", videoSize,
+		"-framerate", fps, "-i", devicePath}
+	yuyv := []string{"-f", "v4l2", "-input_format", "yuyv422", "-video_size", videoSize,
+		"-framerate", fps, "-i", devicePath}
+
+	var variants [][]string
+	if format == "mjpeg" {
+		variants = append(variants, mjpeg, yuyv)
+	} else if format == "yuyv" {
+		variants = append(variants, yuyv, mjpeg)
+	}
+
+	// Auto format detection as last resort
+	return append(variants, []string{"-f", "v4l2", "-video_size", videoSize,
+		"-framerate", fps, "-i", devicePath})
+}
+
+// discoverDesktopCameras is only used on macOS and Windows; Linux
+// discovers /dev/video* devices with v4l2-ctl.
+func discoverDesktopCameras(s Settings) ([]Camera, bool) {
+	return nil, false
+}
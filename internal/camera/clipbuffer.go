@@ -0,0 +1,83 @@
+package camera
+
+import (
+	"sync"
+	"time"
+)
+
+// =============================================================================
+// Clip buffer
+// =============================================================================
+// With [clips] or [replay] seconds > 0 every capture worker keeps the
+// JPEG bytes of the frames it displayed over the last N seconds, so "Save
+// clip" can write what just happened and the fullscreen Replay bar can
+// scrub back through it. Frames are kept as FFmpeg produced them (no
+// re-encoding); at 640x480 that is roughly 1 MB per second per camera.
+// =============================================================================
+
+// ClipFrame is one buffered JPEG frame.
+type ClipFrame struct {
+	JPEG []byte
+	At   time.Time // Capture time
+}
+
+type clipEntry struct {
+	jpeg []byte
+	at   int64 // Monotonic nanos since processStart
+}
+
+// ClipBuffer holds the frames of the last window, oldest first.
+type ClipBuffer struct {
+	window int64 // Nanoseconds
+
+	mu     sync.Mutex
+	frames []clipEntry
+}
+
+// NewClipBuffer creates a buffer keeping window worth of frames.
+func NewClipBuffer(window time.Duration) *ClipBuffer {
+	return &ClipBuffer{window: int64(window)}
+}
+
+// Add stores a copy of jpeg (the capture loop reuses its read buffer)
+// and drops frames that fell out of the window.
+func (c *ClipBuffer) Add(jpeg []byte) {
+	now := monoNow()
+	data := make([]byte, len(jpeg))
+	copy(data, jpeg)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.frames = append(c.frames, clipEntry{jpeg: data, at: now})
+	drop := 0
+	for drop < len(c.frames) && now-c.frames[drop].at > c.window {
+		drop++
+	}
+	if drop > 0 {
+		// Shift instead of reslicing so the backing array doesn't grow forever
+		n := copy(c.frames, c.frames[drop:])
+		for i := n; i < len(c.frames); i++ {
+			c.frames[i] = clipEntry{}
+		}
+		c.frames = c.frames[:n]
+	}
+}
+
+// Frames returns the buffered frames, oldest first. The JPEG bytes are
+// shared but never modified after Add.
+func (c *ClipBuffer) Frames() []ClipFrame {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	out := make([]ClipFrame, len(c.frames))
+	for i, f := range c.frames {
+		out[i] = ClipFrame{JPEG: f.jpeg, At: monoToTime(f.at)}
+	}
+	return out
+}
+
+// Reset drops all buffered frames.
+func (c *ClipBuffer) Reset() {
+	c.mu.Lock()
+	c.frames = nil
+	c.mu.Unlock()
+}
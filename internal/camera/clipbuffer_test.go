@@ -0,0 +1,32 @@
+package camera
+
+import (
+	"testing"
+	"time"
+)
+
+func TestClipBuffer_KeepsWindowAndCopies(t *testing.T) {
+	c := NewClipBuffer(30 * time.Millisecond)
+	data := []byte{0xFF, 0xD8, 1, 0xFF, 0xD9}
+	c.Add(data)
+	data[2] = 99 // The capture loop reuses its buffer
+	if frames := c.Frames(); len(frames) != 1 || frames[0].JPEG[2] != 1 {
+		t.Fatalf("Frames = %v, want one copied frame", frames)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	c.Add([]byte{2})
+	c.Add([]byte{3})
+	frames := c.Frames()
+	if len(frames) != 2 || frames[0].JPEG[0] != 2 || frames[1].JPEG[0] != 3 {
+		t.Errorf("Frames after window = %v, want the two recent frames", frames)
+	}
+	if frames[0].At.After(frames[1].At) {
+		t.Error("frames should be oldest first")
+	}
+
+	c.Reset()
+	if len(c.Frames()) != 0 {
+		t.Error("Reset should drop all frames")
+	}
+}
@@ -0,0 +1,39 @@
+package camera
+
+import "time"
+
+// =============================================================================
+// Monotonic timestamps
+// =============================================================================
+// Frame timestamps are stored in atomics as int64. time.Time.UnixNano()
+// drops Go's monotonic clock reading, so ages computed from stored
+// UnixNano values jump whenever NTP or an RTC reset steps the wall clock
+// (a step back makes every frame look "from the future", a step forward
+// makes every camera look stale at once).
+//
+// Instead we store nanoseconds elapsed since processStart, measured on
+// the monotonic clock, and convert back with monoToTime which preserves
+// the monotonic reading for time.Since / Sub.
+// =============================================================================
+
+// processStart anchors all stored monotonic timestamps.
+var processStart = time.Now()
+
+// monoNow returns monotonic nanoseconds since processStart.
+// Never returns 0, which callers use as "no timestamp yet".
+func monoNow() int64 {
+	n := int64(time.Since(processStart))
+	if n <= 0 {
+		return 1
+	}
+	return n
+}
+
+// monoToTime converts a monoNow value back to a time.Time that carries a
+// monotonic reading. Returns the zero Time for 0.
+func monoToTime(n int64) time.Time {
+	if n == 0 {
+		return time.Time{}
+	}
+	return processStart.Add(time.Duration(n))
+}
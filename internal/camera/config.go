@@ -1,5 +1,10 @@
 package camera
 
+import (
+	"camera-dashboard-go/internal/clock"
+	"time"
+)
+
 // =============================================================================
 // Camera Settings
 // =============================================================================
@@ -24,6 +29,58 @@ type Settings struct {
 	FPS        int    // Target frames per second
 	Format     string // Capture format: "mjpeg" or "yuyv"
 	MaxCameras int    // Maximum number of cameras to discover/use
+
+	DisabledDevices []string // Cameras to skip (see IsDeviceDisabled)
+
+	PerCamera []CameraOverride // Capture settings of single cameras, first match wins (see ForCamera)
+
+	NetworkCameras map[string]string // Declared stream cameras: name -> RTSP/HTTP URL (see network.go)
+	CSICameras     bool              // Probe for Pi camera modules with rpicam-hello (see csi.go)
+	Simulate       []string          // Play these files/directories instead of discovering cameras (see simulate.go)
+
+	SyncHistory int // Frames each FrameBuffer retains for soft-sync (0 = off, see FrameBuffer.ReadAt)
+
+	ClipWindow time.Duration // JPEG history kept per camera for saved clips and replay (0 = off, see clipbuffer.go)
+
+	Controls        map[string]int // Image controls set on each camera after discovery (see Controls.Apply)
+	ReapplyControls []string       // Cameras whose Controls are set again on every stream start ("all" = every camera)
+
+	CapsCachePath string // Capability cache JSON file; "" disables caching
+
+	FirstFrameWarn time.Duration // Warn when Start -> first frame exceeds this (0 = never)
+
+	HiddenFPS int // Decode rate while a worker is hidden (off screen); 0 = no throttle
+
+	NoTestPattern bool // Recovery mode sends no synthetic frames; SignalLost reports it instead
+
+	TraceEvery int // Time one decoded frame in this many through the pipeline (0 = off, see trace.go)
+
+	// Reconnect backoff (see backoff.go)
+	ReconnectCooldown time.Duration // Longest wait between attempts (0 = 30s)
+	ReconnectBudget   int           // Full attempts before retrying one format only (0 = 5)
+
+	// Stream health and format fallback (see streamhealth.go)
+	HealthWindow        time.Duration // Stream scoring window (0 = 30s)
+	FallbackCorruptRate float64       // Corrupt share (0.0-1.0) that switches MJPEG to YUYV; 0 = never
+	FallbackWidth       int           // YUYV fallback resolution cap
+	FallbackHeight      int
+
+	// USB bandwidth scheduling (see bandwidth.go)
+	BandwidthBudget   float64  // Bytes per second per USB 2.0 bus; 0 = scheduler off
+	BandwidthMinFPS   int      // Lowest capture rate the scheduler sets
+	BandwidthTiers    [][2]int // Resolutions to step down to after min FPS, largest first
+	BandwidthPriority []string // Cameras, most important first (see MatchesCamera)
+
+	// CPU pinning (nil = no pinning)
+	FFmpegCPUs  []int // FFmpeg processes run under taskset -c
+	CaptureCPUs []int // Capture/decode goroutines are locked to threads pinned here
+	FFmpegNice  int   // nice(1) increment for FFmpeg (0 = unchanged)
+
+	Faults *FaultInjector // Soak-test fault injection; nil in normal operation
+
+	Backend Backend // Replaces discovery and FFmpeg (integration tests); nil = real cameras
+
+	Clock clock.Clock // Frame limiting and stream health time (tests); nil = clock.System
 }
 
 // DefaultSettings returns sensible defaults for vehicle camera monitoring.
@@ -34,5 +91,48 @@ func DefaultSettings() Settings {
 		FPS:        DefaultFPS,
 		Format:     DefaultFormat,
 		MaxCameras: DefaultMaxCameras,
+		CSICameras: true,
+	}
+}
+
+// CameraOverride replaces the capture settings of the cameras it matches
+// (a [camera.<name>] section of config.ini). Zero fields keep Settings'.
+type CameraOverride struct {
+	Match         []string // Entries of MatchesCamera; any one of them matches
+	Width, Height int
+	FPS           int
+	Format        string
+}
+
+// ForCamera returns s with the first PerCamera override matching cam
+// applied.
+func (s Settings) ForCamera(cam Camera) Settings {
+	for _, o := range s.PerCamera {
+		if !o.matches(cam) {
+			continue
+		}
+		if o.Width > 0 {
+			s.Width = o.Width
+		}
+		if o.Height > 0 {
+			s.Height = o.Height
+		}
+		if o.FPS > 0 {
+			s.FPS = o.FPS
+		}
+		if o.Format != "" {
+			s.Format = o.Format
+		}
+		break
+	}
+	return s
+}
+
+func (o CameraOverride) matches(cam Camera) bool {
+	for _, entry := range o.Match {
+		if MatchesCamera(entry, cam) {
+			return true
+		}
 	}
+	return false
 }
@@ -0,0 +1,264 @@
+package camera
+
+import (
+	"fmt"
+	"log"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// =============================================================================
+// Image controls
+// =============================================================================
+// Brightness, contrast, saturation, exposure and auto white balance are
+// set through `v4l2-ctl --set-ctrl`, which talks to the driver while
+// FFmpeg keeps streaming. UVC drivers keep the values until the camera
+// is unplugged, so worker restarts don't lose them. Some cameras reset
+// exposure/white balance whenever streaming starts; cameras listed in
+// [controls] reapply_on_connect get the configured set again on the
+// first frame of every capture session (see reapplyControls).
+//
+// Control names differ between kernel versions (exposure_absolute vs
+// exposure_time_absolute, ...), so each control lists its known V4L2
+// names and the first one the device reports is used. Ranges are per
+// camera; values are raw driver units.
+// =============================================================================
+
+// Control names used in config and by the UI.
+const (
+	ControlBrightness       = "brightness"
+	ControlContrast         = "contrast"
+	ControlSaturation       = "saturation"
+	ControlExposure         = "exposure"
+	ControlAutoWhiteBalance = "auto_white_balance"
+)
+
+// ControlNames lists the supported controls in display order.
+var ControlNames = []string{
+	ControlBrightness,
+	ControlContrast,
+	ControlSaturation,
+	ControlExposure,
+	ControlAutoWhiteBalance,
+}
+
+// v4l2ControlNames maps each control to its V4L2 names, newest first.
+var v4l2ControlNames = map[string][]string{
+	ControlBrightness:       {"brightness"},
+	ControlContrast:         {"contrast"},
+	ControlSaturation:       {"saturation"},
+	ControlExposure:         {"exposure_time_absolute", "exposure_absolute"},
+	ControlAutoWhiteBalance: {"white_balance_automatic", "white_balance_temperature_auto"},
+}
+
+// Manual exposure only takes effect with auto exposure in manual mode.
+var autoExposureNames = []string{"auto_exposure", "exposure_auto"}
+
+const autoExposureManual = 1 // V4L2_EXPOSURE_MANUAL
+
+// ControlInfo describes one control as reported by the device.
+type ControlInfo struct {
+	Name     string // One of ControlNames
+	V4L2Name string
+	Type     string // "int", "bool" or "menu"
+	Min      int
+	Max      int
+	Step     int
+	Default  int
+	Value    int
+}
+
+// IsBool reports whether the control is an on/off switch.
+func (c ControlInfo) IsBool() bool {
+	return c.Type == "bool"
+}
+
+// runV4L2Ctl runs v4l2-ctl; replaced in tests.
+var runV4L2Ctl = func(args ...string) ([]byte, error) {
+	return exec.Command("v4l2-ctl", args...).CombinedOutput()
+}
+
+// Controls reads and sets image controls on one device.
+type Controls struct {
+	devicePath string
+}
+
+// NewControls returns the controls of the device at devicePath.
+func NewControls(devicePath string) *Controls {
+	return &Controls{devicePath: devicePath}
+}
+
+// List returns the supported controls the device has, in ControlNames order.
+func (c *Controls) List() ([]ControlInfo, error) {
+	all, err := c.listAll()
+	if err != nil {
+		return nil, err
+	}
+	var out []ControlInfo
+	for _, name := range ControlNames {
+		if info, ok := findControl(all, name); ok {
+			out = append(out, info)
+		}
+	}
+	return out, nil
+}
+
+// Set changes one control. Setting exposure also switches auto exposure
+// to manual.
+func (c *Controls) Set(name string, value int) error {
+	all, err := c.listAll()
+	if err != nil {
+		return err
+	}
+	info, ok := findControl(all, name)
+	if !ok {
+		return fmt.Errorf("%s: control %q not supported", c.devicePath, name)
+	}
+	if value < info.Min || (info.Max > info.Min && value > info.Max) {
+		return fmt.Errorf("%s: %s %d out of range %d..%d", c.devicePath, name, value, info.Min, info.Max)
+	}
+
+	var sets []string
+	if name == ControlExposure {
+		if auto, ok := firstPresent(all, autoExposureNames); ok && auto.Value != autoExposureManual {
+			sets = append(sets, fmt.Sprintf("%s=%d", auto.V4L2Name, autoExposureManual))
+		}
+	}
+	sets = append(sets, fmt.Sprintf("%s=%d", info.V4L2Name, value))
+	return c.setCtrl(sets)
+}
+
+// Reset restores every supported control, and auto exposure, to the
+// driver default.
+func (c *Controls) Reset() error {
+	all, err := c.listAll()
+	if err != nil {
+		return err
+	}
+	var sets []string
+	if auto, ok := firstPresent(all, autoExposureNames); ok {
+		sets = append(sets, fmt.Sprintf("%s=%d", auto.V4L2Name, auto.Default))
+	}
+	for _, name := range ControlNames {
+		// Exposure default is meaningless while auto exposure is on
+		if name == ControlExposure {
+			continue
+		}
+		if info, ok := findControl(all, name); ok {
+			sets = append(sets, fmt.Sprintf("%s=%d", info.V4L2Name, info.Default))
+		}
+	}
+	if len(sets) == 0 {
+		return nil
+	}
+	return c.setCtrl(sets)
+}
+
+// Apply sets each control in values (from [controls] in config.ini) and
+// logs failures; a camera without a control is not an error worth
+// stopping for.
+func (c *Controls) Apply(values map[string]int) {
+	for _, name := range ControlNames {
+		value, ok := values[name]
+		if !ok {
+			continue
+		}
+		if err := c.Set(name, value); err != nil {
+			log.Printf("[Controls] WARNING: %v", err)
+			continue
+		}
+		log.Printf("[Controls] %s: %s = %d", c.devicePath, name, value)
+	}
+}
+
+// shouldReapplyControls reports whether cam is listed in
+// Settings.ReapplyControls and has controls to reapply.
+func shouldReapplyControls(s Settings, cam Camera) bool {
+	if len(s.Controls) == 0 || !cam.HasDeviceNode() {
+		return false
+	}
+	for _, entry := range s.ReapplyControls {
+		if strings.EqualFold(entry, "all") || MatchesCamera(entry, cam) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Controls) listAll() (map[string]ControlInfo, error) {
+	output, err := runV4L2Ctl("-d", c.devicePath, "--list-ctrls")
+	if err != nil {
+		return nil, fmt.Errorf("%s: v4l2-ctl --list-ctrls: %v", c.devicePath, err)
+	}
+	return parseControlList(string(output)), nil
+}
+
+func (c *Controls) setCtrl(sets []string) error {
+	output, err := runV4L2Ctl("-d", c.devicePath, "--set-ctrl="+strings.Join(sets, ","))
+	if err != nil {
+		return fmt.Errorf("%s: v4l2-ctl --set-ctrl=%s: %v: %s",
+			c.devicePath, strings.Join(sets, ","), err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+func findControl(all map[string]ControlInfo, name string) (ControlInfo, bool) {
+	info, ok := firstPresent(all, v4l2ControlNames[name])
+	info.Name = name
+	return info, ok
+}
+
+func firstPresent(all map[string]ControlInfo, v4l2Names []string) (ControlInfo, bool) {
+	for _, v4l2Name := range v4l2Names {
+		if info, ok := all[v4l2Name]; ok {
+			return info, true
+		}
+	}
+	return ControlInfo{}, false
+}
+
+// Matches lines of v4l2-ctl --list-ctrls, e.g.
+//
+//	brightness 0x00980900 (int)    : min=0 max=255 step=1 default=128 value=128
+var controlLineRe = regexp.MustCompile(`^\s*(\w+)\s+0x[0-9a-fA-F]+\s+\((\w+)\)\s*:\s*(.*)$`)
+
+// parseControlList parses v4l2-ctl --list-ctrls output keyed by V4L2 name.
+func parseControlList(output string) map[string]ControlInfo {
+	controls := make(map[string]ControlInfo)
+	for _, line := range strings.Split(output, "\n") {
+		m := controlLineRe.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		info := ControlInfo{V4L2Name: m[1], Type: m[2], Step: 1}
+		if info.Type == "bool" {
+			info.Max = 1
+		}
+		for _, field := range strings.Fields(m[3]) {
+			key, value, ok := strings.Cut(field, "=")
+			if !ok {
+				continue
+			}
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				continue
+			}
+			switch key {
+			case "min":
+				info.Min = n
+			case "max":
+				info.Max = n
+			case "step":
+				info.Step = n
+			case "default":
+				info.Default = n
+			case "value":
+				info.Value = n
+			}
+		}
+		controls[info.V4L2Name] = info
+	}
+	return controls
+}
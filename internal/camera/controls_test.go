@@ -0,0 +1,110 @@
+package camera
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const sampleControls = `
+User Controls
+
+                     brightness 0x00980900 (int)    : min=0 max=255 step=1 default=128 value=140
+                       contrast 0x00980901 (int)    : min=0 max=255 step=1 default=32 value=32
+ white_balance_temperature_auto 0x0098090c (bool)   : default=1 value=1
+
+Camera Controls
+
+                  exposure_auto 0x009a0901 (menu)   : min=0 max=3 default=3 value=3
+              exposure_absolute 0x009a0902 (int)    : min=3 max=2047 step=1 default=250 value=250 flags=inactive
+`
+
+// fakeV4L2Ctl serves sampleControls and records --set-ctrl arguments.
+func fakeV4L2Ctl(t *testing.T) *[]string {
+	t.Helper()
+	var sets []string
+	orig := runV4L2Ctl
+	runV4L2Ctl = func(args ...string) ([]byte, error) {
+		last := args[len(args)-1]
+		if strings.HasPrefix(last, "--set-ctrl=") {
+			sets = append(sets, strings.TrimPrefix(last, "--set-ctrl="))
+			return nil, nil
+		}
+		return []byte(sampleControls), nil
+	}
+	t.Cleanup(func() { runV4L2Ctl = orig })
+	return &sets
+}
+
+func TestControlsList(t *testing.T) {
+	fakeV4L2Ctl(t)
+	list, err := NewControls("/dev/video0").List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, c := range list {
+		names = append(names, c.Name)
+	}
+	// No saturation on this camera; old kernel names are matched
+	want := []string{ControlBrightness, ControlContrast, ControlExposure, ControlAutoWhiteBalance}
+	if !reflect.DeepEqual(names, want) {
+		t.Fatalf("controls = %v, want %v", names, want)
+	}
+	if b := list[0]; b.Min != 0 || b.Max != 255 || b.Default != 128 || b.Value != 140 {
+		t.Errorf("brightness = %+v", b)
+	}
+	if awb := list[3]; !awb.IsBool() || awb.Max != 1 || awb.V4L2Name != "white_balance_temperature_auto" {
+		t.Errorf("auto white balance = %+v", awb)
+	}
+}
+
+func TestControlsSet(t *testing.T) {
+	sets := fakeV4L2Ctl(t)
+	c := NewControls("/dev/video0")
+
+	if err := c.Set(ControlExposure, 100); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Set(ControlBrightness, 300); err == nil {
+		t.Error("out-of-range brightness should fail")
+	}
+	if err := c.Set(ControlSaturation, 10); err == nil {
+		t.Error("unsupported control should fail")
+	}
+	if err := c.Reset(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		"exposure_auto=1,exposure_absolute=100",
+		"exposure_auto=3,brightness=128,contrast=32,white_balance_temperature_auto=1",
+	}
+	if !reflect.DeepEqual(*sets, want) {
+		t.Errorf("set-ctrl calls = %q, want %q", *sets, want)
+	}
+}
+
+func TestShouldReapplyControls(t *testing.T) {
+	cam := Camera{DeviceID: "video2", DevicePath: "/dev/video2"}
+	controls := map[string]int{ControlExposure: 100}
+
+	tests := []struct {
+		name string
+		s    Settings
+		cam  Camera
+		want bool
+	}{
+		{"listed", Settings{Controls: controls, ReapplyControls: []string{"video2"}}, cam, true},
+		{"all", Settings{Controls: controls, ReapplyControls: []string{"ALL"}}, cam, true},
+		{"not listed", Settings{Controls: controls, ReapplyControls: []string{"video0"}}, cam, false},
+		{"no controls", Settings{ReapplyControls: []string{"all"}}, cam, false},
+		{"network camera", Settings{Controls: controls, ReapplyControls: []string{"all"}},
+			Camera{DeviceID: "net-trailer", DevicePath: "rtsp://10.0.0.5/live"}, false},
+	}
+	for _, tt := range tests {
+		if got := shouldReapplyControls(tt.s, tt.cam); got != tt.want {
+			t.Errorf("%s: shouldReapplyControls = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
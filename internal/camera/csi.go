@@ -0,0 +1,130 @@
+package camera
+
+import (
+	"fmt"
+	"log"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// =============================================================================
+// Raspberry Pi camera modules (CSI, libcamera)
+// =============================================================================
+// CSI modules aren't plain V4L2 capture devices, so they're listed with
+// rpicam-hello --list-cameras and captured with rpicam-vid --codec mjpeg
+// writing to stdout: the same MJPEG stream the FFmpeg pipe produces, so
+// frame reading, decoding and the FrameBuffer are shared. Bullseye names
+// the tools libcamera-hello / libcamera-vid; either works.
+//
+// DevicePath is "csi:<index>" and DeviceID "csi<index>". CSI cameras take
+// slots before USB cameras. They can't be hot-plugged, and V4L2 controls
+// don't apply to them. [camera] csi = false skips the probe.
+// =============================================================================
+
+// csiPrefix marks a CSI camera's DevicePath.
+const csiPrefix = "csi:"
+
+var (
+	rpicamVidOnce  sync.Once
+	rpicamVidBin   string
+	rpicamHelloBin = []string{"rpicam-hello", "libcamera-hello"}
+)
+
+// csiIndex returns the libcamera index of a "csi:N" path.
+func csiIndex(path string) (int, bool) {
+	if !strings.HasPrefix(path, csiPrefix) {
+		return 0, false
+	}
+	n, err := strconv.Atoi(strings.TrimPrefix(path, csiPrefix))
+	return n, err == nil && n >= 0
+}
+
+// rpicamVidPath returns the rpicam-vid (or libcamera-vid) binary, or ""
+// if neither is installed.
+func rpicamVidPath() string {
+	rpicamVidOnce.Do(func() {
+		for _, name := range []string{"rpicam-vid", "libcamera-vid"} {
+			if p, err := exec.LookPath(name); err == nil {
+				rpicamVidBin = p
+				return
+			}
+		}
+		log.Println("[Capture] WARNING: rpicam-vid / libcamera-vid not found, CSI cameras can't be captured (install rpicam-apps)")
+	})
+	return rpicamVidBin
+}
+
+// csiCaptureArgs returns the rpicam-vid arguments to try: the configured
+// mode, then the same size at the sensor's default frame rate.
+func csiCaptureArgs(index, width, height, fps int) [][]string {
+	base := []string{"--camera", strconv.Itoa(index), "-t", "0", "--nopreview",
+		"--codec", "mjpeg", "--width", strconv.Itoa(width), "--height", strconv.Itoa(height)}
+	withFPS := append(append([]string{}, base...), "--framerate", strconv.Itoa(fps), "-o", "-")
+	return [][]string{withFPS, append(append([]string{}, base...), "-o", "-")}
+}
+
+// csiListRegex matches "0 : imx708 [4608x2592 10-bit RGGB] (/base/...)".
+var csiListRegex = regexp.MustCompile(`^\s*(\d+)\s*:\s*(\S+)\s*\[`)
+
+// parseCSICameraList parses rpicam-hello --list-cameras output into
+// (index, sensor) pairs.
+func parseCSICameraList(output string) []listedDevice {
+	var devices []listedDevice
+	for _, line := range strings.Split(output, "\n") {
+		if m := csiListRegex.FindStringSubmatch(line); m != nil {
+			devices = append(devices, listedDevice{Input: m[1], Name: m[2]})
+		}
+	}
+	return devices
+}
+
+// discoverCSICameras lists attached camera modules, or nothing when the
+// probe is disabled or rpicam-apps isn't installed.
+func discoverCSICameras(s Settings) []Camera {
+	if !s.CSICameras {
+		return nil
+	}
+	var output []byte
+	found := false
+	for _, name := range rpicamHelloBin {
+		if _, err := exec.LookPath(name); err != nil {
+			continue
+		}
+		found = true
+		// Exits non-zero when no camera is attached; the list is still valid
+		output, _ = exec.Command(name, "--list-cameras").CombinedOutput()
+		break
+	}
+	if !found {
+		return nil
+	}
+
+	var cameras []Camera
+	for _, dev := range parseCSICameraList(string(output)) {
+		cam := Camera{
+			DeviceID:   "csi" + dev.Input,
+			DevicePath: csiPrefix + dev.Input,
+			Name:       fmt.Sprintf("Pi Camera (%s)", dev.Name),
+			Available:  true,
+		}
+		cs := s.ForCamera(cam) // Matched by its ID, csi0
+		cam.Capabilities = CameraCapabilities{
+			MaxWidth:  cs.Width,
+			MaxHeight: cs.Height,
+			MaxFPS:    cs.FPS,
+			Format:    "mjpeg",
+		}
+		if isDisabled(s.DisabledDevices, cam) {
+			log.Printf("[Discovery] %s is disabled in config, skipping", cam.DeviceID)
+			continue
+		}
+		cameras = append(cameras, cam)
+	}
+	if len(cameras) > 0 {
+		log.Printf("[Discovery] Found %d CSI camera module(s)", len(cameras))
+	}
+	return cameras
+}
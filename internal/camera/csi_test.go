@@ -0,0 +1,53 @@
+package camera
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseCSICameraList(t *testing.T) {
+	output := `Available cameras
+-----------------
+0 : imx708_wide [4608x2592 10-bit RGGB] (/base/axi/pcie@120000/rp1/i2c@88000/imx708@1a)
+    Modes: 'SRGGB10_CSI2P' : 1536x864 [120.13 fps - (768, 432)/3072x1728 crop]
+                             2304x1296 [56.03 fps - (0, 0)/4608x2592 crop]
+
+1 : ov5647 [2592x1944 10-bit GBRG] (/base/axi/pcie@120000/rp1/i2c@80000/ov5647@36)
+    Modes: 'SGBRG10_CSI2P' : 640x480 [58.92 fps - (16, 0)/2560x1920 crop]
+`
+	want := []listedDevice{{Input: "0", Name: "imx708_wide"}, {Input: "1", Name: "ov5647"}}
+	if got := parseCSICameraList(output); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseCSICameraList = %+v, want %+v", got, want)
+	}
+	if got := parseCSICameraList("No cameras available!\n"); len(got) != 0 {
+		t.Errorf("no cameras parsed as %+v", got)
+	}
+}
+
+func TestCSIIndex(t *testing.T) {
+	if n, ok := csiIndex("csi:1"); !ok || n != 1 {
+		t.Errorf("csiIndex(csi:1) = %d, %v", n, ok)
+	}
+	for _, path := range []string{"/dev/video0", "csi:", "csi:-1", "csi:front"} {
+		if _, ok := csiIndex(path); ok {
+			t.Errorf("csiIndex(%q) should not match", path)
+		}
+	}
+}
+
+func TestCSICaptureArgs(t *testing.T) {
+	variants := csiCaptureArgs(0, 640, 480, 15)
+	if len(variants) != 2 {
+		t.Fatalf("got %d variants, want 2", len(variants))
+	}
+	first := strings.Join(variants[0], " ")
+	for _, want := range []string{"--camera 0", "--codec mjpeg", "--width 640 --height 480", "--framerate 15", "-o -"} {
+		if !strings.Contains(first, want) {
+			t.Errorf("args %q missing %q", first, want)
+		}
+	}
+	if strings.Contains(strings.Join(variants[1], " "), "--framerate") {
+		t.Errorf("fallback should use the sensor's default frame rate: %v", variants[1])
+	}
+}
@@ -0,0 +1,144 @@
+package camera
+
+import (
+	"fmt"
+	"log"
+	"strings"
+)
+
+// =============================================================================
+// Desktop capture (macOS / Windows development)
+// =============================================================================
+// The dashboard targets Linux + V4L2, but on a laptop it can capture from
+// the built-in webcam through FFmpeg's avfoundation (macOS) or dshow
+// (Windows) input instead of showing test patterns. Only discovery and the
+// FFmpeg input arguments differ (capture_avfoundation.go,
+// capture_dshow.go); decoding, frame buffers and the UI are unchanged.
+//
+// Desktop cameras have no /dev node: DevicePath holds the FFmpeg input
+// ("0" for avfoundation, "video=<name>" for dshow) and DeviceID is camN.
+// V4L2 controls, USB identity and hotplug don't apply to them.
+// =============================================================================
+
+// listedDevice is a video device listed by an external tool (FFmpeg
+// -list_devices, rpicam-hello --list-cameras).
+type listedDevice struct {
+	Input string // FFmpeg -i argument
+	Name  string
+}
+
+// HasDeviceNode reports whether cam is a /dev device (V4L2) rather than
+// a desktop FFmpeg input.
+func (c Camera) HasDeviceNode() bool {
+	return strings.HasPrefix(c.DevicePath, "/dev/")
+}
+
+// desktopCameras converts listed devices into cameras, skipping disabled
+// ones and applying the camera limit. Capabilities are the configured
+// ones; the FFmpeg input variants fall back when the camera refuses them.
+func desktopCameras(devices []listedDevice, s Settings) []Camera {
+	maxCameras := s.MaxCameras
+	if maxCameras <= 0 {
+		maxCameras = DefaultMaxCameras
+	}
+
+	var cameras []Camera
+	for _, dev := range devices {
+		if IsDeviceDisabled(s.DisabledDevices, dev.Input) {
+			log.Printf("[Discovery] %s (%s) is disabled in config, skipping", dev.Input, dev.Name)
+			continue
+		}
+		if len(cameras) >= maxCameras {
+			break
+		}
+		cameras = append(cameras, Camera{
+			DeviceID:   fmt.Sprintf("cam%d", len(cameras)),
+			DevicePath: dev.Input,
+			Name:       dev.Name,
+			Available:  true,
+			Capabilities: CameraCapabilities{
+				MaxWidth:  s.Width,
+				MaxHeight: s.Height,
+				MaxFPS:    s.FPS,
+				Format:    s.Format,
+			},
+		})
+	}
+
+	log.Printf("[Discovery] Found %d desktop cameras", len(cameras))
+	for _, cam := range cameras {
+		log.Printf("[Discovery]   %s: %s (%s)", cam.DeviceID, cam.Name, cam.DevicePath)
+	}
+	return cameras
+}
+
+// stripLogPrefix removes FFmpeg's "[dshow @ 0x...] " log context.
+func stripLogPrefix(line string) string {
+	line = strings.TrimSpace(line)
+	if strings.HasPrefix(line, "[") {
+		if i := strings.Index(line, "] "); i > 0 {
+			line = strings.TrimSpace(line[i+2:])
+		}
+	}
+	return line
+}
+
+// parseAVFoundationDevices parses the video section of FFmpeg's
+// avfoundation -list_devices output. Screen capture devices are skipped.
+func parseAVFoundationDevices(output string) []listedDevice {
+	var devices []listedDevice
+	inVideo := false
+	for _, line := range strings.Split(output, "\n") {
+		line = stripLogPrefix(line)
+		switch {
+		case strings.Contains(line, "AVFoundation video devices"):
+			inVideo = true
+			continue
+		case strings.Contains(line, "AVFoundation audio devices"):
+			inVideo = false
+			continue
+		}
+		if !inVideo || !strings.HasPrefix(line, "[") {
+			continue
+		}
+		index, name, ok := strings.Cut(strings.TrimPrefix(line, "["), "] ")
+		if !ok || strings.HasPrefix(name, "Capture screen") {
+			continue
+		}
+		devices = append(devices, listedDevice{Input: index, Name: strings.TrimSpace(name)})
+	}
+	return devices
+}
+
+// parseDShowDevices parses "ffmpeg -list_devices true -f dshow -i dummy".
+// FFmpeg 5+ tags each device with "(video)"/"(audio)"; older versions
+// list them under "DirectShow video devices" / "audio devices" headers.
+func parseDShowDevices(output string) []listedDevice {
+	var devices []listedDevice
+	inVideo := false
+	for _, line := range strings.Split(output, "\n") {
+		line = stripLogPrefix(line)
+		switch {
+		case strings.HasPrefix(line, "DirectShow video devices"):
+			inVideo = true
+			continue
+		case strings.HasPrefix(line, "DirectShow audio devices"):
+			inVideo = false
+			continue
+		}
+		if !strings.HasPrefix(line, `"`) {
+			continue // "Alternative name" and other detail lines
+		}
+		end := strings.Index(line[1:], `"`)
+		if end < 0 {
+			continue
+		}
+		name := line[1 : end+1]
+		kind := strings.TrimSpace(line[end+2:])
+		isVideo := strings.Contains(kind, "video") || (kind == "" && inVideo)
+		if isVideo && name != "" {
+			devices = append(devices, listedDevice{Input: "video=" + name, Name: name})
+		}
+	}
+	return devices
+}
@@ -0,0 +1,65 @@
+package camera
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseAVFoundationDevices(t *testing.T) {
+	output := `[AVFoundation indev @ 0x7f9] AVFoundation video devices:
+[AVFoundation indev @ 0x7f9] [0] FaceTime HD Camera
+[AVFoundation indev @ 0x7f9] [1] USB Camera
+[AVFoundation indev @ 0x7f9] [2] Capture screen 0
+[AVFoundation indev @ 0x7f9] AVFoundation audio devices:
+[AVFoundation indev @ 0x7f9] [0] MacBook Pro Microphone
+: Input/output error
+`
+	want := []listedDevice{{Input: "0", Name: "FaceTime HD Camera"}, {Input: "1", Name: "USB Camera"}}
+	if got := parseAVFoundationDevices(output); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseAVFoundationDevices = %+v, want %+v", got, want)
+	}
+}
+
+func TestParseDShowDevices(t *testing.T) {
+	want := []listedDevice{{Input: "video=Integrated Camera", Name: "Integrated Camera"}}
+
+	modern := `[dshow @ 000001] "Integrated Camera" (video)
+[dshow @ 000001]   Alternative name "@device_pnp_\\?\usb#vid_04f2"
+[dshow @ 000001] "Microphone (Realtek Audio)" (audio)
+[dshow @ 000001]   Alternative name "@device_cm_{33D9A762}"
+dummy: Immediate exit requested
+`
+	if got := parseDShowDevices(modern); !reflect.DeepEqual(got, want) {
+		t.Errorf("FFmpeg 5+ list = %+v, want %+v", got, want)
+	}
+
+	legacy := `[dshow @ 0000a] DirectShow video devices (some may be both video and audio devices)
+[dshow @ 0000a]  "Integrated Camera"
+[dshow @ 0000a]     Alternative name "@device_pnp_\\?\usb#vid_04f2"
+[dshow @ 0000a] DirectShow audio devices
+[dshow @ 0000a]  "Microphone (Realtek Audio)"
+`
+	if got := parseDShowDevices(legacy); !reflect.DeepEqual(got, want) {
+		t.Errorf("legacy list = %+v, want %+v", got, want)
+	}
+}
+
+func TestDesktopCameras(t *testing.T) {
+	s := DefaultSettings()
+	s.MaxCameras = 1
+	s.DisabledDevices = []string{"video=Virtual Cam"}
+	cams := desktopCameras([]listedDevice{
+		{Input: "video=Virtual Cam", Name: "Virtual Cam"},
+		{Input: "video=Integrated Camera", Name: "Integrated Camera"},
+		{Input: "video=USB Camera", Name: "USB Camera"},
+	}, s)
+	if len(cams) != 1 || cams[0].DeviceID != "cam0" || cams[0].DevicePath != "video=Integrated Camera" {
+		t.Fatalf("desktopCameras = %+v", cams)
+	}
+	if cams[0].HasDeviceNode() {
+		t.Error("desktop camera should not report a device node")
+	}
+	if cams[0].Capabilities.MaxWidth != s.Width || cams[0].Capabilities.MaxFPS != s.FPS {
+		t.Errorf("capabilities = %+v, want configured %dx%d @ %d", cams[0].Capabilities, s.Width, s.Height, s.FPS)
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 )
 
 // CameraCapabilities holds the camera's maximum capabilities
@@ -28,12 +29,17 @@ type Camera struct {
 	Name         string
 	Available    bool
 	Capabilities CameraCapabilities
+	USB          USBDescriptor // Zero for non-USB devices
 }
 
 // DiscoverCamerasWithSettings finds all available USB camera devices on Linux
-// using the provided settings for resolution/FPS defaults.
+// using the provided settings for resolution/FPS defaults. On macOS and
+// Windows it lists FFmpeg desktop capture devices instead (desktop.go).
 func DiscoverCamerasWithSettings(s Settings) ([]Camera, error) {
 	log.Println("[Discovery] Starting camera discovery...")
+	if cameras, ok := discoverDesktopCameras(s); ok {
+		return cameras, nil
+	}
 	var cameras []Camera
 	maxCameras := s.MaxCameras
 	if maxCameras <= 0 {
@@ -95,6 +101,17 @@ func DiscoverCamerasWithSettings(s Settings) ([]Camera, error) {
 		}
 	}
 
+	// Drop cameras disabled in config before applying the limit
+	enabled := devicePaths[:0]
+	for _, dev := range devicePaths {
+		if IsDeviceDisabled(s.DisabledDevices, dev.path) {
+			log.Printf("[Discovery] %s (%s) is disabled in config, skipping", dev.path, cleanCameraName(dev.name))
+			continue
+		}
+		enabled = append(enabled, dev)
+	}
+	devicePaths = enabled
+
 	// Limit to configured number of cameras
 	if len(devicePaths) > maxCameras {
 		devicePaths = devicePaths[:maxCameras]
@@ -111,7 +128,8 @@ func DiscoverCamerasWithSettings(s Settings) ([]Camera, error) {
 			Name:       cleanCameraName(dev.name),
 			Available:  true,
 		}
-		cam.Capabilities = queryCameraCapabilities(dev.path, numCameras, s)
+		cam.USB = ReadUSBDescriptor(dev.path)
+		cam.Capabilities = queryCameraCapabilities(dev.path, numCameras, s.ForCamera(cam))
 		cameras = append(cameras, cam)
 	}
 
@@ -130,9 +148,9 @@ func DiscoverCamerasWithSettings(s Settings) ([]Camera, error) {
 
 	log.Printf("[Discovery] Found %d cameras", len(cameras))
 	for _, cam := range cameras {
-		log.Printf("[Discovery]   %s: %dx%d @ %dfps (%s)",
+		log.Printf("[Discovery]   %s: %dx%d @ %dfps (%s) %s",
 			cam.DeviceID, cam.Capabilities.MaxWidth, cam.Capabilities.MaxHeight,
-			cam.Capabilities.MaxFPS, cam.Capabilities.Format)
+			cam.Capabilities.MaxFPS, cam.Capabilities.Format, cam.USB)
 	}
 	return cameras, nil
 }
@@ -143,6 +161,90 @@ func DiscoverCameras() ([]Camera, error) {
 	return DiscoverCamerasWithSettings(DefaultSettings())
 }
 
+// withExtraCameras puts declared network cameras and CSI camera modules
+// first and fills the remaining slots with discovered USB cameras.
+func withExtraCameras(extra, local []Camera, s Settings) []Camera {
+	if len(extra) == 0 {
+		return local
+	}
+	maxCameras := s.MaxCameras
+	if maxCameras <= 0 {
+		maxCameras = DefaultMaxCameras
+	}
+	if len(extra) > maxCameras {
+		log.Printf("[Discovery] WARNING: %d network/CSI cameras but only %d slots", len(extra), maxCameras)
+		extra = extra[:maxCameras]
+	}
+	if free := maxCameras - len(extra); len(local) > free {
+		for _, cam := range local[free:] {
+			log.Printf("[Discovery] No slot left for %s (%s) after network/CSI cameras", cam.DeviceID, cam.DevicePath)
+		}
+		local = local[:free]
+	}
+	for _, cam := range extra {
+		log.Printf("[Discovery]   %s: %s (%s)", cam.DeviceID, cam.Name, RedactSource(cam.DevicePath))
+	}
+	return append(extra, local...)
+}
+
+// IsDeviceDisabled reports whether devicePath matches an entry of
+// disabled: a device path ("/dev/video2"), a device ID ("video2"), or a
+// USB identity ("046d:0825:SERIAL", stable across re-enumeration).
+func IsDeviceDisabled(disabled []string, devicePath string) bool {
+	if len(disabled) == 0 {
+		return false
+	}
+	identity := ""
+	for _, entry := range disabled {
+		switch {
+		case entry == devicePath || entry == filepath.Base(devicePath):
+			return true
+		case strings.Count(entry, ":") == 2:
+			if identity == "" {
+				identity = usbIdentity(devicePath)
+			}
+			if entry == identity {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// DisableKey returns the config entry used to disable cam: its USB
+// identity when the camera reports a serial number, otherwise its
+// device path.
+func DisableKey(cam Camera) string {
+	if cam.USB.VendorID != "" && cam.USB.Serial != "" {
+		return fmt.Sprintf("%s:%s:%s", cam.USB.VendorID, cam.USB.ProductID, cam.USB.Serial)
+	}
+	return cam.DevicePath
+}
+
+// MatchesCamera reports whether a per-camera config entry refers to cam.
+// entry may be a device path (/dev/video0), a device ID (video0), a
+// USB identity vendor:product:serial as returned by DisableKey, a USB
+// port (port:1-1.2) or a serial number alone (serial:A1B2C3D4).
+func MatchesCamera(entry string, cam Camera) bool {
+	entry = strings.TrimSpace(entry)
+	if entry == "" {
+		return false
+	}
+	if entry == cam.DevicePath || entry == cam.DeviceID {
+		return true
+	}
+	if strings.HasPrefix(entry, "port:") {
+		return cam.USB.Port != "" && strings.TrimPrefix(entry, "port:") == cam.USB.Port
+	}
+	if strings.HasPrefix(entry, "serial:") {
+		return cam.USB.Serial != "" && strings.TrimPrefix(entry, "serial:") == cam.USB.Serial
+	}
+	if cam.USB.VendorID == "" || cam.USB.Serial == "" {
+		return false
+	}
+	return strings.EqualFold(entry, fmt.Sprintf("%s:%s:%s", cam.USB.VendorID, cam.USB.ProductID, cam.USB.Serial))
+}
+
 // isUSBCamera checks if the device name indicates a USB camera
 func isUSBCamera(name string) bool {
 	nameLower := strings.ToLower(name)
@@ -178,9 +280,9 @@ func extractVideoNumber(deviceID string) int {
 
 // ResolutionPreset defines preferred resolutions for different scenarios
 type ResolutionPreset struct {
-	Width    int
-	Height   int
-	Priority int // Higher = better match
+	Width    int `json:"width"`
+	Height   int `json:"height"`
+	Priority int `json:"priority,omitempty"` // Higher = better match
 }
 
 // getOptimalResolution returns the configured resolution from settings.
@@ -225,6 +327,7 @@ func abs(x int) int {
 
 // queryCameraCapabilities queries the camera's resolution and FPS capabilities.
 // Returns optimal settings based on camera, display, and Pi constraints.
+// The v4l2-ctl probe is served from the capability cache when possible.
 func queryCameraCapabilities(devicePath string, numCameras int, s Settings) CameraCapabilities {
 	caps := CameraCapabilities{
 		MaxWidth:  s.Width,
@@ -233,23 +336,53 @@ func queryCameraCapabilities(devicePath string, numCameras int, s Settings) Came
 		Format:    s.Format,
 	}
 
-	cmd := exec.Command("v4l2-ctl", "-d", devicePath, "--list-formats-ext")
-	output, err := cmd.Output()
+	probe, err := cachedProbe(s.CapsCachePath, devicePath, probeCameraFormats)
 	if err != nil {
 		log.Printf("[Discovery] Failed to query capabilities for %s: %v", devicePath, err)
 		return caps
 	}
 
-	lines := strings.Split(string(output), "\n")
-	inMJPEG := false
+	if probe.MaxFPS > caps.MaxFPS {
+		caps.MaxFPS = probe.MaxFPS
+	}
+
+	// Pick optimal resolution
+	if len(probe.Resolutions) > 0 {
+		caps.MaxWidth, caps.MaxHeight = getOptimalResolution(probe.Resolutions, numCameras, s)
+	}
+
+	// Limit FPS based on number of cameras to reduce USB bandwidth contention
+	// USB 2.0 bandwidth is limited (~35MB/s real-world shared across all devices)
+	// At 640x480 MJPEG, each frame is ~20-40KB, so 30fps = 0.6-1.2MB/s per camera
+	// With 2+ cameras, we can get USB buffer overruns causing stream failures
+	caps.MaxFPS = getOptimalFPS(caps.MaxFPS, numCameras, s)
 
-	// Regex patterns
-	sizeRegex := regexp.MustCompile(`Size: Discrete (\d+)x(\d+)`)
-	fpsRegex := regexp.MustCompile(`(\d+)\.(\d+) fps`)
+	return caps
+}
+
+// Regex patterns for v4l2-ctl --list-formats-ext output
+var (
+	sizeRegex = regexp.MustCompile(`Size: Discrete (\d+)x(\d+)`)
+	fpsRegex  = regexp.MustCompile(`(\d+)\.(\d+) fps`)
+)
+
+// probeCameraFormats runs v4l2-ctl and returns the MJPEG resolutions and
+// highest MJPEG frame rate the device advertises.
+func probeCameraFormats(devicePath string) (*capabilityProbe, error) {
+	cmd := exec.Command("v4l2-ctl", "-d", devicePath, "--list-formats-ext")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+	return parseFormatList(string(output)), nil
+}
 
-	var availableResolutions []ResolutionPreset
+// parseFormatList parses v4l2-ctl --list-formats-ext output.
+func parseFormatList(output string) *capabilityProbe {
+	probe := &capabilityProbe{ProbedAt: time.Now()}
+	inMJPEG := false
 
-	for _, line := range lines {
+	for _, line := range strings.Split(output, "\n") {
 		line = strings.TrimSpace(line)
 
 		// Check for MJPEG format section (preferred - low CPU)
@@ -268,7 +401,7 @@ func queryCameraCapabilities(devicePath string, numCameras int, s Settings) Came
 			if matches := sizeRegex.FindStringSubmatch(line); len(matches) == 3 {
 				width, _ := strconv.Atoi(matches[1])
 				height, _ := strconv.Atoi(matches[2])
-				availableResolutions = append(availableResolutions, ResolutionPreset{
+				probe.Resolutions = append(probe.Resolutions, ResolutionPreset{
 					Width:  width,
 					Height: height,
 				})
@@ -277,25 +410,13 @@ func queryCameraCapabilities(devicePath string, numCameras int, s Settings) Came
 			// Parse FPS - take the highest available
 			if matches := fpsRegex.FindStringSubmatch(line); len(matches) == 3 {
 				fps, _ := strconv.Atoi(matches[1])
-				if fps > caps.MaxFPS {
-					caps.MaxFPS = fps
+				if fps > probe.MaxFPS {
+					probe.MaxFPS = fps
 				}
 			}
 		}
 	}
-
-	// Pick optimal resolution
-	if len(availableResolutions) > 0 {
-		caps.MaxWidth, caps.MaxHeight = getOptimalResolution(availableResolutions, numCameras, s)
-	}
-
-	// Limit FPS based on number of cameras to reduce USB bandwidth contention
-	// USB 2.0 bandwidth is limited (~35MB/s real-world shared across all devices)
-	// At 640x480 MJPEG, each frame is ~20-40KB, so 30fps = 0.6-1.2MB/s per camera
-	// With 2+ cameras, we can get USB buffer overruns causing stream failures
-	caps.MaxFPS = getOptimalFPS(caps.MaxFPS, numCameras, s)
-
-	return caps
+	return probe
 }
 
 // getOptimalFPS returns the configured FPS from settings.
@@ -334,6 +455,9 @@ func discoverCamerasSimple(s Settings) ([]Camera, error) {
 		if _, err := os.Stat(devicePath); os.IsNotExist(err) {
 			continue
 		}
+		if IsDeviceDisabled(s.DisabledDevices, devicePath) {
+			continue
+		}
 
 		// Verify it's a video capture device using v4l2-ctl
 		cmd := exec.Command("v4l2-ctl", "--device="+devicePath, "--info")
@@ -362,7 +486,8 @@ func discoverCamerasSimple(s Settings) ([]Camera, error) {
 			Name:       fmt.Sprintf("Camera %d", i+1),
 			Available:  true,
 		}
-		cam.Capabilities = queryCameraCapabilities(devicePath, numCameras, s)
+		cam.USB = ReadUSBDescriptor(devicePath)
+		cam.Capabilities = queryCameraCapabilities(devicePath, numCameras, s.ForCamera(cam))
 		cameras = append(cameras, cam)
 	}
 
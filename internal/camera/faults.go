@@ -0,0 +1,194 @@
+package camera
+
+import (
+	"fmt"
+	"log"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// =============================================================================
+// Fault injection (soak-test mode)
+// =============================================================================
+// Bench-only: randomly breaks capture workers so the recovery paths
+// (stale detection, watchdog, test-pattern fallback, reconnect) can be
+// exercised for hours before the unit goes in the vehicle. Enabled only
+// via the undocumented [soak] config section.
+//
+// Per-frame faults (checked in the capture loop):
+//   - Corrupt JPEG: flip bytes / truncate before decode
+//   - Delay frame:  sleep before publishing the frame
+// Periodic faults (rolled once per second per worker):
+//   - Kill FFmpeg:       the stream ends as if FFmpeg crashed
+//   - Device removal:    kill FFmpeg and refuse to reopen the device for
+//                        RemovalDuration, as if it was unplugged
+// =============================================================================
+
+// FaultConfig holds fault rates. Zero disables a fault.
+type FaultConfig struct {
+	KillFFmpegPerHour    float64       // Expected FFmpeg kills per worker per hour
+	CorruptJPEGRate      float64       // Probability per frame (0..1)
+	DelayFrameRate       float64       // Probability per frame (0..1)
+	DelayFrameMax        time.Duration // Delays are uniform in (0, DelayFrameMax]
+	DeviceRemovalPerHour float64       // Expected simulated unplugs per worker per hour
+	RemovalDuration      time.Duration // How long a simulated unplug lasts
+	Seed                 int64         // 0 = seed from the clock
+}
+
+// FaultInjector applies FaultConfig to capture workers.
+type FaultInjector struct {
+	cfg FaultConfig
+
+	rngMu sync.Mutex
+	rng   *rand.Rand
+
+	removedMu    sync.Mutex
+	removedUntil map[string]time.Time // DeviceID -> end of simulated unplug
+
+	stopCh   chan struct{}
+	stopOnce sync.Once
+
+	// Counters for the periodic summary
+	kills     atomic.Uint64
+	corrupted atomic.Uint64
+	delayed   atomic.Uint64
+	removals  atomic.Uint64
+}
+
+// NewFaultInjector creates an injector. Call Start to enable periodic faults.
+func NewFaultInjector(cfg FaultConfig) *FaultInjector {
+	seed := cfg.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	log.Printf("[Soak] WARNING: fault injection enabled (seed %d) - do not use in the vehicle", seed)
+	return &FaultInjector{
+		cfg:          cfg,
+		rng:          rand.New(rand.NewSource(seed)),
+		removedUntil: make(map[string]time.Time),
+		stopCh:       make(chan struct{}),
+	}
+}
+
+// Start rolls periodic faults once per second against the workers
+// returned by workers (called each tick, so manager reinitialisation
+// is picked up), and logs a summary every 10 minutes.
+func (f *FaultInjector) Start(workers func() []*CaptureWorker) {
+	go func() {
+		ticker := time.NewTicker(time.Second)
+		defer ticker.Stop()
+		summary := time.NewTicker(10 * time.Minute)
+		defer summary.Stop()
+
+		for {
+			select {
+			case <-f.stopCh:
+				return
+			case <-summary.C:
+				log.Printf("[Soak] Injected so far: %s", f.Summary())
+			case <-ticker.C:
+				for _, w := range workers() {
+					if w != nil && w.running.Load() {
+						f.rollPeriodic(w)
+					}
+				}
+			}
+		}
+	}()
+}
+
+// Stop ends periodic fault injection.
+func (f *FaultInjector) Stop() {
+	f.stopOnce.Do(func() {
+		close(f.stopCh)
+		log.Printf("[Soak] Stopped. Injected: %s", f.Summary())
+	})
+}
+
+// Summary returns injected fault counts for logging.
+func (f *FaultInjector) Summary() string {
+	return fmt.Sprintf("kills=%d removals=%d corrupt=%d delayed=%d",
+		f.kills.Load(), f.removals.Load(), f.corrupted.Load(), f.delayed.Load())
+}
+
+// chance returns true with probability p.
+func (f *FaultInjector) chance(p float64) bool {
+	if p <= 0 {
+		return false
+	}
+	f.rngMu.Lock()
+	defer f.rngMu.Unlock()
+	return f.rng.Float64() < p
+}
+
+func (f *FaultInjector) intn(n int) int {
+	f.rngMu.Lock()
+	defer f.rngMu.Unlock()
+	return f.rng.Intn(n)
+}
+
+// rollPeriodic decides this second's kill / unplug for one worker.
+func (f *FaultInjector) rollPeriodic(w *CaptureWorker) {
+	id := w.camera.DeviceID
+	if f.chance(f.cfg.DeviceRemovalPerHour / 3600) {
+		f.removedMu.Lock()
+		f.removedUntil[id] = time.Now().Add(f.cfg.RemovalDuration)
+		f.removedMu.Unlock()
+		f.removals.Add(1)
+		log.Printf("[Soak] Camera %s: simulating device removal for %v", id, f.cfg.RemovalDuration)
+		w.killFFmpeg()
+		return
+	}
+	if f.chance(f.cfg.KillFFmpegPerHour / 3600) {
+		f.kills.Add(1)
+		log.Printf("[Soak] Camera %s: killing FFmpeg", id)
+		w.killFFmpeg()
+	}
+}
+
+// deviceRemoved reports whether a simulated unplug is in effect.
+func (f *FaultInjector) deviceRemoved(deviceID string) bool {
+	f.removedMu.Lock()
+	defer f.removedMu.Unlock()
+	until, ok := f.removedUntil[deviceID]
+	if !ok {
+		return false
+	}
+	if time.Now().After(until) {
+		delete(f.removedUntil, deviceID)
+		log.Printf("[Soak] Camera %s: simulated device re-attached", deviceID)
+		return false
+	}
+	return true
+}
+
+// maybeCorrupt returns jpegData, damaged with probability CorruptJPEGRate.
+// Damage is either a truncation or a run of flipped bytes after the
+// header, so both decode errors and garbled-but-decodable frames occur.
+func (f *FaultInjector) maybeCorrupt(jpegData []byte) []byte {
+	if len(jpegData) < 64 || !f.chance(f.cfg.CorruptJPEGRate) {
+		return jpegData
+	}
+	f.corrupted.Add(1)
+	if f.intn(2) == 0 {
+		return jpegData[:len(jpegData)/2]
+	}
+	damaged := make([]byte, len(jpegData))
+	copy(damaged, jpegData)
+	start := len(damaged)/4 + f.intn(len(damaged)/2)
+	for i := start; i < start+16 && i < len(damaged)-2; i++ {
+		damaged[i] ^= 0xA5
+	}
+	return damaged
+}
+
+// frameDelay returns how long to stall before publishing this frame.
+func (f *FaultInjector) frameDelay() time.Duration {
+	if f.cfg.DelayFrameMax <= 0 || !f.chance(f.cfg.DelayFrameRate) {
+		return 0
+	}
+	f.delayed.Add(1)
+	return time.Duration(1 + f.intn(int(f.cfg.DelayFrameMax)))
+}
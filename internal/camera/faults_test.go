@@ -0,0 +1,70 @@
+package camera
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestFaultInjector_MaybeCorrupt(t *testing.T) {
+	data := bytes.Repeat([]byte{0x11}, 256)
+
+	off := NewFaultInjector(FaultConfig{Seed: 1})
+	if got := off.maybeCorrupt(data); !bytes.Equal(got, data) {
+		t.Error("rate 0 should never corrupt")
+	}
+
+	on := NewFaultInjector(FaultConfig{CorruptJPEGRate: 1, Seed: 1})
+	for i := 0; i < 10; i++ {
+		got := on.maybeCorrupt(data)
+		if bytes.Equal(got, data) {
+			t.Fatal("rate 1 should always corrupt")
+		}
+	}
+	if !bytes.Equal(data, bytes.Repeat([]byte{0x11}, 256)) {
+		t.Error("maybeCorrupt must not modify the input slice")
+	}
+	if on.corrupted.Load() != 10 {
+		t.Errorf("corrupted = %d, want 10", on.corrupted.Load())
+	}
+}
+
+func TestFaultInjector_FrameDelay(t *testing.T) {
+	f := NewFaultInjector(FaultConfig{DelayFrameRate: 1, DelayFrameMax: 50 * time.Millisecond, Seed: 1})
+	for i := 0; i < 100; i++ {
+		d := f.frameDelay()
+		if d <= 0 || d > 50*time.Millisecond {
+			t.Fatalf("frameDelay() = %v, want in (0, 50ms]", d)
+		}
+	}
+
+	none := NewFaultInjector(FaultConfig{DelayFrameRate: 1, Seed: 1})
+	if d := none.frameDelay(); d != 0 {
+		t.Errorf("frameDelay() without DelayFrameMax = %v, want 0", d)
+	}
+}
+
+func TestFaultInjector_DeviceRemoval(t *testing.T) {
+	f := NewFaultInjector(FaultConfig{
+		DeviceRemovalPerHour: 3600 * 2, // Certain on every roll
+		RemovalDuration:      30 * time.Millisecond,
+		Seed:                 1,
+	})
+	w := NewCaptureWorkerWithBuffer(Camera{DeviceID: "video0"}, NewFrameBuffer(), DefaultSettings())
+
+	f.rollPeriodic(w)
+	if !f.deviceRemoved("video0") {
+		t.Fatal("device should be removed after a certain removal roll")
+	}
+	if f.deviceRemoved("video2") {
+		t.Error("other devices must not be affected")
+	}
+
+	time.Sleep(40 * time.Millisecond)
+	if f.deviceRemoved("video0") {
+		t.Error("device should re-attach after RemovalDuration")
+	}
+	if f.removals.Load() != 1 {
+		t.Errorf("removals = %d, want 1", f.removals.Load())
+	}
+}
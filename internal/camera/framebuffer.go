@@ -7,75 +7,163 @@ import (
 	"time"
 )
 
-// FrameBuffer provides mutex-protected access to the latest frame.
+// FrameBuffer holds the latest frame for lock-free reads.
 // Capture writes at max speed, UI reads when ready.
-// Uses double-buffering with a RWMutex to safely swap read/write slots.
+//
+// Each Write publishes a new immutable slot (frame, capture time,
+// sequence number) with a single atomic pointer store; readers load the
+// pointer and get a complete slot that no later Write touches. The
+// writer never reuses a slot the UI might still hold - the old one is
+// simply dropped and collected once no reader has it - so this gives
+// triple buffering's guarantee (the reader always sees a whole, untouched
+// frame, the writer never waits) without a fixed set of slots to rotate.
 type FrameBuffer struct {
-	// Double-buffering with atomic swap
-	frames     [2]image.Image
-	writeIndex atomic.Int32
-	readIndex  atomic.Int32
+	latest atomic.Pointer[stampedFrame] // nil until the first Write
 
 	// Frame metadata
 	frameCount   atomic.Uint64
-	lastFrameAt  atomic.Int64 // Unix nano timestamp
+	lastFrameAt  atomic.Int64 // Monotonic nanos since processStart (see clock.go)
 	droppedCount atomic.Uint64
 
+	// Recent frames for soft-sync (nil unless KeepHistory; guarded by mu)
+	history     []stampedFrame
+	historyNext int
+	keeping     atomic.Bool // len(history) > 0, so Write skips mu without history
+
 	// Stats for performance monitoring
 	captureStartTime time.Time
 	mu               sync.RWMutex
 }
 
+// stampedFrame is a frame with its capture time and sequence number.
+// Published slots are never modified.
+type stampedFrame struct {
+	frame image.Image
+	at    int64 // Monotonic nanos since processStart
+	seq   uint64
+	trace *FrameTrace // Sampled frames only (see trace.go)
+}
+
 // NewFrameBuffer creates a new frame buffer
 func NewFrameBuffer() *FrameBuffer {
-	fb := &FrameBuffer{
+	return &FrameBuffer{
 		captureStartTime: time.Now(),
 	}
-	fb.writeIndex.Store(0)
-	fb.readIndex.Store(1)
-	return fb
 }
 
-// Write stores a new frame (called by capture goroutine)
-// This is non-blocking and always succeeds
+// Write stores a new frame (called by the capture goroutine, one writer
+// per buffer). This is non-blocking for readers and always succeeds.
 func (fb *FrameBuffer) Write(frame image.Image) {
+	fb.write(frame, nil)
+}
+
+// WriteTraced is Write for a sampled frame: it fills in trace's sequence
+// number and write time and publishes trace with the frame (see Trace).
+func (fb *FrameBuffer) WriteTraced(frame image.Image, trace *FrameTrace) {
+	fb.write(frame, trace)
+}
+
+func (fb *FrameBuffer) write(frame image.Image, trace *FrameTrace) {
+	now := monoNow()
+	slot := &stampedFrame{frame: frame, at: now, seq: fb.frameCount.Add(1), trace: trace}
+
+	if fb.keeping.Load() {
+		fb.mu.Lock()
+		if len(fb.history) > 0 {
+			fb.history[fb.historyNext] = *slot
+			fb.historyNext = (fb.historyNext + 1) % len(fb.history)
+		}
+		fb.mu.Unlock()
+	}
+
+	// The trace is complete before readers can reach it
+	if trace != nil {
+		trace.Seq = slot.seq
+		trace.Write = time.Duration(monoNow() - now)
+		trace.Written = time.Now()
+	}
+	fb.latest.Store(slot)
+	fb.lastFrameAt.Store(now)
+}
+
+// KeepHistory makes the buffer retain the last n frames with their
+// capture times for ReadAt (0 = latest frame only).
+func (fb *FrameBuffer) KeepHistory(n int) {
 	fb.mu.Lock()
-	// Write to current write slot
-	writeIdx := fb.writeIndex.Load()
-	fb.frames[writeIdx] = frame
+	defer fb.mu.Unlock()
+	if n <= 0 {
+		fb.history = nil
+	} else {
+		fb.history = make([]stampedFrame, n)
+	}
+	fb.historyNext = 0
+	fb.keeping.Store(n > 0)
+}
 
-	// Atomic swap - make written frame available for reading
-	fb.writeIndex.Store(1 - writeIdx)
-	fb.readIndex.Store(writeIdx)
-	fb.mu.Unlock()
+// ReadAt returns the newest retained frame captured at or before t, with
+// its capture time and sequence number (comparable with ReadIfNew's
+// count). If every retained frame is newer than t it returns the oldest;
+// without history it returns the latest frame.
+func (fb *FrameBuffer) ReadAt(t time.Time) (image.Image, time.Time, uint64) {
+	fb.mu.RLock()
+	defer fb.mu.RUnlock()
+	if len(fb.history) == 0 {
+		slot := fb.latest.Load()
+		if slot == nil {
+			return nil, time.Time{}, 0
+		}
+		return slot.frame, monoToTime(slot.at), slot.seq
+	}
 
-	fb.frameCount.Add(1)
-	fb.lastFrameAt.Store(time.Now().UnixNano())
+	var best, oldest *stampedFrame
+	for i := range fb.history {
+		f := &fb.history[i]
+		if f.frame == nil {
+			continue
+		}
+		if oldest == nil || f.seq < oldest.seq {
+			oldest = f
+		}
+		if !monoToTime(f.at).After(t) && (best == nil || f.seq > best.seq) {
+			best = f
+		}
+	}
+	if best == nil {
+		best = oldest
+	}
+	if best == nil {
+		return nil, time.Time{}, 0
+	}
+	return best.frame, monoToTime(best.at), best.seq
 }
 
 // Read returns the latest frame (called by UI goroutine)
 // Returns nil if no frame available yet
 func (fb *FrameBuffer) Read() image.Image {
-	fb.mu.RLock()
-	readIdx := fb.readIndex.Load()
-	frame := fb.frames[readIdx]
-	fb.mu.RUnlock()
-	return frame
+	if slot := fb.latest.Load(); slot != nil {
+		return slot.frame
+	}
+	return nil
 }
 
 // ReadIfNew returns the frame only if it's newer than lastRead
-// Returns nil if no new frame, avoiding unnecessary UI refreshes
+// Returns nil if no new frame, avoiding unnecessary UI refreshes.
+// The returned count is the frame's own sequence number.
 func (fb *FrameBuffer) ReadIfNew(lastRead uint64) (image.Image, uint64, bool) {
-	currentCount := fb.frameCount.Load()
-	if currentCount <= lastRead {
+	slot := fb.latest.Load()
+	if slot == nil || slot.seq <= lastRead {
 		return nil, lastRead, false
 	}
+	return slot.frame, slot.seq, true
+}
 
-	fb.mu.RLock()
-	readIdx := fb.readIndex.Load()
-	frame := fb.frames[readIdx]
-	fb.mu.RUnlock()
-	return frame, currentCount, true
+// Trace returns the trace of frame seq if it was sampled and is still
+// the latest frame, else nil.
+func (fb *FrameBuffer) Trace(seq uint64) *FrameTrace {
+	if slot := fb.latest.Load(); slot != nil && slot.seq == seq {
+		return slot.trace
+	}
+	return nil
 }
 
 // GetFrameCount returns total frames captured
@@ -83,13 +171,11 @@ func (fb *FrameBuffer) GetFrameCount() uint64 {
 	return fb.frameCount.Load()
 }
 
-// GetLastFrameTime returns when the last frame was captured
+// GetLastFrameTime returns when the last frame was captured.
+// The result carries a monotonic reading, so time.Since is safe
+// across wall-clock steps.
 func (fb *FrameBuffer) GetLastFrameTime() time.Time {
-	nanos := fb.lastFrameAt.Load()
-	if nanos == 0 {
-		return time.Time{}
-	}
-	return time.Unix(0, nanos)
+	return monoToTime(fb.lastFrameAt.Load())
 }
 
 // GetCaptureStats returns capture performance stats
@@ -127,8 +213,11 @@ func (fb *FrameBuffer) GetActualFPS() float64 {
 // Reset clears the buffer and stats
 func (fb *FrameBuffer) Reset() {
 	fb.mu.Lock()
-	fb.frames[0] = nil
-	fb.frames[1] = nil
+	fb.latest.Store(nil)
+	for i := range fb.history {
+		fb.history[i] = stampedFrame{}
+	}
+	fb.historyNext = 0
 	fb.frameCount.Store(0)
 	fb.droppedCount.Store(0)
 	fb.lastFrameAt.Store(0)
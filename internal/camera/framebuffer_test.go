@@ -1,8 +1,10 @@
 package camera
 
 import (
+	"fmt"
 	"image"
 	"image/color"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -206,3 +208,170 @@ func TestFrameBuffer_ConcurrentSafety(t *testing.T) {
 		t.Errorf("frame count after concurrent test = %d, want 1000", fb.GetFrameCount())
 	}
 }
+
+func TestFrameBuffer_ReadIfNewMatchesFrame(t *testing.T) {
+	fb := NewFrameBuffer()
+	const writes = 2000
+	done := make(chan struct{})
+
+	// Each frame carries its sequence number in its pixel, so a reader
+	// that got a frame from one Write and a count from another notices.
+	go func() {
+		defer close(done)
+		for i := 1; i <= writes; i++ {
+			img := image.NewGray16(image.Rect(0, 0, 1, 1))
+			img.SetGray16(0, 0, color.Gray16{Y: uint16(i)})
+			fb.Write(img)
+		}
+	}()
+
+	var lastRead uint64
+	for {
+		frame, seq, ok := fb.ReadIfNew(lastRead)
+		if ok {
+			if seq <= lastRead {
+				t.Fatalf("ReadIfNew returned seq %d after %d", seq, lastRead)
+			}
+			if got := uint64(frame.(*image.Gray16).Gray16At(0, 0).Y); got != seq {
+				t.Fatalf("ReadIfNew returned frame %d with seq %d", got, seq)
+			}
+			lastRead = seq
+		}
+		select {
+		case <-done:
+			if lastRead != writes {
+				if _, seq, ok := fb.ReadIfNew(lastRead); !ok || seq != writes {
+					t.Errorf("final ReadIfNew seq = %d, want %d", seq, writes)
+				}
+			}
+			return
+		default:
+		}
+	}
+}
+
+func TestFrameBuffer_LastFrameTimeIsMonotonic(t *testing.T) {
+	fb := NewFrameBuffer()
+	fb.Write(makeTestImage(2, 2, color.White))
+
+	// Timestamps must carry a monotonic reading so ages survive
+	// wall-clock steps; a wall-only time's String() has no "m=" suffix.
+	last := fb.GetLastFrameTime()
+	if !strings.Contains(last.String(), "m=") {
+		t.Errorf("GetLastFrameTime() = %v, want a monotonic reading", last)
+	}
+	if age := time.Since(last); age < 0 || age > time.Second {
+		t.Errorf("frame age = %v, want within [0, 1s]", age)
+	}
+}
+
+func TestFrameBuffer_ReadAt(t *testing.T) {
+	fb := NewFrameBuffer()
+	fb.KeepHistory(3)
+	var stamps []time.Time
+	for i := 0; i < 4; i++ {
+		fb.Write(makeTestImage(1, 1, color.Gray{Y: uint8(i)}))
+		stamps = append(stamps, fb.GetLastFrameTime())
+		time.Sleep(2 * time.Millisecond)
+	}
+
+	// Frame 0 fell out of the ring; frame 1 is the oldest retained.
+	if _, at, seq := fb.ReadAt(stamps[0]); seq != 2 || !at.Equal(stamps[1]) {
+		t.Errorf("ReadAt(before history) = seq %d at %v, want the oldest (seq 2)", seq, at)
+	}
+	if _, _, seq := fb.ReadAt(stamps[2].Add(time.Millisecond)); seq != 3 {
+		t.Errorf("ReadAt(between frames) = seq %d, want 3", seq)
+	}
+	if _, _, seq := fb.ReadAt(time.Now()); seq != 4 {
+		t.Errorf("ReadAt(now) = seq %d, want 4", seq)
+	}
+
+	fb.Reset()
+	if frame, _, seq := fb.ReadAt(time.Now()); frame != nil || seq != 0 {
+		t.Errorf("ReadAt after Reset = %v, %d; want nil, 0", frame, seq)
+	}
+}
+
+func TestFrameBuffer_ReadAtWithoutHistory(t *testing.T) {
+	fb := NewFrameBuffer()
+	fb.Write(makeTestImage(1, 1, color.White))
+	fb.Write(makeTestImage(1, 1, color.Black))
+	if frame, _, seq := fb.ReadAt(time.Time{}); frame == nil || seq != 2 {
+		t.Errorf("ReadAt without history = %v, %d; want the latest frame, 2", frame, seq)
+	}
+}
+
+func TestFrameBuffer_WriteWithoutHistorySkipsLock(t *testing.T) {
+	fb := NewFrameBuffer()
+	fb.KeepHistory(3)
+	fb.KeepHistory(0)
+
+	fb.mu.Lock() // A stats reader or Reset holding the lock
+	done := make(chan struct{})
+	go func() {
+		fb.Write(makeTestImage(1, 1, color.White))
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Error("Write without history waited for mu")
+	}
+	fb.mu.Unlock()
+	<-done
+	if _, _, seq := fb.ReadAt(time.Now()); seq != 1 {
+		t.Errorf("ReadAt after history off = seq %d, want 1", seq)
+	}
+}
+
+func TestFrameBuffer_WriteTraced(t *testing.T) {
+	fb := NewFrameBuffer()
+	img := makeTestImage(4, 4, color.White)
+	fb.Write(img)
+
+	trace := &FrameTrace{Camera: "video0", Decode: time.Millisecond}
+	fb.WriteTraced(img, trace)
+	_, seq, ok := fb.ReadIfNew(1)
+	if !ok || seq != 2 {
+		t.Fatalf("ReadIfNew = %d %v, want frame 2", seq, ok)
+	}
+	if got := fb.Trace(seq); got != trace || got.Seq != 2 || got.Written.IsZero() {
+		t.Errorf("Trace(2) = %+v, want the trace with seq 2 and a write time", got)
+	}
+	if fb.Trace(1) != nil {
+		t.Error("Trace(1) should be nil: frame 1 was not sampled and is no longer the latest")
+	}
+
+	fb.Write(img)
+	if fb.Trace(2) != nil {
+		t.Error("Trace(2) should be nil once a newer frame replaced it")
+	}
+}
+
+// BenchmarkFrameBufferWrite is the capture side publishing a decoded
+// 640x480 frame, alone and into a soft-sync history.
+func BenchmarkFrameBufferWrite(b *testing.B) {
+	img := image.NewYCbCr(image.Rect(0, 0, 640, 480), image.YCbCrSubsampleRatio420)
+	for _, history := range []int{0, 8} {
+		b.Run(fmt.Sprintf("history=%d", history), func(b *testing.B) {
+			fb := NewFrameBuffer()
+			fb.KeepHistory(history)
+			for i := 0; i < b.N; i++ {
+				fb.Write(img)
+			}
+		})
+	}
+}
+
+func TestMonoToTime_RoundTrip(t *testing.T) {
+	if !monoToTime(0).IsZero() {
+		t.Error("monoToTime(0) should be the zero Time")
+	}
+	n := monoNow()
+	if n <= 0 {
+		t.Fatalf("monoNow() = %d, want > 0", n)
+	}
+	if got := monoToTime(n).Sub(processStart); got != time.Duration(n) {
+		t.Errorf("monoToTime(%d) - processStart = %v, want %v", n, got, time.Duration(n))
+	}
+}
@@ -0,0 +1,170 @@
+//go:build integration
+
+package camera_test
+
+import (
+	"camera-dashboard-go/internal/camera"
+	"camera-dashboard-go/internal/testsupport"
+	"testing"
+	"time"
+)
+
+// End-to-end capture with fake and v4l2loopback cameras:
+//
+//   go test -race -tags integration ./internal/camera/ ./internal/ui/
+
+func fakeSettings(backend camera.Backend) camera.Settings {
+	s := camera.DefaultSettings()
+	s.Width, s.Height, s.FPS = 64, 48, 20
+	s.Backend = backend
+	s.ReconnectCooldown = time.Second
+	return s
+}
+
+// waitFor polls cond until it holds or timeout passes.
+func waitFor(t *testing.T, timeout time.Duration, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(timeout)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out after %v waiting for %s", timeout, what)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+func TestIntegration_FakeDiscoveryAndCapture(t *testing.T) {
+	backend := testsupport.NewFakeBackend()
+	backend.Add("fake0", "USB Fake Front")
+	backend.Add("fake1", "USB Fake Rear")
+	backend.Add("fake2", "USB Fake Spare")
+	s := fakeSettings(backend)
+	s.MaxCameras = 2
+
+	m := camera.NewManagerWithSettings(s, true)
+	if err := m.Initialize(); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer m.Stop()
+
+	cams := m.GetCameras()
+	if len(cams) != 2 || cams[0].DeviceID != "fake0" || cams[1].DeviceID != "fake1" {
+		t.Fatalf("cameras = %+v, want fake0 and fake1 (limit 2)", cams)
+	}
+	for _, cam := range cams {
+		buffer := m.GetFrameBuffer(cam.DeviceID)
+		waitFor(t, 3*time.Second, cam.DeviceID+" frames", func() bool { return buffer.GetFrameCount() >= 5 })
+		w := m.GetWorker(cam.DeviceID)
+		if w.SignalLost() {
+			t.Errorf("%s: signal lost while streaming", cam.DeviceID)
+		}
+		if got := w.StreamHealth().Format; got != "backend" {
+			t.Errorf("%s: format = %q, want backend", cam.DeviceID, got)
+		}
+	}
+}
+
+func TestIntegration_FakeUnplugAndReconnect(t *testing.T) {
+	backend := testsupport.NewFakeBackend()
+	dev := backend.Add("fake0", "USB Fake Front")
+	m := camera.NewManagerWithSettings(fakeSettings(backend), true)
+	if err := m.Initialize(); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer m.Stop()
+	w := m.GetWorker("fake0")
+	buffer := m.GetFrameBuffer("fake0")
+	waitFor(t, 3*time.Second, "first frames", func() bool { return buffer.GetFrameCount() >= 3 })
+
+	dev.Unplug()
+	waitFor(t, 3*time.Second, "signal lost after unplug", w.SignalLost)
+
+	dev.Plug()
+	waitFor(t, 10*time.Second, "reconnect after replug", func() bool { return !w.SignalLost() })
+	if dev.Opens() < 2 {
+		t.Errorf("stream opened %d times, want a reopen", dev.Opens())
+	}
+}
+
+func TestIntegration_FakeStallKeepsWorkerAlive(t *testing.T) {
+	backend := testsupport.NewFakeBackend()
+	dev := backend.Add("fake0", "USB Fake Front")
+	m := camera.NewManagerWithSettings(fakeSettings(backend), true)
+	if err := m.Initialize(); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer m.Stop()
+	buffer := m.GetFrameBuffer("fake0")
+	waitFor(t, 3*time.Second, "first frames", func() bool { return buffer.GetFrameCount() >= 3 })
+
+	// A stalled stream times out frame reads but stays open
+	dev.Stall()
+	time.Sleep(300 * time.Millisecond)
+	stalledAt := buffer.GetFrameCount()
+	time.Sleep(time.Second)
+	if n := buffer.GetFrameCount(); n != stalledAt {
+		t.Errorf("%d frames while stalled", n-stalledAt)
+	}
+
+	// Restarting the worker (what stale recovery does) reopens the stream
+	if err := m.RestartCamera("fake0"); err != nil {
+		t.Fatal(err)
+	}
+	dev.Resume()
+	buffer = m.GetFrameBuffer("fake0")
+	before := buffer.GetFrameCount()
+	waitFor(t, 3*time.Second, "frames after resume", func() bool { return buffer.GetFrameCount() >= before+3 })
+	if dev.Opens() != 2 {
+		t.Errorf("stream opened %d times, want 2", dev.Opens())
+	}
+}
+
+func TestIntegration_LoopbackDiscoveryAndCapture(t *testing.T) {
+	if err := testsupport.LoopbackAvailable(); err != nil {
+		t.Skip(err)
+	}
+	lb, err := testsupport.StartLoopback([]int{40}, 320, 240, 15)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lb.Close()
+
+	s := camera.DefaultSettings()
+	s.Width, s.Height, s.FPS, s.Format = 320, 240, 15, "yuyv"
+	s.ReconnectCooldown = time.Second
+	m := camera.NewManagerWithSettings(s, true)
+	if err := m.Initialize(); err != nil {
+		t.Fatal(err)
+	}
+	var found *camera.Camera
+	for _, cam := range m.GetCameras() {
+		if cam.DevicePath == lb.Devices[0] {
+			cam := cam
+			found = &cam
+		}
+	}
+	if found == nil {
+		t.Fatalf("%s not discovered in %+v", lb.Devices[0], m.GetCameras())
+	}
+	if err := m.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer m.Stop()
+	buffer := m.GetFrameBuffer(found.DeviceID)
+	waitFor(t, 10*time.Second, "loopback frames", func() bool { return buffer.GetFrameCount() >= 5 })
+	if m.GetWorker(found.DeviceID).SignalLost() {
+		t.Error("signal lost while the loopback device is fed")
+	}
+
+	lb.Stall(lb.Devices[0])
+	waitFor(t, 10*time.Second, "signal lost after the feeder stopped", m.GetWorker(found.DeviceID).SignalLost)
+}
@@ -12,9 +12,15 @@ type Manager struct {
 	cameras      []Camera
 	workers      []*CaptureWorker
 	frameBuffers map[string]*FrameBuffer // Buffer mode for decoupled capture/render
-	settings     Settings                // Camera capture settings from config
+	settings     Settings                // Camera capture settings from config (per camera: Settings.ForCamera)
 	running      bool
 	mutex        sync.RWMutex
+
+	// Adaptive resolution cap (0x0 = none), also applied to new workers
+	resCapW, resCapH int
+	resizeMu         sync.Mutex // Serializes SetResolution
+
+	bandwidth *BandwidthScheduler // nil unless Settings.BandwidthBudget > 0
 }
 
 // NewManagerWithSettings creates a manager with explicit settings from config
@@ -59,15 +65,35 @@ func (m *Manager) Initialize() error {
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
 
-	log.Println("[Manager] Discovering cameras...")
-	// Discover cameras
-	cameras, err := DiscoverCamerasWithSettings(m.settings)
-	if err != nil {
-		log.Printf("[Manager] Camera discovery failed: %v", err)
-		return err
+	var cameras []Camera
+	switch {
+	case len(m.settings.Simulate) > 0:
+		cameras = simulatedCameras(m.settings)
+	case m.settings.Backend != nil:
+		var err error
+		if cameras, err = m.settings.Backend.Discover(m.settings); err != nil {
+			log.Printf("[Manager] Camera discovery failed: %v", err)
+			return err
+		}
+	default:
+		log.Println("[Manager] Discovering cameras...")
+		var err error
+		cameras, err = DiscoverCamerasWithSettings(m.settings)
+		if err != nil {
+			log.Printf("[Manager] Camera discovery failed: %v", err)
+			return err
+		}
+		cameras = withExtraCameras(append(networkCameras(m.settings), discoverCSICameras(m.settings)...), cameras, m.settings)
 	}
 
 	log.Printf("[Manager] Found %d cameras", len(cameras))
+	if len(m.settings.Controls) > 0 {
+		for _, camera := range cameras {
+			if camera.HasDeviceNode() {
+				NewControls(camera.DevicePath).Apply(m.settings.Controls)
+			}
+		}
+	}
 	m.cameras = cameras
 	m.workers = make([]*CaptureWorker, len(cameras))
 	m.frameBuffers = make(map[string]*FrameBuffer)
@@ -75,10 +101,14 @@ func (m *Manager) Initialize() error {
 	// Create capture workers for each camera
 	for i, camera := range cameras {
 		log.Printf("[Manager] Creating worker for camera %s (%s)",
-			camera.DeviceID, camera.DevicePath)
+			camera.DeviceID, RedactSource(camera.DevicePath))
 
 		buffer := NewFrameBuffer()
-		worker := NewCaptureWorkerWithBuffer(camera, buffer, m.settings)
+		buffer.KeepHistory(m.settings.SyncHistory)
+		worker := NewCaptureWorkerWithBuffer(camera, buffer, m.settings.ForCamera(camera))
+		if m.resCapW > 0 {
+			worker.SetResolution(m.resCapW, m.resCapH) // Not running yet: no restart
+		}
 		m.frameBuffers[camera.DeviceID] = buffer
 		m.workers[i] = worker
 	}
@@ -124,6 +154,10 @@ func (m *Manager) Start() error {
 		log.Printf("[Manager] Started camera %d/%d", i+1, len(m.workers))
 	}
 
+	if m.settings.BandwidthBudget > 0 && m.bandwidth == nil {
+		m.bandwidth = NewBandwidthScheduler(m, m.settings)
+		m.bandwidth.Start()
+	}
 	m.mutex.Unlock()
 	return nil
 }
@@ -135,6 +169,14 @@ func (m *Manager) Stop() {
 
 // stopInternal stops all workers (with its own locking)
 func (m *Manager) stopInternal() {
+	m.mutex.Lock()
+	bandwidth := m.bandwidth
+	m.bandwidth = nil
+	m.mutex.Unlock()
+	if bandwidth != nil {
+		bandwidth.Stop() // Outside the lock: a tick may be reading the workers
+	}
+
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
 
@@ -173,14 +215,66 @@ func (m *Manager) GetFrameBuffer(cameraID string) *FrameBuffer {
 	return nil
 }
 
-// SetFPS sets the FPS for all capture workers
+// SetFPS sets the FPS of all capture workers. fps is relative to the
+// global Settings.FPS: a camera with its own FPS (Settings.PerCamera) is
+// scaled by the same factor, so at the global rate every camera runs at
+// its own and a [camera.<name>] fps above the global one isn't capped
+// back to it.
 func (m *Manager) SetFPS(fps int) {
 	m.mutex.RLock()
 	defer m.mutex.RUnlock()
 
 	for _, worker := range m.workers {
 		if worker != nil {
-			worker.SetFPS(fps)
+			worker.SetFPS(scaleFPS(fps, worker.settings.FPS, m.settings.FPS))
+		}
+	}
+}
+
+// scaleFPS scales fps, a rate relative to base, to a camera whose own
+// rate is own.
+func scaleFPS(fps, own, base int) int {
+	if own <= 0 || base <= 0 || own == base {
+		return fps
+	}
+	return (fps*own + base/2) / base
+}
+
+// SetReconnectCooldown changes the longest wait between reconnect
+// attempts for every worker, and for workers created later.
+func (m *Manager) SetReconnectCooldown(d time.Duration) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.settings.ReconnectCooldown = d
+	for _, worker := range m.workers {
+		if worker != nil {
+			worker.SetReconnectCooldown(d)
+		}
+	}
+}
+
+// SetResolution caps every worker's capture resolution at width x height
+// (0x0 restores each camera's own), restarting the workers whose size
+// changes one at a time so USB bandwidth isn't hit all at once.
+func (m *Manager) SetResolution(width, height int) {
+	m.resizeMu.Lock()
+	defer m.resizeMu.Unlock()
+
+	m.mutex.Lock()
+	m.resCapW, m.resCapH = width, height
+	m.mutex.Unlock()
+
+	for i, worker := range m.GetWorkers() {
+		if worker == nil {
+			continue
+		}
+		oldW, oldH := worker.GetResolution()
+		if err := worker.SetResolution(width, height); err != nil {
+			log.Printf("[Manager] Camera %d: resolution change failed: %v", i, err)
+		}
+		if newW, newH := worker.GetResolution(); newW != oldW || newH != oldH {
+			time.Sleep(500 * time.Millisecond) // Stagger restarts (see Start)
 		}
 	}
 }
@@ -198,6 +292,16 @@ func (m *Manager) GetWorker(cameraID string) *CaptureWorker {
 	return nil
 }
 
+// GetWorkers returns a snapshot of the current capture workers
+func (m *Manager) GetWorkers() []*CaptureWorker {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	workers := make([]*CaptureWorker, len(m.workers))
+	copy(workers, m.workers)
+	return workers
+}
+
 // RestartCamera restarts only the specified camera's capture worker
 // Other cameras continue running uninterrupted
 func (m *Manager) RestartCamera(cameraID string) error {
@@ -0,0 +1,281 @@
+package camera
+
+import "bytes"
+
+// =============================================================================
+// MJPEG stream parser
+// =============================================================================
+// FFmpeg and rpicam-vid write MJPEG as back-to-back JPEG files. Scanning
+// for SOI (FFD8) and EOI (FFD9) bytes is not enough to split them: EXIF
+// and other APPn segments can carry a complete thumbnail JPEG with its own
+// SOI/EOI, and some cameras leave out the EOI altogether. mjpegParser walks
+// the JPEG segment structure instead:
+//
+//   - Marker segments are skipped by their length field, so nothing in a
+//     payload is mistaken for a marker.
+//   - In entropy-coded scan data, FF00 (stuffed byte), FFD0-FFD7 (restart
+//     markers) and FFFF (fill) are data; any other FFxx ends the scan.
+//   - Many UVC cameras leave out the Huffman tables (DHT) and rely on the
+//     standard ones from the MJPEG format (JPEG spec section K.3); the Go
+//     decoder can't decode such a frame, so the standard tables are
+//     inserted before the first SOS of a frame that has none.
+//   - An SOI where the next marker should be ends a frame that has scan
+//     data but no EOI; the frame is returned with an EOI appended (the Go
+//     decoder needs it). The new SOI starts the next frame.
+//   - Anything else that can't be JPEG (a non-marker byte where a marker
+//     should be, a bad length, a frame over maxMJPEGFrame) drops only the
+//     current frame: parsing resyncs at the next SOI and counts a resync.
+//
+// The parser keeps its place between reads, so each byte is looked at
+// once however the pipe splits the stream.
+// =============================================================================
+
+const maxMJPEGFrame = 4 << 20 // Larger than any sane frame; bigger means lost sync
+
+// JPEG markers (the byte after 0xFF)
+const (
+	jpegSOI = 0xD8
+	jpegEOI = 0xD9
+	jpegSOS = 0xDA
+	jpegDHT = 0xC4
+	jpegRST = 0xD0 // RST0; RST0-RST7 are D0-D7
+	jpegTEM = 0x01
+)
+
+// mjpegParser splits an MJPEG byte stream into JPEG frames.
+type mjpegParser struct {
+	buf     []byte // buf[0:2] is the frame's SOI while inFrame
+	pos     int    // Next byte to parse within the frame
+	inFrame bool
+	entropy bool // pos is inside entropy-coded scan data
+	scanned bool // The frame has scan data (a missing EOI can be repaired)
+	hasDHT  bool // The frame defined Huffman tables before its first scan
+	dhtAt   int  // Where to insert the standard tables, -1 = nowhere
+	skipped int  // Bytes dropped since the last frame start
+	resyncs int  // Corrupt stretches skipped, since the last takeResyncs
+}
+
+// newMJPEGParser creates a parser with room for a typical frame.
+func newMJPEGParser() *mjpegParser {
+	return &mjpegParser{buf: make([]byte, 0, 65536)}
+}
+
+// feed appends stream bytes.
+func (p *mjpegParser) feed(data []byte) {
+	p.buf = append(p.buf, data...)
+}
+
+// takeResyncs returns and clears the number of resyncs.
+func (p *mjpegParser) takeResyncs() int {
+	n := p.resyncs
+	p.resyncs = 0
+	return n
+}
+
+// next returns the next complete frame (a copy), or nil when more data is
+// needed.
+func (p *mjpegParser) next() []byte {
+	for {
+		if !p.inFrame && !p.findSOI() {
+			return nil
+		}
+		frame, corrupt := p.parseFrame()
+		if corrupt {
+			// Drop this frame's SOI; the search counts the rest as skipped
+			p.consume(1)
+			p.skipped++
+			p.inFrame = false
+			continue
+		}
+		return frame
+	}
+}
+
+// findSOI drops bytes up to the next SOI and starts a frame there. It
+// returns false when the buffer has none yet.
+func (p *mjpegParser) findSOI() bool {
+	for i := 0; i+1 < len(p.buf); i++ {
+		if p.buf[i] == 0xFF && p.buf[i+1] == jpegSOI {
+			p.skipped += i
+			p.consume(i)
+			if p.skipped > 0 {
+				p.resyncs++
+				p.skipped = 0
+			}
+			p.inFrame, p.pos, p.entropy, p.scanned = true, 2, false, false
+			p.hasDHT, p.dhtAt = false, -1
+			return true
+		}
+	}
+	// Keep a trailing 0xFF, it may be the first half of an SOI
+	keep := 0
+	if n := len(p.buf); n > 0 && p.buf[n-1] == 0xFF {
+		keep = 1
+	}
+	p.skipped += len(p.buf) - keep
+	p.consume(len(p.buf) - keep)
+	return false
+}
+
+// parseFrame continues the current frame. It returns the frame once
+// complete, nil when more data is needed, or corrupt.
+func (p *mjpegParser) parseFrame() (frame []byte, corrupt bool) {
+	for {
+		if p.pos > maxMJPEGFrame {
+			return nil, true
+		}
+		if p.entropy {
+			for p.entropy && p.pos+1 < len(p.buf) {
+				if p.buf[p.pos] != 0xFF {
+					i := bytes.IndexByte(p.buf[p.pos:], 0xFF)
+					if i < 0 {
+						p.pos = len(p.buf)
+						break
+					}
+					p.pos += i
+					continue
+				}
+				switch m := p.buf[p.pos+1]; {
+				case m == 0x00, m >= jpegRST && m <= jpegRST+7:
+					p.pos += 2 // Stuffed 0xFF or restart marker
+				case m == 0xFF:
+					p.pos++ // Fill byte
+				default:
+					p.entropy = false // End of scan
+				}
+			}
+			if p.entropy {
+				return nil, false
+			}
+		}
+
+		if p.pos+1 >= len(p.buf) {
+			return nil, false
+		}
+		if p.buf[p.pos] != 0xFF {
+			return nil, true
+		}
+		m := p.buf[p.pos+1]
+		switch {
+		case m == 0xFF:
+			p.pos++ // Fill byte before a marker
+			continue
+		case m == jpegEOI:
+			return p.take(p.pos+2, false), false
+		case m == jpegSOI:
+			if !p.scanned {
+				return nil, true
+			}
+			return p.take(p.pos, true), false // Next frame began without an EOI
+		case m >= jpegRST && m <= jpegRST+7, m == jpegTEM:
+			p.pos += 2 // Markers without a payload
+			continue
+		case m == 0x00:
+			return nil, true
+		}
+
+		if p.pos+4 > len(p.buf) {
+			return nil, false
+		}
+		length := int(p.buf[p.pos+2])<<8 | int(p.buf[p.pos+3])
+		if length < 2 {
+			return nil, true
+		}
+		end := p.pos + 2 + length
+		if end > maxMJPEGFrame {
+			return nil, true
+		}
+		if end > len(p.buf) {
+			return nil, false
+		}
+		switch {
+		case m == jpegDHT:
+			p.hasDHT = true
+		case m == jpegSOS && !p.scanned && !p.hasDHT:
+			p.dhtAt = p.pos
+		}
+		if m == jpegSOS {
+			p.entropy, p.scanned = true, true
+		}
+		p.pos = end
+	}
+}
+
+// take returns a copy of buf[:n] (with the standard Huffman tables when
+// the frame has none, plus an EOI when addEOI) and removes it from the
+// buffer.
+func (p *mjpegParser) take(n int, addEOI bool) []byte {
+	size := n
+	if p.dhtAt >= 0 {
+		size += len(standardDHT)
+	}
+	if addEOI {
+		size += 2
+	}
+	frame := make([]byte, 0, size)
+	if p.dhtAt >= 0 {
+		frame = append(frame, p.buf[:p.dhtAt]...)
+		frame = append(frame, standardDHT...)
+		frame = append(frame, p.buf[p.dhtAt:n]...)
+	} else {
+		frame = append(frame, p.buf[:n]...)
+	}
+	if addEOI {
+		frame = append(frame, 0xFF, jpegEOI)
+	}
+	p.consume(n)
+	p.inFrame = false
+	return frame
+}
+
+// consume drops the first n buffered bytes, keeping the buffer's capacity.
+func (p *mjpegParser) consume(n int) {
+	p.buf = p.buf[:copy(p.buf, p.buf[n:])]
+}
+
+// standardDHT is a DHT segment with the four Huffman tables of JPEG spec
+// section K.3, which MJPEG frames without a DHT are coded with.
+var standardDHT = func() []byte {
+	tables := []struct {
+		class  byte // Table class (0 = DC, 1 = AC) << 4 | table ID
+		counts [16]byte
+		values []byte
+	}{
+		{0x00, [16]byte{0, 1, 5, 1, 1, 1, 1, 1, 1, 0, 0, 0, 0, 0, 0, 0},
+			[]byte{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11}},
+		{0x10, [16]byte{0, 2, 1, 3, 3, 2, 4, 3, 5, 5, 4, 4, 0, 0, 1, 125}, []byte{
+			0x01, 0x02, 0x03, 0x00, 0x04, 0x11, 0x05, 0x12, 0x21, 0x31, 0x41, 0x06, 0x13, 0x51, 0x61, 0x07,
+			0x22, 0x71, 0x14, 0x32, 0x81, 0x91, 0xa1, 0x08, 0x23, 0x42, 0xb1, 0xc1, 0x15, 0x52, 0xd1, 0xf0,
+			0x24, 0x33, 0x62, 0x72, 0x82, 0x09, 0x0a, 0x16, 0x17, 0x18, 0x19, 0x1a, 0x25, 0x26, 0x27, 0x28,
+			0x29, 0x2a, 0x34, 0x35, 0x36, 0x37, 0x38, 0x39, 0x3a, 0x43, 0x44, 0x45, 0x46, 0x47, 0x48, 0x49,
+			0x4a, 0x53, 0x54, 0x55, 0x56, 0x57, 0x58, 0x59, 0x5a, 0x63, 0x64, 0x65, 0x66, 0x67, 0x68, 0x69,
+			0x6a, 0x73, 0x74, 0x75, 0x76, 0x77, 0x78, 0x79, 0x7a, 0x83, 0x84, 0x85, 0x86, 0x87, 0x88, 0x89,
+			0x8a, 0x92, 0x93, 0x94, 0x95, 0x96, 0x97, 0x98, 0x99, 0x9a, 0xa2, 0xa3, 0xa4, 0xa5, 0xa6, 0xa7,
+			0xa8, 0xa9, 0xaa, 0xb2, 0xb3, 0xb4, 0xb5, 0xb6, 0xb7, 0xb8, 0xb9, 0xba, 0xc2, 0xc3, 0xc4, 0xc5,
+			0xc6, 0xc7, 0xc8, 0xc9, 0xca, 0xd2, 0xd3, 0xd4, 0xd5, 0xd6, 0xd7, 0xd8, 0xd9, 0xda, 0xe1, 0xe2,
+			0xe3, 0xe4, 0xe5, 0xe6, 0xe7, 0xe8, 0xe9, 0xea, 0xf1, 0xf2, 0xf3, 0xf4, 0xf5, 0xf6, 0xf7, 0xf8,
+			0xf9, 0xfa}},
+		{0x01, [16]byte{0, 3, 1, 1, 1, 1, 1, 1, 1, 1, 1, 0, 0, 0, 0, 0},
+			[]byte{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11}},
+		{0x11, [16]byte{0, 2, 1, 2, 4, 4, 3, 4, 7, 5, 4, 4, 0, 1, 2, 119}, []byte{
+			0x00, 0x01, 0x02, 0x03, 0x11, 0x04, 0x05, 0x21, 0x31, 0x06, 0x12, 0x41, 0x51, 0x07, 0x61, 0x71,
+			0x13, 0x22, 0x32, 0x81, 0x08, 0x14, 0x42, 0x91, 0xa1, 0xb1, 0xc1, 0x09, 0x23, 0x33, 0x52, 0xf0,
+			0x15, 0x62, 0x72, 0xd1, 0x0a, 0x16, 0x24, 0x34, 0xe1, 0x25, 0xf1, 0x17, 0x18, 0x19, 0x1a, 0x26,
+			0x27, 0x28, 0x29, 0x2a, 0x35, 0x36, 0x37, 0x38, 0x39, 0x3a, 0x43, 0x44, 0x45, 0x46, 0x47, 0x48,
+			0x49, 0x4a, 0x53, 0x54, 0x55, 0x56, 0x57, 0x58, 0x59, 0x5a, 0x63, 0x64, 0x65, 0x66, 0x67, 0x68,
+			0x69, 0x6a, 0x73, 0x74, 0x75, 0x76, 0x77, 0x78, 0x79, 0x7a, 0x82, 0x83, 0x84, 0x85, 0x86, 0x87,
+			0x88, 0x89, 0x8a, 0x92, 0x93, 0x94, 0x95, 0x96, 0x97, 0x98, 0x99, 0x9a, 0xa2, 0xa3, 0xa4, 0xa5,
+			0xa6, 0xa7, 0xa8, 0xa9, 0xaa, 0xb2, 0xb3, 0xb4, 0xb5, 0xb6, 0xb7, 0xb8, 0xb9, 0xba, 0xc2, 0xc3,
+			0xc4, 0xc5, 0xc6, 0xc7, 0xc8, 0xc9, 0xca, 0xd2, 0xd3, 0xd4, 0xd5, 0xd6, 0xd7, 0xd8, 0xd9, 0xda,
+			0xe2, 0xe3, 0xe4, 0xe5, 0xe6, 0xe7, 0xe8, 0xe9, 0xea, 0xf2, 0xf3, 0xf4, 0xf5, 0xf6, 0xf7, 0xf8,
+			0xf9, 0xfa}},
+	}
+	var payload []byte
+	for _, t := range tables {
+		payload = append(payload, t.class)
+		payload = append(payload, t.counts[:]...)
+		payload = append(payload, t.values...)
+	}
+	length := len(payload) + 2
+	return append([]byte{0xFF, jpegDHT, byte(length >> 8), byte(length)}, payload...)
+}()
@@ -0,0 +1,227 @@
+package camera
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"testing"
+)
+
+// testJPEG encodes a small gradient as a baseline JPEG.
+func testJPEG(t *testing.T, w, h int) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, color.RGBA{uint8(x * 16), uint8(y * 16), 128, 255})
+		}
+	}
+	var b bytes.Buffer
+	if err := jpeg.Encode(&b, img, &jpeg.Options{Quality: 80}); err != nil {
+		t.Fatal(err)
+	}
+	return b.Bytes()
+}
+
+// withThumbnail inserts an APP1 segment carrying a complete JPEG after
+// frame's SOI, like an EXIF thumbnail.
+func withThumbnail(frame, thumb []byte) []byte {
+	length := len(thumb) + 2
+	out := append([]byte{}, frame[:2]...)
+	out = append(out, 0xFF, 0xE1, byte(length>>8), byte(length))
+	out = append(out, thumb...)
+	return append(out, frame[2:]...)
+}
+
+// withoutDHT removes frame's Huffman table segments, like a UVC camera
+// relying on the standard tables.
+func withoutDHT(t *testing.T, frame []byte) []byte {
+	t.Helper()
+	out := append([]byte{}, frame[:2]...)
+	for i := 2; ; {
+		if frame[i] != 0xFF {
+			t.Fatalf("no marker at %d", i)
+		}
+		m := frame[i+1]
+		if m == jpegSOS {
+			return append(out, frame[i:]...)
+		}
+		end := i + 2 + (int(frame[i+2])<<8 | int(frame[i+3]))
+		if m != jpegDHT {
+			out = append(out, frame[i:end]...)
+		}
+		i = end
+	}
+}
+
+// parseAll feeds stream in chunk-sized pieces and collects the frames.
+func parseAll(p *mjpegParser, stream []byte, chunk int) [][]byte {
+	var frames [][]byte
+	for i := 0; i < len(stream); i += chunk {
+		end := i + chunk
+		if end > len(stream) {
+			end = len(stream)
+		}
+		p.feed(stream[i:end])
+		for f := p.next(); f != nil; f = p.next() {
+			frames = append(frames, f)
+		}
+	}
+	return frames
+}
+
+func TestMJPEGParser_BackToBack(t *testing.T) {
+	a, b := testJPEG(t, 16, 16), testJPEG(t, 32, 8)
+	stream := append(append([]byte{}, a...), b...)
+	for _, chunk := range []int{1, 7, 4096} {
+		p := newMJPEGParser()
+		frames := parseAll(p, stream, chunk)
+		if len(frames) != 2 || !bytes.Equal(frames[0], a) || !bytes.Equal(frames[1], b) {
+			t.Fatalf("chunk %d: got %d frames, want the two input frames", chunk, len(frames))
+		}
+		if n := p.takeResyncs(); n != 0 {
+			t.Errorf("chunk %d: resyncs = %d, want 0", chunk, n)
+		}
+	}
+}
+
+func TestMJPEGParser_EmbeddedThumbnail(t *testing.T) {
+	frame := withThumbnail(testJPEG(t, 32, 32), testJPEG(t, 8, 8))
+	stream := append(append([]byte{}, frame...), frame...)
+	frames := parseAll(newMJPEGParser(), stream, 512)
+	if len(frames) != 2 {
+		t.Fatalf("got %d frames, want 2 (the thumbnail's EOI must not end the frame)", len(frames))
+	}
+	for i, f := range frames {
+		if !bytes.Equal(f, frame) {
+			t.Errorf("frame %d: %d bytes, want %d", i, len(f), len(frame))
+		}
+	}
+}
+
+func TestMJPEGParser_MissingEOI(t *testing.T) {
+	a, b := testJPEG(t, 16, 16), testJPEG(t, 16, 16)
+	stream := append(append([]byte{}, a[:len(a)-2]...), b...)
+	p := newMJPEGParser()
+	frames := parseAll(p, stream, 4096)
+	if len(frames) != 2 {
+		t.Fatalf("got %d frames, want 2", len(frames))
+	}
+	if !bytes.Equal(frames[0], a) {
+		t.Error("frame without EOI was not repaired")
+	}
+	if _, err := jpeg.Decode(bytes.NewReader(frames[0])); err != nil {
+		t.Errorf("repaired frame does not decode: %v", err)
+	}
+	if n := p.takeResyncs(); n != 0 {
+		t.Errorf("resyncs = %d, want 0", n)
+	}
+}
+
+func TestMJPEGParser_InsertsStandardHuffmanTables(t *testing.T) {
+	// The Go encoder codes with the section K.3 tables, so the frame must
+	// come back byte for byte once they are inserted again
+	a := testJPEG(t, 16, 16)
+	bare := withoutDHT(t, a)
+	if _, err := jpeg.Decode(bytes.NewReader(bare)); err == nil {
+		t.Fatal("frame without DHT decoded; test setup is wrong")
+	}
+	frames := parseAll(newMJPEGParser(), append(append([]byte{}, bare...), a...), 4096)
+	if len(frames) != 2 {
+		t.Fatalf("got %d frames, want 2", len(frames))
+	}
+	if !bytes.Equal(frames[0], a) {
+		t.Error("frame without DHT: standard tables not inserted before SOS")
+	}
+	if !bytes.Equal(frames[1], a) {
+		t.Error("frame with DHT was changed")
+	}
+}
+
+func TestMJPEGParser_GarbageBetweenFrames(t *testing.T) {
+	a := testJPEG(t, 16, 16)
+	stream := append([]byte{0x12, 0xFF, 0x00}, a...)
+	stream = append(stream, 0x00, 0x42, 0xFF)
+	stream = append(stream, a...)
+	p := newMJPEGParser()
+	frames := parseAll(p, stream, 3)
+	if len(frames) != 2 {
+		t.Fatalf("got %d frames, want 2", len(frames))
+	}
+	if n := p.takeResyncs(); n != 2 {
+		t.Errorf("resyncs = %d, want 2 (junk before each frame)", n)
+	}
+}
+
+func TestMJPEGParser_CorruptFrameDropped(t *testing.T) {
+	a := testJPEG(t, 16, 16)
+	// An empty DQT segment followed by a byte that is not a marker
+	bad := []byte{0xFF, 0xD8, 0xFF, 0xDB, 0x00, 0x02, 0x33, 0x44}
+	stream := append(append(append([]byte{}, a...), bad...), a...)
+	p := newMJPEGParser()
+	frames := parseAll(p, stream, 64)
+	if len(frames) != 2 {
+		t.Fatalf("got %d frames, want 2 (only the corrupt frame dropped)", len(frames))
+	}
+	if n := p.takeResyncs(); n != 1 {
+		t.Errorf("resyncs = %d, want 1", n)
+	}
+}
+
+func TestMJPEGParser_OversizedFrame(t *testing.T) {
+	p := newMJPEGParser()
+	p.feed([]byte{0xFF, 0xD8, 0xFF, 0xDA, 0x00, 0x02})
+	junk := bytes.Repeat([]byte{0x11}, 64<<10)
+	for i := 0; i <= maxMJPEGFrame/len(junk); i++ {
+		p.feed(junk)
+		if f := p.next(); f != nil {
+			t.Fatal("unterminated scan returned a frame")
+		}
+	}
+	a := testJPEG(t, 16, 16)
+	p.feed(a)
+	if f := p.next(); !bytes.Equal(f, a) {
+		t.Fatal("parser did not recover after an oversized frame")
+	}
+	if n := p.takeResyncs(); n != 1 {
+		t.Errorf("resyncs = %d, want 1", n)
+	}
+}
+
+// benchJPEG encodes a camera-like w x h picture (smooth gradients with
+// sensor noise, YCbCr 4:2:0) as a webcam's MJPEG frame would be.
+func benchJPEG(b *testing.B, w, h int) []byte {
+	img := image.NewYCbCr(image.Rect(0, 0, w, h), image.YCbCrSubsampleRatio420)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Y[y*img.YStride+x] = uint8(40 + (x+y)*160/(w+h) + (x*7+y*13)%11)
+		}
+	}
+	for i := range img.Cb {
+		img.Cb[i], img.Cr[i] = uint8(118+i%9), uint8(132-i%7)
+	}
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 80}); err != nil {
+		b.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// BenchmarkDecodeMJPEG is a capture worker's work per frame of a 640x480
+// MJPEG stream (the default capture size): splitting the frame out of
+// the pipe's bytes and decoding it.
+func BenchmarkDecodeMJPEG(b *testing.B) {
+	frame := benchJPEG(b, 640, 480)
+	cw := &CaptureWorker{}
+	p := newMJPEGParser()
+	b.SetBytes(int64(len(frame)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		p.feed(frame)
+		data := p.next()
+		if data == nil || cw.decodeJPEG(data) == nil {
+			b.Fatal("frame not decoded")
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package camera
+
+import (
+	"log"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// =============================================================================
+// Network (IP / RTSP / HTTP) cameras
+// =============================================================================
+// Cameras declared in [network_cameras] (name = URL) are ingested by
+// FFmpeg like a local device and go through the same MJPEG pipe, decoder
+// and FrameBuffer. They take slots before discovered USB cameras (see
+// withExtraCameras), so a declared trailer camera is never crowded out.
+//
+// DevicePath holds the URL; DeviceID is "net-<name>". There is no device
+// node, so hotplug, V4L2 controls and device-holder cleanup skip them;
+// an unreachable stream drops to test patterns and retries like an
+// unplugged camera.
+// =============================================================================
+
+// networkTimeoutUS is how long FFmpeg waits on a silent stream before
+// giving up (microseconds), so a dropped WiFi link ends the capture
+// instead of hanging it.
+const networkTimeoutUS = "5000000"
+
+// IsNetworkSource reports whether path is a stream URL rather than a
+// local device.
+func IsNetworkSource(path string) bool {
+	u, err := url.Parse(path)
+	if err != nil {
+		return false
+	}
+	switch strings.ToLower(u.Scheme) {
+	case "rtsp", "rtsps", "http", "https", "rtmp", "udp", "tcp", "srt":
+		return u.Host != ""
+	}
+	return false
+}
+
+// RedactSource hides the password in a stream URL for logging.
+func RedactSource(path string) string {
+	if !IsNetworkSource(path) {
+		return path
+	}
+	u, err := url.Parse(path)
+	if err != nil {
+		return path
+	}
+	return u.Redacted()
+}
+
+// networkCameras builds cameras from the declared sources, sorted by
+// name, skipping disabled ones.
+func networkCameras(s Settings) []Camera {
+	names := make([]string, 0, len(s.NetworkCameras))
+	for name := range s.NetworkCameras {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var cameras []Camera
+	for _, name := range names {
+		source := strings.TrimSpace(s.NetworkCameras[name])
+		if !IsNetworkSource(source) {
+			log.Printf("[Discovery] WARNING: network camera %s: %q is not a stream URL, skipping", name, RedactSource(source))
+			continue
+		}
+		cam := Camera{
+			DeviceID:   "net-" + sanitizeID(name),
+			DevicePath: source,
+			Name:       name,
+			Available:  true,
+		}
+		cs := s.ForCamera(cam) // Matched by its ID, net-<name>
+		cam.Capabilities = CameraCapabilities{
+			MaxWidth:  cs.Width,
+			MaxHeight: cs.Height,
+			MaxFPS:    cs.FPS,
+			Format:    "mjpeg",
+		}
+		if isDisabled(s.DisabledDevices, cam) {
+			log.Printf("[Discovery] Network camera %s is disabled in config, skipping", name)
+			continue
+		}
+		cameras = append(cameras, cam)
+	}
+	return cameras
+}
+
+// networkInputVariants returns the FFmpeg input arguments for a stream.
+// The probe limits in the common args are far too small for H.264
+// streams, so they are raised here (later options win). RTSP tries TCP
+// first (reliable over WiFi), then UDP. The stream is scaled to the
+// capture size so re-encoding to MJPEG stays cheap.
+func networkInputVariants(source, videoSize, fps string) [][]string {
+	probe := []string{"-probesize", "500000", "-analyzeduration", "1000000", "-fflags", "nobuffer"}
+	output := []string{"-vf", "scale=" + strings.Replace(videoSize, "x", ":", 1), "-r", fps}
+
+	variant := func(input ...string) []string {
+		args := append(append([]string{}, probe...), input...)
+		return append(append(args, "-i", source), output...)
+	}
+
+	if strings.HasPrefix(strings.ToLower(source), "rtsp") {
+		return [][]string{
+			variant("-rtsp_transport", "tcp", "-timeout", networkTimeoutUS),
+			variant("-rtsp_transport", "udp", "-timeout", networkTimeoutUS),
+		}
+	}
+	return [][]string{variant("-rw_timeout", networkTimeoutUS)}
+}
+
+// redactArgs returns args with the stream URL's password hidden.
+func redactArgs(args []string, source string) []string {
+	if !IsNetworkSource(source) {
+		return args
+	}
+	redacted := make([]string, len(args))
+	for i, arg := range args {
+		if arg == source {
+			arg = RedactSource(source)
+		}
+		redacted[i] = arg
+	}
+	return redacted
+}
+
+// isDisabled reports whether any disabled entry matches cam.
+func isDisabled(disabled []string, cam Camera) bool {
+	for _, entry := range disabled {
+		if MatchesCamera(entry, cam) {
+			return true
+		}
+	}
+	return false
+}
+
+// sanitizeID makes name safe for device IDs (file names, MQTT topics).
+func sanitizeID(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(strings.TrimSpace(name)) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '-', r == '_':
+			b.WriteRune(r)
+		default:
+			b.WriteRune('-')
+		}
+	}
+	if b.Len() == 0 {
+		return "camera"
+	}
+	return b.String()
+}
@@ -1,6 +1,12 @@
 package camera
 
-import "testing"
+import (
+	"camera-dashboard-go/internal/helpers"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
 
 func TestDefaultSettings(t *testing.T) {
 	s := DefaultSettings()
@@ -33,3 +39,108 @@ func TestNewManagerWithSettings_DefaultMaxCameras(t *testing.T) {
 		t.Errorf("MaxCameras = %d, want %d", s.MaxCameras, DefaultMaxCameras)
 	}
 }
+
+func TestFFmpegCommand_Wrappers(t *testing.T) {
+	args := []string{"-i", "/dev/video0", "-"}
+
+	plain := ffmpegCommand(args, Settings{})
+	if filepath.Base(plain.Args[0]) != "ffmpeg" || len(plain.Args) != 4 {
+		t.Errorf("unwrapped command = %v", plain.Args)
+	}
+
+	if _, err := exec.LookPath("nice"); err != nil {
+		t.Skip("nice not installed")
+	}
+	niced := ffmpegCommand(args, Settings{FFmpegNice: 10})
+	got := strings.Join(niced.Args[1:], " ")
+	if want := "-n 10 ffmpeg -i /dev/video0 -"; got != want {
+		t.Errorf("niced args = %q, want %q", got, want)
+	}
+}
+
+// Without ffmpeg_cpus, FFmpeg must not inherit the capture thread's
+// pinning: it goes back on the CPUs the process started with.
+func TestFFmpegCommand_CaptureCPUsNotInherited(t *testing.T) {
+	if _, err := exec.LookPath("taskset"); err != nil || len(processCPUs) == 0 {
+		t.Skip("taskset or CPU affinity not available")
+	}
+	args := []string{"-i", "/dev/video0", "-"}
+	cmd := ffmpegCommand(args, Settings{CaptureCPUs: []int{0}})
+	got := strings.Join(cmd.Args[1:], " ")
+	if want := "-c " + helpers.FormatCPUList(processCPUs) + " ffmpeg -i /dev/video0 -"; got != want {
+		t.Errorf("args = %q, want %q", got, want)
+	}
+
+	cmd = ffmpegCommand(args, Settings{FFmpegCPUs: []int{1}, CaptureCPUs: []int{0}})
+	if got := strings.Join(cmd.Args[1:], " "); got != "-c 1 ffmpeg -i /dev/video0 -" {
+		t.Errorf("args with ffmpeg_cpus = %q", got)
+	}
+}
+
+func TestMatchesCamera(t *testing.T) {
+	cam := Camera{DeviceID: "video2", DevicePath: "/dev/video2", USB: USBDescriptor{VendorID: "046d", ProductID: "0825", Serial: "ABC123", Port: "1-1.2"}}
+	tests := []struct {
+		entry string
+		want  bool
+	}{
+		{"/dev/video2", true},
+		{"video2", true},
+		{"046D:0825:abc123", true},
+		{"port:1-1.2", true},
+		{"port:1-1", false},
+		{"serial:ABC123", true},
+		{"serial:abc123", false},
+		{"", false},
+	}
+	for _, tc := range tests {
+		if got := MatchesCamera(tc.entry, cam); got != tc.want {
+			t.Errorf("MatchesCamera(%q) = %v, want %v", tc.entry, got, tc.want)
+		}
+	}
+	if MatchesCamera("serial:", Camera{DeviceID: "video0"}) || MatchesCamera("port:", Camera{DeviceID: "video0"}) {
+		t.Error("empty serial/port matched a camera without a USB descriptor")
+	}
+}
+
+func TestSettingsForCamera(t *testing.T) {
+	s := DefaultSettings()
+	s.PerCamera = []CameraOverride{
+		{Match: []string{"port:1-1.2"}, FPS: 15, Format: "yuyv"},
+		{Match: []string{"video0", "video2"}, Width: 1280, Height: 720, FPS: 30},
+	}
+
+	rear := s.ForCamera(Camera{DeviceID: "video2", DevicePath: "/dev/video2", USB: USBDescriptor{Port: "1-1.2"}})
+	if rear.Width != DefaultWidth || rear.FPS != 15 || rear.Format != "yuyv" {
+		t.Errorf("first match: %dx%d @ %d %s, want the defaults at 15 FPS yuyv", rear.Width, rear.Height, rear.FPS, rear.Format)
+	}
+	front := s.ForCamera(Camera{DeviceID: "video0", DevicePath: "/dev/video0"})
+	if front.Width != 1280 || front.Height != 720 || front.FPS != 30 || front.Format != DefaultFormat {
+		t.Errorf("second match: %dx%d @ %d %s", front.Width, front.Height, front.FPS, front.Format)
+	}
+	other := s.ForCamera(Camera{DeviceID: "video4", DevicePath: "/dev/video4"})
+	if other.Width != DefaultWidth || other.FPS != DefaultFPS {
+		t.Errorf("no match: %dx%d @ %d", other.Width, other.Height, other.FPS)
+	}
+}
+
+func TestManagerSetFPS_PerCamera(t *testing.T) {
+	s := Settings{Width: 640, Height: 480, FPS: 15}
+	s.PerCamera = []CameraOverride{{Match: []string{"video2"}, FPS: 30}}
+	front := Camera{DeviceID: "video0", DevicePath: "/dev/video0"}
+	rear := Camera{DeviceID: "video2", DevicePath: "/dev/video2"}
+	m := &Manager{settings: s, cameras: []Camera{front, rear}}
+	for _, cam := range m.cameras {
+		m.workers = append(m.workers, NewCaptureWorkerWithBuffer(cam, NewFrameBuffer(), s.ForCamera(cam)))
+	}
+
+	// At the global rate each camera runs at its own
+	m.SetFPS(15)
+	if f, r := m.workers[0].GetFPS(), m.workers[1].GetFPS(); f != 15 || r != 30 {
+		t.Errorf("SetFPS(15): front %d, rear %d; want 15, 30", f, r)
+	}
+	// Throttled, the rear camera keeps its ratio
+	m.SetFPS(10)
+	if f, r := m.workers[0].GetFPS(), m.workers[1].GetFPS(); f != 10 || r != 20 {
+		t.Errorf("SetFPS(10): front %d, rear %d; want 10, 20", f, r)
+	}
+}
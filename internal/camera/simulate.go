@@ -0,0 +1,227 @@
+package camera
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/jpeg"
+	_ "image/png" // PNG frames in image directories
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// =============================================================================
+// Simulated cameras (-simulate)
+// =============================================================================
+// For working on the UI and the FPS controller on a laptop: with
+// Settings.Simulate set, discovery is skipped and each source becomes a
+// camera that plays in a loop instead of capturing:
+//
+//   clip.mjpeg        a raw MJPEG stream, such as a saved clip
+//   frames/           a directory of .jpg / .png images, in name order
+//   still.jpg         a single image
+//   drive.mp4         any other file: decoded by FFmpeg (-stream_loop)
+//
+// MJPEG, directories and images are played in-process at the capture
+// frame rate and need no FFmpeg. Frames go through the same parser,
+// frame skipping, decoder, clip buffer and FrameBuffer as a real
+// camera, so adaptive FPS, clips and replay behave as on the Pi.
+//
+// DevicePath is "sim:<source>" and DeviceID "sim<N>". Simulated cameras
+// have no device node, so hotplug, V4L2 controls and USB bandwidth
+// scheduling skip them.
+// =============================================================================
+
+// simPrefix marks a simulated camera's DevicePath.
+const simPrefix = "sim:"
+
+// simSource returns the source file or directory of a "sim:" path.
+func simSource(path string) (string, bool) {
+	if !strings.HasPrefix(path, simPrefix) {
+		return "", false
+	}
+	return strings.TrimPrefix(path, simPrefix), true
+}
+
+// simPlaysInProcess reports whether source is played without FFmpeg
+// (MJPEG stream, image or image directory).
+func simPlaysInProcess(source string) bool {
+	if info, err := os.Stat(source); err == nil && info.IsDir() {
+		return true
+	}
+	switch strings.ToLower(filepath.Ext(source)) {
+	case ".mjpeg", ".mjpg", ".jpg", ".jpeg", ".png":
+		return true
+	}
+	return false
+}
+
+// simulatedCameras builds one camera per source, up to the camera limit.
+func simulatedCameras(s Settings) []Camera {
+	maxCameras := s.MaxCameras
+	if maxCameras <= 0 {
+		maxCameras = DefaultMaxCameras
+	}
+	var cameras []Camera
+	for _, source := range s.Simulate {
+		if len(cameras) >= maxCameras {
+			log.Printf("[Discovery] No slot left for simulated source %s", source)
+			continue
+		}
+		if _, err := os.Stat(source); err != nil {
+			log.Printf("[Discovery] WARNING: simulated source %s: %v, skipping", source, err)
+			continue
+		}
+		cameras = append(cameras, Camera{
+			DeviceID:   fmt.Sprintf("sim%d", len(cameras)),
+			DevicePath: simPrefix + source,
+			Name:       strings.TrimSuffix(filepath.Base(source), filepath.Ext(source)),
+			Available:  true,
+			Capabilities: CameraCapabilities{
+				MaxWidth:  s.Width,
+				MaxHeight: s.Height,
+				MaxFPS:    s.FPS,
+				Format:    "mjpeg",
+			},
+		})
+	}
+
+	log.Printf("[Discovery] Simulating %d cameras", len(cameras))
+	for _, cam := range cameras {
+		log.Printf("[Discovery]   %s: %s", cam.DeviceID, cam.DevicePath)
+	}
+	return cameras
+}
+
+// simVideoInput returns the FFmpeg input arguments that play a video
+// file in a loop at its own speed, scaled to the capture size.
+func simVideoInput(source, videoSize, fps string) []string {
+	return []string{"-re", "-stream_loop", "-1", "-i", source,
+		"-vf", "scale=" + strings.Replace(videoSize, "x", ":", 1), "-r", fps}
+}
+
+// loadSimFrames reads the JPEG frames of an in-process source.
+func loadSimFrames(source string) ([][]byte, error) {
+	info, err := os.Stat(source)
+	if err != nil {
+		return nil, err
+	}
+	var frames [][]byte
+	if info.IsDir() {
+		entries, err := os.ReadDir(source)
+		if err != nil {
+			return nil, err
+		}
+		names := make([]string, 0, len(entries))
+		for _, e := range entries {
+			switch strings.ToLower(filepath.Ext(e.Name())) {
+			case ".jpg", ".jpeg", ".png":
+				if e.Type().IsRegular() {
+					names = append(names, e.Name())
+				}
+			}
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			frame, err := loadSimImage(filepath.Join(source, name))
+			if err != nil {
+				log.Printf("[Capture] WARNING: simulated frame %s: %v, skipping", name, err)
+				continue
+			}
+			frames = append(frames, frame)
+		}
+	} else if ext := strings.ToLower(filepath.Ext(source)); ext == ".mjpeg" || ext == ".mjpg" {
+		data, err := os.ReadFile(source)
+		if err != nil {
+			return nil, err
+		}
+		parser := newMJPEGParser()
+		parser.feed(data)
+		for frame := parser.next(); frame != nil; frame = parser.next() {
+			frames = append(frames, frame)
+		}
+	} else {
+		frame, err := loadSimImage(source)
+		if err != nil {
+			return nil, err
+		}
+		frames = append(frames, frame)
+	}
+	if len(frames) == 0 {
+		return nil, fmt.Errorf("no frames in %s", source)
+	}
+	return frames, nil
+}
+
+// loadSimImage returns an image file as JPEG bytes, re-encoding other
+// formats.
+func loadSimImage(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if ext := strings.ToLower(filepath.Ext(path)); ext == ".jpg" || ext == ".jpeg" {
+		return data, nil
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 90}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// simReader plays frames as an endless MJPEG stream, one frame every
+// 1/fps seconds.
+type simReader struct {
+	frames  [][]byte
+	fps     func() int
+	next    int
+	due     time.Time
+	pending []byte
+}
+
+func newSimReader(frames [][]byte, fps func() int) *simReader {
+	return &simReader{frames: frames, fps: fps}
+}
+
+func (r *simReader) Read(p []byte) (int, error) {
+	if len(r.pending) == 0 {
+		if wait := time.Until(r.due); wait > 0 {
+			time.Sleep(wait)
+		} else {
+			r.due = time.Now() // First frame, or fell behind: don't burst
+		}
+		fps := r.fps()
+		if fps <= 0 {
+			fps = DefaultFPS
+		}
+		r.due = r.due.Add(time.Second / time.Duration(fps))
+		r.pending = r.frames[r.next]
+		r.next = (r.next + 1) % len(r.frames)
+	}
+	n := copy(p, r.pending)
+	r.pending = r.pending[n:]
+	return n, nil
+}
+
+// trySimulatedCapture plays an in-process source until the worker stops.
+func (cw *CaptureWorker) trySimulatedCapture(source string) bool {
+	frames, err := loadSimFrames(source)
+	if err != nil {
+		log.Printf("[Capture] Camera %s: simulated source: %v", cw.camera.DeviceID, err)
+		return false
+	}
+	log.Printf("[Capture] Camera %s: Playing %d frames from %s in a loop @ %d FPS",
+		cw.camera.DeviceID, len(frames), source, cw.GetMaxFPS())
+	format := "sim"
+	cw.format.Store(&format)
+	return cw.readStream(newSimReader(frames, cw.GetMaxFPS), format)
+}
@@ -0,0 +1,131 @@
+package camera
+
+import (
+	"bytes"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLoadSimFrames(t *testing.T) {
+	dir := t.TempDir()
+	small, large := testJPEG(t, 16, 16), testJPEG(t, 32, 24)
+
+	// MJPEG stream, as saved clips are written
+	clip := filepath.Join(dir, "clip.mjpeg")
+	if err := os.WriteFile(clip, append(append([]byte{}, small...), large...), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	frames, err := loadSimFrames(clip)
+	if err != nil || len(frames) != 2 {
+		t.Fatalf("mjpeg: %d frames, %v; want 2", len(frames), err)
+	}
+
+	// Image directory in name order, PNGs converted, other files ignored
+	images := filepath.Join(dir, "frames")
+	os.Mkdir(images, 0o755)
+	os.WriteFile(filepath.Join(images, "002.jpg"), large, 0o644)
+	os.WriteFile(filepath.Join(images, "notes.txt"), []byte("x"), 0o644)
+	var pngData bytes.Buffer
+	png.Encode(&pngData, image.NewGray(image.Rect(0, 0, 8, 8)))
+	os.WriteFile(filepath.Join(images, "001.png"), pngData.Bytes(), 0o644)
+	frames, err = loadSimFrames(images)
+	if err != nil || len(frames) != 2 {
+		t.Fatalf("directory: %d frames, %v; want 2", len(frames), err)
+	}
+	if img, err := jpeg.Decode(bytes.NewReader(frames[0])); err != nil || img.Bounds().Dx() != 8 {
+		t.Errorf("001.png not converted to JPEG first: %v", err)
+	}
+	if !bytes.Equal(frames[1], large) {
+		t.Error("002.jpg not passed through")
+	}
+
+	if _, err := loadSimFrames(filepath.Join(dir, "missing")); err == nil {
+		t.Error("missing source accepted")
+	}
+	empty := filepath.Join(dir, "empty")
+	os.Mkdir(empty, 0o755)
+	if _, err := loadSimFrames(empty); err == nil {
+		t.Error("empty directory accepted")
+	}
+}
+
+func TestSimulatedCameras(t *testing.T) {
+	dir := t.TempDir()
+	a, b := filepath.Join(dir, "front.mjpeg"), filepath.Join(dir, "rear.mp4")
+	os.WriteFile(a, nil, 0o644)
+	os.WriteFile(b, nil, 0o644)
+
+	cams := simulatedCameras(Settings{Width: 640, Height: 480, FPS: 15, MaxCameras: 2,
+		Simulate: []string{a, filepath.Join(dir, "missing"), b, a}})
+	if len(cams) != 2 {
+		t.Fatalf("%d cameras, want 2 (missing skipped, limit 2)", len(cams))
+	}
+	if cams[0].DeviceID != "sim0" || cams[0].Name != "front" || cams[1].DeviceID != "sim1" || cams[1].Name != "rear" {
+		t.Errorf("cameras = %+v", cams)
+	}
+	if cams[0].HasDeviceNode() {
+		t.Error("simulated camera has a device node")
+	}
+	if !simPlaysInProcess(a) || simPlaysInProcess(b) || !simPlaysInProcess(dir) {
+		t.Error("mjpeg/directory should play in-process, mp4 through FFmpeg")
+	}
+	args := simVideoInput(b, "640x480", "15")
+	if args[0] != "-re" || args[4] != b || streamFormat(cams[1].DevicePath, args) != "sim" {
+		t.Errorf("video input = %v", args)
+	}
+}
+
+func TestSimReader_PacesAndLoops(t *testing.T) {
+	frames := [][]byte{{1, 2, 3}, {4, 5}}
+	r := newSimReader(frames, func() int { return 50 })
+	buf := make([]byte, 2)
+	var got []byte
+	start := time.Now()
+	for len(got) < 10 { // Two full loops
+		n, err := r.Read(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, buf[:n]...)
+	}
+	if want := []byte{1, 2, 3, 4, 5, 1, 2, 3, 4, 5}; !bytes.Equal(got, want) {
+		t.Errorf("stream = %v, want %v", got, want)
+	}
+	// Four frames at 50 FPS: the first at once, then 20ms apart
+	if elapsed := time.Since(start); elapsed < 55*time.Millisecond {
+		t.Errorf("4 frames took %v, want ~60ms", elapsed)
+	}
+}
+
+func TestCaptureWorker_Simulated(t *testing.T) {
+	clip := filepath.Join(t.TempDir(), "clip.mjpeg")
+	if err := os.WriteFile(clip, testJPEG(t, 32, 24), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	buffer := NewFrameBuffer()
+	cams := simulatedCameras(Settings{Width: 32, Height: 24, FPS: 30, Simulate: []string{clip}})
+	cw := NewCaptureWorkerWithBuffer(cams[0], buffer, Settings{Width: 32, Height: 24, FPS: 30})
+	if err := cw.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer cw.Stop()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for buffer.GetFrameCount() < 3 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if n := buffer.GetFrameCount(); n < 3 {
+		t.Fatalf("%d frames after 2s, want a looping stream", n)
+	}
+	if cw.SignalLost() {
+		t.Error("simulated camera reported signal lost")
+	}
+	if s := cw.StreamHealth(); s.Format != "sim" {
+		t.Errorf("format = %q, want sim", s.Format)
+	}
+}
@@ -0,0 +1,37 @@
+package camera
+
+import "sync/atomic"
+
+// =============================================================================
+// Process-wide capture counters
+// =============================================================================
+// A worker's own stats (GetStats) reset when it restarts, and a soft
+// restart replaces the whole Manager. These counters add up every worker
+// for the life of the process, for monitoring (the debug endpoint's
+// expvar counters, see ui/debugserver.go).
+// =============================================================================
+
+var totals struct {
+	decoded      atomic.Uint64
+	dropped      atomic.Uint64
+	decodeErrors atomic.Uint64
+	restarts     atomic.Uint64
+}
+
+// Totals is a snapshot of the process-wide capture counters.
+type Totals struct {
+	FramesDecoded  uint64 // JPEG frames decoded
+	FramesDropped  uint64 // Frames read but not decoded: FPS limit, off screen, decode errors
+	DecodeErrors   uint64 // Frames that failed to decode (also in FramesDropped)
+	WorkerRestarts uint64 // Capture worker restarts (hot-plug, stale recovery, settings)
+}
+
+// ReadTotals returns the process-wide capture counters.
+func ReadTotals() Totals {
+	return Totals{
+		FramesDecoded:  totals.decoded.Load(),
+		FramesDropped:  totals.dropped.Load(),
+		DecodeErrors:   totals.decodeErrors.Load(),
+		WorkerRestarts: totals.restarts.Load(),
+	}
+}
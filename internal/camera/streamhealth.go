@@ -0,0 +1,157 @@
+package camera
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// =============================================================================
+// Stream health and format fallback
+// =============================================================================
+// Every worker scores its stream over the last Settings.HealthWindow:
+// frames decoded against corrupt ones, i.e. JPEG decode errors, frame
+// read timeouts and resyncs (bytes that had to be skipped to find the
+// next frame start). Some cameras produce broken MJPEG when the car
+// shakes - a loose connector or a marginal hub corrupts the compressed
+// stream long before an uncompressed one suffers.
+//
+// When an MJPEG stream's corruption rate reaches FallbackCorruptRate
+// (with at least healthMinSamples attempts in the window) the worker
+// switches to YUYV at FallbackWidth x FallbackHeight: uncompressed
+// frames need far more USB bandwidth, so the resolution goes down with
+// it. FFmpeg encodes the YUYV frames to MJPEG for the pipe, which costs
+// CPU. The fallback lasts for the worker's life; a camera recreated by
+// hotplug starts with the configured format again.
+// =============================================================================
+
+const (
+	defaultHealthWindow = 30 * time.Second
+	healthMinSamples    = 50 // Attempts in the window before a fallback
+)
+
+// healthBucket counts one second of stream events.
+type healthBucket struct {
+	sec                                   int64 // Unix second; buckets from other seconds are stale
+	frames, decodeErrs, timeouts, resyncs int
+}
+
+// streamHealth scores a stream over a sliding window of one-second
+// buckets. Safe for concurrent use.
+type streamHealth struct {
+	mu      sync.Mutex
+	window  time.Duration
+	buckets []healthBucket
+}
+
+// healthEvent is one scored stream event.
+type healthEvent int
+
+const (
+	healthFrame healthEvent = iota
+	healthDecodeError
+	healthTimeout
+	healthResync
+)
+
+// record counts one event at now.
+func (h *streamHealth) record(ev healthEvent, now time.Time) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.buckets == nil {
+		if h.window <= 0 {
+			h.window = defaultHealthWindow
+		}
+		h.buckets = make([]healthBucket, int(h.window/time.Second)+1)
+	}
+	sec := now.Unix()
+	b := &h.buckets[int(sec%int64(len(h.buckets)))]
+	if b.sec != sec {
+		*b = healthBucket{sec: sec}
+	}
+	switch ev {
+	case healthFrame:
+		b.frames++
+	case healthDecodeError:
+		b.decodeErrs++
+	case healthTimeout:
+		b.timeouts++
+	case healthResync:
+		b.resyncs++
+	}
+}
+
+// counts sums the buckets within the window of now.
+func (h *streamHealth) counts(now time.Time) StreamHealth {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	var s StreamHealth
+	oldest := now.Add(-h.window).Unix()
+	for _, b := range h.buckets {
+		if b.sec > oldest && b.sec <= now.Unix() {
+			s.Frames += b.frames
+			s.DecodeErrors += b.decodeErrs
+			s.Timeouts += b.timeouts
+			s.Resyncs += b.resyncs
+		}
+	}
+	if attempts := s.attempts(); attempts > 0 {
+		s.CorruptRate = float64(s.DecodeErrors+s.Timeouts+s.Resyncs) / float64(attempts)
+		if s.CorruptRate > 1 {
+			s.CorruptRate = 1
+		}
+	}
+	return s
+}
+
+// reset forgets all events (after a format change).
+func (h *streamHealth) reset() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for i := range h.buckets {
+		h.buckets[i] = healthBucket{}
+	}
+}
+
+// StreamHealth is a worker's stream score over the health window.
+type StreamHealth struct {
+	Format       string // Active input format: mjpeg, yuyv, auto, csi or network ("" = not streaming yet)
+	Fallback     bool   // Switched to YUYV because of corrupt MJPEG
+	Frames       int    // Frames decoded
+	DecodeErrors int
+	Timeouts     int
+	Resyncs      int
+	CorruptRate  float64 // Corrupt events per attempt, 0.0-1.0
+}
+
+// attempts is every frame the stream delivered or failed to deliver.
+func (s StreamHealth) attempts() int {
+	return s.Frames + s.DecodeErrors + s.Timeouts
+}
+
+// shouldFallback reports whether an MJPEG stream is corrupt enough to
+// switch to YUYV (rate = 0 disables the fallback).
+func (s StreamHealth) shouldFallback(rate float64) bool {
+	return rate > 0 && !s.Fallback && s.Format == "mjpeg" &&
+		s.attempts() >= healthMinSamples && s.CorruptRate >= rate
+}
+
+// streamFormat names the input format of a capture command.
+func streamFormat(devicePath string, args []string) string {
+	if _, ok := csiIndex(devicePath); ok {
+		return "csi"
+	}
+	if IsNetworkSource(devicePath) {
+		return "network"
+	}
+	if _, ok := simSource(devicePath); ok {
+		return "sim"
+	}
+	// Input options come before -i (v4l2 -input_format, dshow -vcodec)
+	for i := 0; i+1 < len(args) && args[i] != "-i"; i++ {
+		if args[i] == "-input_format" || args[i] == "-vcodec" {
+			return strings.TrimSuffix(args[i+1], "422")
+		}
+	}
+	return "auto"
+}
@@ -0,0 +1,143 @@
+package camera
+
+import (
+	"bytes"
+	"camera-dashboard-go/internal/clock"
+	"testing"
+	"time"
+)
+
+func TestStreamHealth_Window(t *testing.T) {
+	h := streamHealth{window: 10 * time.Second}
+	start := time.Unix(1000, 0)
+	for i := 0; i < 90; i++ {
+		h.record(healthFrame, start)
+	}
+	for i := 0; i < 5; i++ {
+		h.record(healthDecodeError, start.Add(time.Second))
+		h.record(healthTimeout, start.Add(time.Second))
+	}
+	h.record(healthResync, start.Add(2*time.Second))
+
+	s := h.counts(start.Add(2 * time.Second))
+	if s.Frames != 90 || s.DecodeErrors != 5 || s.Timeouts != 5 || s.Resyncs != 1 {
+		t.Fatalf("counts = %+v", s)
+	}
+	if want := 11.0 / 100; s.CorruptRate != want {
+		t.Errorf("CorruptRate = %v, want %v", s.CorruptRate, want)
+	}
+
+	// The frames drop out of the window first, then everything
+	if s := h.counts(start.Add(10 * time.Second)); s.Frames != 0 || s.DecodeErrors != 5 || s.CorruptRate != 1 {
+		t.Errorf("after 10s: %+v, want only the errors (rate capped at 1)", s)
+	}
+	if s := h.counts(start.Add(time.Minute)); s != (StreamHealth{}) {
+		t.Errorf("after a minute: %+v, want nothing", s)
+	}
+}
+
+func TestStreamHealth_ShouldFallback(t *testing.T) {
+	corrupt := StreamHealth{Format: "mjpeg", Frames: 80, DecodeErrors: 20, CorruptRate: 0.2}
+	if !corrupt.shouldFallback(0.1) {
+		t.Error("20% corrupt MJPEG should fall back at 10%")
+	}
+	for name, s := range map[string]StreamHealth{
+		"below threshold":   {Format: "mjpeg", Frames: 95, DecodeErrors: 5, CorruptRate: 0.05},
+		"too few samples":   {Format: "mjpeg", Frames: 8, DecodeErrors: 2, CorruptRate: 0.2},
+		"already yuyv":      {Format: "yuyv", Frames: 80, DecodeErrors: 20, CorruptRate: 0.2},
+		"already fell back": {Format: "mjpeg", Fallback: true, Frames: 80, DecodeErrors: 20, CorruptRate: 0.2},
+	} {
+		if s.shouldFallback(0.1) {
+			t.Errorf("%s: should not fall back", name)
+		}
+	}
+	if corrupt.shouldFallback(0) {
+		t.Error("rate 0 should disable the fallback")
+	}
+}
+
+func TestStreamFormat(t *testing.T) {
+	for _, tc := range []struct {
+		device string
+		args   []string
+		want   string
+	}{
+		{"/dev/video0", []string{"-f", "v4l2", "-input_format", "mjpeg", "-i", "/dev/video0", "-vcodec", "mjpeg", "-"}, "mjpeg"},
+		{"/dev/video0", []string{"-f", "v4l2", "-input_format", "yuyv422", "-i", "/dev/video0", "-vcodec", "mjpeg", "-"}, "yuyv"},
+		{"/dev/video0", []string{"-f", "v4l2", "-i", "/dev/video0", "-vcodec", "mjpeg", "-"}, "auto"},
+		{"video=USB Camera", []string{"-f", "dshow", "-vcodec", "mjpeg", "-i", "video=USB Camera"}, "mjpeg"},
+		{"rtsp://10.0.0.5/stream", nil, "network"},
+	} {
+		if got := streamFormat(tc.device, tc.args); got != tc.want {
+			t.Errorf("streamFormat(%q, %v) = %q, want %q", tc.device, tc.args, got, tc.want)
+		}
+	}
+}
+
+func TestCaptureWorker_CheckFallback(t *testing.T) {
+	cw := NewCaptureWorkerWithBuffer(Camera{DeviceID: "video0"}, NewFrameBuffer(),
+		Settings{Width: 1280, Height: 720, FPS: 30, FallbackCorruptRate: 0.1, FallbackWidth: 640, FallbackHeight: 480})
+	format := "mjpeg"
+	cw.format.Store(&format)
+
+	now := time.Now()
+	for i := 0; i < 60; i++ {
+		cw.health.record(healthFrame, now)
+	}
+	if cw.checkFallback(now) {
+		t.Fatal("clean stream should not fall back")
+	}
+	for i := 0; i < 20; i++ {
+		cw.health.record(healthDecodeError, now)
+	}
+	if cw.checkFallback(now.Add(500 * time.Millisecond)) {
+		t.Error("checks within a second of the last should be skipped")
+	}
+	if !cw.checkFallback(now.Add(time.Second)) {
+		t.Fatal("25% corrupt MJPEG should fall back")
+	}
+	if s := cw.StreamHealth(); !s.Fallback || s.DecodeErrors != 0 {
+		t.Errorf("after fallback: %+v, want Fallback and a fresh score", s)
+	}
+	if w, h := cw.GetResolution(); w != 640 || h != 480 {
+		t.Errorf("fallback resolution %dx%d, want 640x480", w, h)
+	}
+
+	// Later tier changes keep the fallback cap
+	cw.SetResolution(960, 540)
+	if w, h := cw.GetResolution(); w != 640 || h != 480 {
+		t.Errorf("tier 960x540 after fallback: %dx%d, want 640x480", w, h)
+	}
+}
+
+func TestReadMJPEGFrameRaw_CountsResync(t *testing.T) {
+	cw := NewCaptureWorkerWithBuffer(Camera{DeviceID: "video0"}, NewFrameBuffer(), Settings{FPS: 10})
+	frame := testJPEG(t, 16, 16)
+	stream := append(append(append([]byte{}, frame...), 0x00, 0x42), frame...)
+
+	r := bytes.NewReader(stream)
+	buf := make([]byte, 64)
+	parser := newMJPEGParser()
+	for i := 0; i < 2; i++ {
+		if _, err := cw.readMJPEGFrameRaw(r, buf, parser, nil); err != nil {
+			t.Fatalf("frame %d: %v", i, err)
+		}
+	}
+	if s := cw.health.counts(time.Now()); s.Resyncs != 1 {
+		t.Errorf("Resyncs = %d, want 1 (junk before the second frame)", s.Resyncs)
+	}
+}
+
+func TestCaptureWorker_StreamHealthFakeClock(t *testing.T) {
+	clk := clock.NewFake(time.Unix(1000, 0))
+	cw := NewCaptureWorkerWithBuffer(Camera{DeviceID: "video0"}, NewFrameBuffer(),
+		Settings{Width: 640, Height: 480, FPS: 30, HealthWindow: 10 * time.Second, Clock: clk})
+	cw.health.record(healthDecodeError, clk.Now())
+	if s := cw.StreamHealth(); s.DecodeErrors != 1 {
+		t.Fatalf("counts = %+v, want the decode error", s)
+	}
+	clk.Advance(11 * time.Second)
+	if s := cw.StreamHealth(); s.DecodeErrors != 0 {
+		t.Errorf("after the window: %+v, want nothing", s)
+	}
+}
@@ -0,0 +1,38 @@
+package camera
+
+import "time"
+
+// =============================================================================
+// Frame tracing
+// =============================================================================
+// With Settings.TraceEvery = n a worker times one decoded frame in n
+// through the capture side of the pipeline and publishes the FrameTrace
+// with the frame; the UI adds the stages after the buffer (see
+// ui/tracing.go and the -trace-frames flag). Frames that aren't sampled
+// cost nothing beyond a few clock reads.
+//
+//   Read    blocked on the FFmpeg pipe for the frame's bytes
+//   Parse   splitting the frame out of the MJPEG stream (mjpeg.go)
+//   Decode  jpeg.Decode
+//   Write   FrameBuffer write
+//
+// Read and Parse cover only the frame itself: a frame the FPS limit
+// skips starts the next frame's timing afresh.
+// =============================================================================
+
+// FrameTrace is the capture-side timing of one sampled frame.
+type FrameTrace struct {
+	Camera  string // Device ID
+	Seq     uint64 // The frame's sequence number in its FrameBuffer
+	Read    time.Duration
+	Parse   time.Duration
+	Decode  time.Duration
+	Write   time.Duration
+	Written time.Time // When the frame was published in the buffer
+}
+
+// frameTiming accumulates the read and parse time of the frame being
+// read (see readMJPEGFrameRaw).
+type frameTiming struct {
+	read, parse time.Duration
+}
@@ -0,0 +1,239 @@
+package camera
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// =============================================================================
+// USB topology diagnostics
+// =============================================================================
+// Maps each camera to its USB bus, port path, hub chain and negotiated
+// speed from sysfs. Every camera on a USB 2.0 bus shares that bus's
+// 480 Mbps with the others, so two MJPEG cameras behind the same
+// USB 2.0 root hub (directly or via hubs) can starve each other even
+// though each one works fine alone. The report flags those buses so the
+// cabling can be rearranged across controllers / USB 3.0 ports.
+//
+// Each camera's descriptor (vendor/product, serial, bcdDevice) is also
+// recorded at discovery so identical models can be told apart.
+//
+// sysfs layout (Pi 4, camera behind a hub on the VL805):
+//   /sys/devices/platform/.../0000:01:00.0/usb1/1-1/1-1.2/1-1.2:1.0
+//                             controller    root hub     camera   interface
+// =============================================================================
+
+// USBDescriptor identifies a USB camera from its device descriptor, so
+// two identical-looking cameras can be told apart in logs and reports.
+type USBDescriptor struct {
+	VendorID     string // idVendor, e.g. "046d"
+	ProductID    string // idProduct, e.g. "0825"
+	Serial       string // iSerialNumber string; many cheap cameras have none
+	BcdDevice    string // Device release (usually the firmware revision), e.g. "0012"
+	Manufacturer string
+	Product      string
+	Port         string // sysfs port name: bus, then the port on each hub ("1-1.2")
+}
+
+// String formats the descriptor as "vendor:product rev bcd serial S".
+func (d USBDescriptor) String() string {
+	if d.VendorID == "" {
+		return "(not USB)"
+	}
+	s := fmt.Sprintf("%s:%s rev %s", d.VendorID, d.ProductID, d.BcdDevice)
+	if d.Serial != "" {
+		s += " serial " + d.Serial
+	} else {
+		s += " (no serial)"
+	}
+	return s
+}
+
+// ReadUSBDescriptor reads the USB descriptor fields of a /dev/videoN
+// device from sysfs. Returns the zero value for non-USB devices.
+func ReadUSBDescriptor(devicePath string) USBDescriptor {
+	dir := usbDeviceDir(devicePath)
+	if dir == "" {
+		return USBDescriptor{}
+	}
+	return USBDescriptor{
+		VendorID:     readSysfsAttr(filepath.Join(dir, "idVendor")),
+		ProductID:    readSysfsAttr(filepath.Join(dir, "idProduct")),
+		Serial:       readSysfsAttr(filepath.Join(dir, "serial")),
+		BcdDevice:    readSysfsAttr(filepath.Join(dir, "bcdDevice")),
+		Manufacturer: readSysfsAttr(filepath.Join(dir, "manufacturer")),
+		Product:      readSysfsAttr(filepath.Join(dir, "product")),
+		Port:         filepath.Base(dir),
+	}
+}
+
+// USBHop is one USB device on the path from the root hub to a camera.
+type USBHop struct {
+	Name    string // sysfs name: "usb1" (root hub), "1-1", "1-1.2", ...
+	ID      string // vendor:product
+	Product string // Product string, if the device reports one
+	Speed   string // Negotiated speed in Mbps as reported by sysfs ("480", "5000", "12")
+}
+
+// USBTopology describes where a camera sits on the USB tree.
+type USBTopology struct {
+	DevicePath string
+	Bus        int      // USB bus number (busnum)
+	Port       string   // Port path below the root hub (devpath), e.g. "1.2"
+	Speed      string   // Camera's negotiated speed in Mbps
+	Controller string   // sysfs name of the host controller (PCI address or platform device)
+	BusSpeed   string   // Root hub speed: "480" = USB 2.0 bus, "5000"+ = USB 3.x bus
+	Hubs       []USBHop // Root hub first, camera excluded
+}
+
+// IsUSB2Bus reports whether the camera's bus is a shared USB 2.0
+// (or slower) bus.
+func (t *USBTopology) IsUSB2Bus() bool {
+	mbps, err := strconv.ParseFloat(t.BusSpeed, 64)
+	return err == nil && mbps <= 480
+}
+
+// ProbeUSBTopology reads the USB topology of a /dev/videoN device from
+// sysfs. Returns nil for non-USB devices.
+func ProbeUSBTopology(devicePath string) *USBTopology {
+	dir := usbDeviceDir(devicePath)
+	if dir == "" {
+		return nil
+	}
+
+	t := &USBTopology{
+		DevicePath: devicePath,
+		Port:       readSysfsAttr(filepath.Join(dir, "devpath")),
+		Speed:      readSysfsAttr(filepath.Join(dir, "speed")),
+	}
+	t.Bus, _ = strconv.Atoi(readSysfsAttr(filepath.Join(dir, "busnum")))
+
+	// Walk up through hubs to the root hub ("usbN")
+	for parent := filepath.Dir(dir); parent != "/" && parent != "."; parent = filepath.Dir(parent) {
+		vendor := readSysfsAttr(filepath.Join(parent, "idVendor"))
+		if vendor == "" {
+			break
+		}
+		hop := USBHop{
+			Name:    filepath.Base(parent),
+			ID:      vendor + ":" + readSysfsAttr(filepath.Join(parent, "idProduct")),
+			Product: readSysfsAttr(filepath.Join(parent, "product")),
+			Speed:   readSysfsAttr(filepath.Join(parent, "speed")),
+		}
+		t.Hubs = append([]USBHop{hop}, t.Hubs...)
+		if strings.HasPrefix(hop.Name, "usb") {
+			t.BusSpeed = hop.Speed
+			t.Controller = filepath.Base(filepath.Dir(parent))
+			break
+		}
+	}
+	return t
+}
+
+// SharedUSB2Buses groups cameras by USB 2.0 bus and returns only the
+// buses carrying more than one camera, keyed by bus number.
+func SharedUSB2Buses(topologies []*USBTopology) map[int][]*USBTopology {
+	byBus := make(map[int][]*USBTopology)
+	for _, t := range topologies {
+		if t != nil && t.IsUSB2Bus() {
+			byBus[t.Bus] = append(byBus[t.Bus], t)
+		}
+	}
+	for bus, cams := range byBus {
+		if len(cams) < 2 {
+			delete(byBus, bus)
+		}
+	}
+	return byBus
+}
+
+// CommonHub returns the sysfs name of the deepest hub every topology
+// hangs off ("1-1", or "usb1" for the root hub), or "" when they share
+// none (different buses) or the list is empty.
+func CommonHub(topologies []*USBTopology) string {
+	var common []USBHop
+	for i, t := range topologies {
+		if t == nil {
+			return ""
+		}
+		if i == 0 {
+			common = t.Hubs
+			continue
+		}
+		n := 0
+		for n < len(common) && n < len(t.Hubs) && common[n].Name == t.Hubs[n].Name {
+			n++
+		}
+		common = common[:n]
+	}
+	if len(common) == 0 {
+		return ""
+	}
+	return common[len(common)-1].Name
+}
+
+// FormatUSBTopologyReport renders each camera's USB descriptor and
+// topology as human-readable lines, followed by a warning per shared
+// USB 2.0 bus.
+func FormatUSBTopologyReport(cameras []Camera) []string {
+	var lines []string
+	var topologies []*USBTopology
+	for _, cam := range cameras {
+		t := ProbeUSBTopology(cam.DevicePath)
+		if t == nil {
+			lines = append(lines, fmt.Sprintf("%s (%s): not a USB device", cam.DeviceID, cam.DevicePath))
+			continue
+		}
+		topologies = append(topologies, t)
+
+		desc := cam.USB
+		if desc.VendorID == "" {
+			desc = ReadUSBDescriptor(cam.DevicePath)
+		}
+
+		chain := make([]string, 0, len(t.Hubs))
+		for _, h := range t.Hubs {
+			hop := h.Name
+			if !strings.HasPrefix(h.Name, "usb") {
+				hop += " [" + h.ID + "]"
+			}
+			chain = append(chain, hop)
+		}
+		lines = append(lines, fmt.Sprintf("%s (%s): %s, bus %d port %s @ %s Mbps via %s, controller %s (%s bus)",
+			cam.DeviceID, cam.DevicePath, desc, t.Bus, t.Port, t.Speed,
+			strings.Join(chain, " > "), t.Controller, busKind(t.BusSpeed)))
+	}
+
+	shared := SharedUSB2Buses(topologies)
+	buses := make([]int, 0, len(shared))
+	for bus := range shared {
+		buses = append(buses, bus)
+	}
+	sort.Ints(buses)
+	for _, bus := range buses {
+		paths := make([]string, 0, len(shared[bus]))
+		for _, t := range shared[bus] {
+			paths = append(paths, t.DevicePath)
+		}
+		lines = append(lines, fmt.Sprintf("WARNING: %s share USB 2.0 bus %d (controller %s, 480 Mbps total); move one to another controller or a USB 3.0 port",
+			strings.Join(paths, ", "), bus, shared[bus][0].Controller))
+	}
+	return lines
+}
+
+func busKind(speed string) string {
+	mbps, err := strconv.ParseFloat(speed, 64)
+	switch {
+	case err != nil:
+		return "unknown"
+	case mbps >= 5000:
+		return "USB 3.x"
+	case mbps >= 480:
+		return "USB 2.0"
+	default:
+		return "USB 1.1"
+	}
+}
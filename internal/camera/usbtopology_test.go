@@ -0,0 +1,149 @@
+package camera
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeUSBDevice creates a sysfs USB device directory with the given
+// attributes.
+func writeUSBDevice(t *testing.T, dir string, attrs map[string]string) {
+	t.Helper()
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for name, value := range attrs {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(value+"\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// fakeTopology builds controller/usbN/N-1 (hub)/N-1.port (camera) and
+// links videoN to the camera's interface.
+func fakeTopology(t *testing.T, root, controller string, bus, busSpeed, port, video string) {
+	t.Helper()
+	rootHub := filepath.Join(root, "devices", controller, "usb"+bus)
+	writeUSBDevice(t, rootHub, map[string]string{"idVendor": "1d6b", "idProduct": "0002", "speed": busSpeed, "busnum": bus})
+	hub := filepath.Join(rootHub, bus+"-1")
+	writeUSBDevice(t, hub, map[string]string{"idVendor": "2109", "idProduct": "3431", "product": "USB2.0 Hub", "speed": "480", "busnum": bus, "devpath": "1"})
+	cam := filepath.Join(hub, bus+"-1."+port)
+	writeUSBDevice(t, cam, map[string]string{
+		"idVendor": "046d", "idProduct": "0825", "bcdDevice": "0012", "serial": "SN" + video,
+		"speed": "480", "busnum": bus, "devpath": "1." + port,
+	})
+	iface := filepath.Join(cam, bus+"-1."+port+":1.0")
+	if err := os.MkdirAll(iface, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	classDir := filepath.Join(root, "class", "video4linux", video)
+	if err := os.MkdirAll(classDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(iface, filepath.Join(classDir, "device")); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestProbeUSBTopology(t *testing.T) {
+	root := t.TempDir()
+	fakeTopology(t, root, "0000:01:00.0", "1", "480", "2", "video0")
+	withSysfsRoot(t, root)
+
+	topo := ProbeUSBTopology("/dev/video0")
+	if topo == nil {
+		t.Fatal("ProbeUSBTopology returned nil for a USB camera")
+	}
+	if topo.Bus != 1 || topo.Port != "1.2" || topo.Speed != "480" {
+		t.Errorf("bus/port/speed = %d/%s/%s, want 1/1.2/480", topo.Bus, topo.Port, topo.Speed)
+	}
+	if topo.Controller != "0000:01:00.0" || !topo.IsUSB2Bus() {
+		t.Errorf("controller = %q usb2 = %v, want 0000:01:00.0 on a USB 2.0 bus", topo.Controller, topo.IsUSB2Bus())
+	}
+	if len(topo.Hubs) != 2 || topo.Hubs[0].Name != "usb1" || topo.Hubs[1].ID != "2109:3431" {
+		t.Errorf("hub chain = %+v, want usb1 > 1-1 [2109:3431]", topo.Hubs)
+	}
+
+	if ProbeUSBTopology("/dev/video9") != nil {
+		t.Error("missing device should have no topology")
+	}
+}
+
+func TestReadUSBDescriptor(t *testing.T) {
+	root := t.TempDir()
+	fakeTopology(t, root, "0000:01:00.0", "1", "480", "2", "video0")
+	withSysfsRoot(t, root)
+
+	d := ReadUSBDescriptor("/dev/video0")
+	want := USBDescriptor{VendorID: "046d", ProductID: "0825", Serial: "SNvideo0", BcdDevice: "0012", Port: "1-1.2"}
+	if d != want {
+		t.Errorf("descriptor = %+v, want %+v", d, want)
+	}
+	if got := d.String(); got != "046d:0825 rev 0012 serial SNvideo0" {
+		t.Errorf("String() = %q", got)
+	}
+	if got := ReadUSBDescriptor("/dev/video9").String(); got != "(not USB)" {
+		t.Errorf("non-USB String() = %q", got)
+	}
+}
+
+func TestUSBTopologyReport_FlagsSharedUSB2Bus(t *testing.T) {
+	root := t.TempDir()
+	fakeTopology(t, root, "0000:01:00.0", "1", "480", "1", "video0")
+	fakeTopology(t, root, "0000:01:00.0", "1", "480", "2", "video2")
+	fakeTopology(t, root, "0000:01:00.0", "2", "5000", "1", "video4")
+	withSysfsRoot(t, root)
+
+	cams := []Camera{
+		{DeviceID: "video0", DevicePath: "/dev/video0"},
+		{DeviceID: "video2", DevicePath: "/dev/video2"},
+		{DeviceID: "video4", DevicePath: "/dev/video4"},
+		{DeviceID: "video8", DevicePath: "/dev/video8"},
+	}
+	lines := FormatUSBTopologyReport(cams)
+	if len(lines) != 5 {
+		t.Fatalf("report = %q, want 4 camera lines + 1 warning", lines)
+	}
+	if !strings.Contains(lines[0], "046d:0825 rev 0012 serial SNvideo0") {
+		t.Errorf("video0 line = %q, want its USB descriptor", lines[0])
+	}
+	if !strings.Contains(lines[2], "USB 3.x bus") {
+		t.Errorf("video4 line = %q, want USB 3.x bus", lines[2])
+	}
+	if !strings.Contains(lines[3], "not a USB device") {
+		t.Errorf("video8 line = %q, want not a USB device", lines[3])
+	}
+	warning := lines[4]
+	if !strings.HasPrefix(warning, "WARNING:") || !strings.Contains(warning, "/dev/video0, /dev/video2") ||
+		strings.Contains(warning, "video4") {
+		t.Errorf("warning = %q, want video0+video2 on bus 1 only", warning)
+	}
+}
+
+func TestCommonHub(t *testing.T) {
+	hops := func(names ...string) *USBTopology {
+		t := &USBTopology{}
+		for _, n := range names {
+			t.Hubs = append(t.Hubs, USBHop{Name: n})
+		}
+		return t
+	}
+	tests := []struct {
+		name string
+		tops []*USBTopology
+		want string
+	}{
+		{"same hub", []*USBTopology{hops("usb1", "1-1", "1-1.2"), hops("usb1", "1-1", "1-1.3")}, "1-1"},
+		{"root hub only", []*USBTopology{hops("usb1", "1-1"), hops("usb1", "1-2")}, "usb1"},
+		{"different buses", []*USBTopology{hops("usb1", "1-1"), hops("usb2", "2-1")}, ""},
+		{"unknown camera", []*USBTopology{hops("usb1"), nil}, ""},
+		{"empty", nil, ""},
+	}
+	for _, tt := range tests {
+		if got := CommonHub(tt.tops); got != tt.want {
+			t.Errorf("%s: CommonHub = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
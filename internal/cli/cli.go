@@ -0,0 +1,229 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// =============================================================================
+// Subcommands
+// =============================================================================
+// camera-dashboard <command> [flags] [args], with command groups one level
+// deep (camera-dashboard config validate). Each command gets its own
+// flag.FlagSet; flags every command shares (-config) are registered on
+// each set by App.Flags. Arguments that start with a flag run the default
+// command, so `camera-dashboard -headless` keeps working as
+// `camera-dashboard run -headless`.
+//
+// Exit status: 0 on success, 1 when a command returns an error, 2 for
+// usage errors (unknown command, bad flag).
+// =============================================================================
+
+// Command is a subcommand, or a group of them when Commands is set.
+type Command struct {
+	Name    string
+	Args    string // Positional arguments for the usage line, e.g. "<file>"
+	Summary string // One line for the command list
+
+	// Flags registers the command's own flags (optional). Run reads them
+	// through the variables they were bound to.
+	Flags func(fs *flag.FlagSet)
+	// Run runs the command with the arguments left after the flags.
+	Run func(args []string) error
+
+	Commands []*Command // Subcommands of a group
+}
+
+// App is a program made of commands.
+type App struct {
+	Name     string
+	Commands []*Command
+	Default  string                 // Command for no arguments or leading flags
+	Flags    func(fs *flag.FlagSet) // Flags shared by every command (optional)
+
+	Stdout, Stderr io.Writer // nil = os.Stdout, os.Stderr
+}
+
+// Run dispatches args (without the program name) and returns the exit
+// status.
+func (a *App) Run(args []string) int {
+	if len(args) > 0 {
+		switch args[0] {
+		case "help", "-h", "-help", "--help":
+			return a.help(args[1:])
+		}
+	}
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		if cmd := find(a.Commands, a.Default); cmd != nil {
+			return a.run(cmd, cmd.Name, args)
+		}
+		a.usage(a.stderr())
+		return 2
+	}
+
+	cmd := find(a.Commands, args[0])
+	if cmd == nil {
+		fmt.Fprintf(a.stderr(), "%s: unknown command %q\n\n", a.Name, args[0])
+		a.usage(a.stderr())
+		return 2
+	}
+	path := cmd.Name
+	args = args[1:]
+	if len(cmd.Commands) > 0 {
+		if len(args) == 0 {
+			a.groupUsage(a.stderr(), cmd)
+			return 2
+		}
+		sub := find(cmd.Commands, args[0])
+		if sub == nil {
+			fmt.Fprintf(a.stderr(), "%s %s: unknown command %q\n\n", a.Name, cmd.Name, args[0])
+			a.groupUsage(a.stderr(), cmd)
+			return 2
+		}
+		cmd, path, args = sub, path+" "+sub.Name, args[1:]
+	}
+	return a.run(cmd, path, args)
+}
+
+// run parses the command's flags and runs it.
+func (a *App) run(cmd *Command, path string, args []string) int {
+	fs := a.flagSet(cmd, path)
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2 // The flag package printed the error and the usage
+	}
+	if err := cmd.Run(fs.Args()); err != nil {
+		fmt.Fprintf(a.stderr(), "%s %s: %v\n", a.Name, path, err)
+		return 1
+	}
+	return 0
+}
+
+func (a *App) flagSet(cmd *Command, path string) *flag.FlagSet {
+	fs := flag.NewFlagSet(a.Name+" "+path, flag.ContinueOnError)
+	fs.SetOutput(a.stderr())
+	if a.Flags != nil {
+		a.Flags(fs)
+	}
+	if cmd.Flags != nil {
+		cmd.Flags(fs)
+	}
+	fs.Usage = func() {
+		w := fs.Output()
+		fmt.Fprintf(w, "Usage: %s %s [flags]", a.Name, path)
+		if cmd.Args != "" {
+			fmt.Fprintf(w, " %s", cmd.Args)
+		}
+		fmt.Fprintf(w, "\n\n%s\n", cmd.Summary)
+		if hasFlags(fs) {
+			fmt.Fprintf(w, "\nFlags:\n")
+			fs.PrintDefaults()
+		}
+	}
+	return fs
+}
+
+// help prints the command list, or a command's usage.
+func (a *App) help(args []string) int {
+	if len(args) == 0 {
+		a.usage(a.stdout())
+		return 0
+	}
+	cmd := find(a.Commands, args[0])
+	if cmd == nil {
+		fmt.Fprintf(a.stderr(), "%s: unknown command %q\n", a.Name, args[0])
+		return 2
+	}
+	path := cmd.Name
+	if len(cmd.Commands) > 0 {
+		if len(args) < 2 {
+			a.groupUsage(a.stdout(), cmd)
+			return 0
+		}
+		if cmd = find(cmd.Commands, args[1]); cmd == nil {
+			fmt.Fprintf(a.stderr(), "%s %s: unknown command %q\n", a.Name, path, args[1])
+			return 2
+		}
+		path += " " + cmd.Name
+	}
+	fs := a.flagSet(cmd, path)
+	fs.SetOutput(a.stdout())
+	fs.Usage()
+	return 0
+}
+
+func (a *App) usage(w io.Writer) {
+	fmt.Fprintf(w, "Usage: %s <command> [flags] [args]\n\nCommands:\n", a.Name)
+	var names, summaries []string
+	for _, cmd := range a.Commands {
+		if len(cmd.Commands) == 0 {
+			summary := cmd.Summary
+			if cmd.Name == a.Default {
+				summary += " (default)"
+			}
+			names, summaries = append(names, cmd.Name), append(summaries, summary)
+			continue
+		}
+		for _, sub := range cmd.Commands {
+			names, summaries = append(names, cmd.Name+" "+sub.Name), append(summaries, sub.Summary)
+		}
+	}
+	printTable(w, names, summaries)
+	fmt.Fprintf(w, "\nRun '%s help <command>' for a command's flags.\n", a.Name)
+}
+
+func (a *App) groupUsage(w io.Writer, group *Command) {
+	fmt.Fprintf(w, "Usage: %s %s <command> [flags] [args]\n\n%s\n\nCommands:\n", a.Name, group.Name, group.Summary)
+	var names, summaries []string
+	for _, sub := range group.Commands {
+		names, summaries = append(names, sub.Name), append(summaries, sub.Summary)
+	}
+	printTable(w, names, summaries)
+}
+
+func printTable(w io.Writer, names, summaries []string) {
+	width := 0
+	for _, name := range names {
+		if len(name) > width {
+			width = len(name)
+		}
+	}
+	for i, name := range names {
+		fmt.Fprintf(w, "  %-*s  %s\n", width, name, summaries[i])
+	}
+}
+
+func find(cmds []*Command, name string) *Command {
+	for _, cmd := range cmds {
+		if cmd.Name == name {
+			return cmd
+		}
+	}
+	return nil
+}
+
+func hasFlags(fs *flag.FlagSet) bool {
+	found := false
+	fs.VisitAll(func(*flag.Flag) { found = true })
+	return found
+}
+
+func (a *App) stdout() io.Writer {
+	if a.Stdout == nil {
+		return os.Stdout
+	}
+	return a.Stdout
+}
+
+func (a *App) stderr() io.Writer {
+	if a.Stderr == nil {
+		return os.Stderr
+	}
+	return a.Stderr
+}
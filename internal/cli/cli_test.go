@@ -0,0 +1,137 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"strings"
+	"testing"
+)
+
+// testApp records which command ran, with what flags and arguments.
+type testApp struct {
+	App
+	ran      string
+	args     []string
+	config   string
+	headless bool
+	stdout   bytes.Buffer
+	stderr   bytes.Buffer
+}
+
+func newTestApp() *testApp {
+	t := &testApp{}
+	record := func(name string) func([]string) error {
+		return func(args []string) error {
+			t.ran, t.args = name, args
+			return nil
+		}
+	}
+	t.App = App{
+		Name:    "dash",
+		Default: "run",
+		Flags:   func(fs *flag.FlagSet) { fs.StringVar(&t.config, "config", "", "config file") },
+		Commands: []*Command{
+			{
+				Name:    "run",
+				Summary: "Start",
+				Flags:   func(fs *flag.FlagSet) { fs.BoolVar(&t.headless, "headless", false, "no window") },
+				Run:     record("run"),
+			},
+			{Name: "fail", Summary: "Fails", Run: func([]string) error { return errors.New("boom") }},
+			{
+				Name:    "config",
+				Summary: "Config file tools",
+				Commands: []*Command{
+					{Name: "validate", Summary: "Check the config", Run: record("config validate")},
+					{Name: "dump", Summary: "Print the config", Run: record("config dump")},
+				},
+			},
+		},
+	}
+	t.App.Stdout, t.App.Stderr = &t.stdout, &t.stderr
+	return t
+}
+
+func TestRun_Dispatch(t *testing.T) {
+	tests := []struct {
+		args     []string
+		ran      string
+		config   string
+		headless bool
+		rest     []string
+	}{
+		{nil, "run", "", false, nil},
+		{[]string{"-headless"}, "run", "", true, nil},
+		{[]string{"-config", "a.ini", "-headless"}, "run", "a.ini", true, nil},
+		{[]string{"run", "-headless"}, "run", "", true, nil},
+		{[]string{"config", "validate", "-config", "b.ini"}, "config validate", "b.ini", false, nil},
+		{[]string{"config", "dump", "x", "y"}, "config dump", "", false, []string{"x", "y"}},
+	}
+	for _, tt := range tests {
+		app := newTestApp()
+		if code := app.Run(tt.args); code != 0 {
+			t.Errorf("%v: exit %d, stderr %q", tt.args, code, app.stderr.String())
+			continue
+		}
+		if app.ran != tt.ran || app.config != tt.config || app.headless != tt.headless {
+			t.Errorf("%v: ran %q config %q headless %v, want %q %q %v",
+				tt.args, app.ran, app.config, app.headless, tt.ran, tt.config, tt.headless)
+		}
+		if strings.Join(app.args, " ") != strings.Join(tt.rest, " ") {
+			t.Errorf("%v: args %v, want %v", tt.args, app.args, tt.rest)
+		}
+	}
+}
+
+func TestRun_Errors(t *testing.T) {
+	tests := []struct {
+		args []string
+		code int
+		want string // In stderr
+	}{
+		{[]string{"fail"}, 1, "dash fail: boom"},
+		{[]string{"nope"}, 2, `unknown command "nope"`},
+		{[]string{"config"}, 2, "validate"},
+		{[]string{"config", "nope"}, 2, `dash config: unknown command "nope"`},
+		{[]string{"run", "-bogus"}, 2, "flag provided but not defined: -bogus"},
+		{[]string{"config", "dump", "-headless"}, 2, "flag provided but not defined"},
+	}
+	for _, tt := range tests {
+		app := newTestApp()
+		if code := app.Run(tt.args); code != tt.code {
+			t.Errorf("%v: exit %d, want %d", tt.args, code, tt.code)
+		}
+		if !strings.Contains(app.stderr.String(), tt.want) {
+			t.Errorf("%v: stderr %q, want %q", tt.args, app.stderr.String(), tt.want)
+		}
+		if app.ran != "" {
+			t.Errorf("%v: ran %q", tt.args, app.ran)
+		}
+	}
+}
+
+func TestRun_Help(t *testing.T) {
+	app := newTestApp()
+	if code := app.Run([]string{"help"}); code != 0 {
+		t.Fatalf("exit %d", code)
+	}
+	for _, want := range []string{"run              Start (default)", "config validate  Check the config", "config dump"} {
+		if !strings.Contains(app.stdout.String(), want) {
+			t.Errorf("help = %q, want %q", app.stdout.String(), want)
+		}
+	}
+
+	app = newTestApp()
+	app.Run([]string{"help", "run"})
+	for _, want := range []string{"Usage: dash run [flags]", "-headless", "-config"} {
+		if !strings.Contains(app.stdout.String(), want) {
+			t.Errorf("help run = %q, want %q", app.stdout.String(), want)
+		}
+	}
+
+	app = newTestApp()
+	if code := app.Run([]string{"run", "-h"}); code != 0 || app.ran != "" {
+		t.Errorf("run -h: exit %d, ran %q; want usage only", code, app.ran)
+	}
+}
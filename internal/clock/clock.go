@@ -0,0 +1,78 @@
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// =============================================================================
+// Clock
+// =============================================================================
+// Stale-frame detection, restart windows, reconnect debounce, stream
+// health and the FPS controller decide on elapsed time. They read it
+// from a Clock instead of calling time.Now, so their tests can step a
+// Fake clock through cooldowns and windows instead of sleeping:
+//
+//   clk := clock.NewFake(time.Unix(0, 0))
+//   ... a.clk = clk                 // or Settings.Clock, SetClock
+//   clk.Advance(6 * time.Second)    // past the restart cooldown
+//
+// System is the real clock. I/O deadlines (frame read timeouts, process
+// and HTTP timeouts) and the watchdog heartbeats stay on time.Now: they
+// guard against real hangs, which a frozen fake clock would hide.
+//
+// Not the time of day: recording names and report times come from
+// timesync.Clock, which corrects a Clock's readings from GPS or NTP.
+// =============================================================================
+
+// Clock tells the time.
+type Clock interface {
+	Now() time.Time
+}
+
+// System is the real clock (time.Now).
+var System Clock = systemClock{}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+// Or returns c, or System when c is nil (a zero-value setting).
+func Or(c Clock) Clock {
+	if c == nil {
+		return System
+	}
+	return c
+}
+
+// Fake is a Clock that only moves when told to. Safe for concurrent use.
+type Fake struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// NewFake returns a Fake clock reading start.
+func NewFake(start time.Time) *Fake {
+	return &Fake{now: start}
+}
+
+// Now returns the fake time.
+func (f *Fake) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+// Advance moves the clock forward by d.
+func (f *Fake) Advance(d time.Duration) {
+	f.mu.Lock()
+	f.now = f.now.Add(d)
+	f.mu.Unlock()
+}
+
+// Set moves the clock to t, backwards too (a wall-clock step).
+func (f *Fake) Set(t time.Time) {
+	f.mu.Lock()
+	f.now = t
+	f.mu.Unlock()
+}
@@ -0,0 +1,32 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFake(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	f := NewFake(start)
+	if !f.Now().Equal(start) {
+		t.Fatalf("Now = %v, want %v", f.Now(), start)
+	}
+	f.Advance(1500 * time.Millisecond)
+	if got := f.Now().Sub(start); got != 1500*time.Millisecond {
+		t.Errorf("after Advance: %v since start, want 1.5s", got)
+	}
+	f.Set(start.Add(-time.Hour)) // Wall clock stepped back
+	if got := f.Now().Sub(start); got != -time.Hour {
+		t.Errorf("after Set: %v since start, want -1h", got)
+	}
+}
+
+func TestOr(t *testing.T) {
+	if Or(nil) != System {
+		t.Error("Or(nil) is not System")
+	}
+	f := NewFake(time.Unix(0, 0))
+	if Or(f) != f {
+		t.Error("Or(fake) replaced the fake")
+	}
+}
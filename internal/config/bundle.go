@@ -0,0 +1,155 @@
+package config
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// =============================================================================
+// Setup bundles
+// =============================================================================
+// A bundle is a .tar.gz holding everything needed to clone a dashboard
+// to another vehicle or restore it after an SD card failure: config.ini
+// (including [layouts] and disabled cameras), the camera capability
+// cache, and a list of the cameras that were attached. main's
+// -export-bundle / -import-bundle decide what goes in; this file only
+// reads and writes the archive.
+// =============================================================================
+
+// Bundle entry names.
+const (
+	BundleConfigFile  = "config.ini"
+	BundleCapsFile    = "camera_caps.json"
+	BundleCamerasFile = "cameras.json"
+
+	bundleManifestFile = "manifest.json"
+	bundleVersion      = 1
+	maxBundleEntry     = 16 << 20 // Larger entries are rejected, not truncated
+)
+
+// BundleManifest describes a bundle.
+type BundleManifest struct {
+	Version int       `json:"version"`
+	Created time.Time `json:"created"`
+	Host    string    `json:"host"`
+	Files   []string  `json:"files"`
+}
+
+// WriteBundle writes files (entry name -> contents) to a new bundle at path.
+func WriteBundle(path string, files map[string][]byte) error {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	host, _ := os.Hostname()
+	manifest, err := json.MarshalIndent(BundleManifest{
+		Version: bundleVersion,
+		Created: time.Now().UTC(),
+		Host:    host,
+		Files:   names,
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("config: create bundle: %w", err)
+	}
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	write := func(name string, data []byte) error {
+		hdr := &tar.Header{Name: name, Mode: 0o644, Size: int64(len(data)), ModTime: time.Now()}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		_, err := tw.Write(data)
+		return err
+	}
+
+	err = write(bundleManifestFile, manifest)
+	for _, name := range names {
+		if err != nil {
+			break
+		}
+		err = write(name, files[name])
+	}
+	if cerr := tw.Close(); err == nil {
+		err = cerr
+	}
+	if cerr := gz.Close(); err == nil {
+		err = cerr
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(path)
+		return fmt.Errorf("config: write bundle %s: %w", path, err)
+	}
+	return nil
+}
+
+// ReadBundle reads a bundle written by WriteBundle. Entries with path
+// components are rejected so a bundle can't name files outside the
+// directories the caller writes to.
+func ReadBundle(path string) (BundleManifest, map[string][]byte, error) {
+	var manifest BundleManifest
+	f, err := os.Open(path)
+	if err != nil {
+		return manifest, nil, fmt.Errorf("config: open bundle: %w", err)
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return manifest, nil, fmt.Errorf("config: %s is not a bundle: %w", path, err)
+	}
+	defer gz.Close()
+
+	files := make(map[string][]byte)
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return manifest, nil, fmt.Errorf("config: read bundle %s: %w", path, err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		if hdr.Name != filepath.Base(hdr.Name) {
+			return manifest, nil, fmt.Errorf("config: bundle entry %q has a path", hdr.Name)
+		}
+		if hdr.Size > maxBundleEntry {
+			return manifest, nil, fmt.Errorf("config: bundle entry %s is %d bytes (limit %d)", hdr.Name, hdr.Size, maxBundleEntry)
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return manifest, nil, fmt.Errorf("config: read bundle entry %s: %w", hdr.Name, err)
+		}
+		files[hdr.Name] = data
+	}
+
+	data, ok := files[bundleManifestFile]
+	if !ok {
+		return manifest, nil, fmt.Errorf("config: %s has no %s", path, bundleManifestFile)
+	}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return manifest, nil, fmt.Errorf("config: bad bundle manifest: %w", err)
+	}
+	if manifest.Version > bundleVersion {
+		return manifest, nil, fmt.Errorf("config: bundle version %d is newer than supported (%d)", manifest.Version, bundleVersion)
+	}
+	delete(files, bundleManifestFile)
+	return manifest, files, nil
+}
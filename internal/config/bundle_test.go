@@ -0,0 +1,63 @@
+package config
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestBundleRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "setup.tar.gz")
+	files := map[string][]byte{
+		BundleConfigFile:  []byte("[profile]\nui_fps = 15\n"),
+		BundleCamerasFile: []byte(`[{"device_path":"/dev/video0"}]`),
+	}
+	if err := WriteBundle(path, files); err != nil {
+		t.Fatalf("WriteBundle: %v", err)
+	}
+
+	manifest, got, err := ReadBundle(path)
+	if err != nil {
+		t.Fatalf("ReadBundle: %v", err)
+	}
+	if !reflect.DeepEqual(got, files) {
+		t.Errorf("files = %q, want %q", got, files)
+	}
+	if manifest.Version != bundleVersion || !reflect.DeepEqual(manifest.Files, []string{BundleCamerasFile, BundleConfigFile}) {
+		t.Errorf("manifest = %+v", manifest)
+	}
+}
+
+func TestReadBundle_RejectsPaths(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "evil.tar.gz")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	data := []byte("x")
+	tw.WriteHeader(&tar.Header{Name: "../config.ini", Mode: 0o644, Size: int64(len(data)), Typeflag: tar.TypeReg})
+	tw.Write(data)
+	tw.Close()
+	gz.Close()
+	f.Close()
+
+	if _, _, err := ReadBundle(path); err == nil {
+		t.Error("entry with a path should be rejected")
+	}
+}
+
+func TestReadBundle_RejectsOversizedEntries(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "big.tar.gz")
+	files := map[string][]byte{BundleCapsFile: make([]byte, maxBundleEntry+1)}
+	if err := WriteBundle(path, files); err != nil {
+		t.Fatalf("WriteBundle: %v", err)
+	}
+	if _, _, err := ReadBundle(path); err == nil {
+		t.Error("an entry over the limit should be rejected, not truncated")
+	}
+}
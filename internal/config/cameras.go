@@ -0,0 +1,218 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// =============================================================================
+// Per-camera sections ([camera.<name>])
+// =============================================================================
+// A [camera.rear] section gives the cameras it matches their own capture
+// settings, on top of [profile]:
+//
+//   [camera.rear]
+//   serial = A1B2C3D4
+//   width = 1280
+//   height = 720
+//   fps = 15
+//   transform = mirror
+//   name = Rear
+//   priority = 10
+//
+// device (path or ID), usb_port (sysfs port name, "1-1.2") and serial
+// select the cameras; a camera matching any of them matches the section,
+// and the first section in name order wins. transform and name are added
+// to [transform] and [names] under those matches, so they reload like
+// the entries there (which win for the same match). priority ranks the
+// camera for the USB bandwidth scheduler, highest first, ahead of
+// [bandwidth] priority. The capture keys need a restart.
+// =============================================================================
+
+const cameraSectionPrefix = "camera."
+
+// cameraSectionKeys are the keys of a [camera.<name>] section.
+var cameraSectionKeys = []string{
+	"device", "usb_port", "serial",
+	"width", "height", "fps", "format",
+	"transform", "name", "priority",
+}
+
+// CameraSection is one [camera.<name>] section. Zero capture fields keep
+// the [profile] value.
+type CameraSection struct {
+	Name          string   // "rear" of [camera.rear]
+	Match         []string // Camera matches: device, port:<usb_port>, serial:<serial>
+	Width, Height int
+	FPS           int
+	Format        string // "mjpeg" or "yuyv"
+	Priority      int    // Bandwidth priority, higher first; 0 = unranked
+}
+
+// isCameraSection reports whether section is a [camera.<name>] section.
+func isCameraSection(section string) bool {
+	return strings.HasPrefix(section, cameraSectionPrefix) && len(section) > len(cameraSectionPrefix)
+}
+
+// subsections returns the sections of ini named prefix + something, sorted.
+func subsections(ini iniData, prefix string) []string {
+	var sections []string
+	for section := range ini {
+		if strings.HasPrefix(section, prefix) && len(section) > len(prefix) {
+			sections = append(sections, section)
+		}
+	}
+	sort.Strings(sections)
+	return sections
+}
+
+// applyCameraSections reads the [camera.<name>] sections into
+// cfg.CameraSections and adds their transform and name entries to
+// CameraTransforms and CameraNames.
+func applyCameraSections(cfg *Config, ini iniData) {
+	cfg.CameraSections = nil
+	for _, section := range subsections(ini, cameraSectionPrefix) {
+		values := ini[section]
+		cs := CameraSection{Name: strings.TrimPrefix(section, cameraSectionPrefix)}
+		if v := strings.TrimSpace(values["device"]); v != "" {
+			cs.Match = append(cs.Match, v)
+		}
+		if v := strings.TrimSpace(values["usb_port"]); v != "" {
+			cs.Match = append(cs.Match, "port:"+v)
+		}
+		if v := strings.TrimSpace(values["serial"]); v != "" {
+			cs.Match = append(cs.Match, "serial:"+v)
+		}
+		cs.Width = asInt(values["width"], 0, intPtr(160), intPtr(1920))
+		cs.Height = asInt(values["height"], 0, intPtr(120), intPtr(1080))
+		cs.FPS = asInt(values["fps"], 0, intPtr(1), intPtr(60))
+		if v := strings.ToLower(strings.TrimSpace(values["format"])); v == "mjpeg" || v == "yuyv" {
+			cs.Format = v
+		}
+		cs.Priority = asInt(values["priority"], 0, intPtr(0), nil)
+		cfg.CameraSections = append(cfg.CameraSections, cs)
+
+		cfg.CameraTransforms = addCameraEntries(cfg.CameraTransforms, cs.Match, strings.TrimSpace(values["transform"]))
+		cfg.CameraNames = addCameraEntries(cfg.CameraNames, cs.Match, strings.TrimSpace(values["name"]))
+	}
+}
+
+// addCameraEntries sets m[match] = value for the matches m doesn't have
+// yet. An empty value adds nothing.
+func addCameraEntries(m map[string]string, matches []string, value string) map[string]string {
+	if value == "" {
+		return m
+	}
+	for _, match := range matches {
+		if _, ok := m[match]; ok {
+			continue
+		}
+		if m == nil {
+			m = make(map[string]string)
+		}
+		m[match] = value
+	}
+	return m
+}
+
+// checkCameraSections reports [camera.<name>] sections that match no
+// camera and values applyCameraSections couldn't use as written.
+func checkCameraSections(cfg *Config, ini iniData) []Warning {
+	var warnings []Warning
+	for _, cs := range cfg.CameraSections {
+		section := cameraSectionPrefix + cs.Name
+		values := ini[section]
+		if len(cs.Match) == 0 {
+			warnings = append(warnings, Warning{Section: section, Message: "sets no device, usb_port or serial, matches no camera"})
+		}
+		for _, k := range []struct {
+			key   string
+			value int
+		}{
+			{"width", cs.Width}, {"height", cs.Height}, {"fps", cs.FPS}, {"priority", cs.Priority},
+		} {
+			raw := strings.TrimSpace(values[k.key])
+			if raw == "" || sameValue(raw, strconv.Itoa(k.value)) {
+				continue
+			}
+			problem := "is out of range, using " + strconv.Itoa(k.value)
+			if _, err := strconv.Atoi(raw); err != nil {
+				problem = "is not a whole number, using [profile]"
+				if k.key == "priority" {
+					problem = "is not a whole number, ignored"
+				}
+			}
+			warnings = append(warnings, Warning{Section: section, Key: k.key, Message: fmt.Sprintf("%q %s", raw, problem)})
+		}
+		if raw := strings.TrimSpace(values["format"]); raw != "" && cs.Format == "" {
+			warnings = append(warnings, Warning{Section: section, Key: "format", Message: fmt.Sprintf("%q is not mjpeg or yuyv, using [profile]", raw)})
+		}
+	}
+	return warnings
+}
+
+// cameraSectionSettings lists the keys the [camera.<name>] sections set,
+// with their effective values, for Inspect.
+func cameraSectionSettings(cfg *Config, ini iniData, envVars map[string]string) []Setting {
+	var settings []Setting
+	for _, cs := range cfg.CameraSections {
+		section := cameraSectionPrefix + cs.Name
+		for _, key := range cameraSectionKeys {
+			raw, set := ini.get(section, key)
+			if !set {
+				continue
+			}
+			value := strings.TrimSpace(raw)
+			switch key {
+			case "width":
+				value = formatCount(cs.Width)
+			case "height":
+				value = formatCount(cs.Height)
+			case "fps":
+				value = formatCount(cs.FPS)
+			case "priority":
+				value = formatCount(cs.Priority)
+			case "format":
+				value = cs.Format
+			}
+			settings = append(settings, Setting{
+				Section: section,
+				Key:     key,
+				Value:   value,
+				Raw:     raw,
+				Set:     true,
+				Env:     envVars[section+"."+key],
+			})
+		}
+	}
+	return settings
+}
+
+// formatCount formats n, or "" for 0 (not set).
+func formatCount(n int) string {
+	if n == 0 {
+		return ""
+	}
+	return strconv.Itoa(n)
+}
+
+// BandwidthPriorityOrder returns the cameras in the USB bandwidth
+// scheduler's priority order: the matches of [camera.<name>] sections
+// with a priority, highest first, then [bandwidth] priority.
+func (c *Config) BandwidthPriorityOrder() []string {
+	sections := make([]CameraSection, 0, len(c.CameraSections))
+	for _, cs := range c.CameraSections {
+		if cs.Priority != 0 {
+			sections = append(sections, cs)
+		}
+	}
+	sort.SliceStable(sections, func(i, j int) bool { return sections[i].Priority > sections[j].Priority })
+
+	var order []string
+	for _, cs := range sections {
+		order = append(order, cs.Match...)
+	}
+	return append(order, c.BandwidthPriority...)
+}
@@ -0,0 +1,119 @@
+package config
+
+import (
+	"bytes"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLoad_CameraSections(t *testing.T) {
+	cfg, err := Load(writeTempFile(t, `[names]
+/dev/video0 = Reversing
+
+[camera.rear]
+device = /dev/video0
+serial = A1B2C3D4
+width = 1280
+height = 720
+fps = 15
+format = YUYV
+transform = mirror
+name = Rear
+priority = 10
+
+[camera.left]
+usb_port = 1-1.2
+fps = 99
+format = h264
+priority = 2
+
+[camera.spare]
+width = wide
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []CameraSection{
+		{Name: "left", Match: []string{"port:1-1.2"}, FPS: 60, Priority: 2},
+		{Name: "rear", Match: []string{"/dev/video0", "serial:A1B2C3D4"}, Width: 1280, Height: 720, FPS: 15, Format: "yuyv", Priority: 10},
+		{Name: "spare"},
+	}
+	if !reflect.DeepEqual(cfg.CameraSections, want) {
+		t.Errorf("CameraSections = %+v, want %+v", cfg.CameraSections, want)
+	}
+
+	// [names] wins for the same match
+	wantNames := map[string]string{"/dev/video0": "Reversing", "serial:A1B2C3D4": "Rear"}
+	if !reflect.DeepEqual(cfg.CameraNames, wantNames) {
+		t.Errorf("CameraNames = %v, want %v", cfg.CameraNames, wantNames)
+	}
+	wantTransforms := map[string]string{"/dev/video0": "mirror", "serial:A1B2C3D4": "mirror"}
+	if !reflect.DeepEqual(cfg.CameraTransforms, wantTransforms) {
+		t.Errorf("CameraTransforms = %v, want %v", cfg.CameraTransforms, wantTransforms)
+	}
+
+	var warnings []string
+	for _, w := range cfg.Warnings {
+		warnings = append(warnings, w.String())
+	}
+	wantWarnings := []string{
+		`[camera.left] fps: "99" is out of range, using 60`,
+		`[camera.left] format: "h264" is not mjpeg or yuyv, using [profile]`,
+		`[camera.spare]: sets no device, usb_port or serial, matches no camera`,
+		`[camera.spare] width: "wide" is not a whole number, using [profile]`,
+	}
+	if !reflect.DeepEqual(warnings, wantWarnings) {
+		t.Errorf("warnings = %q, want %q", warnings, wantWarnings)
+	}
+}
+
+func TestLoad_CameraSectionUnknownKey(t *testing.T) {
+	cfg, err := Load(writeTempFile(t, "[camera.rear]\ndevice = video0\nfsp = 15\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "line 3: [camera.rear] fsp: unknown key, ignored (did you mean fps?)"
+	if len(cfg.Warnings) != 1 || cfg.Warnings[0].String() != want {
+		t.Errorf("warnings = %v, want %q", cfg.Warnings, want)
+	}
+}
+
+func TestBandwidthPriorityOrder(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.BandwidthPriority = []string{"video4"}
+	cfg.CameraSections = []CameraSection{
+		{Name: "left", Match: []string{"video2"}, Priority: 1},
+		{Name: "rear", Match: []string{"video0", "serial:A1"}, Priority: 5},
+		{Name: "spare", Match: []string{"video6"}},
+	}
+	want := []string{"video0", "serial:A1", "video2", "video4"}
+	if got := cfg.BandwidthPriorityOrder(); !reflect.DeepEqual(got, want) {
+		t.Errorf("BandwidthPriorityOrder() = %v, want %v", got, want)
+	}
+}
+
+// config dump keeps the sections and doesn't copy their names into [names].
+func TestWriteINI_CameraSections(t *testing.T) {
+	template, err := os.ReadFile("../../config.ini")
+	if err != nil {
+		t.Fatal(err)
+	}
+	in, err := Inspect(writeTempFile(t, "[camera.rear]\nserial = A1B2C3D4\nfps = 15\nname = Rear\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := in.WriteINI(&buf, template); err != nil {
+		t.Fatal(err)
+	}
+	dump := buf.String()
+	if !strings.Contains(dump, "\n[camera.rear]\nserial = A1B2C3D4\nfps = 15\nname = Rear\n") {
+		t.Errorf("dump lacks the [camera.rear] section:\n%s", dump)
+	}
+	if strings.Contains(dump, "serial:A1B2C3D4 =") {
+		t.Error("dump copied the section's name into [names]")
+	}
+}
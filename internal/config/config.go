@@ -5,8 +5,11 @@
 package config
 
 import (
+	"camera-dashboard-go/internal/helpers"
 	"fmt"
 	"os"
+	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 )
@@ -17,6 +20,14 @@ import (
 
 // Config holds all runtime configuration values.
 type Config struct {
+	// Path is the INI file this config was loaded from (and is saved to).
+	// Set by Load even when the file doesn't exist yet.
+	Path string
+	// Warnings lists what Load ignored or replaced in the file: unknown
+	// sections and keys, duplicate keys, values that didn't parse or were
+	// out of range.
+	Warnings []Warning
+
 	// Logging
 	// LogLevel controls coarse output filtering (DEBUG/INFO/WARNING/ERROR/CRITICAL).
 	// Untagged messages are treated as INFO by the logger filter.
@@ -24,6 +35,8 @@ type Config struct {
 	LogFile        string
 	LogMaxBytes    int
 	LogBackupCount int
+	LogRotateDaily bool // Also rotate at local midnight
+	LogCompress    bool // Gzip rotated backups
 	LogToStdout    bool
 
 	// Performance + Recovery
@@ -31,7 +44,11 @@ type Config struct {
 	PerfCheckIntervalMS  int
 	MinDynamicFPS        int
 	MinDynamicUIFPS      int
-	UIFPSStep            int
+	UIFPSStep            int     // Capture FPS step up
+	FPSStepDown          int     // Capture FPS step down (larger, to damp oscillation)
+	FPSMinDwellSec       float64 // Minimum time at an FPS before a non-urgent change
+	FPSFailLimit         int     // Reductions from one FPS within fps_penalty_sec before it is capped (0 = off)
+	FPSPenaltySec        float64 // Failure window and how long a failed FPS stays capped
 	CPULoadThreshold     float64
 	CPUTempThresholdC    float64
 	StressHoldCount      int
@@ -41,11 +58,36 @@ type Config struct {
 	MaxRestartsPerWindow int
 	RestartWindowSec     float64
 
+	// Adaptive resolution (second axis after FPS, see perf/resolution.go)
+	ResolutionTiers       []Resolution // Lower tiers below the profile resolution; empty = off
+	ResolutionStepDownSec float64      // Sustained stress at min FPS before dropping a tier
+	ResolutionStepUpSec   float64      // Sustained cool before stepping back up
+	ResolutionHysteresisC float64      // Cool = cpu_temp_threshold_c minus this
+
+	// Thermal thresholds of the FPS state machine
+	ThermalProfile string            // "auto" (detect the board; resolved at load), "pi4" or "pi5"
+	Thermal        ThermalThresholds // Profile defaults, then temp_*_c overrides
+
+	// CPU tuning
+	GoMaxProcs  int    // 0 = Go default (all cores)
+	FFmpegCPUs  string // taskset-style list, e.g. "1-3"; empty = no pinning
+	CaptureCPUs string // Same, for capture/decode goroutines
+
+	// Process priority
+	FFmpegNice   int    // nice increment for FFmpeg children (0 = unchanged, max 19)
+	WriteIOClass string // ionice class for app disk writes: "", "best-effort", "idle"
+	WriteIOLevel int    // best-effort level 0 (high) - 7 (low)
+
 	// Camera rescan (hot-plug)
 	RescanIntervalMS      int
-	FailedCameraCooldownS float64
+	FailedCameraCooldownS float64 // Also the longest wait between a camera's reconnect attempts
+	ReconnectBudget       int     // Full reconnect attempts before retrying one format only
 	CameraSlotCount       int
 	KillDeviceHolders     bool
+	CapsCacheFile         string   // v4l2 capability cache; "" disables
+	DisabledCameras       []string // Device paths, IDs or vendor:product:serial to skip
+	CSICameras            bool     // Probe for Pi camera modules (rpicam-hello)
+	TestPattern           bool     // Test pattern while a camera can't be opened (false = SIGNAL LOST banner only)
 
 	// Profile
 	CaptureWidth  int
@@ -54,8 +96,271 @@ type Config struct {
 	CaptureFormat string // "mjpeg" or "yuyv"; passed to FFmpeg as -input_format
 	UIFPS         int
 
+	// Display defaults (applied at startup and on config reload)
+	NightMode         bool
+	NightGain         float64  // Night mode brightness gain
+	NightTint         string   // Night mode color: "red", "amber" or "green"
+	NightGamma        float64  // Night mode gamma; 1 = linear, above 1 lifts shadows
+	BrightnessPercent int      // One of the settings tile presets: 15, 60, 80, 100, 150
+	DrivingMode       bool     // Camera feeds only: hide settings tile and swap
+	DrivingSpeed      int      // GPS speed ([gps] units) that turns driving mode on; 0 = off
+	PIPCorners        []string // Picture-in-picture overlay corners, in camera order
+	PIPSizePercent    int      // Overlay size as a percentage of the screen
+	HiddenCameraFPS   int      // Decode rate of cameras not on screen (0 = no throttle)
+	FreezeIndicatorMS int      // Frame age that greys a tile and shows FROZEN (0 = off)
+	GridLayouts       string   // Grid overrides per tile count, e.g. "4:1x4"; empty = automatic
+	StripLayout       bool     // Cameras in one row, settings tile as a corner button
+	HealthTile        string   // System stats tile: "off", "replace" (the settings tile) or "extra"
+	ToastSeconds      int      // How long notifications stay on screen (0 = off)
+	DisplayBackend    string   // "fyne" (window) or "framebuffer" (direct to FramebufferDevice)
+	FramebufferDevice string
+
+	// Dashboard colors ([theme], see ui/theme.go)
+	ThemeName   string            // "dark" or "daylight" (high contrast)
+	ThemeColors map[string]string // ThemeColorKeys -> #RRGGBB[AA] or "none", over the named theme
+
+	// Image controls set on every camera at start ([controls]); keys are
+	// camera.ControlNames, missing keys keep the driver default
+	CameraControls  map[string]int
+	ControlsReapply []string // Cameras that get CameraControls again on every (re)connect
+
+	// Stream cameras ([network_cameras]): name -> RTSP/HTTP URL, placed
+	// before USB cameras in the grid
+	NetworkCameras map[string]string
+
+	// Simulated cameras (-simulate flag, not read from config.ini): MJPEG
+	// files, videos or image directories played instead of discovery
+	SimulateSources []string
+
+	// Frame tracing (-trace-frames flags, not read from config.ini; see
+	// ui/tracing.go): per-frame pipeline timing of one frame in
+	// TraceSampleEvery per camera, logged or written to TraceCSV
+	TraceFrames      bool
+	TraceCSV         string // "" = log
+	TraceSampleEvery int
+
+	// Per-camera display transforms ([transform]): camera match (device
+	// path, device ID or vendor:product:serial) -> "mirror, flip, rotate=90"
+	CameraTransforms map[string]string
+
+	// Fisheye correction ([dewarp]): camera match -> "k1=-0.3, k2=0.05, zoom=1.1"
+	CameraDewarp map[string]string
+
+	// Display names ([names]): camera match -> "Rear", shown on the tiles
+	// and in logs (see ui/names.go)
+	CameraNames map[string]string
+
+	// Capture settings of single cameras ([camera.<name>] sections, see
+	// cameras.go); their transform and name keys are in CameraTransforms
+	// and CameraNames
+	CameraSections []CameraSection
+
+	// Named overrides ([profile.<name>] sections, see profiles.go)
+	Profile     string   // Active profile ([profiles] active); "" = none
+	Profiles    []string // Names of the [profile.<name>] sections, sorted
+	ProfileKeys []string // "section.key" of the settings the active profile sets
+
+	// Soft-sync across cameras ([sync], see ui/sync.go)
+	SyncEnabled        bool
+	SyncMaxDelayFrames int            // Frames kept per camera, i.e. the most a feed is delayed
+	SyncLatencyMS      map[string]int // Camera match -> known pipeline latency in ms
+
+	// USB bandwidth scheduler ([bandwidth], see camera/bandwidth.go)
+	BandwidthEnabled  bool
+	BandwidthBudgetMB float64  // MB/s per USB 2.0 bus
+	BandwidthMinFPS   int      // Lowest capture rate it sets before lowering resolution
+	BandwidthPriority []string // Camera matches, most important first
+
+	// Stream health scoring and MJPEG -> YUYV fallback ([stream_health],
+	// see camera/streamhealth.go)
+	StreamFallback           bool
+	StreamCorruptPercent     float64    // Corrupt frames (% of the window) that trigger the fallback
+	StreamHealthWindowSec    int        // Scoring window
+	StreamFallbackResolution Resolution // YUYV capture size cap
+
+	// Startup layout presets ([layouts]): name -> spec, chosen at launch
+	// with -layout or CAMERA_DASHBOARD_LAYOUT (see ui/layout.go)
+	Layouts map[string]string
+
 	// Health
 	HealthLogIntervalSec float64
+	FirstFrameWarnSec    float64  // Warn when a capture session's first frame takes longer (0 = off)
+	HealthHTTPAddr       string   // Listen address for GET /healthz ("" = off)
+	DebugHTTPAddr        string   // Listen address for pprof, expvar and goroutine dumps ("" = off)
+	HealthAllowedCIDRs   []string // Client networks both endpoints accept, besides loopback (empty = any)
+
+	// Snapshots
+	SnapshotDir string // "" = snapshots disabled
+
+	// Saved clips ([clips], see ui/clips.go)
+	ClipSeconds int    // Seconds of history kept per camera (0 = off)
+	ClipsDir    string // Where clips and their JSON sidecars are written
+
+	// Trip reliability report ([trip_report], see ui/tripreport.go)
+	TripReportDir  string // Where the report is written at shutdown ("" = off)
+	TripReportMQTT bool   // Also publish it as <prefix>/trip_report
+
+	// Instant replay ([replay], see ui/replay.go)
+	ReplaySeconds int // How far back the fullscreen Replay bar goes (0 = off)
+
+	// Mirror-replacement mode ([mirror], see ui/mirror.go)
+	MirrorEnabled    bool   // Show the Mirror button on the grid
+	MirrorCamera     string // Rear camera match ("" = first camera)
+	MirrorLeft       string // Left insert match ("" = next camera in order)
+	MirrorRight      string // Right insert match ("" = next camera in order)
+	MirrorAutoDim    bool   // Dim the rear picture when the scene is dark
+	MirrorDimPercent int    // Brightness while dimmed, percent of normal
+	MirrorDarkLuma   int    // Mean luma (0-255) below which the scene is dark
+
+	// G-sensor impact detection ([gsensor], see ui/incident.go)
+	GSensorEnabled     bool
+	GSensorBus         string // I2C bus device node
+	GSensorAddress     int    // I2C address of the MPU6050
+	GSensorThresholdG  float64
+	GSensorSampleHz    int
+	GSensorCooldownSec float64
+
+	// Automatic night mode ([auto_night], see ui/autonight.go)
+	AutoNightSource     string  // "off", "luma", "schedule" or "sensor"
+	AutoNightDarkLuma   int     // Mean camera luma (0-255) below which it is night
+	AutoNightLatitude   float64 // Degrees, north positive (schedule)
+	AutoNightLongitude  float64 // Degrees, east positive (schedule)
+	AutoNightSensorBus  string  // I2C bus of the BH1750 (sensor)
+	AutoNightSensorAddr int
+	AutoNightDarkLux    float64 // Illuminance below which it is night (sensor)
+	AutoNightHoldSec    float64 // A change must hold this long (luma, sensor)
+
+	// Battery voltage monitor ([battery], see ui/battery.go)
+	BatteryEnabled          bool
+	BatteryADC              string  // "ina219" or "ads1115"
+	BatteryBus              string  // I2C bus device node
+	BatteryAddress          int     // I2C address; 0 = the ADC's default
+	BatteryChannel          int     // ADS1115 input (0-3)
+	BatteryDivider          float64 // ADS1115 voltage divider ratio (battery V per pin V)
+	BatteryShutdownV        float64 // Shut down below this average voltage; 0 = never
+	BatteryShutdownDelaySec float64 // ...once it has been below for this long
+	BatteryShutdownCmd      string  // Run at a low-battery shutdown (e.g. "systemctl poweroff"); "" = only exit
+
+	// PIN lock for the settings panel and camera controls ([lock], see ui/lock.go)
+	LockPIN         string  // 4-8 digits; "" = no lock (see ValidPIN)
+	LockUnlockSec   float64 // An unlock lasts this long
+	LockMaxAttempts int     // Wrong PINs before the keypad locks out
+	LockLockoutSec  float64 // ...for this long
+
+	// Camera tile gestures ([gestures], see ui/gesture.go); each is one of GestureActions
+	GestureTap          string
+	GestureDoubleTap    string // "none" keeps single taps instant
+	GestureLongPress    string
+	GestureTwoFingerTap string // Also a right click
+
+	// GPS ([gps], see ui/gps.go)
+	GPSSource  string // "gpsd://host:port", a serial device path, or "" = off
+	GPSUnits   string // "kmh" or "mph"
+	GPSOverlay bool   // Show speed/position over the cameras
+
+	// Time of day ([time], see ui/timesync.go)
+	TimeSources          []string // Trusted time sources in order: "gps", "ntp"; empty = system time at start only
+	TimeRenameRecordings bool     // Rename this run's recordings when the clock is corrected
+
+	// Camera power rails ([power], see ui/power.go)
+	CameraPower      map[string]string // Camera match -> "17" or "17, active_low" (GPIO line)
+	PowerChip        string            // GPIO character device
+	PowerWarmupSec   float64           // Wait after powering on before discovery/restart
+	PowerCycleOffSec float64           // Off time of a recovery power cycle
+	PowerOffOnExit   bool              // Switch all rails off when the dashboard exits
+
+	// Hardware buttons and rotary encoders ([input], see ui/input.go)
+	InputBindings   map[string]string // "gpio17", "key28", "rotate_cw"... -> action (see internal/input)
+	InputDevices    []string          // evdev devices for keys and encoders
+	InputChip       string            // GPIO character device for gpioN buttons
+	InputDebounceMs int               // Ignore GPIO button edges closer than this
+
+	// Alert sounds ([alerts], see ui/alerts.go); tones are FREQ:MS sequences (see internal/alert)
+	AlertsEnabled          bool
+	AlertsMuted            bool    // Global mute, also on the settings panel
+	AlertBackend           string  // "aplay" (ALSA) or "beep"
+	AlertDevice            string  // ALSA device for aplay; "" = default
+	AlertVolume            int     // aplay volume, 0-100
+	AlertDisconnectTones   string  // A camera was unplugged
+	AlertReverseStaleTones string  // The rear camera has no live picture while reversing
+	AlertOverTempTones     string  // CPU temperature reached AlertOverTempC
+	AlertOverTempC         float64 // 0 = no temperature alert
+	AlertReverseGPIO       int     // Reverse gear signal on this InputChip line; -1 = none
+	AlertReverseActiveHigh bool    // The signal pulls the line high (default: low)
+	AlertRearCamera        string  // [names] name or camera match of the rear camera
+
+	// Correlated USB failures ([usb], see ui/usbincident.go)
+	USBCorrelationSec      float64 // Cameras going stale within this window = one incident
+	USBCorrelationMin      int     // Stale cameras needed for an incident
+	USBHubPowerCycleCmd    string  // Run once per incident ("{hub}" = uhubctl location); "" = off
+	USBHubCycleCooldownSec float64 // Minimum time between power cycles
+
+	// Read-only root support (see storage.go)
+	ReadOnlyRoot   bool   // Probe writable paths at startup and relocate/disable
+	StateDir       string // Where unwritable state is moved in read-only mode
+	ConfigReadOnly bool   // Set by PrepareStorage: config.ini can't be saved
+
+	// Disk space manager (see internal/storage)
+	LowSpaceMB         int  // Warn below this much free space; 0 = no check
+	FreeSpace          bool // Delete oldest recordings to stay above LowSpaceMB
+	SnapshotsQuotaMB   int  // Per-directory quotas, 0 = unlimited
+	ClipsQuotaMB       int
+	TripsQuotaMB       int
+	LogsQuotaMB        int
+	StorageIntervalSec float64 // Time between checks
+
+	// SD-card write endurance (see internal/ui/endurance.go)
+	EnduranceEnabled     bool
+	EnduranceRAMDir      string  // tmpfs where recordings wait to be flushed
+	EnduranceFlushSec    float64 // Flush staged recordings at least this often
+	EnduranceFlushMB     int     // ...or as soon as this much is staged
+	EnduranceLogFlushSec float64 // Log lines are written out in batches this often
+	StopRecordingMB      int     // Refuse recordings below this much free space; 0 = off
+	WearDevice           string  // Block device with an eMMC life_time estimate, e.g. mmcblk0
+	StopRecordingWearPct int     // Refuse recordings at this estimated wear; 0 = off
+
+	// Remote upload of recordings (see internal/upload)
+	UploadEnabled       bool
+	UploadTarget        string // "s3", "webdav" or "sftp"
+	UploadURL           string
+	UploadUsername      string // webdav
+	UploadPassword      string
+	UploadAccessKey     string // s3
+	UploadSecretKey     string
+	UploadRegion        string
+	UploadIdentity      string  // sftp private key
+	UploadSSID          string  // Only upload on this WiFi network; "" = any
+	UploadCheckHost     string  // host:port that must be reachable; "" = the target
+	UploadOnlyProtected bool    // Only recordings marked .keep (impacts)
+	UploadRateKB        int     // KB/s, 0 = unlimited
+	UploadIntervalSec   float64 // Time between connectivity checks
+	UploadStateFile     string  // Uploaded files and resumable transfers
+
+	// MQTT status publishing + command subscription
+	MQTTEnabled      bool
+	MQTTBroker       string // host:port
+	MQTTClientID     string
+	MQTTUsername     string
+	MQTTPassword     string
+	MQTTTopicPrefix  string // Status under <prefix>/..., commands under <prefix>/cmd/...
+	MQTTKeepAliveSec int
+
+	// Watchdog (heartbeat supervision + systemd sd_notify)
+	WatchdogEnabled           bool
+	WatchdogCheckIntervalSec  float64
+	WatchdogUITimeoutSec      float64 // UI refresh loop; hang restarts the process
+	WatchdogCaptureTimeoutSec float64 // Capture goroutines; hang restarts the worker
+	WatchdogMaxRecoveries     int     // Worker restarts per hang before giving up
+
+	// Soak-test fault injection (bench only; intentionally not in config.ini)
+	SoakEnabled              bool
+	SoakKillFFmpegPerHour    float64
+	SoakCorruptJPEGRate      float64
+	SoakDelayFrameRate       float64
+	SoakDelayFrameMaxMS      int
+	SoakDeviceRemovalPerHour float64
+	SoakDeviceRemovalSec     float64
+	SoakSeed                 int
 
 	// Render overhead (code-only, not in INI)
 	RenderOverheadMS int
@@ -64,6 +369,28 @@ type Config struct {
 	UIFPSLogging bool
 }
 
+// ThemeColorKeys lists the [theme] color keys.
+var ThemeColorKeys = []string{
+	"background",        // Behind the grid
+	"tile",              // Camera tile background
+	"settings_tile",     // Settings tile background
+	"border",            // Tile outline ("none" = no outline)
+	"highlight",         // Tile outline while picked for a swap
+	"focus",             // Tile outline with keyboard focus
+	"disconnected_text", // "Disconnected" label
+	"detail_text",       // Recovery status under it
+}
+
+// GestureActions lists what a [gestures] key can map a gesture to.
+var GestureActions = []string{
+	"none",
+	"fullscreen",   // Show the camera fullscreen
+	"swap",         // Pick the tile for a swap
+	"snapshot",     // Save a snapshot of the camera
+	"info",         // Toggle the camera info overlay on the tile
+	"mute_overlay", // Hide/show the info and GPS overlays everywhere
+}
+
 // =============================================================================
 // Defaults
 // =============================================================================
@@ -77,6 +404,8 @@ func DefaultConfig() *Config {
 		LogFile:        "./logs/camera_dashboard.log",
 		LogMaxBytes:    5 * 1024 * 1024, // 5 MB
 		LogBackupCount: 3,
+		LogRotateDaily: false,
+		LogCompress:    false,
 		LogToStdout:    true,
 
 		// Performance + Recovery
@@ -85,6 +414,10 @@ func DefaultConfig() *Config {
 		MinDynamicFPS:        10,
 		MinDynamicUIFPS:      12,
 		UIFPSStep:            2,
+		FPSStepDown:          4,
+		FPSMinDwellSec:       30,
+		FPSFailLimit:         2,
+		FPSPenaltySec:        600,
 		CPULoadThreshold:     0.75,
 		CPUTempThresholdC:    75.0,
 		StressHoldCount:      3,
@@ -94,11 +427,41 @@ func DefaultConfig() *Config {
 		MaxRestartsPerWindow: 3,
 		RestartWindowSec:     30.0,
 
+		// Adaptive resolution (off until tiers are listed)
+		ResolutionStepDownSec: 30,
+		ResolutionStepUpSec:   120,
+		ResolutionHysteresisC: 5,
+
+		// Thermal thresholds (Pi 5 until the board is detected at load)
+		ThermalProfile: "auto",
+		Thermal:        thermalProfiles["pi5"],
+
+		// CPU tuning (no pinning by default)
+		GoMaxProcs:  0,
+		FFmpegCPUs:  "",
+		CaptureCPUs: "",
+
+		// Process priority (unchanged by default)
+		FFmpegNice:   0,
+		WriteIOClass: "",
+		WriteIOLevel: 7,
+
 		// Camera rescan
-		RescanIntervalMS:      15000,
-		FailedCameraCooldownS: 30.0,
-		CameraSlotCount:       3,
-		KillDeviceHolders:     true,
+		RescanIntervalMS:         15000,
+		FailedCameraCooldownS:    30.0,
+		ReconnectBudget:          5,
+		CameraSlotCount:          3,
+		KillDeviceHolders:        true,
+		CSICameras:               true,
+		TestPattern:              true,
+		SyncMaxDelayFrames:       4,
+		BandwidthBudgetMB:        35,
+		BandwidthMinFPS:          10,
+		StreamFallback:           true,
+		StreamCorruptPercent:     10,
+		StreamHealthWindowSec:    30,
+		StreamFallbackResolution: Resolution{Width: 640, Height: 480},
+		CapsCacheFile:            "./camera_caps.json",
 
 		// Profile
 		CaptureWidth:  640,
@@ -107,8 +470,160 @@ func DefaultConfig() *Config {
 		CaptureFormat: "mjpeg",
 		UIFPS:         20,
 
+		// Display defaults
+		NightMode:         false,
+		NightGain:         1.6,
+		NightTint:         "red",
+		NightGamma:        1.0,
+		BrightnessPercent: 100,
+		DrivingMode:       false,
+		DrivingSpeed:      0,
+		PIPCorners:        []string{"top-right", "bottom-right", "bottom-left"},
+		PIPSizePercent:    25,
+		HiddenCameraFPS:   2,
+		FreezeIndicatorMS: 500,
+		HealthTile:        "off",
+		ToastSeconds:      4,
+		DisplayBackend:    "fyne",
+		FramebufferDevice: "/dev/fb0",
+		ThemeName:         "dark",
+
 		// Health
 		HealthLogIntervalSec: 30.0,
+		FirstFrameWarnSec:    5.0,
+
+		// Snapshots
+		SnapshotDir: "./snapshots",
+
+		// Clips
+		ClipSeconds: 0,
+		ClipsDir:    "./clips",
+
+		// Trip report
+		TripReportDir:  "./trips",
+		TripReportMQTT: true,
+
+		// Replay
+		ReplaySeconds: 0,
+
+		// Mirror
+		MirrorEnabled:    false,
+		MirrorAutoDim:    true,
+		MirrorDimPercent: 40,
+		MirrorDarkLuma:   50,
+
+		// G-sensor
+		GSensorEnabled:     false,
+		GSensorBus:         "/dev/i2c-1",
+		GSensorAddress:     0x68,
+		GSensorThresholdG:  2.5,
+		GSensorSampleHz:    50,
+		GSensorCooldownSec: 10.0,
+
+		// Automatic night mode
+		AutoNightSource:     "off",
+		AutoNightDarkLuma:   50,
+		AutoNightSensorBus:  "/dev/i2c-1",
+		AutoNightSensorAddr: 0x23,
+		AutoNightDarkLux:    10.0,
+		AutoNightHoldSec:    60.0,
+
+		BatteryEnabled:          false,
+		BatteryADC:              "ina219",
+		BatteryBus:              "/dev/i2c-1",
+		BatteryDivider:          1.0,
+		BatteryShutdownV:        11.5,
+		BatteryShutdownDelaySec: 30.0,
+
+		LockUnlockSec:   120.0,
+		LockMaxAttempts: 5,
+		LockLockoutSec:  60.0,
+
+		GestureTap:          "fullscreen",
+		GestureDoubleTap:    "none",
+		GestureLongPress:    "swap",
+		GestureTwoFingerTap: "swap",
+
+		// GPS
+		GPSSource:  "",
+		GPSUnits:   "kmh",
+		GPSOverlay: true,
+
+		// Time of day
+		TimeSources:          []string{"gps", "ntp"},
+		TimeRenameRecordings: true,
+
+		// Camera power rails (none configured)
+		PowerChip:        "/dev/gpiochip0",
+		PowerWarmupSec:   3.0,
+		PowerCycleOffSec: 2.0,
+		PowerOffOnExit:   true,
+		InputChip:        "/dev/gpiochip0",
+		InputDebounceMs:  50,
+
+		// Alert sounds (off)
+		AlertBackend:           "aplay",
+		AlertVolume:            70,
+		AlertDisconnectTones:   "880:150 660:300",
+		AlertReverseStaleTones: "1400:120 0:80 1400:120 0:80 1400:120",
+		AlertOverTempTones:     "440:500 0:150 440:500",
+		AlertOverTempC:         80.0,
+		AlertReverseGPIO:       -1,
+		AlertRearCamera:        "rear",
+
+		// Correlated USB failures
+		USBCorrelationSec:      5.0,
+		USBCorrelationMin:      2,
+		USBHubCycleCooldownSec: 300.0,
+
+		// Storage
+		ReadOnlyRoot: false,
+		StateDir:     "/var/lib/camera-dashboard",
+
+		// Disk space manager
+		LowSpaceMB:         500,
+		FreeSpace:          false,
+		StorageIntervalSec: 60.0,
+
+		// SD-card write endurance
+		EnduranceEnabled:     false,
+		EnduranceRAMDir:      "/dev/shm/camera-dashboard",
+		EnduranceFlushSec:    300.0,
+		EnduranceFlushMB:     32,
+		EnduranceLogFlushSec: 30.0,
+		StopRecordingMB:      0,
+		StopRecordingWearPct: 90,
+
+		// Remote upload
+		UploadEnabled:       false,
+		UploadTarget:        "s3",
+		UploadRegion:        "us-east-1",
+		UploadOnlyProtected: true,
+		UploadIntervalSec:   60.0,
+		UploadStateFile:     "./upload_state.json",
+
+		// MQTT
+		MQTTEnabled:      false,
+		MQTTBroker:       "localhost:1883",
+		MQTTClientID:     "camera-dashboard",
+		MQTTTopicPrefix:  "camera_dashboard",
+		MQTTKeepAliveSec: 30,
+
+		// Watchdog
+		WatchdogEnabled:           true,
+		WatchdogCheckIntervalSec:  2.0,
+		WatchdogUITimeoutSec:      15.0,
+		WatchdogCaptureTimeoutSec: 10.0,
+		WatchdogMaxRecoveries:     3,
+
+		// Soak test
+		SoakEnabled:              false,
+		SoakKillFFmpegPerHour:    6,
+		SoakCorruptJPEGRate:      0.01,
+		SoakDelayFrameRate:       0.005,
+		SoakDelayFrameMaxMS:      2000,
+		SoakDeviceRemovalPerHour: 2,
+		SoakDeviceRemovalSec:     20,
 
 		// Code-only defaults
 		RenderOverheadMS: 3,
@@ -120,22 +635,101 @@ func DefaultConfig() *Config {
 // INI parser (minimal, no external deps)
 // =============================================================================
 
+// Resolution is a capture size (adaptive resolution tier).
+type Resolution struct {
+	Width, Height int
+}
+
+// ThermalThresholds are the CPU temperatures (Celsius) the performance
+// controller acts on, coolest first.
+type ThermalThresholds struct {
+	IdealC    float64 // Below this FPS may go up
+	ComfortC  float64 // Sweet-spot ceiling
+	WarmC     float64 // Start being cautious
+	HotC      float64 // Reduce FPS
+	CriticalC float64 // Emergency minimum FPS
+}
+
+// Ordered reports whether every threshold is above the previous one.
+func (t ThermalThresholds) Ordered() bool {
+	return t.IdealC < t.ComfortC && t.ComfortC < t.WarmC && t.WarmC < t.HotC && t.HotC < t.CriticalC
+}
+
+// thermalProfiles are the per-board threshold defaults. The Pi 5
+// throttles at 85°C; the Pi 4 firmware starts soft throttling at 80°C.
+var thermalProfiles = map[string]ThermalThresholds{
+	"pi5": {IdealC: 72, ComfortC: 78, WarmC: 82, HotC: 84, CriticalC: 86},
+	"pi4": {IdealC: 66, ComfortC: 72, WarmC: 75, HotC: 77, CriticalC: 79},
+}
+
+// boardModelPath names the board on Raspberry Pi OS.
+var boardModelPath = "/proc/device-tree/model"
+
+// detectThermalProfile picks the thermal profile for this board: "pi4"
+// for the Pi 4 family (Pi 400, CM4), otherwise "pi5".
+func detectThermalProfile() string {
+	data, err := os.ReadFile(boardModelPath)
+	if err != nil {
+		return "pi5"
+	}
+	model := string(data)
+	if strings.Contains(model, "Raspberry Pi 4") || strings.Contains(model, "Compute Module 4") {
+		return "pi4"
+	}
+	return "pi5"
+}
+
 // iniData stores parsed INI sections and their key-value pairs.
 type iniData map[string]map[string]string
 
+// Warning is a problem in the config file that Load worked around: the
+// line or key is ignored, or the setting keeps its default.
+type Warning struct {
+	Line    int // 0 when it isn't about one line
+	Section string
+	Key     string
+	Message string
+}
+
+func (w Warning) String() string {
+	var b strings.Builder
+	if w.Line > 0 {
+		fmt.Fprintf(&b, "line %d: ", w.Line)
+	}
+	if w.Section != "" {
+		b.WriteString("[" + w.Section + "]")
+		if w.Key != "" {
+			b.WriteString(" " + w.Key)
+		}
+		b.WriteString(": ")
+	}
+	return b.String() + w.Message
+}
+
 // parseINI reads an INI file and returns its sections and key-value pairs.
 // Supports comments (# and ;), sections ([name]), and key = value lines.
 func parseINI(path string) (iniData, error) {
+	ini, _, err := readINI(path)
+	return ini, err
+}
+
+// readINI is parseINI that also reports what it ignores or overrides:
+// unknown sections and keys, duplicate keys (the last one wins) and lines
+// that aren't a section or a setting.
+func readINI(path string) (iniData, []Warning, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	result := make(iniData)
 	currentSection := ""
+	lines := make(map[string]int) // "section.key" -> line it was set on
+	var warnings []Warning
 
-	for _, rawLine := range strings.Split(string(data), "\n") {
+	for n, rawLine := range strings.Split(string(data), "\n") {
 		line := strings.TrimSpace(rawLine)
+		lineNo := n + 1
 
 		// Skip empty lines and comments
 		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
@@ -147,21 +741,37 @@ func parseINI(path string) (iniData, error) {
 			currentSection = strings.TrimSpace(line[1 : len(line)-1])
 			if _, ok := result[currentSection]; !ok {
 				result[currentSection] = make(map[string]string)
+				if !knownSection(currentSection) {
+					warnings = append(warnings, Warning{lineNo, currentSection, "", "unknown section, ignored"})
+				}
 			}
 			continue
 		}
 
 		// Key = value
-		if idx := strings.IndexByte(line, '='); idx > 0 {
-			key := strings.TrimSpace(line[:idx])
-			value := strings.TrimSpace(line[idx+1:])
-			if currentSection != "" {
-				result[currentSection][key] = value
+		idx := strings.IndexByte(line, '=')
+		if idx <= 0 {
+			warnings = append(warnings, Warning{lineNo, currentSection, "", fmt.Sprintf("%q is not a key = value line, ignored", line)})
+			continue
+		}
+		key := strings.TrimSpace(line[:idx])
+		value := strings.TrimSpace(line[idx+1:])
+		if currentSection == "" {
+			warnings = append(warnings, Warning{lineNo, "", key, key + " is outside a [section], ignored"})
+			continue
+		}
+		if prev, ok := lines[currentSection+"."+key]; ok {
+			warnings = append(warnings, Warning{lineNo, currentSection, key, fmt.Sprintf("set again, overrides line %d", prev)})
+		} else if _, freeform := freeformKeys[currentSection]; !freeform && knownSection(currentSection) {
+			if !knownKey(currentSection, key) {
+				warnings = append(warnings, Warning{lineNo, currentSection, key, "unknown key, ignored" + suggestKey(currentSection, key)})
 			}
 		}
+		lines[currentSection+"."+key] = lineNo
+		result[currentSection][key] = value
 	}
 
-	return result, nil
+	return result, warnings, nil
 }
 
 // get returns a value from the parsed INI data, or empty string if not found.
@@ -239,6 +849,61 @@ func asFloat(value string, fallback float64, minVal, maxVal *float64) float64 {
 }
 
 // Helper functions to create pointers for min/max bounds
+// splitList parses a comma-separated list, dropping empty entries.
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// parseResolutionTiers parses "480x360, 320x240" into tiers sorted from
+// largest to smallest. Malformed or out-of-range entries are skipped.
+func parseResolutionTiers(value string) []Resolution {
+	var tiers []Resolution
+	for _, item := range splitList(value) {
+		var r Resolution
+		if _, err := fmt.Sscanf(strings.ToLower(item), "%dx%d", &r.Width, &r.Height); err != nil {
+			continue
+		}
+		if r.Width < 160 || r.Width > 1920 || r.Height < 120 || r.Height > 1080 {
+			continue
+		}
+		tiers = append(tiers, r)
+	}
+	sort.SliceStable(tiers, func(i, j int) bool {
+		return tiers[i].Width*tiers[i].Height > tiers[j].Width*tiers[j].Height
+	})
+	return tiers
+}
+
+// ValidPIN reports whether pin can be used as the [lock] pin: 4 to 8
+// digits.
+func ValidPIN(pin string) bool {
+	if len(pin) < 4 || len(pin) > 8 {
+		return false
+	}
+	for _, r := range pin {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// isGestureAction reports whether action is one of GestureActions.
+func isGestureAction(action string) bool {
+	for _, a := range GestureActions {
+		if a == action {
+			return true
+		}
+	}
+	return false
+}
+
 func intPtr(v int) *int           { return &v }
 func floatPtr(v float64) *float64 { return &v }
 
@@ -258,32 +923,127 @@ func ConfigPath() string {
 // and returns a fully populated Config. Missing sections or keys
 // fall back to DefaultConfig() values.
 func Load(path string) (*Config, error) {
+	return load(path, true, true)
+}
+
+// LoadFile is Load without the CAMERA_DASHBOARD_* environment overrides:
+// the settings and warnings of the file alone, e.g. to check one before
+// it replaces config.ini.
+func LoadFile(path string) (*Config, error) {
+	return load(path, true, false)
+}
+
+// load is Load; withProfile = false leaves out the active [profile.<name>],
+// for the file's own values, and withEnv = false the environment.
+func load(path string, withProfile, withEnv bool) (*Config, error) {
 	if path == "" {
 		path = ConfigPath()
 	}
 
 	cfg := DefaultConfig()
+	cfg.Path = path
+
+	// The capability cache lives next to the config file unless overridden
+	cfg.CapsCacheFile = filepath.Join(filepath.Dir(path), "camera_caps.json")
+	cfg.UploadStateFile = filepath.Join(filepath.Dir(path), "upload_state.json")
 
-	// If file doesn't exist, return defaults (not an error)
-	if _, err := os.Stat(path); os.IsNotExist(err) {
-		return cfg, nil
+	// If file doesn't exist, use defaults (not an error)
+	ini := iniData{}
+	var warnings []Warning
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		if ini, warnings, err = readINI(path); err != nil {
+			return cfg, fmt.Errorf("config: failed to parse %s: %w", path, err)
+		}
 	}
 
-	ini, err := parseINI(path)
-	if err != nil {
-		return cfg, fmt.Errorf("config: failed to parse %s: %w", path, err)
+	// CAMERA_DASHBOARD_<SECTION>_<KEY> overrides the file
+	var env []envSetting
+	var envWarnings []Warning
+	if withEnv {
+		env, envWarnings = envSettings(os.Environ())
+	}
+	applyEnv(ini, env)
+
+	// The active [profile.<name>] goes over the file, under the environment
+	var profiled map[string]bool
+	if withProfile {
+		profiled = applyProfile(ini)
+		applyEnv(ini, env)
 	}
 
 	applyINI(cfg, ini)
+	cfg.ProfileKeys = sortedSet(profiled)
+	for _, w := range checkValues(cfg, ini) {
+		setBy := ""
+		if profiled[w.Section+"."+w.Key] {
+			setBy = " (set by [" + profileSectionPrefix + cfg.Profile + "])"
+		}
+		for _, e := range env {
+			if e.section == w.Section && e.key == w.Key {
+				setBy = " (set by " + e.name + ")"
+			}
+		}
+		w.Message += setBy
+		warnings = append(warnings, w)
+	}
+	warnings = append(warnings, checkCameraSections(cfg, ini)...)
+	warnings = append(warnings, checkProfiles(cfg)...)
+	cfg.Warnings = append(warnings, envWarnings...)
 
 	// Environment variable overrides
-	if logFile := os.Getenv("CAMERA_DASHBOARD_LOG_FILE"); logFile != "" {
+	if logFile := os.Getenv("CAMERA_DASHBOARD_LOG_FILE"); withEnv && logFile != "" {
 		cfg.LogFile = logFile
 	}
 
 	return cfg, nil
 }
 
+// applyThermal sets the thermal thresholds: the defaults of [performance]
+// thermal_profile ("auto" detects the board), then any temp_*_c
+// overrides.
+func applyThermal(cfg *Config, ini iniData) {
+	if v, ok := ini.get("performance", "thermal_profile"); ok {
+		profile := strings.ToLower(strings.TrimSpace(v))
+		if _, known := thermalProfiles[profile]; known || profile == "auto" {
+			cfg.ThermalProfile = profile
+		}
+	}
+	if cfg.ThermalProfile == "auto" {
+		cfg.ThermalProfile = detectThermalProfile()
+	}
+	if t, ok := thermalProfiles[cfg.ThermalProfile]; ok {
+		cfg.Thermal = t
+	}
+
+	for _, k := range []struct {
+		key   string
+		value *float64
+	}{
+		{"temp_ideal_c", &cfg.Thermal.IdealC},
+		{"temp_comfort_c", &cfg.Thermal.ComfortC},
+		{"temp_warm_c", &cfg.Thermal.WarmC},
+		{"temp_hot_c", &cfg.Thermal.HotC},
+		{"temp_critical_c", &cfg.Thermal.CriticalC},
+	} {
+		if v, ok := ini.get("performance", k.key); ok {
+			*k.value = asFloat(v, *k.value, floatPtr(40.0), floatPtr(100.0))
+		}
+	}
+}
+
+// ThermalLimits returns the thermal thresholds to use: the configured
+// ones, or the profile defaults when they are out of order (see
+// Validate).
+func (c *Config) ThermalLimits() ThermalThresholds {
+	if c.Thermal.Ordered() {
+		return c.Thermal
+	}
+	if t, ok := thermalProfiles[c.ThermalProfile]; ok {
+		return t
+	}
+	return thermalProfiles["pi5"]
+}
+
 // applyINI maps INI key-value pairs onto the Config struct,
 // matching Python's apply_config() exactly.
 func applyINI(cfg *Config, ini iniData) {
@@ -301,6 +1061,12 @@ func applyINI(cfg *Config, ini iniData) {
 		if v, ok := ini.get("logging", "backup_count"); ok {
 			cfg.LogBackupCount = asInt(v, cfg.LogBackupCount, intPtr(1), nil)
 		}
+		if v, ok := ini.get("logging", "rotate_daily"); ok {
+			cfg.LogRotateDaily = asBool(v, cfg.LogRotateDaily)
+		}
+		if v, ok := ini.get("logging", "compress"); ok {
+			cfg.LogCompress = asBool(v, cfg.LogCompress)
+		}
 		if v, ok := ini.get("logging", "stdout"); ok {
 			cfg.LogToStdout = asBool(v, cfg.LogToStdout)
 		}
@@ -323,6 +1089,18 @@ func applyINI(cfg *Config, ini iniData) {
 		if v, ok := ini.get("performance", "ui_fps_step"); ok {
 			cfg.UIFPSStep = asInt(v, cfg.UIFPSStep, intPtr(1), nil)
 		}
+		if v, ok := ini.get("performance", "fps_step_down"); ok {
+			cfg.FPSStepDown = asInt(v, cfg.FPSStepDown, intPtr(1), nil)
+		}
+		if v, ok := ini.get("performance", "fps_min_dwell_sec"); ok {
+			cfg.FPSMinDwellSec = asFloat(v, cfg.FPSMinDwellSec, floatPtr(0), nil)
+		}
+		if v, ok := ini.get("performance", "fps_fail_limit"); ok {
+			cfg.FPSFailLimit = asInt(v, cfg.FPSFailLimit, intPtr(0), nil)
+		}
+		if v, ok := ini.get("performance", "fps_penalty_sec"); ok {
+			cfg.FPSPenaltySec = asFloat(v, cfg.FPSPenaltySec, floatPtr(30), nil)
+		}
 		if v, ok := ini.get("performance", "cpu_load_threshold"); ok {
 			cfg.CPULoadThreshold = asFloat(v, cfg.CPULoadThreshold, floatPtr(0.1), floatPtr(1.0))
 		}
@@ -347,6 +1125,41 @@ func applyINI(cfg *Config, ini iniData) {
 		if v, ok := ini.get("performance", "restart_window_sec"); ok {
 			cfg.RestartWindowSec = asFloat(v, cfg.RestartWindowSec, floatPtr(5.0), nil)
 		}
+		if v, ok := ini.get("performance", "resolution_tiers"); ok {
+			cfg.ResolutionTiers = parseResolutionTiers(v)
+		}
+		if v, ok := ini.get("performance", "resolution_step_down_sec"); ok {
+			cfg.ResolutionStepDownSec = asFloat(v, cfg.ResolutionStepDownSec, floatPtr(5.0), nil)
+		}
+		if v, ok := ini.get("performance", "resolution_step_up_sec"); ok {
+			cfg.ResolutionStepUpSec = asFloat(v, cfg.ResolutionStepUpSec, floatPtr(10.0), nil)
+		}
+		if v, ok := ini.get("performance", "resolution_hysteresis_c"); ok {
+			cfg.ResolutionHysteresisC = asFloat(v, cfg.ResolutionHysteresisC, floatPtr(1.0), floatPtr(30.0))
+		}
+	}
+	applyThermal(cfg, ini)
+
+	// [cpu]
+	if ini.hasSection("cpu") {
+		if v, ok := ini.get("cpu", "gomaxprocs"); ok {
+			cfg.GoMaxProcs = asInt(v, cfg.GoMaxProcs, intPtr(0), nil)
+		}
+		if v, ok := ini.get("cpu", "ffmpeg_cpus"); ok {
+			cfg.FFmpegCPUs = strings.TrimSpace(v)
+		}
+		if v, ok := ini.get("cpu", "capture_cpus"); ok {
+			cfg.CaptureCPUs = strings.TrimSpace(v)
+		}
+		if v, ok := ini.get("cpu", "ffmpeg_nice"); ok {
+			cfg.FFmpegNice = asInt(v, cfg.FFmpegNice, intPtr(0), intPtr(19))
+		}
+		if v, ok := ini.get("cpu", "write_io_class"); ok {
+			cfg.WriteIOClass = strings.ToLower(strings.TrimSpace(v))
+		}
+		if v, ok := ini.get("cpu", "write_io_level"); ok {
+			cfg.WriteIOLevel = asInt(v, cfg.WriteIOLevel, intPtr(0), intPtr(7))
+		}
 	}
 
 	// [camera]
@@ -357,12 +1170,27 @@ func applyINI(cfg *Config, ini iniData) {
 		if v, ok := ini.get("camera", "failed_camera_cooldown_sec"); ok {
 			cfg.FailedCameraCooldownS = asFloat(v, cfg.FailedCameraCooldownS, floatPtr(1.0), nil)
 		}
+		if v, ok := ini.get("camera", "reconnect_budget"); ok {
+			cfg.ReconnectBudget = asInt(v, cfg.ReconnectBudget, intPtr(1), intPtr(100))
+		}
 		if v, ok := ini.get("camera", "slot_count"); ok {
 			cfg.CameraSlotCount = asInt(v, cfg.CameraSlotCount, intPtr(1), intPtr(8))
 		}
 		if v, ok := ini.get("camera", "kill_device_holders"); ok {
 			cfg.KillDeviceHolders = asBool(v, cfg.KillDeviceHolders)
 		}
+		if v, ok := ini.get("camera", "caps_cache_file"); ok {
+			cfg.CapsCacheFile = strings.TrimSpace(v)
+		}
+		if v, ok := ini.get("camera", "disabled"); ok {
+			cfg.DisabledCameras = splitList(v)
+		}
+		if v, ok := ini.get("camera", "csi"); ok {
+			cfg.CSICameras = asBool(v, cfg.CSICameras)
+		}
+		if v, ok := ini.get("camera", "test_pattern"); ok {
+			cfg.TestPattern = asBool(v, cfg.TestPattern)
+		}
 	}
 
 	// [profile]
@@ -387,11 +1215,740 @@ func applyINI(cfg *Config, ini iniData) {
 		}
 	}
 
+	// [display]
+	if ini.hasSection("display") {
+		if v, ok := ini.get("display", "night_mode"); ok {
+			cfg.NightMode = asBool(v, cfg.NightMode)
+		}
+		if v, ok := ini.get("display", "night_gain"); ok {
+			cfg.NightGain = asFloat(v, cfg.NightGain, floatPtr(0.5), floatPtr(4))
+		}
+		if v, ok := ini.get("display", "night_tint"); ok {
+			switch v = strings.ToLower(strings.TrimSpace(v)); v {
+			case "red", "amber", "green":
+				cfg.NightTint = v
+			}
+		}
+		if v, ok := ini.get("display", "night_gamma"); ok {
+			cfg.NightGamma = asFloat(v, cfg.NightGamma, floatPtr(0.2), floatPtr(5))
+		}
+		if v, ok := ini.get("display", "brightness"); ok {
+			cfg.BrightnessPercent = asInt(v, cfg.BrightnessPercent, intPtr(15), intPtr(150))
+		}
+		if v, ok := ini.get("display", "driving_mode"); ok {
+			cfg.DrivingMode = asBool(v, cfg.DrivingMode)
+		}
+		if v, ok := ini.get("display", "driving_speed"); ok {
+			cfg.DrivingSpeed = asInt(v, cfg.DrivingSpeed, intPtr(0), intPtr(200))
+		}
+		if v, ok := ini.get("display", "pip_corners"); ok {
+			var corners []string
+			for _, c := range splitList(strings.ToLower(v)) {
+				switch c {
+				case "top-left", "top-right", "bottom-left", "bottom-right":
+					corners = append(corners, c)
+				}
+			}
+			if len(corners) > 0 {
+				cfg.PIPCorners = corners
+			}
+		}
+		if v, ok := ini.get("display", "pip_size"); ok {
+			cfg.PIPSizePercent = asInt(v, cfg.PIPSizePercent, intPtr(10), intPtr(50))
+		}
+		if v, ok := ini.get("display", "hidden_camera_fps"); ok {
+			cfg.HiddenCameraFPS = asInt(v, cfg.HiddenCameraFPS, intPtr(0), intPtr(5))
+		}
+		if v, ok := ini.get("display", "freeze_indicator_ms"); ok {
+			cfg.FreezeIndicatorMS = asInt(v, cfg.FreezeIndicatorMS, intPtr(0), intPtr(10000))
+		}
+		if v, ok := ini.get("display", "grid_layouts"); ok {
+			cfg.GridLayouts = strings.TrimSpace(v)
+		}
+		if v, ok := ini.get("display", "strip_layout"); ok {
+			cfg.StripLayout = asBool(v, cfg.StripLayout)
+		}
+		if v, ok := ini.get("display", "health_tile"); ok {
+			switch v = strings.ToLower(strings.TrimSpace(v)); v {
+			case "off", "replace", "extra":
+				cfg.HealthTile = v
+			}
+		}
+		if v, ok := ini.get("display", "toast_seconds"); ok {
+			cfg.ToastSeconds = asInt(v, cfg.ToastSeconds, intPtr(0), intPtr(60))
+		}
+		if v, ok := ini.get("display", "backend"); ok {
+			v = strings.ToLower(strings.TrimSpace(v))
+			if v == "fyne" || v == "framebuffer" {
+				cfg.DisplayBackend = v
+			}
+		}
+		if v, ok := ini.get("display", "framebuffer_device"); ok && strings.TrimSpace(v) != "" {
+			cfg.FramebufferDevice = strings.TrimSpace(v)
+		}
+	}
+
+	// [theme]
+	if ini.hasSection("theme") {
+		if v, ok := ini.get("theme", "name"); ok {
+			if v = strings.ToLower(strings.TrimSpace(v)); v == "dark" || v == "daylight" {
+				cfg.ThemeName = v
+			}
+		}
+		for _, key := range ThemeColorKeys {
+			if v, ok := ini.get("theme", key); ok && strings.TrimSpace(v) != "" {
+				if cfg.ThemeColors == nil {
+					cfg.ThemeColors = make(map[string]string)
+				}
+				cfg.ThemeColors[key] = strings.TrimSpace(v)
+			}
+		}
+	}
+
+	// [controls]
+	if ini.hasSection("controls") {
+		cfg.CameraControls = make(map[string]int)
+		for _, key := range []string{"brightness", "contrast", "saturation", "exposure"} {
+			if v, ok := ini.get("controls", key); ok && strings.TrimSpace(v) != "" {
+				if n, err := strconv.Atoi(strings.TrimSpace(v)); err == nil {
+					cfg.CameraControls[key] = n
+				}
+			}
+		}
+		if v, ok := ini.get("controls", "reapply_on_connect"); ok {
+			cfg.ControlsReapply = splitList(v)
+		}
+		if v, ok := ini.get("controls", "auto_white_balance"); ok && strings.TrimSpace(v) != "" {
+			if asBool(v, true) {
+				cfg.CameraControls["auto_white_balance"] = 1
+			} else {
+				cfg.CameraControls["auto_white_balance"] = 0
+			}
+		}
+	}
+
+	// [network_cameras]
+	if ini.hasSection("network_cameras") {
+		cfg.NetworkCameras = make(map[string]string)
+		for name, source := range ini["network_cameras"] {
+			cfg.NetworkCameras[name] = source
+		}
+	}
+
+	// [transform]
+	if ini.hasSection("transform") {
+		cfg.CameraTransforms = make(map[string]string)
+		for camera, spec := range ini["transform"] {
+			cfg.CameraTransforms[camera] = spec
+		}
+	}
+
+	// [dewarp]
+	if ini.hasSection("dewarp") {
+		cfg.CameraDewarp = make(map[string]string)
+		for camera, spec := range ini["dewarp"] {
+			cfg.CameraDewarp[camera] = spec
+		}
+	}
+
+	// [names]
+	if ini.hasSection("names") {
+		cfg.CameraNames = make(map[string]string)
+		for camera, name := range ini["names"] {
+			if name = strings.TrimSpace(name); name != "" {
+				cfg.CameraNames[camera] = name
+			}
+		}
+	}
+
+	// [camera.<name>], after [transform] and [names], which win
+	applyCameraSections(cfg, ini)
+
+	// [profiles]; Load has already applied the active [profile.<name>]
+	cfg.Profiles = profileNames(ini)
+	if v, ok := ini.get("profiles", "active"); ok {
+		cfg.Profile = strings.TrimSpace(v)
+	}
+
+	// [sync]
+	if ini.hasSection("sync") {
+		if v, ok := ini.get("sync", "enabled"); ok {
+			cfg.SyncEnabled = asBool(v, cfg.SyncEnabled)
+		}
+		if v, ok := ini.get("sync", "max_delay_frames"); ok {
+			cfg.SyncMaxDelayFrames = asInt(v, cfg.SyncMaxDelayFrames, intPtr(1), intPtr(10))
+		}
+		if v, ok := ini.get("sync", "latency_ms"); ok {
+			cfg.SyncLatencyMS = make(map[string]int)
+			for _, item := range splitList(v) {
+				// Split at the last colon: camera keys may be vendor:product:serial
+				idx := strings.LastIndex(item, ":")
+				if idx <= 0 {
+					continue
+				}
+				if ms, err := strconv.Atoi(strings.TrimSpace(item[idx+1:])); err == nil && ms >= 0 {
+					cfg.SyncLatencyMS[strings.TrimSpace(item[:idx])] = ms
+				}
+			}
+		}
+	}
+
+	// [bandwidth]
+	if ini.hasSection("bandwidth") {
+		if v, ok := ini.get("bandwidth", "enabled"); ok {
+			cfg.BandwidthEnabled = asBool(v, cfg.BandwidthEnabled)
+		}
+		if v, ok := ini.get("bandwidth", "budget_mb_s"); ok {
+			cfg.BandwidthBudgetMB = asFloat(v, cfg.BandwidthBudgetMB, floatPtr(5.0), floatPtr(60.0))
+		}
+		if v, ok := ini.get("bandwidth", "min_fps"); ok {
+			cfg.BandwidthMinFPS = asInt(v, cfg.BandwidthMinFPS, intPtr(5), intPtr(30))
+		}
+		if v, ok := ini.get("bandwidth", "priority"); ok {
+			cfg.BandwidthPriority = splitList(v)
+		}
+	}
+
+	// [stream_health]
+	if ini.hasSection("stream_health") {
+		if v, ok := ini.get("stream_health", "fallback"); ok {
+			cfg.StreamFallback = asBool(v, cfg.StreamFallback)
+		}
+		if v, ok := ini.get("stream_health", "corrupt_percent"); ok {
+			cfg.StreamCorruptPercent = asFloat(v, cfg.StreamCorruptPercent, floatPtr(1.0), floatPtr(50.0))
+		}
+		if v, ok := ini.get("stream_health", "window_sec"); ok {
+			cfg.StreamHealthWindowSec = asInt(v, cfg.StreamHealthWindowSec, intPtr(10), intPtr(300))
+		}
+		if v, ok := ini.get("stream_health", "fallback_resolution"); ok {
+			if r := parseResolutionTiers(v); len(r) == 1 {
+				cfg.StreamFallbackResolution = r[0]
+			}
+		}
+	}
+
+	// [layouts]
+	if ini.hasSection("layouts") {
+		cfg.Layouts = make(map[string]string)
+		for name, spec := range ini["layouts"] {
+			cfg.Layouts[name] = spec
+		}
+	}
+
 	// [health]
 	if ini.hasSection("health") {
 		if v, ok := ini.get("health", "log_interval_sec"); ok {
 			cfg.HealthLogIntervalSec = asFloat(v, cfg.HealthLogIntervalSec, floatPtr(5.0), nil)
 		}
+		if v, ok := ini.get("health", "first_frame_warn_sec"); ok {
+			cfg.FirstFrameWarnSec = asFloat(v, cfg.FirstFrameWarnSec, floatPtr(0), nil)
+		}
+		if v, ok := ini.get("health", "http_addr"); ok {
+			cfg.HealthHTTPAddr = strings.TrimSpace(v)
+		}
+		if v, ok := ini.get("health", "debug_addr"); ok {
+			cfg.DebugHTTPAddr = strings.TrimSpace(v)
+		}
+		if v, ok := ini.get("health", "allowed_cidrs"); ok {
+			cfg.HealthAllowedCIDRs = splitList(v)
+		}
+	}
+
+	// [snapshot]
+	if ini.hasSection("snapshot") {
+		if v, ok := ini.get("snapshot", "dir"); ok {
+			cfg.SnapshotDir = v
+		}
+	}
+
+	// [clips]
+	if ini.hasSection("clips") {
+		if v, ok := ini.get("clips", "seconds"); ok {
+			cfg.ClipSeconds = asInt(v, cfg.ClipSeconds, intPtr(0), intPtr(120))
+		}
+		if v, ok := ini.get("clips", "dir"); ok {
+			cfg.ClipsDir = strings.TrimSpace(v)
+		}
+	}
+
+	// [trip_report]
+	if ini.hasSection("trip_report") {
+		if v, ok := ini.get("trip_report", "dir"); ok {
+			cfg.TripReportDir = strings.TrimSpace(v)
+		}
+		if v, ok := ini.get("trip_report", "mqtt"); ok {
+			cfg.TripReportMQTT = asBool(v, cfg.TripReportMQTT)
+		}
+	}
+
+	// [replay]
+	if ini.hasSection("replay") {
+		if v, ok := ini.get("replay", "seconds"); ok {
+			cfg.ReplaySeconds = asInt(v, cfg.ReplaySeconds, intPtr(0), intPtr(60))
+		}
+	}
+
+	// [mirror]
+	if ini.hasSection("mirror") {
+		if v, ok := ini.get("mirror", "enabled"); ok {
+			cfg.MirrorEnabled = asBool(v, cfg.MirrorEnabled)
+		}
+		if v, ok := ini.get("mirror", "camera"); ok {
+			cfg.MirrorCamera = strings.TrimSpace(v)
+		}
+		if v, ok := ini.get("mirror", "left"); ok {
+			cfg.MirrorLeft = strings.TrimSpace(v)
+		}
+		if v, ok := ini.get("mirror", "right"); ok {
+			cfg.MirrorRight = strings.TrimSpace(v)
+		}
+		if v, ok := ini.get("mirror", "auto_dim"); ok {
+			cfg.MirrorAutoDim = asBool(v, cfg.MirrorAutoDim)
+		}
+		if v, ok := ini.get("mirror", "dim_percent"); ok {
+			cfg.MirrorDimPercent = asInt(v, cfg.MirrorDimPercent, intPtr(10), intPtr(100))
+		}
+		if v, ok := ini.get("mirror", "dark_luma"); ok {
+			cfg.MirrorDarkLuma = asInt(v, cfg.MirrorDarkLuma, intPtr(0), intPtr(255))
+		}
+	}
+
+	// [gsensor]
+	if ini.hasSection("gsensor") {
+		if v, ok := ini.get("gsensor", "enabled"); ok {
+			cfg.GSensorEnabled = asBool(v, cfg.GSensorEnabled)
+		}
+		if v, ok := ini.get("gsensor", "i2c_bus"); ok && strings.TrimSpace(v) != "" {
+			cfg.GSensorBus = strings.TrimSpace(v)
+		}
+		if v, ok := ini.get("gsensor", "address"); ok {
+			// Accept 0x68 as well as 104
+			if addr, err := strconv.ParseInt(strings.TrimSpace(v), 0, 0); err == nil && addr > 0 && addr < 0x80 {
+				cfg.GSensorAddress = int(addr)
+			}
+		}
+		if v, ok := ini.get("gsensor", "threshold_g"); ok {
+			cfg.GSensorThresholdG = asFloat(v, cfg.GSensorThresholdG, floatPtr(0.5), floatPtr(16))
+		}
+		if v, ok := ini.get("gsensor", "sample_hz"); ok {
+			cfg.GSensorSampleHz = asInt(v, cfg.GSensorSampleHz, intPtr(10), intPtr(1000))
+		}
+		if v, ok := ini.get("gsensor", "cooldown_sec"); ok {
+			cfg.GSensorCooldownSec = asFloat(v, cfg.GSensorCooldownSec, floatPtr(0), nil)
+		}
+	}
+
+	// [auto_night]
+	if ini.hasSection("auto_night") {
+		if v, ok := ini.get("auto_night", "source"); ok {
+			switch v = strings.ToLower(strings.TrimSpace(v)); v {
+			case "off", "luma", "schedule", "sensor":
+				cfg.AutoNightSource = v
+			}
+		}
+		if v, ok := ini.get("auto_night", "dark_luma"); ok {
+			cfg.AutoNightDarkLuma = asInt(v, cfg.AutoNightDarkLuma, intPtr(1), intPtr(240))
+		}
+		if v, ok := ini.get("auto_night", "latitude"); ok {
+			cfg.AutoNightLatitude = asFloat(v, cfg.AutoNightLatitude, floatPtr(-90), floatPtr(90))
+		}
+		if v, ok := ini.get("auto_night", "longitude"); ok {
+			cfg.AutoNightLongitude = asFloat(v, cfg.AutoNightLongitude, floatPtr(-180), floatPtr(180))
+		}
+		if v, ok := ini.get("auto_night", "i2c_bus"); ok && strings.TrimSpace(v) != "" {
+			cfg.AutoNightSensorBus = strings.TrimSpace(v)
+		}
+		if v, ok := ini.get("auto_night", "address"); ok {
+			// Accept 0x23 as well as 35
+			if addr, err := strconv.ParseInt(strings.TrimSpace(v), 0, 0); err == nil && addr > 0 && addr < 0x80 {
+				cfg.AutoNightSensorAddr = int(addr)
+			}
+		}
+		if v, ok := ini.get("auto_night", "dark_lux"); ok {
+			cfg.AutoNightDarkLux = asFloat(v, cfg.AutoNightDarkLux, floatPtr(0.1), floatPtr(10000))
+		}
+		if v, ok := ini.get("auto_night", "hold_sec"); ok {
+			cfg.AutoNightHoldSec = asFloat(v, cfg.AutoNightHoldSec, floatPtr(0), floatPtr(3600))
+		}
+	}
+
+	// [battery]
+	if ini.hasSection("battery") {
+		if v, ok := ini.get("battery", "enabled"); ok {
+			cfg.BatteryEnabled = asBool(v, cfg.BatteryEnabled)
+		}
+		if v, ok := ini.get("battery", "adc"); ok {
+			switch v = strings.ToLower(strings.TrimSpace(v)); v {
+			case "ina219", "ads1115":
+				cfg.BatteryADC = v
+			}
+		}
+		if v, ok := ini.get("battery", "i2c_bus"); ok && strings.TrimSpace(v) != "" {
+			cfg.BatteryBus = strings.TrimSpace(v)
+		}
+		if v, ok := ini.get("battery", "address"); ok {
+			// Accept 0x40 as well as 64; empty = the ADC's default
+			if addr, err := strconv.ParseInt(strings.TrimSpace(v), 0, 0); err == nil && addr > 0 && addr < 0x80 {
+				cfg.BatteryAddress = int(addr)
+			}
+		}
+		if v, ok := ini.get("battery", "channel"); ok {
+			cfg.BatteryChannel = asInt(v, cfg.BatteryChannel, intPtr(0), intPtr(3))
+		}
+		if v, ok := ini.get("battery", "divider"); ok {
+			cfg.BatteryDivider = asFloat(v, cfg.BatteryDivider, floatPtr(1), floatPtr(100))
+		}
+		if v, ok := ini.get("battery", "shutdown_v"); ok {
+			cfg.BatteryShutdownV = asFloat(v, cfg.BatteryShutdownV, floatPtr(0), floatPtr(60))
+		}
+		if v, ok := ini.get("battery", "shutdown_delay_sec"); ok {
+			cfg.BatteryShutdownDelaySec = asFloat(v, cfg.BatteryShutdownDelaySec, floatPtr(0), floatPtr(3600))
+		}
+		if v, ok := ini.get("battery", "shutdown_command"); ok {
+			cfg.BatteryShutdownCmd = strings.TrimSpace(v)
+		}
+	}
+
+	// [lock]
+	if ini.hasSection("lock") {
+		if v, ok := ini.get("lock", "pin"); ok {
+			cfg.LockPIN = strings.TrimSpace(v)
+		}
+		if v, ok := ini.get("lock", "unlock_sec"); ok {
+			cfg.LockUnlockSec = asFloat(v, cfg.LockUnlockSec, floatPtr(10), floatPtr(3600))
+		}
+		if v, ok := ini.get("lock", "max_attempts"); ok {
+			cfg.LockMaxAttempts = asInt(v, cfg.LockMaxAttempts, intPtr(1), intPtr(20))
+		}
+		if v, ok := ini.get("lock", "lockout_sec"); ok {
+			cfg.LockLockoutSec = asFloat(v, cfg.LockLockoutSec, floatPtr(0), floatPtr(3600))
+		}
+	}
+
+	// [gestures]
+	if ini.hasSection("gestures") {
+		for key, field := range map[string]*string{
+			"tap":            &cfg.GestureTap,
+			"double_tap":     &cfg.GestureDoubleTap,
+			"long_press":     &cfg.GestureLongPress,
+			"two_finger_tap": &cfg.GestureTwoFingerTap,
+		} {
+			if v, ok := ini.get("gestures", key); ok {
+				if action := strings.ToLower(strings.TrimSpace(v)); isGestureAction(action) {
+					*field = action
+				}
+			}
+		}
+	}
+
+	// [gps]
+	if ini.hasSection("gps") {
+		if v, ok := ini.get("gps", "source"); ok {
+			cfg.GPSSource = strings.TrimSpace(v)
+		}
+		if v, ok := ini.get("gps", "units"); ok {
+			if u := strings.ToLower(strings.TrimSpace(v)); u == "kmh" || u == "mph" {
+				cfg.GPSUnits = u
+			}
+		}
+		if v, ok := ini.get("gps", "overlay"); ok {
+			cfg.GPSOverlay = asBool(v, cfg.GPSOverlay)
+		}
+	}
+
+	// [time]
+	if ini.hasSection("time") {
+		if v, ok := ini.get("time", "sources"); ok {
+			sources := []string{}
+			valid := true
+			for _, src := range splitList(strings.ToLower(v)) {
+				switch src {
+				case "gps", "ntp":
+					sources = append(sources, src)
+				default:
+					valid = false
+				}
+			}
+			if valid {
+				cfg.TimeSources = sources
+			}
+		}
+		if v, ok := ini.get("time", "rename_recordings"); ok {
+			cfg.TimeRenameRecordings = asBool(v, cfg.TimeRenameRecordings)
+		}
+	}
+
+	// [power]: settings plus one key per camera rail
+	if ini.hasSection("power") {
+		for key, v := range ini["power"] {
+			switch key {
+			case "chip":
+				if v = strings.TrimSpace(v); v != "" {
+					cfg.PowerChip = v
+				}
+			case "warmup_sec":
+				cfg.PowerWarmupSec = asFloat(v, cfg.PowerWarmupSec, floatPtr(0), floatPtr(60.0))
+			case "cycle_off_sec":
+				cfg.PowerCycleOffSec = asFloat(v, cfg.PowerCycleOffSec, floatPtr(0.5), floatPtr(60.0))
+			case "off_on_exit":
+				cfg.PowerOffOnExit = asBool(v, cfg.PowerOffOnExit)
+			default:
+				if cfg.CameraPower == nil {
+					cfg.CameraPower = make(map[string]string)
+				}
+				cfg.CameraPower[key] = v
+			}
+		}
+	}
+
+	// [input]: settings plus one key per control
+	if ini.hasSection("input") {
+		for key, v := range ini["input"] {
+			switch key {
+			case "devices":
+				cfg.InputDevices = splitList(v)
+			case "chip":
+				if v = strings.TrimSpace(v); v != "" {
+					cfg.InputChip = v
+				}
+			case "debounce_ms":
+				cfg.InputDebounceMs = asInt(v, cfg.InputDebounceMs, intPtr(0), intPtr(1000))
+			default:
+				if cfg.InputBindings == nil {
+					cfg.InputBindings = make(map[string]string)
+				}
+				cfg.InputBindings[key] = v
+			}
+		}
+	}
+
+	// [alerts]
+	if ini.hasSection("alerts") {
+		if v, ok := ini.get("alerts", "enabled"); ok {
+			cfg.AlertsEnabled = asBool(v, cfg.AlertsEnabled)
+		}
+		if v, ok := ini.get("alerts", "muted"); ok {
+			cfg.AlertsMuted = asBool(v, cfg.AlertsMuted)
+		}
+		if v, ok := ini.get("alerts", "backend"); ok {
+			if v = strings.ToLower(strings.TrimSpace(v)); v == "aplay" || v == "beep" {
+				cfg.AlertBackend = v
+			}
+		}
+		if v, ok := ini.get("alerts", "device"); ok {
+			cfg.AlertDevice = strings.TrimSpace(v)
+		}
+		if v, ok := ini.get("alerts", "volume"); ok {
+			cfg.AlertVolume = asInt(v, cfg.AlertVolume, intPtr(0), intPtr(100))
+		}
+		for key, field := range map[string]*string{
+			"disconnect_tones":    &cfg.AlertDisconnectTones,
+			"reverse_stale_tones": &cfg.AlertReverseStaleTones,
+			"over_temp_tones":     &cfg.AlertOverTempTones,
+		} {
+			if v, ok := ini.get("alerts", key); ok && strings.TrimSpace(v) != "" {
+				*field = strings.TrimSpace(v)
+			}
+		}
+		if v, ok := ini.get("alerts", "over_temp_c"); ok {
+			cfg.AlertOverTempC = asFloat(v, cfg.AlertOverTempC, floatPtr(0), floatPtr(110))
+		}
+		if v, ok := ini.get("alerts", "reverse_gpio"); ok {
+			cfg.AlertReverseGPIO = asInt(v, cfg.AlertReverseGPIO, intPtr(-1), nil)
+		}
+		if v, ok := ini.get("alerts", "reverse_active_high"); ok {
+			cfg.AlertReverseActiveHigh = asBool(v, cfg.AlertReverseActiveHigh)
+		}
+		if v, ok := ini.get("alerts", "rear_camera"); ok && strings.TrimSpace(v) != "" {
+			cfg.AlertRearCamera = strings.TrimSpace(v)
+		}
+	}
+
+	// [usb]
+	if ini.hasSection("usb") {
+		if v, ok := ini.get("usb", "correlation_window_sec"); ok {
+			cfg.USBCorrelationSec = asFloat(v, cfg.USBCorrelationSec, floatPtr(1.0), floatPtr(60.0))
+		}
+		if v, ok := ini.get("usb", "correlation_min_cameras"); ok {
+			cfg.USBCorrelationMin = asInt(v, cfg.USBCorrelationMin, intPtr(2), nil)
+		}
+		if v, ok := ini.get("usb", "hub_power_cycle_cmd"); ok {
+			cfg.USBHubPowerCycleCmd = strings.TrimSpace(v)
+		}
+		if v, ok := ini.get("usb", "hub_power_cycle_cooldown_sec"); ok {
+			cfg.USBHubCycleCooldownSec = asFloat(v, cfg.USBHubCycleCooldownSec, floatPtr(30.0), nil)
+		}
+	}
+
+	// [storage]
+	if ini.hasSection("storage") {
+		if v, ok := ini.get("storage", "read_only"); ok {
+			cfg.ReadOnlyRoot = asBool(v, cfg.ReadOnlyRoot)
+		}
+		if v, ok := ini.get("storage", "state_dir"); ok {
+			cfg.StateDir = strings.TrimSpace(v)
+		}
+		if v, ok := ini.get("storage", "low_space_mb"); ok {
+			cfg.LowSpaceMB = asInt(v, cfg.LowSpaceMB, intPtr(0), nil)
+		}
+		if v, ok := ini.get("storage", "free_space"); ok {
+			cfg.FreeSpace = asBool(v, cfg.FreeSpace)
+		}
+		for key, quota := range map[string]*int{
+			"snapshots_quota_mb": &cfg.SnapshotsQuotaMB,
+			"clips_quota_mb":     &cfg.ClipsQuotaMB,
+			"trips_quota_mb":     &cfg.TripsQuotaMB,
+			"logs_quota_mb":      &cfg.LogsQuotaMB,
+		} {
+			if v, ok := ini.get("storage", key); ok {
+				*quota = asInt(v, *quota, intPtr(0), nil)
+			}
+		}
+		if v, ok := ini.get("storage", "check_interval_sec"); ok {
+			cfg.StorageIntervalSec = asFloat(v, cfg.StorageIntervalSec, floatPtr(5.0), floatPtr(3600.0))
+		}
+	}
+
+	// [endurance]
+	if ini.hasSection("endurance") {
+		if v, ok := ini.get("endurance", "enabled"); ok {
+			cfg.EnduranceEnabled = asBool(v, cfg.EnduranceEnabled)
+		}
+		if v, ok := ini.get("endurance", "ram_dir"); ok && strings.TrimSpace(v) != "" {
+			cfg.EnduranceRAMDir = strings.TrimSpace(v)
+		}
+		if v, ok := ini.get("endurance", "flush_interval_sec"); ok {
+			cfg.EnduranceFlushSec = asFloat(v, cfg.EnduranceFlushSec, floatPtr(10.0), floatPtr(86400.0))
+		}
+		if v, ok := ini.get("endurance", "flush_mb"); ok {
+			cfg.EnduranceFlushMB = asInt(v, cfg.EnduranceFlushMB, intPtr(1), intPtr(1024))
+		}
+		if v, ok := ini.get("endurance", "log_flush_sec"); ok {
+			cfg.EnduranceLogFlushSec = asFloat(v, cfg.EnduranceLogFlushSec, floatPtr(1.0), floatPtr(600.0))
+		}
+		if v, ok := ini.get("endurance", "stop_recording_mb"); ok {
+			cfg.StopRecordingMB = asInt(v, cfg.StopRecordingMB, intPtr(0), nil)
+		}
+		if v, ok := ini.get("endurance", "wear_device"); ok {
+			cfg.WearDevice = strings.TrimPrefix(strings.TrimSpace(v), "/dev/")
+		}
+		if v, ok := ini.get("endurance", "stop_recording_wear_pct"); ok {
+			cfg.StopRecordingWearPct = asInt(v, cfg.StopRecordingWearPct, intPtr(0), intPtr(100))
+		}
+	}
+
+	// [upload]
+	if ini.hasSection("upload") {
+		if v, ok := ini.get("upload", "enabled"); ok {
+			cfg.UploadEnabled = asBool(v, cfg.UploadEnabled)
+		}
+		if v, ok := ini.get("upload", "target"); ok {
+			switch t := strings.ToLower(strings.TrimSpace(v)); t {
+			case "s3", "webdav", "sftp":
+				cfg.UploadTarget = t
+			}
+		}
+		for key, field := range map[string]*string{
+			"url":           &cfg.UploadURL,
+			"username":      &cfg.UploadUsername,
+			"password":      &cfg.UploadPassword,
+			"access_key":    &cfg.UploadAccessKey,
+			"secret_key":    &cfg.UploadSecretKey,
+			"identity_file": &cfg.UploadIdentity,
+			"ssid":          &cfg.UploadSSID,
+			"check_host":    &cfg.UploadCheckHost,
+		} {
+			if v, ok := ini.get("upload", key); ok {
+				*field = strings.TrimSpace(v)
+			}
+		}
+		if v, ok := ini.get("upload", "region"); ok && strings.TrimSpace(v) != "" {
+			cfg.UploadRegion = strings.TrimSpace(v)
+		}
+		if v, ok := ini.get("upload", "only_protected"); ok {
+			cfg.UploadOnlyProtected = asBool(v, cfg.UploadOnlyProtected)
+		}
+		if v, ok := ini.get("upload", "rate_limit_kb"); ok {
+			cfg.UploadRateKB = asInt(v, cfg.UploadRateKB, intPtr(0), nil)
+		}
+		if v, ok := ini.get("upload", "interval_sec"); ok {
+			cfg.UploadIntervalSec = asFloat(v, cfg.UploadIntervalSec, floatPtr(10.0), floatPtr(3600.0))
+		}
+		if v, ok := ini.get("upload", "state_file"); ok && strings.TrimSpace(v) != "" {
+			cfg.UploadStateFile = strings.TrimSpace(v)
+		}
+	}
+
+	// [mqtt]
+	if ini.hasSection("mqtt") {
+		if v, ok := ini.get("mqtt", "enabled"); ok {
+			cfg.MQTTEnabled = asBool(v, cfg.MQTTEnabled)
+		}
+		if v, ok := ini.get("mqtt", "broker"); ok {
+			cfg.MQTTBroker = v
+		}
+		if v, ok := ini.get("mqtt", "client_id"); ok {
+			cfg.MQTTClientID = v
+		}
+		if v, ok := ini.get("mqtt", "username"); ok {
+			cfg.MQTTUsername = v
+		}
+		if v, ok := ini.get("mqtt", "password"); ok {
+			cfg.MQTTPassword = v
+		}
+		if v, ok := ini.get("mqtt", "topic_prefix"); ok {
+			cfg.MQTTTopicPrefix = strings.Trim(strings.TrimSpace(v), "/")
+		}
+		if v, ok := ini.get("mqtt", "keepalive_sec"); ok {
+			cfg.MQTTKeepAliveSec = asInt(v, cfg.MQTTKeepAliveSec, intPtr(5), intPtr(3600))
+		}
+	}
+
+	// [watchdog]
+	if ini.hasSection("watchdog") {
+		if v, ok := ini.get("watchdog", "enabled"); ok {
+			cfg.WatchdogEnabled = asBool(v, cfg.WatchdogEnabled)
+		}
+		if v, ok := ini.get("watchdog", "check_interval_sec"); ok {
+			cfg.WatchdogCheckIntervalSec = asFloat(v, cfg.WatchdogCheckIntervalSec, floatPtr(0.5), nil)
+		}
+		if v, ok := ini.get("watchdog", "ui_timeout_sec"); ok {
+			cfg.WatchdogUITimeoutSec = asFloat(v, cfg.WatchdogUITimeoutSec, floatPtr(2.0), nil)
+		}
+		if v, ok := ini.get("watchdog", "capture_timeout_sec"); ok {
+			cfg.WatchdogCaptureTimeoutSec = asFloat(v, cfg.WatchdogCaptureTimeoutSec, floatPtr(2.0), nil)
+		}
+		if v, ok := ini.get("watchdog", "max_recoveries"); ok {
+			cfg.WatchdogMaxRecoveries = asInt(v, cfg.WatchdogMaxRecoveries, intPtr(1), nil)
+		}
+	}
+
+	// [soak] - undocumented bench-test section
+	if ini.hasSection("soak") {
+		if v, ok := ini.get("soak", "enabled"); ok {
+			cfg.SoakEnabled = asBool(v, cfg.SoakEnabled)
+		}
+		if v, ok := ini.get("soak", "kill_ffmpeg_per_hour"); ok {
+			cfg.SoakKillFFmpegPerHour = asFloat(v, cfg.SoakKillFFmpegPerHour, floatPtr(0), nil)
+		}
+		if v, ok := ini.get("soak", "corrupt_jpeg_rate"); ok {
+			cfg.SoakCorruptJPEGRate = asFloat(v, cfg.SoakCorruptJPEGRate, floatPtr(0), floatPtr(1))
+		}
+		if v, ok := ini.get("soak", "delay_frame_rate"); ok {
+			cfg.SoakDelayFrameRate = asFloat(v, cfg.SoakDelayFrameRate, floatPtr(0), floatPtr(1))
+		}
+		if v, ok := ini.get("soak", "delay_frame_max_ms"); ok {
+			cfg.SoakDelayFrameMaxMS = asInt(v, cfg.SoakDelayFrameMaxMS, intPtr(0), nil)
+		}
+		if v, ok := ini.get("soak", "device_removal_per_hour"); ok {
+			cfg.SoakDeviceRemovalPerHour = asFloat(v, cfg.SoakDeviceRemovalPerHour, floatPtr(0), nil)
+		}
+		if v, ok := ini.get("soak", "device_removal_sec"); ok {
+			cfg.SoakDeviceRemovalSec = asFloat(v, cfg.SoakDeviceRemovalSec, floatPtr(1), nil)
+		}
+		if v, ok := ini.get("soak", "seed"); ok {
+			cfg.SoakSeed = asInt(v, cfg.SoakSeed, nil, nil)
+		}
 	}
 }
 
@@ -405,8 +1962,25 @@ func applyINI(cfg *Config, ini iniData) {
 //
 // Returns (width, height, captureFPS, uiFPS).
 func (c *Config) ChooseProfile(cameraCount int) (int, int, int, int) {
+	return c.ChooseProfileTier(cameraCount, 0)
+}
+
+// ChooseProfileTier is ChooseProfile at an adaptive resolution tier:
+// tier 0 is the profile resolution, tier n the nth resolution_tiers
+// entry (clamped to the last). A tier never exceeds the profile size.
+func (c *Config) ChooseProfileTier(cameraCount, tier int) (int, int, int, int) {
 	_ = cameraCount // Reserved for future profile variants; parity currently ignores count.
-	return c.CaptureWidth, c.CaptureHeight, c.CaptureFPS, c.UIFPS
+	w, h := c.CaptureWidth, c.CaptureHeight
+	if tier > len(c.ResolutionTiers) {
+		tier = len(c.ResolutionTiers)
+	}
+	if tier > 0 {
+		r := c.ResolutionTiers[tier-1]
+		if r.Width*r.Height < w*h {
+			w, h = r.Width, r.Height
+		}
+	}
+	return w, h, c.CaptureFPS, c.UIFPS
 }
 
 // roundDown16 rounds n down to the nearest multiple of 16.
@@ -422,6 +1996,14 @@ func max16(minimum, value int) int {
 	return value
 }
 
+// intMin returns the smaller of a and b.
+func intMin(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
 // intMax returns the larger of a and b.
 func intMax(a, b int) int {
 	if a > b {
@@ -461,9 +2043,73 @@ func (c *Config) Validate() (ok bool, warnings []string) {
 		warnings = append(warnings, fmt.Sprintf("MinDynamicFPS (%d) > CaptureFPS (%d)", c.MinDynamicFPS, c.CaptureFPS))
 	}
 
+	if c.DynamicFPSEnabled && c.FPSStepDown <= c.UIFPSStep {
+		warnings = append(warnings, fmt.Sprintf("[performance] fps_step_down (%d) <= ui_fps_step (%d) - the controller may oscillate", c.FPSStepDown, c.UIFPSStep))
+	}
+
+	if !c.Thermal.Ordered() {
+		t := c.ThermalLimits()
+		warnings = append(warnings, fmt.Sprintf("[performance] temp_*_c must increase ideal < comfort < warm < hot < critical - using the %s defaults (%.0f/%.0f/%.0f/%.0f/%.0f°C)",
+			c.ThermalProfile, t.IdealC, t.ComfortC, t.WarmC, t.HotC, t.CriticalC))
+	}
+
 	if c.UIFPS > 60 {
 		warnings = append(warnings, "UI FPS > 60 is wasteful and likely unsupported")
 	}
 
+	for _, cpus := range []struct{ key, value string }{
+		{"ffmpeg_cpus", c.FFmpegCPUs},
+		{"capture_cpus", c.CaptureCPUs},
+	} {
+		if _, err := helpers.ValidateCPUList(cpus.value); err != nil {
+			warnings = append(warnings, fmt.Sprintf("[cpu] %s ignored: %v", cpus.key, err))
+		}
+	}
+
+	if _, err := helpers.ParseCIDRs(c.HealthAllowedCIDRs); err != nil {
+		warnings = append(warnings, fmt.Sprintf("[health] allowed_cidrs: %v - the HTTP endpoints accept loopback only", err))
+	}
+	if c.DebugHTTPAddr != "" && !helpers.IsLoopbackAddr(c.DebugHTTPAddr) && len(c.HealthAllowedCIDRs) == 0 {
+		warnings = append(warnings, fmt.Sprintf("[health] debug_addr %s is not loopback and allowed_cidrs is empty - the debug endpoint is off (pprof has no authentication)", c.DebugHTTPAddr))
+	}
+
+	if _, err := helpers.ParseIOClass(c.WriteIOClass); err != nil {
+		warnings = append(warnings, fmt.Sprintf("[cpu] write_io_class ignored: %v", err))
+	}
+
+	if _, err := helpers.ParseGridLayouts(c.GridLayouts); err != nil {
+		warnings = append(warnings, fmt.Sprintf("[display] grid_layouts ignored: %v - using automatic layouts", err))
+	}
+	if c.AutoNightSource == "schedule" && c.AutoNightLatitude == 0 && c.AutoNightLongitude == 0 {
+		warnings = append(warnings, "[auto_night] source = schedule needs latitude and longitude - automatic night mode is off")
+	}
+	if c.BatteryEnabled && c.BatteryADC == "ads1115" && c.BatteryDivider == 1 {
+		warnings = append(warnings, "[battery] adc = ads1115 measures at most 4.096 V - wire the battery through a voltage divider and set divider")
+	}
+	if c.LockPIN != "" && !ValidPIN(c.LockPIN) {
+		warnings = append(warnings, "[lock] pin must be 4-8 digits - the settings are not locked")
+	}
+	for _, key := range ThemeColorKeys {
+		if v, ok := c.ThemeColors[key]; ok {
+			if _, err := helpers.ParseHexColor(v); err != nil {
+				warnings = append(warnings, fmt.Sprintf("[theme] %s ignored: %v - using the %s theme's", key, err, c.ThemeName))
+			}
+		}
+	}
+
+	switch c.BrightnessPercent {
+	case 15, 60, 80, 100, 150:
+	default:
+		warnings = append(warnings, fmt.Sprintf("[display] brightness %d%% is not a preset (15/60/80/100/150) - using 100%%", c.BrightnessPercent))
+	}
+
+	if c.SoakEnabled {
+		warnings = append(warnings, "Soak-test fault injection is ENABLED - cameras will fail on purpose")
+	}
+
+	if c.MQTTEnabled && (c.MQTTBroker == "" || c.MQTTTopicPrefix == "") {
+		warnings = append(warnings, "MQTT enabled but broker or topic_prefix is empty - MQTT disabled")
+	}
+
 	return ok, warnings
 }
@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 )
@@ -25,6 +26,9 @@ func TestDefaultConfig(t *testing.T) {
 	if cfg.LogMaxBytes != 5*1024*1024 {
 		t.Errorf("LogMaxBytes = %d, want %d", cfg.LogMaxBytes, 5*1024*1024)
 	}
+	if cfg.LogRotateDaily || cfg.LogCompress {
+		t.Errorf("LogRotateDaily/LogCompress = %v/%v, want false/false (size rotation only, as before)", cfg.LogRotateDaily, cfg.LogCompress)
+	}
 	if cfg.DynamicFPSEnabled != true {
 		t.Errorf("DynamicFPSEnabled = %v, want true", cfg.DynamicFPSEnabled)
 	}
@@ -49,6 +53,15 @@ func TestDefaultConfig(t *testing.T) {
 	if cfg.KillDeviceHolders != true {
 		t.Errorf("KillDeviceHolders = %v, want true", cfg.KillDeviceHolders)
 	}
+	if !cfg.CSICameras {
+		t.Error("CSICameras should default to true")
+	}
+	if cfg.ReconnectBudget != 5 {
+		t.Errorf("ReconnectBudget = %d, want 5", cfg.ReconnectBudget)
+	}
+	if !cfg.TestPattern {
+		t.Error("TestPattern should default to true")
+	}
 	if cfg.CameraSlotCount != 3 {
 		t.Errorf("CameraSlotCount = %d, want 3", cfg.CameraSlotCount)
 	}
@@ -230,6 +243,49 @@ func TestParseINI_MissingFile(t *testing.T) {
 	}
 }
 
+func TestLoad_Warnings(t *testing.T) {
+	content := `stray = 1
+[profile]
+capture_fbs = 15
+capture_fps = 20
+capture_fps = abc
+capture_width = 99999
+capture_format = mjepg
+
+[performance]
+dynamic_fps = maybe
+not a setting
+
+[camerra]
+slot_count = 2
+
+[names]
+video0 = Rear
+`
+	cfg, err := Load(writeTempFile(t, content))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		`line 1: stray is outside a [section], ignored`,
+		`line 3: [profile] capture_fbs: unknown key, ignored (did you mean capture_fps?)`,
+		`line 5: [profile] capture_fps: set again, overrides line 4`,
+		`line 11: [performance]: "not a setting" is not a key = value line, ignored`,
+		`line 13: [camerra]: unknown section, ignored`,
+		`[performance] dynamic_fps: "maybe" is not true or false, using true`,
+		`[profile] capture_width: "99999" is out of range, using 1920`,
+		`[profile] capture_fps: "abc" is not a whole number, using 25`,
+		`[profile] capture_format: "mjepg" is not valid, using mjpeg`,
+	}
+	var got []string
+	for _, w := range cfg.Warnings {
+		got = append(got, w.String())
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("warnings:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
 // =============================================================================
 // Load tests
 // =============================================================================
@@ -253,6 +309,8 @@ level = DEBUG
 file = /tmp/test.log
 max_bytes = 1048576
 backup_count = 5
+rotate_daily = true
+compress = true
 stdout = false
 
 [performance]
@@ -270,8 +328,11 @@ restart_window_sec = 60.0
 [camera]
 rescan_interval_ms = 20000
 failed_camera_cooldown_sec = 60.0
+reconnect_budget = 8
+test_pattern = false
 slot_count = 4
 kill_device_holders = false
+csi = false
 
 [profile]
 capture_width = 1280
@@ -281,6 +342,7 @@ ui_fps = 25
 
 [health]
 log_interval_sec = 60
+first_frame_warn_sec = 8
 `
 	tmp := writeTempFile(t, content)
 
@@ -302,6 +364,9 @@ log_interval_sec = 60
 	if cfg.LogBackupCount != 5 {
 		t.Errorf("LogBackupCount = %d, want 5", cfg.LogBackupCount)
 	}
+	if !cfg.LogRotateDaily || !cfg.LogCompress {
+		t.Errorf("LogRotateDaily/LogCompress = %v/%v, want true/true", cfg.LogRotateDaily, cfg.LogCompress)
+	}
 	if cfg.LogToStdout != false {
 		t.Errorf("LogToStdout = %v, want false", cfg.LogToStdout)
 	}
@@ -332,6 +397,15 @@ log_interval_sec = 60
 	if cfg.KillDeviceHolders != false {
 		t.Errorf("KillDeviceHolders = %v, want false", cfg.KillDeviceHolders)
 	}
+	if cfg.CSICameras {
+		t.Error("CSICameras = true, want false")
+	}
+	if cfg.ReconnectBudget != 8 {
+		t.Errorf("ReconnectBudget = %d, want 8", cfg.ReconnectBudget)
+	}
+	if cfg.TestPattern {
+		t.Error("TestPattern = true, want false")
+	}
 	if cfg.HealthLogIntervalSec != 60.0 {
 		t.Errorf("HealthLogIntervalSec = %f, want 60.0", cfg.HealthLogIntervalSec)
 	}
@@ -341,6 +415,9 @@ log_interval_sec = 60
 	if cfg.MaxRestartsPerWindow != 5 {
 		t.Errorf("MaxRestartsPerWindow = %d, want 5", cfg.MaxRestartsPerWindow)
 	}
+	if cfg.FirstFrameWarnSec != 8 {
+		t.Errorf("FirstFrameWarnSec = %v, want 8", cfg.FirstFrameWarnSec)
+	}
 }
 
 func TestLoad_PartialINI(t *testing.T) {
@@ -458,6 +535,46 @@ cpu_load_threshold = 5.0
 	}
 }
 
+func TestLoad_MQTTSection(t *testing.T) {
+	content := `
+[mqtt]
+enabled = true
+broker = 10.0.0.2:1883
+username = car
+password = secret
+topic_prefix = /vehicle/dash/
+keepalive_sec = 1
+`
+	tmp := writeTempFile(t, content)
+
+	cfg, err := Load(tmp)
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+
+	if !cfg.MQTTEnabled {
+		t.Error("MQTTEnabled = false, want true")
+	}
+	if cfg.MQTTBroker != "10.0.0.2:1883" {
+		t.Errorf("MQTTBroker = %q, want %q", cfg.MQTTBroker, "10.0.0.2:1883")
+	}
+	if cfg.MQTTUsername != "car" || cfg.MQTTPassword != "secret" {
+		t.Errorf("MQTT credentials = %q/%q, want car/secret", cfg.MQTTUsername, cfg.MQTTPassword)
+	}
+	// Leading/trailing slashes are trimmed so topics join cleanly
+	if cfg.MQTTTopicPrefix != "vehicle/dash" {
+		t.Errorf("MQTTTopicPrefix = %q, want %q", cfg.MQTTTopicPrefix, "vehicle/dash")
+	}
+	// keepalive_sec min is 5
+	if cfg.MQTTKeepAliveSec != 5 {
+		t.Errorf("MQTTKeepAliveSec = %d, want 5 (clamped)", cfg.MQTTKeepAliveSec)
+	}
+	// Client ID keeps its default
+	if cfg.MQTTClientID != "camera-dashboard" {
+		t.Errorf("MQTTClientID = %q, want default", cfg.MQTTClientID)
+	}
+}
+
 // =============================================================================
 // ChooseProfile tests
 // =============================================================================
@@ -555,6 +672,26 @@ func TestChooseProfile_NoCameraCountScaling(t *testing.T) {
 	}
 }
 
+func TestChooseProfileTier(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.ResolutionTiers = []Resolution{{480, 360}, {800, 600}}
+
+	tests := []struct {
+		tier, w, h int
+	}{
+		{0, 640, 480},
+		{1, 480, 360},
+		{2, 640, 480}, // Larger than the profile: stays at the profile size
+		{9, 640, 480},
+	}
+	for _, tt := range tests {
+		w, h, fps, _ := cfg.ChooseProfileTier(3, tt.tier)
+		if w != tt.w || h != tt.h || fps != 25 {
+			t.Errorf("tier %d = %dx%d@%d, want %dx%d@25", tt.tier, w, h, fps, tt.w, tt.h)
+		}
+	}
+}
+
 // =============================================================================
 // Validate tests
 // =============================================================================
@@ -645,6 +782,39 @@ func TestValidate_MinDynamicFPSTooHigh(t *testing.T) {
 	}
 }
 
+func TestValidate_DebugAddrNotLoopback(t *testing.T) {
+	debugWarning := func(cfg *Config) bool {
+		_, warnings := cfg.Validate()
+		for _, w := range warnings {
+			if strings.Contains(w, "debug_addr") {
+				return true
+			}
+		}
+		return false
+	}
+	cfg := DefaultConfig()
+	for addr, want := range map[string]bool{
+		"":               false,
+		"127.0.0.1:6060": false,
+		"localhost:6060": false,
+		"[::1]:6060":     false,
+		":6060":          true,
+		"0.0.0.0:6060":   true,
+		"192.168.1.5:80": true,
+	} {
+		cfg.DebugHTTPAddr = addr
+		if got := debugWarning(cfg); got != want {
+			t.Errorf("debug_addr %q: warning %v, want %v", addr, got, want)
+		}
+	}
+
+	cfg.DebugHTTPAddr = ":6060"
+	cfg.HealthAllowedCIDRs = []string{"192.168.1.0/24"}
+	if debugWarning(cfg) {
+		t.Error("warning with allowed_cidrs set")
+	}
+}
+
 // =============================================================================
 // roundDown16 tests
 // =============================================================================
@@ -706,3 +876,885 @@ func writeTempFile(t *testing.T, content string) string {
 	}
 	return tmp
 }
+
+func TestLoad_SoakSection(t *testing.T) {
+	content := `
+[soak]
+enabled = true
+kill_ffmpeg_per_hour = 30
+corrupt_jpeg_rate = 5
+delay_frame_max_ms = 500
+seed = 42
+`
+	cfg, err := Load(writeTempFile(t, content))
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if !cfg.SoakEnabled {
+		t.Error("SoakEnabled = false, want true")
+	}
+	if cfg.SoakKillFFmpegPerHour != 30 {
+		t.Errorf("SoakKillFFmpegPerHour = %v, want 30", cfg.SoakKillFFmpegPerHour)
+	}
+	if cfg.SoakCorruptJPEGRate != 1 {
+		t.Errorf("SoakCorruptJPEGRate = %v, want clamped to 1", cfg.SoakCorruptJPEGRate)
+	}
+	if cfg.SoakDelayFrameMaxMS != 500 || cfg.SoakSeed != 42 {
+		t.Errorf("SoakDelayFrameMaxMS/SoakSeed = %d/%d, want 500/42", cfg.SoakDelayFrameMaxMS, cfg.SoakSeed)
+	}
+
+	_, warnings := cfg.Validate()
+	found := false
+	for _, w := range warnings {
+		if strings.Contains(w, "fault injection") {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("Validate() should warn when soak mode is enabled")
+	}
+}
+
+func TestLoad_CPUSection(t *testing.T) {
+	content := `
+[cpu]
+gomaxprocs = 3
+ffmpeg_cpus = 0
+capture_cpus = 2-a
+ffmpeg_nice = 40
+write_io_class = Idle
+`
+	cfg, err := Load(writeTempFile(t, content))
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if cfg.GoMaxProcs != 3 || cfg.FFmpegCPUs != "0" || cfg.CaptureCPUs != "2-a" {
+		t.Errorf("cpu = (%d, %q, %q), want (3, \"0\", \"2-a\")", cfg.GoMaxProcs, cfg.FFmpegCPUs, cfg.CaptureCPUs)
+	}
+
+	if cfg.FFmpegNice != 19 || cfg.WriteIOClass != "idle" || cfg.WriteIOLevel != 7 {
+		t.Errorf("priority = (%d, %q, %d), want (19, \"idle\", 7)", cfg.FFmpegNice, cfg.WriteIOClass, cfg.WriteIOLevel)
+	}
+
+	_, warnings := cfg.Validate()
+	var cpuWarnings []string
+	for _, w := range warnings {
+		if strings.HasPrefix(w, "[cpu]") {
+			cpuWarnings = append(cpuWarnings, w)
+		}
+	}
+	if len(cpuWarnings) != 1 || !strings.Contains(cpuWarnings[0], "capture_cpus") {
+		t.Errorf("cpu warnings = %v, want one for capture_cpus", cpuWarnings)
+	}
+}
+
+func TestLoad_CapsCacheFileNextToConfig(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.ini")
+	if err := os.WriteFile(path, []byte("[camera]\nslot_count = 2\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if want := filepath.Join(dir, "camera_caps.json"); cfg.CapsCacheFile != want {
+		t.Errorf("CapsCacheFile = %q, want %q", cfg.CapsCacheFile, want)
+	}
+
+	if err := os.WriteFile(path, []byte("[camera]\ncaps_cache_file =\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, _ = Load(path)
+	if cfg.CapsCacheFile != "" {
+		t.Errorf("CapsCacheFile = %q, want empty (disabled)", cfg.CapsCacheFile)
+	}
+}
+
+func TestLoad_DisplaySection(t *testing.T) {
+	cfg, err := Load(writeTempFile(t, "[display]\nnight_mode = yes\nbrightness = 70\npip_corners = Top-Left, middle, bottom-right\npip_size = 80\n"))
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if !cfg.NightMode || cfg.BrightnessPercent != 70 {
+		t.Errorf("display = (%v, %d), want (true, 70)", cfg.NightMode, cfg.BrightnessPercent)
+	}
+	if !reflect.DeepEqual(cfg.PIPCorners, []string{"top-left", "bottom-right"}) || cfg.PIPSizePercent != 50 {
+		t.Errorf("pip = (%v, %d), want ([top-left bottom-right], 50)", cfg.PIPCorners, cfg.PIPSizePercent)
+	}
+
+	_, warnings := cfg.Validate()
+	found := false
+	for _, w := range warnings {
+		if strings.HasPrefix(w, "[display] brightness") {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("warnings = %v, want one for non-preset brightness", warnings)
+	}
+}
+
+func TestLoad_HiddenCameraFPS(t *testing.T) {
+	if cfg := DefaultConfig(); cfg.HiddenCameraFPS != 2 {
+		t.Errorf("default HiddenCameraFPS = %d, want 2", cfg.HiddenCameraFPS)
+	}
+	for _, tc := range []struct {
+		value string
+		want  int
+	}{{"0", 0}, {"1", 1}, {"30", 5}, {"fast", 2}} {
+		cfg, err := Load(writeTempFile(t, "[display]\nhidden_camera_fps = "+tc.value+"\n"))
+		if err != nil {
+			t.Fatalf("Load() error: %v", err)
+		}
+		if cfg.HiddenCameraFPS != tc.want {
+			t.Errorf("hidden_camera_fps = %s: got %d, want %d", tc.value, cfg.HiddenCameraFPS, tc.want)
+		}
+	}
+}
+
+func TestLoad_DrivingSpeed(t *testing.T) {
+	if cfg := DefaultConfig(); cfg.DrivingSpeed != 0 {
+		t.Errorf("default DrivingSpeed = %d, want 0", cfg.DrivingSpeed)
+	}
+	for _, tc := range []struct {
+		value string
+		want  int
+	}{{"0", 0}, {"15", 15}, {"500", 200}, {"-5", 0}, {"fast", 0}} {
+		cfg, err := Load(writeTempFile(t, "[display]\ndriving_speed = "+tc.value+"\n"))
+		if err != nil {
+			t.Fatalf("Load() error: %v", err)
+		}
+		if cfg.DrivingSpeed != tc.want {
+			t.Errorf("driving_speed = %s: got %d, want %d", tc.value, cfg.DrivingSpeed, tc.want)
+		}
+	}
+}
+
+func TestLoad_FreezeIndicatorMS(t *testing.T) {
+	if cfg := DefaultConfig(); cfg.FreezeIndicatorMS != 500 {
+		t.Errorf("default FreezeIndicatorMS = %d, want 500", cfg.FreezeIndicatorMS)
+	}
+	for _, tc := range []struct {
+		value string
+		want  int
+	}{{"0", 0}, {"250", 250}, {"60000", 10000}, {"-1", 0}, {"soon", 500}} {
+		cfg, err := Load(writeTempFile(t, "[display]\nfreeze_indicator_ms = "+tc.value+"\n"))
+		if err != nil {
+			t.Fatalf("Load() error: %v", err)
+		}
+		if cfg.FreezeIndicatorMS != tc.want {
+			t.Errorf("freeze_indicator_ms = %s: got %d, want %d", tc.value, cfg.FreezeIndicatorMS, tc.want)
+		}
+	}
+}
+
+func TestLoad_NightPalette(t *testing.T) {
+	cfg := DefaultConfig()
+	if cfg.NightGain != 1.6 || cfg.NightTint != "red" || cfg.NightGamma != 1 {
+		t.Errorf("defaults = %v, %q, %v; want 1.6, red, 1", cfg.NightGain, cfg.NightTint, cfg.NightGamma)
+	}
+	cfg, err := Load(writeTempFile(t, "[display]\nnight_gain = 2.2\nnight_tint = Amber\nnight_gamma = 1.8\n"))
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if cfg.NightGain != 2.2 || cfg.NightTint != "amber" || cfg.NightGamma != 1.8 {
+		t.Errorf("got %v, %q, %v; want 2.2, amber, 1.8", cfg.NightGain, cfg.NightTint, cfg.NightGamma)
+	}
+	cfg, err = Load(writeTempFile(t, "[display]\nnight_gain = 10\nnight_tint = blue\nnight_gamma = 0\n"))
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if cfg.NightGain != 4 || cfg.NightTint != "red" || cfg.NightGamma != 0.2 {
+		t.Errorf("out of range = %v, %q, %v; want 4, red, 0.2", cfg.NightGain, cfg.NightTint, cfg.NightGamma)
+	}
+}
+
+func TestLoad_BatterySection(t *testing.T) {
+	cfg := DefaultConfig()
+	if cfg.BatteryEnabled || cfg.BatteryADC != "ina219" || cfg.BatteryShutdownV != 11.5 {
+		t.Errorf("defaults = %v, %q, %v", cfg.BatteryEnabled, cfg.BatteryADC, cfg.BatteryShutdownV)
+	}
+	cfg, err := Load(writeTempFile(t, `[battery]
+enabled = true
+adc = ADS1115
+address = 0x49
+channel = 7
+divider = 5.7
+shutdown_v = 11.8
+shutdown_delay_sec = 60
+shutdown_command = systemctl poweroff
+`))
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if !cfg.BatteryEnabled || cfg.BatteryADC != "ads1115" || cfg.BatteryAddress != 0x49 || cfg.BatteryChannel != 3 {
+		t.Errorf("got %v, %q, 0x%x, channel %d", cfg.BatteryEnabled, cfg.BatteryADC, cfg.BatteryAddress, cfg.BatteryChannel)
+	}
+	if cfg.BatteryDivider != 5.7 || cfg.BatteryShutdownV != 11.8 || cfg.BatteryShutdownDelaySec != 60 || cfg.BatteryShutdownCmd != "systemctl poweroff" {
+		t.Errorf("got divider %v, shutdown %v V after %v s, %q", cfg.BatteryDivider, cfg.BatteryShutdownV, cfg.BatteryShutdownDelaySec, cfg.BatteryShutdownCmd)
+	}
+	_, warnings := cfg.Validate()
+	for _, w := range warnings {
+		if strings.Contains(w, "[battery]") {
+			t.Errorf("unexpected warning %q", w)
+		}
+	}
+
+	cfg.BatteryDivider = 1
+	found := false
+	_, warnings = cfg.Validate()
+	for _, w := range warnings {
+		found = found || strings.Contains(w, "[battery]")
+	}
+	if !found {
+		t.Error("ads1115 without a divider should warn")
+	}
+}
+
+func TestLoad_LockSection(t *testing.T) {
+	cfg, err := Load(writeTempFile(t, `[lock]
+pin = 0420
+unlock_sec = 1
+max_attempts = 3
+lockout_sec = 30
+`))
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if cfg.LockPIN != "0420" || cfg.LockUnlockSec != 10 || cfg.LockMaxAttempts != 3 || cfg.LockLockoutSec != 30 {
+		t.Errorf("got %q, %v s, %d attempts, %v s lockout", cfg.LockPIN, cfg.LockUnlockSec, cfg.LockMaxAttempts, cfg.LockLockoutSec)
+	}
+	lockWarning := func() bool {
+		_, warnings := cfg.Validate()
+		for _, w := range warnings {
+			if strings.Contains(w, "[lock]") {
+				return true
+			}
+		}
+		return false
+	}
+	if lockWarning() {
+		t.Error("a valid PIN should not warn")
+	}
+	cfg.LockPIN = "12ab"
+	if !lockWarning() {
+		t.Error("a PIN with letters should warn")
+	}
+
+	for pin, want := range map[string]bool{"1234": true, "00000000": true, "123": false, "123456789": false, "12 4": false, "": false} {
+		if ValidPIN(pin) != want {
+			t.Errorf("ValidPIN(%q) = %v, want %v", pin, !want, want)
+		}
+	}
+}
+
+func TestLoad_GesturesSection(t *testing.T) {
+	cfg := DefaultConfig()
+	if cfg.GestureTap != "fullscreen" || cfg.GestureDoubleTap != "none" || cfg.GestureLongPress != "swap" || cfg.GestureTwoFingerTap != "swap" {
+		t.Errorf("defaults = %q, %q, %q, %q", cfg.GestureTap, cfg.GestureDoubleTap, cfg.GestureLongPress, cfg.GestureTwoFingerTap)
+	}
+	cfg, err := Load(writeTempFile(t, `[gestures]
+tap = Info
+double_tap = fullscreen
+long_press = zoom
+two_finger_tap = snapshot
+`))
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if cfg.GestureTap != "info" || cfg.GestureDoubleTap != "fullscreen" || cfg.GestureTwoFingerTap != "snapshot" {
+		t.Errorf("got %q, %q, %q", cfg.GestureTap, cfg.GestureDoubleTap, cfg.GestureTwoFingerTap)
+	}
+	if cfg.GestureLongPress != "swap" {
+		t.Errorf("unknown action: long_press = %q, want the default", cfg.GestureLongPress)
+	}
+}
+
+func TestLoad_GridLayouts(t *testing.T) {
+	cfg, err := Load(writeTempFile(t, "[display]\ngrid_layouts = 4:1x4, 6:3x2\n"))
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if cfg.StripLayout {
+		t.Error("strip_layout should be off by default")
+	}
+	if cfg.GridLayouts != "4:1x4, 6:3x2" {
+		t.Errorf("GridLayouts = %q, want %q", cfg.GridLayouts, "4:1x4, 6:3x2")
+	}
+	gridWarning := func() bool {
+		_, warnings := cfg.Validate()
+		for _, w := range warnings {
+			if strings.HasPrefix(w, "[display] grid_layouts ignored") {
+				return true
+			}
+		}
+		return false
+	}
+	if gridWarning() {
+		t.Error("valid grid_layouts should not warn")
+	}
+	cfg.GridLayouts = "5:2x2"
+	if !gridWarning() {
+		t.Error("grid_layouts with too few cells should warn")
+	}
+
+	if cfg, _ := Load(writeTempFile(t, "[display]\nstrip_layout = true\n")); !cfg.StripLayout {
+		t.Error("strip_layout = true not loaded")
+	}
+}
+
+func TestLoad_HealthTile(t *testing.T) {
+	if cfg := DefaultConfig(); cfg.HealthTile != "off" {
+		t.Errorf("HealthTile default = %q, want off", cfg.HealthTile)
+	}
+	for value, want := range map[string]string{"Extra": "extra", "replace": "replace", "sixth": "off"} {
+		cfg, err := Load(writeTempFile(t, "[display]\nhealth_tile = "+value+"\n"))
+		if err != nil {
+			t.Fatalf("Load() error: %v", err)
+		}
+		if cfg.HealthTile != want {
+			t.Errorf("health_tile = %s: HealthTile = %q, want %q", value, cfg.HealthTile, want)
+		}
+	}
+}
+
+func TestLoad_ToastSeconds(t *testing.T) {
+	if cfg := DefaultConfig(); cfg.ToastSeconds != 4 {
+		t.Errorf("ToastSeconds default = %d, want 4", cfg.ToastSeconds)
+	}
+	for value, want := range map[string]int{"0": 0, "10": 10, "600": 60, "soon": 4} {
+		cfg, err := Load(writeTempFile(t, "[display]\ntoast_seconds = "+value+"\n"))
+		if err != nil {
+			t.Fatalf("Load() error: %v", err)
+		}
+		if cfg.ToastSeconds != want {
+			t.Errorf("toast_seconds = %s: ToastSeconds = %d, want %d", value, cfg.ToastSeconds, want)
+		}
+	}
+}
+
+func TestLoad_DisplayBackend(t *testing.T) {
+	cfg := DefaultConfig()
+	if cfg.DisplayBackend != "fyne" || cfg.FramebufferDevice != "/dev/fb0" {
+		t.Errorf("defaults = %q, %q", cfg.DisplayBackend, cfg.FramebufferDevice)
+	}
+	cfg, err := Load(writeTempFile(t, "[display]\nbackend = Framebuffer\nframebuffer_device = /dev/fb1\n"))
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if cfg.DisplayBackend != "framebuffer" || cfg.FramebufferDevice != "/dev/fb1" {
+		t.Errorf("loaded = %q, %q", cfg.DisplayBackend, cfg.FramebufferDevice)
+	}
+	if cfg, _ := Load(writeTempFile(t, "[display]\nbackend = sdl\nframebuffer_device =\n")); cfg.DisplayBackend != "fyne" || cfg.FramebufferDevice != "/dev/fb0" {
+		t.Errorf("invalid values = %q, %q, want the defaults", cfg.DisplayBackend, cfg.FramebufferDevice)
+	}
+}
+
+func TestLoad_ThemeSection(t *testing.T) {
+	if cfg := DefaultConfig(); cfg.ThemeName != "dark" || cfg.ThemeColors != nil {
+		t.Errorf("defaults = %q, %v", cfg.ThemeName, cfg.ThemeColors)
+	}
+	cfg, err := Load(writeTempFile(t, "[theme]\nname = Daylight\nhighlight = #00c8ff\nborder = none\ntile = grey\nfont = big\n"))
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	want := map[string]string{"highlight": "#00c8ff", "border": "none", "tile": "grey"}
+	if cfg.ThemeName != "daylight" || !reflect.DeepEqual(cfg.ThemeColors, want) {
+		t.Errorf("loaded = %q, %v; want daylight, %v", cfg.ThemeName, cfg.ThemeColors, want)
+	}
+	_, warnings := cfg.Validate()
+	found := false
+	for _, w := range warnings {
+		if strings.HasPrefix(w, "[theme] tile ignored") {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("warnings = %v, want one for tile = grey", warnings)
+	}
+
+	if cfg, _ := Load(writeTempFile(t, "[theme]\nname = neon\n")); cfg.ThemeName != "dark" {
+		t.Errorf("unknown theme = %q, want dark", cfg.ThemeName)
+	}
+}
+
+func TestLoad_AutoNightSection(t *testing.T) {
+	if cfg := DefaultConfig(); cfg.AutoNightSource != "off" || cfg.AutoNightSensorAddr != 0x23 || cfg.AutoNightHoldSec != 60 {
+		t.Errorf("defaults = %q, %#x, %v", cfg.AutoNightSource, cfg.AutoNightSensorAddr, cfg.AutoNightHoldSec)
+	}
+	cfg, err := Load(writeTempFile(t, "[auto_night]\nsource = Schedule\nlatitude = 52.37\nlongitude = 200\n"+
+		"dark_luma = 0\naddress = 0x5c\ndark_lux = 4.5\nhold_sec = 15\n"))
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if cfg.AutoNightSource != "schedule" || cfg.AutoNightLatitude != 52.37 || cfg.AutoNightLongitude != 180 {
+		t.Errorf("schedule = %q, %v, %v", cfg.AutoNightSource, cfg.AutoNightLatitude, cfg.AutoNightLongitude)
+	}
+	if cfg.AutoNightDarkLuma != 1 || cfg.AutoNightSensorAddr != 0x5c || cfg.AutoNightDarkLux != 4.5 || cfg.AutoNightHoldSec != 15 {
+		t.Errorf("luma/address/lux/hold = %d, %#x, %v, %v", cfg.AutoNightDarkLuma, cfg.AutoNightSensorAddr, cfg.AutoNightDarkLux, cfg.AutoNightHoldSec)
+	}
+
+	cfg, _ = Load(writeTempFile(t, "[auto_night]\nsource = schedule\n"))
+	_, warnings := cfg.Validate()
+	found := false
+	for _, w := range warnings {
+		found = found || strings.HasPrefix(w, "[auto_night] source = schedule needs latitude")
+	}
+	if !found {
+		t.Errorf("warnings = %v, want one for the missing position", warnings)
+	}
+	if cfg, _ := Load(writeTempFile(t, "[auto_night]\nsource = moon\n")); cfg.AutoNightSource != "off" {
+		t.Errorf("unknown source = %q, want off", cfg.AutoNightSource)
+	}
+}
+
+func TestLoad_NetworkCamerasSection(t *testing.T) {
+	cfg, err := Load(writeTempFile(t, "[network_cameras]\ntrailer = rtsp://10.0.0.5:554/live?channel=1\n"))
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	want := map[string]string{"trailer": "rtsp://10.0.0.5:554/live?channel=1"}
+	if !reflect.DeepEqual(cfg.NetworkCameras, want) {
+		t.Errorf("NetworkCameras = %v, want %v", cfg.NetworkCameras, want)
+	}
+}
+
+func TestLoad_TransformSection(t *testing.T) {
+	cfg, err := Load(writeTempFile(t, "[transform]\n/dev/video0 = rotate=180\n046d:0825:ABC = mirror, flip\n"))
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	want := map[string]string{"/dev/video0": "rotate=180", "046d:0825:ABC": "mirror, flip"}
+	if !reflect.DeepEqual(cfg.CameraTransforms, want) {
+		t.Errorf("CameraTransforms = %v, want %v", cfg.CameraTransforms, want)
+	}
+}
+
+func TestLoad_NamesSection(t *testing.T) {
+	cfg, err := Load(writeTempFile(t, "[names]\n/dev/video0 = Rear\n046d:0825:ABC = Left blind spot\nvideo4 =\n"))
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	want := map[string]string{"/dev/video0": "Rear", "046d:0825:ABC": "Left blind spot"}
+	if !reflect.DeepEqual(cfg.CameraNames, want) {
+		t.Errorf("CameraNames = %v, want %v", cfg.CameraNames, want)
+	}
+}
+
+func TestLoad_DewarpSection(t *testing.T) {
+	cfg, err := Load(writeTempFile(t, "[dewarp]\nvideo2 = k1=-0.3, k2=0.05\n"))
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	want := map[string]string{"video2": "k1=-0.3, k2=0.05"}
+	if !reflect.DeepEqual(cfg.CameraDewarp, want) {
+		t.Errorf("CameraDewarp = %v, want %v", cfg.CameraDewarp, want)
+	}
+}
+
+func TestLoad_SyncSection(t *testing.T) {
+	cfg, err := Load(writeTempFile(t, "[sync]\nenabled = true\nmax_delay_frames = 20\nlatency_ms = net-trailer:300, 046d:0825:ABC:40, bad\n"))
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if !cfg.SyncEnabled || cfg.SyncMaxDelayFrames != 10 {
+		t.Errorf("SyncEnabled/SyncMaxDelayFrames = %v/%d, want true/10", cfg.SyncEnabled, cfg.SyncMaxDelayFrames)
+	}
+	want := map[string]int{"net-trailer": 300, "046d:0825:ABC": 40}
+	if !reflect.DeepEqual(cfg.SyncLatencyMS, want) {
+		t.Errorf("SyncLatencyMS = %v, want %v", cfg.SyncLatencyMS, want)
+	}
+}
+
+func TestLoad_ResolutionTiers(t *testing.T) {
+	cfg, err := Load(writeTempFile(t, "[performance]\nresolution_tiers = 320x240, bad, 480X360, 100x50\nresolution_step_up_sec = 60\nresolution_hysteresis_c = 50\n"))
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	want := []Resolution{{480, 360}, {320, 240}}
+	if !reflect.DeepEqual(cfg.ResolutionTiers, want) {
+		t.Errorf("ResolutionTiers = %v, want %v", cfg.ResolutionTiers, want)
+	}
+	if cfg.ResolutionStepUpSec != 60 || cfg.ResolutionStepDownSec != 30 || cfg.ResolutionHysteresisC != 30 {
+		t.Errorf("step down/up/hysteresis = %v/%v/%v, want 30/60/30",
+			cfg.ResolutionStepDownSec, cfg.ResolutionStepUpSec, cfg.ResolutionHysteresisC)
+	}
+	if len(DefaultConfig().ResolutionTiers) != 0 {
+		t.Error("adaptive resolution should be off by default")
+	}
+}
+
+func TestLoad_PowerSection(t *testing.T) {
+	cfg, err := Load(writeTempFile(t, "[power]\nchip = /dev/gpiochip4\nwarmup_sec = 5\noff_on_exit = false\nvideo0 = 17\nnet-trailer = 27, active_low\n"))
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	want := map[string]string{"video0": "17", "net-trailer": "27, active_low"}
+	if !reflect.DeepEqual(cfg.CameraPower, want) {
+		t.Errorf("CameraPower = %v, want %v", cfg.CameraPower, want)
+	}
+	if cfg.PowerChip != "/dev/gpiochip4" || cfg.PowerWarmupSec != 5 || cfg.PowerCycleOffSec != 2 || cfg.PowerOffOnExit {
+		t.Errorf("chip/warmup/off/exit = %q/%v/%v/%v", cfg.PowerChip, cfg.PowerWarmupSec, cfg.PowerCycleOffSec, cfg.PowerOffOnExit)
+	}
+}
+
+func TestLoad_InputSection(t *testing.T) {
+	cfg, err := Load(writeTempFile(t, "[input]\ndevices = /dev/input/event2, /dev/input/event3\ndebounce_ms = 5000\ngpio17 = night_mode\nrotate_cw = next_camera\n"))
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	want := map[string]string{"gpio17": "night_mode", "rotate_cw": "next_camera"}
+	if !reflect.DeepEqual(cfg.InputBindings, want) {
+		t.Errorf("InputBindings = %v, want %v", cfg.InputBindings, want)
+	}
+	if !reflect.DeepEqual(cfg.InputDevices, []string{"/dev/input/event2", "/dev/input/event3"}) {
+		t.Errorf("InputDevices = %v", cfg.InputDevices)
+	}
+	if cfg.InputChip != "/dev/gpiochip0" || cfg.InputDebounceMs != 1000 {
+		t.Errorf("chip/debounce = %q/%d, want /dev/gpiochip0/1000", cfg.InputChip, cfg.InputDebounceMs)
+	}
+}
+
+func TestLoad_AlertsSection(t *testing.T) {
+	if cfg := DefaultConfig(); cfg.AlertsEnabled || cfg.AlertBackend != "aplay" || cfg.AlertReverseGPIO != -1 {
+		t.Errorf("defaults = %v/%q/%d, want off/aplay/-1", cfg.AlertsEnabled, cfg.AlertBackend, cfg.AlertReverseGPIO)
+	}
+	cfg, err := Load(writeTempFile(t, "[alerts]\nenabled = true\nmuted = yes\nbackend = BEEP\nvolume = 150\n"+
+		"disconnect_tones = 500:100\nover_temp_tones =\nover_temp_c = 75\nreverse_gpio = 22\nrear_camera = Back\n"))
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if !cfg.AlertsEnabled || !cfg.AlertsMuted || cfg.AlertBackend != "beep" || cfg.AlertVolume != 100 {
+		t.Errorf("enabled/muted/backend/volume = %v/%v/%q/%d", cfg.AlertsEnabled, cfg.AlertsMuted, cfg.AlertBackend, cfg.AlertVolume)
+	}
+	if cfg.AlertDisconnectTones != "500:100" || cfg.AlertOverTempTones != DefaultConfig().AlertOverTempTones {
+		t.Errorf("tones = %q/%q", cfg.AlertDisconnectTones, cfg.AlertOverTempTones)
+	}
+	if cfg.AlertOverTempC != 75 || cfg.AlertReverseGPIO != 22 || cfg.AlertRearCamera != "Back" {
+		t.Errorf("over_temp_c/reverse_gpio/rear_camera = %v/%d/%q", cfg.AlertOverTempC, cfg.AlertReverseGPIO, cfg.AlertRearCamera)
+	}
+}
+
+func TestLoad_StorageSpace(t *testing.T) {
+	if cfg := DefaultConfig(); cfg.LowSpaceMB != 500 || cfg.FreeSpace || cfg.ClipsQuotaMB != 0 {
+		t.Errorf("defaults = %d/%v/%d, want 500/false/0", cfg.LowSpaceMB, cfg.FreeSpace, cfg.ClipsQuotaMB)
+	}
+	cfg, err := Load(writeTempFile(t, "[storage]\nlow_space_mb = 1024\nfree_space = true\n"+
+		"snapshots_quota_mb = 200\nclips_quota_mb = 4096\ntrips_quota_mb = -5\nlogs_quota_mb = 50\ncheck_interval_sec = 1\n"))
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if cfg.LowSpaceMB != 1024 || !cfg.FreeSpace || cfg.StorageIntervalSec != 5 {
+		t.Errorf("low_space_mb/free_space/check_interval_sec = %d/%v/%v", cfg.LowSpaceMB, cfg.FreeSpace, cfg.StorageIntervalSec)
+	}
+	if cfg.SnapshotsQuotaMB != 200 || cfg.ClipsQuotaMB != 4096 || cfg.TripsQuotaMB != 0 || cfg.LogsQuotaMB != 50 {
+		t.Errorf("quotas = %d/%d/%d/%d", cfg.SnapshotsQuotaMB, cfg.ClipsQuotaMB, cfg.TripsQuotaMB, cfg.LogsQuotaMB)
+	}
+}
+
+func TestLoad_EnduranceSection(t *testing.T) {
+	if cfg := DefaultConfig(); cfg.EnduranceEnabled || cfg.EnduranceRAMDir != "/dev/shm/camera-dashboard" || cfg.StopRecordingMB != 0 {
+		t.Errorf("defaults = %v/%q/%d", cfg.EnduranceEnabled, cfg.EnduranceRAMDir, cfg.StopRecordingMB)
+	}
+	cfg, err := Load(writeTempFile(t, "[endurance]\nenabled = on\nram_dir =\nflush_interval_sec = 1\nflush_mb = 64\n"+
+		"log_flush_sec = 60\nstop_recording_mb = 256\nwear_device = /dev/mmcblk0\nstop_recording_wear_pct = 120\n"))
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if !cfg.EnduranceEnabled || cfg.EnduranceRAMDir != "/dev/shm/camera-dashboard" || cfg.EnduranceFlushSec != 10 || cfg.EnduranceFlushMB != 64 {
+		t.Errorf("enabled/ram_dir/flush = %v/%q/%v/%d", cfg.EnduranceEnabled, cfg.EnduranceRAMDir, cfg.EnduranceFlushSec, cfg.EnduranceFlushMB)
+	}
+	if cfg.EnduranceLogFlushSec != 60 || cfg.StopRecordingMB != 256 || cfg.WearDevice != "mmcblk0" || cfg.StopRecordingWearPct != 100 {
+		t.Errorf("log_flush/stop_mb/wear = %v/%d/%q/%d", cfg.EnduranceLogFlushSec, cfg.StopRecordingMB, cfg.WearDevice, cfg.StopRecordingWearPct)
+	}
+}
+
+func TestLoad_UploadSection(t *testing.T) {
+	path := writeTempFile(t, "[upload]\nenabled = true\ntarget = WebDAV\nurl = https://dav.example.com/car\nusername = me\n"+
+		"password = secret\nssid = Home\nrate_limit_kb = 500\ninterval_sec = 2\nonly_protected = no\n")
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if !cfg.UploadEnabled || cfg.UploadTarget != "webdav" || cfg.UploadURL != "https://dav.example.com/car" || cfg.UploadUsername != "me" {
+		t.Errorf("enabled/target/url/username = %v/%q/%q/%q", cfg.UploadEnabled, cfg.UploadTarget, cfg.UploadURL, cfg.UploadUsername)
+	}
+	if cfg.UploadSSID != "Home" || cfg.UploadRateKB != 500 || cfg.UploadIntervalSec != 10 || cfg.UploadOnlyProtected {
+		t.Errorf("ssid/rate/interval/only_protected = %q/%d/%v/%v", cfg.UploadSSID, cfg.UploadRateKB, cfg.UploadIntervalSec, cfg.UploadOnlyProtected)
+	}
+	if cfg.UploadStateFile != filepath.Join(filepath.Dir(path), "upload_state.json") {
+		t.Errorf("UploadStateFile = %q, want it next to the config", cfg.UploadStateFile)
+	}
+	if cfg, _ := Load(writeTempFile(t, "[upload]\ntarget = ftp\n")); cfg.UploadTarget != "s3" {
+		t.Errorf("unknown target should keep the default, got %q", cfg.UploadTarget)
+	}
+}
+
+func TestLoad_USBSection(t *testing.T) {
+	cfg, err := Load(writeTempFile(t, "[usb]\ncorrelation_window_sec = 120\ncorrelation_min_cameras = 1\nhub_power_cycle_cmd = uhubctl -l {hub} -a cycle\n"))
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if cfg.USBCorrelationSec != 60 || cfg.USBCorrelationMin != 2 {
+		t.Errorf("USBCorrelationSec/Min = %v/%d, want 60/2", cfg.USBCorrelationSec, cfg.USBCorrelationMin)
+	}
+	if cfg.USBHubPowerCycleCmd != "uhubctl -l {hub} -a cycle" || cfg.USBHubCycleCooldownSec != 300 {
+		t.Errorf("USBHubPowerCycleCmd/Cooldown = %q/%v", cfg.USBHubPowerCycleCmd, cfg.USBHubCycleCooldownSec)
+	}
+	if DefaultConfig().USBHubPowerCycleCmd != "" {
+		t.Error("hub power cycling should be off by default")
+	}
+}
+
+func TestLoad_ClipsSection(t *testing.T) {
+	cfg, err := Load(writeTempFile(t, "[clips]\nseconds = 300\ndir = /data/clips\n"))
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if cfg.ClipSeconds != 120 || cfg.ClipsDir != "/data/clips" {
+		t.Errorf("ClipSeconds/ClipsDir = %d/%q, want 120//data/clips", cfg.ClipSeconds, cfg.ClipsDir)
+	}
+	if DefaultConfig().ClipSeconds != 0 {
+		t.Error("clips should be off by default")
+	}
+}
+
+func TestLoad_BandwidthSection(t *testing.T) {
+	def := DefaultConfig()
+	if def.BandwidthEnabled || def.BandwidthBudgetMB != 35 || def.BandwidthMinFPS != 10 {
+		t.Errorf("defaults = %v, %v, %d; want off, 35 MB/s, 10 FPS", def.BandwidthEnabled, def.BandwidthBudgetMB, def.BandwidthMinFPS)
+	}
+	cfg, err := Load(writeTempFile(t, "[bandwidth]\nenabled = true\nbudget_mb_s = 100\nmin_fps = 2\npriority = video0, 046d:0825:ABC\n"))
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if !cfg.BandwidthEnabled || cfg.BandwidthBudgetMB != 60 || cfg.BandwidthMinFPS != 5 {
+		t.Errorf("got %v, %v, %d; want on, 60 (max), 5 (min)", cfg.BandwidthEnabled, cfg.BandwidthBudgetMB, cfg.BandwidthMinFPS)
+	}
+	if want := []string{"video0", "046d:0825:ABC"}; !reflect.DeepEqual(cfg.BandwidthPriority, want) {
+		t.Errorf("priority = %v, want %v", cfg.BandwidthPriority, want)
+	}
+}
+
+func TestLoad_StreamHealthSection(t *testing.T) {
+	def := DefaultConfig()
+	if !def.StreamFallback || def.StreamCorruptPercent != 10 || def.StreamHealthWindowSec != 30 ||
+		def.StreamFallbackResolution != (Resolution{640, 480}) {
+		t.Errorf("defaults = %v, %v, %d, %v", def.StreamFallback, def.StreamCorruptPercent, def.StreamHealthWindowSec, def.StreamFallbackResolution)
+	}
+	cfg, err := Load(writeTempFile(t, "[stream_health]\nfallback = off\ncorrupt_percent = 80\nwindow_sec = 5\nfallback_resolution = 320X240\n"))
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if cfg.StreamFallback || cfg.StreamCorruptPercent != 50 || cfg.StreamHealthWindowSec != 10 ||
+		cfg.StreamFallbackResolution != (Resolution{320, 240}) {
+		t.Errorf("got %v, %v, %d, %v; want off, 50 (max), 10 (min), 320x240",
+			cfg.StreamFallback, cfg.StreamCorruptPercent, cfg.StreamHealthWindowSec, cfg.StreamFallbackResolution)
+	}
+	cfg, err = Load(writeTempFile(t, "[stream_health]\nfallback_resolution = tiny\n"))
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if cfg.StreamFallbackResolution != (Resolution{640, 480}) {
+		t.Errorf("invalid fallback_resolution: got %v, want the default", cfg.StreamFallbackResolution)
+	}
+}
+
+func TestLoad_ReplaySection(t *testing.T) {
+	if DefaultConfig().ReplaySeconds != 0 {
+		t.Error("replay should be off by default")
+	}
+	for _, tc := range []struct {
+		value string
+		want  int
+	}{{"10", 10}, {"600", 60}, {"-5", 0}, {"long", 0}} {
+		cfg, err := Load(writeTempFile(t, "[replay]\nseconds = "+tc.value+"\n"))
+		if err != nil {
+			t.Fatalf("Load() error: %v", err)
+		}
+		if cfg.ReplaySeconds != tc.want {
+			t.Errorf("seconds = %s: got %d, want %d", tc.value, cfg.ReplaySeconds, tc.want)
+		}
+	}
+}
+
+func TestLoad_MirrorSection(t *testing.T) {
+	def := DefaultConfig()
+	if def.MirrorEnabled || !def.MirrorAutoDim || def.MirrorDimPercent != 40 || def.MirrorDarkLuma != 50 {
+		t.Errorf("mirror defaults = %v/%v/%d/%d", def.MirrorEnabled, def.MirrorAutoDim, def.MirrorDimPercent, def.MirrorDarkLuma)
+	}
+	cfg, err := Load(writeTempFile(t, "[mirror]\nenabled = yes\ncamera = /dev/video4\nleft = 046d:0825\nright = \nauto_dim = off\ndim_percent = 5\ndark_luma = 300\n"))
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if !cfg.MirrorEnabled || cfg.MirrorCamera != "/dev/video4" || cfg.MirrorLeft != "046d:0825" || cfg.MirrorRight != "" {
+		t.Errorf("mirror enabled/camera/left/right = %v/%q/%q/%q", cfg.MirrorEnabled, cfg.MirrorCamera, cfg.MirrorLeft, cfg.MirrorRight)
+	}
+	if cfg.MirrorAutoDim || cfg.MirrorDimPercent != 10 || cfg.MirrorDarkLuma != 255 {
+		t.Errorf("auto_dim/dim_percent/dark_luma = %v/%d/%d, want false/10/255", cfg.MirrorAutoDim, cfg.MirrorDimPercent, cfg.MirrorDarkLuma)
+	}
+}
+
+func TestLoad_TripReportSection(t *testing.T) {
+	def := DefaultConfig()
+	if def.TripReportDir != "./trips" || !def.TripReportMQTT {
+		t.Errorf("trip report defaults = %q/%v", def.TripReportDir, def.TripReportMQTT)
+	}
+	cfg, err := Load(writeTempFile(t, "[trip_report]\ndir = \nmqtt = off\n"))
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if cfg.TripReportDir != "" || cfg.TripReportMQTT {
+		t.Errorf("dir/mqtt = %q/%v, want empty/false", cfg.TripReportDir, cfg.TripReportMQTT)
+	}
+}
+
+func TestLoad_GSensorSection(t *testing.T) {
+	cfg, err := Load(writeTempFile(t, "[gsensor]\nenabled = true\ni2c_bus = /dev/i2c-3\naddress = 0x69\nthreshold_g = 40\nsample_hz = 100\n"))
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if !cfg.GSensorEnabled || cfg.GSensorBus != "/dev/i2c-3" || cfg.GSensorAddress != 0x69 {
+		t.Errorf("GSensor enabled/bus/address = %v/%q/%#x", cfg.GSensorEnabled, cfg.GSensorBus, cfg.GSensorAddress)
+	}
+	if cfg.GSensorThresholdG != 16 || cfg.GSensorSampleHz != 100 {
+		t.Errorf("GSensorThresholdG/SampleHz = %v/%d, want 16/100", cfg.GSensorThresholdG, cfg.GSensorSampleHz)
+	}
+}
+
+func TestLoad_GPSSection(t *testing.T) {
+	cfg, err := Load(writeTempFile(t, "[gps]\nsource = gpsd://localhost:2947\nunits = MPH\noverlay = false\n"))
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if cfg.GPSSource != "gpsd://localhost:2947" || cfg.GPSUnits != "mph" || cfg.GPSOverlay {
+		t.Errorf("GPS source/units/overlay = %q/%q/%v", cfg.GPSSource, cfg.GPSUnits, cfg.GPSOverlay)
+	}
+
+	cfg, _ = Load(writeTempFile(t, "[gps]\nunits = knots\n"))
+	if cfg.GPSUnits != "kmh" {
+		t.Errorf("unknown units should keep the default, got %q", cfg.GPSUnits)
+	}
+}
+
+func TestLoad_TimeSection(t *testing.T) {
+	cfg, err := Load(writeTempFile(t, "[gps]\n"))
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if !reflect.DeepEqual(cfg.TimeSources, []string{"gps", "ntp"}) || !cfg.TimeRenameRecordings {
+		t.Errorf("defaults: sources %v, rename %v", cfg.TimeSources, cfg.TimeRenameRecordings)
+	}
+
+	cfg, _ = Load(writeTempFile(t, "[time]\nsources = NTP\nrename_recordings = false\n"))
+	if !reflect.DeepEqual(cfg.TimeSources, []string{"ntp"}) || cfg.TimeRenameRecordings {
+		t.Errorf("sources %v, rename %v", cfg.TimeSources, cfg.TimeRenameRecordings)
+	}
+
+	cfg, _ = Load(writeTempFile(t, "[time]\nsources =\n"))
+	if len(cfg.TimeSources) != 0 {
+		t.Errorf("empty sources = %v, want none", cfg.TimeSources)
+	}
+
+	cfg, _ = Load(writeTempFile(t, "[time]\nsources = gps, rtc\n"))
+	if !reflect.DeepEqual(cfg.TimeSources, []string{"gps", "ntp"}) {
+		t.Errorf("unknown source should keep the default, got %v", cfg.TimeSources)
+	}
+}
+
+func TestLoad_ControlsSection(t *testing.T) {
+	cfg, err := Load(writeTempFile(t, "[controls]\nbrightness = 140\ncontrast =\nexposure = bright\nauto_white_balance = off\nreapply_on_connect = video2, 046d:0825:ABC\n"))
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	want := map[string]int{"brightness": 140, "auto_white_balance": 0}
+	if !reflect.DeepEqual(cfg.CameraControls, want) {
+		t.Errorf("CameraControls = %v, want %v", cfg.CameraControls, want)
+	}
+	if want := []string{"video2", "046d:0825:ABC"}; !reflect.DeepEqual(cfg.ControlsReapply, want) {
+		t.Errorf("ControlsReapply = %v, want %v", cfg.ControlsReapply, want)
+	}
+}
+
+func TestLoad_ThermalProfile(t *testing.T) {
+	model := filepath.Join(t.TempDir(), "model")
+	old := boardModelPath
+	boardModelPath = model
+	defer func() { boardModelPath = old }()
+
+	// No device tree: Pi 5 defaults
+	cfg, err := Load(writeTempFile(t, "[performance]\ndynamic_fps = true\n"))
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if cfg.ThermalProfile != "pi5" || cfg.Thermal != thermalProfiles["pi5"] {
+		t.Errorf("no board: profile %q %+v, want pi5 defaults", cfg.ThermalProfile, cfg.Thermal)
+	}
+
+	if err := os.WriteFile(model, []byte("Raspberry Pi 4 Model B Rev 1.4\x00"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, _ = Load(writeTempFile(t, "[performance]\ntemp_critical_c = 81\n"))
+	want := thermalProfiles["pi4"]
+	want.CriticalC = 81
+	if cfg.ThermalProfile != "pi4" || cfg.Thermal != want {
+		t.Errorf("Pi 4 board: profile %q %+v, want pi4 with critical 81", cfg.ThermalProfile, cfg.Thermal)
+	}
+
+	// An explicit profile wins over detection; unknown names are ignored
+	cfg, _ = Load(writeTempFile(t, "[performance]\nthermal_profile = PI5\n"))
+	if cfg.ThermalProfile != "pi5" {
+		t.Errorf("thermal_profile = PI5: got %q", cfg.ThermalProfile)
+	}
+	cfg, _ = Load(writeTempFile(t, "[performance]\nthermal_profile = pi9\n"))
+	if cfg.ThermalProfile != "pi4" {
+		t.Errorf("thermal_profile = pi9: got %q, want detected pi4", cfg.ThermalProfile)
+	}
+}
+
+func TestThermalLimits_OutOfOrderFallsBack(t *testing.T) {
+	cfg, err := Load(writeTempFile(t, "[performance]\nthermal_profile = pi5\ntemp_warm_c = 90\n"))
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if cfg.Thermal.WarmC != 90 {
+		t.Fatalf("WarmC = %.0f, want 90 (kept as configured)", cfg.Thermal.WarmC)
+	}
+	if got := cfg.ThermalLimits(); got != thermalProfiles["pi5"] {
+		t.Errorf("ThermalLimits() = %+v, want pi5 defaults", got)
+	}
+	_, warnings := cfg.Validate()
+	found := false
+	for _, w := range warnings {
+		if strings.HasPrefix(w, "[performance] temp_*_c") {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("warnings = %v, want one for out-of-order thresholds", warnings)
+	}
+}
+
+func TestLoad_FPSDamping(t *testing.T) {
+	cfg, err := Load(writeTempFile(t, "[performance]\nui_fps_step = 2\nfps_step_down = 5\nfps_min_dwell_sec = 45\nfps_fail_limit = 0\nfps_penalty_sec = 5\n"))
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if cfg.FPSStepDown != 5 || cfg.FPSMinDwellSec != 45 || cfg.FPSFailLimit != 0 || cfg.FPSPenaltySec != 30 {
+		t.Errorf("damping = (%d, %.0f, %d, %.0f), want (5, 45, 0, 30)",
+			cfg.FPSStepDown, cfg.FPSMinDwellSec, cfg.FPSFailLimit, cfg.FPSPenaltySec)
+	}
+
+	cfg, _ = Load(writeTempFile(t, "[performance]\nui_fps_step = 3\nfps_step_down = 2\n"))
+	_, warnings := cfg.Validate()
+	found := false
+	for _, w := range warnings {
+		if strings.HasPrefix(w, "[performance] fps_step_down") {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("warnings = %v, want one for fps_step_down <= ui_fps_step", warnings)
+	}
+}
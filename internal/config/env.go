@@ -0,0 +1,93 @@
+package config
+
+import (
+	"sort"
+	"strings"
+)
+
+// =============================================================================
+// Environment overrides
+// =============================================================================
+// CAMERA_DASHBOARD_<SECTION>_<KEY> sets [section] key over config.ini, so
+// a container or a fleet unit can change a setting without its own INI:
+//
+//   CAMERA_DASHBOARD_PROFILE_CAPTURE_FPS=15      [profile] capture_fps = 15
+//   CAMERA_DASHBOARD_NAMES_VIDEO0=Rear           [names] video0 = Rear
+//
+// Overrides go into the parsed INI before it is applied, so they are
+// parsed, clamped and reported like values in the file, and apply even
+// without a config file. Keys of free-form sections are lowercased, so
+// only keys a variable name can spell (video0, gpio22) can be set.
+// CONFIG, LAYOUT and LOG_FILE keep their own meaning.
+// =============================================================================
+
+const envPrefix = "CAMERA_DASHBOARD_"
+
+// envReserved are the CAMERA_DASHBOARD_ variables that aren't settings.
+var envReserved = map[string]bool{
+	envPrefix + "CONFIG":   true,
+	envPrefix + "LAYOUT":   true,
+	envPrefix + "LOG_FILE": true,
+}
+
+// envKeys maps the variable of each fixed key to the key.
+var envKeys = buildEnvKeys()
+
+func buildEnvKeys() map[string]iniKey {
+	keys := make(map[string]iniKey, len(iniKeys))
+	for _, k := range iniKeys {
+		keys[envName(k.section, k.name)] = k
+	}
+	return keys
+}
+
+// envName returns the environment variable that overrides [section] key.
+func envName(section, key string) string {
+	return envPrefix + strings.ToUpper(section+"_"+key)
+}
+
+// envSetting is a setting overridden by an environment variable.
+type envSetting struct {
+	name, section, key, value string
+}
+
+// envSettings returns the settings environ ("NAME=value" entries) overrides,
+// sorted by variable, and a warning for each CAMERA_DASHBOARD_ variable that
+// doesn't name one.
+func envSettings(environ []string) ([]envSetting, []Warning) {
+	var settings []envSetting
+	var warnings []Warning
+	for _, entry := range environ {
+		name, value, _ := strings.Cut(entry, "=")
+		if !strings.HasPrefix(name, envPrefix) || envReserved[name] {
+			continue
+		}
+		if k, ok := envKeys[name]; ok {
+			settings = append(settings, envSetting{name, k.section, k.name, strings.TrimSpace(value)})
+			continue
+		}
+		found := false
+		for section := range freeformKeys {
+			if key := strings.TrimPrefix(name, envName(section, "")); key != name && key != "" {
+				settings = append(settings, envSetting{name, section, strings.ToLower(key), strings.TrimSpace(value)})
+				found = true
+				break
+			}
+		}
+		if !found {
+			warnings = append(warnings, Warning{Message: name + " is not a setting, ignored"})
+		}
+	}
+	sort.Slice(settings, func(i, j int) bool { return settings[i].name < settings[j].name })
+	return settings, warnings
+}
+
+// applyEnv sets the overridden settings in ini.
+func applyEnv(ini iniData, settings []envSetting) {
+	for _, s := range settings {
+		if ini[s.section] == nil {
+			ini[s.section] = make(map[string]string)
+		}
+		ini[s.section][s.key] = s.value
+	}
+}
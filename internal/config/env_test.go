@@ -0,0 +1,92 @@
+package config
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// No two keys share a variable ([auto_night] source vs [auto] night_source).
+func TestEnvKeys_Unique(t *testing.T) {
+	if len(envKeys) != len(iniKeys) {
+		t.Errorf("%d variables for %d keys", len(envKeys), len(iniKeys))
+	}
+}
+
+func TestEnvSettings(t *testing.T) {
+	settings, warnings := envSettings([]string{
+		"PATH=/usr/bin",
+		"CAMERA_DASHBOARD_CONFIG=/etc/dash.ini",
+		"CAMERA_DASHBOARD_PROFILE_CAPTURE_FPS= 15 ",
+		"CAMERA_DASHBOARD_AUTO_NIGHT_SOURCE=schedule",
+		"CAMERA_DASHBOARD_NAMES_VIDEO2=Left = blind spot",
+		"CAMERA_DASHBOARD_NAMES_=x",
+		"CAMERA_DASHBOARD_PROFILE_CAPTURE_FBS=15",
+	})
+	want := []envSetting{
+		{"CAMERA_DASHBOARD_AUTO_NIGHT_SOURCE", "auto_night", "source", "schedule"},
+		{"CAMERA_DASHBOARD_NAMES_VIDEO2", "names", "video2", "Left = blind spot"},
+		{"CAMERA_DASHBOARD_PROFILE_CAPTURE_FPS", "profile", "capture_fps", "15"},
+	}
+	if !reflect.DeepEqual(settings, want) {
+		t.Errorf("settings = %+v, want %+v", settings, want)
+	}
+	if len(warnings) != 2 ||
+		warnings[0].String() != "CAMERA_DASHBOARD_NAMES_ is not a setting, ignored" ||
+		warnings[1].String() != "CAMERA_DASHBOARD_PROFILE_CAPTURE_FBS is not a setting, ignored" {
+		t.Errorf("warnings = %v", warnings)
+	}
+}
+
+func TestLoad_EnvOverrides(t *testing.T) {
+	t.Setenv("CAMERA_DASHBOARD_PROFILE_CAPTURE_FPS", "15")
+	t.Setenv("CAMERA_DASHBOARD_CAMERA_SLOT_COUNT", "many")
+	t.Setenv("CAMERA_DASHBOARD_NAMES_VIDEO0", "Rear")
+
+	cfg, err := Load(writeTempFile(t, "[profile]\ncapture_fps = 20\ncapture_width = 800\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.CaptureFPS != 15 || cfg.CaptureWidth != 800 {
+		t.Errorf("capture %dx? @ %d, want 800 wide @ 15 (env over file)", cfg.CaptureWidth, cfg.CaptureFPS)
+	}
+	if cfg.CameraNames["video0"] != "Rear" {
+		t.Errorf("names = %v", cfg.CameraNames)
+	}
+	if cfg.CameraSlotCount != DefaultConfig().CameraSlotCount {
+		t.Errorf("slot_count = %d, want the default", cfg.CameraSlotCount)
+	}
+	want := `[camera] slot_count: "many" is not a whole number, using 3 (set by CAMERA_DASHBOARD_CAMERA_SLOT_COUNT)`
+	if len(cfg.Warnings) != 1 || cfg.Warnings[0].String() != want {
+		t.Errorf("warnings = %v, want %s", cfg.Warnings, want)
+	}
+
+	// Without a config file too
+	cfg, err = Load(filepath.Join(t.TempDir(), "missing.ini"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.CaptureFPS != 15 {
+		t.Errorf("no file: capture_fps = %d, want 15", cfg.CaptureFPS)
+	}
+}
+
+func TestLoadFile_IgnoresEnv(t *testing.T) {
+	t.Setenv("CAMERA_DASHBOARD_PROFILE_CAPTURE_FPS", "15")
+	t.Setenv("CAMERA_DASHBOARD_CAMERA_SLOT_COUNT", "many")
+	t.Setenv("CAMERA_DASHBOARD_LOG_FILE", "/env/override.log")
+
+	cfg, err := LoadFile(writeTempFile(t, "[profile]\ncapture_fps = 20\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.CaptureFPS != 20 {
+		t.Errorf("capture_fps = %d, want the file's 20", cfg.CaptureFPS)
+	}
+	if cfg.LogFile == "/env/override.log" {
+		t.Error("log file taken from CAMERA_DASHBOARD_LOG_FILE")
+	}
+	if len(cfg.Warnings) != 0 {
+		t.Errorf("warnings = %v, want none (the bad value is the environment's)", cfg.Warnings)
+	}
+}
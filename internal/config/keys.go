@@ -0,0 +1,821 @@
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// =============================================================================
+// Keys, effective values, dump
+// =============================================================================
+// iniKeys lists every key applyINI reads, with the Config field that
+// holds its value, named like reloadableFields: "Field", "Struct.Field",
+// or "Map[key]" for one entry of a map field. Sections in freeformKeys
+// take any key as an entry of a map field ([names] video2 = Rear), on
+// top of their fixed keys. From the two:
+//
+//   - Inspect reports each key's effective value and the keys and
+//     sections the dashboard doesn't read (config validate)
+//   - Inspection.WriteINI fills the values into the commented config.ini
+//     template (config dump)
+//
+// A key added to applyINI needs an entry here; TestINIKeys_Fields checks
+// that each entry's field is the one its key sets.
+// =============================================================================
+
+// iniSections is the section order of config.ini ([soak] is undocumented).
+var iniSections = []string{
+	"logging", "performance", "cpu", "camera", "usb", "power", "input", "alerts",
+	"network_cameras", "profile", "display", "theme", "gestures", "controls",
+	"transform", "names", "dewarp", "profiles", "sync", "bandwidth", "stream_health", "layouts",
+	"health", "snapshot", "clips", "replay", "trip_report", "mirror", "gsensor",
+	"auto_night", "battery", "lock", "gps", "time", "storage", "endurance", "upload",
+	"mqtt", "watchdog", "soak",
+}
+
+// freeformKeys maps sections with free-form keys to their map field.
+var freeformKeys = map[string]string{
+	"power":           "CameraPower",
+	"input":           "InputBindings",
+	"network_cameras": "NetworkCameras",
+	"transform":       "CameraTransforms",
+	"names":           "CameraNames",
+	"dewarp":          "CameraDewarp",
+	"layouts":         "Layouts",
+}
+
+type iniKey struct {
+	section, name, field string
+}
+
+var iniKeys = buildINIKeys()
+
+func buildINIKeys() []iniKey {
+	keys := []iniKey{
+		{"logging", "level", "LogLevel"},
+		{"logging", "file", "LogFile"},
+		{"logging", "max_bytes", "LogMaxBytes"},
+		{"logging", "backup_count", "LogBackupCount"},
+		{"logging", "rotate_daily", "LogRotateDaily"},
+		{"logging", "compress", "LogCompress"},
+		{"logging", "stdout", "LogToStdout"},
+
+		{"performance", "dynamic_fps", "DynamicFPSEnabled"},
+		{"performance", "perf_check_interval_ms", "PerfCheckIntervalMS"},
+		{"performance", "min_dynamic_fps", "MinDynamicFPS"},
+		{"performance", "min_dynamic_ui_fps", "MinDynamicUIFPS"},
+		{"performance", "ui_fps_step", "UIFPSStep"},
+		{"performance", "fps_step_down", "FPSStepDown"},
+		{"performance", "fps_min_dwell_sec", "FPSMinDwellSec"},
+		{"performance", "fps_fail_limit", "FPSFailLimit"},
+		{"performance", "fps_penalty_sec", "FPSPenaltySec"},
+		{"performance", "cpu_load_threshold", "CPULoadThreshold"},
+		{"performance", "cpu_temp_threshold_c", "CPUTempThresholdC"},
+		{"performance", "stress_hold_count", "StressHoldCount"},
+		{"performance", "recover_hold_count", "RecoverHoldCount"},
+		{"performance", "stale_frame_timeout_sec", "StaleFrameTimeoutSec"},
+		{"performance", "restart_cooldown_sec", "RestartCooldownSec"},
+		{"performance", "max_restarts_per_window", "MaxRestartsPerWindow"},
+		{"performance", "restart_window_sec", "RestartWindowSec"},
+		{"performance", "thermal_profile", "ThermalProfile"},
+		{"performance", "temp_ideal_c", "Thermal.IdealC"},
+		{"performance", "temp_comfort_c", "Thermal.ComfortC"},
+		{"performance", "temp_warm_c", "Thermal.WarmC"},
+		{"performance", "temp_hot_c", "Thermal.HotC"},
+		{"performance", "temp_critical_c", "Thermal.CriticalC"},
+		{"performance", "resolution_tiers", "ResolutionTiers"},
+		{"performance", "resolution_step_down_sec", "ResolutionStepDownSec"},
+		{"performance", "resolution_step_up_sec", "ResolutionStepUpSec"},
+		{"performance", "resolution_hysteresis_c", "ResolutionHysteresisC"},
+
+		{"cpu", "gomaxprocs", "GoMaxProcs"},
+		{"cpu", "ffmpeg_cpus", "FFmpegCPUs"},
+		{"cpu", "capture_cpus", "CaptureCPUs"},
+		{"cpu", "ffmpeg_nice", "FFmpegNice"},
+		{"cpu", "write_io_class", "WriteIOClass"},
+		{"cpu", "write_io_level", "WriteIOLevel"},
+
+		{"camera", "rescan_interval_ms", "RescanIntervalMS"},
+		{"camera", "failed_camera_cooldown_sec", "FailedCameraCooldownS"},
+		{"camera", "reconnect_budget", "ReconnectBudget"},
+		{"camera", "slot_count", "CameraSlotCount"},
+		{"camera", "kill_device_holders", "KillDeviceHolders"},
+		{"camera", "caps_cache_file", "CapsCacheFile"},
+		{"camera", "disabled", "DisabledCameras"},
+		{"camera", "csi", "CSICameras"},
+		{"camera", "test_pattern", "TestPattern"},
+
+		{"usb", "correlation_window_sec", "USBCorrelationSec"},
+		{"usb", "correlation_min_cameras", "USBCorrelationMin"},
+		{"usb", "hub_power_cycle_cmd", "USBHubPowerCycleCmd"},
+		{"usb", "hub_power_cycle_cooldown_sec", "USBHubCycleCooldownSec"},
+
+		{"power", "chip", "PowerChip"},
+		{"power", "warmup_sec", "PowerWarmupSec"},
+		{"power", "cycle_off_sec", "PowerCycleOffSec"},
+		{"power", "off_on_exit", "PowerOffOnExit"},
+
+		{"input", "devices", "InputDevices"},
+		{"input", "chip", "InputChip"},
+		{"input", "debounce_ms", "InputDebounceMs"},
+
+		{"alerts", "enabled", "AlertsEnabled"},
+		{"alerts", "muted", "AlertsMuted"},
+		{"alerts", "backend", "AlertBackend"},
+		{"alerts", "device", "AlertDevice"},
+		{"alerts", "volume", "AlertVolume"},
+		{"alerts", "disconnect_tones", "AlertDisconnectTones"},
+		{"alerts", "reverse_stale_tones", "AlertReverseStaleTones"},
+		{"alerts", "over_temp_tones", "AlertOverTempTones"},
+		{"alerts", "over_temp_c", "AlertOverTempC"},
+		{"alerts", "reverse_gpio", "AlertReverseGPIO"},
+		{"alerts", "reverse_active_high", "AlertReverseActiveHigh"},
+		{"alerts", "rear_camera", "AlertRearCamera"},
+
+		{"profile", "capture_width", "CaptureWidth"},
+		{"profile", "capture_height", "CaptureHeight"},
+		{"profile", "capture_fps", "CaptureFPS"},
+		{"profile", "capture_format", "CaptureFormat"},
+		{"profile", "ui_fps", "UIFPS"},
+
+		{"display", "night_mode", "NightMode"},
+		{"display", "night_tint", "NightTint"},
+		{"display", "night_gain", "NightGain"},
+		{"display", "night_gamma", "NightGamma"},
+		{"display", "brightness", "BrightnessPercent"},
+		{"display", "driving_mode", "DrivingMode"},
+		{"display", "driving_speed", "DrivingSpeed"},
+		{"display", "pip_corners", "PIPCorners"},
+		{"display", "pip_size", "PIPSizePercent"},
+		{"display", "hidden_camera_fps", "HiddenCameraFPS"},
+		{"display", "freeze_indicator_ms", "FreezeIndicatorMS"},
+		{"display", "grid_layouts", "GridLayouts"},
+		{"display", "strip_layout", "StripLayout"},
+		{"display", "health_tile", "HealthTile"},
+		{"display", "toast_seconds", "ToastSeconds"},
+		{"display", "backend", "DisplayBackend"},
+		{"display", "framebuffer_device", "FramebufferDevice"},
+
+		{"theme", "name", "ThemeName"},
+	}
+	for _, key := range ThemeColorKeys {
+		keys = append(keys, iniKey{"theme", key, "ThemeColors[" + key + "]"})
+	}
+	keys = append(keys, []iniKey{
+		{"gestures", "tap", "GestureTap"},
+		{"gestures", "double_tap", "GestureDoubleTap"},
+		{"gestures", "long_press", "GestureLongPress"},
+		{"gestures", "two_finger_tap", "GestureTwoFingerTap"},
+
+		{"controls", "brightness", "CameraControls[brightness]"},
+		{"controls", "contrast", "CameraControls[contrast]"},
+		{"controls", "saturation", "CameraControls[saturation]"},
+		{"controls", "exposure", "CameraControls[exposure]"},
+		{"controls", "auto_white_balance", "CameraControls[auto_white_balance]"},
+		{"controls", "reapply_on_connect", "ControlsReapply"},
+
+		{"profiles", "active", "Profile"},
+
+		{"sync", "enabled", "SyncEnabled"},
+		{"sync", "max_delay_frames", "SyncMaxDelayFrames"},
+		{"sync", "latency_ms", "SyncLatencyMS"},
+
+		{"bandwidth", "enabled", "BandwidthEnabled"},
+		{"bandwidth", "budget_mb_s", "BandwidthBudgetMB"},
+		{"bandwidth", "min_fps", "BandwidthMinFPS"},
+		{"bandwidth", "priority", "BandwidthPriority"},
+
+		{"stream_health", "fallback", "StreamFallback"},
+		{"stream_health", "corrupt_percent", "StreamCorruptPercent"},
+		{"stream_health", "window_sec", "StreamHealthWindowSec"},
+		{"stream_health", "fallback_resolution", "StreamFallbackResolution"},
+
+		{"health", "log_interval_sec", "HealthLogIntervalSec"},
+		{"health", "first_frame_warn_sec", "FirstFrameWarnSec"},
+		{"health", "http_addr", "HealthHTTPAddr"},
+		{"health", "debug_addr", "DebugHTTPAddr"},
+		{"health", "allowed_cidrs", "HealthAllowedCIDRs"},
+
+		{"snapshot", "dir", "SnapshotDir"},
+
+		{"clips", "seconds", "ClipSeconds"},
+		{"clips", "dir", "ClipsDir"},
+
+		{"replay", "seconds", "ReplaySeconds"},
+
+		{"trip_report", "dir", "TripReportDir"},
+		{"trip_report", "mqtt", "TripReportMQTT"},
+
+		{"mirror", "enabled", "MirrorEnabled"},
+		{"mirror", "camera", "MirrorCamera"},
+		{"mirror", "left", "MirrorLeft"},
+		{"mirror", "right", "MirrorRight"},
+		{"mirror", "auto_dim", "MirrorAutoDim"},
+		{"mirror", "dim_percent", "MirrorDimPercent"},
+		{"mirror", "dark_luma", "MirrorDarkLuma"},
+
+		{"gsensor", "enabled", "GSensorEnabled"},
+		{"gsensor", "i2c_bus", "GSensorBus"},
+		{"gsensor", "address", "GSensorAddress"},
+		{"gsensor", "threshold_g", "GSensorThresholdG"},
+		{"gsensor", "sample_hz", "GSensorSampleHz"},
+		{"gsensor", "cooldown_sec", "GSensorCooldownSec"},
+
+		{"auto_night", "source", "AutoNightSource"},
+		{"auto_night", "dark_luma", "AutoNightDarkLuma"},
+		{"auto_night", "latitude", "AutoNightLatitude"},
+		{"auto_night", "longitude", "AutoNightLongitude"},
+		{"auto_night", "i2c_bus", "AutoNightSensorBus"},
+		{"auto_night", "address", "AutoNightSensorAddr"},
+		{"auto_night", "dark_lux", "AutoNightDarkLux"},
+		{"auto_night", "hold_sec", "AutoNightHoldSec"},
+
+		{"battery", "enabled", "BatteryEnabled"},
+		{"battery", "adc", "BatteryADC"},
+		{"battery", "i2c_bus", "BatteryBus"},
+		{"battery", "address", "BatteryAddress"},
+		{"battery", "channel", "BatteryChannel"},
+		{"battery", "divider", "BatteryDivider"},
+		{"battery", "shutdown_v", "BatteryShutdownV"},
+		{"battery", "shutdown_delay_sec", "BatteryShutdownDelaySec"},
+		{"battery", "shutdown_command", "BatteryShutdownCmd"},
+
+		{"lock", "pin", "LockPIN"},
+		{"lock", "unlock_sec", "LockUnlockSec"},
+		{"lock", "max_attempts", "LockMaxAttempts"},
+		{"lock", "lockout_sec", "LockLockoutSec"},
+
+		{"gps", "source", "GPSSource"},
+		{"gps", "units", "GPSUnits"},
+		{"gps", "overlay", "GPSOverlay"},
+
+		{"time", "sources", "TimeSources"},
+		{"time", "rename_recordings", "TimeRenameRecordings"},
+
+		{"storage", "read_only", "ReadOnlyRoot"},
+		{"storage", "state_dir", "StateDir"},
+		{"storage", "low_space_mb", "LowSpaceMB"},
+		{"storage", "free_space", "FreeSpace"},
+		{"storage", "snapshots_quota_mb", "SnapshotsQuotaMB"},
+		{"storage", "clips_quota_mb", "ClipsQuotaMB"},
+		{"storage", "trips_quota_mb", "TripsQuotaMB"},
+		{"storage", "logs_quota_mb", "LogsQuotaMB"},
+		{"storage", "check_interval_sec", "StorageIntervalSec"},
+
+		{"endurance", "enabled", "EnduranceEnabled"},
+		{"endurance", "ram_dir", "EnduranceRAMDir"},
+		{"endurance", "flush_interval_sec", "EnduranceFlushSec"},
+		{"endurance", "flush_mb", "EnduranceFlushMB"},
+		{"endurance", "log_flush_sec", "EnduranceLogFlushSec"},
+		{"endurance", "stop_recording_mb", "StopRecordingMB"},
+		{"endurance", "wear_device", "WearDevice"},
+		{"endurance", "stop_recording_wear_pct", "StopRecordingWearPct"},
+
+		{"upload", "enabled", "UploadEnabled"},
+		{"upload", "target", "UploadTarget"},
+		{"upload", "url", "UploadURL"},
+		{"upload", "access_key", "UploadAccessKey"},
+		{"upload", "secret_key", "UploadSecretKey"},
+		{"upload", "region", "UploadRegion"},
+		{"upload", "username", "UploadUsername"},
+		{"upload", "password", "UploadPassword"},
+		{"upload", "identity_file", "UploadIdentity"},
+		{"upload", "ssid", "UploadSSID"},
+		{"upload", "check_host", "UploadCheckHost"},
+		{"upload", "only_protected", "UploadOnlyProtected"},
+		{"upload", "rate_limit_kb", "UploadRateKB"},
+		{"upload", "interval_sec", "UploadIntervalSec"},
+		{"upload", "state_file", "UploadStateFile"},
+
+		{"mqtt", "enabled", "MQTTEnabled"},
+		{"mqtt", "broker", "MQTTBroker"},
+		{"mqtt", "client_id", "MQTTClientID"},
+		{"mqtt", "username", "MQTTUsername"},
+		{"mqtt", "password", "MQTTPassword"},
+		{"mqtt", "topic_prefix", "MQTTTopicPrefix"},
+		{"mqtt", "keepalive_sec", "MQTTKeepAliveSec"},
+
+		{"watchdog", "enabled", "WatchdogEnabled"},
+		{"watchdog", "check_interval_sec", "WatchdogCheckIntervalSec"},
+		{"watchdog", "ui_timeout_sec", "WatchdogUITimeoutSec"},
+		{"watchdog", "capture_timeout_sec", "WatchdogCaptureTimeoutSec"},
+		{"watchdog", "max_recoveries", "WatchdogMaxRecoveries"},
+
+		{"soak", "enabled", "SoakEnabled"},
+		{"soak", "kill_ffmpeg_per_hour", "SoakKillFFmpegPerHour"},
+		{"soak", "corrupt_jpeg_rate", "SoakCorruptJPEGRate"},
+		{"soak", "delay_frame_rate", "SoakDelayFrameRate"},
+		{"soak", "delay_frame_max_ms", "SoakDelayFrameMaxMS"},
+		{"soak", "device_removal_per_hour", "SoakDeviceRemovalPerHour"},
+		{"soak", "device_removal_sec", "SoakDeviceRemovalSec"},
+		{"soak", "seed", "SoakSeed"},
+	}...)
+	return keys
+}
+
+// findKey returns the fixed key section.name.
+func findKey(section, name string) (iniKey, bool) {
+	for _, k := range iniKeys {
+		if k.section == section && k.name == name {
+			return k, true
+		}
+	}
+	return iniKey{}, false
+}
+
+// knownSection reports whether section is one the dashboard reads.
+func knownSection(section string) bool {
+	for _, s := range iniSections {
+		if s == section {
+			return true
+		}
+	}
+	return isCameraSection(section) || isProfileSection(section)
+}
+
+// sectionKeys returns the fixed keys of section.
+func sectionKeys(section string) []string {
+	if isCameraSection(section) {
+		return cameraSectionKeys
+	}
+	if isProfileSection(section) {
+		return profileKeys()
+	}
+	var names []string
+	for _, k := range iniKeys {
+		if k.section == section {
+			names = append(names, k.name)
+		}
+	}
+	return names
+}
+
+// knownKey reports whether key is a fixed key of section.
+func knownKey(section, key string) bool {
+	if isProfileSection(section) {
+		_, _, ok := splitProfileKey(key)
+		return ok
+	}
+	for _, name := range sectionKeys(section) {
+		if name == key {
+			return true
+		}
+	}
+	return false
+}
+
+// Setting is a config.ini key with its effective value.
+type Setting struct {
+	Section string
+	Key     string
+	Value   string // Effective value, formatted as config.ini takes it
+	Raw     string // As written in the file
+	Set     bool   // The file or Env sets it; otherwise Value is the default
+	Env     string // The environment variable that overrides the file, if any
+}
+
+// Adjusted reports whether the file (or Env) sets the key but the dashboard uses
+// another value: clamped, resolved (thermal_profile = auto) or invalid.
+func (s Setting) Adjusted() bool {
+	return s.Set && !sameValue(s.Raw, s.Value)
+}
+
+// sameValue reports whether a value as written reads as the effective
+// one: equal text, the same number ("75.0" and "75", "55" and "0x37"),
+// or empty (the default).
+func sameValue(raw, value string) bool {
+	if raw == "" || strings.EqualFold(raw, value) {
+		return true
+	}
+	if a, err := strconv.ParseFloat(raw, 64); err == nil {
+		b, err := strconv.ParseFloat(value, 64)
+		if err == nil && a == b {
+			return true
+		}
+	}
+	a, errA := strconv.ParseInt(raw, 0, 64)
+	b, errB := strconv.ParseInt(value, 0, 64)
+	return errA == nil && errB == nil && a == b
+}
+
+// Inspection is a config file as the dashboard reads it. What it ignores
+// is in Config.Warnings.
+type Inspection struct {
+	Config   *Config
+	Settings []Setting // Every key in config.ini order; free-form entries after their section's fixed keys, [camera.<name>] then [profile.<name>] sections last
+}
+
+// Inspect loads the config at path (or the default/env path) and lists
+// the effective value of every setting. The values are the file's own:
+// the active profile's entries are listed under its section.
+func Inspect(path string) (*Inspection, error) {
+	cfg, err := Load(path)
+	if err != nil {
+		return nil, err
+	}
+	base := cfg
+	if cfg.ActiveProfile() != "" {
+		if base, err = load(path, false, true); err != nil {
+			return nil, err
+		}
+	}
+	ini := iniData{}
+	if _, statErr := os.Stat(cfg.Path); statErr == nil {
+		if ini, err = parseINI(cfg.Path); err != nil {
+			return nil, err
+		}
+	}
+	env, _ := envSettings(os.Environ())
+	applyEnv(ini, env)
+	envVars := make(map[string]string, len(env))
+	for _, e := range env {
+		envVars[e.section+"."+e.key] = e.name
+	}
+
+	in := &Inspection{Config: cfg}
+	v := reflect.ValueOf(base).Elem()
+	for _, section := range iniSections {
+		for _, k := range iniKeys {
+			if k.section != section {
+				continue
+			}
+			raw, set := ini.get(section, k.name)
+			in.Settings = append(in.Settings, Setting{
+				Section: section,
+				Key:     k.name,
+				Value:   formatField(v, k),
+				Raw:     raw,
+				Set:     set,
+				Env:     envVars[section+"."+k.name],
+			})
+		}
+		if field, ok := freeformKeys[section]; ok {
+			m := v.FieldByName(field)
+			for _, key := range sortedKeys(m) {
+				raw, set := ini.get(section, key)
+				if !set {
+					continue // From a [camera.<name>] section
+				}
+				in.Settings = append(in.Settings, Setting{
+					Section: section,
+					Key:     key,
+					Value:   m.MapIndex(reflect.ValueOf(key)).String(),
+					Raw:     raw,
+					Set:     true,
+					Env:     envVars[section+"."+key],
+				})
+			}
+		}
+	}
+	in.Settings = append(in.Settings, cameraSectionSettings(base, ini, envVars)...)
+	in.Settings = append(in.Settings, profileSettings(ini)...)
+
+	return in, nil
+}
+
+// checkValues reports the settings the file sets to a value Load couldn't
+// use as written: not a number or boolean, out of range, or not one of
+// the accepted words. Lists and resolutions drop bad entries silently.
+func checkValues(cfg *Config, ini iniData) []Warning {
+	v := reflect.ValueOf(cfg).Elem()
+	defaults := reflect.ValueOf(DefaultConfig()).Elem()
+	var warnings []Warning
+	for _, k := range iniKeys {
+		raw, set := ini.get(k.section, k.name)
+		if !set || detectedKeys[k.section+"."+k.name] {
+			continue
+		}
+		value := formatField(v, k)
+		if sameValue(raw, value) {
+			continue
+		}
+		var problem string
+		field, _ := fieldOf(v, k)
+		switch field.Kind() {
+		case reflect.Bool:
+			if _, err := strconv.ParseBool(raw); err == nil || isBoolWord(raw) {
+				continue
+			}
+			problem = "is not true or false"
+		case reflect.Int:
+			if _, err := strconv.Atoi(raw); err != nil {
+				problem = "is not a whole number"
+			} else {
+				problem = "is out of range"
+			}
+		case reflect.Float64:
+			if _, err := strconv.ParseFloat(raw, 64); err != nil {
+				problem = "is not a number"
+			} else {
+				problem = "is out of range"
+			}
+		case reflect.String:
+			if value != formatField(defaults, k) {
+				continue // Taken, tidied up ("/cam/" -> "cam")
+			}
+			problem = "is not valid"
+		default:
+			continue
+		}
+		use := "using " + value
+		if value == "" {
+			use = "ignored"
+		}
+		warnings = append(warnings, Warning{
+			Section: k.section,
+			Key:     k.name,
+			Message: fmt.Sprintf("%q %s, %s", raw, problem, use),
+		})
+	}
+	return warnings
+}
+
+// isBoolWord reports whether asBool reads value.
+func isBoolWord(value string) bool {
+	return asBool(value, true) == asBool(value, false)
+}
+
+// suggestKey returns " (did you mean capture_fps?)" for a key one or two
+// typos away from a key of the section, or "".
+func suggestKey(section, key string) string {
+	best, bestDist := "", 3
+	for _, name := range sectionKeys(section) {
+		if d := editDistance(key, name); d < bestDist {
+			best, bestDist = name, d
+		}
+	}
+	if best == "" {
+		return ""
+	}
+	return " (did you mean " + best + "?)"
+}
+
+// editDistance is the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = intMin(intMin(prev[j]+1, cur[j-1]+1), prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
+
+// fieldOf returns the Config field of k. For a map entry that isn't set
+// it returns the zero value of the map's element type and false.
+func fieldOf(cfg reflect.Value, k iniKey) (reflect.Value, bool) {
+	name, mapKey := k.field, ""
+	if i := strings.IndexByte(name, '['); i > 0 {
+		name, mapKey = name[:i], strings.TrimSuffix(name[i+1:], "]")
+	}
+	field := cfg
+	for _, part := range strings.Split(name, ".") {
+		field = field.FieldByName(part)
+	}
+	if mapKey != "" {
+		entry := field.MapIndex(reflect.ValueOf(mapKey))
+		if !entry.IsValid() {
+			return reflect.Zero(field.Type().Elem()), false
+		}
+		field = entry
+	}
+	return field, true
+}
+
+// formatField formats the Config field of k as config.ini takes it.
+func formatField(cfg reflect.Value, k iniKey) string {
+	field, ok := fieldOf(cfg, k)
+	if !ok {
+		return "" // Not set: the camera's or theme's own
+	}
+
+	switch x := field.Interface().(type) {
+	case string:
+		return x
+	case bool:
+		return strconv.FormatBool(x)
+	case int:
+		if k.name == "address" { // I2C addresses; 0 = the device default
+			if x == 0 {
+				return ""
+			}
+			return fmt.Sprintf("0x%02x", x)
+		}
+		return strconv.Itoa(x)
+	case float64:
+		return strconv.FormatFloat(x, 'f', -1, 64)
+	case []string:
+		return strings.Join(x, ", ")
+	case Resolution:
+		return formatResolution(x)
+	case []Resolution:
+		items := make([]string, len(x))
+		for i, r := range x {
+			items[i] = formatResolution(r)
+		}
+		return strings.Join(items, ", ")
+	case map[string]int:
+		var items []string
+		for _, key := range sortedKeys(field) {
+			items = append(items, fmt.Sprintf("%s:%d", key, x[key]))
+		}
+		return strings.Join(items, ", ")
+	}
+	panic("config: can't format " + k.field)
+}
+
+func formatResolution(r Resolution) string {
+	if r.Width == 0 || r.Height == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%dx%d", r.Width, r.Height)
+}
+
+func sortedKeys(m reflect.Value) []string {
+	keys := make([]string, 0, m.Len())
+	for _, key := range m.MapKeys() {
+		keys = append(keys, key.String())
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// detectedKeys are resolved at startup (thermal_profile = auto reads the
+// board). A dump keeps what the file says, so it still detects on
+// another board.
+var detectedKeys = map[string]bool{"performance.thermal_profile": true}
+
+// commentedKey matches a commented-out setting in the template: "# key = value".
+var commentedKey = regexp.MustCompile(`^[#;]\s*([a-z][a-z0-9_]*)\s*=`)
+
+// WriteINI writes template, a commented config.ini, with the inspected
+// values: the template's settings get their effective value, commented
+// examples are uncommented where the file sets them, and settings and
+// free-form entries the template doesn't have are added at the end of
+// their section. Unknown keys are left out.
+func (in *Inspection) WriteINI(w io.Writer, template []byte) error {
+	values := make(map[string]Setting, len(in.Settings))
+	for _, s := range in.Settings {
+		values[s.Section+"."+s.Key] = s
+	}
+	written := make(map[string]bool)
+	bw := bufio.NewWriter(w)
+
+	// format formats a setting as "key = value" ("key =" when empty)
+	format := func(s Setting) string {
+		value := s.Value
+		if s.Set && (detectedKeys[s.Section+"."+s.Key] || !s.Adjusted()) {
+			value = s.Raw // As the file has it: "auto", "75.0"
+		}
+		return strings.TrimRight(s.Key+" = "+value, " ")
+	}
+	// rest writes a section's settings the template didn't have
+	rest := func(section string) {
+		for _, s := range in.Settings {
+			id := s.Section + "." + s.Key
+			if s.Section != section || written[id] {
+				continue
+			}
+			written[id] = true
+			if _, fixed := findKey(s.Section, s.Key); fixed && !s.Set {
+				fmt.Fprintln(bw, "# "+format(s))
+			} else {
+				fmt.Fprintln(bw, format(s))
+			}
+		}
+	}
+
+	// The template's settings, so an example above one isn't uncommented too
+	active := make(map[string]bool)
+	section := ""
+	eachLine(template, func(line string) {
+		if name, ok := sectionHeader(line); ok {
+			section = name
+		} else if idx := strings.IndexByte(line, '='); idx > 0 && !isComment(line) {
+			active[section+"."+strings.TrimSpace(line[:idx])] = true
+		}
+	})
+
+	section = ""
+	seen := make(map[string]bool)
+	blank := 0 // Blank lines held back so a section's additions go before them
+	err := eachLine(template, func(line string) {
+		if line == "" {
+			blank++
+			return
+		}
+		name, header := sectionHeader(line)
+		if header {
+			if section != "" {
+				rest(section)
+			}
+			section = name
+			seen[section] = true
+		}
+		for ; blank > 0; blank-- {
+			bw.WriteString("\n")
+		}
+
+		switch {
+		case header:
+		case isComment(line):
+			// A commented-out example of a fixed key
+			if m := commentedKey.FindStringSubmatch(line); m != nil {
+				id := section + "." + m[1]
+				if _, fixed := findKey(section, m[1]); fixed && !active[id] && !written[id] {
+					written[id] = true
+					if s := values[id]; s.Set {
+						fmt.Fprintln(bw, format(s))
+						return
+					}
+				}
+			}
+		default:
+			if idx := strings.IndexByte(line, '='); idx > 0 {
+				id := section + "." + strings.TrimSpace(line[:idx])
+				if s, ok := values[id]; ok && !written[id] {
+					written[id] = true
+					// An unset key keeps the template's line when it reads
+					// the same ("75.0", "latitude =")
+					if s.Set || !detectedKeys[id] && !sameValue(strings.TrimSpace(line[idx+1:]), s.Value) {
+						fmt.Fprintln(bw, format(s))
+						return
+					}
+				}
+			}
+		}
+		bw.WriteString(line + "\n")
+	})
+	if err != nil {
+		return err
+	}
+	if section != "" {
+		rest(section)
+	}
+
+	// Sections the template doesn't have, when the file sets something
+	for _, name := range in.sections() {
+		if seen[name] {
+			continue
+		}
+		set := false
+		for _, s := range in.Settings {
+			set = set || (s.Section == name && s.Set)
+		}
+		if set {
+			fmt.Fprintf(bw, "\n[%s]\n", name)
+			rest(name)
+		}
+	}
+	return bw.Flush()
+}
+
+// sections returns iniSections followed by the [camera.<name>] and
+// [profile.<name>] sections of the settings.
+func (in *Inspection) sections() []string {
+	sections := append([]string(nil), iniSections...)
+	for _, s := range in.Settings {
+		if (isCameraSection(s.Section) || isProfileSection(s.Section)) && sections[len(sections)-1] != s.Section {
+			sections = append(sections, s.Section)
+		}
+	}
+	return sections
+}
+
+// eachLine calls fn with every line of data, trimmed.
+func eachLine(data []byte, fn func(line string)) error {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		fn(strings.TrimSpace(scanner.Text()))
+	}
+	return scanner.Err()
+}
+
+func isComment(line string) bool {
+	return strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";")
+}
+
+func sectionHeader(line string) (string, bool) {
+	if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+		return strings.TrimSpace(line[1 : len(line)-1]), true
+	}
+	return "", false
+}
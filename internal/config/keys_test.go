@@ -0,0 +1,187 @@
+package config
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+)
+
+// settingsOf returns the settings of the config at path as section.key -> value.
+func settingsOf(t *testing.T, path string) map[string]string {
+	t.Helper()
+	in, err := Inspect(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	values := make(map[string]string, len(in.Settings))
+	for _, s := range in.Settings {
+		values[s.Section+"."+s.Key] = s.Value
+	}
+	return values
+}
+
+// The shipped config.ini only has keys and values the dashboard reads.
+func TestINIKeys_Template(t *testing.T) {
+	cfg, err := Load("../../config.ini")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, w := range cfg.Warnings {
+		t.Errorf("config.ini: %s", w)
+	}
+}
+
+// Each key sets the field iniKeys names for it, and nothing else: one of
+// the probe values changes exactly that key's effective value.
+func TestINIKeys_Fields(t *testing.T) {
+	probes := []string{
+		"true", "false", "0", "1", "2", "3", "7", "55", "101", "1000", "0.75", "2.5", "-1",
+		"cam", "amber", "yuyv", "daylight", "framebuffer", "replace", "snapshot", "beep",
+		"ads1115", "sftp", "mph", "ntp", "schedule", "320x240", "top-left", "cam:5", "0x30",
+		"pi4", "pi5",
+	}
+	// Changing the profile also changes the temperatures it sets
+	sideEffects := map[string]bool{"performance.thermal_profile": true}
+
+	// One path for every probe: some defaults live next to the config file
+	path := writeTempFile(t, "")
+	defaults := settingsOf(t, path)
+	for _, k := range iniKeys {
+		id := k.section + "." + k.name
+		changed := false
+		for _, probe := range probes {
+			content := "[" + k.section + "]\n" + k.name + " = " + probe + "\n"
+			if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			got := settingsOf(t, path)
+			var diff []string
+			for key, v := range got {
+				if v != defaults[key] {
+					diff = append(diff, key)
+				}
+			}
+			if len(diff) == 0 {
+				continue
+			}
+			changed = true
+			if len(diff) > 1 && !sideEffects[id] {
+				t.Errorf("%s = %s changed %v", id, probe, diff)
+			} else if got[id] == defaults[id] {
+				t.Errorf("%s = %s changed %v, not %s", id, probe, diff, id)
+			}
+			break
+		}
+		if !changed {
+			t.Errorf("%s: no probe value changes it", id)
+		}
+	}
+}
+
+func TestInspect_Set(t *testing.T) {
+	path := writeTempFile(t, `[profile]
+capture_fps = 15
+capture_fbs = 10
+
+[names]
+video0 = Rear
+
+[camerra]
+slot_count = 2
+`)
+	in, err := Inspect(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := map[string]Setting{}
+	for _, s := range in.Settings {
+		found[s.Section+"."+s.Key] = s
+	}
+	if s := found["profile.capture_fps"]; !s.Set || s.Value != "15" {
+		t.Errorf("capture_fps = %+v, want set to 15", s)
+	}
+	if s := found["profile.capture_width"]; s.Set || s.Value != "640" {
+		t.Errorf("capture_width = %+v, want the default 640", s)
+	}
+	if s := found["names.video0"]; !s.Set || s.Value != "Rear" {
+		t.Errorf("names.video0 = %+v", s)
+	}
+	if _, ok := found["profile.capture_fbs"]; ok {
+		t.Error("unknown key capture_fbs listed as a setting")
+	}
+}
+
+// A dump loads back to the same settings, keeps the template's comments,
+// and leaves unknown keys out.
+func TestWriteINI_RoundTrip(t *testing.T) {
+	template, err := os.ReadFile("../../config.ini")
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := writeTempFile(t, `[performance]
+thermal_profile = auto
+temp_hot_c = 83
+
+[profile]
+capture_fps = 15
+capture_fbs = 10
+
+[display]
+grid_layouts = 4:1x4
+
+[names]
+video0 = Rear
+
+[input]
+gpio22 = night_mode
+
+[soak]
+seed = 7
+`)
+	in, err := Inspect(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := in.WriteINI(&buf, template); err != nil {
+		t.Fatal(err)
+	}
+	dump := buf.String()
+
+	for _, want := range []string{
+		"# Capture resolution and FPS\n",
+		"capture_fps = 15\n",
+		"thermal_profile = auto\n", // Not the detected board
+		"temp_hot_c = 83\n",
+		"# temp_ideal_c = 72\n",
+		"# grid_layouts = 4:1x4, 5:1x5\ngrid_layouts = 4:1x4\n",
+		"video0 = Rear\n",
+		"gpio22 = night_mode\n",
+		"[soak]\n",
+	} {
+		if !strings.Contains(dump, want) {
+			t.Errorf("dump lacks %q", want)
+		}
+	}
+	for _, unwanted := range []string{"capture_fbs", "\ngrid_layouts =\n"} {
+		if strings.Contains(dump, unwanted) {
+			t.Errorf("dump has %q", unwanted)
+		}
+	}
+
+	dumped := writeTempFile(t, dump)
+	want, got := settingsOf(t, path), settingsOf(t, dumped)
+	for key, v := range want {
+		if key == "camera.caps_cache_file" || key == "upload.state_file" {
+			continue // Next to the config file
+		}
+		if got[key] != v {
+			t.Errorf("%s = %q after the round trip, want %q", key, got[key], v)
+		}
+	}
+	cfg, _ := Load(dumped)
+	for _, w := range cfg.Warnings {
+		t.Errorf("dump: %s", w)
+	}
+}
@@ -1,6 +1,8 @@
 package config
 
 import (
+	"bufio"
+	"compress/gzip"
 	"fmt"
 	"io"
 	"log"
@@ -8,6 +10,7 @@ import (
 	"path/filepath"
 	"strings"
 	"sync"
+	"time"
 )
 
 // =============================================================================
@@ -17,13 +20,42 @@ import (
 // RotatingFileWriter implements io.Writer with log rotation by file size.
 // Matches Python's logging.handlers.RotatingFileHandler behaviour:
 // when the current file exceeds MaxBytes, it is rotated to .1, .2, etc.
+//
+// Optionally it also rotates when the local date changes (like
+// TimedRotatingFileHandler with when="midnight") and gzips backups to
+// .1.gz, .2.gz, etc. to save SD card space on long-running installs.
+//
+// With a flush interval, lines are collected in memory and written out
+// in one go every interval (and at once for ERROR/CRITICAL lines), so
+// an SD card sees a few large writes instead of one per line.
 type RotatingFileWriter struct {
 	mu          sync.Mutex
 	path        string
 	maxBytes    int
 	backupCount int
+	daily       bool
+	compress    bool
 	file        *os.File
 	currentSize int64
+	fileDay     string           // Local date (YYYY-MM-DD) the current file belongs to
+	now         func() time.Time // Overridable for tests
+	buf         *bufio.Writer    // nil = unbuffered
+	stop        chan struct{}    // Ends the flush loop; set once, never cleared
+	flushDone   chan struct{}    // Closed when the flush loop has returned
+	stopOnce    sync.Once
+	compressing chan struct{} // Closed when the last backup's gzip is done; nil = none started
+}
+
+// logBufferSize is the batch size for buffered log writes; a full
+// buffer is written out early.
+const logBufferSize = 64 * 1024
+
+// RotationOptions enables rotation behaviour beyond size limits.
+type RotationOptions struct {
+	Daily    bool // Also rotate when the local date changes
+	Compress bool // Gzip rotated backups
+
+	FlushInterval time.Duration // Batch writes and flush this often; 0 = write every line
 }
 
 // Log levels for coarse filtering when using Go's standard log package.
@@ -86,6 +118,12 @@ func (w *levelFilterWriter) Write(p []byte) (int, error) {
 // NewRotatingFileWriter creates a new rotating file writer.
 // maxBytes <= 0 disables rotation (single unbounded file).
 func NewRotatingFileWriter(path string, maxBytes, backupCount int) (*RotatingFileWriter, error) {
+	return NewRotatingFileWriterWithOptions(path, maxBytes, backupCount, RotationOptions{})
+}
+
+// NewRotatingFileWriterWithOptions creates a rotating file writer with
+// optional daily rotation and gzip compression of backups.
+func NewRotatingFileWriterWithOptions(path string, maxBytes, backupCount int, opts RotationOptions) (*RotatingFileWriter, error) {
 	dir := filepath.Dir(path)
 	if dir != "" {
 		if err := os.MkdirAll(dir, 0o755); err != nil {
@@ -97,14 +135,50 @@ func NewRotatingFileWriter(path string, maxBytes, backupCount int) (*RotatingFil
 		path:        path,
 		maxBytes:    maxBytes,
 		backupCount: backupCount,
+		daily:       opts.Daily,
+		compress:    opts.Compress,
+		now:         time.Now,
 	}
 
 	if err := rw.openFile(); err != nil {
 		return nil, err
 	}
+	if opts.FlushInterval > 0 {
+		rw.buf = bufio.NewWriterSize(rw.file, logBufferSize)
+		rw.stop = make(chan struct{})
+		rw.flushDone = make(chan struct{})
+		go rw.flushLoop(rw.stop, rw.flushDone, opts.FlushInterval)
+	}
 	return rw, nil
 }
 
+// flushLoop writes buffered lines out every interval until stop is
+// closed, then closes done.
+func (rw *RotatingFileWriter) flushLoop(stop <-chan struct{}, done chan<- struct{}, interval time.Duration) {
+	defer close(done)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			rw.Flush()
+		}
+	}
+}
+
+// Flush writes out buffered lines. It is a no-op without a flush
+// interval.
+func (rw *RotatingFileWriter) Flush() error {
+	rw.mu.Lock()
+	defer rw.mu.Unlock()
+	if rw.buf == nil {
+		return nil
+	}
+	return rw.buf.Flush()
+}
+
 func (rw *RotatingFileWriter) openFile() error {
 	f, err := os.OpenFile(rw.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
 	if err != nil {
@@ -117,28 +191,56 @@ func (rw *RotatingFileWriter) openFile() error {
 	}
 	rw.file = f
 	rw.currentSize = info.Size()
+	// An existing non-empty file belongs to the day it was last written,
+	// so a restart after midnight still rotates yesterday's log.
+	day := rw.now()
+	if info.Size() > 0 {
+		day = info.ModTime()
+	}
+	rw.fileDay = day.Format("2006-01-02")
 	return nil
 }
 
 // Write implements io.Writer. It writes p to the current log file,
-// rotating first if the write would exceed MaxBytes.
+// rotating first if the write would exceed MaxBytes or, with daily
+// rotation enabled, if the date has changed since the file was opened.
 func (rw *RotatingFileWriter) Write(p []byte) (int, error) {
 	rw.mu.Lock()
 	defer rw.mu.Unlock()
 
-	if rw.maxBytes > 0 && rw.currentSize+int64(len(p)) > int64(rw.maxBytes) {
+	switch {
+	case rw.maxBytes > 0 && rw.currentSize+int64(len(p)) > int64(rw.maxBytes):
+		rw.rotate()
+	case rw.daily && rw.currentSize > 0 && rw.now().Format("2006-01-02") != rw.fileDay:
 		rw.rotate()
 	}
 
-	n, err := rw.file.Write(p)
+	if rw.buf == nil {
+		n, err := rw.file.Write(p)
+		rw.currentSize += int64(n)
+		return n, err
+	}
+	n, err := rw.buf.Write(p)
 	rw.currentSize += int64(n)
+	if err == nil && detectMessageLevel(string(p)) >= LevelError {
+		err = rw.buf.Flush()
+	}
 	return n, err
 }
 
-// Close closes the underlying file.
+// Close writes out buffered lines and closes the underlying file. The
+// flush loop is stopped first, so no tick flushes into a closed file.
 func (rw *RotatingFileWriter) Close() error {
+	if rw.stop != nil {
+		rw.stopOnce.Do(func() { close(rw.stop) })
+		<-rw.flushDone
+	}
 	rw.mu.Lock()
 	defer rw.mu.Unlock()
+	if rw.buf != nil {
+		rw.buf.Flush()
+	}
+	rw.waitCompress()
 	if rw.file != nil {
 		return rw.file.Close()
 	}
@@ -146,18 +248,41 @@ func (rw *RotatingFileWriter) Close() error {
 }
 
 // rotate performs log rotation: file -> file.1, file.1 -> file.2, etc.
+// With compression, backups are file.1.gz, file.2.gz, etc. The new .1
+// is gzipped in the background, so writers only wait for the renames; a
+// rotation that comes before the previous gzip is done waits for it.
 func (rw *RotatingFileWriter) rotate() {
+	if rw.buf != nil {
+		rw.buf.Flush()
+	}
 	rw.file.Close()
+	rw.waitCompress()
 
-	// Shift existing backups
-	for i := rw.backupCount; i > 0; i-- {
-		src := rw.path
-		if i > 1 {
-			src = fmt.Sprintf("%s.%d", rw.path, i-1)
+	// Shift existing backups. Both plain and .gz names are shifted so
+	// toggling compression doesn't strand old backups.
+	for i := rw.backupCount; i > 1; i-- {
+		for _, ext := range []string{"", ".gz"} {
+			src := fmt.Sprintf("%s.%d%s", rw.path, i-1, ext)
+			dst := fmt.Sprintf("%s.%d%s", rw.path, i, ext)
+			os.Remove(dst)
+			os.Rename(src, dst)
 		}
-		dst := fmt.Sprintf("%s.%d", rw.path, i)
+	}
+	if rw.backupCount > 0 {
+		dst := rw.path + ".1"
 		os.Remove(dst)
-		os.Rename(src, dst)
+		os.Remove(dst + ".gz")
+		os.Rename(rw.path, dst)
+		if rw.compress {
+			done := make(chan struct{})
+			rw.compressing = done
+			go func() {
+				defer close(done)
+				if err := gzipFile(dst); err != nil {
+					fmt.Fprintf(os.Stderr, "config: failed to compress %s: %v\n", dst, err)
+				}
+			}()
+		}
 	}
 
 	// Open fresh file
@@ -166,6 +291,51 @@ func (rw *RotatingFileWriter) rotate() {
 		// This avoids silent data loss.
 		fmt.Fprintf(os.Stderr, "config: failed to reopen log file after rotation: %v\n", err)
 	}
+	if rw.buf != nil {
+		rw.buf.Reset(rw.file)
+	}
+}
+
+// waitCompress waits for the background gzip of the last backup, if
+// any. Called with rw.mu held.
+func (rw *RotatingFileWriter) waitCompress() {
+	if rw.compressing != nil {
+		<-rw.compressing
+		rw.compressing = nil
+	}
+}
+
+// gzipFile compresses path to path.gz and removes the original.
+// On failure the uncompressed file is left in place.
+func gzipFile(path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(path+".gz", os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	zw := gzip.NewWriter(dst)
+	if _, err := io.Copy(zw, src); err != nil {
+		zw.Close()
+		dst.Close()
+		os.Remove(path + ".gz")
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		dst.Close()
+		os.Remove(path + ".gz")
+		return err
+	}
+	if err := dst.Close(); err != nil {
+		os.Remove(path + ".gz")
+		return err
+	}
+	src.Close()
+	return os.Remove(path)
 }
 
 // =============================================================================
@@ -183,7 +353,14 @@ func ConfigureLogging(cfg *Config) (cleanup func(), err error) {
 
 	// Rotating file handler
 	if cfg.LogFile != "" {
-		rw, err := NewRotatingFileWriter(cfg.LogFile, cfg.LogMaxBytes, cfg.LogBackupCount)
+		opts := RotationOptions{
+			Daily:    cfg.LogRotateDaily,
+			Compress: cfg.LogCompress,
+		}
+		if cfg.EnduranceEnabled {
+			opts.FlushInterval = time.Duration(cfg.EnduranceLogFlushSec * float64(time.Second))
+		}
+		rw, err := NewRotatingFileWriterWithOptions(cfg.LogFile, cfg.LogMaxBytes, cfg.LogBackupCount, opts)
 		if err != nil {
 			log.Printf("[Config] WARNING: Failed to configure file logging: %v", err)
 		} else {
@@ -208,6 +385,9 @@ func ConfigureLogging(cfg *Config) (cleanup func(), err error) {
 	} else {
 		w = io.MultiWriter(writers...)
 	}
+	logOutputMu.Lock()
+	logOutput = w // Kept for SetLogLevel
+	logOutputMu.Unlock()
 	w = &levelFilterWriter{
 		minLevel: parseLogLevel(cfg.LogLevel),
 		next:     w,
@@ -217,10 +397,13 @@ func ConfigureLogging(cfg *Config) (cleanup func(), err error) {
 	log.SetOutput(w)
 	log.SetFlags(log.Ldate | log.Ltime) // "2006/01/02 15:04:05" matches Python's "%(asctime)s"
 
+	var once sync.Once // main's defer and the signal handler may both call it
 	cleanup = func() {
-		for _, c := range closers {
-			c.Close()
-		}
+		once.Do(func() {
+			for _, c := range closers {
+				c.Close()
+			}
+		})
 	}
 	return cleanup, nil
 }
@@ -2,10 +2,15 @@ package config
 
 import (
 	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"log"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestNewRotatingFileWriter_CreatesFile(t *testing.T) {
@@ -164,6 +169,31 @@ func TestConfigureLogging_WithFileAndStdout(t *testing.T) {
 	}
 }
 
+// The signal handler calls cleanup before os.Exit while main's defer may
+// call it too: the batched lines must reach the file either way.
+func TestConfigureLogging_CleanupFlushesOnce(t *testing.T) {
+	logPath := filepath.Join(t.TempDir(), "test.log")
+	cfg := DefaultConfig()
+	cfg.LogFile = logPath
+	cfg.LogToStdout = false
+	cfg.EnduranceEnabled = true
+	cfg.EnduranceLogFlushSec = 3600
+
+	cleanup, err := ConfigureLogging(cfg)
+	if err != nil {
+		t.Fatalf("ConfigureLogging() error: %v", err)
+	}
+	log.Println("[Main] shutting down")
+	cleanup()
+	cleanup()
+	log.SetOutput(os.Stderr)
+
+	data, _ := os.ReadFile(logPath)
+	if !strings.Contains(string(data), "[Main] shutting down") {
+		t.Errorf("log file = %q, want the buffered line flushed", data)
+	}
+}
+
 func TestConfigureLogging_StdoutOnly(t *testing.T) {
 	cfg := DefaultConfig()
 	cfg.LogFile = ""
@@ -232,3 +262,172 @@ func TestLevelFilterWriter(t *testing.T) {
 		t.Fatalf("expected warning message to pass filter, got %q", buf.String())
 	}
 }
+
+func TestRotatingFileWriter_RotatesDaily(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "test.log")
+
+	rw, err := NewRotatingFileWriterWithOptions(path, 0, 2, RotationOptions{Daily: true})
+	if err != nil {
+		t.Fatalf("NewRotatingFileWriterWithOptions() error: %v", err)
+	}
+	day := time.Date(2024, 6, 1, 23, 59, 0, 0, time.Local)
+	rw.now = func() time.Time { return day }
+	rw.fileDay = day.Format("2006-01-02")
+
+	rw.Write([]byte("before midnight\n"))
+	day = day.Add(2 * time.Minute)
+	rw.Write([]byte("after midnight\n"))
+	rw.Close()
+
+	old, _ := os.ReadFile(path + ".1")
+	if string(old) != "before midnight\n" {
+		t.Errorf("backup .1 = %q, want previous day's log", old)
+	}
+	cur, _ := os.ReadFile(path)
+	if string(cur) != "after midnight\n" {
+		t.Errorf("current log = %q, want today's log", cur)
+	}
+}
+
+func TestRotatingFileWriter_CompressesBackups(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "test.log")
+
+	rw, err := NewRotatingFileWriterWithOptions(path, 20, 2, RotationOptions{Compress: true})
+	if err != nil {
+		t.Fatalf("NewRotatingFileWriterWithOptions() error: %v", err)
+	}
+
+	first := strings.Repeat("a", 25) + "\n"
+	second := strings.Repeat("b", 25) + "\n"
+	rw.Write([]byte(first))
+	rw.Write([]byte(second))
+	rw.Write([]byte("c\n"))
+	rw.Close()
+
+	for _, name := range []string{path + ".1", path + ".2"} {
+		if _, err := os.Stat(name); !os.IsNotExist(err) {
+			t.Errorf("uncompressed backup %s should not exist", name)
+		}
+	}
+
+	readGz := func(name string) string {
+		f, err := os.Open(name)
+		if err != nil {
+			t.Fatalf("open %s: %v", name, err)
+		}
+		defer f.Close()
+		zr, err := gzip.NewReader(f)
+		if err != nil {
+			t.Fatalf("gzip reader %s: %v", name, err)
+		}
+		data, _ := io.ReadAll(zr)
+		return string(data)
+	}
+	if got := readGz(path + ".1.gz"); got != second {
+		t.Errorf(".1.gz = %q, want %q", got, second)
+	}
+	if got := readGz(path + ".2.gz"); got != first {
+		t.Errorf(".2.gz = %q, want %q", got, first)
+	}
+	if _, err := os.Stat(path + ".3.gz"); !os.IsNotExist(err) {
+		t.Error("backup .3.gz should not exist (backupCount=2)")
+	}
+}
+
+func TestRotatingFileWriter_BatchesWrites(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "test.log")
+
+	rw, err := NewRotatingFileWriterWithOptions(path, 1<<20, 3, RotationOptions{FlushInterval: time.Hour})
+	if err != nil {
+		t.Fatalf("NewRotatingFileWriterWithOptions() error: %v", err)
+	}
+	rw.Write([]byte("[UI] first\n"))
+	rw.Write([]byte("[UI] second\n"))
+	if data, _ := os.ReadFile(path); len(data) != 0 {
+		t.Errorf("lines written before a flush: %q", data)
+	}
+
+	// Errors go out at once, with everything before them
+	rw.Write([]byte("[UI] ERROR: broken\n"))
+	if data, _ := os.ReadFile(path); string(data) != "[UI] first\n[UI] second\n[UI] ERROR: broken\n" {
+		t.Errorf("after an error line: %q", data)
+	}
+
+	rw.Write([]byte("[UI] last\n"))
+	if err := rw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(path); !strings.HasSuffix(string(data), "[UI] last\n") {
+		t.Errorf("Close() should flush: %q", data)
+	}
+}
+
+func TestRotatingFileWriter_FlushLoopStopsOnClose(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "test.log")
+
+	rw, err := NewRotatingFileWriterWithOptions(path, 1<<20, 3, RotationOptions{FlushInterval: time.Millisecond})
+	if err != nil {
+		t.Fatalf("NewRotatingFileWriterWithOptions() error: %v", err)
+	}
+	rw.Write([]byte("[UI] ticked\n"))
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		if data, _ := os.ReadFile(path); string(data) == "[UI] ticked\n" {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("flush loop never wrote the buffered line")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	if err := rw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-rw.flushDone:
+	default:
+		t.Error("flush loop still running after Close()")
+	}
+	rw.Close() // A second Close must not panic or hang
+}
+
+func TestRotatingFileWriter_CompressesInBackground(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "test.log")
+
+	rw, err := NewRotatingFileWriterWithOptions(path, 20, 3, RotationOptions{Compress: true})
+	if err != nil {
+		t.Fatalf("NewRotatingFileWriterWithOptions() error: %v", err)
+	}
+	// Rotations in quick succession: each waits for the previous gzip,
+	// so no backup is shifted while it is being compressed
+	for _, c := range "abcd" {
+		rw.Write([]byte(strings.Repeat(string(c), 25) + "\n"))
+	}
+	rw.Close()
+
+	for i, want := range []string{"c", "b", "a"} {
+		name := fmt.Sprintf("%s.%d.gz", path, i+1)
+		f, err := os.Open(name)
+		if err != nil {
+			t.Fatalf("open %s: %v", name, err)
+		}
+		zr, err := gzip.NewReader(f)
+		if err != nil {
+			t.Fatalf("gzip reader %s: %v", name, err)
+		}
+		data, _ := io.ReadAll(zr)
+		f.Close()
+		if string(data) != strings.Repeat(want, 25)+"\n" {
+			t.Errorf("%s = %q", name, data)
+		}
+		if _, err := os.Stat(fmt.Sprintf("%s.%d", path, i+1)); !os.IsNotExist(err) {
+			t.Errorf("uncompressed backup .%d left behind", i+1)
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// =============================================================================
+// Profiles ([profile.<name>])
+// =============================================================================
+// A [profile.<name>] section is a named set of overrides for the rest of
+// the file, one section.key = value entry per setting (the section may
+// itself be dotted):
+//
+//   [profiles]
+//   active = towing
+//
+//   [profile.towing]
+//   camera.rear.fps = 30
+//   display.driving_mode = true
+//
+// Load copies the entries of the active profile over the file before
+// applying it, under the environment overrides, so they are parsed,
+// clamped and reported like the file's own values. Switching profiles
+// (settings panel, MQTT cmd/profile) saves [profiles] active and reloads
+// the file: reloadable settings change at once, the rest are logged as
+// needing a restart like any other edit.
+// =============================================================================
+
+const profileSectionPrefix = "profile."
+
+// isProfileSection reports whether section is a [profile.<name>] section.
+func isProfileSection(section string) bool {
+	return strings.HasPrefix(section, profileSectionPrefix) && len(section) > len(profileSectionPrefix)
+}
+
+// profileNames returns the names of the [profile.<name>] sections, sorted.
+func profileNames(ini iniData) []string {
+	var names []string
+	for _, section := range subsections(ini, profileSectionPrefix) {
+		names = append(names, strings.TrimPrefix(section, profileSectionPrefix))
+	}
+	return names
+}
+
+// splitProfileKey splits a profile entry into the section and key it
+// sets. Free-form sections take the rest as the key ("names.usb-1.2");
+// otherwise the key is after the last dot ("camera.rear.fps"). ok is
+// false for anything but a setting of another section.
+func splitProfileKey(entry string) (section, key string, ok bool) {
+	if i := strings.IndexByte(entry, '.'); i > 0 {
+		if _, freeform := freeformKeys[entry[:i]]; freeform {
+			return entry[:i], entry[i+1:], i+1 < len(entry)
+		}
+	}
+	i := strings.LastIndexByte(entry, '.')
+	if i <= 0 {
+		return "", "", false
+	}
+	section, key = entry[:i], entry[i+1:]
+	if section == "profiles" || isProfileSection(section) || !knownSection(section) || !knownKey(section, key) {
+		return "", "", false
+	}
+	return section, key, true
+}
+
+// profileKeys returns every fixed key as a profile entry takes it
+// ("display.brightness"), for suggestions.
+func profileKeys() []string {
+	var keys []string
+	for _, k := range iniKeys {
+		if k.section != "profiles" {
+			keys = append(keys, k.section+"."+k.name)
+		}
+	}
+	return keys
+}
+
+// applyProfile copies the entries of the profile [profiles] active names
+// into their sections of ini, and returns the settings it set
+// ("section.key"). An unknown profile sets nothing.
+func applyProfile(ini iniData) map[string]bool {
+	name, _ := ini.get("profiles", "active")
+	entries, ok := ini[profileSectionPrefix+strings.TrimSpace(name)]
+	if !ok {
+		return nil
+	}
+	set := make(map[string]bool, len(entries))
+	for entry, value := range entries {
+		section, key, ok := splitProfileKey(entry)
+		if !ok {
+			continue // Reported by readINI
+		}
+		if ini[section] == nil {
+			ini[section] = make(map[string]string)
+		}
+		ini[section][key] = value
+		set[section+"."+key] = true
+	}
+	return set
+}
+
+// checkProfiles reports an active profile without a section.
+func checkProfiles(cfg *Config) []Warning {
+	if cfg.Profile == "" || cfg.ActiveProfile() != "" {
+		return nil
+	}
+	return []Warning{{
+		Section: "profiles",
+		Key:     "active",
+		Message: fmt.Sprintf("%q has no [%s%s] section, ignored", cfg.Profile, profileSectionPrefix, cfg.Profile),
+	}}
+}
+
+// ActiveProfile returns the profile in use, or "" when [profiles] active
+// is empty or names no [profile.<name>] section.
+func (c *Config) ActiveProfile() string {
+	for _, name := range c.Profiles {
+		if name == c.Profile {
+			return name
+		}
+	}
+	return ""
+}
+
+// profileSettings lists the entries of the [profile.<name>] sections, for
+// Inspect.
+func profileSettings(ini iniData) []Setting {
+	var settings []Setting
+	for _, section := range subsections(ini, profileSectionPrefix) {
+		entries := ini[section]
+		keys := make([]string, 0, len(entries))
+		for key := range entries {
+			if _, _, ok := splitProfileKey(key); ok {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			raw := entries[key]
+			settings = append(settings, Setting{
+				Section: section,
+				Key:     key,
+				Value:   strings.TrimSpace(raw),
+				Raw:     raw,
+				Set:     true,
+			})
+		}
+	}
+	return settings
+}
+
+// sortedSet returns the members of set, sorted.
+func sortedSet(set map[string]bool) []string {
+	var items []string
+	for item := range set {
+		items = append(items, item)
+	}
+	sort.Strings(items)
+	return items
+}
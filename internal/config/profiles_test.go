@@ -0,0 +1,145 @@
+package config
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const profilesINI = `[display]
+brightness = 100
+
+[camera.rear]
+device = /dev/video0
+fps = 15
+
+[profiles]
+active = towing
+
+[profile.towing]
+camera.rear.fps = 30
+display.driving_mode = true
+names.usb-1.2 = Trailer
+display.brightnes = 60
+
+[profile.night]
+display.brightness = 15
+display.night_mode = true
+`
+
+func TestLoad_Profile(t *testing.T) {
+	cfg, err := Load(writeTempFile(t, profilesINI))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if cfg.ActiveProfile() != "towing" || !reflect.DeepEqual(cfg.Profiles, []string{"night", "towing"}) {
+		t.Errorf("profile = %q of %v, want towing of [night towing]", cfg.ActiveProfile(), cfg.Profiles)
+	}
+	if !cfg.DrivingMode || cfg.BrightnessPercent != 100 || cfg.NightMode {
+		t.Errorf("driving %v brightness %d night %v, want the towing profile over the file",
+			cfg.DrivingMode, cfg.BrightnessPercent, cfg.NightMode)
+	}
+	if len(cfg.CameraSections) != 1 || cfg.CameraSections[0].FPS != 30 {
+		t.Errorf("CameraSections = %+v, want rear at 30 fps", cfg.CameraSections)
+	}
+	if cfg.CameraNames["usb-1.2"] != "Trailer" {
+		t.Errorf("CameraNames = %v, want usb-1.2 = Trailer", cfg.CameraNames)
+	}
+	wantKeys := []string{"camera.rear.fps", "display.driving_mode", "names.usb-1.2"}
+	if !reflect.DeepEqual(cfg.ProfileKeys, wantKeys) {
+		t.Errorf("ProfileKeys = %v, want %v", cfg.ProfileKeys, wantKeys)
+	}
+
+	var warnings []string
+	for _, w := range cfg.Warnings {
+		warnings = append(warnings, w.String())
+	}
+	want := []string{"line 15: [profile.towing] display.brightnes: unknown key, ignored (did you mean display.brightness?)"}
+	if !reflect.DeepEqual(warnings, want) {
+		t.Errorf("warnings = %q, want %q", warnings, want)
+	}
+}
+
+func TestLoad_ProfileValues(t *testing.T) {
+	t.Setenv("CAMERA_DASHBOARD_DISPLAY_BRIGHTNESS", "80")
+	cfg, err := Load(writeTempFile(t, `[profiles]
+active = dim
+
+[profile.dim]
+display.brightness = 15
+display.pip_size = 90
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The environment wins over the profile
+	if cfg.BrightnessPercent != 80 || cfg.PIPSizePercent != 50 {
+		t.Errorf("brightness %d pip_size %d, want 80 / 50", cfg.BrightnessPercent, cfg.PIPSizePercent)
+	}
+	var warnings []string
+	for _, w := range cfg.Warnings {
+		warnings = append(warnings, w.String())
+	}
+	want := []string{`[display] pip_size: "90" is out of range, using 50 (set by [profile.dim])`}
+	if !reflect.DeepEqual(warnings, want) {
+		t.Errorf("warnings = %q, want %q", warnings, want)
+	}
+}
+
+func TestLoad_ProfileUnknown(t *testing.T) {
+	cfg, err := Load(writeTempFile(t, "[profiles]\nactive = towing\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.ActiveProfile() != "" {
+		t.Errorf("ActiveProfile = %q, want none", cfg.ActiveProfile())
+	}
+	if len(cfg.Warnings) != 1 || cfg.Warnings[0].String() != `[profiles] active: "towing" has no [profile.towing] section, ignored` {
+		t.Errorf("warnings = %v", cfg.Warnings)
+	}
+}
+
+func TestWatcher_ReloadProfile(t *testing.T) {
+	path := writeTempFile(t, profilesINI)
+	live, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := NewWatcher(path, live)
+
+	if err := SaveINI(path, []INIUpdate{{Section: "profiles", Key: "active", Value: "night"}}); err != nil {
+		t.Fatal(err)
+	}
+	needRestart := w.Reload()
+	if live.ActiveProfile() != "night" || live.DrivingMode || !live.NightMode || live.BrightnessPercent != 15 {
+		t.Errorf("profile %q driving %v night %v brightness %d, want the night profile applied",
+			live.ActiveProfile(), live.DrivingMode, live.NightMode, live.BrightnessPercent)
+	}
+	if !reflect.DeepEqual(needRestart, []string{"CameraSections"}) {
+		t.Errorf("needRestart = %v, want [CameraSections]", needRestart)
+	}
+}
+
+// A dump keeps the file's own values and the profiles as written.
+func TestWriteINI_Profiles(t *testing.T) {
+	in, err := Inspect(writeTempFile(t, profilesINI))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := in.WriteINI(&buf, []byte("[display]\nbrightness = 100\ndriving_mode = false\n")); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"driving_mode = false\n",
+		"[profiles]\nactive = towing\n",
+		"[profile.night]\ndisplay.brightness = 15\ndisplay.night_mode = true\n",
+		"[profile.towing]\ncamera.rear.fps = 30\ndisplay.driving_mode = true\nnames.usb-1.2 = Trailer\n",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("dump lacks %q:\n%s", want, buf.String())
+		}
+	}
+}
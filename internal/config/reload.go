@@ -0,0 +1,362 @@
+package config
+
+import (
+	"io"
+	"log"
+	"os"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// =============================================================================
+// Hot reload
+// =============================================================================
+// A Watcher re-reads config.ini when its mtime/size changes (polled; no
+// inotify dependency) or when Reload is called (SIGHUP in main). Only
+// the fields in reloadableFields are copied into the live Config that
+// every subsystem already holds a pointer to; anything else that
+// changed is logged as needing a restart.
+//
+// Readers load reloadable fields on every use (refresh loop, stale
+// detection, adaptive FPS), so a change takes effect on the reader's
+// next iteration. Reloads write the live Config holding the lock given
+// to SetLocker, and readers on other goroutines read under it. Maps and
+// slices are replaced, never modified in place, so a reader may keep
+// one it copied out under the lock.
+// Subsystems that must act on a change (log level, display defaults)
+// register with Subscribe.
+//
+// A soft restart (ui App.restart) reloads with ReloadForRestart, which
+// also copies restartFields: the settings the capture pipeline reads
+// when it starts, which the restart then starts again with.
+// =============================================================================
+
+// reloadableFields lists Config fields that may change at runtime.
+var reloadableFields = []string{
+	"LogLevel",
+	"UIFPS",
+	"MinDynamicUIFPS",
+	"UIFPSStep",
+	"FPSStepDown",
+	"FPSMinDwellSec",
+	"FPSFailLimit",
+	"FPSPenaltySec",
+	"CPULoadThreshold",
+	"CPUTempThresholdC",
+	"StressHoldCount",
+	"RecoverHoldCount",
+	"StaleFrameTimeoutSec",
+	"RestartCooldownSec",
+	"MaxRestartsPerWindow",
+	"RestartWindowSec",
+	"FailedCameraCooldownS",
+	"NightMode",
+	"BrightnessPercent",
+	"DrivingMode",
+	"DrivingSpeed",
+	"FreezeIndicatorMS",
+	"CameraTransforms",
+	"CameraDewarp",
+	"CameraNames",
+	"AlertsMuted",
+	"Profile",
+	"Profiles",
+	"ProfileKeys",
+}
+
+// restartFields lists the Config fields that, on top of
+// reloadableFields, take effect on a soft restart: read when the camera
+// manager and its workers, the adaptive FPS controller, the hotplug and
+// health loops, the MQTT client or the soak test start. The rest shape
+// the window and the services that keep running, and need the process
+// restarted.
+var restartFields = []string{
+	"DynamicFPSEnabled",
+	"PerfCheckIntervalMS",
+	"MinDynamicFPS",
+	"ResolutionTiers",
+	"ResolutionStepDownSec",
+	"ResolutionStepUpSec",
+	"ResolutionHysteresisC",
+	"ThermalProfile",
+	"Thermal",
+	"FFmpegCPUs",
+	"CaptureCPUs",
+	"FFmpegNice",
+	"RescanIntervalMS",
+	"ReconnectBudget",
+	"KillDeviceHolders",
+	"CapsCacheFile",
+	"DisabledCameras",
+	"CSICameras",
+	"TestPattern",
+	"CaptureWidth",
+	"CaptureHeight",
+	"CaptureFPS",
+	"CaptureFormat",
+	"HiddenCameraFPS",
+	"CameraControls",
+	"ControlsReapply",
+	"NetworkCameras",
+	"CameraSections",
+	"SyncEnabled",
+	"SyncMaxDelayFrames",
+	"SyncLatencyMS",
+	"BandwidthEnabled",
+	"BandwidthBudgetMB",
+	"BandwidthMinFPS",
+	"BandwidthPriority",
+	"StreamFallback",
+	"StreamCorruptPercent",
+	"StreamHealthWindowSec",
+	"StreamFallbackResolution",
+	"HealthLogIntervalSec",
+	"FirstFrameWarnSec",
+	"MQTTEnabled",
+	"MQTTBroker",
+	"MQTTClientID",
+	"MQTTUsername",
+	"MQTTPassword",
+	"MQTTTopicPrefix",
+	"MQTTKeepAliveSec",
+	"SoakEnabled",
+	"SoakKillFFmpegPerHour",
+	"SoakCorruptJPEGRate",
+	"SoakDelayFrameRate",
+	"SoakDelayFrameMaxMS",
+	"SoakDeviceRemovalPerHour",
+	"SoakDeviceRemovalSec",
+	"SoakSeed",
+}
+
+// flagFields are set from command-line flags rather than config.ini.
+var flagFields = map[string]bool{
+	"SimulateSources":  true,
+	"TraceFrames":      true,
+	"TraceCSV":         true,
+	"TraceSampleEvery": true,
+}
+
+// ApplyReloadable copies the runtime-changeable fields of src into dst.
+// It returns the names of reloadable fields that changed and of
+// non-reloadable fields that differ (which need a restart).
+func ApplyReloadable(dst, src *Config) (changed, needRestart []string) {
+	return applyFields(dst, src, reloadableFields)
+}
+
+// ApplyRestartable is ApplyReloadable for a soft restart: it also copies
+// restartFields. needRestart are the fields that need the process
+// restarted.
+func ApplyRestartable(dst, src *Config) (changed, needRestart []string) {
+	return applyFields(dst, src, reloadableFields, restartFields)
+}
+
+// NeedsSoftRestart reports whether any of fields, as returned by
+// Reload, is applied by a soft restart (ReloadForRestart).
+func NeedsSoftRestart(fields []string) bool {
+	for _, name := range fields {
+		for _, r := range restartFields {
+			if name == r {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// applyFields copies the fields of src named in lists into dst.
+func applyFields(dst, src *Config, lists ...[]string) (changed, needRestart []string) {
+	reloadable := make(map[string]bool)
+	for _, list := range lists {
+		for _, name := range list {
+			reloadable[name] = true
+		}
+	}
+
+	dv := reflect.ValueOf(dst).Elem()
+	sv := reflect.ValueOf(src).Elem()
+	for i := 0; i < dv.NumField(); i++ {
+		name := dv.Type().Field(i).Name
+		if name == "Warnings" {
+			continue // Not a setting; Reload logs them
+		}
+		if flagFields[name] {
+			continue // Set by command-line flags, which a reload keeps
+		}
+		if reflect.DeepEqual(dv.Field(i).Interface(), sv.Field(i).Interface()) {
+			continue
+		}
+		if reloadable[name] {
+			dv.Field(i).Set(sv.Field(i))
+			changed = append(changed, name)
+		} else {
+			needRestart = append(needRestart, name)
+		}
+	}
+	return changed, needRestart
+}
+
+// Watcher reloads a config file into a live Config.
+type Watcher struct {
+	path string
+	live *Config
+
+	mu      sync.Mutex  // Serialises reloads and protects subs/modTime/size
+	lock    sync.Locker // Held while writing live; nil = none
+	subs    []func(cfg *Config, changed []string)
+	modTime time.Time
+	size    int64
+
+	stopCh   chan struct{}
+	stopOnce sync.Once
+}
+
+// NewWatcher creates a watcher for path ("" = ConfigPath()) that applies
+// changes to live, the Config returned by Load at startup.
+func NewWatcher(path string, live *Config) *Watcher {
+	if path == "" {
+		path = ConfigPath()
+	}
+	w := &Watcher{path: path, live: live, stopCh: make(chan struct{})}
+	w.modTime, w.size = statFile(path)
+	return w
+}
+
+// SetLocker makes reloads hold l while they write the live Config. Call
+// it before Start.
+func (w *Watcher) SetLocker(l sync.Locker) {
+	w.mu.Lock()
+	w.lock = l
+	w.mu.Unlock()
+}
+
+// Subscribe registers fn to be called after a reload changed at least
+// one reloadable field. fn receives the live Config and the changed
+// field names, and runs on the reloading goroutine.
+func (w *Watcher) Subscribe(fn func(cfg *Config, changed []string)) {
+	w.mu.Lock()
+	w.subs = append(w.subs, fn)
+	w.mu.Unlock()
+}
+
+// Start polls the file's mtime/size every interval and reloads on change.
+func (w *Watcher) Start(interval time.Duration) {
+	if interval <= 0 {
+		interval = 2 * time.Second
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-w.stopCh:
+				return
+			case <-ticker.C:
+				modTime, size := statFile(w.path)
+				w.mu.Lock()
+				changed := !modTime.Equal(w.modTime) || size != w.size
+				w.mu.Unlock()
+				if changed {
+					log.Printf("[Config] %s changed on disk, reloading", w.path)
+					w.Reload()
+				}
+			}
+		}
+	}()
+}
+
+// Stop ends polling.
+func (w *Watcher) Stop() {
+	w.stopOnce.Do(func() { close(w.stopCh) })
+}
+
+// Reload re-reads the file and applies reloadable changes. A file that
+// is missing or fails to parse leaves the running config untouched. It
+// returns the changed fields that need a restart.
+func (w *Watcher) Reload() (needRestart []string) {
+	return w.reload(ApplyReloadable, "take effect after a restart")
+}
+
+// ReloadForRestart is Reload for a soft restart, which applies
+// restartFields too. It returns the changed fields that need the process
+// restarted.
+func (w *Watcher) ReloadForRestart() (needRestart []string) {
+	return w.reload(ApplyRestartable, "need the process restarted")
+}
+
+// reload re-reads the file and applies it with apply; restartNote says
+// what the fields apply doesn't copy need.
+func (w *Watcher) reload(apply func(dst, src *Config) (changed, needRestart []string), restartNote string) (needRestart []string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.modTime, w.size = statFile(w.path)
+	if w.size < 0 {
+		// Load would fall back to defaults; a missing file is more likely
+		// an editor mid-save than a request to reset everything
+		log.Printf("[Config] WARNING: %s not found, keeping current settings", w.path)
+		return nil
+	}
+	next, err := Load(w.path)
+	if err != nil {
+		log.Printf("[Config] WARNING: reload failed, keeping current settings: %v", err)
+		return nil
+	}
+	next.PrepareStorage() // Resolve paths the same way as at startup
+	for _, warning := range next.Warnings {
+		log.Printf("[Config] WARNING: %s: %s", w.path, warning)
+	}
+	_, warnings := next.Validate()
+	for _, warning := range warnings {
+		log.Printf("[Config] WARNING: %s", warning)
+	}
+
+	if w.lock != nil {
+		w.lock.Lock()
+	}
+	changed, needRestart := apply(w.live, next)
+	if w.lock != nil {
+		w.lock.Unlock()
+	}
+	if len(needRestart) > 0 {
+		log.Printf("[Config] WARNING: changes to %v %s", needRestart, restartNote)
+	}
+	if len(changed) == 0 {
+		log.Println("[Config] Reload: no runtime-changeable settings changed")
+		return needRestart
+	}
+	log.Printf("[Config] Reload applied: %v", changed)
+	for _, fn := range w.subs {
+		fn(w.live, changed)
+	}
+	return needRestart
+}
+
+func statFile(path string) (time.Time, int64) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}, -1
+	}
+	return info.ModTime(), info.Size()
+}
+
+// =============================================================================
+// Runtime log level
+// =============================================================================
+
+var (
+	logOutputMu sync.Mutex
+	logOutput   io.Writer // Unfiltered destination set by ConfigureLogging
+)
+
+// SetLogLevel changes the minimum level of the standard logger
+// configured by ConfigureLogging.
+func SetLogLevel(level string) {
+	logOutputMu.Lock()
+	defer logOutputMu.Unlock()
+	if logOutput == nil {
+		return
+	}
+	log.SetOutput(&levelFilterWriter{minLevel: parseLogLevel(level), next: logOutput})
+}
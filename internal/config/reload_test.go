@@ -0,0 +1,203 @@
+package config
+
+import (
+	"bytes"
+	"log"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestApplyReloadable(t *testing.T) {
+	live := DefaultConfig()
+	next := DefaultConfig()
+	next.UIFPS = 10
+	next.NightMode = true
+	next.CaptureWidth = 1280
+
+	changed, needRestart := ApplyReloadable(live, next)
+	if !reflect.DeepEqual(changed, []string{"UIFPS", "NightMode"}) {
+		t.Errorf("changed = %v, want [UIFPS NightMode]", changed)
+	}
+	if !reflect.DeepEqual(needRestart, []string{"CaptureWidth"}) {
+		t.Errorf("needRestart = %v, want [CaptureWidth]", needRestart)
+	}
+	if live.UIFPS != 10 || !live.NightMode {
+		t.Error("reloadable fields were not applied")
+	}
+	if live.CaptureWidth != 640 {
+		t.Errorf("CaptureWidth = %d, want 640 (needs restart, not applied)", live.CaptureWidth)
+	}
+}
+
+func TestApplyRestartable(t *testing.T) {
+	live := DefaultConfig()
+	next := DefaultConfig()
+	next.UIFPS = 10
+	next.CaptureWidth = 1280
+	next.CameraSlotCount = 2
+
+	changed, needRestart := ApplyRestartable(live, next)
+	if !reflect.DeepEqual(changed, []string{"CaptureWidth", "UIFPS"}) {
+		t.Errorf("changed = %v, want [CaptureWidth UIFPS]", changed)
+	}
+	if !reflect.DeepEqual(needRestart, []string{"CameraSlotCount"}) {
+		t.Errorf("needRestart = %v, want [CameraSlotCount]", needRestart)
+	}
+	if live.CaptureWidth != 1280 || live.CameraSlotCount == 2 {
+		t.Errorf("CaptureWidth %d slots %d, want 1280 and the slot count unchanged", live.CaptureWidth, live.CameraSlotCount)
+	}
+}
+
+func TestNeedsSoftRestart(t *testing.T) {
+	if !NeedsSoftRestart([]string{"CameraSlotCount", "CameraSections"}) {
+		t.Error("CameraSections needs no soft restart, want one")
+	}
+	if NeedsSoftRestart([]string{"CameraSlotCount"}) || NeedsSoftRestart(nil) {
+		t.Error("a soft restart for fields it doesn't apply")
+	}
+}
+
+// Every listed field must exist, or a rename would silently stop it
+// from being applied.
+func TestApplyFields_Names(t *testing.T) {
+	typ := reflect.TypeOf(Config{})
+	names := append(append([]string{}, reloadableFields...), restartFields...)
+	for name := range flagFields {
+		names = append(names, name)
+	}
+	for _, name := range names {
+		if _, ok := typ.FieldByName(name); !ok {
+			t.Errorf("%s is not a Config field", name)
+		}
+	}
+}
+
+func TestWatcher_Reload(t *testing.T) {
+	path := writeTempFile(t, "[profile]\nui_fps = 20\n")
+	live, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w := NewWatcher(path, live)
+	var got []string
+	w.Subscribe(func(cfg *Config, changed []string) {
+		if cfg != live {
+			t.Error("subscriber should receive the live config")
+		}
+		got = changed
+	})
+
+	if err := os.WriteFile(path, []byte("[profile]\nui_fps = 12\n[display]\nbrightness = 60\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	w.Reload()
+	if !reflect.DeepEqual(got, []string{"UIFPS", "BrightnessPercent"}) {
+		t.Errorf("changed = %v, want [UIFPS BrightnessPercent]", got)
+	}
+	if live.UIFPS != 12 || live.BrightnessPercent != 60 {
+		t.Errorf("live = ui_fps %d brightness %d, want 12 / 60", live.UIFPS, live.BrightnessPercent)
+	}
+
+	// A missing file must not reset settings to defaults
+	got = nil
+	os.Remove(path)
+	w.Reload()
+	if got != nil || live.UIFPS != 12 {
+		t.Errorf("reload of missing file changed settings (changed=%v, ui_fps=%d)", got, live.UIFPS)
+	}
+}
+
+func TestWatcher_SetLocker(t *testing.T) {
+	path := writeTempFile(t, "[profile]\nui_fps = 20\n")
+	live, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var mu sync.RWMutex
+	w := NewWatcher(path, live)
+	w.SetLocker(&mu)
+	w.Subscribe(func(cfg *Config, changed []string) {
+		// Subscribers run after the write, without the lock
+		if !mu.TryLock() {
+			t.Error("lock still held while subscribers run")
+			return
+		}
+		mu.Unlock()
+	})
+
+	if err := os.WriteFile(path, []byte("[profile]\nui_fps = 12\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	mu.RLock()
+	done := make(chan struct{})
+	go func() {
+		w.Reload()
+		close(done)
+	}()
+	select {
+	case <-done:
+		t.Fatal("reload wrote the config while a reader held the lock")
+	case <-time.After(50 * time.Millisecond):
+	}
+	if live.UIFPS != 20 {
+		t.Errorf("UIFPS = %d while the reader holds the lock, want 20", live.UIFPS)
+	}
+	mu.RUnlock()
+	<-done
+	if live.UIFPS != 12 {
+		t.Errorf("UIFPS = %d after the reload, want 12", live.UIFPS)
+	}
+}
+
+func TestWatcher_PollsFileChanges(t *testing.T) {
+	path := writeTempFile(t, "[performance]\nstale_frame_timeout_sec = 1.5\n")
+	live, _ := Load(path)
+
+	w := NewWatcher(path, live)
+	reloaded := make(chan []string, 1)
+	w.Subscribe(func(_ *Config, changed []string) { reloaded <- changed })
+	w.Start(10 * time.Millisecond)
+	defer w.Stop()
+
+	if err := os.WriteFile(path, []byte("[performance]\nstale_frame_timeout_sec = 4.25\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case changed := <-reloaded:
+		if !reflect.DeepEqual(changed, []string{"StaleFrameTimeoutSec"}) {
+			t.Errorf("changed = %v, want [StaleFrameTimeoutSec]", changed)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("file change was not picked up")
+	}
+}
+
+func TestSetLogLevel(t *testing.T) {
+	var buf bytes.Buffer
+	logOutputMu.Lock()
+	logOutput = &buf
+	logOutputMu.Unlock()
+	defer func() {
+		logOutputMu.Lock()
+		logOutput = nil
+		logOutputMu.Unlock()
+		log.SetOutput(os.Stderr)
+	}()
+
+	SetLogLevel("WARNING")
+	log.Print("[UI] info message")
+	if buf.Len() != 0 {
+		t.Fatalf("INFO passed a WARNING filter: %q", buf.String())
+	}
+	SetLogLevel("DEBUG")
+	log.Print("[UI] info message")
+	if !strings.Contains(buf.String(), "info message") {
+		t.Errorf("INFO filtered at DEBUG level: %q", buf.String())
+	}
+}
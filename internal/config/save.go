@@ -0,0 +1,100 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// =============================================================================
+// INI writer
+// =============================================================================
+// Used by the settings panel to persist changes. Edits are applied to
+// the existing file line by line so comments, ordering and untouched
+// keys survive; keys that aren't present yet are appended to their
+// section (or a new section at the end). Commented-out keys are left
+// alone.
+// =============================================================================
+
+// INIUpdate sets one key in one section.
+type INIUpdate struct {
+	Section string
+	Key     string
+	Value   string
+}
+
+// SaveINI applies updates to the INI file at path, creating it if needed.
+// The file is replaced atomically (temp file + rename).
+func SaveINI(path string, updates []INIUpdate) error {
+	if path == "" {
+		path = ConfigPath()
+	}
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("config: read %s: %w", path, err)
+	}
+
+	var lines []string
+	if len(data) > 0 {
+		lines = strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	}
+
+	for _, u := range updates {
+		lines = setINIValue(lines, u)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("config: create dir for %s: %w", path, err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(strings.Join(lines, "\n")+"\n"), 0o644); err != nil {
+		return fmt.Errorf("config: write %s: %w", tmp, err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("config: replace %s: %w", path, err)
+	}
+	return nil
+}
+
+// setINIValue returns lines with u applied.
+func setINIValue(lines []string, u INIUpdate) []string {
+	entry := u.Key + " = " + u.Value
+	section := ""
+	sectionEnd := -1 // Index after the last non-blank line of u.Section
+
+	for i, raw := range lines {
+		line := strings.TrimSpace(raw)
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			section = strings.TrimSpace(line[1 : len(line)-1])
+			if section == u.Section {
+				sectionEnd = i + 1
+			}
+			continue
+		}
+		if section != u.Section {
+			continue
+		}
+		if line != "" {
+			sectionEnd = i + 1
+		}
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+		if idx := strings.IndexByte(line, '='); idx > 0 && strings.TrimSpace(line[:idx]) == u.Key {
+			lines[i] = entry
+			return lines
+		}
+	}
+
+	if sectionEnd < 0 {
+		if len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) != "" {
+			lines = append(lines, "")
+		}
+		return append(lines, "["+u.Section+"]", entry)
+	}
+	lines = append(lines, "")
+	copy(lines[sectionEnd+1:], lines[sectionEnd:])
+	lines[sectionEnd] = entry
+	return lines
+}
@@ -0,0 +1,78 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestSaveINI_PreservesCommentsAndOrder(t *testing.T) {
+	path := writeTempFile(t, `[profile]
+# Capture resolution
+capture_width = 640
+capture_height = 480
+# ui_fps = 30
+
+[camera]
+slot_count = 3
+`)
+	err := SaveINI(path, []INIUpdate{
+		{Section: "profile", Key: "capture_width", Value: "320"},
+		{Section: "profile", Key: "ui_fps", Value: "15"},
+		{Section: "camera", Key: "disabled", Value: "/dev/video2"},
+		{Section: "display", Key: "brightness", Value: "60"},
+	})
+	if err != nil {
+		t.Fatalf("SaveINI: %v", err)
+	}
+
+	data, _ := os.ReadFile(path)
+	want := `[profile]
+# Capture resolution
+capture_width = 320
+capture_height = 480
+# ui_fps = 30
+ui_fps = 15
+
+[camera]
+slot_count = 3
+disabled = /dev/video2
+
+[display]
+brightness = 60
+`
+	if string(data) != want {
+		t.Errorf("saved file:\n%s\nwant:\n%s", data, want)
+	}
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.CaptureWidth != 320 || cfg.UIFPS != 15 || cfg.BrightnessPercent != 60 ||
+		!reflect.DeepEqual(cfg.DisabledCameras, []string{"/dev/video2"}) {
+		t.Errorf("reloaded config does not match saved values: %+v", cfg)
+	}
+}
+
+func TestSaveINI_CreatesFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sub", "config.ini")
+	if err := SaveINI(path, []INIUpdate{{Section: "profile", Key: "ui_fps", Value: "10"}}); err != nil {
+		t.Fatalf("SaveINI: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	if string(data) != "[profile]\nui_fps = 10\n" {
+		t.Errorf("new file = %q", data)
+	}
+}
+
+func TestSplitList(t *testing.T) {
+	got := splitList(" /dev/video2, ,046d:0825:ABC ,")
+	if !reflect.DeepEqual(got, []string{"/dev/video2", "046d:0825:ABC"}) {
+		t.Errorf("splitList = %q", got)
+	}
+	if splitList("") != nil {
+		t.Error("empty list should be nil")
+	}
+}
@@ -0,0 +1,135 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// =============================================================================
+// Read-only root
+// =============================================================================
+// With [storage] read_only = true (overlayfs / read-only root images),
+// PrepareStorage checks every location the dashboard writes to and
+// moves the ones that aren't writable under state_dir (a tmpfs or a
+// writable data partition). What can't be placed anywhere is turned
+// off rather than failing later:
+//
+//   log file          -> stdout only
+//   capability cache  -> re-probe cameras every start
+//   snapshots         -> MQTT snapshot command reports an error
+//   clips             -> Save clip reports an error
+//   trip reports      -> logged and published over MQTT only
+//   upload journal    -> kept in memory, uploads start over every start
+//   config.ini        -> settings panel applies changes without saving
+//
+// Without read_only nothing is probed or moved.
+// =============================================================================
+
+// PrepareStorage resolves writable paths for read-only mode and returns
+// a description of every relocation or fallback. It is a no-op unless
+// ReadOnlyRoot is set.
+func (c *Config) PrepareStorage() []string {
+	if !c.ReadOnlyRoot {
+		return nil
+	}
+	var notes []string
+
+	if c.LogFile != "" {
+		dir, ok := c.writableDir(filepath.Dir(c.LogFile), "logs")
+		if ok {
+			c.LogFile = filepath.Join(dir, filepath.Base(c.LogFile))
+		} else {
+			notes = append(notes, fmt.Sprintf("no writable log directory for %s, logging to stdout only", c.LogFile))
+			c.LogFile = ""
+			c.LogToStdout = true
+		}
+	}
+
+	if c.CapsCacheFile != "" {
+		dir, ok := c.writableDir(filepath.Dir(c.CapsCacheFile), "")
+		if ok {
+			c.CapsCacheFile = filepath.Join(dir, filepath.Base(c.CapsCacheFile))
+		} else {
+			notes = append(notes, "no writable directory for the capability cache, cameras are probed every start")
+			c.CapsCacheFile = ""
+		}
+	}
+
+	if c.SnapshotDir != "" {
+		dir, ok := c.writableDir(c.SnapshotDir, "snapshots")
+		if ok {
+			c.SnapshotDir = dir
+		} else {
+			notes = append(notes, fmt.Sprintf("no writable snapshot directory for %s, snapshots disabled", c.SnapshotDir))
+			c.SnapshotDir = ""
+		}
+	}
+
+	if c.ClipSeconds > 0 && c.ClipsDir != "" {
+		dir, ok := c.writableDir(c.ClipsDir, "clips")
+		if ok {
+			c.ClipsDir = dir
+		} else {
+			notes = append(notes, fmt.Sprintf("no writable clip directory for %s, clips disabled", c.ClipsDir))
+			c.ClipsDir = ""
+		}
+	}
+
+	if c.TripReportDir != "" {
+		dir, ok := c.writableDir(c.TripReportDir, "trips")
+		if ok {
+			c.TripReportDir = dir
+		} else {
+			notes = append(notes, fmt.Sprintf("no writable trip report directory for %s, reports are not saved", c.TripReportDir))
+			c.TripReportDir = ""
+		}
+	}
+
+	if c.UploadEnabled && c.UploadStateFile != "" {
+		dir, ok := c.writableDir(filepath.Dir(c.UploadStateFile), "")
+		if ok {
+			c.UploadStateFile = filepath.Join(dir, filepath.Base(c.UploadStateFile))
+		} else {
+			notes = append(notes, "no writable directory for the upload journal, uploads start over every start")
+			c.UploadStateFile = ""
+		}
+	}
+
+	if c.Path != "" && !dirWritable(filepath.Dir(c.Path)) {
+		c.ConfigReadOnly = true
+		notes = append(notes, fmt.Sprintf("%s is read-only, settings changes apply until restart only", c.Path))
+	}
+	return notes
+}
+
+// writableDir returns dir if it is writable, otherwise StateDir/sub if
+// that is, otherwise false.
+func (c *Config) writableDir(dir, sub string) (string, bool) {
+	if dirWritable(dir) {
+		return dir, true
+	}
+	if c.StateDir == "" {
+		return "", false
+	}
+	alt := filepath.Join(c.StateDir, sub)
+	if dirWritable(alt) {
+		return alt, true
+	}
+	return "", false
+}
+
+// dirWritable creates dir if needed and checks a file can be created in it.
+func dirWritable(dir string) bool {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return false
+	}
+	f, err := os.CreateTemp(dir, ".write-test-*")
+	if err != nil {
+		return false
+	}
+	name := f.Name()
+	f.Close()
+	os.Remove(name)
+	return true
+}
@@ -0,0 +1,71 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPrepareStorage_RelocatesAndDegrades(t *testing.T) {
+	root := t.TempDir()
+	// A regular file where a directory is expected can't be written
+	// under, even as root
+	blocked := filepath.Join(root, "ro")
+	if err := os.WriteFile(blocked, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	state := filepath.Join(root, "state")
+
+	cfg := DefaultConfig()
+	cfg.ReadOnlyRoot = true
+	cfg.StateDir = state
+	cfg.Path = filepath.Join(blocked, "config.ini")
+	cfg.LogFile = filepath.Join(blocked, "logs", "camera_dashboard.log")
+	cfg.CapsCacheFile = filepath.Join(root, "writable", "camera_caps.json")
+	cfg.SnapshotDir = filepath.Join(blocked, "snapshots")
+	cfg.ClipSeconds = 30
+	cfg.ClipsDir = filepath.Join(blocked, "clips")
+	cfg.TripReportDir = filepath.Join(blocked, "trips")
+
+	notes := cfg.PrepareStorage()
+	if cfg.LogFile != filepath.Join(state, "logs", "camera_dashboard.log") {
+		t.Errorf("LogFile = %q, want it under state_dir", cfg.LogFile)
+	}
+	if cfg.CapsCacheFile != filepath.Join(root, "writable", "camera_caps.json") {
+		t.Errorf("CapsCacheFile = %q, writable path should be kept", cfg.CapsCacheFile)
+	}
+	if cfg.SnapshotDir != filepath.Join(state, "snapshots") {
+		t.Errorf("SnapshotDir = %q, want it under state_dir", cfg.SnapshotDir)
+	}
+	if cfg.ClipsDir != filepath.Join(state, "clips") {
+		t.Errorf("ClipsDir = %q, want it under state_dir", cfg.ClipsDir)
+	}
+	if cfg.TripReportDir != filepath.Join(state, "trips") {
+		t.Errorf("TripReportDir = %q, want it under state_dir", cfg.TripReportDir)
+	}
+	if !cfg.ConfigReadOnly || len(notes) != 1 {
+		t.Errorf("ConfigReadOnly = %v, notes = %q", cfg.ConfigReadOnly, notes)
+	}
+
+	// Nowhere to relocate to: disable instead
+	cfg = DefaultConfig()
+	cfg.ReadOnlyRoot = true
+	cfg.StateDir = filepath.Join(blocked, "state")
+	cfg.LogFile = filepath.Join(blocked, "app.log")
+	cfg.SnapshotDir = filepath.Join(blocked, "snapshots")
+	cfg.CapsCacheFile = filepath.Join(blocked, "camera_caps.json")
+	cfg.TripReportDir = filepath.Join(blocked, "trips")
+	cfg.PrepareStorage()
+	if cfg.LogFile != "" || !cfg.LogToStdout || cfg.SnapshotDir != "" || cfg.CapsCacheFile != "" || cfg.TripReportDir != "" {
+		t.Errorf("unwritable paths should be disabled: log %q stdout %v snapshots %q caps %q trips %q",
+			cfg.LogFile, cfg.LogToStdout, cfg.SnapshotDir, cfg.CapsCacheFile, cfg.TripReportDir)
+	}
+}
+
+func TestPrepareStorage_OffByDefault(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.SnapshotDir = "/nonexistent/\x00"
+	if notes := cfg.PrepareStorage(); notes != nil || cfg.SnapshotDir != "/nonexistent/\x00" {
+		t.Errorf("PrepareStorage without read_only changed config: %q", notes)
+	}
+}
@@ -0,0 +1,35 @@
+package helpers
+
+import (
+	"fmt"
+	"image/color"
+	"strconv"
+	"strings"
+)
+
+// =============================================================================
+// Config colors
+// =============================================================================
+// Colors in config.ini are written as #RRGGBB or #RRGGBBAA (the # is
+// optional), or "none" for transparent.
+// =============================================================================
+
+// ParseHexColor parses a config color.
+func ParseHexColor(s string) (color.NRGBA, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if s == "none" || s == "transparent" {
+		return color.NRGBA{}, nil
+	}
+	hex := strings.TrimPrefix(s, "#")
+	if len(hex) != 6 && len(hex) != 8 {
+		return color.NRGBA{}, fmt.Errorf("color %q must be #RRGGBB, #RRGGBBAA or none", s)
+	}
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return color.NRGBA{}, fmt.Errorf("color %q must be #RRGGBB, #RRGGBBAA or none", s)
+	}
+	if len(hex) == 6 {
+		v = v<<8 | 0xff
+	}
+	return color.NRGBA{R: uint8(v >> 24), G: uint8(v >> 16), B: uint8(v >> 8), A: uint8(v)}, nil
+}
@@ -0,0 +1,99 @@
+package helpers
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// =============================================================================
+// Container detection and device checks
+// =============================================================================
+// In a container the cameras, sysfs and the display only exist if they
+// were passed in. CheckDeviceAccess lists what is missing together with
+// the docker run option that provides it, so a misconfigured container
+// says so at startup instead of showing "no cameras".
+// =============================================================================
+
+// containerRoot is prefixed to every probed path; replaced in tests.
+var containerRoot = "/"
+
+// DetectContainer returns the container runtime the process runs under
+// ("docker", "podman", "kubernetes", "lxc", "containerd"), or "" on bare
+// metal.
+func DetectContainer() string {
+	if fileExists(rootPath(".dockerenv")) {
+		return "docker"
+	}
+	if fileExists(rootPath("run/.containerenv")) {
+		return "podman"
+	}
+	if os.Getenv("KUBERNETES_SERVICE_HOST") != "" {
+		return "kubernetes"
+	}
+	if data, err := os.ReadFile(rootPath("proc/1/cgroup")); err == nil {
+		cgroup := string(data)
+		for _, name := range []string{"kubepods", "docker", "containerd", "lxc"} {
+			if strings.Contains(cgroup, name) {
+				if name == "kubepods" {
+					return "kubernetes"
+				}
+				return name
+			}
+		}
+	}
+	return ""
+}
+
+// DeviceAccessIssue is a missing mount or device with the fix.
+type DeviceAccessIssue struct {
+	Problem string
+	Fix     string
+}
+
+// CheckDeviceAccess reports missing camera devices, sysfs trees and (for
+// the windowed UI) the X11 socket.
+func CheckDeviceAccess(needDisplay bool) []DeviceAccessIssue {
+	var issues []DeviceAccessIssue
+	if videos, _ := filepath.Glob(rootPath("dev/video*")); len(videos) == 0 {
+		issues = append(issues, DeviceAccessIssue{
+			Problem: "no /dev/video* devices",
+			Fix:     "--device /dev/video0 (one per camera node), or -v /dev:/dev --device-cgroup-rule 'c 81:* rmw' for hotplug",
+		})
+	}
+	if !fileExists(rootPath("sys/class/video4linux")) {
+		issues = append(issues, DeviceAccessIssue{
+			Problem: "/sys/class/video4linux missing (hotplug and USB identity need it)",
+			Fix:     "-v /sys:/sys:ro",
+		})
+	}
+	if !fileExists(rootPath("sys/bus/usb/devices")) {
+		issues = append(issues, DeviceAccessIssue{
+			Problem: "/sys/bus/usb/devices missing (USB topology diagnostics need it)",
+			Fix:     "-v /sys:/sys:ro",
+		})
+	}
+	if needDisplay {
+		if os.Getenv("DISPLAY") == "" {
+			issues = append(issues, DeviceAccessIssue{
+				Problem: "DISPLAY not set",
+				Fix:     "-e DISPLAY=:0, or run with -headless",
+			})
+		} else if !fileExists(rootPath("tmp/.X11-unix")) {
+			issues = append(issues, DeviceAccessIssue{
+				Problem: "/tmp/.X11-unix missing",
+				Fix:     "-v /tmp/.X11-unix:/tmp/.X11-unix, or run with -headless",
+			})
+		}
+	}
+	return issues
+}
+
+func rootPath(rel string) string {
+	return filepath.Join(containerRoot, rel)
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
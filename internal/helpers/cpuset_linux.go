@@ -0,0 +1,50 @@
+package helpers
+
+import (
+	"fmt"
+	"syscall"
+	"unsafe"
+)
+
+// PinCurrentThread restricts the calling OS thread to cpus via
+// sched_setaffinity(2). The caller must have called runtime.LockOSThread,
+// otherwise the goroutine may migrate to an unpinned thread (and another
+// goroutine inherit the pinned one).
+func PinCurrentThread(cpus []int) error {
+	if len(cpus) == 0 {
+		return nil
+	}
+	var mask [16]uint64 // Up to 1024 CPUs, matching glibc's cpu_set_t
+	for _, cpu := range cpus {
+		if cpu < 0 || cpu >= len(mask)*64 {
+			return fmt.Errorf("CPU %d out of range", cpu)
+		}
+		mask[cpu/64] |= 1 << (uint(cpu) % 64)
+	}
+	_, _, errno := syscall.RawSyscall(syscall.SYS_SCHED_SETAFFINITY, 0,
+		uintptr(len(mask)*8), uintptr(unsafe.Pointer(&mask[0])))
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+// ThreadCPUs returns the CPUs the calling OS thread may run on, via
+// sched_getaffinity(2).
+func ThreadCPUs() ([]int, error) {
+	var mask [16]uint64
+	_, _, errno := syscall.RawSyscall(syscall.SYS_SCHED_GETAFFINITY, 0,
+		uintptr(len(mask)*8), uintptr(unsafe.Pointer(&mask[0])))
+	if errno != 0 {
+		return nil, errno
+	}
+	var cpus []int
+	for i, word := range mask {
+		for bit := 0; bit < 64; bit++ {
+			if word&(1<<uint(bit)) != 0 {
+				cpus = append(cpus, i*64+bit)
+			}
+		}
+	}
+	return cpus, nil
+}
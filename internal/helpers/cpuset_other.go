@@ -0,0 +1,18 @@
+//go:build !linux
+
+package helpers
+
+import "errors"
+
+// PinCurrentThread is only supported on Linux.
+func PinCurrentThread(cpus []int) error {
+	if len(cpus) == 0 {
+		return nil
+	}
+	return errors.New("CPU pinning is only supported on Linux")
+}
+
+// ThreadCPUs is only supported on Linux.
+func ThreadCPUs() ([]int, error) {
+	return nil, errors.New("CPU affinity is only supported on Linux")
+}
@@ -0,0 +1,15 @@
+//go:build !windows
+
+package helpers
+
+import "syscall"
+
+// DiskFree returns the bytes available to unprivileged users and the
+// total size of the filesystem holding path.
+func DiskFree(path string) (free, total uint64, err error) {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(path, &st); err != nil {
+		return 0, 0, err
+	}
+	return uint64(st.Bavail) * uint64(st.Bsize), uint64(st.Blocks) * uint64(st.Bsize), nil
+}
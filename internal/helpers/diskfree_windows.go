@@ -0,0 +1,10 @@
+//go:build windows
+
+package helpers
+
+import "errors"
+
+// DiskFree is not supported on Windows.
+func DiskFree(path string) (free, total uint64, err error) {
+	return 0, 0, errors.New("disk space is not reported on Windows")
+}
@@ -1,12 +1,27 @@
 package helpers
 
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
 // =============================================================================
 // Smart Grid Layout
 // =============================================================================
 // Returns a sensible (rows, cols) for N camera widgets.
 // Matches Python's get_smart_grid() from ui/layout.py.
+//
+// Layouts can be overridden per tile count with a list such as
+// "4:1x4, 6:3x2" (tiles:ROWSxCOLS), e.g. a 1x4 strip on an ultrawide
+// display. GridFor applies the overrides and falls back to GetSmartGrid.
 // =============================================================================
 
+// GridSize is a grid layout.
+type GridSize struct {
+	Rows, Cols int
+}
+
 // GetSmartGrid returns optimal (rows, cols) for n camera widgets.
 // Handles 1-9 cameras with hardcoded optimal layouts, and 10+
 // with a dynamic formula capping at 4 columns.
@@ -52,3 +67,52 @@ func isqrt(n int) int {
 	}
 	return x
 }
+
+// ParseGridLayouts parses a "tiles:ROWSxCOLS" list into overrides keyed by
+// tile count. Every layout must have at least as many cells as tiles. An
+// empty string returns nil (no overrides).
+func ParseGridLayouts(s string) (map[int]GridSize, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, nil
+	}
+
+	layouts := make(map[int]GridSize)
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		idx := strings.Index(part, ":")
+		if idx < 0 {
+			return nil, fmt.Errorf("invalid grid layout %q, want tiles:ROWSxCOLS", part)
+		}
+		tiles, err := strconv.Atoi(strings.TrimSpace(part[:idx]))
+		if err != nil || tiles < 1 {
+			return nil, fmt.Errorf("invalid tile count in grid layout %q", part)
+		}
+		dims := strings.Split(strings.ToLower(strings.TrimSpace(part[idx+1:])), "x")
+		if len(dims) != 2 {
+			return nil, fmt.Errorf("invalid grid layout %q, want tiles:ROWSxCOLS", part)
+		}
+		rows, errRows := strconv.Atoi(strings.TrimSpace(dims[0]))
+		cols, errCols := strconv.Atoi(strings.TrimSpace(dims[1]))
+		if errRows != nil || errCols != nil || rows < 1 || cols < 1 {
+			return nil, fmt.Errorf("invalid grid size in layout %q", part)
+		}
+		if rows*cols < tiles {
+			return nil, fmt.Errorf("grid layout %q has %d cells for %d tiles", part, rows*cols, tiles)
+		}
+		layouts[tiles] = GridSize{Rows: rows, Cols: cols}
+	}
+	return layouts, nil
+}
+
+// GridFor returns the (rows, cols) for n widgets: the override for n if
+// there is one, otherwise GetSmartGrid's layout.
+func GridFor(n int, overrides map[int]GridSize) (rows, cols int) {
+	if g, ok := overrides[n]; ok && g.Rows*g.Cols >= n {
+		return g.Rows, g.Cols
+	}
+	return GetSmartGrid(n)
+}
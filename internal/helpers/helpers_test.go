@@ -1,7 +1,12 @@
 package helpers
 
 import (
+	"image/color"
+	"net"
 	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
 	"syscall"
 	"testing"
 )
@@ -74,10 +79,88 @@ func TestGetSmartGrid_TenPlus(t *testing.T) {
 	}
 }
 
+func TestParseGridLayouts(t *testing.T) {
+	got, err := ParseGridLayouts(" 4:1x4, 6 : 3X2 ")
+	if err != nil {
+		t.Fatalf("ParseGridLayouts() error: %v", err)
+	}
+	want := map[int]GridSize{4: {1, 4}, 6: {3, 2}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseGridLayouts() = %v, want %v", got, want)
+	}
+
+	if got, err := ParseGridLayouts(""); got != nil || err != nil {
+		t.Errorf("ParseGridLayouts(\"\") = %v, %v; want nil, nil", got, err)
+	}
+	for _, bad := range []string{"4", "4:1x", "4:1x2x2", "0:1x1", "4:0x4", "5:2x2", "x:1x4"} {
+		if _, err := ParseGridLayouts(bad); err == nil {
+			t.Errorf("ParseGridLayouts(%q) should fail", bad)
+		}
+	}
+}
+
+func TestGridFor(t *testing.T) {
+	overrides := map[int]GridSize{4: {1, 4}, 3: {1, 1}}
+	if rows, cols := GridFor(4, overrides); rows != 1 || cols != 4 {
+		t.Errorf("GridFor(4) = (%d,%d), want (1,4)", rows, cols)
+	}
+	// No override, or one without enough cells: the smart grid
+	if rows, cols := GridFor(5, overrides); rows != 2 || cols != 3 {
+		t.Errorf("GridFor(5) = (%d,%d), want (2,3)", rows, cols)
+	}
+	if rows, cols := GridFor(3, overrides); rows != 1 || cols != 3 {
+		t.Errorf("GridFor(3) = (%d,%d), want (1,3)", rows, cols)
+	}
+}
+
 // ===========================================================================
 // isqrt tests
 // ===========================================================================
 
+func TestParseHexColor(t *testing.T) {
+	for in, want := range map[string]color.NRGBA{
+		"#FFC800":   {255, 200, 0, 255},
+		"1e1e1e":    {30, 30, 30, 255},
+		"#00000080": {0, 0, 0, 128},
+		" None ":    {},
+	} {
+		if got, err := ParseHexColor(in); err != nil || got != want {
+			t.Errorf("ParseHexColor(%q) = %v, %v; want %v", in, got, err, want)
+		}
+	}
+	for _, in := range []string{"", "#fff", "#12345g", "red"} {
+		if _, err := ParseHexColor(in); err == nil {
+			t.Errorf("ParseHexColor(%q) should fail", in)
+		}
+	}
+}
+
+func TestParseCIDRs(t *testing.T) {
+	nets, err := ParseCIDRs([]string{"192.168.1.0/24", " 10.8.0.7 ", "fd00::/8"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for ip, want := range map[string]bool{
+		"192.168.1.40": true,
+		"192.168.2.1":  false,
+		"10.8.0.7":     true,
+		"10.8.0.8":     false,
+		"fd00::1":      true,
+		"127.0.0.1":    true, // Loopback is always allowed
+		"::1":          true,
+		"100.64.3.2":   false,
+	} {
+		if got := AllowedIP(net.ParseIP(ip), nets); got != want {
+			t.Errorf("AllowedIP(%s) = %v, want %v", ip, got, want)
+		}
+	}
+	for _, bad := range []string{"lte0", "192.168.1.0/33", ""} {
+		if _, err := ParseCIDRs([]string{bad}); err == nil {
+			t.Errorf("ParseCIDRs(%q) should fail", bad)
+		}
+	}
+}
+
 func TestIsqrt(t *testing.T) {
 	tests := []struct {
 		input int
@@ -190,3 +273,195 @@ func TestIsPIDAlive_Nonexistent(t *testing.T) {
 		t.Skip("PID 2147483647 actually exists (unlikely)")
 	}
 }
+
+// ===========================================================================
+// CPU list tests
+// ===========================================================================
+
+func TestParseCPUList(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []int
+	}{
+		{"", nil},
+		{"2", []int{2}},
+		{"1-3", []int{1, 2, 3}},
+		{"0, 2-3", []int{0, 2, 3}},
+		{"3,1,1-2", []int{1, 2, 3}},
+	}
+	for _, tc := range tests {
+		got, err := ParseCPUList(tc.in)
+		if err != nil {
+			t.Errorf("ParseCPUList(%q) error: %v", tc.in, err)
+			continue
+		}
+		if FormatCPUList(got) != FormatCPUList(tc.want) {
+			t.Errorf("ParseCPUList(%q) = %v, want %v", tc.in, got, tc.want)
+		}
+	}
+}
+
+func TestParseCPUList_Invalid(t *testing.T) {
+	for _, in := range []string{"a", "3-1", "-1", "1,,2", "1-"} {
+		if _, err := ParseCPUList(in); err == nil {
+			t.Errorf("ParseCPUList(%q) expected error", in)
+		}
+	}
+}
+
+func TestValidateCPUList_NonexistentCPU(t *testing.T) {
+	if _, err := ValidateCPUList("4096"); err == nil {
+		t.Error("expected error for CPU beyond NumCPU")
+	}
+}
+
+func TestPinCurrentThread(t *testing.T) {
+	if err := PinCurrentThread(nil); err != nil {
+		t.Errorf("PinCurrentThread(nil) error: %v", err)
+	}
+	if runtime.GOOS != "linux" {
+		t.Skip("CPU pinning is Linux-only")
+	}
+
+	// Run on a throwaway locked goroutine so the test's own thread keeps
+	// its affinity (the thread is discarded when the goroutine exits).
+	errCh := make(chan error, 1)
+	go func() {
+		runtime.LockOSThread()
+		errCh <- PinCurrentThread([]int{0})
+	}()
+	if err := <-errCh; err != nil {
+		t.Errorf("PinCurrentThread([0]) error: %v", err)
+	}
+}
+
+func TestThreadCPUs(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("CPU affinity is Linux-only")
+	}
+	type result struct {
+		cpus []int
+		err  error
+	}
+	ch := make(chan result, 1)
+	go func() {
+		runtime.LockOSThread()
+		if err := PinCurrentThread([]int{0}); err != nil {
+			ch <- result{err: err}
+			return
+		}
+		cpus, err := ThreadCPUs()
+		ch <- result{cpus, err}
+	}()
+	r := <-ch
+	if r.err != nil || len(r.cpus) != 1 || r.cpus[0] != 0 {
+		t.Errorf("ThreadCPUs() after pinning to 0 = %v, %v; want [0]", r.cpus, r.err)
+	}
+}
+
+// ===========================================================================
+// IO priority tests
+// ===========================================================================
+
+func TestParseIOClass(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int
+	}{
+		{"", IOClassNone},
+		{"idle", IOClassIdle},
+		{"Best-Effort", IOClassBestEffort},
+		{"be", IOClassBestEffort},
+	}
+	for _, tc := range tests {
+		got, err := ParseIOClass(tc.in)
+		if err != nil || got != tc.want {
+			t.Errorf("ParseIOClass(%q) = (%d, %v), want %d", tc.in, got, err, tc.want)
+		}
+	}
+	if _, err := ParseIOClass("realtime"); err == nil {
+		t.Error("ParseIOClass(realtime) expected error")
+	}
+}
+
+func TestRunWithIOPriority(t *testing.T) {
+	for _, class := range []int{IOClassNone, IOClassIdle} {
+		ran := false
+		err := RunWithIOPriority(class, 7, func() error {
+			ran = true
+			return os.ErrExist
+		})
+		if !ran || err != os.ErrExist {
+			t.Errorf("class %d: ran=%v err=%v, want fn run and its error returned", class, ran, err)
+		}
+	}
+}
+
+// ===========================================================================
+// Container tests
+// ===========================================================================
+
+func withContainerRoot(t *testing.T, files ...string) {
+	t.Helper()
+	root := t.TempDir()
+	for _, f := range files {
+		path := filepath.Join(root, f)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("0::/system.slice/docker-abc.scope\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	orig := containerRoot
+	containerRoot = root
+	t.Cleanup(func() { containerRoot = orig })
+}
+
+func TestDetectContainer(t *testing.T) {
+	t.Setenv("KUBERNETES_SERVICE_HOST", "")
+	withContainerRoot(t, ".dockerenv")
+	if got := DetectContainer(); got != "docker" {
+		t.Errorf("DetectContainer() = %q, want docker", got)
+	}
+	withContainerRoot(t, "proc/1/cgroup")
+	if got := DetectContainer(); got != "docker" {
+		t.Errorf("DetectContainer() from cgroup = %q, want docker", got)
+	}
+	withContainerRoot(t)
+	if got := DetectContainer(); got != "" {
+		t.Errorf("DetectContainer() on bare metal = %q", got)
+	}
+}
+
+func TestCheckDeviceAccess(t *testing.T) {
+	withContainerRoot(t, "dev/video0", "sys/class/video4linux/video0/name")
+	t.Setenv("DISPLAY", "")
+
+	if issues := CheckDeviceAccess(false); len(issues) != 1 || issues[0].Fix != "-v /sys:/sys:ro" {
+		t.Errorf("headless issues = %+v, want only /sys/bus/usb", issues)
+	}
+	if issues := CheckDeviceAccess(true); len(issues) != 2 {
+		t.Errorf("display issues = %+v, want /sys/bus/usb + DISPLAY", issues)
+	}
+}
+
+// ===========================================================================
+// DiskFree tests
+// ===========================================================================
+
+func TestDiskFree(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("not reported on Windows")
+	}
+	free, total, err := DiskFree(t.TempDir())
+	if err != nil {
+		t.Fatalf("DiskFree() error: %v", err)
+	}
+	if total == 0 || free > total {
+		t.Errorf("DiskFree() = %d free of %d", free, total)
+	}
+	if _, _, err := DiskFree(filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Error("DiskFree(missing) should fail")
+	}
+}
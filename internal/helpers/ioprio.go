@@ -0,0 +1,57 @@
+package helpers
+
+import (
+	"fmt"
+	"log"
+	"runtime"
+	"strings"
+)
+
+// =============================================================================
+// IO priority — keep app disk writes from starving the UI on slow SD cards
+// =============================================================================
+// Mirrors ionice(1): class best-effort (levels 0-7, 7 lowest) or idle
+// (only when the disk is otherwise unused). Linux IO priority is
+// per-thread, so writes run on a dedicated locked OS thread.
+// =============================================================================
+
+// IO priority classes (linux/ioprio.h)
+const (
+	IOClassNone       = 0
+	IOClassBestEffort = 2
+	IOClassIdle       = 3
+)
+
+// ParseIOClass parses an ionice-style class name. "" means unchanged.
+func ParseIOClass(s string) (int, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "none":
+		return IOClassNone, nil
+	case "best-effort", "besteffort", "be":
+		return IOClassBestEffort, nil
+	case "idle":
+		return IOClassIdle, nil
+	default:
+		return IOClassNone, fmt.Errorf("unknown IO class %q (use best-effort or idle)", s)
+	}
+}
+
+// RunWithIOPriority runs fn on an OS thread with the given IO priority
+// and returns its error. With IOClassNone fn runs directly. If the
+// priority can't be set, fn still runs at the default priority.
+func RunWithIOPriority(class, level int, fn func() error) error {
+	if class == IOClassNone {
+		return fn()
+	}
+	errCh := make(chan error, 1)
+	go func() {
+		// Never unlocked: the thread exits with the goroutine, so its
+		// lowered priority can't leak to other goroutines.
+		runtime.LockOSThread()
+		if err := setThreadIOPriority(class, level); err != nil {
+			log.Printf("[IOPrio] Failed to set IO priority (class %d, level %d): %v", class, level, err)
+		}
+		errCh <- fn()
+	}()
+	return <-errCh
+}
@@ -0,0 +1,27 @@
+package helpers
+
+import "syscall"
+
+const (
+	ioprioWhoProcess = 1  // IOPRIO_WHO_PROCESS; who=0 is the calling thread
+	ioprioClassShift = 13 // IOPRIO_CLASS_SHIFT
+)
+
+// setThreadIOPriority sets the calling thread's IO priority via ioprio_set(2).
+func setThreadIOPriority(class, level int) error {
+	if level < 0 {
+		level = 0
+	}
+	if level > 7 {
+		level = 7
+	}
+	if class == IOClassIdle {
+		level = 0 // Idle has no levels
+	}
+	prio := class<<ioprioClassShift | level
+	_, _, errno := syscall.RawSyscall(syscall.SYS_IOPRIO_SET, ioprioWhoProcess, 0, uintptr(prio))
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
@@ -0,0 +1,10 @@
+//go:build !linux
+
+package helpers
+
+import "errors"
+
+// setThreadIOPriority is only supported on Linux.
+func setThreadIOPriority(class, level int) error {
+	return errors.New("IO priority is only supported on Linux")
+}
@@ -59,7 +59,7 @@ func KillDeviceHoldersWithGrace(devicePath string, enabled bool, grace time.Dura
 
 	// Phase 1: SIGTERM
 	for pid := range pids {
-		if err := syscall.Kill(pid, syscall.SIGTERM); err != nil {
+		if err := signalPID(pid, syscall.SIGTERM); err != nil {
 			if isPermissionError(err) {
 				// Escalate to sudo fuser -k
 				runCmd("sudo", "fuser", "-k", devicePath)
@@ -77,7 +77,7 @@ func KillDeviceHoldersWithGrace(devicePath string, enabled bool, grace time.Dura
 		if !isPIDAlive(pid) {
 			continue
 		}
-		if err := syscall.Kill(pid, syscall.SIGKILL); err != nil {
+		if err := signalPID(pid, syscall.SIGKILL); err != nil {
 			if isPermissionError(err) {
 				runCmd("sudo", "fuser", "-k", devicePath)
 			} else {
@@ -118,7 +118,7 @@ func getPIDsFromFuser(devicePath string) map[int]struct{} {
 
 // isPIDAlive checks if a PID exists by sending signal 0.
 func isPIDAlive(pid int) bool {
-	err := syscall.Kill(pid, 0)
+	err := signalPID(pid, 0)
 	return err == nil
 }
 
@@ -0,0 +1,10 @@
+//go:build !windows
+
+package helpers
+
+import "syscall"
+
+// signalPID sends sig to pid (0 checks that the process exists).
+func signalPID(pid int, sig syscall.Signal) error {
+	return syscall.Kill(pid, sig)
+}
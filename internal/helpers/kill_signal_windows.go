@@ -0,0 +1,19 @@
+//go:build windows
+
+package helpers
+
+import (
+	"os"
+	"syscall"
+)
+
+// signalPID terminates pid; Windows has no signals, so SIGTERM and
+// SIGKILL both kill and 0 only checks that the process can be opened.
+// (Device holders are never found on Windows: there is no lsof/fuser.)
+func signalPID(pid int, sig syscall.Signal) error {
+	p, err := os.FindProcess(pid)
+	if err != nil || sig == 0 {
+		return err
+	}
+	return p.Kill()
+}
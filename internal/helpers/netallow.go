@@ -0,0 +1,70 @@
+package helpers
+
+import (
+	"fmt"
+	"net"
+	"strings"
+)
+
+// =============================================================================
+// Network allow-lists
+// =============================================================================
+// The HTTP endpoints accept clients from the networks in [health]
+// allowed_cidrs, e.g. "192.168.1.0/24, 10.8.0.0/16" for the home network
+// and a VPN but not the LTE interface. A bare address is one host.
+// Loopback is always allowed.
+// =============================================================================
+
+// ParseCIDRs parses allow-list entries.
+func ParseCIDRs(entries []string) ([]*net.IPNet, error) {
+	nets := make([]*net.IPNet, 0, len(entries))
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if !strings.Contains(entry, "/") {
+			ip := net.ParseIP(entry)
+			if ip == nil {
+				return nil, fmt.Errorf("%q is not an address or CIDR range", entry)
+			}
+			bits := 8 * net.IPv6len
+			if ip4 := ip.To4(); ip4 != nil {
+				ip, bits = ip4, 8*net.IPv4len
+			}
+			nets = append(nets, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, n, err := net.ParseCIDR(entry)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not an address or CIDR range", entry)
+		}
+		nets = append(nets, n)
+	}
+	return nets, nil
+}
+
+// AllowedIP reports whether ip is loopback or in one of nets.
+func AllowedIP(ip net.IP, nets []*net.IPNet) bool {
+	if ip.IsLoopback() {
+		return true
+	}
+	for _, n := range nets {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsLoopbackAddr reports whether the listen address addr (host:port)
+// accepts connections from this machine only. An empty host listens on
+// every interface.
+func IsLoopbackAddr(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
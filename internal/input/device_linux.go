@@ -0,0 +1,177 @@
+package input
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// evdevEvent mirrors struct input_event: 24 bytes on 64-bit, 16 on
+// 32-bit Raspberry Pi OS (the timeval fields are longs).
+type evdevEvent struct {
+	Time  syscall.Timeval
+	Type  uint16
+	Code  uint16
+	Value int32
+}
+
+// evdevDevice is an open /dev/input/event* node.
+type evdevDevice struct {
+	f   *os.File
+	buf []byte
+}
+
+// OpenEvdev opens an evdev device (e.g. a rotary-encoder or gpio-keys
+// node under /dev/input/by-path).
+func OpenEvdev(path string) (Device, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	return &evdevDevice{f: f, buf: make([]byte, 64*unsafe.Sizeof(evdevEvent{}))}, nil
+}
+
+func (d *evdevDevice) Read() ([]Control, error) {
+	n, err := d.f.Read(d.buf)
+	if err != nil {
+		return nil, err
+	}
+	size := int(unsafe.Sizeof(evdevEvent{}))
+	offset := int(unsafe.Sizeof(syscall.Timeval{}))
+	var controls []Control
+	for i := 0; i+size <= n; i += size {
+		ev := d.buf[i+offset : i+size]
+		typ := binary.LittleEndian.Uint16(ev[0:2])
+		code := binary.LittleEndian.Uint16(ev[2:4])
+		value := int32(binary.LittleEndian.Uint32(ev[4:8]))
+		controls = append(controls, decodeEvdev(typ, code, value)...)
+	}
+	return controls, nil
+}
+
+func (d *evdevDevice) Close() error {
+	return d.f.Close()
+}
+
+// GPIO character device uAPI (v1 line handles and events, linux/gpio.h).
+const (
+	gpioHandleRequestInput     = 1 << 0
+	gpioHandleRequestActiveLow = 1 << 2
+	gpioEventRisingEdge        = 1 << 0
+	gpioEventFallingEdge       = 1 << 1
+
+	gpioGetLineHandleIoctl      = 0xC16CB403 // _IOWR(0xB4, 0x03, struct gpiohandle_request)
+	gpioGetLineEventIoctl       = 0xC030B404 // _IOWR(0xB4, 0x04, struct gpioevent_request)
+	gpioHandleGetLineValueIoctl = 0xC040B408 // _IOWR(0xB4, 0x08, struct gpiohandle_data)
+)
+
+// gpioHandleRequest mirrors struct gpiohandle_request (364 bytes).
+type gpioHandleRequest struct {
+	LineOffsets   [64]uint32
+	Flags         uint32
+	DefaultValues [64]uint8
+	ConsumerLabel [32]byte
+	Lines         uint32
+	Fd            int32
+}
+
+// gpioHandleData mirrors struct gpiohandle_data.
+type gpioHandleData struct {
+	Values [64]uint8
+}
+
+// gpioEventRequest mirrors struct gpioevent_request (48 bytes).
+type gpioEventRequest struct {
+	LineOffset    uint32
+	HandleFlags   uint32
+	EventFlags    uint32
+	ConsumerLabel [32]byte
+	Fd            int32
+}
+
+// gpioEventDataSize is sizeof(struct gpioevent_data): a u64 timestamp
+// and a u32 edge id, padded.
+const gpioEventDataSize = 16
+
+// gpioButton is a push button on one GPIO line; the event fd holds it.
+type gpioButton struct {
+	f   *os.File
+	pin int
+}
+
+// OpenGPIOButton requests press edges of a button on line pin of chip
+// (e.g. /dev/gpiochip0): falling for a button to ground (with a pull-up),
+// rising for one to 3.3V with activeHigh.
+func OpenGPIOButton(chip string, pin int, activeHigh bool) (Device, error) {
+	f, err := os.OpenFile(chip, os.O_RDWR, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	req := gpioEventRequest{LineOffset: uint32(pin), HandleFlags: gpioHandleRequestInput, EventFlags: gpioEventFallingEdge}
+	if activeHigh {
+		req.EventFlags = gpioEventRisingEdge
+	}
+	copy(req.ConsumerLabel[:], "camera-dashboard")
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, f.Fd(), gpioGetLineEventIoctl, uintptr(unsafe.Pointer(&req))); errno != 0 {
+		return nil, fmt.Errorf("request line %d on %s: %w", pin, chip, errno)
+	}
+	return &gpioButton{f: os.NewFile(uintptr(req.Fd), fmt.Sprintf("%s:%d", chip, pin)), pin: pin}, nil
+}
+
+// Read returns one press per edge; Mapper debounces them.
+func (b *gpioButton) Read() ([]Control, error) {
+	var buf [gpioEventDataSize]byte
+	if _, err := io.ReadFull(b.f, buf[:]); err != nil {
+		return nil, err
+	}
+	return []Control{{Kind: GPIO, Code: b.pin}}, nil
+}
+
+func (b *gpioButton) Close() error {
+	return b.f.Close()
+}
+
+// gpioSwitch is a level input on one GPIO line; the handle fd holds it.
+type gpioSwitch struct {
+	f *os.File
+}
+
+// OpenGPIOSwitch requests line pin of chip as an input read by level:
+// active when pulled low (e.g. through an optocoupler), or high with
+// activeHigh.
+func OpenGPIOSwitch(chip string, pin int, activeHigh bool) (Switch, error) {
+	f, err := os.OpenFile(chip, os.O_RDWR, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	req := gpioHandleRequest{Flags: gpioHandleRequestInput, Lines: 1}
+	req.LineOffsets[0] = uint32(pin)
+	if !activeHigh {
+		req.Flags |= gpioHandleRequestActiveLow
+	}
+	copy(req.ConsumerLabel[:], "camera-dashboard")
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, f.Fd(), gpioGetLineHandleIoctl, uintptr(unsafe.Pointer(&req))); errno != 0 {
+		return nil, fmt.Errorf("request line %d on %s: %w", pin, chip, errno)
+	}
+	return &gpioSwitch{f: os.NewFile(uintptr(req.Fd), fmt.Sprintf("%s:%d", chip, pin))}, nil
+}
+
+// Active reads the line (the kernel applies active-low).
+func (s *gpioSwitch) Active() (bool, error) {
+	var data gpioHandleData
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, s.f.Fd(), gpioHandleGetLineValueIoctl, uintptr(unsafe.Pointer(&data))); errno != 0 {
+		return false, errno
+	}
+	return data.Values[0] != 0, nil
+}
+
+func (s *gpioSwitch) Close() error {
+	return s.f.Close()
+}
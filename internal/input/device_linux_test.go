@@ -0,0 +1,61 @@
+package input
+
+import (
+	"encoding/binary"
+	"os"
+	"reflect"
+	"syscall"
+	"testing"
+	"unsafe"
+)
+
+func TestGPIOStructSizes(t *testing.T) {
+	// The ioctl number encodes this size (linux/gpio.h)
+	if n := unsafe.Sizeof(gpioEventRequest{}); n != 48 {
+		t.Errorf("gpioevent_request is %d bytes, want 48", n)
+	}
+	if n := unsafe.Sizeof(gpioHandleRequest{}); n != 364 {
+		t.Errorf("gpiohandle_request is %d bytes, want 364", n)
+	}
+	want := 2*unsafe.Sizeof(syscall.Timeval{}.Sec) + 8
+	if n := unsafe.Sizeof(evdevEvent{}); n != want {
+		t.Errorf("input_event is %d bytes, want %d", n, want)
+	}
+}
+
+func TestEvdevRead(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	dev := &evdevDevice{f: r, buf: make([]byte, 64*unsafe.Sizeof(evdevEvent{}))}
+	defer dev.Close()
+
+	// Encoder step, EV_SYN, key press: one read as the kernel delivers them
+	var data []byte
+	for _, ev := range []evdevEvent{
+		{Type: evRel, Code: 0, Value: -1},
+		{Type: 0, Code: 0, Value: 0},
+		{Type: evKey, Code: 28, Value: 1},
+	} {
+		b := make([]byte, unsafe.Sizeof(ev))
+		offset := unsafe.Sizeof(ev.Time)
+		binary.LittleEndian.PutUint16(b[offset:], ev.Type)
+		binary.LittleEndian.PutUint16(b[offset+2:], ev.Code)
+		binary.LittleEndian.PutUint32(b[offset+4:], uint32(ev.Value))
+		data = append(data, b...)
+	}
+	if _, err := w.Write(data); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := dev.Read()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Control{{Rotate, -1}, {Key, 28}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Read() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,20 @@
+//go:build !linux
+
+package input
+
+import "errors"
+
+// OpenEvdev is only supported on Linux.
+func OpenEvdev(path string) (Device, error) {
+	return nil, errors.New("evdev input is only supported on Linux")
+}
+
+// OpenGPIOButton is only supported on Linux.
+func OpenGPIOButton(chip string, pin int, activeHigh bool) (Device, error) {
+	return nil, errors.New("GPIO buttons are only supported on Linux")
+}
+
+// OpenGPIOSwitch is only supported on Linux.
+func OpenGPIOSwitch(chip string, pin int, activeHigh bool) (Switch, error) {
+	return nil, errors.New("GPIO switches are only supported on Linux")
+}
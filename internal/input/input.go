@@ -0,0 +1,239 @@
+// Package input reads hardware buttons and rotary encoders, for
+// gloves-on operation where the touchscreen is impractical.
+package input
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// =============================================================================
+// Buttons and rotary encoders
+// =============================================================================
+// Three kinds of control can be bound to an action in [input]:
+//
+//   gpio17    = night_mode     Push button on GPIO line 17 (to ground;
+//                              "night_mode, active_high" for one to 3.3V)
+//   key28     = fullscreen     evdev key code 28 (KEY_ENTER), e.g. an
+//                              encoder's push switch through gpio-keys
+//   rotate_cw = next_camera    A clockwise / counter-clockwise step of a
+//   rotate_ccw = prev_camera   rotary encoder (any relative axis)
+//
+// GPIO buttons are read from the GPIO character device and debounced
+// here. Keys and encoders come from evdev devices (/dev/input/event*),
+// typically the rotary-encoder and gpio-keys device tree overlays, whose
+// drivers debounce and decode the quadrature themselves.
+// =============================================================================
+
+// Actions a control can trigger.
+const (
+	ActionNextCamera = "next_camera" // Fullscreen the next camera (from the grid: the first)
+	ActionPrevCamera = "prev_camera" // ...the previous one (from the grid: the last)
+	ActionFullscreen = "fullscreen"  // Enter/leave fullscreen
+	ActionNightMode  = "night_mode"  // Toggle night mode
+	ActionSnapshot   = "snapshot"    // Snapshot the fullscreen camera, or all from the grid
+)
+
+// Actions lists every valid action.
+var Actions = []string{ActionNextCamera, ActionPrevCamera, ActionFullscreen, ActionNightMode, ActionSnapshot}
+
+// Kind is the kind of a control.
+type Kind int
+
+const (
+	GPIO   Kind = iota // Button on a GPIO line; Code = line offset
+	Key                // evdev key; Code = key code
+	Rotate             // Encoder step; Code = +1 (clockwise) or -1
+)
+
+// Control identifies one button or encoder direction.
+type Control struct {
+	Kind Kind
+	Code int
+}
+
+func (c Control) String() string {
+	switch c.Kind {
+	case GPIO:
+		return fmt.Sprintf("gpio%d", c.Code)
+	case Key:
+		return fmt.Sprintf("key%d", c.Code)
+	case Rotate:
+		if c.Code < 0 {
+			return "rotate_ccw"
+		}
+		return "rotate_cw"
+	}
+	return "unknown"
+}
+
+// Binding is a parsed [input] entry.
+type Binding struct {
+	Control    Control
+	Action     string
+	ActiveHigh bool // GPIO button pulls the line high when pressed
+}
+
+// ParseBinding parses an [input] entry such as "gpio17" = "snapshot" or
+// "gpio27" = "night_mode, active_high".
+func ParseBinding(key, spec string) (Binding, error) {
+	var b Binding
+	key = strings.ToLower(strings.TrimSpace(key))
+	switch {
+	case key == "rotate_cw":
+		b.Control = Control{Kind: Rotate, Code: 1}
+	case key == "rotate_ccw":
+		b.Control = Control{Kind: Rotate, Code: -1}
+	case strings.HasPrefix(key, "gpio"):
+		pin, err := strconv.Atoi(key[len("gpio"):])
+		if err != nil || pin < 0 {
+			return Binding{}, fmt.Errorf("invalid GPIO line %q", key)
+		}
+		b.Control = Control{Kind: GPIO, Code: pin}
+	case strings.HasPrefix(key, "key"):
+		code, err := strconv.Atoi(key[len("key"):])
+		if err != nil || code <= 0 {
+			return Binding{}, fmt.Errorf("invalid key code %q", key)
+		}
+		b.Control = Control{Kind: Key, Code: code}
+	default:
+		return Binding{}, fmt.Errorf("unknown control %q", key)
+	}
+
+	parts := strings.Split(spec, ",")
+	b.Action = strings.ToLower(strings.TrimSpace(parts[0]))
+	if !isAction(b.Action) {
+		return Binding{}, fmt.Errorf("unknown action %q", b.Action)
+	}
+	for _, option := range parts[1:] {
+		switch strings.ToLower(strings.TrimSpace(option)) {
+		case "active_high":
+			if b.Control.Kind != GPIO {
+				return Binding{}, fmt.Errorf("active_high only applies to GPIO buttons")
+			}
+			b.ActiveHigh = true
+		case "", "active_low":
+		default:
+			return Binding{}, fmt.Errorf("unknown option %q", strings.TrimSpace(option))
+		}
+	}
+	return b, nil
+}
+
+func isAction(action string) bool {
+	for _, a := range Actions {
+		if a == action {
+			return true
+		}
+	}
+	return false
+}
+
+// Device is an open input source.
+type Device interface {
+	// Read blocks until controls are pressed or turned (nil for events
+	// that aren't presses), or the device fails or is closed.
+	Read() ([]Control, error)
+	Close() error
+}
+
+// Switch is a held input such as the reverse gear signal, read as a
+// level rather than as presses.
+type Switch interface {
+	Active() (bool, error)
+	Close() error
+}
+
+// Mapper turns control presses into actions.
+type Mapper struct {
+	mu       sync.Mutex
+	actions  map[Control]string
+	debounce time.Duration // GPIO buttons only
+	last     map[Control]time.Time
+}
+
+// NewMapper maps bindings, ignoring GPIO presses within debounce of the
+// previous one.
+func NewMapper(bindings []Binding, debounce time.Duration) *Mapper {
+	m := &Mapper{actions: make(map[Control]string), debounce: debounce, last: make(map[Control]time.Time)}
+	for _, b := range bindings {
+		m.actions[b.Control] = b.Action
+	}
+	return m
+}
+
+// Action returns the action for a press of c at now, if it is bound and
+// not a bounce.
+func (m *Mapper) Action(c Control, now time.Time) (string, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	action, ok := m.actions[c]
+	if !ok {
+		return "", false
+	}
+	if c.Kind == GPIO {
+		if last, seen := m.last[c]; seen && now.Sub(last) < m.debounce {
+			return "", false
+		}
+		m.last[c] = now
+	}
+	return action, true
+}
+
+// Run reads dev until it fails or is closed, calling handle with the
+// action of every bound press.
+func (m *Mapper) Run(name string, dev Device, handle func(action string)) {
+	for {
+		controls, err := dev.Read()
+		if err != nil {
+			log.Printf("[Input] %s stopped: %v", name, err)
+			return
+		}
+		for _, c := range controls {
+			if action, ok := m.Action(c, time.Now()); ok {
+				log.Printf("[Input] %s: %s", c, action)
+				handle(action)
+			}
+		}
+	}
+}
+
+// evdev event types and the key value for a press (linux/input-event-codes.h).
+const (
+	evKey      = 0x01
+	evRel      = 0x02
+	keyPressed = 1 // 0 = release, 2 = autorepeat
+
+	maxSteps = 16 // Encoder steps taken from one event
+)
+
+// decodeEvdev returns the controls an evdev event stands for: a key
+// press, or one Rotate per encoder step.
+func decodeEvdev(typ, code uint16, value int32) []Control {
+	switch typ {
+	case evKey:
+		if value == keyPressed {
+			return []Control{{Kind: Key, Code: int(code)}}
+		}
+	case evRel:
+		if value > maxSteps {
+			value = maxSteps
+		} else if value < -maxSteps {
+			value = -maxSteps
+		}
+		steps, step := int(value), 1
+		if value < 0 {
+			steps, step = -steps, -1
+		}
+		controls := make([]Control, 0, steps)
+		for i := 0; i < steps; i++ {
+			controls = append(controls, Control{Kind: Rotate, Code: step})
+		}
+		return controls
+	}
+	return nil
+}
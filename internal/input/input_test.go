@@ -0,0 +1,140 @@
+package input
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseBinding(t *testing.T) {
+	tests := []struct {
+		key, spec string
+		want      Binding
+	}{
+		{"gpio17", "night_mode", Binding{Control: Control{GPIO, 17}, Action: ActionNightMode}},
+		{"GPIO27", " Snapshot , active_high", Binding{Control: Control{GPIO, 27}, Action: ActionSnapshot, ActiveHigh: true}},
+		{"gpio5", "fullscreen, active_low", Binding{Control: Control{GPIO, 5}, Action: ActionFullscreen}},
+		{"key28", "fullscreen", Binding{Control: Control{Key, 28}, Action: ActionFullscreen}},
+		{"rotate_cw", "next_camera", Binding{Control: Control{Rotate, 1}, Action: ActionNextCamera}},
+		{"rotate_ccw", "prev_camera", Binding{Control: Control{Rotate, -1}, Action: ActionPrevCamera}},
+	}
+	for _, tt := range tests {
+		got, err := ParseBinding(tt.key, tt.spec)
+		if err != nil || got != tt.want {
+			t.Errorf("ParseBinding(%q, %q) = %+v, %v; want %+v", tt.key, tt.spec, got, err, tt.want)
+		}
+	}
+
+	for _, bad := range [][2]string{
+		{"gpio", "snapshot"},
+		{"gpio-1", "snapshot"},
+		{"key0", "snapshot"},
+		{"button1", "snapshot"},
+		{"gpio17", "reboot"},
+		{"gpio17", ""},
+		{"gpio17", "snapshot, pull_up"},
+		{"key28", "snapshot, active_high"},
+	} {
+		if b, err := ParseBinding(bad[0], bad[1]); err == nil {
+			t.Errorf("ParseBinding(%q, %q) = %+v, want error", bad[0], bad[1], b)
+		}
+	}
+}
+
+func TestControlString(t *testing.T) {
+	for _, key := range []string{"gpio17", "key28", "rotate_cw", "rotate_ccw"} {
+		b, err := ParseBinding(key, "snapshot")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := b.Control.String(); got != key {
+			t.Errorf("String() = %q, want %q", got, key)
+		}
+	}
+}
+
+func TestMapper_Debounce(t *testing.T) {
+	m := NewMapper([]Binding{
+		{Control: Control{GPIO, 17}, Action: ActionSnapshot},
+		{Control: Control{Rotate, 1}, Action: ActionNextCamera},
+	}, 50*time.Millisecond)
+	t0 := time.Now()
+
+	if action, ok := m.Action(Control{GPIO, 17}, t0); !ok || action != ActionSnapshot {
+		t.Fatalf("first press = %q, %v", action, ok)
+	}
+	if _, ok := m.Action(Control{GPIO, 17}, t0.Add(10*time.Millisecond)); ok {
+		t.Error("bounce 10ms later should be ignored")
+	}
+	if _, ok := m.Action(Control{GPIO, 17}, t0.Add(60*time.Millisecond)); !ok {
+		t.Error("press 60ms later should count")
+	}
+	// Encoder steps come from a debouncing driver and may be quick
+	for i := 0; i < 3; i++ {
+		if _, ok := m.Action(Control{Rotate, 1}, t0); !ok {
+			t.Errorf("encoder step %d ignored", i)
+		}
+	}
+	if _, ok := m.Action(Control{GPIO, 18}, t0); ok {
+		t.Error("unbound control should have no action")
+	}
+}
+
+func TestDecodeEvdev(t *testing.T) {
+	tests := []struct {
+		typ, code uint16
+		value     int32
+		want      []Control
+	}{
+		{evKey, 28, 1, []Control{{Key, 28}}},
+		{evKey, 28, 0, nil}, // Release
+		{evKey, 28, 2, nil}, // Autorepeat
+		{evRel, 0, 1, []Control{{Rotate, 1}}},
+		{evRel, 8, -2, []Control{{Rotate, -1}, {Rotate, -1}}},
+		{evRel, 0, 0, []Control{}},
+		{0x00, 0, 0, nil}, // EV_SYN
+	}
+	for _, tt := range tests {
+		if got := decodeEvdev(tt.typ, tt.code, tt.value); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("decodeEvdev(%d, %d, %d) = %v, want %v", tt.typ, tt.code, tt.value, got, tt.want)
+		}
+	}
+	if got := decodeEvdev(evRel, 0, -1<<31); len(got) != maxSteps {
+		t.Errorf("huge step gave %d controls, want %d", len(got), maxSteps)
+	}
+}
+
+type fakeDevice struct {
+	reads [][]Control
+}
+
+func (d *fakeDevice) Read() ([]Control, error) {
+	if len(d.reads) == 0 {
+		return nil, errors.New("closed")
+	}
+	r := d.reads[0]
+	d.reads = d.reads[1:]
+	return r, nil
+}
+
+func (d *fakeDevice) Close() error { return nil }
+
+func TestMapper_Run(t *testing.T) {
+	m := NewMapper([]Binding{
+		{Control: Control{Rotate, 1}, Action: ActionNextCamera},
+		{Control: Control{Key, 28}, Action: ActionFullscreen},
+	}, 0)
+	dev := &fakeDevice{reads: [][]Control{
+		{{Rotate, 1}, {Rotate, 1}},
+		nil,
+		{{Key, 30}, {Key, 28}},
+	}}
+	var got []string
+	m.Run("test", dev, func(action string) { got = append(got, action) })
+
+	want := []string{ActionNextCamera, ActionNextCamera, ActionFullscreen}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("actions = %v, want %v", got, want)
+	}
+}
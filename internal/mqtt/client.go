@@ -0,0 +1,433 @@
+// Package mqtt is a minimal MQTT 3.1.1 client used to publish dashboard
+// status and receive remote commands.
+//
+// Only what the dashboard needs is implemented: QoS 0 publish, subscribe
+// with wildcard filters, keepalive pings and automatic reconnect.
+// No external dependencies.
+package mqtt
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Options configures a Client.
+type Options struct {
+	Broker    string // host:port
+	ClientID  string
+	Username  string
+	Password  string
+	KeepAlive time.Duration
+}
+
+// Handler is called for every message received on a subscribed topic.
+// Handlers run on the client's read goroutine and must not block.
+type Handler func(topic string, payload []byte)
+
+type subscription struct {
+	filter  string
+	handler Handler
+}
+
+// Client is a reconnecting MQTT client.
+type Client struct {
+	opts Options
+
+	conn      net.Conn
+	writeMu   sync.Mutex // Serializes packet writes and protects conn
+	connected atomic.Bool
+
+	subsMu sync.RWMutex
+	subs   []subscription
+
+	packetID atomic.Uint32
+	running  atomic.Bool
+	stopCh   chan struct{}
+	wg       sync.WaitGroup
+}
+
+// NewClient creates a client. Call Subscribe before Start so the
+// subscriptions are sent on the first connect.
+func NewClient(opts Options) *Client {
+	if opts.KeepAlive <= 0 {
+		opts.KeepAlive = 30 * time.Second
+	}
+	if opts.ClientID == "" {
+		opts.ClientID = "camera-dashboard"
+	}
+	return &Client{
+		opts:   opts,
+		stopCh: make(chan struct{}),
+	}
+}
+
+// Subscribe registers a handler for a topic filter ("+" and "#" wildcards
+// are supported). If already connected, the subscription is sent immediately.
+func (c *Client) Subscribe(filter string, handler Handler) {
+	c.subsMu.Lock()
+	c.subs = append(c.subs, subscription{filter: filter, handler: handler})
+	c.subsMu.Unlock()
+
+	if c.connected.Load() {
+		if err := c.sendSubscribe([]string{filter}); err != nil {
+			log.Printf("[MQTT] Subscribe %s failed: %v", filter, err)
+		}
+	}
+}
+
+// Start connects in the background and keeps reconnecting until Stop.
+func (c *Client) Start() {
+	if c.running.Swap(true) {
+		return
+	}
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		c.run()
+	}()
+}
+
+// Stop disconnects and waits for the background goroutine to exit.
+func (c *Client) Stop() {
+	if !c.running.Swap(false) {
+		return
+	}
+	close(c.stopCh)
+
+	c.writeMu.Lock()
+	if c.conn != nil {
+		c.conn.SetWriteDeadline(time.Now().Add(time.Second))
+		c.conn.Write([]byte{packetDisconnect << 4, 0})
+		c.conn.Close()
+	}
+	c.writeMu.Unlock()
+
+	c.wg.Wait()
+}
+
+// IsConnected reports whether the client currently has a broker session.
+func (c *Client) IsConnected() bool {
+	return c.connected.Load()
+}
+
+// Publish sends a QoS 0 message. Returns ErrNotConnected while the
+// client is (re)connecting; messages are not queued.
+func (c *Client) Publish(topic string, payload []byte, retain bool) error {
+	if !c.connected.Load() {
+		return ErrNotConnected
+	}
+	return c.writePacket(encodePublish(topic, payload, retain))
+}
+
+// run is the reconnect loop.
+func (c *Client) run() {
+	backoff := time.Second
+	for c.running.Load() {
+		err := c.connectAndServe()
+		c.connected.Store(false)
+		if !c.running.Load() {
+			return
+		}
+		log.Printf("[MQTT] Connection to %s lost: %v (retry in %s)", c.opts.Broker, err, backoff)
+
+		select {
+		case <-c.stopCh:
+			return
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+		if backoff > 30*time.Second {
+			backoff = 30 * time.Second
+		}
+	}
+}
+
+// connectAndServe opens one broker session and blocks until it fails.
+func (c *Client) connectAndServe() error {
+	conn, err := net.DialTimeout("tcp", c.opts.Broker, 5*time.Second)
+	if err != nil {
+		return err
+	}
+
+	c.writeMu.Lock()
+	c.conn = conn
+	c.writeMu.Unlock()
+	defer func() {
+		c.writeMu.Lock()
+		conn.Close()
+		c.conn = nil
+		c.writeMu.Unlock()
+	}()
+
+	reader := bufio.NewReader(conn)
+
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+	if err := c.writePacket(encodeConnect(c.opts)); err != nil {
+		return err
+	}
+	pktType, body, err := readPacket(reader)
+	if err != nil {
+		return err
+	}
+	if pktType != packetConnack || len(body) < 2 {
+		return fmt.Errorf("mqtt: expected CONNACK, got packet type %d", pktType)
+	}
+	if body[1] != 0 {
+		return fmt.Errorf("mqtt: connection refused (code %d)", body[1])
+	}
+	conn.SetDeadline(time.Time{})
+
+	c.connected.Store(true)
+	log.Printf("[MQTT] Connected to %s as %s", c.opts.Broker, c.opts.ClientID)
+
+	c.subsMu.RLock()
+	filters := make([]string, 0, len(c.subs))
+	for _, s := range c.subs {
+		filters = append(filters, s.filter)
+	}
+	c.subsMu.RUnlock()
+	if len(filters) > 0 {
+		if err := c.sendSubscribe(filters); err != nil {
+			return err
+		}
+	}
+
+	// Keepalive pings; the read loop below notices a dead connection
+	// through the read deadline (1.5x keepalive, as the spec allows).
+	pingDone := make(chan struct{})
+	defer close(pingDone)
+	go func() {
+		ticker := time.NewTicker(c.opts.KeepAlive / 2)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-pingDone:
+				return
+			case <-c.stopCh:
+				return
+			case <-ticker.C:
+				c.writePacket([]byte{packetPingreq << 4, 0})
+			}
+		}
+	}()
+
+	for {
+		conn.SetReadDeadline(time.Now().Add(c.opts.KeepAlive * 3 / 2))
+		pktType, body, err := readPacket(reader)
+		if err != nil {
+			return err
+		}
+		if pktType == packetPublish {
+			topic, payload, err := decodePublish(body)
+			if err != nil {
+				log.Printf("[MQTT] Dropping malformed PUBLISH: %v", err)
+				continue
+			}
+			c.dispatch(topic, payload)
+		}
+		// SUBACK / PINGRESP need no handling beyond resetting the deadline.
+	}
+}
+
+// dispatch calls every handler whose filter matches topic. The handlers
+// are collected under subsMu and called after it is released, so a
+// handler may call Subscribe.
+func (c *Client) dispatch(topic string, payload []byte) {
+	var handlers []Handler
+	c.subsMu.RLock()
+	for _, s := range c.subs {
+		if topicMatches(s.filter, topic) && s.handler != nil {
+			handlers = append(handlers, s.handler)
+		}
+	}
+	c.subsMu.RUnlock()
+	for _, h := range handlers {
+		h(topic, payload)
+	}
+}
+
+func (c *Client) sendSubscribe(filters []string) error {
+	id := uint16(c.packetID.Add(1))
+	if id == 0 {
+		id = uint16(c.packetID.Add(1))
+	}
+	return c.writePacket(encodeSubscribe(id, filters))
+}
+
+func (c *Client) writePacket(pkt []byte) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	if c.conn == nil {
+		return ErrNotConnected
+	}
+	c.conn.SetWriteDeadline(time.Now().Add(5 * time.Second))
+	_, err := c.conn.Write(pkt)
+	return err
+}
+
+// =============================================================================
+// Packet encoding (MQTT 3.1.1, QoS 0)
+// =============================================================================
+
+const (
+	packetConnect    = 1
+	packetConnack    = 2
+	packetPublish    = 3
+	packetSubscribe  = 8
+	packetSuback     = 9
+	packetPingreq    = 12
+	packetPingresp   = 13
+	packetDisconnect = 14
+)
+
+// maxPacketSize bounds incoming packets; commands are tiny.
+const maxPacketSize = 256 * 1024
+
+// encodeRemainingLength encodes n using the MQTT variable-length scheme.
+func encodeRemainingLength(n int) []byte {
+	var out []byte
+	for {
+		b := byte(n % 128)
+		n /= 128
+		if n > 0 {
+			b |= 0x80
+		}
+		out = append(out, b)
+		if n == 0 {
+			return out
+		}
+	}
+}
+
+func appendString(buf []byte, s string) []byte {
+	buf = append(buf, byte(len(s)>>8), byte(len(s)))
+	return append(buf, s...)
+}
+
+func buildPacket(header byte, body []byte) []byte {
+	pkt := []byte{header}
+	pkt = append(pkt, encodeRemainingLength(len(body))...)
+	return append(pkt, body...)
+}
+
+func encodeConnect(opts Options) []byte {
+	flags := byte(0x02) // Clean session
+	if opts.Username != "" {
+		flags |= 0x80
+		if opts.Password != "" {
+			flags |= 0x40
+		}
+	}
+	keepAlive := int(opts.KeepAlive / time.Second)
+	if keepAlive > 0xFFFF {
+		keepAlive = 0xFFFF
+	}
+
+	var body []byte
+	body = appendString(body, "MQTT")
+	body = append(body, 4, flags, byte(keepAlive>>8), byte(keepAlive))
+	body = appendString(body, opts.ClientID)
+	if opts.Username != "" {
+		body = appendString(body, opts.Username)
+		if opts.Password != "" {
+			body = appendString(body, opts.Password)
+		}
+	}
+	return buildPacket(packetConnect<<4, body)
+}
+
+func encodePublish(topic string, payload []byte, retain bool) []byte {
+	header := byte(packetPublish << 4)
+	if retain {
+		header |= 0x01
+	}
+	body := appendString(nil, topic)
+	body = append(body, payload...)
+	return buildPacket(header, body)
+}
+
+func encodeSubscribe(packetID uint16, filters []string) []byte {
+	body := []byte{byte(packetID >> 8), byte(packetID)}
+	for _, f := range filters {
+		body = appendString(body, f)
+		body = append(body, 0) // Requested QoS 0
+	}
+	return buildPacket(packetSubscribe<<4|0x02, body)
+}
+
+// readPacket reads one packet and returns its type and body.
+func readPacket(r *bufio.Reader) (byte, []byte, error) {
+	header, err := r.ReadByte()
+	if err != nil {
+		return 0, nil, err
+	}
+
+	length := 0
+	multiplier := 1
+	for i := 0; ; i++ {
+		if i >= 4 {
+			return 0, nil, fmt.Errorf("mqtt: malformed remaining length")
+		}
+		b, err := r.ReadByte()
+		if err != nil {
+			return 0, nil, err
+		}
+		length += int(b&0x7F) * multiplier
+		if b&0x80 == 0 {
+			break
+		}
+		multiplier *= 128
+	}
+	if length > maxPacketSize {
+		return 0, nil, fmt.Errorf("mqtt: packet too large (%d bytes)", length)
+	}
+
+	body := make([]byte, length)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return 0, nil, err
+	}
+	return header >> 4, body, nil
+}
+
+// decodePublish extracts topic and payload from a PUBLISH body.
+// Brokers only deliver at the subscribed QoS (0), so there is no packet ID.
+func decodePublish(body []byte) (string, []byte, error) {
+	if len(body) < 2 {
+		return "", nil, fmt.Errorf("mqtt: short PUBLISH")
+	}
+	n := int(body[0])<<8 | int(body[1])
+	if len(body) < 2+n {
+		return "", nil, fmt.Errorf("mqtt: truncated topic")
+	}
+	return string(body[2 : 2+n]), body[2+n:], nil
+}
+
+// topicMatches reports whether topic matches a subscription filter.
+func topicMatches(filter, topic string) bool {
+	fParts := strings.Split(filter, "/")
+	tParts := strings.Split(topic, "/")
+	for i, f := range fParts {
+		if f == "#" {
+			return true
+		}
+		if i >= len(tParts) {
+			return false
+		}
+		if f != "+" && f != tParts[i] {
+			return false
+		}
+	}
+	return len(fParts) == len(tParts)
+}
+
+// Errors
+var (
+	ErrNotConnected = fmt.Errorf("mqtt: not connected")
+)
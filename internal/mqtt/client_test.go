@@ -0,0 +1,199 @@
+package mqtt
+
+import (
+	"bufio"
+	"bytes"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestEncodeRemainingLength(t *testing.T) {
+	tests := []struct {
+		n    int
+		want []byte
+	}{
+		{0, []byte{0x00}},
+		{127, []byte{0x7F}},
+		{128, []byte{0x80, 0x01}},
+		{16383, []byte{0xFF, 0x7F}},
+		{16384, []byte{0x80, 0x80, 0x01}},
+	}
+	for _, tc := range tests {
+		got := encodeRemainingLength(tc.n)
+		if !bytes.Equal(got, tc.want) {
+			t.Errorf("encodeRemainingLength(%d) = %x, want %x", tc.n, got, tc.want)
+		}
+	}
+}
+
+func TestEncodeConnect(t *testing.T) {
+	pkt := encodeConnect(Options{ClientID: "cd", Username: "u", Password: "p", KeepAlive: 30 * time.Second})
+	want := []byte{
+		0x10, 20,
+		0, 4, 'M', 'Q', 'T', 'T',
+		4,     // Protocol level
+		0xC2,  // Username + password + clean session
+		0, 30, // Keepalive
+		0, 2, 'c', 'd',
+		0, 1, 'u',
+		0, 1, 'p',
+	}
+	if !bytes.Equal(pkt, want) {
+		t.Errorf("encodeConnect = %x, want %x", pkt, want)
+	}
+}
+
+func TestPublishRoundTrip(t *testing.T) {
+	pkt := encodePublish("a/b", []byte("on"), true)
+	if pkt[0] != 0x31 {
+		t.Errorf("header = %#x, want 0x31 (PUBLISH, retain)", pkt[0])
+	}
+
+	pktType, body, err := readPacket(bufio.NewReader(bytes.NewReader(pkt)))
+	if err != nil {
+		t.Fatalf("readPacket error: %v", err)
+	}
+	if pktType != packetPublish {
+		t.Fatalf("type = %d, want %d", pktType, packetPublish)
+	}
+	topic, payload, err := decodePublish(body)
+	if err != nil {
+		t.Fatalf("decodePublish error: %v", err)
+	}
+	if topic != "a/b" || string(payload) != "on" {
+		t.Errorf("decoded (%q, %q), want (\"a/b\", \"on\")", topic, payload)
+	}
+}
+
+func TestDecodePublish_Truncated(t *testing.T) {
+	if _, _, err := decodePublish([]byte{0, 5, 'a'}); err == nil {
+		t.Error("expected error for truncated topic")
+	}
+}
+
+func TestTopicMatches(t *testing.T) {
+	tests := []struct {
+		filter, topic string
+		want          bool
+	}{
+		{"a/b", "a/b", true},
+		{"a/b", "a/c", false},
+		{"a/+", "a/b", true},
+		{"a/+", "a/b/c", false},
+		{"a/#", "a/b/c", true},
+		{"a/#", "a", true},
+		{"#", "x/y", true},
+		{"a/b/c", "a/b", false},
+	}
+	for _, tc := range tests {
+		if got := topicMatches(tc.filter, tc.topic); got != tc.want {
+			t.Errorf("topicMatches(%q, %q) = %v, want %v", tc.filter, tc.topic, got, tc.want)
+		}
+	}
+}
+
+func TestClient_PublishAndReceive(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer ln.Close()
+
+	published := make(chan string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+
+		// CONNECT -> CONNACK
+		if typ, _, err := readPacket(r); err != nil || typ != packetConnect {
+			return
+		}
+		conn.Write([]byte{packetConnack << 4, 2, 0, 0})
+
+		// SUBSCRIBE -> SUBACK, then deliver a command
+		if typ, _, err := readPacket(r); err != nil || typ != packetSubscribe {
+			return
+		}
+		conn.Write([]byte{packetSuback << 4, 3, 0, 1, 0})
+		conn.Write(encodePublish("dash/cmd/nightmode", []byte("on"), false))
+
+		for {
+			typ, body, err := readPacket(r)
+			if err != nil {
+				return
+			}
+			if typ == packetPublish {
+				topic, payload, _ := decodePublish(body)
+				published <- topic + "=" + string(payload)
+			}
+		}
+	}()
+
+	c := NewClient(Options{Broker: ln.Addr().String(), ClientID: "test"})
+	received := make(chan string, 1)
+	c.Subscribe("dash/cmd/#", func(topic string, payload []byte) {
+		received <- topic + "=" + string(payload)
+	})
+	c.Start()
+	defer c.Stop()
+
+	select {
+	case got := <-received:
+		if got != "dash/cmd/nightmode=on" {
+			t.Errorf("received %q", got)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("timed out waiting for command")
+	}
+
+	if err := c.Publish("dash/health", []byte("ok"), false); err != nil {
+		t.Fatalf("Publish error: %v", err)
+	}
+	select {
+	case got := <-published:
+		if got != "dash/health=ok" {
+			t.Errorf("broker got %q", got)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("timed out waiting for publish")
+	}
+}
+
+func TestClient_PublishWhileDisconnected(t *testing.T) {
+	c := NewClient(Options{Broker: "127.0.0.1:1"})
+	if err := c.Publish("x", nil, false); err != ErrNotConnected {
+		t.Errorf("Publish error = %v, want ErrNotConnected", err)
+	}
+}
+
+// A handler that subscribes must not deadlock on the subscription lock.
+func TestClient_DispatchHandlerSubscribes(t *testing.T) {
+	c := NewClient(Options{Broker: "127.0.0.1:1"})
+	var got []string
+	c.Subscribe("cmd/+", func(topic string, payload []byte) {
+		got = append(got, topic)
+		c.Subscribe("cmd/extra", func(topic string, payload []byte) { got = append(got, "extra") })
+	})
+
+	done := make(chan struct{})
+	go func() {
+		c.dispatch("cmd/snapshot", nil)
+		c.dispatch("cmd/extra", nil)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("dispatch deadlocked on a handler calling Subscribe")
+	}
+	// cmd/extra matches cmd/+ (subscribing again) and the new handler
+	if want := []string{"cmd/snapshot", "cmd/extra", "extra"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("handlers called for %v, want %v", got, want)
+	}
+}
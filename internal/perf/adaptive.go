@@ -2,7 +2,9 @@ package perf
 
 import (
 	"camera-dashboard-go/internal/camera"
+	"camera-dashboard-go/internal/clock"
 	"camera-dashboard-go/internal/config"
+	"fmt"
 	"log"
 	"sync"
 	"sync/atomic"
@@ -17,16 +19,6 @@ const (
 	StateEmergency         // Critical thermal - minimum FPS
 )
 
-// Thermal thresholds for Pi 4/5 (designed to run warm)
-// Pi 5 throttles at 85°C, so we have headroom up to 83°C
-const (
-	TempIdeal    = 72.0 // Below this: can try increasing FPS
-	TempComfort  = 78.0 // Sweet spot ceiling - Pi runs fine here
-	TempWarm     = 82.0 // Start being cautious (still safe)
-	TempHot      = 84.0 // Need to reduce FPS (approaching throttle)
-	TempCritical = 86.0 // Emergency minimum FPS (throttling imminent)
-)
-
 // Load thresholds on normalized load ratio (0.0-1.0).
 // Monitor normalizes 1-minute load average by CPU count.
 const (
@@ -46,9 +38,10 @@ const (
 // machine (Probing -> Stable -> Recovering -> Emergency) to find and
 // maintain the highest sustainable FPS. When disabled, it runs at fixed FPS.
 type SmartController struct {
-	monitor *Monitor
+	monitor SystemMonitor
 	manager *camera.Manager
-	cfg     *config.Config
+	cfg     *config.Config // The controller's own copy (see SetConfig)
+	clock   clock.Clock    // clock.System, or the simulated clock (see scenario.go)
 
 	// FPS control
 	currentFPS   int
@@ -58,6 +51,11 @@ type SmartController struct {
 
 	// Dynamic FPS mode
 	dynamicEnabled bool
+	thermal        config.ThermalThresholds // Per-board, from [performance]
+
+	// Adaptive resolution tier (see resolution.go)
+	res        resolutionScaler
+	numCameras int
 
 	// State machine
 	state          atomic.Int32
@@ -76,11 +74,16 @@ type SmartController struct {
 	// Stats
 	stableSeconds atomic.Int64
 	adjustCount   int
+	stability     *stabilityTracker // FPS change history (see stability.go)
+	failures      failureTracker    // FPS values that keep failing (see damping.go)
+
+	onFPSChange func(oldFPS, newFPS int, reason string) // See SetOnFPSChange
 
 	// Concurrency
 	mutex   sync.RWMutex
 	running atomic.Bool
 	stopCh  chan struct{}
+	wg      sync.WaitGroup // controlLoop; Stop waits for it
 }
 
 // NewSmartController creates a performance controller.
@@ -88,10 +91,19 @@ type SmartController struct {
 // If cfg.DynamicFPSEnabled is true, the controller actively adapts FPS
 // based on CPU temperature and load.
 func NewSmartController(manager *camera.Manager, cfg *config.Config) *SmartController {
+	return NewSmartControllerWithMonitor(manager, cfg, NewMonitor())
+}
+
+// NewSmartControllerWithMonitor creates a performance controller that
+// reads temperature and load from monitor instead of /proc and /sys,
+// e.g. a scenario replay in tests.
+func NewSmartControllerWithMonitor(manager *camera.Manager, cfg *config.Config, monitor SystemMonitor) *SmartController {
 	if cfg == nil {
 		cfg = config.DefaultConfig()
 		cfg.DynamicFPSEnabled = false // Safe default without config
 	}
+	own := *cfg // Config reloads write the caller's cfg while we tick
+	cfg = &own
 
 	captureFPS := cfg.CaptureFPS
 	minFPS := cfg.MinDynamicFPS
@@ -121,13 +133,27 @@ func NewSmartController(manager *camera.Manager, cfg *config.Config) *SmartContr
 	}
 
 	sc := &SmartController{
-		monitor:        NewMonitor(),
+		monitor:        monitor,
+		clock:          clock.System,
 		manager:        manager,
 		cfg:            cfg,
 		dynamicEnabled: cfg.DynamicFPSEnabled,
+		thermal:        cfg.ThermalLimits(),
+		numCameras:     numCameras,
 		tempHistory:    make([]float64, 0, 10),
 		stopCh:         make(chan struct{}),
 	}
+	if cfg.DynamicFPSEnabled && len(cfg.ResolutionTiers) > 0 {
+		sc.res = resolutionScaler{
+			maxTier:  len(cfg.ResolutionTiers),
+			stepDown: time.Duration(cfg.ResolutionStepDownSec * float64(time.Second)),
+			stepUp:   time.Duration(cfg.ResolutionStepUpSec * float64(time.Second)),
+			hotC:     cfg.CPUTempThresholdC,
+			coolC:    cfg.CPUTempThresholdC - cfg.ResolutionHysteresisC,
+		}
+		log.Printf("[SmartCtrl] Adaptive resolution: %d lower tiers %v (down after %.0fs >= %.0f°C at min FPS, up after %.0fs <= %.0f°C)",
+			sc.res.maxTier, cfg.ResolutionTiers, cfg.ResolutionStepDownSec, sc.res.hotC, cfg.ResolutionStepUpSec, sc.res.coolC)
+	}
 
 	if cfg.DynamicFPSEnabled {
 		// Dynamic mode: min and max differ, start with probing
@@ -137,6 +163,8 @@ func NewSmartController(manager *camera.Manager, cfg *config.Config) *SmartContr
 		sc.sweetSpotFPS = captureFPS
 		log.Printf("[SmartCtrl] Config: %dx%d @ %d FPS for %d cameras (dynamic adaptation enabled, min=%d)",
 			cfg.CaptureWidth, cfg.CaptureHeight, captureFPS, numCameras, minFPS)
+		log.Printf("[SmartCtrl] Thermal thresholds (%s): ideal %.0f, comfort %.0f, warm %.0f, hot %.0f, critical %.0f°C",
+			cfg.ThermalProfile, sc.thermal.IdealC, sc.thermal.ComfortC, sc.thermal.WarmC, sc.thermal.HotC, sc.thermal.CriticalC)
 	} else {
 		// Fixed mode: no adaptation
 		sc.minFPS = captureFPS
@@ -146,6 +174,7 @@ func NewSmartController(manager *camera.Manager, cfg *config.Config) *SmartContr
 		log.Printf("[SmartCtrl] Config: %dx%d @ %d FPS for %d cameras (fixed, no adaptation)",
 			cfg.CaptureWidth, cfg.CaptureHeight, captureFPS, numCameras)
 	}
+	sc.stability = newStabilityTracker(sc.now(), sc.currentFPS)
 
 	return sc
 }
@@ -156,8 +185,21 @@ func (sc *SmartController) Start() {
 		return
 	}
 
-	sc.stateEnterTime = time.Now()
-	sc.lastChange = time.Now()
+	sc.begin()
+	sc.wg.Add(1)
+	go func() {
+		defer sc.wg.Done()
+		sc.controlLoop()
+	}()
+}
+
+// begin enters the initial state and applies the starting FPS. It locks
+// like tick: the controller is already visible to GetCurrentFPS callers.
+func (sc *SmartController) begin() {
+	sc.mutex.Lock()
+	defer sc.mutex.Unlock()
+	sc.stateEnterTime = sc.now()
+	sc.lastChange = sc.now()
 
 	if sc.dynamicEnabled {
 		sc.state.Store(StateProbing)
@@ -169,26 +211,30 @@ func (sc *SmartController) Start() {
 
 	// Apply initial FPS
 	sc.applyFPS(sc.currentFPS)
-
-	go sc.controlLoop()
+	sc.stability = newStabilityTracker(sc.now(), sc.currentFPS)
 }
 
-// Stop halts the controller
+// Stop halts the controller and waits for the control loop to return,
+// so no FPS change is applied after it.
 func (sc *SmartController) Stop() {
 	if !sc.running.Swap(false) {
 		return
 	}
 	close(sc.stopCh)
+	sc.wg.Wait()
+
+	if sc.dynamicEnabled {
+		for _, line := range sc.StabilityReport().Lines() {
+			log.Printf("[SmartCtrl] %s", line)
+		}
+	}
 }
 
 // controlLoop runs the main control tick
 func (sc *SmartController) controlLoop() {
-	// Use config's perf check interval, defaulting to 1 second
-	interval := time.Duration(sc.cfg.PerfCheckIntervalMS) * time.Millisecond
-	if interval < 250*time.Millisecond {
-		interval = 250 * time.Millisecond
-	}
-
+	sc.mutex.RLock()
+	interval := checkInterval(sc.cfg)
+	sc.mutex.RUnlock()
 	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
 
@@ -207,6 +253,16 @@ func (sc *SmartController) controlLoop() {
 	}
 }
 
+// checkInterval is the control tick: the config's perf check interval,
+// at least 250ms.
+func checkInterval(cfg *config.Config) time.Duration {
+	interval := time.Duration(cfg.PerfCheckIntervalMS) * time.Millisecond
+	if interval < 250*time.Millisecond {
+		interval = 250 * time.Millisecond
+	}
+	return interval
+}
+
 // tick performs one monitoring + adaptation cycle
 func (sc *SmartController) tick() {
 	if err := sc.monitor.UpdateStats(); err != nil {
@@ -223,7 +279,7 @@ func (sc *SmartController) tick() {
 
 	if !sc.dynamicEnabled {
 		// Fixed mode: monitor only, warn on critical temps
-		if temp >= TempCritical {
+		if temp >= sc.thermal.CriticalC {
 			log.Printf("[SmartCtrl] WARNING: Temperature critical (%.1f°C) - consider improving ventilation", temp)
 		}
 		if sc.state.Load() != StateStable {
@@ -245,6 +301,20 @@ func (sc *SmartController) tick() {
 	case StateEmergency:
 		sc.handleEmergency(temp)
 	}
+
+	if tier, changed := sc.res.update(temp, sc.currentFPS <= sc.minFPS, sc.now()); changed {
+		sc.applyResolutionTier(tier, temp)
+	}
+}
+
+// applyResolutionTier restarts the workers at tier's resolution. The
+// restarts take a while, so they run off the control loop.
+func (sc *SmartController) applyResolutionTier(tier int, temp float64) {
+	w, h, _, _ := sc.cfg.ChooseProfileTier(sc.numCameras, tier)
+	log.Printf("[SmartCtrl] Resolution tier %d/%d: %dx%d @ %.1f°C", tier, sc.res.maxTier, w, h, temp)
+	if sc.manager != nil {
+		go sc.manager.SetResolution(w, h)
+	}
 }
 
 // updateTempTrend tracks temperature changes
@@ -263,11 +333,12 @@ func (sc *SmartController) updateTempTrend(temp float64) {
 // handleEmergency - at minimum FPS, waiting for cooldown
 func (sc *SmartController) handleEmergency(temp float64) {
 	if sc.currentFPS != sc.minFPS {
+		sc.stability.record(sc.now(), sc.minFPS, ReasonEmergency)
 		sc.applyFPS(sc.minFPS)
 	}
 
 	// Exit emergency when cooled down
-	if temp < TempWarm && sc.tempTrend <= 0 && time.Since(sc.stateEnterTime) > 10*time.Second {
+	if temp < sc.thermal.WarmC && sc.tempTrend <= 0 && sc.now().Sub(sc.stateEnterTime) > 10*time.Second {
 		log.Printf("[SmartCtrl] Exiting emergency - temp: %.1f°C", temp)
 		sc.enterState(StateRecovering)
 	}
@@ -275,10 +346,10 @@ func (sc *SmartController) handleEmergency(temp float64) {
 
 // handleProbing - finding the max sustainable FPS
 func (sc *SmartController) handleProbing(temp, load float64) {
-	timeSinceChange := time.Since(sc.lastChange)
+	timeSinceChange := sc.now().Sub(sc.lastChange)
 
 	// Emergency check
-	if temp >= TempCritical {
+	if temp >= sc.thermal.CriticalC {
 		log.Printf("[SmartCtrl] EMERGENCY - temp: %.1f°C", temp)
 		sc.enterState(StateEmergency)
 		return
@@ -293,7 +364,7 @@ func (sc *SmartController) handleProbing(temp, load float64) {
 	isLoadOK := load < LoadHigh
 
 	// Check sustainability with thermal thresholds
-	isSustainable := (temp < TempWarm) || (temp < TempHot && sc.tempTrend <= 0)
+	isSustainable := (temp < sc.thermal.WarmC) || (temp < sc.thermal.HotC && sc.tempTrend <= 0)
 
 	if isSustainable && isLoadOK && !isUnderStress {
 		sc.stabilityCount++
@@ -314,9 +385,9 @@ func (sc *SmartController) handleProbing(temp, load float64) {
 			}
 
 			// Try higher FPS if cooling and stable
-			if sc.currentFPS < sc.maxFPS && temp < TempComfort &&
-				sc.tempTrend < 0 && timeSinceChange > 15*time.Second {
-				sc.changeFPS(sc.currentFPS + sc.cfg.UIFPSStep)
+			if sc.currentFPS < sc.maxFPS && temp < sc.thermal.ComfortC &&
+				sc.tempTrend < 0 && timeSinceChange > 15*time.Second && sc.dwelled() {
+				sc.changeFPS(sc.currentFPS+sc.cfg.UIFPSStep, ReasonProbe)
 			}
 		}
 	} else {
@@ -325,14 +396,14 @@ func (sc *SmartController) handleProbing(temp, load float64) {
 
 		// Use stress hold count from config before reducing
 		if sc.stressCount >= sc.cfg.StressHoldCount {
-			shouldReduce := temp >= TempHot || (temp >= TempWarm && sc.tempTrend > 0.3) || load >= LoadHigh
+			shouldReduce := temp >= sc.thermal.HotC || (temp >= sc.thermal.WarmC && sc.tempTrend > 0.3) || load >= LoadHigh
 
-			if shouldReduce && timeSinceChange > 5*time.Second {
-				newFPS := sc.currentFPS - 3
+			if shouldReduce && timeSinceChange > 5*time.Second && (sc.urgent(temp, load) || sc.dwelled()) {
+				newFPS := sc.currentFPS - sc.cfg.FPSStepDown
 				if newFPS < sc.minFPS {
 					newFPS = sc.minFPS
 				}
-				sc.changeFPS(newFPS)
+				sc.changeFPS(newFPS, sc.stressReason(temp))
 
 				// Update sweet spot if we had to go lower
 				if newFPS < sc.sweetSpotFPS {
@@ -349,7 +420,7 @@ func (sc *SmartController) handleStable(temp, load float64) {
 	sc.stableSeconds.Add(1)
 
 	// Check for emergency
-	if temp >= TempCritical {
+	if temp >= sc.thermal.CriticalC {
 		log.Printf("[SmartCtrl] EMERGENCY in stable - temp: %.1f°C", temp)
 		sc.enterState(StateEmergency)
 		return
@@ -361,17 +432,17 @@ func (sc *SmartController) handleStable(temp, load float64) {
 	isUnderStress := temp >= cpuTempThresh || load >= cpuLoadThresh
 
 	// Need to reduce?
-	if temp >= TempHot || (temp >= TempWarm && sc.tempTrend > 0.5) || load >= LoadHigh || isUnderStress {
+	if temp >= sc.thermal.HotC || (temp >= sc.thermal.WarmC && sc.tempTrend > 0.5) || load >= LoadHigh || isUnderStress {
 		sc.stressCount++
 
-		if sc.stressCount >= sc.cfg.StressHoldCount {
+		if sc.stressCount >= sc.cfg.StressHoldCount && (sc.urgent(temp, load) || sc.dwelled()) {
 			log.Printf("[SmartCtrl] Reducing FPS - temp: %.1f°C, load: %.2f (stress count: %d)",
 				temp, load, sc.stressCount)
-			newFPS := sc.currentFPS - sc.cfg.UIFPSStep
+			newFPS := sc.currentFPS - sc.cfg.FPSStepDown
 			if newFPS < sc.minFPS {
 				newFPS = sc.minFPS
 			}
-			sc.changeFPS(newFPS)
+			sc.changeFPS(newFPS, sc.stressReason(temp))
 
 			if newFPS < sc.sweetSpotFPS {
 				sc.sweetSpotFPS = newFPS
@@ -388,10 +459,10 @@ func (sc *SmartController) handleStable(temp, load float64) {
 	// Can we try higher? (after 30+ seconds stable, cooling, well under threshold)
 	stableTime := sc.stableSeconds.Load()
 	if stableTime > 30 && sc.currentFPS < sc.maxFPS &&
-		temp < TempIdeal && sc.tempTrend < 0 && load < LoadIdeal &&
-		sc.recoverCount >= sc.cfg.RecoverHoldCount {
+		temp < sc.thermal.IdealC && sc.tempTrend < 0 && load < LoadIdeal &&
+		sc.recoverCount >= sc.cfg.RecoverHoldCount && sc.dwelled() {
 		log.Printf("[SmartCtrl] Conditions excellent - trying higher FPS")
-		sc.changeFPS(sc.currentFPS + sc.cfg.UIFPSStep)
+		sc.changeFPS(sc.currentFPS+sc.cfg.UIFPSStep, ReasonProbe)
 		sc.stableSeconds.Store(0)
 		sc.recoverCount = 0
 	}
@@ -399,20 +470,22 @@ func (sc *SmartController) handleStable(temp, load float64) {
 
 // handleRecovering - stepping back up to sweet spot
 func (sc *SmartController) handleRecovering(temp float64) {
-	if temp >= TempHot {
-		if temp >= TempCritical {
+	if temp >= sc.thermal.HotC {
+		if temp >= sc.thermal.CriticalC {
 			sc.enterState(StateEmergency)
 		}
 		return
 	}
 
 	// Gradually increase toward sweet spot
-	if temp < TempComfort && sc.tempTrend <= 0 && time.Since(sc.lastChange) > 5*time.Second {
+	if temp < sc.thermal.ComfortC && sc.tempTrend <= 0 && sc.now().Sub(sc.lastChange) > 5*time.Second {
 		sc.recoverCount++
 		if sc.recoverCount >= sc.cfg.RecoverHoldCount {
 			if sc.currentFPS < sc.sweetSpotFPS {
-				sc.changeFPS(sc.currentFPS + sc.cfg.UIFPSStep)
-				sc.recoverCount = 0
+				if sc.dwelled() {
+					sc.changeFPS(sc.currentFPS+sc.cfg.UIFPSStep, ReasonRecovery)
+					sc.recoverCount = 0
+				}
 			} else {
 				log.Printf("[SmartCtrl] Recovered to sweet spot: %d FPS", sc.sweetSpotFPS)
 				sc.enterState(StateStable)
@@ -423,21 +496,50 @@ func (sc *SmartController) handleRecovering(temp float64) {
 	}
 }
 
-// changeFPS applies a new FPS value
-func (sc *SmartController) changeFPS(fps int) {
+// stressReason names what an FPS reduction at temp responds to: load
+// when the temperature alone wouldn't have called for it.
+func (sc *SmartController) stressReason(temp float64) string {
+	if temp >= sc.thermal.WarmC || temp >= sc.cfg.CPUTempThresholdC {
+		return ReasonThermal
+	}
+	return ReasonLoad
+}
+
+// changeFPS applies a new FPS value, recording it for the stability report
+func (sc *SmartController) changeFPS(fps int, reason string) {
 	if fps < sc.minFPS {
 		fps = sc.minFPS
 	}
 	if fps > sc.maxFPS {
 		fps = sc.maxFPS
 	}
+	if fps > sc.currentFPS {
+		// Stay below an FPS that keeps failing (see damping.go)
+		if limit := sc.failures.limit(sc.now(), sc.maxFPS); fps > limit {
+			if limit <= sc.currentFPS {
+				return
+			}
+			fps = limit
+		}
+	}
 	if fps == sc.currentFPS {
 		return
 	}
 
 	oldFPS := sc.currentFPS
+	if fps < oldFPS && (reason == ReasonThermal || reason == ReasonLoad) {
+		penalty := time.Duration(sc.cfg.FPSPenaltySec * float64(time.Second))
+		if sc.failures.record(oldFPS, sc.now(), sc.cfg.FPSFailLimit, penalty) {
+			log.Printf("[SmartCtrl] %d FPS failed %d times: staying below it for %.0fs",
+				oldFPS, sc.cfg.FPSFailLimit, penalty.Seconds())
+			if sc.sweetSpotFPS >= oldFPS {
+				sc.sweetSpotFPS = fps
+			}
+		}
+	}
+	sc.stability.record(sc.now(), fps, reason)
 	sc.currentFPS = fps
-	sc.lastChange = time.Now()
+	sc.lastChange = sc.now()
 	sc.stabilityCount = 0
 	sc.adjustCount++
 
@@ -445,7 +547,10 @@ func (sc *SmartController) changeFPS(fps int) {
 		sc.manager.SetFPS(fps)
 	}
 
-	log.Printf("[SmartCtrl] FPS: %d -> %d", oldFPS, fps)
+	log.Printf("[SmartCtrl] FPS: %d -> %d (%s)", oldFPS, fps, reason)
+	if sc.onFPSChange != nil {
+		sc.onFPSChange(oldFPS, fps, reason)
+	}
 }
 
 // applyFPS sets FPS without logging (for initial setup)
@@ -459,7 +564,7 @@ func (sc *SmartController) applyFPS(fps int) {
 // enterState transitions to a new state
 func (sc *SmartController) enterState(state int) {
 	oldState := sc.state.Swap(int32(state))
-	sc.stateEnterTime = time.Now()
+	sc.stateEnterTime = sc.now()
 	sc.stabilityCount = 0
 	sc.stressCount = 0
 	sc.recoverCount = 0
@@ -467,7 +572,12 @@ func (sc *SmartController) enterState(state int) {
 	log.Printf("[SmartCtrl] State: %s -> %s", stateName(oldState), stateName(int32(state)))
 
 	if state == StateEmergency {
+		oldFPS := sc.currentFPS
+		sc.stability.record(sc.now(), sc.minFPS, ReasonEmergency)
 		sc.applyFPS(sc.minFPS)
+		if sc.onFPSChange != nil && oldFPS != sc.minFPS {
+			sc.onFPSChange(oldFPS, sc.minFPS, ReasonEmergency)
+		}
 	}
 	if state == StateStable {
 		sc.stableSeconds.Store(0)
@@ -483,8 +593,12 @@ func (sc *SmartController) logStatus() {
 	load := sc.monitor.GetLoadAverage()
 
 	if sc.dynamicEnabled {
-		log.Printf("[SmartCtrl] %s | FPS: %d (sweet=%d, range %d-%d) | Temp: %.1f°C | Load: %.2f | Uptime: %ds",
-			sc.GetState(), sc.currentFPS, sc.sweetSpotFPS, sc.minFPS, sc.maxFPS,
+		var res string
+		if sc.res.maxTier > 0 {
+			res = fmt.Sprintf(" | Res tier: %d/%d", sc.res.tier, sc.res.maxTier)
+		}
+		log.Printf("[SmartCtrl] %s | FPS: %d (sweet=%d, range %d-%d)%s | Temp: %.1f°C | Load: %.2f | Uptime: %ds",
+			sc.GetState(), sc.currentFPS, sc.sweetSpotFPS, sc.minFPS, sc.maxFPS, res,
 			temp, load, sc.stableSeconds.Load())
 	} else {
 		log.Printf("[SmartCtrl] Fixed mode | FPS: %d | Temp: %.1f°C | Load: %.2f | Uptime: %ds",
@@ -506,6 +620,78 @@ func (sc *SmartController) GetSweetSpotFPS() int {
 	return sc.sweetSpotFPS
 }
 
+// StabilityReport returns the session's FPS changes: time at each FPS,
+// changes per reason and oscillations.
+func (sc *SmartController) StabilityReport() StabilityReport {
+	sc.mutex.RLock()
+	defer sc.mutex.RUnlock()
+	return sc.stability.report(sc.now())
+}
+
+// GetResolutionTier returns the adaptive resolution tier (0 = full).
+func (sc *SmartController) GetResolutionTier() int {
+	sc.mutex.RLock()
+	defer sc.mutex.RUnlock()
+	return sc.res.tier
+}
+
+// GetTemperature returns the last measured CPU temperature in Celsius
+func (sc *SmartController) GetTemperature() float64 {
+	return sc.monitor.GetTemperature()
+}
+
+// GetLoadAverage returns the last normalized load average (0.0-1.0)
+func (sc *SmartController) GetLoadAverage() float64 {
+	return sc.monitor.GetLoadAverage()
+}
+
+// SetOnFPSChange registers fn to be told about every FPS change made by
+// the controller. Call it before Start. fn runs on the control loop with
+// the controller locked, so it must return quickly and may only call
+// GetTemperature and GetLoadAverage.
+func (sc *SmartController) SetOnFPSChange(fn func(oldFPS, newFPS int, reason string)) {
+	sc.onFPSChange = fn
+}
+
+// SetConfig applies a reloaded config's thresholds and steps from the
+// next tick. The caller must keep cfg from changing during the call.
+// Settings read only at construction (capture FPS, resolution tiers,
+// thermal profile) keep their values until the controller is replaced.
+func (sc *SmartController) SetConfig(cfg *config.Config) {
+	own := *cfg
+	sc.mutex.Lock()
+	sc.cfg = &own
+	sc.mutex.Unlock()
+}
+
+// SetClock replaces the clock that dwell times, holds and the stability
+// report are measured on (nil = clock.System). Call it before Start.
+func (sc *SmartController) SetClock(c clock.Clock) {
+	sc.clock = clock.Or(c)
+}
+
+// now returns the controller's time.
+func (sc *SmartController) now() time.Time {
+	return sc.clock.Now()
+}
+
+// GetTempTrend returns the recent temperature change per control tick
+// (positive = heating, negative = cooling).
+func (sc *SmartController) GetTempTrend() float64 {
+	sc.mutex.RLock()
+	defer sc.mutex.RUnlock()
+	return sc.tempTrend
+}
+
+// GetMemoryUsage returns the memory use percentage (0-100), or 0 if the
+// monitor doesn't measure it.
+func (sc *SmartController) GetMemoryUsage() float64 {
+	if m, ok := sc.monitor.(interface{ GetMemoryUsage() float64 }); ok {
+		return m.GetMemoryUsage()
+	}
+	return 0
+}
+
 // GetState returns current state name
 func (sc *SmartController) GetState() string {
 	return stateName(sc.state.Load())
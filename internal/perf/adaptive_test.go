@@ -2,6 +2,8 @@ package perf
 
 import (
 	"camera-dashboard-go/internal/config"
+	"fmt"
+	"reflect"
 	"testing"
 )
 
@@ -101,24 +103,44 @@ func TestChangeFPS_Clamping(t *testing.T) {
 	sc := NewSmartController(nil, cfg)
 
 	// clamp below min
-	sc.changeFPS(5)
+	sc.changeFPS(5, ReasonThermal)
 	if sc.GetCurrentFPS() != 10 {
 		t.Errorf("changeFPS(5): FPS = %d, want 10 (clamped to min)", sc.GetCurrentFPS())
 	}
 
 	// clamp above max
-	sc.changeFPS(30)
+	sc.changeFPS(30, ReasonProbe)
 	if sc.GetCurrentFPS() != 20 {
 		t.Errorf("changeFPS(30): FPS = %d, want 20 (clamped to max)", sc.GetCurrentFPS())
 	}
 
 	// within range
-	sc.changeFPS(15)
+	sc.changeFPS(15, ReasonThermal)
 	if sc.GetCurrentFPS() != 15 {
 		t.Errorf("changeFPS(15): FPS = %d, want 15", sc.GetCurrentFPS())
 	}
 }
 
+func TestChangeFPS_OnFPSChange(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.DynamicFPSEnabled = true
+	cfg.CaptureFPS = 20
+	cfg.MinDynamicFPS = 10
+
+	sc := NewSmartController(nil, cfg)
+	var got []string
+	sc.SetOnFPSChange(func(oldFPS, newFPS int, reason string) {
+		got = append(got, fmt.Sprintf("%d->%d %s", oldFPS, newFPS, reason))
+	})
+	sc.changeFPS(15, ReasonThermal)
+	sc.changeFPS(15, ReasonLoad) // Unchanged: not reported
+	sc.enterState(StateEmergency)
+	want := []string{"20->15 thermal", "15->10 emergency"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("FPS changes = %q, want %q", got, want)
+	}
+}
+
 func TestChangeFPS_NoOpWhenSame(t *testing.T) {
 	cfg := config.DefaultConfig()
 	cfg.DynamicFPSEnabled = true
@@ -128,7 +150,7 @@ func TestChangeFPS_NoOpWhenSame(t *testing.T) {
 	sc := NewSmartController(nil, cfg)
 	initialAdjustCount := sc.adjustCount
 
-	sc.changeFPS(15) // same as current
+	sc.changeFPS(15, ReasonThermal) // same as current
 	if sc.adjustCount != initialAdjustCount {
 		t.Error("changeFPS should be no-op when FPS unchanged")
 	}
@@ -178,6 +200,22 @@ func TestIsDynamic(t *testing.T) {
 	}
 }
 
+func TestSetConfig(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.FPSStepDown = 2
+	sc := NewSmartController(nil, cfg)
+
+	cfg.FPSStepDown = 5 // A reload writing the caller's config
+	if sc.cfg.FPSStepDown != 2 {
+		t.Errorf("FPSStepDown = %d after the caller's config changed, want 2", sc.cfg.FPSStepDown)
+	}
+	sc.SetConfig(cfg)
+	cfg.FPSStepDown = 7
+	if sc.cfg.FPSStepDown != 5 {
+		t.Errorf("FPSStepDown = %d after SetConfig, want 5", sc.cfg.FPSStepDown)
+	}
+}
+
 func TestGetSweetSpotFPS(t *testing.T) {
 	cfg := config.DefaultConfig()
 	cfg.DynamicFPSEnabled = true
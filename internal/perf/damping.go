@@ -0,0 +1,75 @@
+package perf
+
+import (
+	"time"
+)
+
+// =============================================================================
+// Oscillation damping
+// =============================================================================
+// Three rules keep the controller from flapping between two FPS values:
+//
+//   - Dwell: after a change, FPS stays put for fps_min_dwell_sec. Only
+//     urgent reductions (hot, high load, emergency) skip the wait.
+//   - Asymmetric steps: FPS goes down by fps_step_down and up by the
+//     smaller ui_fps_step, so a probe that fails lands below where it
+//     started.
+//   - Penalty: an FPS that has to be reduced fps_fail_limit times within
+//     fps_penalty_sec is capped: the sweet spot and later probes stay
+//     below it for fps_penalty_sec.
+// =============================================================================
+
+// failureTracker remembers which FPS values had to be reduced.
+type failureTracker struct {
+	failures map[int][]time.Time // Recent reductions, per FPS reduced from
+	ceiling  int                 // FPS at or above this are capped; 0 = none
+	until    time.Time           // End of the cap
+}
+
+// record notes a stress reduction from fps. It returns true when that
+// caps fps: limit failures within window.
+func (f *failureTracker) record(fps int, now time.Time, limit int, window time.Duration) bool {
+	if limit <= 0 {
+		return false
+	}
+	if f.failures == nil {
+		f.failures = make(map[int][]time.Time)
+	}
+	recent := f.failures[fps][:0]
+	for _, at := range f.failures[fps] {
+		if now.Sub(at) < window {
+			recent = append(recent, at)
+		}
+	}
+	recent = append(recent, now)
+	f.failures[fps] = recent
+	if len(recent) < limit {
+		return false
+	}
+	if f.ceiling == 0 || !now.Before(f.until) || fps < f.ceiling {
+		f.ceiling = fps
+	}
+	f.until = now.Add(window)
+	delete(f.failures, fps)
+	return true
+}
+
+// limit returns the highest FPS allowed at now (at most maxFPS).
+func (f *failureTracker) limit(now time.Time, maxFPS int) int {
+	if f.ceiling == 0 || !now.Before(f.until) || f.ceiling > maxFPS {
+		return maxFPS
+	}
+	return f.ceiling - 1
+}
+
+// dwelled reports whether the current FPS has been held for the minimum
+// dwell time.
+func (sc *SmartController) dwelled() bool {
+	return sc.now().Sub(sc.lastChange) >= time.Duration(sc.cfg.FPSMinDwellSec*float64(time.Second))
+}
+
+// urgent reports whether temp or load call for a reduction without
+// waiting out the dwell time.
+func (sc *SmartController) urgent(temp, load float64) bool {
+	return temp >= sc.thermal.HotC || load >= LoadHigh
+}
@@ -0,0 +1,93 @@
+package perf
+
+import (
+	"camera-dashboard-go/internal/clock"
+	"camera-dashboard-go/internal/config"
+	"testing"
+	"time"
+)
+
+func TestFailureTracker_CapsRepeatedFailures(t *testing.T) {
+	var f failureTracker
+	start := time.Unix(0, 0)
+	window := 10 * time.Minute
+
+	if f.record(20, start, 2, window) {
+		t.Fatal("first failure should not cap")
+	}
+	if got := f.limit(start, 25); got != 25 {
+		t.Errorf("limit before cap = %d, want 25", got)
+	}
+	if f.record(20, start.Add(11*time.Minute), 2, window) {
+		t.Fatal("failures further apart than the window should not cap")
+	}
+	if !f.record(20, start.Add(15*time.Minute), 2, window) {
+		t.Fatal("second failure within the window should cap")
+	}
+	if got := f.limit(start.Add(16*time.Minute), 25); got != 19 {
+		t.Errorf("limit while capped = %d, want 19", got)
+	}
+	if got := f.limit(start.Add(26*time.Minute), 25); got != 25 {
+		t.Errorf("limit after the penalty = %d, want 25", got)
+	}
+	if f.record(20, start, 0, window) {
+		t.Error("fail limit 0 should never cap")
+	}
+}
+
+func TestSimulate_DampingReducesFlapping(t *testing.T) {
+	// Temperature swinging between 66 and 80°C every 40s
+	var s Scenario
+	for i := 0; i <= 60; i++ {
+		temp := 66.0
+		if i%2 == 1 {
+			temp = 80
+		}
+		s = append(s, ScenarioPoint{At: time.Duration(i) * 40 * time.Second, Temp: temp, Load: 0.4})
+	}
+	run := func(damped bool) StabilityReport {
+		cfg := config.DefaultConfig()
+		cfg.DynamicFPSEnabled = true
+		cfg.CaptureFPS = 25
+		if !damped {
+			cfg.FPSMinDwellSec = 0
+			cfg.FPSFailLimit = 0
+			cfg.FPSStepDown = cfg.UIFPSStep
+		}
+		_, r := Simulate(cfg, s)
+		return r
+	}
+
+	undamped, damped := run(false), run(true)
+	if undamped.Oscillations == 0 {
+		t.Fatal("scenario should make the undamped controller oscillate")
+	}
+	if damped.Changes*2 > undamped.Changes || damped.Oscillations*2 > undamped.Oscillations {
+		t.Errorf("damped %d changes / %d oscillations, want under half of undamped %d / %d",
+			damped.Changes, damped.Oscillations, undamped.Changes, undamped.Oscillations)
+	}
+}
+
+func TestDwelled_FakeClock(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.DynamicFPSEnabled = true
+	cfg.CaptureFPS = 20
+	cfg.MinDynamicFPS = 10
+	cfg.FPSMinDwellSec = 30
+
+	sc := NewSmartController(nil, cfg)
+	clk := clock.NewFake(time.Unix(1000, 0))
+	sc.SetClock(clk)
+	sc.changeFPS(15, ReasonThermal)
+	if sc.dwelled() {
+		t.Fatal("dwelled right after a change")
+	}
+	clk.Advance(29 * time.Second)
+	if sc.dwelled() {
+		t.Error("dwelled after 29s, want 30s")
+	}
+	clk.Advance(time.Second)
+	if !sc.dwelled() {
+		t.Error("not dwelled after 30s")
+	}
+}
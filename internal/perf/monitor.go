@@ -10,6 +10,15 @@ import (
 	"time"
 )
 
+// SystemMonitor is what the SmartController reads each tick. Monitor
+// reads the real system; a scenario replay (see scenario.go) plays back
+// recorded or made-up values.
+type SystemMonitor interface {
+	UpdateStats() error
+	GetTemperature() float64 // Celsius
+	GetLoadAverage() float64 // Normalized 0.0-1.0
+}
+
 // Monitor tracks system performance metrics
 type Monitor struct {
 	mu          sync.RWMutex
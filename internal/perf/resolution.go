@@ -0,0 +1,69 @@
+package perf
+
+import "time"
+
+// =============================================================================
+// Adaptive resolution (second adaptation axis)
+// =============================================================================
+// FPS is the cheap knob: frame skipping, no restart. When the CPU is
+// still over cpu_temp_threshold_c with FPS already at its minimum, the
+// controller steps down one resolution tier ([performance]
+// resolution_tiers, via Config.ChooseProfileTier) and the manager
+// restarts the workers at the smaller size.
+//
+// Restarts are expensive, so both directions need sustained conditions
+// and a hysteresis band between them:
+//   - Step down: hot and FPS exhausted for resolution_step_down_sec
+//   - Step up:   at least resolution_hysteresis_c below the threshold
+//     for resolution_step_up_sec
+// In between, the tier holds. One tier changes per period.
+// =============================================================================
+
+// resolutionScaler decides the resolution tier from temperature.
+type resolutionScaler struct {
+	maxTier  int           // Number of lower tiers (0 = disabled)
+	stepDown time.Duration // Sustained stress before dropping a tier
+	stepUp   time.Duration // Sustained cool before stepping back up
+	hotC     float64       // Stress threshold
+	coolC    float64       // Cool threshold (hotC minus hysteresis)
+
+	tier      int
+	hotSince  time.Time // Zero when not under stress
+	coolSince time.Time // Zero when not cool
+}
+
+// update feeds one sample. fpsExhausted reports that FPS is already at
+// its minimum. It returns the tier to run at and whether it changed.
+func (r *resolutionScaler) update(temp float64, fpsExhausted bool, now time.Time) (int, bool) {
+	if r.maxTier == 0 {
+		return 0, false
+	}
+
+	switch {
+	case temp >= r.hotC && fpsExhausted:
+		r.coolSince = time.Time{}
+		if r.hotSince.IsZero() {
+			r.hotSince = now
+		}
+		if r.tier < r.maxTier && now.Sub(r.hotSince) >= r.stepDown {
+			r.tier++
+			r.hotSince = now // Next tier needs another full period
+			return r.tier, true
+		}
+	case temp <= r.coolC:
+		r.hotSince = time.Time{}
+		if r.coolSince.IsZero() {
+			r.coolSince = now
+		}
+		if r.tier > 0 && now.Sub(r.coolSince) >= r.stepUp {
+			r.tier--
+			r.coolSince = now
+			return r.tier, true
+		}
+	default:
+		// Hysteresis band: hold the tier
+		r.hotSince = time.Time{}
+		r.coolSince = time.Time{}
+	}
+	return r.tier, false
+}
@@ -0,0 +1,47 @@
+package perf
+
+import (
+	"testing"
+	"time"
+)
+
+func TestResolutionScaler(t *testing.T) {
+	r := resolutionScaler{maxTier: 2, stepDown: 30 * time.Second, stepUp: 120 * time.Second, hotC: 75, coolC: 70}
+	start := time.Now()
+	at := func(sec int) time.Time { return start.Add(time.Duration(sec) * time.Second) }
+
+	// Hot but FPS can still drop: no change
+	if _, changed := r.update(80, false, at(0)); changed {
+		t.Fatal("tier should not change while FPS has headroom")
+	}
+	r.update(80, true, at(0))
+	if tier, changed := r.update(80, true, at(29)); changed || tier != 0 {
+		t.Fatalf("step down before stepDown: tier %d changed %v", tier, changed)
+	}
+	if tier, changed := r.update(80, true, at(30)); !changed || tier != 1 {
+		t.Fatalf("after 30s hot: tier %d changed %v, want 1 true", tier, changed)
+	}
+	if tier, _ := r.update(80, true, at(60)); tier != 2 {
+		t.Fatalf("after another 30s hot: tier %d, want 2", tier)
+	}
+	if _, changed := r.update(80, true, at(200)); changed {
+		t.Fatal("tier should stop at maxTier")
+	}
+
+	// Inside the hysteresis band nothing moves, and it resets the timer
+	r.update(69, false, at(300))
+	r.update(72, false, at(400))
+	if _, changed := r.update(69, false, at(450)); changed {
+		t.Fatal("cool timer should restart after the band")
+	}
+	if tier, changed := r.update(69, false, at(570)); !changed || tier != 1 {
+		t.Fatalf("after 120s cool: tier %d changed %v, want 1 true", tier, changed)
+	}
+}
+
+func TestResolutionScaler_Disabled(t *testing.T) {
+	var r resolutionScaler
+	if tier, changed := r.update(100, true, time.Now()); tier != 0 || changed {
+		t.Errorf("disabled scaler = (%d, %v), want (0, false)", tier, changed)
+	}
+}
@@ -0,0 +1,196 @@
+package perf
+
+import (
+	"bufio"
+	"camera-dashboard-go/internal/clock"
+	"camera-dashboard-go/internal/config"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// =============================================================================
+// Thermal scenarios (controller simulation)
+// =============================================================================
+// A scenario is a CSV of temperature and load over time:
+//
+//	# seconds, temp_c, load
+//	0,   65, 0.40
+//	120, 84, 0.60
+//	300, 70, 0.40
+//
+// Values between rows are interpolated linearly. Simulate replays a
+// scenario through a SmartController on a simulated clock, one control
+// tick per perf_check_interval_ms, so the Probing/Stable/Recovering/
+// Emergency transitions can be tested and the thresholds tuned in
+// milliseconds instead of an afternoon in a parked car. No cameras are
+// touched; FPS and resolution changes are only recorded, along with the
+// controller's stability report (see stability.go).
+// =============================================================================
+
+// ScenarioPoint is one row of a scenario.
+type ScenarioPoint struct {
+	At   time.Duration
+	Temp float64 // Celsius
+	Load float64 // Normalized 0.0-1.0
+}
+
+// Scenario is a temperature/load timeline, sorted by time.
+type Scenario []ScenarioPoint
+
+// LoadScenario reads a scenario CSV file.
+func LoadScenario(path string) (Scenario, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ParseScenario(f)
+}
+
+// ParseScenario parses "seconds,temp_c,load" rows. Blank lines, '#'
+// comments and a header row are skipped; times must increase.
+func ParseScenario(r io.Reader) (Scenario, error) {
+	var s Scenario
+	scanner := bufio.NewScanner(r)
+	lineNo, rows := 0, 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		rows++
+		fields := strings.Split(line, ",")
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("line %d: want seconds,temp_c,load", lineNo)
+		}
+		var values [3]float64
+		var err error
+		for i, field := range fields {
+			if values[i], err = strconv.ParseFloat(strings.TrimSpace(field), 64); err != nil {
+				break
+			}
+		}
+		if err != nil {
+			if rows == 1 {
+				continue // Header
+			}
+			return nil, fmt.Errorf("line %d: %v", lineNo, err)
+		}
+		at := time.Duration(values[0] * float64(time.Second))
+		if len(s) > 0 && at <= s[len(s)-1].At {
+			return nil, fmt.Errorf("line %d: time %.1fs is not after the previous row", lineNo, values[0])
+		}
+		s = append(s, ScenarioPoint{At: at, Temp: values[1], Load: values[2]})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(s) == 0 {
+		return nil, fmt.Errorf("no rows")
+	}
+	return s, nil
+}
+
+// Duration is the time of the last row.
+func (s Scenario) Duration() time.Duration {
+	if len(s) == 0 {
+		return 0
+	}
+	return s[len(s)-1].At
+}
+
+// At returns the interpolated temperature and load at t. Before the first
+// row and after the last one the nearest row holds.
+func (s Scenario) At(t time.Duration) (temp, load float64) {
+	if len(s) == 0 {
+		return 0, 0
+	}
+	if t <= s[0].At {
+		return s[0].Temp, s[0].Load
+	}
+	for i := 1; i < len(s); i++ {
+		if t <= s[i].At {
+			a, b := s[i-1], s[i]
+			f := float64(t-a.At) / float64(b.At-a.At)
+			return a.Temp + f*(b.Temp-a.Temp), a.Load + f*(b.Load-a.Load)
+		}
+	}
+	last := s[len(s)-1]
+	return last.Temp, last.Load
+}
+
+// scenarioMonitor plays a scenario back as a SystemMonitor.
+type scenarioMonitor struct {
+	scenario Scenario
+	elapsed  func() time.Duration
+	temp     float64
+	load     float64
+}
+
+func (m *scenarioMonitor) UpdateStats() error {
+	m.temp, m.load = m.scenario.At(m.elapsed())
+	return nil
+}
+
+func (m *scenarioMonitor) GetTemperature() float64 { return m.temp }
+func (m *scenarioMonitor) GetLoadAverage() float64 { return m.load }
+
+// SimSample is the controller's state after one simulated tick.
+type SimSample struct {
+	At    time.Duration
+	Temp  float64
+	Load  float64
+	State string
+	FPS   int
+	Tier  int // Adaptive resolution tier (0 = full)
+}
+
+// Simulate replays s through a SmartController configured by cfg and
+// returns its state after every control tick, and its stability report.
+func Simulate(cfg *config.Config, s Scenario) ([]SimSample, StabilityReport) {
+	start := time.Unix(0, 0)
+	var elapsed time.Duration
+	monitor := &scenarioMonitor{scenario: s, elapsed: func() time.Duration { return elapsed }}
+	sc := NewSmartControllerWithMonitor(nil, cfg, monitor)
+	clk := clock.NewFake(start)
+	sc.SetClock(clk)
+	sc.begin()
+
+	interval := checkInterval(sc.cfg)
+	var samples []SimSample
+	for ; elapsed <= s.Duration(); elapsed += interval {
+		clk.Set(start.Add(elapsed))
+		sc.tick()
+		samples = append(samples, SimSample{
+			At:    elapsed,
+			Temp:  monitor.temp,
+			Load:  monitor.load,
+			State: sc.GetState(),
+			FPS:   sc.currentFPS,
+			Tier:  sc.res.tier,
+		})
+	}
+	return samples, sc.StabilityReport()
+}
+
+// Transitions returns the first sample and every sample where the state,
+// FPS or resolution tier changed.
+func Transitions(samples []SimSample) []SimSample {
+	var out []SimSample
+	for i, sample := range samples {
+		if i == 0 {
+			out = append(out, sample)
+			continue
+		}
+		prev := samples[i-1]
+		if sample.State != prev.State || sample.FPS != prev.FPS || sample.Tier != prev.Tier {
+			out = append(out, sample)
+		}
+	}
+	return out
+}
@@ -0,0 +1,100 @@
+package perf
+
+import (
+	"camera-dashboard-go/internal/config"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseScenario(t *testing.T) {
+	s, err := ParseScenario(strings.NewReader("# comment\nseconds,temp_c,load\n\n0, 60, 0.2\n10, 80, 0.6\n"))
+	if err != nil {
+		t.Fatalf("ParseScenario() error: %v", err)
+	}
+	want := Scenario{{0, 60, 0.2}, {10 * time.Second, 80, 0.6}}
+	if !reflect.DeepEqual(s, want) {
+		t.Errorf("ParseScenario() = %v, want %v", s, want)
+	}
+
+	for _, bad := range []string{"", "0,60\n", "0,60,0.2\nx,70,0.3\n", "5,60,0.2\n5,70,0.3\n"} {
+		if _, err := ParseScenario(strings.NewReader(bad)); err == nil {
+			t.Errorf("ParseScenario(%q) should fail", bad)
+		}
+	}
+}
+
+func TestScenario_AtInterpolates(t *testing.T) {
+	s := Scenario{{0, 60, 0.2}, {10 * time.Second, 80, 0.6}}
+	cases := []struct {
+		at         time.Duration
+		temp, load float64
+	}{
+		{-time.Second, 60, 0.2},
+		{5 * time.Second, 70, 0.4},
+		{time.Minute, 80, 0.6},
+	}
+	for _, c := range cases {
+		temp, load := s.At(c.at)
+		if temp != c.temp || load < c.load-1e-9 || load > c.load+1e-9 {
+			t.Errorf("At(%v) = (%.1f, %.2f), want (%.1f, %.2f)", c.at, temp, load, c.temp, c.load)
+		}
+	}
+}
+
+func TestSimulate_Heatwave(t *testing.T) {
+	s, err := LoadScenario("testdata/heatwave.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := config.DefaultConfig()
+	cfg.DynamicFPSEnabled = true
+	cfg.CaptureFPS = 25
+	cfg.MinDynamicFPS = 10
+
+	samples, _ := Simulate(cfg, s)
+	if len(samples) == 0 {
+		t.Fatal("no samples")
+	}
+	var states []string
+	for _, sample := range Transitions(samples) {
+		if len(states) == 0 || states[len(states)-1] != sample.State {
+			states = append(states, sample.State)
+		}
+		if sample.State == "Emergency" && sample.FPS != 10 {
+			t.Errorf("%v: Emergency at %d FPS, want minimum 10", sample.At, sample.FPS)
+		}
+	}
+	want := []string{"Probing", "Stable", "Emergency", "Recovering", "Stable"}
+	if !reflect.DeepEqual(states, want) {
+		t.Errorf("states = %v, want %v", states, want)
+	}
+	if last := samples[len(samples)-1]; last.FPS <= 10 {
+		t.Errorf("after cooling: %d FPS, want back above the minimum", last.FPS)
+	}
+}
+
+func TestSimulate_ThresholdsFromConfig(t *testing.T) {
+	// 81°C is warm for a Pi 5 but past a Pi 4's critical threshold
+	s := Scenario{{0, 81, 0.3}, {60 * time.Second, 81, 0.3}}
+	cfg := config.DefaultConfig()
+	cfg.DynamicFPSEnabled = true
+	cfg.CPUTempThresholdC = 90 // Keep the generic stress check out of the way
+
+	emergency := func(samples []SimSample, _ StabilityReport) bool {
+		for _, sample := range samples {
+			if sample.State == "Emergency" {
+				return true
+			}
+		}
+		return false
+	}
+	if emergency(Simulate(cfg, s)) {
+		t.Error("Pi 5 thresholds: emergency at 81°C")
+	}
+	cfg.Thermal = config.ThermalThresholds{IdealC: 66, ComfortC: 72, WarmC: 75, HotC: 77, CriticalC: 79}
+	if !emergency(Simulate(cfg, s)) {
+		t.Error("Pi 4 thresholds: no emergency at 81°C")
+	}
+}
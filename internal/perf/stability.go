@@ -0,0 +1,152 @@
+package perf
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// =============================================================================
+// Controller stability report
+// =============================================================================
+// Every FPS change the SmartController makes is recorded with its reason.
+// The report gives, for the session, the time spent at each FPS, the
+// changes per reason and the number of oscillations: changes that
+// reverse the previous change's direction within oscillationWindow
+// (down-up-down flapping). A steady controller has a few thermal steps
+// and next to no oscillations; a flapping one needs longer hold counts
+// or wider thresholds.
+// =============================================================================
+
+// Reasons for an FPS change.
+const (
+	ReasonThermal   = "thermal"   // Temperature too high
+	ReasonLoad      = "load"      // CPU load too high
+	ReasonEmergency = "emergency" // Critical temperature: straight to minimum
+	ReasonProbe     = "probe"     // Cool and idle: trying a higher FPS
+	ReasonRecovery  = "recovery"  // Stepping back up to the sweet spot
+)
+
+// oscillationWindow is how soon a reversal must follow a change to
+// count as an oscillation.
+const oscillationWindow = 2 * time.Minute
+
+// maxRecentChanges bounds the change log kept for the report.
+const maxRecentChanges = 20
+
+// FPSChange is one recorded FPS change.
+type FPSChange struct {
+	At       time.Time
+	From, To int
+	Reason   string
+}
+
+// StabilityReport summarizes the controller's FPS changes in a session.
+type StabilityReport struct {
+	Session      time.Duration
+	TimeAtFPS    map[int]time.Duration
+	Changes      int
+	ByReason     map[string]int
+	Oscillations int
+	Recent       []FPSChange // Newest last, at most maxRecentChanges
+}
+
+// stabilityTracker records FPS changes. Guarded by the controller's mutex.
+type stabilityTracker struct {
+	start     time.Time
+	fps       int
+	fpsSince  time.Time
+	timeAtFPS map[int]time.Duration
+	byReason  map[string]int
+	changes   int
+	osc       int
+	recent    []FPSChange
+}
+
+func newStabilityTracker(now time.Time, fps int) *stabilityTracker {
+	return &stabilityTracker{
+		start:     now,
+		fps:       fps,
+		fpsSince:  now,
+		timeAtFPS: make(map[int]time.Duration),
+		byReason:  make(map[string]int),
+	}
+}
+
+// record notes a change to fps at now.
+func (t *stabilityTracker) record(now time.Time, fps int, reason string) {
+	if fps == t.fps {
+		return
+	}
+	if n := len(t.recent); n > 0 {
+		last := t.recent[n-1]
+		if now.Sub(last.At) <= oscillationWindow && (last.To > last.From) != (fps > t.fps) {
+			t.osc++
+		}
+	}
+	t.timeAtFPS[t.fps] += now.Sub(t.fpsSince)
+	t.recent = append(t.recent, FPSChange{At: now, From: t.fps, To: fps, Reason: reason})
+	if len(t.recent) > maxRecentChanges {
+		t.recent = t.recent[1:]
+	}
+	t.byReason[reason]++
+	t.changes++
+	t.fps, t.fpsSince = fps, now
+}
+
+// report returns the session so far.
+func (t *stabilityTracker) report(now time.Time) StabilityReport {
+	r := StabilityReport{
+		Session:      now.Sub(t.start),
+		TimeAtFPS:    make(map[int]time.Duration, len(t.timeAtFPS)+1),
+		Changes:      t.changes,
+		ByReason:     make(map[string]int, len(t.byReason)),
+		Oscillations: t.osc,
+		Recent:       append([]FPSChange(nil), t.recent...),
+	}
+	for fps, d := range t.timeAtFPS {
+		r.TimeAtFPS[fps] = d
+	}
+	r.TimeAtFPS[t.fps] += now.Sub(t.fpsSince)
+	for reason, n := range t.byReason {
+		r.ByReason[reason] = n
+	}
+	return r
+}
+
+// Lines renders the report: a summary, the time at each FPS (highest
+// first) and the recent changes.
+func (r StabilityReport) Lines() []string {
+	reasons := make([]string, 0, len(r.ByReason))
+	for reason, n := range r.ByReason {
+		reasons = append(reasons, fmt.Sprintf("%s %d", reason, n))
+	}
+	sort.Strings(reasons)
+	summary := fmt.Sprintf("Session %s: %d FPS changes", r.Session.Round(time.Second), r.Changes)
+	if len(reasons) > 0 {
+		summary += " (" + strings.Join(reasons, ", ") + ")"
+	}
+	summary += fmt.Sprintf(", %d oscillations", r.Oscillations)
+	lines := []string{summary}
+
+	fpsList := make([]int, 0, len(r.TimeAtFPS))
+	for fps := range r.TimeAtFPS {
+		fpsList = append(fpsList, fps)
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(fpsList)))
+	parts := make([]string, 0, len(fpsList))
+	for _, fps := range fpsList {
+		pct := 0.0
+		if r.Session > 0 {
+			pct = 100 * float64(r.TimeAtFPS[fps]) / float64(r.Session)
+		}
+		parts = append(parts, fmt.Sprintf("%d=%s (%.0f%%)", fps, r.TimeAtFPS[fps].Round(time.Second), pct))
+	}
+	lines = append(lines, "Time at FPS: "+strings.Join(parts, " "))
+
+	for _, c := range r.Recent {
+		lines = append(lines, fmt.Sprintf("%s %d -> %d %s", c.At.Format("15:04:05"), c.From, c.To, c.Reason))
+	}
+	return lines
+}
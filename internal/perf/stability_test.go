@@ -0,0 +1,59 @@
+package perf
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStabilityTracker_TimeAtFPSAndOscillations(t *testing.T) {
+	start := time.Unix(1000, 0)
+	at := func(sec int) time.Time { return start.Add(time.Duration(sec) * time.Second) }
+
+	tr := newStabilityTracker(start, 20)
+	tr.record(at(60), 17, ReasonThermal)  // Down
+	tr.record(at(90), 19, ReasonProbe)    // Up within 2 min: oscillation
+	tr.record(at(100), 19, ReasonProbe)   // Same FPS: ignored
+	tr.record(at(120), 16, ReasonThermal) // Down within 2 min: oscillation
+	tr.record(at(600), 18, ReasonRecovery)
+
+	r := tr.report(at(660))
+	if r.Changes != 4 || r.Oscillations != 2 {
+		t.Errorf("changes/oscillations = %d/%d, want 4/2", r.Changes, r.Oscillations)
+	}
+	want := map[int]time.Duration{20: 60 * time.Second, 17: 30 * time.Second, 19: 30 * time.Second, 16: 480 * time.Second, 18: 60 * time.Second}
+	for fps, d := range want {
+		if r.TimeAtFPS[fps] != d {
+			t.Errorf("time at %d FPS = %v, want %v", fps, r.TimeAtFPS[fps], d)
+		}
+	}
+	if r.ByReason[ReasonThermal] != 2 || r.ByReason[ReasonProbe] != 1 || r.ByReason[ReasonRecovery] != 1 {
+		t.Errorf("ByReason = %v", r.ByReason)
+	}
+
+	lines := r.Lines()
+	if !strings.HasPrefix(lines[0], "Session 11m0s: 4 FPS changes (probe 1, recovery 1, thermal 2), 2 oscillations") {
+		t.Errorf("summary = %q", lines[0])
+	}
+	if !strings.HasPrefix(lines[1], "Time at FPS: 20=1m0s (9%) 19=30s (5%) 18=1m0s (9%)") {
+		t.Errorf("time at FPS = %q", lines[1])
+	}
+	if len(lines) != 2+4 {
+		t.Errorf("%d lines, want summary, time at FPS and 4 changes", len(lines))
+	}
+}
+
+func TestStabilityTracker_RecentIsBounded(t *testing.T) {
+	start := time.Unix(0, 0)
+	tr := newStabilityTracker(start, 10)
+	for i := 1; i <= maxRecentChanges+5; i++ {
+		tr.record(start.Add(time.Duration(i)*time.Hour), 10+i%2, ReasonProbe)
+	}
+	r := tr.report(start.Add(48 * time.Hour))
+	if len(r.Recent) != maxRecentChanges || r.Changes != maxRecentChanges+5 {
+		t.Errorf("recent %d / changes %d, want %d / %d", len(r.Recent), r.Changes, maxRecentChanges, maxRecentChanges+5)
+	}
+	if r.Oscillations != 0 {
+		t.Errorf("oscillations = %d, want 0 (changes an hour apart)", r.Oscillations)
+	}
+}
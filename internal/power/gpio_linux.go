@@ -0,0 +1,77 @@
+package power
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// GPIO character device uAPI (v1 line handles, linux/gpio.h).
+const (
+	gpioHandleRequestOutput    = 1 << 1
+	gpioHandleRequestActiveLow = 1 << 2
+
+	gpioGetLineHandleIoctl      = 0xC16CB403 // _IOWR(0xB4, 0x03, struct gpiohandle_request)
+	gpioHandleSetLineValueIoctl = 0xC040B409 // _IOWR(0xB4, 0x09, struct gpiohandle_data)
+)
+
+// gpioHandleRequest mirrors struct gpiohandle_request (364 bytes).
+type gpioHandleRequest struct {
+	LineOffsets   [64]uint32
+	Flags         uint32
+	DefaultValues [64]uint8
+	ConsumerLabel [32]byte
+	Lines         uint32
+	Fd            int32
+}
+
+// gpioHandleData mirrors struct gpiohandle_data.
+type gpioHandleData struct {
+	Values [64]uint8
+}
+
+// gpioLine is a requested output line; the handle fd holds it.
+type gpioLine struct {
+	f *os.File
+}
+
+// OpenGPIO returns an Opener for lines on chip (e.g. /dev/gpiochip0).
+func OpenGPIO(chip string) Opener {
+	return func(r Rail) (Line, error) {
+		f, err := os.OpenFile(chip, os.O_RDWR, 0)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+
+		req := gpioHandleRequest{Flags: gpioHandleRequestOutput, Lines: 1}
+		req.LineOffsets[0] = uint32(r.Pin)
+		req.DefaultValues[0] = 1 // On
+		if r.ActiveLow {
+			req.Flags |= gpioHandleRequestActiveLow
+		}
+		copy(req.ConsumerLabel[:], "camera-dashboard")
+		if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, f.Fd(), gpioGetLineHandleIoctl, uintptr(unsafe.Pointer(&req))); errno != 0 {
+			return nil, fmt.Errorf("request line %d on %s: %w", r.Pin, chip, errno)
+		}
+		return &gpioLine{f: os.NewFile(uintptr(req.Fd), fmt.Sprintf("%s:%d", chip, r.Pin))}, nil
+	}
+}
+
+// Set drives the line on (1) or off (0).
+func (l *gpioLine) Set(on bool) error {
+	var data gpioHandleData
+	if on {
+		data.Values[0] = 1
+	}
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, l.f.Fd(), gpioHandleSetLineValueIoctl, uintptr(unsafe.Pointer(&data))); errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+// Close releases the line.
+func (l *gpioLine) Close() error {
+	return l.f.Close()
+}
@@ -0,0 +1,16 @@
+package power
+
+import (
+	"testing"
+	"unsafe"
+)
+
+func TestGPIOStructSizes(t *testing.T) {
+	// The ioctl numbers encode these sizes (linux/gpio.h)
+	if n := unsafe.Sizeof(gpioHandleRequest{}); n != 364 {
+		t.Errorf("gpiohandle_request is %d bytes, want 364", n)
+	}
+	if n := unsafe.Sizeof(gpioHandleData{}); n != 64 {
+		t.Errorf("gpiohandle_data is %d bytes, want 64", n)
+	}
+}
@@ -0,0 +1,12 @@
+//go:build !linux
+
+package power
+
+import "errors"
+
+// OpenGPIO is only supported on Linux.
+func OpenGPIO(chip string) Opener {
+	return func(r Rail) (Line, error) {
+		return nil, errors.New("GPIO power control is only supported on Linux")
+	}
+}
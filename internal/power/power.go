@@ -0,0 +1,169 @@
+// Package power switches camera power rails through GPIO lines (relays
+// or load switches).
+package power
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// =============================================================================
+// Camera power rails
+// =============================================================================
+// Each rail is one GPIO output driving a relay or load switch in a
+// camera's 5V line. [power] maps cameras to rails:
+//
+//   video0      = 17
+//   net-trailer = 27, active_low
+//
+// Several cameras may share a rail (one line is requested per pin);
+// switching it affects all of them. Lines are requested once at startup
+// with the rails on and stay held by the process. Values are logical:
+// "on" drives the pin low for active_low rails.
+// =============================================================================
+
+// Rail is a parsed [power] entry.
+type Rail struct {
+	Pin       int
+	ActiveLow bool
+}
+
+// ParseRail parses "17" or "17, active_low".
+func ParseRail(spec string) (Rail, error) {
+	parts := strings.Split(spec, ",")
+	pin, err := strconv.Atoi(strings.TrimSpace(parts[0]))
+	if err != nil || pin < 0 {
+		return Rail{}, fmt.Errorf("invalid GPIO line %q", strings.TrimSpace(parts[0]))
+	}
+	r := Rail{Pin: pin}
+	for _, option := range parts[1:] {
+		switch strings.ToLower(strings.TrimSpace(option)) {
+		case "active_low":
+			r.ActiveLow = true
+		case "", "active_high":
+		default:
+			return Rail{}, fmt.Errorf("unknown option %q", strings.TrimSpace(option))
+		}
+	}
+	return r, nil
+}
+
+// Line is one requested GPIO output.
+type Line interface {
+	Set(on bool) error
+	Close() error
+}
+
+// Opener requests a GPIO output line, initially on.
+type Opener func(r Rail) (Line, error)
+
+// Controller owns the camera rails.
+type Controller struct {
+	mu      sync.Mutex
+	lines   map[int]Line   // By pin
+	entries map[string]int // Camera match -> pin
+}
+
+// NewController requests a line for every valid entry (powering it on).
+// Invalid entries and lines that can't be requested are logged and
+// skipped.
+func NewController(entries map[string]string, open Opener) *Controller {
+	c := &Controller{lines: make(map[int]Line), entries: make(map[string]int)}
+	for _, entry := range sortedKeys(entries) {
+		r, err := ParseRail(entries[entry])
+		if err != nil {
+			log.Printf("[Power] WARNING: [power] %s: %v", entry, err)
+			continue
+		}
+		if _, ok := c.lines[r.Pin]; !ok {
+			line, err := open(r)
+			if err != nil {
+				log.Printf("[Power] WARNING: %s: GPIO %d: %v", entry, r.Pin, err)
+				continue
+			}
+			c.lines[r.Pin] = line
+		}
+		c.entries[entry] = r.Pin
+		log.Printf("[Power] %s: rail on GPIO %d (on)", entry, r.Pin)
+	}
+	return c
+}
+
+// Entries returns the camera matches with a working rail.
+func (c *Controller) Entries() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entries := make([]string, 0, len(c.entries))
+	for entry := range c.entries {
+		entries = append(entries, entry)
+	}
+	sort.Strings(entries)
+	return entries
+}
+
+// Len returns the number of rails.
+func (c *Controller) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.lines)
+}
+
+// SetAll switches every rail.
+func (c *Controller) SetAll(on bool) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var firstErr error
+	for pin, line := range c.lines {
+		if err := line.Set(on); err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("GPIO %d: %w", pin, err)
+		}
+	}
+	return firstErr
+}
+
+// Cycle switches entry's rail off for off, then back on. Other cameras
+// on the same rail are cycled too.
+func (c *Controller) Cycle(entry string, off time.Duration) error {
+	c.mu.Lock()
+	pin, ok := c.entries[entry]
+	line := c.lines[pin]
+	c.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("no rail for %s", entry)
+	}
+
+	log.Printf("[Power] %s: power cycling GPIO %d (%.1fs off)", entry, pin, off.Seconds())
+	if err := line.Set(false); err != nil {
+		return fmt.Errorf("GPIO %d off: %w", pin, err)
+	}
+	time.Sleep(off)
+	if err := line.Set(true); err != nil {
+		return fmt.Errorf("GPIO %d on: %w", pin, err)
+	}
+	return nil
+}
+
+// Close releases the lines (their last value normally stays).
+func (c *Controller) Close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for pin, line := range c.lines {
+		line.Close()
+		delete(c.lines, pin)
+	}
+	c.entries = make(map[string]int)
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
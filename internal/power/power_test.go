@@ -0,0 +1,68 @@
+package power
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParseRail(t *testing.T) {
+	if r, err := ParseRail("27, Active_Low"); err != nil || r != (Rail{Pin: 27, ActiveLow: true}) {
+		t.Errorf("ParseRail = %+v, %v", r, err)
+	}
+	for _, bad := range []string{"", "x", "-1", "17, inverted"} {
+		if _, err := ParseRail(bad); err == nil {
+			t.Errorf("ParseRail(%q) should fail", bad)
+		}
+	}
+}
+
+// fakeLine records the values it was set to.
+type fakeLine struct {
+	values []bool
+	closed bool
+}
+
+func (l *fakeLine) Set(on bool) error { l.values = append(l.values, on); return nil }
+func (l *fakeLine) Close() error      { l.closed = true; return nil }
+
+func TestController(t *testing.T) {
+	lines := make(map[int]*fakeLine)
+	open := func(r Rail) (Line, error) {
+		if r.Pin == 99 {
+			return nil, errors.New("busy")
+		}
+		lines[r.Pin] = &fakeLine{}
+		return lines[r.Pin], nil
+	}
+	c := NewController(map[string]string{
+		"video0": "17",
+		"video2": "17", // Shares video0's rail
+		"video4": "22, active_low",
+		"video6": "99",
+		"video8": "bad",
+	}, open)
+
+	if got := c.Entries(); !reflect.DeepEqual(got, []string{"video0", "video2", "video4"}) {
+		t.Errorf("Entries = %v", got)
+	}
+	if c.Len() != 2 {
+		t.Errorf("Len = %d, want 2 (one line per pin)", c.Len())
+	}
+
+	if err := c.Cycle("video2", 0); err != nil {
+		t.Fatalf("Cycle: %v", err)
+	}
+	if !reflect.DeepEqual(lines[17].values, []bool{false, true}) || len(lines[22].values) != 0 {
+		t.Errorf("after cycle: pin 17 %v, pin 22 %v", lines[17].values, lines[22].values)
+	}
+	if err := c.Cycle("video6", 0); err == nil {
+		t.Error("Cycle without a rail should fail")
+	}
+
+	c.SetAll(false)
+	c.Close()
+	if !lines[22].closed || !reflect.DeepEqual(lines[22].values, []bool{false}) {
+		t.Errorf("pin 22 after SetAll(false)+Close: %v closed=%v", lines[22].values, lines[22].closed)
+	}
+}
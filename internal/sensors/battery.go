@@ -0,0 +1,197 @@
+package sensors
+
+import (
+	"fmt"
+	"io"
+	"time"
+)
+
+// =============================================================================
+// Battery voltage over I2C (INA219 or ADS1115)
+// =============================================================================
+// INA219: a current/power monitor whose bus voltage register measures
+// the supply on VIN- directly (0-26 V in 4 mV steps), so a 12 V feed can
+// be wired to it as is.
+// ADS1115: a 16-bit ADC. One single-ended channel is read single-shot
+// at the +/-4.096 V range, behind a voltage divider; the reading is
+// multiplied by the divider ratio (e.g. 5.7 for 47k over 10k).
+//
+// BatteryGuard smooths the readings and reports a low battery only once
+// the average has stayed below the threshold for a delay, so the dip of
+// an engine start doesn't count. The I2C bus access itself is platform
+// code (i2c_linux.go).
+// =============================================================================
+
+const (
+	DefaultINA219Address  = 0x40
+	DefaultADS1115Address = 0x48
+
+	ina219RegBusVoltage = 0x02
+	ina219VoltsPerLSB   = 0.004 // Bus voltage register, bits 15:3
+
+	adsRegConversion  = 0x00
+	adsRegConfig      = 0x01
+	adsFullScaleVolts = 4.096
+	// Start a single conversion, AINx vs GND (MUX 1xx), +/-4.096 V,
+	// single-shot, 128 SPS, comparator off
+	adsConfigSingle = 0x8000 | 0x4000 | 0x0200 | 0x0100 | 0x0080 | 0x0003
+	adsConvWait     = 10 * time.Millisecond // A conversion takes 7.8 ms at 128 SPS
+
+	batteryAverageWeight = 0.3 // Weight of a new reading in the average
+)
+
+// VoltageSensor reads a supply voltage.
+type VoltageSensor interface {
+	Voltage() (float64, error)
+	Close() error
+}
+
+// OpenBatterySensor opens the ADC named adc ("ina219" or "ads1115") at
+// addr on bus (0 = the chip's default address). channel and divider
+// apply to the ADS1115 only.
+func OpenBatterySensor(adc, bus string, addr, channel int, divider float64) (VoltageSensor, error) {
+	switch adc {
+	case "ina219":
+		if addr == 0 {
+			addr = DefaultINA219Address
+		}
+		return OpenINA219(bus, addr)
+	case "ads1115":
+		if addr == 0 {
+			addr = DefaultADS1115Address
+		}
+		return OpenADS1115(bus, addr, channel, divider)
+	}
+	return nil, fmt.Errorf("unknown ADC %q (want ina219 or ads1115)", adc)
+}
+
+// INA219 is a power monitor on an I2C bus.
+type INA219 struct {
+	dev io.ReadWriteCloser
+}
+
+// OpenINA219 opens the monitor at addr on bus (e.g. "/dev/i2c-1").
+func OpenINA219(bus string, addr int) (*INA219, error) {
+	dev, err := openI2C(bus, addr)
+	if err != nil {
+		return nil, err
+	}
+	s := &INA219{dev: dev}
+	if _, err := s.Voltage(); err != nil {
+		dev.Close()
+		return nil, fmt.Errorf("INA219 at %s 0x%02x: no response: %w", bus, addr, err)
+	}
+	return s, nil
+}
+
+// Voltage returns the bus voltage.
+func (s *INA219) Voltage() (float64, error) {
+	raw, err := readReg16(s.dev, ina219RegBusVoltage)
+	if err != nil {
+		return 0, err
+	}
+	return float64(raw>>3) * ina219VoltsPerLSB, nil
+}
+
+// Close releases the bus.
+func (s *INA219) Close() error {
+	return s.dev.Close()
+}
+
+// ADS1115 is an ADC on an I2C bus measuring one channel through a
+// voltage divider.
+type ADS1115 struct {
+	dev     io.ReadWriteCloser
+	channel int     // AIN0-AIN3
+	divider float64 // Battery volts per volt at the pin
+	sleep   func(time.Duration)
+}
+
+// OpenADS1115 opens the ADC at addr on bus, reading AIN<channel>.
+func OpenADS1115(bus string, addr, channel int, divider float64) (*ADS1115, error) {
+	if channel < 0 || channel > 3 {
+		return nil, fmt.Errorf("ADS1115 channel %d (want 0-3)", channel)
+	}
+	if divider <= 0 {
+		divider = 1
+	}
+	dev, err := openI2C(bus, addr)
+	if err != nil {
+		return nil, err
+	}
+	s := &ADS1115{dev: dev, channel: channel, divider: divider, sleep: time.Sleep}
+	if _, err := s.Voltage(); err != nil {
+		dev.Close()
+		return nil, fmt.Errorf("ADS1115 at %s 0x%02x: no response: %w", bus, addr, err)
+	}
+	return s, nil
+}
+
+// Voltage runs one conversion and returns the battery voltage.
+func (s *ADS1115) Voltage() (float64, error) {
+	config := uint16(adsConfigSingle | s.channel<<12)
+	if _, err := s.dev.Write([]byte{adsRegConfig, byte(config >> 8), byte(config)}); err != nil {
+		return 0, err
+	}
+	s.sleep(adsConvWait)
+	raw, err := readReg16(s.dev, adsRegConversion)
+	if err != nil {
+		return 0, err
+	}
+	v := float64(int16(raw)) * adsFullScaleVolts / 32768 * s.divider
+	if v < 0 {
+		v = 0 // Single-ended inputs only go slightly negative (noise)
+	}
+	return v, nil
+}
+
+// Close releases the bus.
+func (s *ADS1115) Close() error {
+	return s.dev.Close()
+}
+
+// readReg16 reads the big-endian 16-bit register reg.
+func readReg16(dev io.ReadWriter, reg byte) (uint16, error) {
+	if _, err := dev.Write([]byte{reg}); err != nil {
+		return 0, err
+	}
+	buf := make([]byte, 2)
+	if _, err := io.ReadFull(dev, buf); err != nil {
+		return 0, err
+	}
+	return uint16(buf[0])<<8 | uint16(buf[1]), nil
+}
+
+// BatteryGuard tracks the battery voltage and decides when it is low.
+type BatteryGuard struct {
+	threshold float64 // Volts; 0 = never low
+	delay     time.Duration
+	average   float64
+	measured  bool
+	lowSince  time.Time // When the average fell below threshold; zero = not below
+}
+
+// NewBatteryGuard creates a guard reporting low once the average voltage
+// has been below thresholdV for delay.
+func NewBatteryGuard(thresholdV float64, delay time.Duration) *BatteryGuard {
+	return &BatteryGuard{threshold: thresholdV, delay: delay}
+}
+
+// Update adds a reading taken at now. It returns the average voltage,
+// whether that is below the threshold, and whether it has been for the
+// whole delay.
+func (g *BatteryGuard) Update(volts float64, now time.Time) (average float64, below, low bool) {
+	if !g.measured {
+		g.average, g.measured = volts, true
+	} else {
+		g.average += batteryAverageWeight * (volts - g.average)
+	}
+	if g.threshold <= 0 || g.average >= g.threshold {
+		g.lowSince = time.Time{}
+		return g.average, false, false
+	}
+	if g.lowSince.IsZero() {
+		g.lowSince = now
+	}
+	return g.average, true, now.Sub(g.lowSince) >= g.delay
+}
@@ -0,0 +1,81 @@
+package sensors
+
+import (
+	"bytes"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestINA219_Voltage(t *testing.T) {
+	// 12.6 V = 3150 counts, in bits 15:3
+	raw := uint16(3150 << 3)
+	bus := &fakeBus{reply: bytes.NewReader([]byte{byte(raw >> 8), byte(raw)})}
+	s := &INA219{dev: bus}
+	v, err := s.Voltage()
+	if err != nil || math.Abs(v-12.6) > 1e-9 {
+		t.Errorf("Voltage = %v, %v; want 12.6", v, err)
+	}
+	if want := []byte{0x02}; !bytes.Equal(bus.written.Bytes(), want) {
+		t.Errorf("wrote % x, want % x", bus.written.Bytes(), want)
+	}
+}
+
+func TestADS1115_Voltage(t *testing.T) {
+	// 2.048 V at the pin (half scale) behind a 6:1 divider
+	bus := &fakeBus{reply: bytes.NewReader([]byte{0x40, 0x00})}
+	var waited time.Duration
+	s := &ADS1115{dev: bus, channel: 2, divider: 6, sleep: func(d time.Duration) { waited = d }}
+	v, err := s.Voltage()
+	if err != nil || math.Abs(v-12.288) > 1e-9 {
+		t.Errorf("Voltage = %v, %v; want 12.288", v, err)
+	}
+	// Config: single shot on AIN2, then select the conversion register
+	if want := []byte{0x01, 0xE3, 0x83, 0x00}; !bytes.Equal(bus.written.Bytes(), want) {
+		t.Errorf("wrote % x, want % x", bus.written.Bytes(), want)
+	}
+	if waited < 8*time.Millisecond {
+		t.Errorf("waited %v for the conversion", waited)
+	}
+}
+
+func TestBatteryGuard(t *testing.T) {
+	g := NewBatteryGuard(11.5, 30*time.Second)
+	start := time.Unix(1000, 0)
+
+	if avg, below, low := g.Update(12.6, start); avg != 12.6 || below || low {
+		t.Errorf("first reading = %v, %v, %v", avg, below, low)
+	}
+	// A cranking dip is averaged away
+	if _, below, _ := g.Update(9.5, start.Add(time.Second)); below {
+		t.Error("one low reading should not pull the average below")
+	}
+	for i := 2; i < 10; i++ {
+		g.Update(11.0, start.Add(time.Duration(i)*time.Second))
+	}
+	_, below, low := g.Update(11.0, start.Add(10*time.Second))
+	if !below || low {
+		t.Errorf("just below = %v, %v; want below, not low yet", below, low)
+	}
+	// Low only once below for the whole delay
+	var lowAt time.Duration
+	for i := 11; i < 60 && lowAt == 0; i++ {
+		if _, _, low := g.Update(11.0, start.Add(time.Duration(i)*time.Second)); low {
+			lowAt = time.Duration(i) * time.Second
+		}
+	}
+	if lowAt == 0 || lowAt > 45*time.Second {
+		t.Errorf("low after %v, want ~30s below", lowAt)
+	}
+	// Recovering resets the delay
+	for i := 0; i < 10; i++ {
+		g.Update(12.8, start.Add(time.Minute+time.Duration(i)*time.Second))
+	}
+	if _, below, low := g.Update(12.8, start.Add(2*time.Minute)); below || low {
+		t.Error("should recover above the threshold")
+	}
+
+	if _, below, _ := NewBatteryGuard(0, 0).Update(5, start); below {
+		t.Error("threshold 0 should never be low")
+	}
+}
@@ -0,0 +1,65 @@
+package sensors
+
+import (
+	"fmt"
+	"io"
+)
+
+// =============================================================================
+// BH1750 ambient light sensor over I2C
+// =============================================================================
+// The sensor is powered on and put in continuous high-resolution mode
+// (1 lx steps, a new value every ~120 ms). Each Read fetches the
+// big-endian 16-bit count; lux = count / 1.2. The I2C bus access itself
+// is platform code (i2c_linux.go).
+// =============================================================================
+
+const (
+	DefaultBH1750Address = 0x23 // 0x5C with ADDR pulled high
+
+	bh1750PowerOn        = 0x01
+	bh1750ContinuousHRes = 0x10
+	bh1750CountsPerLux   = 1.2
+)
+
+// BH1750 is an ambient light sensor on an I2C bus.
+type BH1750 struct {
+	dev io.ReadWriteCloser
+}
+
+// OpenBH1750 opens the sensor at addr on bus (e.g. "/dev/i2c-1") and
+// starts continuous measurement.
+func OpenBH1750(bus string, addr int) (*BH1750, error) {
+	dev, err := openI2C(bus, addr)
+	if err != nil {
+		return nil, err
+	}
+	b := &BH1750{dev: dev}
+	if err := b.init(); err != nil {
+		dev.Close()
+		return nil, fmt.Errorf("BH1750 at %s 0x%02x: %w", bus, addr, err)
+	}
+	return b, nil
+}
+
+func (b *BH1750) init() error {
+	if _, err := b.dev.Write([]byte{bh1750PowerOn}); err != nil {
+		return fmt.Errorf("no response: %w", err)
+	}
+	_, err := b.dev.Write([]byte{bh1750ContinuousHRes})
+	return err
+}
+
+// Lux returns the current illuminance.
+func (b *BH1750) Lux() (float64, error) {
+	raw := make([]byte, 2)
+	if _, err := io.ReadFull(b.dev, raw); err != nil {
+		return 0, err
+	}
+	return float64(uint16(raw[0])<<8|uint16(raw[1])) / bh1750CountsPerLux, nil
+}
+
+// Close releases the bus.
+func (b *BH1750) Close() error {
+	return b.dev.Close()
+}
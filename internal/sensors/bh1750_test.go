@@ -0,0 +1,20 @@
+package sensors
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestBH1750_InitAndLux(t *testing.T) {
+	bus := &fakeBus{reply: bytes.NewReader([]byte{0x01, 0x2C})}
+	b := &BH1750{dev: bus}
+	if err := b.init(); err != nil {
+		t.Fatalf("init: %v", err)
+	}
+	if want := []byte{0x01, 0x10}; !bytes.Equal(bus.written.Bytes(), want) {
+		t.Errorf("init wrote % x, want % x", bus.written.Bytes(), want)
+	}
+	if lux, err := b.Lux(); err != nil || lux != 250 {
+		t.Errorf("Lux = %v, %v; want 250", lux, err)
+	}
+}
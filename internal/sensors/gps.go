@@ -0,0 +1,246 @@
+package sensors
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// =============================================================================
+// GPS (gpsd or serial NMEA)
+// =============================================================================
+// The source is either a gpsd daemon ("gpsd://host:port", JSON TPV
+// reports) or a serial receiver read directly ("/dev/ttyACM0", NMEA RMC
+// sentences). USB receivers are CDC-ACM devices and need no baud setup;
+// a UART receiver's baud rate is set outside the dashboard (stty). The
+// latest fix is kept in memory; a lost connection is retried.
+// =============================================================================
+
+const (
+	gpsdScheme       = "gpsd://"
+	gpsRetryInterval = 5 * time.Second
+	gpsFixMaxAge     = 5 * time.Second // Older fixes are reported as lost
+	knotsToMS        = 0.514444
+)
+
+// Fix is a position report.
+type Fix struct {
+	Time    time.Time // When the fix was received (local clock)
+	UTC     time.Time // Satellite time of the fix; zero when not reported
+	Lat     float64   // Degrees, north positive
+	Lon     float64   // Degrees, east positive
+	SpeedMS float64   // Ground speed in m/s
+}
+
+// GPS reads fixes from a gpsd daemon or a serial NMEA receiver.
+type GPS struct {
+	source string
+
+	mu     sync.RWMutex
+	latest Fix
+}
+
+// NewGPS creates a reader for source ("gpsd://host:port" or a device path).
+func NewGPS(source string) *GPS {
+	return &GPS{source: source}
+}
+
+// Latest returns the most recent fix, or false when there is none or it
+// is older than gpsFixMaxAge.
+func (g *GPS) Latest() (Fix, bool) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	if g.latest.Time.IsZero() || time.Since(g.latest.Time) > gpsFixMaxAge {
+		return Fix{}, false
+	}
+	return g.latest, true
+}
+
+func (g *GPS) store(f Fix) {
+	g.mu.Lock()
+	g.latest = f
+	g.mu.Unlock()
+}
+
+// Run reads fixes until stop is closed, reconnecting on errors.
+func (g *GPS) Run(stop <-chan struct{}) {
+	for {
+		err := g.readOnce(stop)
+		select {
+		case <-stop:
+			return
+		default:
+		}
+		log.Printf("[GPS] WARNING: %s: %v (retrying in %v)", g.source, err, gpsRetryInterval)
+		select {
+		case <-stop:
+			return
+		case <-time.After(gpsRetryInterval):
+		}
+	}
+}
+
+// readOnce opens the source and parses it until it fails or stop closes.
+func (g *GPS) readOnce(stop <-chan struct{}) error {
+	var (
+		rc    io.ReadCloser
+		parse func(string) (Fix, bool)
+	)
+	if strings.HasPrefix(g.source, gpsdScheme) {
+		conn, err := net.DialTimeout("tcp", strings.TrimPrefix(g.source, gpsdScheme), 5*time.Second)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(conn, `?WATCH={"enable":true,"json":true}`+"\n"); err != nil {
+			conn.Close()
+			return err
+		}
+		rc, parse = conn, parseGPSDReport
+	} else {
+		f, err := os.Open(g.source)
+		if err != nil {
+			return err
+		}
+		rc, parse = f, parseNMEA
+	}
+	log.Printf("[GPS] Reading %s", g.source)
+
+	// Unblock the scanner on shutdown
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-stop:
+		case <-done:
+		}
+		rc.Close()
+	}()
+
+	scanner := bufio.NewScanner(rc)
+	for scanner.Scan() {
+		if fix, ok := parse(strings.TrimSpace(scanner.Text())); ok {
+			fix.Time = time.Now()
+			g.store(fix)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	return io.EOF
+}
+
+// parseGPSDReport parses a gpsd JSON TPV report with a 2D or 3D fix.
+func parseGPSDReport(line string) (Fix, bool) {
+	var tpv struct {
+		Class string   `json:"class"`
+		Mode  int      `json:"mode"`
+		Lat   *float64 `json:"lat"`
+		Lon   *float64 `json:"lon"`
+		Speed float64  `json:"speed"`
+		Time  string   `json:"time"`
+	}
+	if err := json.Unmarshal([]byte(line), &tpv); err != nil {
+		return Fix{}, false
+	}
+	if tpv.Class != "TPV" || tpv.Mode < 2 || tpv.Lat == nil || tpv.Lon == nil {
+		return Fix{}, false
+	}
+	fix := Fix{Lat: *tpv.Lat, Lon: *tpv.Lon, SpeedMS: tpv.Speed}
+	if t, err := time.Parse(time.RFC3339Nano, tpv.Time); err == nil {
+		fix.UTC = t.UTC()
+	}
+	return fix, true
+}
+
+// parseNMEA parses an RMC sentence (any talker: GP, GN, GL...) with a
+// valid fix and checksum.
+func parseNMEA(line string) (Fix, bool) {
+	body, ok := checkNMEA(line)
+	if !ok {
+		return Fix{}, false
+	}
+	fields := strings.Split(body, ",")
+	if len(fields) < 8 || len(fields[0]) != 5 || fields[0][2:] != "RMC" || fields[2] != "A" {
+		return Fix{}, false
+	}
+	lat, err1 := nmeaDegrees(fields[3], fields[4], "S")
+	lon, err2 := nmeaDegrees(fields[5], fields[6], "W")
+	if err1 != nil || err2 != nil {
+		return Fix{}, false
+	}
+	knots, _ := strconv.ParseFloat(fields[7], 64) // Empty when stationary on some receivers
+	fix := Fix{Lat: lat, Lon: lon, SpeedMS: knots * knotsToMS}
+	if len(fields) > 9 {
+		fix.UTC = nmeaTime(fields[1], fields[9])
+	}
+	return fix, true
+}
+
+// nmeaTime parses RMC's hhmmss(.ss) and ddmmyy fields (zero if invalid).
+func nmeaTime(hms, dmy string) time.Time {
+	if len(hms) < 6 || len(dmy) != 6 {
+		return time.Time{}
+	}
+	t, err := time.Parse("020106 150405", dmy+" "+hms[:6])
+	if err != nil {
+		return time.Time{}
+	}
+	if len(hms) > 7 && hms[6] == '.' {
+		if frac, err := strconv.ParseFloat("0"+hms[6:], 64); err == nil {
+			t = t.Add(time.Duration(frac * float64(time.Second)))
+		}
+	}
+	return t
+}
+
+// checkNMEA strips "$" and the "*hh" checksum, verifying it.
+func checkNMEA(line string) (string, bool) {
+	if !strings.HasPrefix(line, "$") {
+		return "", false
+	}
+	body, sum, ok := strings.Cut(line[1:], "*")
+	if !ok {
+		return "", false
+	}
+	want, err := strconv.ParseUint(strings.TrimSpace(sum), 16, 8)
+	if err != nil {
+		return "", false
+	}
+	var got byte
+	for i := 0; i < len(body); i++ {
+		got ^= body[i]
+	}
+	return body, got == byte(want)
+}
+
+// nmeaDegrees converts (d)ddmm.mmmm plus hemisphere to signed degrees.
+func nmeaDegrees(value, hemisphere, negative string) (float64, error) {
+	dot := strings.IndexByte(value, '.')
+	if dot < 0 {
+		dot = len(value)
+	}
+	if dot < 3 {
+		return 0, fmt.Errorf("invalid coordinate %q", value)
+	}
+	deg, err := strconv.ParseFloat(value[:dot-2], 64)
+	if err != nil {
+		return 0, err
+	}
+	min, err := strconv.ParseFloat(value[dot-2:], 64)
+	if err != nil {
+		return 0, err
+	}
+	d := deg + min/60
+	if hemisphere == negative {
+		d = -d
+	}
+	return d, nil
+}
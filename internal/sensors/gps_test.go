@@ -0,0 +1,104 @@
+package sensors
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"testing"
+	"time"
+)
+
+func near(a, b float64) bool { return math.Abs(a-b) < 1e-4 }
+
+func TestParseNMEA(t *testing.T) {
+	fix, ok := parseNMEA("$GPRMC,123519,A,4807.038,N,01131.000,E,022.4,084.4,230394,003.1,W*6A")
+	if !ok {
+		t.Fatal("valid RMC sentence rejected")
+	}
+	if !near(fix.Lat, 48.1173) || !near(fix.Lon, 11.516667) || !near(fix.SpeedMS, 22.4*knotsToMS) {
+		t.Errorf("fix = %+v", fix)
+	}
+	if want := time.Date(1994, 3, 23, 12, 35, 19, 0, time.UTC); !fix.UTC.Equal(want) {
+		t.Errorf("UTC = %v, want %v", fix.UTC, want)
+	}
+
+	// Southern/western hemisphere, GN talker
+	body := "GNRMC,000000,A,3351.600,S,15112.600,W,0.0,0.0,010124,,"
+	var sum byte
+	for i := 0; i < len(body); i++ {
+		sum ^= body[i]
+	}
+	fix, ok = parseNMEA(fmt.Sprintf("$%s*%02X", body, sum))
+	if !ok || !near(fix.Lat, -33.86) || !near(fix.Lon, -151.21) {
+		t.Errorf("GNRMC fix = %+v, %v", fix, ok)
+	}
+	if want := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC); !fix.UTC.Equal(want) {
+		t.Errorf("GNRMC UTC = %v, want %v", fix.UTC, want)
+	}
+
+	for _, bad := range []string{
+		"$GPRMC,123519,V,4807.038,N,01131.000,E,022.4,084.4,230394,003.1,W*7D", // No fix
+		"$GPRMC,123519,A,4807.038,N,01131.000,E,022.4,084.4,230394,003.1,W*00", // Bad checksum
+		"$GPGGA,123519,4807.038,N,01131.000,E,1,08,0.9,545.4,M,46.9,M,,*47",    // Not RMC
+	} {
+		if _, ok := parseNMEA(bad); ok {
+			t.Errorf("parseNMEA(%q) should fail", bad)
+		}
+	}
+}
+
+func TestParseGPSDReport(t *testing.T) {
+	fix, ok := parseGPSDReport(`{"class":"TPV","mode":3,"lat":52.1,"lon":4.3,"speed":13.9,"time":"2025-06-01T08:30:15.500Z"}`)
+	if !ok || fix.Lat != 52.1 || fix.Lon != 4.3 || fix.SpeedMS != 13.9 {
+		t.Errorf("TPV fix = %+v, %v", fix, ok)
+	}
+	if want := time.Date(2025, 6, 1, 8, 30, 15, 500e6, time.UTC); !fix.UTC.Equal(want) {
+		t.Errorf("TPV UTC = %v, want %v", fix.UTC, want)
+	}
+	for _, bad := range []string{
+		`{"class":"TPV","mode":1}`,
+		`{"class":"SKY","mode":3,"lat":1,"lon":2}`,
+		`not json`,
+	} {
+		if _, ok := parseGPSDReport(bad); ok {
+			t.Errorf("parseGPSDReport(%q) should fail", bad)
+		}
+	}
+}
+
+func TestGPS_ReadsFromGPSD(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("listen: %v", err)
+	}
+	defer ln.Close()
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		buf := make([]byte, 64)
+		conn.Read(buf) // ?WATCH
+		fmt.Fprintln(conn, `{"class":"VERSION","release":"3.22"}`)
+		fmt.Fprintln(conn, `{"class":"TPV","mode":2,"lat":1.5,"lon":-2.5,"speed":3}`)
+		time.Sleep(time.Second)
+	}()
+
+	g := NewGPS("gpsd://" + ln.Addr().String())
+	stop := make(chan struct{})
+	defer close(stop)
+	go g.Run(stop)
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if fix, ok := g.Latest(); ok {
+			if fix.Lat != 1.5 || fix.Lon != -2.5 || fix.SpeedMS != 3 {
+				t.Errorf("fix = %+v", fix)
+			}
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("no fix received from gpsd")
+}
@@ -0,0 +1,169 @@
+package sensors
+
+import (
+	"log"
+	"math"
+	"sync/atomic"
+	"time"
+)
+
+// =============================================================================
+// G-sensor impact detection
+// =============================================================================
+// An accelerometer is polled at a fixed rate. Gravity (and the mounting
+// tilt) is tracked with a slow low-pass filter and subtracted, so only
+// the dynamic acceleration is compared with the threshold: braking and
+// cornering stay well under 1 g, a collision or a hard pothole goes far
+// above it. After an impact the detector stays quiet for a cooldown so
+// one crash is one incident.
+// =============================================================================
+
+// gravityAlpha is the low-pass weight of each new sample in the gravity
+// estimate (~1 s time constant at 50 Hz).
+const gravityAlpha = 0.02
+
+// Sample is one acceleration reading in g.
+type Sample struct {
+	X, Y, Z float64
+}
+
+// Magnitude returns the length of the acceleration vector in g.
+func (s Sample) Magnitude() float64 {
+	return math.Sqrt(s.X*s.X + s.Y*s.Y + s.Z*s.Z)
+}
+
+// Accelerometer is a source of acceleration samples.
+type Accelerometer interface {
+	Read() (Sample, error)
+	Close() error
+}
+
+// Impact is a detected impact.
+type Impact struct {
+	Time  time.Time
+	PeakG float64 // Dynamic acceleration (gravity removed) in g
+	Raw   Sample
+}
+
+// ImpactDetector flags samples whose dynamic acceleration exceeds a
+// threshold.
+type ImpactDetector struct {
+	thresholdG float64
+	cooldown   time.Duration
+
+	gravity    Sample
+	primed     bool
+	lastImpact time.Time
+}
+
+// NewImpactDetector creates a detector firing above thresholdG, at most
+// once per cooldown.
+func NewImpactDetector(thresholdG float64, cooldown time.Duration) *ImpactDetector {
+	return &ImpactDetector{thresholdG: thresholdG, cooldown: cooldown}
+}
+
+// Gravity returns the current gravity estimate (the sensor at rest reads
+// about 1 g pointing up) and whether a sample has been seen.
+func (d *ImpactDetector) Gravity() (Sample, bool) {
+	return d.gravity, d.primed
+}
+
+// Tilt returns the roll and pitch in degrees of a sensor mounted with X
+// pointing forward, Y to the left and Z up, from its gravity reading.
+// Roll is positive with the right side down, pitch with the nose up.
+func Tilt(g Sample) (rollDeg, pitchDeg float64) {
+	roll := math.Atan2(g.Y, g.Z)
+	pitch := math.Atan2(g.X, math.Hypot(g.Y, g.Z))
+	return roll * 180 / math.Pi, pitch * 180 / math.Pi
+}
+
+// Update feeds one sample and reports an impact when it crosses the
+// threshold. The first sample only seeds the gravity estimate.
+func (d *ImpactDetector) Update(s Sample, now time.Time) (Impact, bool) {
+	if !d.primed {
+		d.gravity = s
+		d.primed = true
+		return Impact{}, false
+	}
+
+	dynamic := Sample{s.X - d.gravity.X, s.Y - d.gravity.Y, s.Z - d.gravity.Z}.Magnitude()
+	if dynamic < d.thresholdG {
+		d.gravity.X += gravityAlpha * (s.X - d.gravity.X)
+		d.gravity.Y += gravityAlpha * (s.Y - d.gravity.Y)
+		d.gravity.Z += gravityAlpha * (s.Z - d.gravity.Z)
+		return Impact{}, false
+	}
+	// Spikes are not folded into gravity, so a crash doesn't skew it
+	if !d.lastImpact.IsZero() && now.Sub(d.lastImpact) < d.cooldown {
+		return Impact{}, false
+	}
+	d.lastImpact = now
+	return Impact{Time: now, PeakG: dynamic, Raw: s}, true
+}
+
+// Monitor polls an accelerometer and reports impacts.
+type Monitor struct {
+	sensor   Accelerometer
+	detector *ImpactDetector
+	interval time.Duration
+	onImpact func(Impact)
+	gravity  atomic.Pointer[Sample] // Latest gravity estimate; nil before the first sample
+}
+
+// NewMonitor creates a monitor sampling sensor sampleHz times a second.
+// onImpact runs on the monitor goroutine.
+func NewMonitor(sensor Accelerometer, detector *ImpactDetector, sampleHz int, onImpact func(Impact)) *Monitor {
+	if sampleHz < 1 {
+		sampleHz = 1
+	}
+	return &Monitor{
+		sensor:   sensor,
+		detector: detector,
+		interval: time.Second / time.Duration(sampleHz),
+		onImpact: onImpact,
+	}
+}
+
+// Gravity returns the detector's latest gravity estimate, for the
+// level display. Safe to call from any goroutine.
+func (m *Monitor) Gravity() (Sample, bool) {
+	if g := m.gravity.Load(); g != nil {
+		return *g, true
+	}
+	return Sample{}, false
+}
+
+// Run samples until stop is closed. Read errors (a loose I2C wire) are
+// logged, throttled, and sampling continues.
+func (m *Monitor) Run(stop <-chan struct{}) {
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+
+	failures := 0
+	for {
+		select {
+		case <-stop:
+			return
+		case now := <-ticker.C:
+			s, err := m.sensor.Read()
+			if err != nil {
+				if failures%100 == 0 {
+					log.Printf("[GSensor] WARNING: read failed (%d consecutive): %v", failures+1, err)
+				}
+				failures++
+				continue
+			}
+			if failures > 0 {
+				log.Printf("[GSensor] Reading again after %d failures", failures)
+				failures = 0
+			}
+			impact, ok := m.detector.Update(s, now)
+			if g, primed := m.detector.Gravity(); primed {
+				m.gravity.Store(&g)
+			}
+			if ok && m.onImpact != nil {
+				m.onImpact(impact)
+			}
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package sensors
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestImpactDetector(t *testing.T) {
+	d := NewImpactDetector(2.5, 10*time.Second)
+	start := time.Now()
+	at := func(ms int) time.Time { return start.Add(time.Duration(ms) * time.Millisecond) }
+
+	// Tilted mount: gravity mostly on Z with some Y; not an impact.
+	rest := Sample{Y: 0.3, Z: 0.95}
+	for i := 0; i < 50; i++ {
+		if _, ok := d.Update(rest, at(i*20)); ok {
+			t.Fatal("resting samples should not trigger")
+		}
+	}
+	if _, ok := d.Update(Sample{X: 1.2, Y: 0.3, Z: 0.95}, at(1000)); ok {
+		t.Error("hard braking (1.2 g) should not trigger at 2.5 g")
+	}
+
+	impact, ok := d.Update(Sample{X: -3.5, Y: 0.3, Z: 0.95}, at(1020))
+	if !ok {
+		t.Fatal("3.5 g spike should trigger")
+	}
+	if impact.PeakG < 3.4 || impact.PeakG > 3.6 {
+		t.Errorf("PeakG = %.2f, want ~3.5 (gravity removed)", impact.PeakG)
+	}
+	if _, ok := d.Update(Sample{X: 4, Y: 0.3, Z: 0.95}, at(1040)); ok {
+		t.Error("second spike inside the cooldown should not trigger")
+	}
+	if _, ok := d.Update(Sample{X: 4, Y: 0.3, Z: 0.95}, at(12000)); !ok {
+		t.Error("spike after the cooldown should trigger")
+	}
+}
+
+type fakeAccel struct {
+	samples []Sample
+	err     error
+	reads   int
+}
+
+func (f *fakeAccel) Read() (Sample, error) {
+	f.reads++
+	if f.err != nil {
+		return Sample{}, f.err
+	}
+	s := f.samples[0]
+	if len(f.samples) > 1 {
+		f.samples = f.samples[1:]
+	}
+	return s, nil
+}
+
+func (f *fakeAccel) Close() error { return nil }
+
+func TestMonitor_ReportsImpact(t *testing.T) {
+	sensor := &fakeAccel{samples: []Sample{{Z: 1}, {Z: 1}, {X: 5, Z: 1}, {Z: 1}}}
+	impacts := make(chan Impact, 1)
+	stop := make(chan struct{})
+	m := NewMonitor(sensor, NewImpactDetector(2, time.Minute), 1000, func(i Impact) { impacts <- i })
+	go m.Run(stop)
+	defer close(stop)
+
+	select {
+	case i := <-impacts:
+		if i.Raw.X != 5 {
+			t.Errorf("impact sample = %+v, want the spike", i.Raw)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("no impact reported")
+	}
+}
+
+func TestMonitor_SurvivesReadErrors(t *testing.T) {
+	sensor := &fakeAccel{err: errors.New("remote I/O error")}
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	m := NewMonitor(sensor, NewImpactDetector(2, time.Minute), 1000, nil)
+	go func() {
+		m.Run(stop)
+		close(done)
+	}()
+	time.Sleep(20 * time.Millisecond)
+	close(stop)
+	<-done
+	if sensor.reads < 2 {
+		t.Errorf("reads = %d, want sampling to continue after errors", sensor.reads)
+	}
+}
+
+func TestTilt(t *testing.T) {
+	for _, tc := range []struct {
+		g           Sample
+		roll, pitch float64
+	}{
+		{Sample{Z: 1}, 0, 0},
+		{Sample{Y: 1}, 90, 0}, // On its left side: right side down
+		{Sample{Y: -0.5, Z: 0.5}, -45, 0},
+		{Sample{X: 0.5, Z: 0.5}, 0, 45}, // Nose up
+	} {
+		roll, pitch := Tilt(tc.g)
+		if math.Abs(roll-tc.roll) > 1e-9 || math.Abs(pitch-tc.pitch) > 1e-9 {
+			t.Errorf("Tilt(%+v) = %.2f, %.2f; want %.2f, %.2f", tc.g, roll, pitch, tc.roll, tc.pitch)
+		}
+	}
+}
+
+func TestMonitor_Gravity(t *testing.T) {
+	sensor := &fakeAccel{samples: []Sample{{Y: 0.1, Z: 1}}}
+	m := NewMonitor(sensor, NewImpactDetector(2, time.Minute), 1000, nil)
+	if _, ok := m.Gravity(); ok {
+		t.Fatal("Gravity() before any sample should report false")
+	}
+	stop := make(chan struct{})
+	go m.Run(stop)
+	defer close(stop)
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if g, ok := m.Gravity(); ok {
+			if g.Y != 0.1 || g.Z != 1 {
+				t.Errorf("Gravity() = %+v, want the first sample", g)
+			}
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatal("no gravity estimate after sampling")
+}
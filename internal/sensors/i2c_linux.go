@@ -0,0 +1,25 @@
+package sensors
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"syscall"
+)
+
+// i2cSlave is the I2C_SLAVE ioctl: subsequent reads/writes on the bus
+// file address this device.
+const i2cSlave = 0x0703
+
+// openI2C opens an I2C bus device node bound to addr.
+func openI2C(bus string, addr int) (io.ReadWriteCloser, error) {
+	f, err := os.OpenFile(bus, os.O_RDWR, 0)
+	if err != nil {
+		return nil, err
+	}
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, f.Fd(), i2cSlave, uintptr(addr)); errno != 0 {
+		f.Close()
+		return nil, fmt.Errorf("select I2C address 0x%02x on %s: %w", addr, bus, errno)
+	}
+	return f, nil
+}
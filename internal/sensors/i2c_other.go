@@ -0,0 +1,13 @@
+//go:build !linux
+
+package sensors
+
+import (
+	"errors"
+	"io"
+)
+
+// openI2C is only supported on Linux.
+func openI2C(bus string, addr int) (io.ReadWriteCloser, error) {
+	return nil, errors.New("I2C sensors are only supported on Linux")
+}
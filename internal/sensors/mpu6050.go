@@ -0,0 +1,96 @@
+package sensors
+
+import (
+	"fmt"
+	"io"
+)
+
+// =============================================================================
+// MPU6050 (and MPU6500/9250 clones) over I2C
+// =============================================================================
+// The accelerometer is woken from sleep and set to its +/-8 g range (4096
+// LSB/g), enough headroom for impacts without losing low-g detail. Each
+// Read fetches the three big-endian 16-bit ACCEL_*OUT registers in one
+// burst. The I2C bus access itself is platform code (i2c_linux.go).
+// =============================================================================
+
+const (
+	DefaultMPU6050Address = 0x68 // 0x69 with AD0 pulled high
+
+	mpuRegAccelConfig = 0x1C
+	mpuRegAccelXOutH  = 0x3B
+	mpuRegPwrMgmt1    = 0x6B
+	mpuRegWhoAmI      = 0x75
+
+	mpuAccelRange8G = 0x10   // AFS_SEL=2
+	mpuLSBPerG8G    = 4096.0 // Sensitivity at +/-8 g
+)
+
+// MPU6050 is an accelerometer on an I2C bus.
+type MPU6050 struct {
+	dev io.ReadWriteCloser
+}
+
+// OpenMPU6050 opens the sensor at addr on bus (e.g. "/dev/i2c-1") and
+// configures it.
+func OpenMPU6050(bus string, addr int) (*MPU6050, error) {
+	dev, err := openI2C(bus, addr)
+	if err != nil {
+		return nil, err
+	}
+	m := &MPU6050{dev: dev}
+	if err := m.init(); err != nil {
+		dev.Close()
+		return nil, fmt.Errorf("MPU6050 at %s 0x%02x: %w", bus, addr, err)
+	}
+	return m, nil
+}
+
+func (m *MPU6050) init() error {
+	if _, err := m.readRegs(mpuRegWhoAmI, 1); err != nil {
+		return fmt.Errorf("no response: %w", err)
+	}
+	if err := m.writeReg(mpuRegPwrMgmt1, 0x00); err != nil { // Wake, internal clock
+		return err
+	}
+	return m.writeReg(mpuRegAccelConfig, mpuAccelRange8G)
+}
+
+// Read returns the current acceleration.
+func (m *MPU6050) Read() (Sample, error) {
+	raw, err := m.readRegs(mpuRegAccelXOutH, 6)
+	if err != nil {
+		return Sample{}, err
+	}
+	return decodeAccel(raw, mpuLSBPerG8G), nil
+}
+
+// Close releases the bus.
+func (m *MPU6050) Close() error {
+	return m.dev.Close()
+}
+
+func (m *MPU6050) writeReg(reg, value byte) error {
+	_, err := m.dev.Write([]byte{reg, value})
+	return err
+}
+
+// readRegs reads n consecutive registers starting at reg.
+func (m *MPU6050) readRegs(reg byte, n int) ([]byte, error) {
+	if _, err := m.dev.Write([]byte{reg}); err != nil {
+		return nil, err
+	}
+	buf := make([]byte, n)
+	if _, err := io.ReadFull(m.dev, buf); err != nil {
+		return nil, err
+	}
+	return buf, nil
+}
+
+// decodeAccel converts three big-endian int16 axis values to g.
+func decodeAccel(raw []byte, lsbPerG float64) Sample {
+	axis := func(i int) float64 {
+		return float64(int16(uint16(raw[i])<<8|uint16(raw[i+1]))) / lsbPerG
+	}
+	return Sample{X: axis(0), Y: axis(2), Z: axis(4)}
+}
@@ -0,0 +1,40 @@
+package sensors
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestDecodeAccel(t *testing.T) {
+	// X = +4096 (1 g), Y = -2048 (-0.5 g), Z = 0
+	got := decodeAccel([]byte{0x10, 0x00, 0xF8, 0x00, 0x00, 0x00}, mpuLSBPerG8G)
+	if got != (Sample{X: 1, Y: -0.5}) {
+		t.Errorf("decodeAccel = %+v", got)
+	}
+}
+
+// fakeBus records writes and serves reads from a fixed byte stream.
+type fakeBus struct {
+	written bytes.Buffer
+	reply   *bytes.Reader
+}
+
+func (f *fakeBus) Write(p []byte) (int, error) { return f.written.Write(p) }
+func (f *fakeBus) Read(p []byte) (int, error)  { return f.reply.Read(p) }
+func (f *fakeBus) Close() error                { return nil }
+
+func TestMPU6050_InitAndRead(t *testing.T) {
+	bus := &fakeBus{reply: bytes.NewReader([]byte{0x68, 0x00, 0x00, 0x00, 0x00, 0x10, 0x00})}
+	m := &MPU6050{dev: bus}
+	if err := m.init(); err != nil {
+		t.Fatalf("init: %v", err)
+	}
+	// WHO_AM_I select, wake, +/-8 g range
+	if want := []byte{0x75, 0x6B, 0x00, 0x1C, 0x10}; !bytes.Equal(bus.written.Bytes(), want) {
+		t.Errorf("init wrote % x, want % x", bus.written.Bytes(), want)
+	}
+	s, err := m.Read()
+	if err != nil || s != (Sample{Z: 1}) {
+		t.Errorf("Read = %+v, %v; want 1 g on Z", s, err)
+	}
+}
@@ -0,0 +1,42 @@
+package sensors
+
+import (
+	"math"
+	"time"
+)
+
+// =============================================================================
+// Sun position
+// =============================================================================
+// SunElevation uses the NOAA solar position approximation (declination
+// and equation of time from the fractional year), good to a fraction of
+// a degree, which is minutes around sunrise and sunset - plenty for
+// switching night mode. No time zone is needed: t is converted to UTC.
+// =============================================================================
+
+// SunsetElevation is the sun's elevation at sunrise and sunset, in
+// degrees (upper limb on the horizon, with refraction).
+const SunsetElevation = -0.833
+
+// SunElevation returns the sun's elevation in degrees above the horizon
+// at t, seen from latitude and longitude (degrees, north and east
+// positive).
+func SunElevation(t time.Time, latitude, longitude float64) float64 {
+	t = t.UTC()
+	hours := float64(t.Hour()) + float64(t.Minute())/60 + float64(t.Second())/3600
+	gamma := 2 * math.Pi / 365 * (float64(t.YearDay()-1) + (hours-12)/24)
+
+	eqTime := 229.18 * (0.000075 + 0.001868*math.Cos(gamma) - 0.032077*math.Sin(gamma) -
+		0.014615*math.Cos(2*gamma) - 0.040849*math.Sin(2*gamma)) // Minutes
+	decl := 0.006918 - 0.399912*math.Cos(gamma) + 0.070257*math.Sin(gamma) -
+		0.006758*math.Cos(2*gamma) + 0.000907*math.Sin(2*gamma) -
+		0.002697*math.Cos(3*gamma) + 0.00148*math.Sin(3*gamma) // Radians
+
+	solarMinutes := hours*60 + eqTime + 4*longitude
+	hourAngle := (solarMinutes/4 - 180) * math.Pi / 180
+	lat := latitude * math.Pi / 180
+
+	cosZenith := math.Sin(lat)*math.Sin(decl) + math.Cos(lat)*math.Cos(decl)*math.Cos(hourAngle)
+	cosZenith = math.Max(-1, math.Min(1, cosZenith))
+	return 90 - math.Acos(cosZenith)*180/math.Pi
+}
@@ -0,0 +1,34 @@
+package sensors
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestSunElevation(t *testing.T) {
+	// London (51.5 N, 0.13 W)
+	lat, lon := 51.5, -0.13
+	// Summer solstice noon: 90 - 51.5 + 23.44
+	if got := SunElevation(time.Date(2024, 6, 20, 12, 2, 0, 0, time.UTC), lat, lon); math.Abs(got-61.9) > 0.5 {
+		t.Errorf("solstice noon = %.2f, want ~61.9", got)
+	}
+	// Midnight is dark
+	if got := SunElevation(time.Date(2024, 6, 20, 0, 0, 0, 0, time.UTC), lat, lon); got >= SunsetElevation {
+		t.Errorf("midnight = %.2f, want below the horizon", got)
+	}
+	// Sunset on 2024-01-15 is 16:20 GMT: just before and after
+	if got := SunElevation(time.Date(2024, 1, 15, 16, 10, 0, 0, time.UTC), lat, lon); got <= SunsetElevation {
+		t.Errorf("10 min before sunset = %.2f, want above", got)
+	}
+	if got := SunElevation(time.Date(2024, 1, 15, 16, 30, 0, 0, time.UTC), lat, lon); got >= SunsetElevation {
+		t.Errorf("10 min after sunset = %.2f, want below", got)
+	}
+	// Time zone doesn't matter
+	tz := time.FixedZone("UTC+2", 2*3600)
+	a := SunElevation(time.Date(2024, 3, 1, 14, 0, 0, 0, tz), lat, lon)
+	b := SunElevation(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC), lat, lon)
+	if math.Abs(a-b) > 1e-9 {
+		t.Errorf("zoned %.3f != UTC %.3f", a, b)
+	}
+}
@@ -0,0 +1,300 @@
+// Package storage keeps the directories the dashboard writes to within
+// their quotas and the disk from filling up, and reads flash wear.
+package storage
+
+import (
+	"camera-dashboard-go/internal/helpers"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// =============================================================================
+// Disk space manager
+// =============================================================================
+// Each managed directory (snapshots, clips, trip reports, logs) is read
+// as a list of segments: the files of one recording, i.e. a clip's
+// .mjpeg with its .json sidecar, or a single snapshot, trip report or
+// rotated log. A check
+//
+//   1. deletes the oldest segments of every directory over its quota
+//      until it fits again
+//   2. reads the free space of each directory's filesystem; below the
+//      low-space threshold the status is Low and, with free-up on, the
+//      oldest segments of all directories on a low filesystem are
+//      deleted until the shortfall is covered
+//
+// A segment with a <name>.keep marker (written by Protect, or by hand)
+// is never deleted. Only files directly in a directory that the
+// dashboard wrote are considered: recordings (.mjpeg, .jpg, .json and
+// .keep markers) or, in the logs directory, the rotated backups of the
+// log (<log>.1, <log>.2.gz, ...), never the log being written or other
+// files that share the directory (syslog, config.ini, the binary).
+// =============================================================================
+
+// KeepExt marks a protected segment: "<segment>.keep" next to its files.
+const KeepExt = ".keep"
+
+// Dir is one managed directory.
+type Dir struct {
+	Name  string // For logs and metrics, e.g. "clips"
+	Path  string
+	Quota int64  // Bytes the directory may hold, 0 = unlimited
+	Log   string // Log file name: only its rotated backups are managed; "" = recordings
+}
+
+// segment is the files of one recording.
+type segment struct {
+	files     []string
+	size      int64
+	modTime   time.Time // Newest of its files
+	protected bool
+}
+
+// DirStatus is a directory's usage after a check.
+type DirStatus struct {
+	Name       string
+	Path       string
+	UsedBytes  int64
+	Files      int
+	FreeBytes  uint64 // Free space of its filesystem
+	TotalBytes uint64
+}
+
+// Status is the result of a check.
+type Status struct {
+	Dirs      []DirStatus
+	FreeBytes uint64   // Least free space of any managed filesystem
+	Low       bool     // FreeBytes is below the threshold
+	Deleted   []string // Files deleted by this check
+	Errors    []error
+}
+
+// Manager enforces quotas and the free-space threshold.
+type Manager struct {
+	dirs     []Dir
+	lowFree  uint64 // Bytes; 0 = no free-space check
+	freeUp   bool
+	diskFree func(path string) (free, total uint64, err error)
+}
+
+// NewManager manages dirs (ones with an empty Path are skipped). Below
+// lowFree bytes free the status is Low; with freeUp the oldest segments
+// are then deleted to get back above it.
+func NewManager(dirs []Dir, lowFree uint64, freeUp bool) *Manager {
+	m := &Manager{lowFree: lowFree, freeUp: freeUp, diskFree: helpers.DiskFree}
+	for _, d := range dirs {
+		if d.Path != "" {
+			m.dirs = append(m.dirs, d)
+		}
+	}
+	return m
+}
+
+// Check enforces the quotas and the free-space threshold once.
+func (m *Manager) Check() Status {
+	var st Status
+	segs := make([][]*segment, len(m.dirs))
+	for i, d := range m.dirs {
+		list, err := scanDir(d)
+		if err != nil {
+			st.Errors = append(st.Errors, fmt.Errorf("%s: %w", d.Name, err))
+		}
+		segs[i] = list
+		if d.Quota > 0 {
+			used := totalSize(list)
+			for _, s := range list {
+				if used <= d.Quota {
+					break
+				}
+				if m.remove(s, &st) {
+					used -= s.size
+				}
+			}
+		}
+	}
+
+	if m.lowFree > 0 {
+		var pool []*segment
+		var shortfall uint64
+		for i, d := range m.dirs {
+			free, _, err := m.diskFree(d.Path)
+			if err != nil || free >= m.lowFree {
+				continue
+			}
+			if m.lowFree-free > shortfall {
+				shortfall = m.lowFree - free
+			}
+			pool = append(pool, segs[i]...)
+		}
+		if m.freeUp && shortfall > 0 {
+			sortOldestFirst(pool)
+			var freed uint64
+			for _, s := range pool {
+				if freed >= shortfall {
+					break
+				}
+				if m.remove(s, &st) {
+					freed += uint64(s.size)
+				}
+			}
+		}
+	}
+
+	measured := false
+	for i, d := range m.dirs {
+		ds := DirStatus{Name: d.Name, Path: d.Path}
+		for _, s := range segs[i] {
+			if s.files != nil {
+				ds.UsedBytes += s.size
+				ds.Files += len(s.files)
+			}
+		}
+		free, total, err := m.diskFree(d.Path)
+		if err == nil {
+			ds.FreeBytes, ds.TotalBytes = free, total
+			if !measured || free < st.FreeBytes {
+				st.FreeBytes, measured = free, true
+			}
+			if m.lowFree > 0 && free < m.lowFree {
+				st.Low = true
+			}
+		}
+		st.Dirs = append(st.Dirs, ds)
+	}
+	return st
+}
+
+// remove deletes an unprotected segment and reports whether it is gone.
+func (m *Manager) remove(s *segment, st *Status) bool {
+	if s.protected || s.files == nil {
+		return false
+	}
+	for _, f := range s.files {
+		if err := os.Remove(f); err != nil && !os.IsNotExist(err) {
+			st.Errors = append(st.Errors, err)
+			return false
+		}
+		st.Deleted = append(st.Deleted, f)
+	}
+	s.files = nil // Deleted: no longer counted
+	return true
+}
+
+// scanDir lists the segments of d, oldest first. A missing directory
+// has none.
+func scanDir(d Dir) ([]*segment, error) {
+	entries, err := os.ReadDir(d.Path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	byKey := make(map[string]*segment)
+	var list []*segment
+	for _, e := range entries {
+		if e.IsDir() || !d.owns(e.Name()) {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue // Deleted meanwhile
+		}
+		key := segmentKey(e.Name())
+		s := byKey[key]
+		if s == nil {
+			s = &segment{}
+			byKey[key] = s
+			list = append(list, s)
+		}
+		s.files = append(s.files, filepath.Join(d.Path, e.Name()))
+		s.size += info.Size()
+		if info.ModTime().After(s.modTime) {
+			s.modTime = info.ModTime()
+		}
+		if strings.HasSuffix(e.Name(), KeepExt) {
+			s.protected = true
+		}
+	}
+	sortOldestFirst(list)
+	return list, nil
+}
+
+// owns reports whether name is a file the manager may delete in d.
+func (d Dir) owns(name string) bool {
+	if d.Log != "" {
+		return isRotatedLog(d.Log, name)
+	}
+	return isRecording(name)
+}
+
+// isRecording reports whether name has a recording extension.
+func isRecording(name string) bool {
+	switch filepath.Ext(name) {
+	case ".mjpeg", ".jpg", ".json", KeepExt:
+		return true
+	}
+	return false
+}
+
+// isRotatedLog reports whether name is a rotated backup of log:
+// <log>.N or <log>.N.gz.
+func isRotatedLog(log, name string) bool {
+	n := strings.TrimPrefix(name, log+".")
+	if n == name {
+		return false
+	}
+	n = strings.TrimSuffix(n, ".gz")
+	if n == "" {
+		return false
+	}
+	for _, c := range n {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// segmentKey is the name shared by the files of one recording: the
+// name without a recording extension, or the whole name.
+func segmentKey(name string) string {
+	if isRecording(name) {
+		return strings.TrimSuffix(name, filepath.Ext(name))
+	}
+	return name
+}
+
+func sortOldestFirst(list []*segment) {
+	sort.SliceStable(list, func(i, j int) bool { return list[i].modTime.Before(list[j].modTime) })
+}
+
+func totalSize(list []*segment) int64 {
+	var n int64
+	for _, s := range list {
+		n += s.size
+	}
+	return n
+}
+
+// Protect marks the recording path belongs to so it is never deleted.
+func Protect(path string) error {
+	dir, name := filepath.Split(path)
+	f, err := os.OpenFile(filepath.Join(dir, segmentKey(name)+KeepExt), os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// IsProtected reports whether the recording path belongs to has a .keep
+// marker.
+func IsProtected(path string) bool {
+	dir, name := filepath.Split(path)
+	_, err := os.Stat(filepath.Join(dir, segmentKey(name)+KeepExt))
+	return err == nil
+}
@@ -0,0 +1,187 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+)
+
+// writeAged writes size bytes to dir/name, modified age ago.
+func writeAged(t *testing.T, dir, name string, size int, age time.Duration) {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, make([]byte, size), 0o444); err != nil {
+		t.Fatal(err)
+	}
+	when := time.Now().Add(-age)
+	if err := os.Chtimes(path, when, when); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func listDir(t *testing.T, dir string) []string {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestSegmentKey(t *testing.T) {
+	for name, want := range map[string]string{
+		"20240101_120000.000_video0.mjpeg": "20240101_120000.000_video0",
+		"20240101_120000.000_video0.json":  "20240101_120000.000_video0",
+		"20240101_120000.000_video0.keep":  "20240101_120000.000_video0",
+		"20240101_120000.000_video2.jpg":   "20240101_120000.000_video2",
+		"camera_dashboard.log.1.gz":        "camera_dashboard.log.1.gz",
+	} {
+		if got := segmentKey(name); got != want {
+			t.Errorf("segmentKey(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestCheck_QuotaDeletesOldestUnprotected(t *testing.T) {
+	dir := t.TempDir()
+	writeAged(t, dir, "a.mjpeg", 400, 4*time.Hour) // Oldest, protected
+	writeAged(t, dir, "a.json", 100, 4*time.Hour)
+	writeAged(t, dir, "a.keep", 0, 4*time.Hour)
+	writeAged(t, dir, "b.mjpeg", 400, 3*time.Hour)
+	writeAged(t, dir, "b.json", 100, 3*time.Hour)
+	writeAged(t, dir, "c.mjpeg", 400, 2*time.Hour)
+	writeAged(t, dir, "c.json", 100, 2*time.Hour)
+	writeAged(t, dir, "d.mjpeg", 400, time.Hour)
+
+	m := NewManager([]Dir{{Name: "clips", Path: dir, Quota: 1200}}, 0, false)
+	st := m.Check()
+	if len(st.Errors) > 0 {
+		t.Fatal(st.Errors)
+	}
+	want := []string{"a.json", "a.keep", "a.mjpeg", "d.mjpeg"}
+	if got := listDir(t, dir); !reflect.DeepEqual(got, want) {
+		t.Errorf("left %q, want %q", got, want)
+	}
+	if len(st.Deleted) != 4 || st.Dirs[0].UsedBytes != 900 || st.Dirs[0].Files != 4 {
+		t.Errorf("deleted %q, used %d in %d files", st.Deleted, st.Dirs[0].UsedBytes, st.Dirs[0].Files)
+	}
+}
+
+func TestCheck_LowSpace(t *testing.T) {
+	clips, logs := t.TempDir(), t.TempDir()
+	writeAged(t, clips, "old.mjpeg", 300, 3*time.Hour)
+	writeAged(t, clips, "new.mjpeg", 300, time.Hour)
+	writeAged(t, logs, "camera_dashboard.log", 300, 5*time.Hour) // Being written
+	writeAged(t, logs, "camera_dashboard.log.1", 300, 2*time.Hour)
+	dirs := []Dir{{Name: "clips", Path: clips}, {Name: "logs", Path: logs, Log: "camera_dashboard.log"}}
+
+	free := uint64(1000)
+	fakeFree := func(string) (uint64, uint64, error) { return free, 10000, nil }
+
+	// Warning only
+	m := NewManager(dirs, 1500, false)
+	m.diskFree = fakeFree
+	if st := m.Check(); !st.Low || st.FreeBytes != 1000 || len(st.Deleted) > 0 {
+		t.Errorf("low = %v, free = %d, deleted %q", st.Low, st.FreeBytes, st.Deleted)
+	}
+
+	// Free up: 500 bytes short, the two oldest deletable files go
+	m = NewManager(dirs, 1500, true)
+	m.diskFree = fakeFree
+	st := m.Check()
+	want := []string{filepath.Join(clips, "old.mjpeg"), filepath.Join(logs, "camera_dashboard.log.1")}
+	if !reflect.DeepEqual(st.Deleted, want) {
+		t.Errorf("deleted %q, want %q", st.Deleted, want)
+	}
+
+	free = 2000
+	if st := m.Check(); st.Low || len(st.Deleted) > 0 {
+		t.Errorf("with enough space: low = %v, deleted %q", st.Low, st.Deleted)
+	}
+}
+
+func TestCheck_OnlyOwnFiles(t *testing.T) {
+	logs, clips := t.TempDir(), t.TempDir()
+	// A log directory shared with the system and the app
+	writeAged(t, logs, "syslog", 500, 6*time.Hour)
+	writeAged(t, logs, "config.ini", 500, 6*time.Hour)
+	writeAged(t, logs, "camera-dashboard", 500, 6*time.Hour)
+	writeAged(t, logs, "camera_dashboard.log", 500, 5*time.Hour) // Being written
+	writeAged(t, logs, "camera_dashboard.log.bak", 500, 5*time.Hour)
+	writeAged(t, logs, "camera_dashboard.log.2.gz", 500, 4*time.Hour)
+	writeAged(t, logs, "camera_dashboard.log.1", 500, 3*time.Hour)
+	// Recordings next to a file the dashboard didn't write
+	writeAged(t, clips, "notes.txt", 500, 6*time.Hour)
+	writeAged(t, clips, "a.mjpeg", 500, 2*time.Hour)
+
+	m := NewManager([]Dir{
+		{Name: "logs", Path: logs, Quota: 1, Log: "camera_dashboard.log"},
+		{Name: "clips", Path: clips, Quota: 1},
+	}, 0, false)
+	st := m.Check()
+	if len(st.Errors) > 0 {
+		t.Fatal(st.Errors)
+	}
+	if want := []string{"camera-dashboard", "camera_dashboard.log", "camera_dashboard.log.bak", "config.ini", "syslog"}; !reflect.DeepEqual(listDir(t, logs), want) {
+		t.Errorf("logs left %q, want %q", listDir(t, logs), want)
+	}
+	if want := []string{"notes.txt"}; !reflect.DeepEqual(listDir(t, clips), want) {
+		t.Errorf("clips left %q, want %q", listDir(t, clips), want)
+	}
+	if st.Dirs[0].UsedBytes != 0 || st.Dirs[1].UsedBytes != 0 {
+		t.Errorf("foreign files counted: %+v", st.Dirs)
+	}
+}
+
+func TestIsRotatedLog(t *testing.T) {
+	for name, want := range map[string]bool{
+		"dash.log.1":     true,
+		"dash.log.12.gz": true,
+		"dash.log":       false,
+		"dash.log.":      false,
+		"dash.log.gz":    false,
+		"dash.log.1.bak": false,
+		"dash.log.bak":   false,
+		"other.log.1":    false,
+	} {
+		if got := isRotatedLog("dash.log", name); got != want {
+			t.Errorf("isRotatedLog(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestProtect(t *testing.T) {
+	dir := t.TempDir()
+	writeAged(t, dir, "x.jpg", 100, time.Hour)
+	if err := Protect(filepath.Join(dir, "x.jpg")); err != nil {
+		t.Fatal(err)
+	}
+	if !IsProtected(filepath.Join(dir, "x.jpg")) || IsProtected(filepath.Join(dir, "y.jpg")) {
+		t.Error("IsProtected() should follow the .keep marker")
+	}
+	m := NewManager([]Dir{{Name: "snapshots", Path: dir, Quota: 1}}, 0, false)
+	if st := m.Check(); len(st.Deleted) > 0 {
+		t.Errorf("protected snapshot deleted: %q", st.Deleted)
+	}
+}
+
+func TestParseLifeTime(t *testing.T) {
+	for in, want := range map[string]int{"0x01 0x02\n": 20, "0x0a 0x03": 100, "0x0B 0x01": 110, "0x00 0x00": 0} {
+		if got, err := parseLifeTime(in); err != nil || got != want {
+			t.Errorf("parseLifeTime(%q) = %d, %v; want %d", in, got, err, want)
+		}
+	}
+	for _, bad := range []string{"", "wear", "0x0C 0x01"} {
+		if _, err := parseLifeTime(bad); err == nil {
+			t.Errorf("parseLifeTime(%q) should fail", bad)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package storage
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// =============================================================================
+// Flash wear estimate
+// =============================================================================
+// eMMC modules (and some industrial SD cards) report their wear in
+// /sys/block/<dev>/device/life_time as two hex values, one per memory
+// type: 0x01 = 0-10% of the rated erase cycles used, ... 0x0A = 90-100%,
+// 0x0B = past its rated life. Most consumer SD cards don't have the file.
+// =============================================================================
+
+// Wear returns the estimated percentage of device's rated life used
+// (the upper end of the worse of the two ranges, so 0x0A reads 100).
+func Wear(device string) (int, error) {
+	data, err := os.ReadFile(filepath.Join("/sys/block", device, "device", "life_time"))
+	if err != nil {
+		return 0, err
+	}
+	return parseLifeTime(string(data))
+}
+
+func parseLifeTime(s string) (int, error) {
+	fields := strings.Fields(s)
+	if len(fields) == 0 {
+		return 0, fmt.Errorf("empty life_time")
+	}
+	worst := 0
+	for _, f := range fields {
+		v, err := strconv.ParseUint(strings.TrimPrefix(strings.ToLower(f), "0x"), 16, 8)
+		if err != nil || v > 0x0B {
+			return 0, fmt.Errorf("invalid life_time %q", strings.TrimSpace(s))
+		}
+		if int(v) > worst {
+			worst = int(v)
+		}
+	}
+	return worst * 10, nil
+}
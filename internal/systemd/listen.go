@@ -0,0 +1,56 @@
+package systemd
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+)
+
+// =============================================================================
+// Socket activation (sd_listen_fds)
+// =============================================================================
+// A .socket unit can own a listening socket and hand it to the service
+// as file descriptor 3 onwards ($LISTEN_FDS, for $LISTEN_PID). The
+// socket then exists before the dashboard starts and survives its
+// restarts, so health checks connect even while it is coming up.
+// =============================================================================
+
+// listenFDsStart is SD_LISTEN_FDS_START, the first passed descriptor.
+const listenFDsStart = 3
+
+// Listeners returns the sockets passed by systemd socket activation (nil
+// without socket activation). The environment variables are cleared so
+// processes we start don't try to take them too.
+func Listeners() ([]net.Listener, error) {
+	pid, err := strconv.Atoi(os.Getenv("LISTEN_PID"))
+	if err != nil || pid != os.Getpid() {
+		return nil, nil
+	}
+	n, err := strconv.Atoi(os.Getenv("LISTEN_FDS"))
+	if err != nil || n <= 0 {
+		return nil, nil
+	}
+	os.Unsetenv("LISTEN_PID")
+	os.Unsetenv("LISTEN_FDS")
+	os.Unsetenv("LISTEN_FDNAMES")
+	return fileListeners(listenFDsStart, n)
+}
+
+// fileListeners wraps the n descriptors from first as listeners.
+func fileListeners(first, n int) ([]net.Listener, error) {
+	listeners := make([]net.Listener, 0, n)
+	for fd := first; fd < first+n; fd++ {
+		f := os.NewFile(uintptr(fd), "LISTEN_FD_"+strconv.Itoa(fd))
+		l, err := net.FileListener(f) // Duplicates the descriptor
+		f.Close()
+		if err != nil {
+			for _, l := range listeners {
+				l.Close()
+			}
+			return nil, fmt.Errorf("socket activation fd %d: %w", fd, err)
+		}
+		listeners = append(listeners, l)
+	}
+	return listeners, nil
+}
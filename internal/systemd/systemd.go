@@ -0,0 +1,119 @@
+// Package systemd integrates the dashboard with a systemd service:
+// readiness and status notifications, watchdog keepalives and socket
+// activation. Outside systemd every call is a silent no-op.
+package systemd
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"time"
+)
+
+// =============================================================================
+// systemd notify protocol (sd_notify)
+// =============================================================================
+// Hand-rolled equivalent of sd_notify(3): a datagram with "KEY=VALUE"
+// lines sent to the unix socket in $NOTIFY_SOCKET. With Type=notify the
+// unit counts as started only after READY=1, which the dashboard sends
+// once the first camera discovery is done; STOPPING=1 marks a clean
+// shutdown and EXTEND_TIMEOUT_USEC asks for more time while recordings
+// are flushed. With WatchdogSec= the unit is restarted unless
+// WATCHDOG=1 arrives at least every WatchdogSec.
+// =============================================================================
+
+// Notify sends state to $NOTIFY_SOCKET. Returns false (and no error)
+// if the process isn't running under a notify-enabled systemd unit.
+func Notify(state string) (bool, error) {
+	socket := os.Getenv("NOTIFY_SOCKET")
+	if socket == "" {
+		return false, nil
+	}
+	// Leading '@' means a Linux abstract socket
+	if socket[0] == '@' {
+		socket = "\x00" + socket[1:]
+	}
+
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: socket, Net: "unixgram"})
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+
+	if _, err := conn.Write([]byte(state)); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Ready reports that startup is complete, with a status line for
+// `systemctl status`.
+func Ready(status string) (bool, error) {
+	return Notify("READY=1\nSTATUS=" + status)
+}
+
+// Status updates the status line shown by `systemctl status`.
+func Status(status string) {
+	Notify("STATUS=" + status)
+}
+
+// Stopping reports that a clean shutdown has begun.
+func Stopping() {
+	Notify("STOPPING=1")
+}
+
+// ExtendTimeout asks systemd to wait d longer for the current start or
+// stop to finish.
+func ExtendTimeout(d time.Duration) {
+	Notify(fmt.Sprintf("EXTEND_TIMEOUT_USEC=%d", d.Microseconds()))
+}
+
+// Watchdog feeds the WatchdogSec= timer.
+func Watchdog() {
+	Notify("WATCHDOG=1")
+}
+
+// WatchdogInterval returns the WatchdogSec= timeout systemd expects
+// pings within, or 0 if the systemd watchdog is not enabled for this
+// process.
+func WatchdogInterval() time.Duration {
+	usec, err := strconv.ParseInt(os.Getenv("WATCHDOG_USEC"), 10, 64)
+	if err != nil || usec <= 0 {
+		return 0
+	}
+	// WATCHDOG_PID, when set, must name us (not a parent shell script)
+	if pid := os.Getenv("WATCHDOG_PID"); pid != "" && pid != strconv.Itoa(os.Getpid()) {
+		return 0
+	}
+	return time.Duration(usec) * time.Microsecond
+}
+
+// WatchdogEnabled reports whether systemd expects WATCHDOG=1 pings from
+// this process (and will kill/restart it if they stop).
+func WatchdogEnabled() bool {
+	return WatchdogInterval() > 0
+}
+
+// KeepAlive pings the systemd watchdog at half its interval while
+// healthy returns true, until stop is closed. It returns at once when
+// the systemd watchdog is off.
+func KeepAlive(stop <-chan struct{}, healthy func() bool) {
+	interval := WatchdogInterval()
+	if interval <= 0 {
+		return
+	}
+	// Ping at least twice per WatchdogSec, as sd_watchdog_enabled(3) advises
+	ticker := time.NewTicker(interval / 2)
+	defer ticker.Stop()
+	for {
+		if healthy() {
+			Watchdog()
+		}
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
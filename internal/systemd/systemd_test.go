@@ -0,0 +1,125 @@
+package systemd
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// listenNotify captures datagrams sent to a NOTIFY_SOCKET.
+func listenNotify(t *testing.T) *net.UnixConn {
+	t.Helper()
+	sock := filepath.Join(t.TempDir(), "notify.sock")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: sock, Net: "unixgram"})
+	if err != nil {
+		t.Skipf("unixgram not available: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	t.Setenv("NOTIFY_SOCKET", sock)
+	return conn
+}
+
+func receive(t *testing.T, conn *net.UnixConn) string {
+	t.Helper()
+	buf := make([]byte, 256)
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	n, _, err := conn.ReadFromUnix(buf)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	return string(buf[:n])
+}
+
+func TestNotify(t *testing.T) {
+	conn := listenNotify(t)
+	ok, err := Notify("READY=1")
+	if err != nil || !ok {
+		t.Fatalf("Notify = (%v, %v), want (true, nil)", ok, err)
+	}
+	if got := receive(t, conn); got != "READY=1" {
+		t.Errorf("received %q, want READY=1", got)
+	}
+
+	Ready("3 cameras online")
+	if got := receive(t, conn); got != "READY=1\nSTATUS=3 cameras online" {
+		t.Errorf("Ready sent %q", got)
+	}
+	ExtendTimeout(5 * time.Second)
+	if got := receive(t, conn); got != "EXTEND_TIMEOUT_USEC=5000000" {
+		t.Errorf("ExtendTimeout sent %q", got)
+	}
+}
+
+func TestNotify_NoSocket(t *testing.T) {
+	t.Setenv("NOTIFY_SOCKET", "")
+	if ok, err := Notify("READY=1"); ok || err != nil {
+		t.Errorf("Notify without socket = (%v, %v), want (false, nil)", ok, err)
+	}
+}
+
+func TestWatchdogInterval(t *testing.T) {
+	t.Setenv("WATCHDOG_USEC", "30000000")
+	t.Setenv("WATCHDOG_PID", "")
+	if got := WatchdogInterval(); got != 30*time.Second {
+		t.Errorf("interval = %v, want 30s", got)
+	}
+
+	t.Setenv("WATCHDOG_PID", strconv.Itoa(os.Getpid()+1))
+	if got := WatchdogInterval(); got != 0 {
+		t.Errorf("interval for another PID = %v, want 0", got)
+	}
+
+	t.Setenv("WATCHDOG_USEC", "")
+	if WatchdogEnabled() {
+		t.Error("watchdog should be disabled without WATCHDOG_USEC")
+	}
+}
+
+func TestKeepAlive(t *testing.T) {
+	conn := listenNotify(t)
+	t.Setenv("WATCHDOG_USEC", "100000")
+	t.Setenv("WATCHDOG_PID", "")
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		KeepAlive(stop, func() bool { return true })
+		close(done)
+	}()
+	if got := receive(t, conn); got != "WATCHDOG=1" {
+		t.Errorf("KeepAlive sent %q", got)
+	}
+	close(stop)
+	<-done
+
+	t.Setenv("WATCHDOG_USEC", "")
+	KeepAlive(nil, func() bool { return true }) // Returns at once
+}
+
+func TestListeners(t *testing.T) {
+	t.Setenv("LISTEN_PID", strconv.Itoa(os.Getpid()+1))
+	t.Setenv("LISTEN_FDS", "1")
+	if ls, err := Listeners(); ls != nil || err != nil {
+		t.Errorf("Listeners for another PID = %v, %v", ls, err)
+	}
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("tcp not available: %v", err)
+	}
+	defer l.Close()
+	f, err := l.(*net.TCPListener).File()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ls, err := fileListeners(int(f.Fd()), 1)
+	if err != nil || len(ls) != 1 {
+		t.Fatalf("fileListeners = %v, %v", ls, err)
+	}
+	defer ls[0].Close()
+	if ls[0].Addr().String() != l.Addr().String() {
+		t.Errorf("listener on %v, want %v", ls[0].Addr(), l.Addr())
+	}
+}
@@ -0,0 +1,278 @@
+package testsupport
+
+import (
+	"bytes"
+	"camera-dashboard-go/internal/camera"
+	"errors"
+	"image"
+	"image/jpeg"
+	"io"
+	"sync"
+	"time"
+)
+
+// =============================================================================
+// Fake cameras
+// =============================================================================
+// FakeBackend is a camera.Backend whose cameras live in memory. Tests
+// plug, unplug and stall them and watch discovery, hotplug, stale-frame
+// detection and restart policies react, without V4L2 or FFmpeg:
+//
+//   backend := testsupport.NewFakeBackend()
+//   front := backend.Add("fake0", "USB Fake Camera")
+//   settings.Backend = backend        // camera.Settings
+//   ...
+//   front.Stall()                     // frames stop, the stream stays open
+//   front.Unplug()                    // open streams end, Present is false
+//
+// A stream sends a few solid-grey JPEG frames in a loop at the requested
+// frame rate. While stalled, Read returns no data once per frame
+// interval, like an FFmpeg pipe with a hung camera behind it.
+// =============================================================================
+
+// fakePathPrefix starts a fake camera's DevicePath ("fake:<id>").
+const fakePathPrefix = "fake:"
+
+// FakeBackend is an in-memory camera.Backend.
+type FakeBackend struct {
+	mu      sync.Mutex
+	devices []*FakeDevice
+}
+
+// NewFakeBackend returns a backend with no cameras.
+func NewFakeBackend() *FakeBackend {
+	return &FakeBackend{}
+}
+
+// Add plugs in a new camera with device ID id.
+func (b *FakeBackend) Add(id, name string) *FakeDevice {
+	d := &FakeDevice{ID: id, Name: name, plugged: true}
+	b.mu.Lock()
+	b.devices = append(b.devices, d)
+	b.mu.Unlock()
+	return d
+}
+
+// device returns the camera at devicePath, or nil.
+func (b *FakeBackend) device(devicePath string) *FakeDevice {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, d := range b.devices {
+		if d.Path() == devicePath {
+			return d
+		}
+	}
+	return nil
+}
+
+// Discover returns the plugged-in cameras in the order they were added,
+// leaving out disabled ones and those over the camera limit.
+func (b *FakeBackend) Discover(s camera.Settings) ([]camera.Camera, error) {
+	b.mu.Lock()
+	devices := append([]*FakeDevice(nil), b.devices...)
+	b.mu.Unlock()
+
+	maxCameras := s.MaxCameras
+	if maxCameras <= 0 {
+		maxCameras = camera.DefaultMaxCameras
+	}
+	var cameras []camera.Camera
+	for _, d := range devices {
+		if !d.Plugged() || camera.IsDeviceDisabled(s.DisabledDevices, d.Path()) {
+			continue
+		}
+		if len(cameras) >= maxCameras {
+			break
+		}
+		cam := camera.Camera{
+			DeviceID:   d.ID,
+			DevicePath: d.Path(),
+			Name:       d.Name,
+			Available:  true,
+		}
+		cs := s.ForCamera(cam) // Like v4l2 discovery, the camera's own settings
+		cam.Capabilities = camera.CameraCapabilities{
+			MaxWidth:  cs.Width,
+			MaxHeight: cs.Height,
+			MaxFPS:    cs.FPS,
+			Format:    "mjpeg",
+		}
+		cameras = append(cameras, cam)
+	}
+	return cameras, nil
+}
+
+// Open starts a stream from the camera, which must be plugged in.
+func (b *FakeBackend) Open(cam camera.Camera, width, height, fps int) (io.ReadCloser, error) {
+	d := b.device(cam.DevicePath)
+	if d == nil {
+		return nil, errors.New("no such fake camera: " + cam.DevicePath)
+	}
+	stream, err := d.open(width, height, fps)
+	if err != nil {
+		return nil, err
+	}
+	return stream, nil
+}
+
+// Present reports whether the camera at devicePath is plugged in.
+func (b *FakeBackend) Present(devicePath string) bool {
+	d := b.device(devicePath)
+	return d != nil && d.Plugged()
+}
+
+// FakeDevice is one camera of a FakeBackend.
+type FakeDevice struct {
+	ID, Name string
+
+	mu      sync.Mutex
+	plugged bool
+	stalled bool
+	opens   int
+	streams []*fakeStream
+}
+
+// Path returns the camera's DevicePath.
+func (d *FakeDevice) Path() string {
+	return fakePathPrefix + d.ID
+}
+
+// Plugged reports whether the camera is plugged in.
+func (d *FakeDevice) Plugged() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.plugged
+}
+
+// Unplug removes the camera: open streams end and Open fails until Plug.
+func (d *FakeDevice) Unplug() {
+	d.mu.Lock()
+	d.plugged = false
+	streams := d.streams
+	d.streams = nil
+	d.mu.Unlock()
+	for _, s := range streams {
+		s.Close()
+	}
+}
+
+// Plug plugs the camera back in.
+func (d *FakeDevice) Plug() {
+	d.mu.Lock()
+	d.plugged = true
+	d.mu.Unlock()
+}
+
+// Stall stops frames on open and new streams without ending them.
+func (d *FakeDevice) Stall() {
+	d.mu.Lock()
+	d.stalled = true
+	d.mu.Unlock()
+}
+
+// Resume lets frames flow again after Stall.
+func (d *FakeDevice) Resume() {
+	d.mu.Lock()
+	d.stalled = false
+	d.mu.Unlock()
+}
+
+// Opens returns how many streams have been opened, restarts included.
+func (d *FakeDevice) Opens() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.opens
+}
+
+func (d *FakeDevice) open(width, height, fps int) (*fakeStream, error) {
+	frames, err := fakeFrames(width, height)
+	if err != nil {
+		return nil, err
+	}
+	if fps <= 0 {
+		fps = camera.DefaultFPS
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if !d.plugged {
+		return nil, errors.New("fake camera " + d.ID + " is unplugged")
+	}
+	s := &fakeStream{device: d, frames: frames, interval: time.Second / time.Duration(fps), done: make(chan struct{})}
+	d.opens++
+	d.streams = append(d.streams, s)
+	return s, nil
+}
+
+func (d *FakeDevice) isStalled() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.stalled
+}
+
+// fakeFrames encodes a short loop of solid-grey frames, so consecutive
+// frames differ.
+func fakeFrames(width, height int) ([][]byte, error) {
+	if width <= 0 || height <= 0 {
+		width, height = camera.DefaultWidth, camera.DefaultHeight
+	}
+	var frames [][]byte
+	for _, shade := range []uint8{64, 96, 128, 160} {
+		img := image.NewGray(image.Rect(0, 0, width, height))
+		for i := range img.Pix {
+			img.Pix[i] = shade
+		}
+		var buf bytes.Buffer
+		if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 75}); err != nil {
+			return nil, err
+		}
+		frames = append(frames, buf.Bytes())
+	}
+	return frames, nil
+}
+
+// fakeStream is an open fake camera stream.
+type fakeStream struct {
+	device   *FakeDevice
+	frames   [][]byte
+	interval time.Duration
+	next     int
+	due      time.Time
+	pending  []byte
+
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func (s *fakeStream) Read(p []byte) (int, error) {
+	if len(s.pending) == 0 {
+		wait := time.Until(s.due)
+		if wait <= 0 {
+			s.due = time.Now() // First frame, or fell behind: don't burst
+			wait = 0
+		}
+		select {
+		case <-s.done:
+			return 0, io.EOF
+		case <-time.After(wait):
+		}
+		s.due = s.due.Add(s.interval)
+		if s.device.isStalled() {
+			return 0, nil
+		}
+		s.pending = s.frames[s.next]
+		s.next = (s.next + 1) % len(s.frames)
+	}
+	select {
+	case <-s.done:
+		return 0, io.EOF
+	default:
+	}
+	n := copy(p, s.pending)
+	s.pending = s.pending[n:]
+	return n, nil
+}
+
+func (s *fakeStream) Close() error {
+	s.closeOnce.Do(func() { close(s.done) })
+	return nil
+}
@@ -0,0 +1,147 @@
+package testsupport
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// =============================================================================
+// v4l2loopback cameras
+// =============================================================================
+// For the real path (v4l2-ctl discovery, FFmpeg capture, /dev hotplug)
+// Loopback creates v4l2loopback devices and feeds each one an FFmpeg test
+// pattern in YUYV, so capture them with Format "yuyv":
+//
+//   lb, err := testsupport.StartLoopback([]int{40, 42}, 320, 240, 15)
+//   defer lb.Close()
+//   lb.Stall("/dev/video40")          // feeder stops, the device stays
+//   lb.Remove("/dev/video40")         // device node goes away
+//
+// Needs root, the v4l2loopback kernel module and ffmpeg;
+// LoopbackAvailable says why not, for t.Skip. The devices are labelled
+// "USB Loopback Camera" so discovery takes them for USB cameras. Pick
+// device numbers above the real cameras'.
+// =============================================================================
+
+// loopbackLabel names the devices; discovery needs "USB" in the name.
+const loopbackLabel = "USB Loopback Camera"
+
+// LoopbackAvailable returns why loopback cameras can't be created here,
+// or nil.
+func LoopbackAvailable() error {
+	if os.Geteuid() != 0 {
+		return errors.New("v4l2loopback needs root")
+	}
+	for _, tool := range []string{"modprobe", "ffmpeg", "v4l2-ctl"} {
+		if _, err := exec.LookPath(tool); err != nil {
+			return fmt.Errorf("%s not installed", tool)
+		}
+	}
+	if err := exec.Command("modinfo", "v4l2loopback").Run(); err != nil {
+		return errors.New("v4l2loopback kernel module not available")
+	}
+	if _, err := os.Stat("/sys/module/v4l2loopback"); err == nil {
+		return errors.New("v4l2loopback already loaded; unload it first")
+	}
+	return nil
+}
+
+// Loopback is a set of v4l2loopback cameras fed with test patterns.
+type Loopback struct {
+	Devices []string // /dev/videoN, in the order asked for
+
+	width, height, fps int
+
+	mu      sync.Mutex
+	feeders map[string]*exec.Cmd
+}
+
+// StartLoopback loads v4l2loopback with the device numbers and starts
+// feeding every device.
+func StartLoopback(numbers []int, width, height, fps int) (*Loopback, error) {
+	if len(numbers) == 0 {
+		return nil, errors.New("no loopback devices asked for")
+	}
+	nrs := make([]string, len(numbers))
+	labels := make([]string, len(numbers))
+	l := &Loopback{width: width, height: height, fps: fps, feeders: make(map[string]*exec.Cmd)}
+	for i, n := range numbers {
+		nrs[i] = strconv.Itoa(n)
+		labels[i] = fmt.Sprintf("%s %d", loopbackLabel, n)
+		l.Devices = append(l.Devices, fmt.Sprintf("/dev/video%d", n))
+	}
+	out, err := exec.Command("modprobe", "v4l2loopback",
+		"devices="+strconv.Itoa(len(numbers)),
+		"video_nr="+strings.Join(nrs, ","),
+		"card_label="+strings.Join(labels, ","),
+		"exclusive_caps=1").CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("modprobe v4l2loopback: %v: %s", err, strings.TrimSpace(string(out)))
+	}
+	for _, dev := range l.Devices {
+		if err := l.Feed(dev); err != nil {
+			l.Close()
+			return nil, err
+		}
+	}
+	return l, nil
+}
+
+// Feed starts (or restarts) the test pattern on dev.
+func (l *Loopback) Feed(dev string) error {
+	l.Stall(dev)
+	cmd := exec.Command("ffmpeg", "-hide_banner", "-loglevel", "error",
+		"-re", "-f", "lavfi",
+		"-i", fmt.Sprintf("testsrc=size=%dx%d:rate=%d", l.width, l.height, l.fps),
+		"-pix_fmt", "yuyv422", "-f", "v4l2", dev)
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("feeding %s: %w", dev, err)
+	}
+	l.mu.Lock()
+	l.feeders[dev] = cmd
+	l.mu.Unlock()
+	// With exclusive_caps the device turns into a capture device once
+	// the feeder has set its format
+	time.Sleep(500 * time.Millisecond)
+	return nil
+}
+
+// Stall stops feeding dev; the device stays and delivers no frames.
+func (l *Loopback) Stall(dev string) {
+	l.mu.Lock()
+	cmd := l.feeders[dev]
+	delete(l.feeders, dev)
+	l.mu.Unlock()
+	if cmd != nil && cmd.Process != nil {
+		cmd.Process.Kill()
+		cmd.Wait()
+	}
+}
+
+// Remove deletes dev like unplugging a camera (needs v4l2loopback-ctl).
+func (l *Loopback) Remove(dev string) error {
+	l.Stall(dev)
+	out, err := exec.Command("v4l2loopback-ctl", "delete", dev).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("v4l2loopback-ctl delete %s: %v: %s", dev, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// Close stops the feeders and unloads v4l2loopback.
+func (l *Loopback) Close() error {
+	for _, dev := range l.Devices {
+		l.Stall(dev)
+	}
+	out, err := exec.Command("modprobe", "-r", "v4l2loopback").CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("modprobe -r v4l2loopback: %v: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
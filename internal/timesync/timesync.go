@@ -0,0 +1,109 @@
+// Package timesync keeps the dashboard's idea of the time of day correct
+// on vehicles that boot without network or a battery-backed RTC.
+package timesync
+
+import (
+	"sync"
+	"time"
+)
+
+// =============================================================================
+// Recording clock
+// =============================================================================
+// A Pi without RTC boots with the time of its last shutdown (fake-hwclock)
+// or 1970, and systemd-timesyncd steps it whenever a network turns up.
+// Timestamps used for ages and windows (frame staleness, restart policy)
+// already run on Go's monotonic clock and never see the step. The Clock
+// does the same for times people read (recording names, sidecars, the
+// replay label): it counts monotonic time from when it was created and
+// adds it to a base, which starts as the system time then and is moved
+// once a trusted source reports the real time:
+//
+//   gps  UTC of a valid RMC sentence or gpsd TPV report, taken at the
+//        local receipt time so serial latency isn't counted twice
+//   ntp  the system clock, once systemd-timesyncd marks it synchronized
+//
+// Later reports only move the base if it has drifted by more than
+// MaxDrift, so GPS jitter doesn't renumber recordings.
+// =============================================================================
+
+// MaxDrift is how far a source may disagree before the clock is moved.
+const MaxDrift = 2 * time.Second
+
+// MinValid rejects source times before this (receivers without a fix
+// report 1980 or a GPS week rollover date).
+var MinValid = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// Clock converts monotonic timestamps to the time of day.
+type Clock struct {
+	mu     sync.Mutex
+	anchor time.Time // time.Now() at creation, with its monotonic reading
+	base   time.Time // Time of day at anchor (no monotonic reading)
+	source string    // "" until synced
+	now    func() time.Time
+}
+
+// New returns a clock starting from the system time.
+func New() *Clock {
+	return newClock(time.Now)
+}
+
+func newClock(now func() time.Time) *Clock {
+	anchor := now()
+	return &Clock{anchor: anchor, base: anchor.Round(0), now: now}
+}
+
+// At returns the time of day for t, a time.Now() value (or one derived
+// from it) taken in this process. Times without a monotonic reading,
+// the zero time and a nil clock give t unchanged.
+func (c *Clock) At(t time.Time) time.Time {
+	if c == nil || !hasMonotonic(t) {
+		return t
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.base.Add(t.Sub(c.anchor))
+}
+
+// hasMonotonic reports whether t carries a monotonic clock reading
+// (Round(0) strips it).
+func hasMonotonic(t time.Time) bool {
+	return t != t.Round(0)
+}
+
+// Now returns the current time of day.
+func (c *Clock) Now() time.Time {
+	if c == nil {
+		return time.Now()
+	}
+	return c.At(c.now())
+}
+
+// Sync reports that the time of day was ref at the local moment at (a
+// time.Now() value). It returns how far the clock moved, and false if
+// it was left alone: ref is implausible or within MaxDrift of the
+// clock after a previous sync.
+func (c *Clock) Sync(ref, at time.Time, source string) (time.Duration, bool) {
+	if c == nil || ref.Before(MinValid) {
+		return 0, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	step := ref.Sub(c.base.Add(at.Sub(c.anchor)))
+	if c.source != "" && step > -MaxDrift && step < MaxDrift {
+		return 0, false
+	}
+	c.base = c.base.Add(step)
+	c.source = source
+	return step, true
+}
+
+// Source returns what the clock was last synced from, or "" if never.
+func (c *Clock) Source() string {
+	if c == nil {
+		return ""
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.source
+}
@@ -0,0 +1,70 @@
+package timesync
+
+import (
+	"testing"
+	"time"
+)
+
+func TestClock_UnsyncedFollowsMonotonic(t *testing.T) {
+	anchor := time.Now()
+	c := newClock(func() time.Time { return anchor })
+	later := anchor.Add(90 * time.Second)
+	if got, want := c.At(later), anchor.Round(0).Add(90*time.Second); !got.Equal(want) {
+		t.Errorf("At = %v, want %v", got, want)
+	}
+	// Wall-only times (parsed, from files) pass through
+	wall := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	if got := c.At(wall); !got.Equal(wall) {
+		t.Errorf("At(wall) = %v, want unchanged", got)
+	}
+	if c.Source() != "" {
+		t.Errorf("Source = %q before sync", c.Source())
+	}
+}
+
+func TestClock_Sync(t *testing.T) {
+	anchor := time.Now()
+	c := newClock(func() time.Time { return anchor })
+
+	// Booted believing it is 1970-ish; GPS says otherwise 10s in
+	at := anchor.Add(10 * time.Second)
+	ref := time.Date(2025, 6, 1, 8, 30, 0, 0, time.UTC)
+	step, ok := c.Sync(ref, at, "gps")
+	if !ok || step == 0 {
+		t.Fatalf("Sync = %v, %v", step, ok)
+	}
+	if c.Source() != "gps" {
+		t.Errorf("Source = %q", c.Source())
+	}
+	if got := c.At(at.Add(5 * time.Second)); !got.Equal(ref.Add(5 * time.Second)) {
+		t.Errorf("At after sync = %v, want %v", got, ref.Add(5*time.Second))
+	}
+	// Earlier timestamps from this run move too
+	if got := c.At(anchor); !got.Equal(ref.Add(-10 * time.Second)) {
+		t.Errorf("At(anchor) = %v", got)
+	}
+
+	// Jitter below MaxDrift is ignored
+	if _, ok := c.Sync(ref.Add(21*time.Second), at.Add(20*time.Second), "gps"); ok {
+		t.Error("1s jitter moved the clock")
+	}
+	// A real step is applied
+	if step, ok := c.Sync(ref.Add(30*time.Second), at.Add(20*time.Second), "ntp"); !ok || step != 10*time.Second {
+		t.Errorf("step = %v, %v; want 10s", step, ok)
+	}
+}
+
+func TestClock_SyncRejectsImplausible(t *testing.T) {
+	c := New()
+	if _, ok := c.Sync(time.Date(1980, 1, 6, 0, 0, 0, 0, time.UTC), time.Now(), "gps"); ok {
+		t.Error("1980 accepted")
+	}
+	var nilClock *Clock
+	if _, ok := nilClock.Sync(time.Now(), time.Now(), "gps"); ok {
+		t.Error("nil clock synced")
+	}
+	now := time.Now()
+	if got := nilClock.At(now); got != now {
+		t.Error("nil clock changed a time")
+	}
+}
@@ -0,0 +1,182 @@
+package ui
+
+import (
+	"camera-dashboard-go/internal/sensors"
+	"fmt"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"image/color"
+	"log"
+	"math"
+	"sync/atomic"
+)
+
+// =============================================================================
+// Aim assist (installation)
+// =============================================================================
+// The "Aim assist" check in the camera controls panel draws a center
+// crosshair and a rule-of-thirds grid over the fullscreen picture, to
+// line a camera up while mounting it. With [gsensor] running, a level
+// line through the center shows the G-sensor's roll (green within
+// aimLevelTolerance, amber otherwise) and the roll and pitch in degrees,
+// so the camera bracket can be set against a level car. The sensor is
+// assumed to be mounted X forward, Y left, Z up (see sensors.Tilt).
+//
+// The overlay stays on for every fullscreen camera until unchecked;
+// driving mode turns it off.
+// =============================================================================
+
+const (
+	aimCrossFraction  = 0.06 // Crosshair arm length, fraction of the shorter side
+	aimLevelFraction  = 0.30 // Level line half length, fraction of the width
+	aimLevelTolerance = 1.0  // Degrees of roll still shown as level
+	aimLabelTop       = 56   // Level readout below the fullscreen buttons
+)
+
+var (
+	aimGridColor  = color.NRGBA{255, 255, 255, 110}
+	aimCrossColor = color.NRGBA{255, 255, 255, 220}
+	aimLevelOK    = color.NRGBA{80, 220, 100, 230}
+	aimLevelOff   = color.NRGBA{255, 180, 0, 230}
+)
+
+// aimLevel is a G-sensor tilt reading in degrees.
+type aimLevel struct {
+	roll, pitch float64
+}
+
+// aimSegment is one overlay line.
+type aimSegment struct {
+	from, to fyne.Position
+}
+
+// aimSegments returns the overlay lines for a picture of size: the two
+// vertical and two horizontal thirds, the crosshair's horizontal and
+// vertical arms and, when level is known, the level line through the
+// center tilted by its roll.
+func aimSegments(size fyne.Size, level *aimLevel) []aimSegment {
+	w, h := size.Width, size.Height
+	cx, cy := w/2, h/2
+	arm := float32(math.Min(float64(w), float64(h))) * aimCrossFraction
+	segments := []aimSegment{
+		{fyne.NewPos(w/3, 0), fyne.NewPos(w/3, h)},
+		{fyne.NewPos(2*w/3, 0), fyne.NewPos(2*w/3, h)},
+		{fyne.NewPos(0, h/3), fyne.NewPos(w, h/3)},
+		{fyne.NewPos(0, 2*h/3), fyne.NewPos(w, 2*h/3)},
+		{fyne.NewPos(cx-arm, cy), fyne.NewPos(cx+arm, cy)},
+		{fyne.NewPos(cx, cy-arm), fyne.NewPos(cx, cy+arm)},
+	}
+	if level != nil {
+		// Screen y grows downwards, so a positive roll (right side down)
+		// lowers the right end
+		r := level.roll * math.Pi / 180
+		dx := float32(math.Cos(r)) * w * aimLevelFraction
+		dy := float32(math.Sin(r)) * w * aimLevelFraction
+		segments = append(segments, aimSegment{fyne.NewPos(cx-dx, cy-dy), fyne.NewPos(cx+dx, cy+dy)})
+	}
+	return segments
+}
+
+// aimOverlay is the fullscreen aim assist layer.
+type aimOverlay struct {
+	content *fyne.Container
+	lines   []*canvas.Line // Thirds and crosshair, in aimSegments order
+	horizon *canvas.Line
+	label   *canvas.Text
+	level   atomic.Pointer[aimLevel] // nil = no G-sensor reading
+}
+
+func newAimOverlay() *aimOverlay {
+	o := &aimOverlay{}
+	var objects []fyne.CanvasObject
+	for i := 0; i < 6; i++ {
+		line := canvas.NewLine(aimGridColor)
+		line.StrokeWidth = 1
+		if i >= 4 {
+			line.StrokeColor = aimCrossColor
+			line.StrokeWidth = 2
+		}
+		o.lines = append(o.lines, line)
+		objects = append(objects, line)
+	}
+	o.horizon = canvas.NewLine(aimLevelOK)
+	o.horizon.StrokeWidth = 3
+	o.horizon.Hide()
+	o.label = canvas.NewText("", aimLevelOK)
+	o.label.TextStyle = fyne.TextStyle{Bold: true}
+	o.label.Alignment = fyne.TextAlignCenter
+	o.label.Hide()
+	objects = append(objects, o.horizon, o.label)
+
+	o.content = container.New(o, objects...)
+	o.content.Hide()
+	return o
+}
+
+func (o *aimOverlay) MinSize(objects []fyne.CanvasObject) fyne.Size {
+	return fyne.NewSize(0, 0)
+}
+
+func (o *aimOverlay) Layout(objects []fyne.CanvasObject, size fyne.Size) {
+	level := o.level.Load()
+	segments := aimSegments(size, level)
+	for i, line := range o.lines {
+		line.Position1, line.Position2 = segments[i].from, segments[i].to
+	}
+	if level == nil {
+		return
+	}
+	o.horizon.Position1, o.horizon.Position2 = segments[len(segments)-1].from, segments[len(segments)-1].to
+	labelSize := o.label.MinSize()
+	o.label.Move(fyne.NewPos((size.Width-labelSize.Width)/2, aimLabelTop))
+	o.label.Resize(labelSize)
+}
+
+// setLevel shows a G-sensor reading (nil hides the level line).
+func (o *aimOverlay) setLevel(level *aimLevel) {
+	o.level.Store(level)
+	if level == nil {
+		o.horizon.Hide()
+		o.label.Hide()
+	} else {
+		c := aimLevelOff
+		if math.Abs(level.roll) < aimLevelTolerance {
+			c = aimLevelOK
+		}
+		o.horizon.StrokeColor = c
+		o.label.Color = c
+		o.label.Text = fmt.Sprintf("Roll %+.1f°  Pitch %+.1f°", level.roll, level.pitch)
+		o.horizon.Show()
+		o.label.Show()
+	}
+	o.content.Refresh()
+}
+
+// setAimAssist shows or hides the aim assist overlay.
+func (a *App) setAimAssist(on bool) {
+	if a.aimOverlay == nil || a.aimAssist.Swap(on) == on {
+		return
+	}
+	if on {
+		log.Println("[UI] Aim assist on")
+		a.updateAimLevel()
+		a.aimOverlay.content.Show()
+	} else {
+		log.Println("[UI] Aim assist off")
+		a.aimOverlay.content.Hide()
+	}
+}
+
+// updateAimLevel refreshes the level line from the G-sensor (fullscreen
+// loop, while aim assist is on).
+func (a *App) updateAimLevel() {
+	var level *aimLevel
+	if m := a.gsensorMonitor.Load(); m != nil {
+		if g, ok := m.Gravity(); ok {
+			roll, pitch := sensors.Tilt(g)
+			level = &aimLevel{roll: roll, pitch: pitch}
+		}
+	}
+	a.aimOverlay.setLevel(level)
+}
@@ -0,0 +1,39 @@
+package ui
+
+import (
+	"fyne.io/fyne/v2"
+	"math"
+	"testing"
+)
+
+func TestAimSegments(t *testing.T) {
+	size := fyne.NewSize(900, 600)
+	segments := aimSegments(size, nil)
+	if len(segments) != 6 {
+		t.Fatalf("got %d segments without a level, want 6 (thirds + crosshair)", len(segments))
+	}
+	if segments[0].from.X != 300 || segments[1].from.X != 600 || segments[2].from.Y != 200 || segments[3].from.Y != 400 {
+		t.Errorf("thirds at x=%v,%v y=%v,%v; want 300,600 and 200,400",
+			segments[0].from.X, segments[1].from.X, segments[2].from.Y, segments[3].from.Y)
+	}
+	if cross := segments[4]; cross.from != fyne.NewPos(414, 300) || cross.to != fyne.NewPos(486, 300) {
+		t.Errorf("crosshair arm %v-%v, want (414,300)-(486,300)", cross.from, cross.to)
+	}
+
+	level := aimSegments(size, &aimLevel{})
+	if len(level) != 7 {
+		t.Fatalf("got %d segments with a level, want 7", len(level))
+	}
+	if line := level[6]; line.from != fyne.NewPos(180, 300) || line.to != fyne.NewPos(720, 300) {
+		t.Errorf("level line at 0 deg %v-%v, want (180,300)-(720,300)", line.from, line.to)
+	}
+
+	// Right side down lowers the right end (screen y grows downwards)
+	tilted := aimSegments(size, &aimLevel{roll: 10})[6]
+	if tilted.to.Y <= 300 || tilted.from.Y >= 300 {
+		t.Errorf("roll +10: level line %v-%v, want the right end lower", tilted.from, tilted.to)
+	}
+	if angle := math.Atan2(float64(tilted.to.Y-tilted.from.Y), float64(tilted.to.X-tilted.from.X)) * 180 / math.Pi; math.Abs(angle-10) > 0.01 {
+		t.Errorf("roll +10: line angle %.2f deg, want 10", angle)
+	}
+}
@@ -0,0 +1,196 @@
+package ui
+
+import (
+	"camera-dashboard-go/internal/alert"
+	"camera-dashboard-go/internal/camera"
+	"camera-dashboard-go/internal/input"
+	"log"
+	"strings"
+	"time"
+)
+
+// =============================================================================
+// Alert sounds ([alerts])
+// =============================================================================
+// Tones (see internal/alert) for things the driver should hear, not
+// just see:
+//   disconnect     a camera was unplugged (hotplug)
+//   reverse_stale  the rear camera has no live picture while reversing:
+//                  repeated every reverseStaleRepeat until it recovers
+//                  or the car leaves reverse
+//   over_temp      the CPU reached over_temp_c; again only after it has
+//                  cooled overTempRearm below it
+// Reversing comes from a GPIO line wired to the reverse light signal
+// (reverse_gpio, through an optocoupler or divider) on the [input] chip;
+// without one there is no reverse_stale alert. The rear camera is the
+// one whose [names] name is rear_camera, or that rear_camera matches
+// like a [transform] key. Alerts play one at a time; one arriving while
+// another plays is dropped. "Mute alert sounds" on the settings panel
+// (or [alerts] muted) silences them all.
+// =============================================================================
+
+const (
+	alertDisconnect   = "disconnect"
+	alertReverseStale = "reverse_stale"
+	alertOverTemp     = "over_temp"
+
+	alertCheckInterval = 500 * time.Millisecond
+	reverseStaleRepeat = 3 * time.Second
+	overTempRearm      = 5.0 // °C
+)
+
+// startAlerts opens the sound output and the reverse signal and starts
+// the alert checks.
+func (a *App) startAlerts() {
+	if a.alertCh == nil {
+		return
+	}
+	tones := make(map[string][]alert.Tone)
+	for kind, spec := range map[string]string{
+		alertDisconnect:   a.cfg.AlertDisconnectTones,
+		alertReverseStale: a.cfg.AlertReverseStaleTones,
+		alertOverTemp:     a.cfg.AlertOverTempTones,
+	} {
+		t, err := alert.ParseTones(spec)
+		if err != nil {
+			log.Printf("[Alert] WARNING: %s_tones: %v, alert off", kind, err)
+			continue
+		}
+		tones[kind] = t
+	}
+	player := alert.NewPlayer(a.cfg.AlertBackend, a.cfg.AlertDevice, a.cfg.AlertVolume)
+	log.Printf("[Alert] Alert sounds via %s (muted=%v)", a.cfg.AlertBackend, a.alertsMuted.Load())
+
+	go func() {
+		failing := false
+		for {
+			select {
+			case <-a.ctx.Done():
+				return
+			case kind := <-a.alertCh:
+				t, ok := tones[kind]
+				if !ok || a.alertsMuted.Load() {
+					continue
+				}
+				err := player.Play(t)
+				if err != nil && !failing {
+					log.Printf("[Alert] WARNING: playing %s alert: %v", kind, err)
+				}
+				failing = err != nil
+			}
+		}
+	}()
+
+	var reverse input.Switch
+	if a.cfg.AlertReverseGPIO >= 0 {
+		sw, err := input.OpenGPIOSwitch(a.cfg.InputChip, a.cfg.AlertReverseGPIO, a.cfg.AlertReverseActiveHigh)
+		if err != nil {
+			log.Printf("[Alert] WARNING: reverse signal: %v", err)
+		} else {
+			reverse = sw
+		}
+	}
+	go a.runAlertChecks(reverse)
+}
+
+// runAlertChecks watches the reverse signal, the rear camera and the
+// CPU temperature.
+func (a *App) runAlertChecks(reverse input.Switch) {
+	ticker := time.NewTicker(alertCheckInterval)
+	defer ticker.Stop()
+	if reverse != nil {
+		defer reverse.Close()
+	}
+	var reversing, hot, readFailed bool
+	var lastStale time.Time
+	for {
+		select {
+		case <-a.ctx.Done():
+			return
+		case now := <-ticker.C:
+			if reverse != nil {
+				active, err := reverse.Active()
+				if err != nil && !readFailed {
+					log.Printf("[Alert] WARNING: reverse signal: %v", err)
+				}
+				readFailed = err != nil
+				if err == nil && active != reversing {
+					reversing = active
+					log.Printf("[Alert] Reverse gear: %v", reversing)
+				}
+			}
+			if reversing && now.Sub(lastStale) >= reverseStaleRepeat {
+				if rear := a.rearCameraIndex(); rear >= 0 {
+					if health, _ := a.cameraHealth(rear, now); health != slotOnline {
+						log.Printf("[Alert] Reversing without a live picture from camera %s", a.cameraTag(rear))
+						a.alert(alertReverseStale)
+						lastStale = now
+					}
+				}
+			}
+
+			if pc := a.perfController.Load(); a.cfg.AlertOverTempC > 0 && pc != nil {
+				var fire bool
+				hot, fire = overTempState(hot, pc.GetTemperature(), a.cfg.AlertOverTempC)
+				if fire {
+					log.Printf("[Alert] CPU at %.1f°C (limit %.0f°C)", pc.GetTemperature(), a.cfg.AlertOverTempC)
+					a.alert(alertOverTemp)
+				}
+			}
+		}
+	}
+}
+
+// overTempState returns whether the CPU counts as over temperature at
+// tempC, and whether that just started (fire the alert).
+func overTempState(hot bool, tempC, limitC float64) (nowHot, fire bool) {
+	switch {
+	case !hot && tempC >= limitC:
+		return true, true
+	case hot && tempC < limitC-overTempRearm:
+		return false, false
+	}
+	return hot, false
+}
+
+// rearCameraIndex returns the camera [alerts] rear_camera names, or -1.
+func (a *App) rearCameraIndex() int {
+	a.frameLock.RLock()
+	cams := a.cameras
+	a.frameLock.RUnlock()
+	return findRearCamera(a.cfg.AlertRearCamera, cams, a.cameraName)
+}
+
+// findRearCamera looks up entry as a camera name (case-insensitive),
+// then as a camera match.
+func findRearCamera(entry string, cams []camera.Camera, name func(int) string) int {
+	for i := range cams {
+		if n := name(i); n != "" && strings.EqualFold(n, entry) {
+			return i
+		}
+	}
+	for i, cam := range cams {
+		if camera.MatchesCamera(entry, cam) {
+			return i
+		}
+	}
+	return -1
+}
+
+// alert plays an alert sound unless muted or one is already playing.
+func (a *App) alert(kind string) {
+	if a.alertCh == nil || a.alertsMuted.Load() {
+		return
+	}
+	select {
+	case a.alertCh <- kind:
+	default:
+	}
+}
+
+// setAlertsMuted mutes or unmutes all alert sounds.
+func (a *App) setAlertsMuted(muted bool) {
+	if a.alertsMuted.Swap(muted) != muted {
+		log.Printf("[Alert] Alert sounds muted: %v", muted)
+	}
+}
@@ -0,0 +1,40 @@
+package ui
+
+import (
+	"camera-dashboard-go/internal/camera"
+	"testing"
+)
+
+func TestOverTempState(t *testing.T) {
+	hot := false
+	var fired int
+	for _, temp := range []float64{70, 80.5, 82, 77, 81, 74, 80} {
+		var fire bool
+		hot, fire = overTempState(hot, temp, 80)
+		if fire {
+			fired++
+		}
+	}
+	// 80.5 fires; 77 and 81 stay hot; 74 re-arms; 80 fires again
+	if fired != 2 || !hot {
+		t.Errorf("fired %d times (hot=%v), want 2 (true)", fired, hot)
+	}
+}
+
+func TestFindRearCamera(t *testing.T) {
+	cams := []camera.Camera{
+		{DeviceID: "video0", DevicePath: "/dev/video0"},
+		{DeviceID: "video2", DevicePath: "/dev/video2"},
+	}
+	names := []string{"Front", "Rear"}
+	name := func(i int) string { return names[i] }
+	if got := findRearCamera("rear", cams, name); got != 1 {
+		t.Errorf("by name = %d, want 1", got)
+	}
+	if got := findRearCamera("/dev/video0", cams, name); got != 0 {
+		t.Errorf("by device path = %d, want 0", got)
+	}
+	if got := findRearCamera("rear", cams, func(int) string { return "" }); got != -1 {
+		t.Errorf("unnamed = %d, want -1", got)
+	}
+}
@@ -2,21 +2,31 @@ package ui
 
 import (
 	"camera-dashboard-go/internal/camera"
+	"camera-dashboard-go/internal/clock"
 	"camera-dashboard-go/internal/config"
 	"camera-dashboard-go/internal/helpers"
+	"camera-dashboard-go/internal/mqtt"
 	"camera-dashboard-go/internal/perf"
+	"camera-dashboard-go/internal/power"
+	"camera-dashboard-go/internal/sensors"
+	"camera-dashboard-go/internal/systemd"
+	"camera-dashboard-go/internal/timesync"
+	"camera-dashboard-go/internal/watchdog"
+	"context"
 	"fmt"
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/app"
 	"fyne.io/fyne/v2/canvas"
 	"fyne.io/fyne/v2/container"
 	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
 	"fyne.io/fyne/v2/widget"
 	"image"
 	"image/color"
 	"log"
+	"net/http"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strings"
 	"sync"
@@ -32,11 +42,14 @@ const defaultReconnectDebounce = 3 * time.Second
 type App struct {
 	fyneApp     fyne.App
 	window      fyne.Window
-	manager     *camera.Manager
+	manager     atomic.Pointer[camera.Manager] // nil until cameras start; swapped by hotplug and soft restarts
 	cameras     []camera.Camera
 	cfg         *config.Config
+	cfgMu       sync.RWMutex // Held by config reloads while they write cfg; read reloadable fields under it
 	cameraSlots int
 
+	configWatcher *config.Watcher // Set by WatchConfig; reloads after a profile switch (see profiles.go)
+
 	// Grid positions: index 0 is settings, index 1..N are camera slots.
 	// Each entry value is: -1 = settings, >=0 = camera index.
 	gridSlots []int
@@ -47,37 +60,105 @@ type App struct {
 	cameraWidgets []*TappableImage // References to camera TappableImage widgets
 	cameraStatus  []bool           // true = connected, false = disconnected
 	lastFrameRead []uint64         // Last frame timestamp read from each buffer
-	frameLock     sync.RWMutex     // Protects cameras, cameraFrames, cameraStatus, lastFrameTime
+	frameLock     sync.RWMutex     // Protects cameras, cameraFrames, cameraStatus, lastFrameTime, shownFrameAt, slotRecovery
 
 	// All grid widgets (for highlighting during swap). Index 0 is settings.
-	gridWidgets []Highlightable
+	gridWidgets    []Highlightable
+	settingsWidget *TappableSettings // nil when the health tile replaces it
+	healthTile     *HealthTile       // [display] health_tile (nil = off)
+	stripSettings  *widget.Button    // Corner settings button in the strip layout (nil otherwise)
 
 	// UI state
 	swapMode          bool
 	swapSourceSlot    int // Grid position (0..len(gridWidgets)-1)
+	keyFocus          int // Grid position with the keyboard focus, -1 = none (see keyboard.go)
 	isFullscreen      atomic.Bool
 	fullscreenSlot    int
 	fullscreenImg     *canvas.Image
 	fullscreenWidget  *TappableImage
 	fullscreenContent *fyne.Container
-	fullscreenStopCh  chan struct{} // Stops the fullscreen update goroutine
-	fullscreenMu      sync.Mutex    // Protects fullscreen state transitions
+	fullscreenLoop    *loopGroup // Fullscreen update loop, under fullscreenMu
+	fullscreenMu      sync.Mutex // Protects fullscreen state transitions
 	gridContent       *fyne.Container
+	gridBackground    *canvas.Rectangle
 	grid              *fyne.Container
+	settingsPanel     *settingsPanel
+	controlsPanel     *controlsPanel // Image controls over fullscreen
+	fullscreenAdjust  *widget.Button // Opens controlsPanel
+	fullscreenClip    *widget.Button // Saves a clip of the fullscreen camera (nil when clips are off)
+	replayBar         *replayBar     // Instant replay scrub bar (nil when [replay] is off)
+	pinLock           *pinLock       // [lock] PIN (nil = no lock; see lock.go)
+	lockScreen        *lockScreen    // PIN keypad overlay
+
+	// Goroutine lifecycle (see lifecycle.go)
+	ctx          context.Context    // Root context, cancelled by cleanup
+	cancel       context.CancelFunc // Cancels ctx
+	refreshLoops *loopGroup         // Camera refresh or render loop
+	captureLoops *loopGroup         // Hot-plug, stale and health loops; replaced by a soft restart (see restart.go)
+	captureMu    sync.Mutex         // Protects captureLoops
+	restarting   atomic.Bool        // Soft restart in progress
+	restarts     atomic.Uint64      // Soft restarts done (debug endpoint counter)
 
 	// Hot-plug detection
-	hotplugStopCh      chan struct{}
 	reinitInProgress   bool // Prevents concurrent reinitializations
 	reinitLock         sync.Mutex
 	lastDisconnectTime []time.Time // Per-camera debounce tracking
 	failedNewDevice    map[string]time.Time
-	cleanupOnce        sync.Once // Prevents double close of hotplugStopCh
+	cleanupOnce        sync.Once // Cleanup runs once
 
 	// Stale frame detection + bounded auto-restart
-	lastFrameTime   []time.Time   // When each camera last produced a frame
-	restartEvents   [][]time.Time // Sliding window of restart timestamps
-	lastRestartTime []time.Time   // Last restart timestamp per camera
-	restartLimitHit []bool        // Whether restart limit was reached
+	lastFrameTime   []time.Time     // When each camera last produced a frame
+	shownFrameAt    []time.Time     // Capture time of the frame on screen (see sync.go)
+	restartPolicies []restartPolicy // Per-camera restart history (see restart_policy.go), under restartMu
+	restartMu       sync.Mutex
+	slotRecovery    []recoveryStatus // What the supervisor is doing per slot (see recovery.go)
+
+	// Correlated USB failures, stale-detection goroutine only (see usbincident.go)
+	staleCorr     *staleCorrelator
+	lastHubCycle  time.Time
+	hubCycleUntil time.Time // Cameras in the incident wait for the hub until then
+
+	// Camera power rails, set once camera init has switched them on (see power.go)
+	powerRails atomic.Pointer[power.Controller]
+
+	// Driving mode: camera feeds only (see driving.go)
+	drivingMode atomic.Bool
+
+	// Picture-in-picture overlays in fullscreen (see pip.go)
+	pipEnabled atomic.Bool
+	pipImages  []*canvas.Image // One per camera slot
+	pipLayout  *pipLayout
+	pipOverlay *fyne.Container
+
+	// Aim assist overlay in fullscreen (see aim.go)
+	aimAssist      atomic.Bool
+	aimOverlay     *aimOverlay
+	gsensorMonitor atomic.Pointer[sensors.Monitor] // nil unless [gsensor] is running
+
+	// Mirror-replacement mode (see mirror.go)
+	mirrorMode   atomic.Bool
+	mirrorDimmed atomic.Bool // Rear picture dimmed for a dark scene
+	mirrorDim    mirrorDimmer
+
+	// Camera shown alone in fullscreen, -1 = all on screen (see offscreen.go)
+	onScreenOnly atomic.Int32
+
+	// Startup layout preset (see layout.go)
+	startupLayoutName string
+	pendingFullscreen atomic.Int32 // Camera index to open fullscreen once discovered; -1 = none
+
+	// Per-camera fisheye correction (see dewarp.go)
+	slotDewarpers atomic.Pointer[[]*dewarper]
+	dewarpBufs    []image.Image // Reusable dewarp output (one per camera slot)
+	dewarpFSBuf   image.Image   // Reusable dewarp output for fullscreen
+
+	// Per-camera mirror/flip/rotate (see transform.go)
+	slotTransforms atomic.Pointer[[]frameTransform]
+	transformBufs  []image.Image // Reusable transform output (one per camera slot)
+	transformFSBuf image.Image   // Reusable transform output for fullscreen
+
+	// [names] per camera index (see names.go)
+	slotNames atomic.Pointer[[]string]
 
 	// Night mode
 	nightModeEnabled atomic.Bool
@@ -88,21 +169,115 @@ type App struct {
 	brightnessPercent atomic.Int32
 	brightnessBufs    []*image.RGBA // Reusable buffers for brightness filter (per camera slot)
 	brightnessFSBuf   *image.RGBA   // Reusable buffer for fullscreen brightness filter
+	freezeFSBuf       *image.RGBA   // Reusable buffer for the frozen fullscreen picture
 
 	// Performance management
-	perfController *perf.AdaptiveController
+	perfController atomic.Pointer[perf.AdaptiveController] // Swapped by soft restarts
+
+	// Remote status/commands (nil when [mqtt] is disabled)
+	mqttClient atomic.Pointer[mqtt.Client] // Swapped by soft restarts
+
+	// Liveness supervision (nil when [watchdog] is disabled)
+	watchdog    *watchdog.Watchdog
+	uiHeartbeat watchdog.Heartbeat // Beaten by the camera refresh loop
+
+	pendingWrites atomic.Int32 // Snapshots/clips being written (see systemd.go)
+
+	healthServer *http.Server // [health] http_addr endpoint (see healthserver.go)
+	debugServer  *http.Server // [health] debug_addr endpoint (see debugserver.go)
+
+	// GPS (nil unless [gps] source is set; see gps.go)
+	gps        *sensors.GPS
+	gpsOverlay *fyne.Container // nil when headless or [gps] overlay is off
+	gpsText    *canvas.Text
+
+	toasts *toastLayer // nil when headless or [display] toast_seconds = 0 (see toast.go)
+
+	// Alert sounds (see alerts.go)
+	alertCh     chan string // nil unless [alerts] is enabled
+	alertsMuted atomic.Bool
+
+	// Disk space (see diskspace.go)
+	diskFree atomic.Int64 // Least free bytes where recordings/logs go, -1 = unknown
+	diskLow  atomic.Bool
+
+	stageMu sync.Mutex // Serializes flushes of [endurance] recordings (see endurance.go)
+
+	// Elapsed time for stale detection, restart policies, hotplug debounce
+	// and the perf controller (clock.System; a clock.Fake in tests)
+	clk clock.Clock
+
+	// Time of day (see timesync.go)
+	clock    *timesync.Clock
+	renameMu sync.Mutex
+	unsynced []unsyncedRecording // Recordings named before the clock was synced
+
+	overlaysMuted atomic.Bool // Info badges and GPS overlay hidden (see tileinfo.go)
+
+	sysfsMissingOnce sync.Once // Hotplug warns once when sysfs isn't mounted
+
+	// Soak-test fault injection (nil unless [soak] is enabled)
+	faults atomic.Pointer[camera.FaultInjector] // Swapped by soft restarts
+
+	// Replaces discovery, FFmpeg and /dev polling (integration tests); nil = real cameras
+	captureBackend camera.Backend
+
+	trip *tripRecorder // Reliability report of this run (see tripreport.go)
+
+	slotSignalLost []bool // Per slot, last reported by updateSignalLost; stale ticker only
+
+	// Freeze indicator (see freeze.go); refresh loop only
+	slotFrozen []bool
+	freezeBufs []*image.RGBA
+
+	// Headless mode: no Fyne app or window; Start blocks on doneCh instead
+	// of the Fyne event loop (see headless.go)
+	headless bool
+	doneCh   chan struct{}
+	quitOnce sync.Once
+
+	// Fyne-less display (nil = none; see render.go)
+	render RenderBackend
 }
 
 // Highlightable interface for widgets that can be highlighted during swap
 type Highlightable interface {
 	SetHighlight(on bool)
+	SetFocused(on bool)
 }
 
 // NewApp creates a new camera dashboard application
 func NewApp(cfg *config.Config) *App {
+	a := newApp(cfg)
+
+	a.fyneApp = app.New()
+	if a.nightModeEnabled.Load() {
+		a.applyNightChrome(true) // Fyne's widgets; ours are built in night colors
+	}
+	a.window = a.fyneApp.NewWindow("Camera Dashboard - Go")
+	a.window.Resize(fyne.NewSize(800, 480))
+	a.window.SetFullScreen(true)
+
+	// Create camera images
+	bgColor := activeTheme().Tile
+	for i := 0; i < a.cameraSlots; i++ {
+		placeholder := createColoredImage(400, 240, bgColor)
+		a.cameraFrames[i] = placeholder
+		a.cameraImages[i] = canvas.NewImageFromImage(placeholder)
+		a.cameraImages[i].FillMode = canvas.ImageFillStretch // Fill entire cell, no black bars
+	}
+
+	return a
+}
+
+// newApp builds the display-independent state shared by NewApp and
+// NewHeadlessApp.
+func newApp(cfg *config.Config) *App {
 	if cfg == nil {
 		cfg = config.DefaultConfig()
 	}
+	setNightPalette(cfg.NightGain, cfg.NightGamma, cfg.NightTint)
+	applyTheme(cfg)
 	slots := cfg.CameraSlotCount
 	if slots < 1 {
 		slots = 1
@@ -111,51 +286,67 @@ func NewApp(cfg *config.Config) *App {
 		slots = 8
 	}
 
-	fyneApp := app.New()
-	window := fyneApp.NewWindow("Camera Dashboard - Go")
-
-	window.Resize(fyne.NewSize(800, 480))
-	window.SetFullScreen(true)
-
 	a := &App{
-		fyneApp:         fyneApp,
-		window:          window,
 		cfg:             cfg,
 		cameraSlots:     slots,
 		swapSourceSlot:  -1,
-		hotplugStopCh:   make(chan struct{}),
+		keyFocus:        -1,
 		failedNewDevice: make(map[string]time.Time),
+		doneCh:          make(chan struct{}),
+		trip:            newTripRecorder(slots, time.Now()),
+		clk:             clock.System,
+		clock:           timesync.New(),
+		pinLock:         newPINLock(cfg),
 	}
+	a.ctx, a.cancel = context.WithCancel(context.Background())
 	a.brightnessPercent.Store(defaultBrightnessPercent)
+	a.nightModeEnabled.Store(cfg.NightMode)
+	nightChrome.Store(cfg.NightMode)
+	a.drivingMode.Store(cfg.DrivingMode)
+	a.pendingFullscreen.Store(-1)
+	a.onScreenOnly.Store(-1)
+	a.alertsMuted.Store(cfg.AlertsMuted)
+	a.diskFree.Store(-1)
+	if cfg.AlertsEnabled {
+		a.alertCh = make(chan string) // Unbuffered: an alert is dropped while another plays
+	}
+	if isBrightnessPreset(cfg.BrightnessPercent) {
+		a.brightnessPercent.Store(int32(cfg.BrightnessPercent))
+	}
 
 	totalSlots := slots + 1 // settings + camera slots
+	if cfg.HealthTile == "extra" {
+		totalSlots++
+	}
 	a.gridSlots = make([]int, totalSlots)
 	a.gridWidgets = make([]Highlightable, totalSlots)
 	a.cameraImages = make([]*canvas.Image, slots)
 	a.cameraFrames = make([]image.Image, slots)
 	a.cameraWidgets = make([]*TappableImage, slots)
+	a.slotSignalLost = make([]bool, slots)
+	a.slotFrozen = make([]bool, slots)
+	a.freezeBufs = make([]*image.RGBA, slots)
 	a.cameraStatus = make([]bool, slots)
 	a.lastFrameRead = make([]uint64, slots)
 	a.lastDisconnectTime = make([]time.Time, slots)
 	a.lastFrameTime = make([]time.Time, slots)
-	a.restartEvents = make([][]time.Time, slots)
-	a.lastRestartTime = make([]time.Time, slots)
-	a.restartLimitHit = make([]bool, slots)
+	a.shownFrameAt = make([]time.Time, slots)
+	a.restartPolicies = make([]restartPolicy, slots)
+	a.slotRecovery = make([]recoveryStatus, slots)
+	a.dewarpBufs = make([]image.Image, slots)
+	a.transformBufs = make([]image.Image, slots)
 	a.nightModeBufs = make([]*image.RGBA, slots)
 	a.brightnessBufs = make([]*image.RGBA, slots)
 
 	a.gridSlots[0] = -1 // Settings
+	if cfg.HealthTile == "replace" {
+		a.gridSlots[0] = healthSlot
+	}
 	for i := 0; i < slots; i++ {
 		a.gridSlots[i+1] = i
 	}
-
-	// Create camera images
-	bgColor := color.RGBA{25, 25, 25, 255}
-	for i := 0; i < slots; i++ {
-		placeholder := createColoredImage(400, 240, bgColor)
-		a.cameraFrames[i] = placeholder
-		a.cameraImages[i] = canvas.NewImageFromImage(placeholder)
-		a.cameraImages[i].FillMode = canvas.ImageFillStretch // Fill entire cell, no black bars
+	if cfg.HealthTile == "extra" {
+		a.gridSlots[totalSlots-1] = healthSlot
 	}
 
 	return a
@@ -202,24 +393,29 @@ func (a *App) effectiveSlots() int {
 }
 
 func (a *App) currentUIFPS() int {
+	a.cfgMu.RLock()
 	base := a.cfg.UIFPS
+	dynamic := a.cfg.DynamicFPSEnabled
+	baseCapture := a.cfg.CaptureFPS
+	minFPS := a.cfg.MinDynamicUIFPS
+	a.cfgMu.RUnlock()
 	if base <= 0 {
 		base = 20
 	}
 
-	if a.perfController == nil || !a.cfg.DynamicFPSEnabled {
+	pc := a.perfController.Load()
+	if pc == nil || !dynamic {
 		return base
 	}
 
-	curCapture := a.perfController.GetCurrentFPS()
-	baseCapture := a.cfg.CaptureFPS
+	curCapture := pc.GetCurrentFPS()
 	if baseCapture <= 0 {
 		baseCapture = 1
 	}
 
 	scaled := int(float64(base) * float64(curCapture) / float64(baseCapture))
-	if scaled < a.cfg.MinDynamicUIFPS {
-		scaled = a.cfg.MinDynamicUIFPS
+	if scaled < minFPS {
+		scaled = minFPS
 	}
 	if scaled > base {
 		scaled = base
@@ -228,13 +424,31 @@ func (a *App) currentUIFPS() int {
 }
 
 func (a *App) Start() {
+	if a.headless {
+		a.startHeadless()
+		return
+	}
 	a.setupUI()
 	a.window.Show()
-	go a.initializeCamerasAsync()
+	a.startCapture()
 	a.startCameraRefresh()
-	go a.startHotplugDetection()
-	go a.startStaleFrameDetection()
-	go a.startHealthLogging()
+	go a.startTripRecorder()
+	a.startHealthServer()
+	a.startDebugServer()
+	a.startGSensor()
+	a.startGPS()
+	a.startWatchdog()
+	a.startSystemdKeepAlive()
+	a.startAutoNight()
+	a.startBattery()
+	a.startTileInfo()
+	a.startInput()
+	a.startHealthTile()
+	a.startAlerts()
+	a.startDiskManager()
+	a.startEndurance()
+	a.startUpload()
+	a.startTimeSync()
 	a.fyneApp.Run()
 }
 
@@ -244,15 +458,39 @@ type TappableImage struct {
 	image           *canvas.Image
 	bg              *canvas.Rectangle
 	border          *canvas.Rectangle
+	outline         color.Color // Border color when not highlighted
 	disconnectLabel *canvas.Text
+	detailLabel     *canvas.Text      // What recovery is doing (see recovery.go)
+	dim             *canvas.Rectangle // Darkens the last frame while the signal is lost
+	freezeBadge     *fyne.Container   // FROZEN badge, top right (see freeze.go)
+	freezeLabel     *canvas.Text      // "FROZEN 2.3s"
+	restartButton   *widget.Button    // Manual restart on disconnected tiles (nil = none)
+	restartable     bool              // Slot has a camera to restart
 	onTap           func()
 	onLongTap       func()
 	pressStart      time.Time
 	longPressTimer  *time.Timer
 	longPressFired  bool
-	tapHandled      bool // Prevents double-firing from MouseUp + Tapped
+	tapHandled      bool                 // Prevents double-firing from MouseUp + Tapped
+	onSwipe         func(swipeDirection) // nil = drags are ignored (see gesture.go)
+	dragDX, dragDY  float32              // Movement since MouseDown
+	swiping         bool                 // Moved past swipeSlop
+	onDoubleTap     func()               // nil = taps fire at once (see gesture.go)
+	onSecondaryTap  func()               // Two-finger tap / right click; nil = onLongTap
+	tapTimer        *time.Timer          // Pending single tap while waiting for a second one
+	infoBadge       *fyne.Container      // Camera info overlay, top left (see tileinfo.go)
+	infoName        *canvas.Text
+	infoStats       *canvas.Text
+	infoOn          bool            // Info overlay toggled on for this tile
+	infoMuted       bool            // All overlays muted
+	nameBadge       *fyne.Container // Camera name, top center (see names.go)
+	nameLabel       *canvas.Text
+	name            string
 	highlighted     bool
+	focused         bool // Keyboard focus (see keyboard.go)
 	disconnected    bool
+	signalLost      bool          // Worker has no picture (see signallost.go)
+	frozenFor       time.Duration // Picture unchanged this long (0 = live)
 	mu              sync.Mutex
 }
 
@@ -261,6 +499,7 @@ func NewTappableImage(img *canvas.Image, bgColor color.Color, onTap, onLongTap f
 		image:     img,
 		bg:        canvas.NewRectangle(bgColor),
 		border:    canvas.NewRectangle(color.Transparent),
+		outline:   color.Transparent,
 		onTap:     onTap,
 		onLongTap: onLongTap,
 	}
@@ -268,19 +507,51 @@ func NewTappableImage(img *canvas.Image, bgColor color.Color, onTap, onLongTap f
 	t.border.StrokeColor = color.Transparent
 
 	// Create disconnected label (hidden by default)
-	t.disconnectLabel = canvas.NewText("Disconnected", color.RGBA{180, 180, 180, 255})
+	t.disconnectLabel = canvas.NewText("Disconnected", activeTheme().DisconnectedText)
 	t.disconnectLabel.TextSize = 18
 	t.disconnectLabel.Alignment = fyne.TextAlignCenter
 	t.disconnectLabel.Hidden = true
+	t.detailLabel = canvas.NewText("", activeTheme().DetailText)
+	t.detailLabel.TextSize = 13
+	t.detailLabel.Alignment = fyne.TextAlignCenter
+	t.detailLabel.Hidden = true
+	t.dim = canvas.NewRectangle(signalLostDim)
+	t.dim.Hidden = true
+	t.freezeLabel = canvas.NewText("", freezeColor)
+	t.freezeLabel.TextSize = 16
+	t.freezeLabel.TextStyle = fyne.TextStyle{Bold: true, Monospace: true}
+	t.freezeBadge = container.NewStack(canvas.NewRectangle(freezeBadgeBg), container.NewPadded(t.freezeLabel))
+	t.freezeBadge.Hide()
+	t.infoName = canvas.NewText("", infoTextColor)
+	t.infoName.TextSize = 13
+	t.infoName.TextStyle = fyne.TextStyle{Bold: true}
+	t.infoStats = canvas.NewText("", infoTextColor)
+	t.infoStats.TextSize = 12
+	t.infoStats.TextStyle = fyne.TextStyle{Monospace: true}
+	t.infoBadge = container.NewStack(canvas.NewRectangle(freezeBadgeBg),
+		container.NewPadded(container.NewVBox(t.infoName, t.infoStats)))
+	t.infoBadge.Hide()
+	t.nameLabel = canvas.NewText("", infoTextColor)
+	t.nameLabel.TextSize = 13
+	t.nameLabel.TextStyle = fyne.TextStyle{Bold: true}
+	t.nameBadge = container.NewStack(canvas.NewRectangle(nameLabelBg), container.NewPadded(t.nameLabel))
+	t.nameBadge.Hide()
 
 	t.ExtendBaseWidget(t)
 	return t
 }
 
 func (t *TappableImage) CreateRenderer() fyne.WidgetRenderer {
-	// Stack: bg, image, disconnected label centered, border on top
-	labelContainer := container.NewCenter(t.disconnectLabel)
-	c := container.NewStack(t.bg, t.image, labelContainer, t.border)
+	// Stack: bg, image, disconnected labels centered, info badge top
+	// left, name top center, freeze badge top right, border on top
+	labels := container.NewVBox(t.disconnectLabel, t.detailLabel)
+	if t.restartButton != nil {
+		labels.Add(container.NewCenter(t.restartButton))
+	}
+	labelContainer := container.NewCenter(labels)
+	badge := container.NewVBox(container.NewHBox(t.infoBadge, layout.NewSpacer(), t.freezeBadge))
+	name := container.NewVBox(container.NewHBox(layout.NewSpacer(), t.nameBadge, layout.NewSpacer()))
+	c := container.NewStack(t.bg, t.image, t.dim, labelContainer, name, badge, t.border)
 	return widget.NewSimpleRenderer(c)
 }
 
@@ -289,31 +560,168 @@ func (t *TappableImage) SetHighlight(on bool) {
 	t.mu.Lock()
 	t.highlighted = on
 	t.mu.Unlock()
+	t.updateBorder()
+}
 
-	if on {
-		t.border.StrokeColor = color.RGBA{255, 200, 0, 255} // Yellow border
-	} else {
-		t.border.StrokeColor = color.Transparent
-	}
+// SetFocused sets the keyboard focus outline (see keyboard.go).
+func (t *TappableImage) SetFocused(on bool) {
+	t.mu.Lock()
+	t.focused = on
+	t.mu.Unlock()
+	t.updateBorder()
+}
+
+// updateBorder draws the swap highlight, the keyboard focus or the
+// outline.
+func (t *TappableImage) updateBorder() {
+	t.mu.Lock()
+	c := tileBorderColor(t.highlighted, t.focused, t.outline)
+	t.mu.Unlock()
+	t.border.StrokeColor = c
 	t.border.Refresh()
 }
 
+// SetTheme recolors the tile (night mode switches the palette).
+func (t *TappableImage) SetTheme(th uiTheme) {
+	t.bg.FillColor = th.Tile
+	t.bg.Refresh()
+	t.detailLabel.Color = th.DetailText
+	t.SetOutline(th.Border) // Also redraws a highlight or focus in the new colors
+	t.updateOverlay()
+}
+
+// SetOutline sets the border color shown while neither highlighted nor
+// focused (grid tiles use the theme's border).
+func (t *TappableImage) SetOutline(c color.Color) {
+	t.mu.Lock()
+	t.outline = c
+	t.mu.Unlock()
+	t.updateBorder()
+}
+
 // SetDisconnected shows or hides the "Disconnected" label
 func (t *TappableImage) SetDisconnected(disconnected bool) {
 	t.mu.Lock()
 	t.disconnected = disconnected
 	t.mu.Unlock()
+	t.updateOverlay()
+}
 
-	if disconnected {
-		t.disconnectLabel.Hidden = false
-		// Show dark placeholder image
-		t.image.Hidden = true
+// SetSignalLost shows or hides the "SIGNAL LOST" banner over the dimmed
+// last frame.
+func (t *TappableImage) SetSignalLost(lost bool) {
+	t.mu.Lock()
+	changed := t.signalLost != lost
+	t.signalLost = lost
+	t.mu.Unlock()
+	if changed {
+		t.updateOverlay()
+	}
+}
+
+// SetFrozen shows the FROZEN badge with how long the picture has been
+// unchanged (0 hides it). The badge gives way to the disconnected and
+// signal lost overlays.
+func (t *TappableImage) SetFrozen(age time.Duration) {
+	t.mu.Lock()
+	changed := (t.frozenFor == 0) != (age == 0)
+	t.frozenFor = age
+	t.mu.Unlock()
+	if age > 0 {
+		if text := freezeLabel(age); text != t.freezeLabel.Text {
+			t.freezeLabel.Text = text
+			t.freezeLabel.Refresh()
+		}
+	}
+	if changed {
+		t.updateOverlay()
+	}
+}
+
+// updateOverlay renders the disconnected / signal lost state. A lost
+// signal keeps the last frame visible but dimmed, so it can't pass for a
+// live picture; a plain disconnect shows the dark placeholder.
+func (t *TappableImage) updateOverlay() {
+	t.mu.Lock()
+	disconnected, lost, frozen := t.disconnected, t.signalLost, t.frozenFor > 0
+	t.mu.Unlock()
+
+	if lost {
+		t.disconnectLabel.Text = "SIGNAL LOST"
+		t.disconnectLabel.Color = signalLostColor
+		t.disconnectLabel.TextStyle = fyne.TextStyle{Bold: true}
+	} else {
+		t.disconnectLabel.Text = "Disconnected"
+		t.disconnectLabel.Color = activeTheme().DisconnectedText
+		t.disconnectLabel.TextStyle = fyne.TextStyle{}
+	}
+	overlay := disconnected || lost
+	t.disconnectLabel.Hidden = !overlay
+	t.detailLabel.Hidden = !overlay || t.detailLabel.Text == ""
+	t.dim.Hidden = !lost
+	t.image.Hidden = disconnected && !lost
+	if frozen && !overlay {
+		t.freezeBadge.Show()
 	} else {
-		t.disconnectLabel.Hidden = true
-		t.image.Hidden = false
+		t.freezeBadge.Hide()
 	}
 	t.disconnectLabel.Refresh()
+	t.detailLabel.Refresh()
+	t.dim.Refresh()
 	t.image.Refresh()
+	t.updateRestartButton()
+}
+
+// SetOnRestart adds a Restart button to the disconnected overlay. Call
+// before the widget is first shown.
+func (t *TappableImage) SetOnRestart(onRestart func()) {
+	t.restartButton = widget.NewButtonWithIcon("Restart", theme.ViewRefreshIcon(), onRestart)
+	t.restartButton.Importance = widget.LowImportance
+	t.updateRestartButton()
+}
+
+// SetRestartable shows the Restart button only on slots with a camera.
+func (t *TappableImage) SetRestartable(restartable bool) {
+	t.mu.Lock()
+	changed := t.restartable != restartable
+	t.restartable = restartable
+	t.mu.Unlock()
+	if changed {
+		t.updateRestartButton()
+	}
+}
+
+// updateRestartButton shows the Restart button on disconnected,
+// restartable tiles.
+func (t *TappableImage) updateRestartButton() {
+	if t.restartButton == nil {
+		return
+	}
+	t.mu.Lock()
+	show := (t.disconnected || t.signalLost) && t.restartable
+	t.mu.Unlock()
+	if show {
+		t.restartButton.Show()
+	} else {
+		t.restartButton.Hide()
+	}
+}
+
+// SetDisconnectDetail sets the line under "Disconnected" (recovery
+// status); shown only while the tile is disconnected or has lost its
+// signal.
+func (t *TappableImage) SetDisconnectDetail(text string) {
+	t.mu.Lock()
+	overlay := t.disconnected || t.signalLost
+	t.mu.Unlock()
+
+	hidden := text == "" || !overlay
+	if t.detailLabel.Text == text && t.detailLabel.Hidden == hidden {
+		return
+	}
+	t.detailLabel.Text = text
+	t.detailLabel.Hidden = hidden
+	t.detailLabel.Refresh()
 }
 
 // IsDisconnected returns whether this camera slot is disconnected
@@ -329,6 +737,7 @@ func (t *TappableImage) MouseDown(ev *desktop.MouseEvent) {
 	t.pressStart = time.Now()
 	t.longPressFired = false
 	t.tapHandled = false
+	t.dragDX, t.dragDY, t.swiping = 0, 0, false
 
 	// Cancel any existing timer
 	if t.longPressTimer != nil {
@@ -367,9 +776,7 @@ func (t *TappableImage) MouseUp(ev *desktop.MouseEvent) {
 	// If long press wasn't fired and not yet handled, treat as regular tap
 	if !fired && !handled {
 		log.Println("[UI] Tapped!")
-		if t.onTap != nil {
-			t.onTap()
-		}
+		t.fireTap()
 	}
 }
 
@@ -386,16 +793,20 @@ func (t *TappableImage) Tapped(_ *fyne.PointEvent) {
 	// Only fire if not already handled by MouseUp
 	if !handled && !fired {
 		log.Println("[UI] Tapped (touch)!")
-		if t.onTap != nil {
-			t.onTap()
-		}
+		t.fireTap()
 	}
 }
 
 func (t *TappableImage) TappedSecondary(_ *fyne.PointEvent) {
-	// Right-click also triggers long-press action
+	// Right-click / two-finger tap; triggers the long-press action unless
+	// it has its own
 	log.Println("[UI] Secondary tap (right-click)")
-	if t.onLongTap != nil {
+	t.mu.Lock()
+	onSecondary := t.onSecondaryTap
+	t.mu.Unlock()
+	if onSecondary != nil {
+		onSecondary()
+	} else if t.onLongTap != nil {
 		t.onLongTap()
 	}
 }
@@ -405,8 +816,10 @@ type TappableSettings struct {
 	widget.BaseWidget
 	bg                *canvas.Rectangle
 	border            *canvas.Rectangle
+	outline           color.Color // Border color when not highlighted
 	content           *fyne.Container
 	nightModeBtn      *widget.Button
+	batteryLabel      *widget.Label // Hidden unless [battery] is enabled
 	brightnessButtons map[int]*widget.Button
 	currentBrightness int
 	onTap             func()
@@ -416,17 +829,19 @@ type TappableSettings struct {
 	longPressFired    bool
 	tapHandled        bool
 	highlighted       bool
+	focused           bool // Keyboard focus (see keyboard.go)
 	mu                sync.Mutex
 }
 
 func NewTappableSettings(
-	onRestart, onExit, onNightModeToggle func(),
+	onOpenSettings, onNightModeToggle func(),
 	onBrightnessChange func(int),
 	onTap, onLongTap func(),
 ) *TappableSettings {
 	t := &TappableSettings{
-		bg:                canvas.NewRectangle(color.RGBA{50, 50, 55, 255}),
+		bg:                canvas.NewRectangle(activeTheme().SettingsTile),
 		border:            canvas.NewRectangle(color.Transparent),
+		outline:           color.Transparent,
 		brightnessButtons: make(map[int]*widget.Button),
 		currentBrightness: defaultBrightnessPercent,
 		onTap:             onTap,
@@ -435,9 +850,9 @@ func NewTappableSettings(
 	t.border.StrokeWidth = 4
 	t.border.StrokeColor = color.Transparent
 
-	restartBtn := widget.NewButton("Restart", func() {
-		if onRestart != nil {
-			onRestart()
+	settingsBtn := widget.NewButton("Settings", func() {
+		if onOpenSettings != nil {
+			onOpenSettings()
 		}
 	})
 
@@ -447,12 +862,6 @@ func NewTappableSettings(
 		}
 	})
 
-	exitBtn := widget.NewButton("Exit", func() {
-		if onExit != nil {
-			onExit()
-		}
-	})
-
 	brightnessLabel := widget.NewLabel("Brightness")
 	brightnessLabel.Alignment = fyne.TextAlignCenter
 
@@ -470,12 +879,16 @@ func NewTappableSettings(
 	}
 	t.SetBrightnessSelection(defaultBrightnessPercent)
 
+	t.batteryLabel = widget.NewLabel("")
+	t.batteryLabel.Alignment = fyne.TextAlignCenter
+	t.batteryLabel.Hide()
+
 	t.content = container.NewCenter(container.NewVBox(
-		restartBtn,
+		settingsBtn,
 		t.nightModeBtn,
 		brightnessLabel,
 		brightnessRow,
-		exitBtn,
+		t.batteryLabel,
 	))
 	t.ExtendBaseWidget(t)
 	return t
@@ -493,6 +906,21 @@ func (t *TappableSettings) SetNightModeLabel(enabled bool) {
 	}
 }
 
+// SetBattery shows the battery voltage line, highlighted when low.
+func (t *TappableSettings) SetBattery(text string, low bool) {
+	importance := widget.MediumImportance
+	if low {
+		importance = widget.DangerImportance
+	}
+	if t.batteryLabel.Text == text && t.batteryLabel.Importance == importance && t.batteryLabel.Visible() {
+		return
+	}
+	t.batteryLabel.Text = text
+	t.batteryLabel.Importance = importance
+	t.batteryLabel.Show()
+	t.batteryLabel.Refresh()
+}
+
 // SetBrightnessSelection updates which brightness preset appears selected.
 func (t *TappableSettings) SetBrightnessSelection(percent int) {
 	t.mu.Lock()
@@ -519,15 +947,43 @@ func (t *TappableSettings) SetHighlight(on bool) {
 	t.mu.Lock()
 	t.highlighted = on
 	t.mu.Unlock()
+	t.updateBorder()
+}
 
-	if on {
-		t.border.StrokeColor = color.RGBA{255, 200, 0, 255} // Yellow border
-	} else {
-		t.border.StrokeColor = color.Transparent
-	}
+// SetFocused sets the keyboard focus outline (see keyboard.go).
+func (t *TappableSettings) SetFocused(on bool) {
+	t.mu.Lock()
+	t.focused = on
+	t.mu.Unlock()
+	t.updateBorder()
+}
+
+// updateBorder draws the swap highlight, the keyboard focus or the
+// outline.
+func (t *TappableSettings) updateBorder() {
+	t.mu.Lock()
+	c := tileBorderColor(t.highlighted, t.focused, t.outline)
+	t.mu.Unlock()
+	t.border.StrokeColor = c
 	t.border.Refresh()
 }
 
+// SetTheme recolors the tile (night mode switches the palette).
+func (t *TappableSettings) SetTheme(th uiTheme) {
+	t.bg.FillColor = th.SettingsTile
+	t.bg.Refresh()
+	t.SetOutline(th.Border) // Also redraws a highlight or focus in the new colors
+}
+
+// SetOutline sets the border color shown while neither highlighted nor
+// focused (grid tiles use the theme's border).
+func (t *TappableSettings) SetOutline(c color.Color) {
+	t.mu.Lock()
+	t.outline = c
+	t.mu.Unlock()
+	t.updateBorder()
+}
+
 // MouseDown starts the long-press timer
 func (t *TappableSettings) MouseDown(ev *desktop.MouseEvent) {
 	t.mu.Lock()
@@ -594,23 +1050,18 @@ func (t *TappableSettings) Tapped(_ *fyne.PointEvent) {
 }
 
 func (a *App) setupUI() {
-	// Dark background
-	background := canvas.NewRectangle(color.RGBA{20, 20, 20, 255})
+	a.gridBackground = canvas.NewRectangle(activeTheme().Background)
 
-	// Settings widget with Restart/Night Mode/Brightness/Exit controls and swap support
+	// Settings tile: opens the settings panel, plus quick night mode and
+	// brightness controls; supports swap like camera tiles
 	var settingsWidget *TappableSettings
 	settingsWidget = NewTappableSettings(
 		func() {
-			log.Println("[UI] Restart clicked")
-			a.restart()
-		},
-		func() {
-			log.Println("[UI] Exit clicked")
-			a.cleanup()
+			log.Println("[UI] Settings clicked")
+			a.openSettings()
 		},
 		func() {
 			a.toggleNightMode()
-			settingsWidget.SetNightModeLabel(a.nightModeEnabled.Load())
 		},
 		func(percent int) {
 			a.setBrightness(percent)
@@ -620,30 +1071,58 @@ func (a *App) setupUI() {
 		func() { a.onWidgetLongPress(settingsWidget) },
 	)
 	settingsWidget.SetBrightnessSelection(a.getBrightnessPercent())
-	a.gridWidgets[0] = settingsWidget
+	settingsWidget.SetOutline(activeTheme().Border)
+	settingsWidget.SetNightModeLabel(a.nightModeEnabled.Load())
+
+	// Health tile ([display] health_tile) in place of or after the settings tile
+	var healthTile *HealthTile
+	if a.cfg.HealthTile != "off" {
+		healthTile = newHealthTile(
+			func() { a.onWidgetTap(healthTile) },
+			func() { a.onWidgetLongPress(healthTile) },
+		)
+		healthTile.SetOutline(activeTheme().Border)
+		a.healthTile = healthTile
+	}
+	var firstTile fyne.CanvasObject = settingsWidget
+	if a.cfg.HealthTile == "replace" {
+		firstTile = healthTile
+		a.gridWidgets[0] = healthTile
+	} else {
+		a.settingsWidget = settingsWidget
+		a.gridWidgets[0] = settingsWidget
+	}
+	if a.drivingMode.Load() || a.stripLayout() {
+		settingsWidget.Hide()
+		if healthTile != nil {
+			healthTile.Hide()
+		}
+	}
 
 	// Camera widgets with tap handlers
-	gridObjects := make([]fyne.CanvasObject, 0, a.effectiveSlots()+1)
-	gridObjects = append(gridObjects, settingsWidget)
+	gridObjects := make([]fyne.CanvasObject, 0, len(a.gridSlots))
+	gridObjects = append(gridObjects, firstTile)
 
 	for i := 0; i < a.effectiveSlots(); i++ {
 		index := i
-		var camWidget *TappableImage
-		camWidget = NewTappableImage(
-			a.cameraImages[index],
-			color.RGBA{25, 25, 25, 255},
-			func() { a.onWidgetTap(camWidget) },
-			func() { a.onWidgetLongPress(camWidget) },
-		)
+		camWidget := NewTappableImage(a.cameraImages[index], activeTheme().Tile, nil, nil)
+		a.bindCameraGestures(camWidget)
+		camWidget.SetOnRestart(func() { a.forceRestart(index) })
+		camWidget.SetOutline(activeTheme().Border)
 		a.gridWidgets[index+1] = camWidget
 		a.cameraWidgets[index] = camWidget
 		camWidget.SetDisconnected(true) // Start disconnected until camera detected
 		gridObjects = append(gridObjects, camWidget)
 	}
+	if a.cfg.HealthTile == "extra" {
+		a.gridWidgets[len(a.gridWidgets)-1] = healthTile
+		gridObjects = append(gridObjects, healthTile)
+	}
 
-	// Dynamic grid layout based on number of widgets (settings + cameras)
-	gridRows, gridCols := helpers.GetSmartGrid(len(gridObjects))
-	a.grid = container.New(&fillGridLayout{rows: gridRows, cols: gridCols}, gridObjects...)
+	// Dynamic grid layout based on number of widgets (settings + cameras),
+	// unless [display] grid_layouts overrides it
+	gridOverrides, _ := helpers.ParseGridLayouts(a.cfg.GridLayouts)
+	a.grid = container.New(&fillGridLayout{overrides: gridOverrides, strip: a.stripLayout()}, gridObjects...)
 
 	// Prepare fullscreen image (reused) - use Stretch to fill screen
 	a.fullscreenImg = canvas.NewImageFromImage(createColoredImage(800, 480, color.RGBA{0, 0, 0, 255}))
@@ -654,42 +1133,99 @@ func (a *App) setupUI() {
 		a.fullscreenImg,
 		color.RGBA{0, 0, 0, 255},
 		func() { a.hideFullscreen() },
-		nil,
+		func() { a.togglePIP() },
 	)
+	a.fullscreenWidget.SetOnSwipe(a.onFullscreenSwipe)
 
-	// Fullscreen content (black bg + image)
+	// Fullscreen content (black bg + image + PiP overlays + controls)
 	fsBg := canvas.NewRectangle(color.RGBA{0, 0, 0, 255})
-	a.fullscreenContent = container.NewStack(fsBg, a.fullscreenWidget)
+	a.pipOverlay = a.newPIPOverlay()
+	a.controlsPanel = newControlsPanel(a)
+	a.fullscreenAdjust = widget.NewButton("Adjust", a.openControlsPanel)
+	fsButtons := container.NewHBox(a.fullscreenAdjust)
+	if a.clipWindow() > 0 {
+		a.fullscreenClip = widget.NewButton("Save clip", a.saveFullscreenClip)
+		fsButtons.Add(a.fullscreenClip)
+	}
+	a.aimOverlay = newAimOverlay()
+	fsLayers := []fyne.CanvasObject{fsBg, a.fullscreenWidget, a.pipOverlay, a.aimOverlay.content}
+	if a.replayWindow() > 0 {
+		a.replayBar = newReplayBar(a)
+		fsButtons.Add(widget.NewButton("Replay", a.toggleReplay))
+		fsLayers = append(fsLayers, a.replayBar.content)
+	}
+	adjustBox := container.NewVBox(fsButtons)
+	a.fullscreenContent = container.NewStack(append(fsLayers, adjustBox, a.controlsPanel.content)...)
 	a.fullscreenContent.Hide()
 
 	// Grid content
-	a.gridContent = container.NewStack(background, a.grid)
+	a.gridContent = container.NewStack(a.gridBackground, a.grid)
+	if a.stripLayout() {
+		a.gridContent.Add(a.newStripSettingsButton())
+	}
+	if a.cfg.MirrorEnabled {
+		a.gridContent.Add(a.newMirrorButton())
+	}
+
+	// Settings panel overlay (hidden until opened from the tile)
+	a.settingsPanel = newSettingsPanel(a)
 
-	// Main content with both layers
-	content := container.NewStack(a.gridContent, a.fullscreenContent)
+	// Main content with all layers (GPS overlay above both views)
+	layers := []fyne.CanvasObject{a.gridContent, a.fullscreenContent}
+	if a.cfg.GPSSource != "" && a.cfg.GPSOverlay {
+		a.gpsOverlay = a.newGPSOverlay()
+		layers = append(layers, a.gpsOverlay)
+	}
+	if a.cfg.ToastSeconds > 0 {
+		a.toasts = newToastLayer(time.Duration(a.cfg.ToastSeconds) * time.Second)
+		layers = append(layers, a.toasts.content)
+	}
+	layers = append(layers, a.settingsPanel.content)
+	if a.pinLock != nil {
+		// PIN keypad above everything it guards
+		a.lockScreen = newLockScreen(a.pinLock)
+		layers = append(layers, a.lockScreen.content)
+	}
+	content := container.NewStack(layers...)
 	a.window.SetContent(content)
+
+	a.applyStartupLayout()
+	a.setupKeyboard()
 }
 
 // fillGridLayout is a custom layout that fills all available space in a grid
 type fillGridLayout struct {
-	rows, cols int
+	overrides map[int]helpers.GridSize // Per tile count, from [display] grid_layouts
+	strip     bool                     // One row of all visible tiles ([display] strip_layout)
 }
 
 func (g *fillGridLayout) MinSize(objects []fyne.CanvasObject) fyne.Size {
 	return fyne.NewSize(100, 100)
 }
 
+// Layout places visible objects in grid order; hidden ones (the settings
+// tile in driving mode) are skipped and the grid shrinks to fit the rest.
 func (g *fillGridLayout) Layout(objects []fyne.CanvasObject, size fyne.Size) {
-	if len(objects) == 0 {
+	visible := make([]fyne.CanvasObject, 0, len(objects))
+	for _, obj := range objects {
+		if obj.Visible() {
+			visible = append(visible, obj)
+		}
+	}
+	if len(visible) == 0 {
 		return
 	}
 
-	cellWidth := size.Width / float32(g.cols)
-	cellHeight := size.Height / float32(g.rows)
+	rows, cols := helpers.GridFor(len(visible), g.overrides)
+	if g.strip {
+		rows, cols = 1, len(visible)
+	}
+	cellWidth := size.Width / float32(cols)
+	cellHeight := size.Height / float32(rows)
 
-	for i, obj := range objects {
-		row := i / g.cols
-		col := i % g.cols
+	for i, obj := range visible {
+		row := i / cols
+		col := i % cols
 
 		x := float32(col) * cellWidth
 		y := float32(row) * cellHeight
@@ -699,26 +1235,38 @@ func (g *fillGridLayout) Layout(objects []fyne.CanvasObject, size fyne.Size) {
 	}
 }
 
-// onGridTap handles tap on any grid position (0-3)
+// onGridTap handles tap on any grid position (0 = settings tile, 1..slots)
 func (a *App) onGridTap(gridPos int) {
 	if gridPos < 0 || gridPos >= len(a.gridSlots) {
 		return
 	}
 	log.Printf("[UI] Grid tap on position %d, swapMode=%v", gridPos, a.swapMode)
 
-	if a.swapMode {
+	switch {
+	case a.swapMode:
 		a.handleSwapTap(gridPos)
-	} else {
+	case a.gridSlots[gridPos] == healthSlot && a.settingsWidget == nil:
+		a.openSettings() // The health tile stands in for the settings tile
+	default:
 		a.showFullscreen(gridPos)
 	}
 }
 
-// onGridLongPress handles long-press on any grid position (0-3)
+// onGridLongPress handles long-press on any grid position (0 = settings tile, 1..slots)
 func (a *App) onGridLongPress(gridPos int) {
 	if gridPos < 0 || gridPos >= len(a.gridSlots) {
 		return
 	}
 	log.Printf("[UI] Long press on grid position %d", gridPos)
+	if a.drivingMode.Load() {
+		a.setDrivingMode(false) // Swap is hidden while driving; long-press is the way out
+		return
+	}
+	a.enterSwapMode(gridPos)
+}
+
+// enterSwapMode picks the tile at gridPos as the first of a swap.
+func (a *App) enterSwapMode(gridPos int) {
 	a.swapMode = true
 	a.swapSourceSlot = gridPos
 
@@ -812,8 +1360,11 @@ func (a *App) swapGridPositions(pos1, pos2 int) {
 	// Swap widget references
 	a.gridWidgets[pos1], a.gridWidgets[pos2] = a.gridWidgets[pos2], a.gridWidgets[pos1]
 
-	// Refresh the grid layout
+	// Refresh the grid layout; the focus stays on the position
 	a.grid.Refresh()
+	if a.keyFocus >= 0 {
+		a.setKeyFocus(a.keyFocus)
+	}
 }
 
 func (a *App) showFullscreen(gridPos int) {
@@ -827,8 +1378,8 @@ func (a *App) showFullscreen(gridPos int) {
 	// Get the content type at this grid position
 	contentType := a.gridSlots[gridPos]
 
-	// Settings widget (-1) doesn't go fullscreen
-	if contentType == -1 {
+	// Settings (-1) and health (-2) tiles don't go fullscreen
+	if contentType < 0 {
 		log.Printf("[UI] Settings widget tapped - no fullscreen")
 		return
 	}
@@ -845,7 +1396,8 @@ func (a *App) showFullscreen(gridPos int) {
 
 	a.isFullscreen.Store(true)
 	a.fullscreenSlot = gridPos
-	log.Printf("[UI] Fullscreen: camera %d from grid position %d", camIndex, gridPos)
+	log.Printf("[UI] Fullscreen: camera %s from grid position %d", a.cameraTag(camIndex), gridPos)
+	a.fullscreenWidget.SetName(a.cameraName(camIndex))
 
 	// Get current frame and set it
 	a.frameLock.RLock()
@@ -853,26 +1405,28 @@ func (a *App) showFullscreen(gridPos int) {
 	a.frameLock.RUnlock()
 
 	if currentFrame != nil {
-		displayFrame := a.applyFullscreenFilters(currentFrame)
+		displayFrame := a.applyFullscreenFilters(camIndex, currentFrame)
 		a.fullscreenImg.Image = displayFrame
 		a.fullscreenImg.Refresh()
 	}
 
 	// Show fullscreen, hide grid
+	if a.drivingMode.Load() {
+		a.fullscreenAdjust.Hide()
+	} else {
+		a.fullscreenAdjust.Show()
+	}
+	a.updatePIPOverlays(camIndex)
+	a.updateCaptureVisibility()
 	a.gridContent.Hide()
 	a.fullscreenContent.Show()
 
-	// Stop any previous fullscreen update goroutine (mutex prevents double-close)
+	// Replace any previous fullscreen update loop
 	a.fullscreenMu.Lock()
-	if a.fullscreenStopCh != nil {
-		close(a.fullscreenStopCh)
-	}
-	a.fullscreenStopCh = make(chan struct{})
-	stopCh := a.fullscreenStopCh
+	a.fullscreenLoop.Stop()
+	a.fullscreenLoop = newLoopGroup(a.ctx, "fullscreen")
+	a.fullscreenLoop.Go(func(ctx context.Context) { a.updateFullscreenLoop(ctx, camIndex) })
 	a.fullscreenMu.Unlock()
-
-	// Start fullscreen update loop
-	go a.updateFullscreenLoop(camIndex, stopCh)
 }
 
 func (a *App) hideFullscreen() {
@@ -882,20 +1436,41 @@ func (a *App) hideFullscreen() {
 	log.Println("[UI] Exiting fullscreen")
 	a.isFullscreen.Store(false)
 
-	// Stop fullscreen update goroutine (mutex prevents double-close)
+	// Stop the fullscreen update loop
 	a.fullscreenMu.Lock()
-	if a.fullscreenStopCh != nil {
-		close(a.fullscreenStopCh)
-		a.fullscreenStopCh = nil
-	}
+	a.fullscreenLoop.Stop()
+	a.fullscreenLoop = nil
 	a.fullscreenMu.Unlock()
 
 	// Hide fullscreen, show grid
+	a.controlsPanel.close()
+	if a.replayBar != nil {
+		a.replayBar.close()
+	}
+	a.exitMirrorMode()
+	a.updatePIPOverlays(-1)
+	a.updateCaptureVisibility()
 	a.fullscreenContent.Hide()
 	a.gridContent.Show()
 }
 
-func (a *App) updateFullscreenLoop(camIndex int, stopCh chan struct{}) {
+// openControlsPanel shows image controls for the fullscreen camera
+// (Adjust button in fullscreen). Not offered in driving mode.
+func (a *App) openControlsPanel() {
+	if !a.isFullscreen.Load() || a.drivingMode.Load() {
+		return
+	}
+	slot := a.fullscreenSlot
+	if slot < 0 || slot >= len(a.gridSlots) {
+		return
+	}
+	camIndex := a.gridSlots[slot]
+	a.withUnlock(func() { a.controlsPanel.open(camIndex) })
+}
+
+func (a *App) updateFullscreenLoop(ctx context.Context, camIndex int) {
+	var lastLuma time.Time
+	fsFrozen := false // Fullscreen image already shows the frozen picture
 	for {
 		if !a.isFullscreen.Load() {
 			return
@@ -907,12 +1482,36 @@ func (a *App) updateFullscreenLoop(camIndex int, stopCh chan struct{}) {
 			frame = a.cameraFrames[camIndex]
 		}
 		a.frameLock.RUnlock()
+		lost := a.cameraSignalLost(camIndex)
+		var frozen time.Duration
+		if replay := a.replayImage(); replay != nil {
+			frame, lost = replay, false
+		} else {
+			frozen = a.slotFrozenFor(camIndex, a.clk.Now())
+		}
+		a.fullscreenWidget.SetSignalLost(lost)
+		a.fullscreenWidget.SetFrozen(frozen)
+		if frame != nil && a.mirrorMode.Load() && a.cfg.MirrorAutoDim && time.Since(lastLuma) >= mirrorLumaInterval {
+			lastLuma = time.Now()
+			a.updateMirrorDim(frame)
+		}
 
-		if frame != nil && a.fullscreenImg != nil {
-			displayFrame := a.applyFullscreenFilters(frame)
+		if frame != nil && a.fullscreenImg != nil && !(frozen > 0 && fsFrozen) {
+			displayFrame := a.applyFullscreenFilters(camIndex, frame)
+			if frozen > 0 {
+				a.freezeFSBuf = applyFreezeReuse(displayFrame, a.freezeFSBuf)
+				displayFrame = a.freezeFSBuf
+			}
+			fsFrozen = frozen > 0
 			a.fullscreenImg.Image = displayFrame
 			a.fullscreenImg.Refresh()
 		}
+		if a.pipEnabled.Load() || a.mirrorMode.Load() {
+			a.updatePIPOverlays(camIndex)
+		}
+		if a.aimAssist.Load() {
+			a.updateAimLevel()
+		}
 
 		uiFPS := a.currentUIFPS()
 		if uiFPS < 1 {
@@ -920,7 +1519,7 @@ func (a *App) updateFullscreenLoop(camIndex int, stopCh chan struct{}) {
 		}
 		wait := time.Second / time.Duration(uiFPS)
 		select {
-		case <-stopCh:
+		case <-ctx.Done():
 			return
 		case <-time.After(wait):
 		}
@@ -935,6 +1534,10 @@ func (a *App) initializeCamerasAsync() {
 	}()
 
 	log.Println("[UI] Starting camera initialization...")
+	defer a.notifyReady()
+
+	// Power the cameras up before looking for them
+	a.startPower()
 
 	// Kill any processes holding camera devices (e.g., stale FFmpeg from previous run)
 	if a.cfg.KillDeviceHolders {
@@ -948,126 +1551,246 @@ func (a *App) initializeCamerasAsync() {
 	}
 
 	// Use buffer mode for decoupled capture/render with config-driven settings
-	a.manager = camera.NewManagerWithSettings(camera.Settings{
-		Width:      a.cfg.CaptureWidth,
-		Height:     a.cfg.CaptureHeight,
-		FPS:        a.cfg.CaptureFPS,
-		Format:     a.cfg.CaptureFormat,
-		MaxCameras: a.effectiveSlots(),
-	}, true)
-
-	if err := a.manager.Initialize(); err != nil {
+	manager := camera.NewManagerWithSettings(a.cameraSettings(), true)
+	a.manager.Store(manager)
+
+	if err := manager.Initialize(); err != nil {
 		log.Printf("[UI] Camera init error: %v", err)
 		return
 	}
 	log.Println("[UI] Manager initialized (buffer mode, config-driven settings)")
 
-	if err := a.manager.Start(); err != nil {
+	if err := manager.Start(); err != nil {
 		log.Printf("[UI] Camera start error: %v", err)
 		return
 	}
 
-	cams := a.manager.GetCameras()
+	cams := manager.GetCameras()
 	a.frameLock.Lock()
 	a.cameras = cams
 	a.frameLock.Unlock()
+	a.updateSlotDewarp(cams)
+	a.updateSlotTransforms(cams)
+	a.updateSlotNames(cams)
 	for i := 0; i < a.effectiveSlots(); i++ {
 		a.updateCameraStatus(i, false)
 	}
 	log.Printf("[UI] Discovered %d cameras", len(cams))
 	for i, cam := range cams {
-		log.Printf("[UI]   - %s: %s", cam.DeviceID, cam.DevicePath)
+		log.Printf("[UI]   - %s: %s", cam.DeviceID, camera.RedactSource(cam.DevicePath))
 		// Mark camera as connected and update UI
 		if i < a.effectiveSlots() {
 			a.updateCameraStatus(i, true)
 		}
 	}
+	for _, line := range camera.FormatUSBTopologyReport(cams) {
+		log.Printf("[Diagnostics] %s", line)
+	}
+	a.showPendingFullscreen()
+
+	// Held until the controller is stored, so a reload either lands in
+	// its copy of cfg or reaches it through SetConfig (see WatchConfig)
+	a.cfgMu.RLock()
+	pc := perf.NewAdaptiveController(manager, a.cfg)
+	pc.SetOnFPSChange(a.onFPSChange)
+	pc.SetClock(a.clk)
+	a.perfController.Store(pc)
+	a.cfgMu.RUnlock()
+	pc.Start()
+}
+
+// cameraSettings builds capture settings from config for a new manager.
+// Invalid CPU lists were already reported by Config.Validate and are
+// treated as "no pinning".
+func (a *App) cameraSettings() camera.Settings {
+	ffmpegCPUs, _ := helpers.ValidateCPUList(a.cfg.FFmpegCPUs)
+	captureCPUs, _ := helpers.ValidateCPUList(a.cfg.CaptureCPUs)
+	a.cfgMu.RLock()
+	reconnectCooldown := secondsToDuration(a.cfg.FailedCameraCooldownS)
+	a.cfgMu.RUnlock()
+	return camera.Settings{
+		Width:               a.cfg.CaptureWidth,
+		Height:              a.cfg.CaptureHeight,
+		FPS:                 a.cfg.CaptureFPS,
+		Format:              a.cfg.CaptureFormat,
+		MaxCameras:          a.effectiveSlots(),
+		DisabledDevices:     a.cfg.DisabledCameras,
+		PerCamera:           a.cameraOverrides(),
+		NetworkCameras:      a.cfg.NetworkCameras,
+		CSICameras:          a.cfg.CSICameras,
+		Simulate:            a.cfg.SimulateSources,
+		SyncHistory:         a.syncHistoryFrames(),
+		ClipWindow:          a.historyWindow(),
+		Controls:            a.cfg.CameraControls,
+		ReapplyControls:     a.cfg.ControlsReapply,
+		CapsCachePath:       a.cfg.CapsCacheFile,
+		FirstFrameWarn:      secondsToDuration(a.cfg.FirstFrameWarnSec),
+		HiddenFPS:           a.cfg.HiddenCameraFPS,
+		NoTestPattern:       !a.cfg.TestPattern,
+		TraceEvery:          a.traceEvery(),
+		ReconnectCooldown:   reconnectCooldown,
+		ReconnectBudget:     a.cfg.ReconnectBudget,
+		BandwidthBudget:     a.bandwidthBudget(),
+		BandwidthMinFPS:     a.cfg.BandwidthMinFPS,
+		BandwidthTiers:      a.bandwidthTiers(),
+		BandwidthPriority:   a.cfg.BandwidthPriorityOrder(),
+		HealthWindow:        time.Duration(a.cfg.StreamHealthWindowSec) * time.Second,
+		FallbackCorruptRate: a.fallbackCorruptRate(),
+		FallbackWidth:       a.cfg.StreamFallbackResolution.Width,
+		FallbackHeight:      a.cfg.StreamFallbackResolution.Height,
+		FFmpegCPUs:          ffmpegCPUs,
+		CaptureCPUs:         captureCPUs,
+		FFmpegNice:          a.cfg.FFmpegNice,
+		Faults:              a.faults.Load(),
+		Backend:             a.captureBackend,
+		Clock:               a.clk,
+	}
+}
+
+// cameraOverrides are the capture settings of the [camera.<name>]
+// sections.
+func (a *App) cameraOverrides() []camera.CameraOverride {
+	overrides := make([]camera.CameraOverride, 0, len(a.cfg.CameraSections))
+	for _, cs := range a.cfg.CameraSections {
+		overrides = append(overrides, camera.CameraOverride{
+			Match:  cs.Match,
+			Width:  cs.Width,
+			Height: cs.Height,
+			FPS:    cs.FPS,
+			Format: cs.Format,
+		})
+	}
+	return overrides
+}
 
-	a.perfController = perf.NewAdaptiveController(a.manager, a.cfg)
-	a.perfController.Start()
+// fallbackCorruptRate is the corrupt share of an MJPEG stream that makes
+// its worker fall back to YUYV (0 = never).
+func (a *App) fallbackCorruptRate() float64 {
+	if !a.cfg.StreamFallback {
+		return 0
+	}
+	return a.cfg.StreamCorruptPercent / 100
+}
+
+// bandwidthBudget is the USB bandwidth scheduler's budget in bytes per
+// second (0 = off).
+func (a *App) bandwidthBudget() float64 {
+	if !a.cfg.BandwidthEnabled {
+		return 0
+	}
+	return a.cfg.BandwidthBudgetMB * 1e6
+}
+
+// bandwidthTiers are the adaptive resolution tiers, which the bandwidth
+// scheduler also steps through once FPS is at its minimum.
+func (a *App) bandwidthTiers() [][2]int {
+	tiers := make([][2]int, 0, len(a.cfg.ResolutionTiers))
+	for _, r := range a.cfg.ResolutionTiers {
+		tiers = append(tiers, [2]int{r.Width, r.Height})
+	}
+	return tiers
 }
 
 func (a *App) startCameraRefresh() {
-	go func() {
+	a.refreshLoops = newLoopGroup(a.ctx, "refresh")
+	a.refreshLoops.Go(func(ctx context.Context) {
 		frameCounters := make(map[string]uint64)
+		tracer := a.newFrameTracer() // nil unless -trace-frames (see tracing.go)
+		defer tracer.close()
 
 		for {
 			select {
-			case <-a.hotplugStopCh:
+			case <-ctx.Done():
 				return
 			default:
 			}
+			a.uiHeartbeat.Beat()
 
-			if a.manager == nil {
-				uiFPS := a.currentUIFPS()
-				if uiFPS < 1 {
-					uiFPS = 1
-				}
-				select {
-				case <-a.hotplugStopCh:
-					return
-				case <-time.After(time.Second / time.Duration(uiFPS)):
-				}
-				continue
+			if manager := a.manager.Load(); manager != nil {
+				a.refreshSlots(manager, frameCounters, tracer)
+			}
+			uiFPS := a.currentUIFPS()
+			if uiFPS < 1 {
+				uiFPS = 1
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(time.Second / time.Duration(uiFPS)):
 			}
+		}
+	})
+}
 
-			a.frameLock.RLock()
-			camCount := len(a.cameras)
-			cameras := make([]camera.Camera, camCount)
-			copy(cameras, a.cameras)
-			a.frameLock.RUnlock()
+// refreshSlots shows each slot's new frame from manager's buffers (one
+// refresh loop iteration).
+func (a *App) refreshSlots(manager *camera.Manager, frameCounters map[string]uint64, tracer *frameTracer) {
+	a.frameLock.RLock()
+	camCount := len(a.cameras)
+	cameras := make([]camera.Camera, camCount)
+	copy(cameras, a.cameras)
+	a.frameLock.RUnlock()
 
-			slotLimit := minInt(a.effectiveSlots(), camCount)
-			for camIndex := 0; camIndex < slotLimit; camIndex++ {
-				cameraID := cameras[camIndex].DeviceID
+	slotLimit := minInt(a.effectiveSlots(), camCount)
+	buffers := make([]*camera.FrameBuffer, slotLimit)
+	for camIndex := range buffers {
+		buffers[camIndex] = manager.GetFrameBuffer(cameras[camIndex].DeviceID)
+	}
+	// Soft-sync: align all cameras to the slowest one (zero = off)
+	syncTarget := a.syncTarget(buffers, cameras, time.Now())
 
-				// Try buffer mode first (preferred)
-				buffer := a.manager.GetFrameBuffer(cameraID)
-				if buffer == nil {
-					continue
-				}
+	for camIndex := 0; camIndex < slotLimit; camIndex++ {
+		cameraID := cameras[camIndex].DeviceID
 
-				// Only update if there's a new frame (avoids unnecessary refreshes)
-				frame, frameNum, hasNew := buffer.ReadIfNew(a.lastFrameRead[camIndex])
-				if !hasNew || frame == nil {
-					continue // No new frame
-				}
+		// Try buffer mode first (preferred)
+		buffer := buffers[camIndex]
+		if buffer == nil {
+			continue
+		}
 
-				a.lastFrameRead[camIndex] = frameNum
+		// Only update if there's a new frame (avoids unnecessary refreshes)
+		frame, frameNum, captured, hasNew := a.readSlotFrame(buffer, camIndex, cameras[camIndex], syncTarget)
+		if !hasNew || frame == nil {
+			a.updateSlotFreeze(camIndex, a.clk.Now())
+			continue // No new frame
+		}
+		a.clearSlotFreeze(camIndex, true)
 
-				// Track frame arrival time for stale detection
-				a.frameLock.Lock()
-				a.cameraFrames[camIndex] = frame
-				a.lastFrameTime[camIndex] = time.Now()
-				a.frameLock.Unlock()
+		var trace *camera.FrameTrace
+		var pickedUp time.Time
+		if tracer != nil {
+			if trace = buffer.Trace(frameNum); trace != nil {
+				pickedUp = time.Now()
+			}
+		}
 
-				displayFrame := a.applySlotFilters(camIndex, frame)
+		a.lastFrameRead[camIndex] = frameNum
 
-				// Update the camera image widget
-				// Fyne's Refresh is thread-safe but can be slow if backed up
-				a.cameraImages[camIndex].Image = displayFrame
-				a.cameraImages[camIndex].Refresh()
+		// Track frame arrival time for stale detection
+		a.frameLock.Lock()
+		a.cameraFrames[camIndex] = frame
+		a.lastFrameTime[camIndex] = a.clk.Now()
+		a.shownFrameAt[camIndex] = captured
+		a.frameLock.Unlock()
 
-				frameCounters[cameraID]++
-				if frameCounters[cameraID]%90 == 1 { // Log every 90 frames (~3 sec at 30fps)
-					fps, totalFrames, _ := buffer.GetCaptureStats()
-					droppedCount := buffer.GetDroppedCount()
-					log.Printf("[UI] Camera %s: frame #%d, buffer stats: %d captured, %d dropped, %.1f fps",
-						cameraID, frameCounters[cameraID], totalFrames, droppedCount, fps)
-				}
-			}
+		// Update the camera image widget (none in headless mode)
+		// Fyne's Refresh is thread-safe but can be slow if backed up
+		if img := a.cameraImages[camIndex]; img != nil {
+			img.Image = a.applySlotFilters(camIndex, frame)
+			img.Refresh()
+		}
+		if trace != nil {
+			tracer.record(trace, pickedUp.Sub(trace.Written), time.Since(pickedUp))
+		}
 
-			uiFPS := a.currentUIFPS()
-			if uiFPS < 1 {
-				uiFPS = 1
-			}
-			select {
-			case <-a.hotplugStopCh:
-				return
-			case <-time.After(time.Second / time.Duration(uiFPS)):
-			}
+		frameCounters[cameraID]++
+		if frameCounters[cameraID]%90 == 1 { // Log every 90 frames (~3 sec at 30fps)
+			fps, totalFrames, _ := buffer.GetCaptureStats()
+			droppedCount := buffer.GetDroppedCount()
+			log.Printf("[UI] Camera %s: frame #%d, buffer stats: %d captured, %d dropped, %.1f fps",
+				cameraID, frameCounters[cameraID], totalFrames, droppedCount, fps)
 		}
-	}()
+	}
 }
 
 // updateCameraStatus updates the connected/disconnected status for a camera slot
@@ -1079,15 +1802,19 @@ func (a *App) updateCameraStatus(camIndex int, connected bool) {
 	a.frameLock.Lock()
 	previousStatus := a.cameraStatus[camIndex]
 	a.cameraStatus[camIndex] = connected
+	if connected && camIndex < len(a.slotRecovery) {
+		a.slotRecovery[camIndex] = recoveryStatus{}
+	}
 	a.frameLock.Unlock()
 
 	if previousStatus != connected {
-		log.Printf("[UI] Camera %d status changed: connected=%v", camIndex, connected)
+		log.Printf("[UI] Camera %s status changed: connected=%v", a.cameraTag(camIndex), connected)
 	}
 
 	// Update the widget UI
 	if a.cameraWidgets[camIndex] != nil {
 		a.cameraWidgets[camIndex].SetDisconnected(!connected)
+		a.renderRecovery(camIndex, a.clk.Now())
 	}
 }
 
@@ -1103,11 +1830,16 @@ func (a *App) getBrightnessPercent() int {
 	return p
 }
 
-func (a *App) setBrightness(percent int) {
+func isBrightnessPreset(percent int) bool {
 	switch percent {
 	case 15, 60, 80, 100, 150:
-		// Valid preset
-	default:
+		return true
+	}
+	return false
+}
+
+func (a *App) setBrightness(percent int) {
+	if !isBrightnessPreset(percent) {
 		log.Printf("[UI] Ignoring unsupported brightness preset: %d%%", percent)
 		return
 	}
@@ -1124,6 +1856,16 @@ func (a *App) applySlotFilters(camIndex int, frame image.Image) image.Image {
 	}
 	displayFrame := frame
 
+	if d := a.slotDewarper(camIndex); d != nil {
+		a.dewarpBufs[camIndex] = d.apply(displayFrame, a.dewarpBufs[camIndex])
+		displayFrame = a.dewarpBufs[camIndex]
+	}
+
+	if t := a.slotTransform(camIndex); !t.isIdentity() {
+		a.transformBufs[camIndex] = applyTransform(displayFrame, t, a.transformBufs[camIndex])
+		displayFrame = a.transformBufs[camIndex]
+	}
+
 	if a.nightModeEnabled.Load() {
 		a.nightModeBufs[camIndex] = applyNightModeReuse(displayFrame, a.nightModeBufs[camIndex])
 		displayFrame = a.nightModeBufs[camIndex]
@@ -1138,15 +1880,25 @@ func (a *App) applySlotFilters(camIndex int, frame image.Image) image.Image {
 	return displayFrame
 }
 
-func (a *App) applyFullscreenFilters(frame image.Image) image.Image {
+func (a *App) applyFullscreenFilters(camIndex int, frame image.Image) image.Image {
 	displayFrame := frame
 
+	if d := a.slotDewarper(camIndex); d != nil {
+		a.dewarpFSBuf = d.apply(displayFrame, a.dewarpFSBuf)
+		displayFrame = a.dewarpFSBuf
+	}
+
+	if t := a.slotTransform(camIndex); !t.isIdentity() {
+		a.transformFSBuf = applyTransform(displayFrame, t, a.transformFSBuf)
+		displayFrame = a.transformFSBuf
+	}
+
 	if a.nightModeEnabled.Load() {
 		a.nightModeFSBuf = applyNightModeReuse(displayFrame, a.nightModeFSBuf)
 		displayFrame = a.nightModeFSBuf
 	}
 
-	brightness := a.getBrightnessPercent()
+	brightness := a.mirrorBrightness(a.getBrightnessPercent())
 	if brightness != defaultBrightnessPercent {
 		a.brightnessFSBuf = applyBrightnessPercentReuse(displayFrame, brightness, a.brightnessFSBuf)
 		displayFrame = a.brightnessFSBuf
@@ -1157,13 +1909,75 @@ func (a *App) applyFullscreenFilters(frame image.Image) image.Image {
 
 // toggleNightMode toggles the night mode state and logs the change.
 func (a *App) toggleNightMode() {
-	wasEnabled := a.nightModeEnabled.Load()
-	a.nightModeEnabled.Store(!wasEnabled)
-	if !wasEnabled {
-		log.Println("[UI] Night mode enabled")
-	} else {
-		log.Println("[UI] Night mode disabled")
-	}
+	a.setNightMode(!a.nightModeEnabled.Load())
+}
+
+// setNightMode sets the night mode state, logs changes and keeps the
+// settings tile label in sync (used by the tile and remote commands).
+func (a *App) setNightMode(enabled bool) {
+	wasEnabled := a.nightModeEnabled.Swap(enabled)
+	if wasEnabled != enabled {
+		if enabled {
+			log.Println("[UI] Night mode enabled")
+		} else {
+			log.Println("[UI] Night mode disabled")
+		}
+		a.applyNightChrome(enabled)
+	}
+	if a.settingsWidget != nil {
+		a.settingsWidget.SetNightModeLabel(enabled)
+	}
+}
+
+// WatchConfig applies display-default changes from config reloads.
+// Other reloadable settings are read live, and the FPS controller gets
+// a copy of the new config. Reloads write cfg holding a.cfgMu: code off the reload goroutine
+// copies reloadable fields out under a.cfgMu.RLock and releases it
+// before acting on them.
+func (a *App) WatchConfig(w *config.Watcher) {
+	a.configWatcher = w
+	w.SetLocker(&a.cfgMu)
+	w.Subscribe(func(cfg *config.Config, changed []string) {
+		for _, name := range changed {
+			switch name {
+			case "NightMode":
+				a.setNightMode(cfg.NightMode)
+			case "DrivingMode":
+				a.setDrivingMode(cfg.DrivingMode)
+			case "CameraTransforms":
+				a.frameLock.RLock()
+				cams := a.cameras
+				a.frameLock.RUnlock()
+				a.updateSlotTransforms(cams)
+			case "CameraDewarp":
+				a.frameLock.RLock()
+				cams := a.cameras
+				a.frameLock.RUnlock()
+				a.updateSlotDewarp(cams)
+			case "AlertsMuted":
+				a.setAlertsMuted(cfg.AlertsMuted)
+			case "CameraNames":
+				a.frameLock.RLock()
+				cams := a.cameras
+				a.frameLock.RUnlock()
+				a.updateSlotNames(cams)
+			case "FailedCameraCooldownS":
+				if m := a.manager.Load(); m != nil {
+					m.SetReconnectCooldown(secondsToDuration(cfg.FailedCameraCooldownS))
+				}
+			case "BrightnessPercent":
+				a.setBrightness(cfg.BrightnessPercent)
+				if a.settingsWidget != nil {
+					a.settingsWidget.SetBrightnessSelection(a.getBrightnessPercent())
+				}
+			}
+		}
+		if pc := a.perfController.Load(); pc != nil {
+			a.cfgMu.RLock()
+			pc.SetConfig(cfg)
+			a.cfgMu.RUnlock()
+		}
+	})
 }
 
 // =============================================================================
@@ -1175,7 +1989,7 @@ func (a *App) toggleNightMode() {
 
 // startHealthLogging periodically logs camera health status.
 // Disabled when HealthLogIntervalSec <= 0.
-func (a *App) startHealthLogging() {
+func (a *App) startHealthLogging(ctx context.Context) {
 	interval := a.cfg.HealthLogIntervalSec
 	if interval <= 0 {
 		log.Println("[Health] Health logging disabled (interval <= 0)")
@@ -1189,7 +2003,7 @@ func (a *App) startHealthLogging() {
 
 	for {
 		select {
-		case <-a.hotplugStopCh:
+		case <-ctx.Done():
 			return
 		case <-ticker.C:
 			a.logHealthSummary()
@@ -1201,47 +2015,161 @@ func (a *App) startHealthLogging() {
 // Counts cameras as online (fresh frame), stale (frame older than threshold),
 // or disconnected (not connected).
 func (a *App) logHealthSummary() {
-	if a.manager == nil {
+	if a.manager.Load() == nil {
 		return
 	}
 
-	now := time.Now()
-	staleThreshold := a.cfg.StaleFrameTimeoutSec // H7: use config instead of hardcoded 10.0
-	online := 0
-	stale := 0
-	disconnected := 0
 	totalSlots := a.cfg.CameraSlotCount
-
 	limit := minInt(totalSlots, len(a.cameraStatus))
-	for camIndex := 0; camIndex < limit; camIndex++ {
-		a.frameLock.RLock()
-		connected := a.cameraStatus[camIndex]
-		lastFrame := a.lastFrameTime[camIndex]
-		a.frameLock.RUnlock()
+	online, stale, disconnected := a.countCameraHealth(a.clk.Now(), true)
 
-		if !connected {
-			disconnected++
-			continue
+	log.Printf("[Health] cameras online=%d stale=%d disconnected=%d total_slots=%d",
+		online, stale, disconnected, totalSlots)
+
+	firstFrameMS := a.firstFrameLatencies(limit)
+	parts := make([]string, len(firstFrameMS))
+	for i, ms := range firstFrameMS {
+		if ms < 0 {
+			parts[i] = fmt.Sprintf("cam%d=pending", i)
+		} else {
+			parts[i] = fmt.Sprintf("cam%d=%dms", i, ms)
 		}
+	}
+	if len(parts) > 0 {
+		log.Printf("[Health] first frame: %s", strings.Join(parts, " "))
+	}
+	if skew := frameSkewSummary(a.frameSyncSamples()); skew != "" {
+		log.Printf("[Health] frame skew: %s", skew)
+	}
+	streams := a.streamHealths(limit)
+	if summary := streamHealthSummary(streams); summary != "" {
+		log.Printf("[Health] streams: %s", summary)
+	}
 
-		if lastFrame.IsZero() {
-			// Never received a frame — treat as stale
+	a.publishHealth(online, stale, disconnected, totalSlots, firstFrameMS, streams)
+}
+
+// slotHealth is a camera slot's state as the health log counts it.
+type slotHealth int
+
+const (
+	slotOnline       slotHealth = iota // Fresh frame
+	slotStale                          // Frame older than the threshold, or none yet
+	slotDisconnected                   // No camera
+)
+
+// cameraHealth classifies one slot; age is its last frame's age in
+// seconds (-1 = no frame yet).
+func (a *App) cameraHealth(camIndex int, now time.Time) (slotHealth, float64) {
+	a.frameLock.RLock()
+	connected := a.cameraStatus[camIndex]
+	lastFrame := a.lastFrameTime[camIndex]
+	a.frameLock.RUnlock()
+
+	if !connected {
+		return slotDisconnected, -1
+	}
+	if lastFrame.IsZero() {
+		// Never received a frame — treat as stale
+		return slotStale, -1
+	}
+	a.cfgMu.RLock()
+	timeout := a.cfg.StaleFrameTimeoutSec
+	a.cfgMu.RUnlock()
+	age := now.Sub(lastFrame).Seconds()
+	if age > timeout { // H7: use config instead of hardcoded 10.0
+		return slotStale, age
+	}
+	return slotOnline, age
+}
+
+// countCameraHealth counts slots as online (fresh frame), stale (frame
+// older than the threshold, or none yet) or disconnected. logStale logs
+// a warning for each stale camera.
+func (a *App) countCameraHealth(now time.Time, logStale bool) (online, stale, disconnected int) {
+	limit := minInt(a.cfg.CameraSlotCount, len(a.cameraStatus))
+	for camIndex := 0; camIndex < limit; camIndex++ {
+		health, age := a.cameraHealth(camIndex, now)
+		switch health {
+		case slotDisconnected:
+			disconnected++
+		case slotStale:
 			stale++
-			log.Printf("[Health] WARNING: camera %d has never produced a frame", camIndex)
+			if !logStale {
+				break
+			}
+			if age < 0 {
+				log.Printf("[Health] WARNING: camera %d has never produced a frame", camIndex)
+			} else {
+				log.Printf("[Health] WARNING: camera %d frame is stale (%.1fs old)", camIndex, age)
+			}
+		default:
+			online++
+		}
+	}
+	return online, stale, disconnected
+}
+
+// firstFrameLatencies returns each slot's current-session first-frame
+// latency in milliseconds, or -1 where no frame has arrived yet.
+func (a *App) firstFrameLatencies(slots int) []int64 {
+	latencies := make([]int64, slots)
+	a.frameLock.RLock()
+	cameras := make([]camera.Camera, len(a.cameras))
+	copy(cameras, a.cameras)
+	a.frameLock.RUnlock()
+
+	manager := a.manager.Load()
+	for i := range latencies {
+		latencies[i] = -1
+		if i >= len(cameras) || manager == nil {
 			continue
 		}
+		if worker := manager.GetWorker(cameras[i].DeviceID); worker != nil {
+			if d, ok := worker.FirstFrameLatency(); ok {
+				latencies[i] = d.Milliseconds()
+			}
+		}
+	}
+	return latencies
+}
 
-		age := now.Sub(lastFrame).Seconds()
-		if age > staleThreshold {
-			stale++
-			log.Printf("[Health] WARNING: camera %d frame is stale (%.1fs old)", camIndex, age)
-		} else {
-			online++
+// streamHealths returns each slot's stream score and active format; slots
+// without a worker have an empty Format.
+func (a *App) streamHealths(slots int) []camera.StreamHealth {
+	streams := make([]camera.StreamHealth, slots)
+	a.frameLock.RLock()
+	cameras := make([]camera.Camera, len(a.cameras))
+	copy(cameras, a.cameras)
+	a.frameLock.RUnlock()
+
+	manager := a.manager.Load()
+	for i := range streams {
+		if i >= len(cameras) || manager == nil {
+			continue
+		}
+		if worker := manager.GetWorker(cameras[i].DeviceID); worker != nil {
+			streams[i] = worker.StreamHealth()
 		}
 	}
+	return streams
+}
 
-	log.Printf("[Health] cameras online=%d stale=%d disconnected=%d total_slots=%d",
-		online, stale, disconnected, totalSlots)
+// streamHealthSummary formats the active format and corruption rate of
+// each streaming slot, e.g. "cam0=mjpeg 0.4% cam1=yuyv(fallback) 2.1%".
+func streamHealthSummary(streams []camera.StreamHealth) string {
+	var parts []string
+	for i, s := range streams {
+		if s.Format == "" {
+			continue
+		}
+		format := s.Format
+		if s.Fallback {
+			format += "(fallback)"
+		}
+		parts = append(parts, fmt.Sprintf("cam%d=%s %.1f%%", i, format, s.CorruptRate*100))
+	}
+	return strings.Join(parts, " ")
 }
 
 // =============================================================================
@@ -1256,7 +2184,7 @@ func (a *App) logHealthSummary() {
 
 // startStaleFrameDetection periodically checks for cameras that have stopped
 // producing frames and restarts their capture workers.
-func (a *App) startStaleFrameDetection() {
+func (a *App) startStaleFrameDetection(ctx context.Context) {
 	log.Println("[Stale] Starting stale frame detection...")
 
 	// Check every 500ms for responsiveness
@@ -1265,17 +2193,19 @@ func (a *App) startStaleFrameDetection() {
 
 	for {
 		select {
-		case <-a.hotplugStopCh:
+		case <-ctx.Done():
 			return
 		case <-ticker.C:
 			a.checkStaleFrames()
+			a.updateSignalLost()
+			a.renderAllRecovery(a.clk.Now())
 		}
 	}
 }
 
 // checkStaleFrames checks each connected camera for stale frames
 func (a *App) checkStaleFrames() {
-	if a.manager == nil {
+	if a.manager.Load() == nil {
 		return
 	}
 
@@ -1287,8 +2217,10 @@ func (a *App) checkStaleFrames() {
 		return
 	}
 
-	now := time.Now()
-	staleTimeout := time.Duration(a.cfg.StaleFrameTimeoutSec * float64(time.Second))
+	now := a.clk.Now()
+	a.cfgMu.RLock()
+	baseTimeout := time.Duration(a.cfg.StaleFrameTimeoutSec * float64(time.Second))
+	a.cfgMu.RUnlock()
 
 	limit := minInt(a.effectiveSlots(), camCount)
 	for camIndex := 0; camIndex < limit; camIndex++ {
@@ -1298,7 +2230,11 @@ func (a *App) checkStaleFrames() {
 		a.frameLock.RUnlock()
 
 		if !connected {
-			continue // Skip disconnected cameras
+			// Retry a stale camera once its restart hold-off is over
+			if a.restartHoldOffExpired(camIndex, now) {
+				a.restartCaptureIfStale(camIndex)
+			}
+			continue
 		}
 
 		// Skip if we haven't received any frames yet (still initializing)
@@ -1308,16 +2244,21 @@ func (a *App) checkStaleFrames() {
 
 		// Check if frame is stale
 		staleDuration := now.Sub(lastFrame)
-		if staleDuration <= staleTimeout {
+		if staleDuration <= a.staleTimeout(camIndex, baseTimeout) {
 			continue // Frame is fresh
 		}
 
-		log.Printf("[Stale] Camera %d: stale frame detected (no frames for %.1fs)",
-			camIndex, staleDuration.Seconds())
+		log.Printf("[Stale] Camera %s: stale frame detected (no frames for %.1fs)",
+			a.cameraTag(camIndex), staleDuration.Seconds())
 
 		// Mark as disconnected in UI
 		a.updateCameraStatus(camIndex, false)
 
+		// Several cameras at once: one hub incident, maybe one power cycle
+		if a.handleCorrelatedStale(camIndex, now) {
+			continue
+		}
+
 		// Attempt bounded auto-restart
 		a.restartCaptureIfStale(camIndex)
 	}
@@ -1329,94 +2270,91 @@ func (a *App) checkStaleFrames() {
 //   - Sliding window restart limit (MAX_RESTARTS_PER_WINDOW in RESTART_WINDOW_SEC)
 //   - Extended cooldown (2x window) when limit is reached
 func (a *App) restartCaptureIfStale(camIndex int) {
-	if camIndex < 0 || camIndex >= len(a.lastRestartTime) || camIndex >= len(a.restartEvents) || camIndex >= len(a.restartLimitHit) {
+	if camIndex < 0 || camIndex >= len(a.restartPolicies) {
 		return
 	}
-	now := time.Now()
-	cooldown := time.Duration(a.cfg.RestartCooldownSec * float64(time.Second))
-	window := time.Duration(a.cfg.RestartWindowSec * float64(time.Second))
-	extendedCooldown := window * 2
-
-	// Check cooldown
-	if !a.lastRestartTime[camIndex].IsZero() && now.Sub(a.lastRestartTime[camIndex]) < cooldown {
-		return // Still in cooldown
-	}
-
-	// Count recent restarts in the sliding window
-	recentCount := 0
-	for _, t := range a.restartEvents[camIndex] {
-		if now.Sub(t) <= window {
-			recentCount++
+	a.cfgMu.RLock()
+	limits := restartLimits{
+		cooldown:    time.Duration(a.cfg.RestartCooldownSec * float64(time.Second)),
+		window:      time.Duration(a.cfg.RestartWindowSec * float64(time.Second)),
+		maxRestarts: a.cfg.MaxRestartsPerWindow,
+	}
+	a.cfgMu.RUnlock()
+
+	a.restartMu.Lock()
+	policy := &a.restartPolicies[camIndex]
+	decision, recent, firstLimitHit := policy.check(a.clk.Now(), limits)
+	last := policy.last
+	a.restartMu.Unlock()
+	switch decision {
+	case restartCooldown:
+		a.setRecovery(camIndex, recoveryStatus{action: "retrying in", until: last.Add(limits.cooldown), holdOff: true})
+		return
+	case restartLimited:
+		if firstLimitHit {
+			log.Printf("[Stale] Camera %d: restart limit reached (%d/%d in %.0fs), will retry in %.0fs",
+				camIndex, recent, limits.maxRestarts,
+				limits.window.Seconds(), (limits.window * 2).Seconds())
 		}
-	}
-
-	if recentCount >= a.cfg.MaxRestartsPerWindow {
-		// Restart limit reached - check extended cooldown
-		if !a.lastRestartTime[camIndex].IsZero() && now.Sub(a.lastRestartTime[camIndex]) < extendedCooldown {
-			if !a.restartLimitHit[camIndex] {
-				log.Printf("[Stale] Camera %d: restart limit reached (%d/%d in %.0fs), will retry in %.0fs",
-					camIndex, recentCount, a.cfg.MaxRestartsPerWindow,
-					a.cfg.RestartWindowSec, extendedCooldown.Seconds())
-				a.restartLimitHit[camIndex] = true
-			}
-			return
+		a.setRecovery(camIndex, recoveryStatus{
+			action: "restart limit reached, backing off", until: last.Add(limits.window * 2), holdOff: true})
+		// Restarts aren't helping: cut the camera's power once, if it has a rail
+		if firstLimitHit {
+			a.powerCycleCamera(camIndex)
 		}
-
-		// Extended cooldown passed - clear events and allow restart
+		return
+	case restartRecovered:
 		log.Printf("[Stale] Camera %d: extended cooldown passed, attempting recovery", camIndex)
-		a.restartEvents[camIndex] = nil
-		a.restartLimitHit[camIndex] = false
 	}
+	a.setRecovery(camIndex, recoveryStatus{action: "restarting capture", holdOff: true})
 
-	// Record this restart event
-	a.restartEvents[camIndex] = append(a.restartEvents[camIndex], now)
-	a.lastRestartTime[camIndex] = now
-
-	// Clean up old events outside the window
-	var filtered []time.Time
-	for _, t := range a.restartEvents[camIndex] {
-		if now.Sub(t) <= window*2 { // Keep slightly more history
-			filtered = append(filtered, t)
-		}
-	}
-	a.restartEvents[camIndex] = filtered
+	log.Printf("[Stale] Camera %s: restarting capture worker after stale frames", a.cameraTag(camIndex))
+	a.publishRestartEvent(camIndex, "stale")
 
-	log.Printf("[Stale] Camera %d: restarting capture worker after stale frames", camIndex)
+	go a.restartWorker(camIndex, limits.cooldown)
+}
 
-	go func(idx int) {
-		if a.manager == nil {
-			return
-		}
+// restartWorker restarts one camera's capture worker (stale recovery or a
+// manual restart). On failure the stale detector retries after cooldown.
+func (a *App) restartWorker(idx int, cooldown time.Duration) {
+	manager := a.manager.Load()
+	if manager == nil {
+		return
+	}
 
-		// Kill any processes holding this camera device before restart
-		a.frameLock.RLock()
-		var devPath string
-		if idx < len(a.cameras) {
-			devPath = a.cameras[idx].DevicePath
-		}
-		a.frameLock.RUnlock()
-		if devPath != "" {
-			helpers.KillDeviceHolders(devPath, a.cfg.KillDeviceHolders)
-		}
+	// Kill any processes holding this camera device before restart
+	a.frameLock.RLock()
+	var devPath string
+	if idx < len(a.cameras) && a.cameras[idx].HasDeviceNode() {
+		devPath = a.cameras[idx].DevicePath
+	}
+	a.frameLock.RUnlock()
+	if devPath != "" {
+		helpers.KillDeviceHolders(devPath, a.cfg.KillDeviceHolders)
+	}
 
-		if err := a.manager.RestartCameraByIndex(idx); err != nil {
-			log.Printf("[Stale] Camera %d: failed to restart: %v", idx, err)
-			return
-		}
+	if err := manager.RestartCameraByIndex(idx); err != nil {
+		log.Printf("[Stale] Camera %d: failed to restart: %v", idx, err)
+		a.setRecovery(idx, recoveryStatus{action: "retrying in", until: a.clk.Now().Add(cooldown), holdOff: true})
+		return
+	}
 
-		// Reset frame time so we don't immediately re-trigger
-		a.frameLock.Lock()
-		a.lastFrameTime[idx] = time.Now()
-		a.frameLock.Unlock()
+	// Reset frame time so we don't immediately re-trigger
+	a.frameLock.Lock()
+	a.lastFrameTime[idx] = a.clk.Now()
+	a.frameLock.Unlock()
 
-		// Mark as connected again
-		a.updateCameraStatus(idx, true)
-		log.Printf("[Stale] Camera %d: successfully restarted", idx)
-	}(camIndex)
+	// Mark as connected again
+	a.updateCameraStatus(idx, true)
+	log.Printf("[Stale] Camera %d: successfully restarted", idx)
 }
 
 // startHotplugDetection starts a goroutine that polls for camera connect/disconnect
-func (a *App) startHotplugDetection() {
+func (a *App) startHotplugDetection(ctx context.Context) {
+	if len(a.cfg.SimulateSources) > 0 {
+		log.Println("[Hotplug] Simulated cameras, hot-plug detection off")
+		return
+	}
 	log.Println("[Hotplug] Starting camera hot-plug detection...")
 
 	interval := time.Duration(a.cfg.RescanIntervalMS) * time.Millisecond
@@ -1428,7 +2366,7 @@ func (a *App) startHotplugDetection() {
 
 	for {
 		select {
-		case <-a.hotplugStopCh:
+		case <-ctx.Done():
 			log.Println("[Hotplug] Stopping hot-plug detection")
 			return
 		case <-ticker.C:
@@ -1451,23 +2389,36 @@ func (a *App) checkCameraChanges() {
 	limit := minInt(len(cameras), len(statusSnapshot))
 	for i := 0; i < limit; i++ {
 		cam := cameras[i]
-
-		// Check if device file still exists
-		_, err := os.Stat(cam.DevicePath)
-		deviceExists := err == nil
+		var deviceExists bool
+		if a.captureBackend != nil {
+			deviceExists = a.captureBackend.Present(cam.DevicePath)
+		} else if !cam.HasDeviceNode() {
+			continue // Desktop FFmpeg input: the capture loop handles reconnects
+		} else {
+			// Check if device file still exists
+			_, err := os.Stat(cam.DevicePath)
+			deviceExists = err == nil
+		}
 
 		wasConnected := statusSnapshot[i]
 
 		if wasConnected && !deviceExists {
 			// Camera disconnected - record time for debouncing
 			a.reinitLock.Lock()
-			a.lastDisconnectTime[i] = time.Now()
+			a.lastDisconnectTime[i] = a.clk.Now()
 			a.reinitLock.Unlock()
-			log.Printf("[Hotplug] Camera %d (%s) disconnected", i, cam.DevicePath)
+			log.Printf("[Hotplug] Camera %s disconnected (%s)", a.cameraTag(i), cam.DevicePath)
+			a.toast(fmt.Sprintf("camera%d", i), a.cameraLabel(i)+" disconnected", toastWarning)
+			a.alert(alertDisconnect)
 			a.updateCameraStatus(i, false)
+			a.setRecovery(i, recoveryStatus{action: "unplugged, waiting for device"})
 		} else if !wasConnected && deviceExists {
+			if a.inRestartHoldOff(i, a.clk.Now()) {
+				continue // Stale recovery owns this camera (see recovery.go)
+			}
 			// Camera reconnected
-			log.Printf("[Hotplug] Camera %d (%s) reconnected", i, cam.DevicePath)
+			log.Printf("[Hotplug] Camera %s reconnected (%s)", a.cameraTag(i), cam.DevicePath)
+			a.toast(fmt.Sprintf("camera%d", i), a.cameraLabel(i)+" reconnected", toastInfo)
 			a.handleCameraReconnect(i)
 		}
 	}
@@ -1508,11 +2459,29 @@ func (a *App) checkForNewCameras() {
 	}
 	a.frameLock.RUnlock()
 
+	a.cfgMu.RLock()
 	cooldown := time.Duration(a.cfg.FailedCameraCooldownS * float64(time.Second))
+	a.cfgMu.RUnlock()
 	if cooldown < time.Second {
 		cooldown = time.Second
 	}
-	now := time.Now()
+	now := a.clk.Now()
+	if a.captureBackend != nil {
+		cams, _ := a.captureBackend.Discover(a.cameraSettings())
+		for _, cam := range cams {
+			if existingPaths[cam.DevicePath] {
+				continue
+			}
+			if last, ok := a.failedNewDevice[cam.DevicePath]; ok && now.Sub(last) < cooldown {
+				continue
+			}
+			log.Printf("[Hotplug] New camera detected at %s", cam.DevicePath)
+			a.failedNewDevice[cam.DevicePath] = now
+			a.handleNewCameraDevice(cam.DevicePath)
+			return
+		}
+		return
+	}
 	maxScan := maxInt(10, a.effectiveSlots()*4+4)
 
 	// Scan /dev/video* for potential new USB cameras.
@@ -1522,6 +2491,9 @@ func (a *App) checkForNewCameras() {
 		if existingPaths[devPath] {
 			continue // Already tracking this device
 		}
+		if camera.IsDeviceDisabled(a.cfg.DisabledCameras, devPath) {
+			continue
+		}
 		if last, ok := a.failedNewDevice[devPath]; ok && now.Sub(last) < cooldown {
 			continue
 		}
@@ -1579,6 +2551,13 @@ func (a *App) isUSBCaptureDevice(devPath string, existingPaths map[string]bool)
 	sysfsPath := fmt.Sprintf("/sys/class/video4linux/video%d/device/modalias", videoNum)
 	data, err := os.ReadFile(sysfsPath)
 	if err != nil {
+		if _, serr := os.Stat("/sys/class/video4linux"); serr != nil {
+			// Container without /sys: nothing can be verified, so new
+			// cameras wait for a restart rather than risking metadata nodes
+			a.sysfsMissingOnce.Do(func() {
+				log.Printf("[Hotplug] WARNING: /sys/class/video4linux not available (container without -v /sys:/sys:ro?); new cameras are picked up after a restart")
+			})
+		}
 		return false
 	}
 
@@ -1647,32 +2626,31 @@ func (a *App) handleNewCameraDevice(devPath string) {
 		time.Sleep(1500 * time.Millisecond)
 
 		// Stop existing manager and wait for cleanup
-		if a.manager != nil {
-			a.manager.Stop()
+		if old := a.manager.Load(); old != nil {
+			old.Stop()
 			time.Sleep(500 * time.Millisecond)
 		}
 
 		// Use buffer mode for decoupled capture/render with config-driven settings
-		a.manager = camera.NewManagerWithSettings(camera.Settings{
-			Width:      a.cfg.CaptureWidth,
-			Height:     a.cfg.CaptureHeight,
-			FPS:        a.cfg.CaptureFPS,
-			Format:     a.cfg.CaptureFormat,
-			MaxCameras: a.effectiveSlots(),
-		}, true)
-		if err := a.manager.Initialize(); err != nil {
+		manager := camera.NewManagerWithSettings(a.cameraSettings(), true)
+		a.manager.Store(manager)
+		if err := manager.Initialize(); err != nil {
 			log.Printf("[Hotplug] Failed to reinitialize manager: %v", err)
 			return
 		}
-		if err := a.manager.Start(); err != nil {
+		if err := manager.Start(); err != nil {
 			log.Printf("[Hotplug] Failed to start manager: %v", err)
 			return
 		}
 
-		cams := a.manager.GetCameras()
+		cams := manager.GetCameras()
 		a.frameLock.Lock()
 		a.cameras = cams
 		a.frameLock.Unlock()
+		a.updateSlotDewarp(cams)
+		a.updateSlotTransforms(cams)
+		a.updateSlotNames(cams)
+		a.updateCaptureVisibility()
 		for i := 0; i < a.effectiveSlots(); i++ {
 			a.updateCameraStatus(i, false)
 		}
@@ -1683,6 +2661,7 @@ func (a *App) handleNewCameraDevice(devPath string) {
 				a.updateCameraStatus(i, true)
 			}
 		}
+		a.showPendingFullscreen()
 	}()
 }
 
@@ -1691,7 +2670,10 @@ func (a *App) handleNewCameraDevice(devPath string) {
 func (a *App) handleCameraReconnect(camIndex int) {
 	// Debounce reconnect checks to avoid flapping on unstable USB links.
 	debounce := defaultReconnectDebounce
-	if cfgDelay := time.Duration(a.cfg.FailedCameraCooldownS * float64(time.Second)); cfgDelay > 0 && cfgDelay < debounce {
+	a.cfgMu.RLock()
+	cfgDelay := time.Duration(a.cfg.FailedCameraCooldownS * float64(time.Second))
+	a.cfgMu.RUnlock()
+	if cfgDelay > 0 && cfgDelay < debounce {
 		debounce = cfgDelay
 	}
 
@@ -1700,9 +2682,11 @@ func (a *App) handleCameraReconnect(camIndex int) {
 		a.reinitLock.Unlock()
 		return
 	}
-	timeSinceDisconnect := time.Since(a.lastDisconnectTime[camIndex])
+	timeSinceDisconnect := a.clk.Now().Sub(a.lastDisconnectTime[camIndex])
 	if timeSinceDisconnect < debounce {
+		retryAt := a.lastDisconnectTime[camIndex].Add(debounce)
 		a.reinitLock.Unlock()
+		a.setRecovery(camIndex, recoveryStatus{action: "reconnecting in", until: retryAt})
 		log.Printf("[Hotplug] Camera %d: Ignoring reconnect (%.1fs since disconnect, need %.1fs debounce)",
 			camIndex, timeSinceDisconnect.Seconds(), debounce.Seconds())
 		return
@@ -1717,6 +2701,8 @@ func (a *App) handleCameraReconnect(camIndex int) {
 	a.reinitLock.Unlock()
 
 	log.Printf("[Hotplug] Camera %d: Attempting per-camera restart (other cameras unaffected)...", camIndex)
+	a.publishRestartEvent(camIndex, "reconnect")
+	a.setRecovery(camIndex, recoveryStatus{action: "reconnecting"})
 
 	go func() {
 		defer func() {
@@ -1731,7 +2717,7 @@ func (a *App) handleCameraReconnect(camIndex int) {
 		// Kill any stale processes holding the device before restart
 		a.frameLock.RLock()
 		var devPath string
-		if camIndex < len(a.cameras) {
+		if camIndex < len(a.cameras) && a.cameras[camIndex].HasDeviceNode() {
 			devPath = a.cameras[camIndex].DevicePath
 		}
 		a.frameLock.RUnlock()
@@ -1740,9 +2726,10 @@ func (a *App) handleCameraReconnect(camIndex int) {
 		}
 
 		// Restart only this camera's worker
-		if a.manager != nil {
-			if err := a.manager.RestartCameraByIndex(camIndex); err != nil {
+		if manager := a.manager.Load(); manager != nil {
+			if err := manager.RestartCameraByIndex(camIndex); err != nil {
 				log.Printf("[Hotplug] Camera %d: Failed to restart: %v", camIndex, err)
+				a.setRecovery(camIndex, recoveryStatus{action: "reconnect failed, rescanning"})
 				return
 			}
 		}
@@ -1757,66 +2744,57 @@ func (a *App) handleCameraReconnect(camIndex int) {
 func (a *App) cleanup() {
 	a.cleanupOnce.Do(func() {
 		log.Println("[UI] Cleanup: stopping all processes...")
+		systemd.Stopping()
+
+		// Stop supervision first so shutdown isn't mistaken for a hang
+		if a.watchdog != nil {
+			a.watchdog.Stop()
+		}
+		if f := a.faults.Swap(nil); f != nil {
+			f.Stop()
+		}
+		a.finishTrip("shutdown")
 
-		// Stop hot-plug detection
-		close(a.hotplugStopCh)
+		// Stop the background loops; refresh, capture and fullscreen
+		// are waited for, so none outlives the camera manager
+		a.cancel()
+		a.stopLoops()
+
+		// Let snapshots and clips in progress finish writing
+		a.flushWrites(flushTimeout)
+		a.flushStaged()
 
 		// Stop performance controller
-		if a.perfController != nil {
-			a.perfController.Stop()
+		if pc := a.perfController.Load(); pc != nil {
+			pc.Stop()
+		}
+
+		// Disconnect from the MQTT broker
+		if c := a.mqttClient.Swap(nil); c != nil {
+			c.Stop()
 		}
+		a.stopHealthServer()
+		a.stopDebugServer()
 
 		// Stop camera manager (kills FFmpeg processes)
-		if a.manager != nil {
-			a.manager.Stop()
+		if m := a.manager.Load(); m != nil {
+			m.Stop()
 			log.Println("[UI] Cleanup: stopped camera manager")
 		}
+		a.stopPower(a.cfg.PowerOffOnExit)
 
 		log.Println("[UI] Cleanup: complete, exiting...")
-		a.fyneApp.Quit()
+		a.quit()
 	})
 }
 
-// restart stops all processes and restarts the application
-func (a *App) restart() {
-	log.Println("[UI] Restart: stopping all processes...")
-
-	// Stop performance controller
-	if a.perfController != nil {
-		a.perfController.Stop()
-	}
-
-	// Stop camera manager
-	if a.manager != nil {
-		a.manager.Stop()
-	}
-
-	// Stop all background goroutines (hotplug, stale detection, health, refresh)
-	a.cleanupOnce.Do(func() {
-		close(a.hotplugStopCh)
-	})
-
-	log.Println("[UI] Restart: relaunching application...")
-
-	// Get the path to the current executable
-	executable, err := os.Executable()
-	if err != nil {
-		log.Printf("[UI] Restart: failed to get executable path: %v", err)
-		return
-	}
-
-	// Launch new instance
-	cmd := exec.Command(executable)
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	cmd.Env = os.Environ()
-
-	if err := cmd.Start(); err != nil {
-		log.Printf("[UI] Restart: failed to start new instance: %v", err)
+// quit ends Start: stops the Fyne event loop, or unblocks the headless
+// wait.
+func (a *App) quit() {
+	if a.headless {
+		a.quitOnce.Do(func() { close(a.doneCh) })
 		return
 	}
-
-	log.Println("[UI] Restart: new instance started, exiting current...")
 	a.fyneApp.Quit()
 }
 
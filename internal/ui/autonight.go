@@ -0,0 +1,202 @@
+package ui
+
+import (
+	"camera-dashboard-go/internal/sensors"
+	"fmt"
+	"log"
+	"time"
+)
+
+// =============================================================================
+// Automatic night mode
+// =============================================================================
+// [auto_night] source switches night mode on at dusk and off at dawn:
+//   luma      mean brightness of the camera pictures (below dark_luma is
+//             night). Cameras brighten dark scenes with their exposure,
+//             so set dark_luma from what the cameras show at dusk.
+//   schedule  sunset to sunrise at latitude/longitude (see sensors/sun.go)
+//   sensor    a BH1750 ambient light sensor on I2C (below dark_lux)
+// The light is read every autoNightInterval. Luma and lux have a
+// hysteresis band (day needs dark_luma + autoNightLumaHysteresis, or
+// autoNightLuxRatio x dark_lux) and a change must hold for hold_sec, so
+// dusk, street lights and short tunnels don't make it flicker.
+//
+// The first reading applies at once. After that only a change of the
+// automatic decision switches night mode, so toggling it by hand (the
+// settings tile, MQTT) sticks until the next dusk or dawn.
+// =============================================================================
+
+const (
+	autoNightInterval       = 5 * time.Second
+	autoNightLumaHysteresis = 15  // Luma above dark_luma needed for day
+	autoNightLuxRatio       = 2.0 // Day needs this multiple of dark_lux
+)
+
+// lightReading is one automatic night mode measurement: dark calls for
+// night, light for day; neither (the hysteresis band) keeps the state.
+type lightReading struct {
+	dark, light bool
+	desc        string // For the log, e.g. "scene luma 32"
+}
+
+// nightSwitch decides when automatic night mode changes.
+type nightSwitch struct {
+	measured bool // False until the first reading, which applies at once
+	night    bool
+	pending  time.Time // When readings first called for the other state; zero = none
+}
+
+// update takes a reading and returns whether it is night and whether
+// that just changed.
+func (s *nightSwitch) update(r lightReading, hold time.Duration, now time.Time) (night, changed bool) {
+	want := s.night
+	switch {
+	case r.dark:
+		want = true
+	case r.light:
+		want = false
+	}
+	if !s.measured {
+		s.measured, s.night = true, want
+		return s.night, true
+	}
+	if want == s.night {
+		s.pending = time.Time{}
+		return s.night, false
+	}
+	if s.pending.IsZero() {
+		s.pending = now
+	}
+	if now.Sub(s.pending) < hold {
+		return s.night, false
+	}
+	s.night, s.pending = want, time.Time{}
+	return s.night, true
+}
+
+// lumaReading classifies a mean scene luma.
+func lumaReading(luma float64, darkLuma int) lightReading {
+	return lightReading{
+		dark:  luma < float64(darkLuma),
+		light: luma > float64(darkLuma+autoNightLumaHysteresis),
+		desc:  fmt.Sprintf("scene luma %.0f", luma),
+	}
+}
+
+// luxReading classifies an ambient light level.
+func luxReading(lux, darkLux float64) lightReading {
+	return lightReading{
+		dark:  lux < darkLux,
+		light: lux > darkLux*autoNightLuxRatio,
+		desc:  fmt.Sprintf("%.1f lx", lux),
+	}
+}
+
+// sunReading classifies the sun's position.
+func sunReading(now time.Time, latitude, longitude float64) lightReading {
+	elevation := sensors.SunElevation(now, latitude, longitude)
+	dark := elevation < sensors.SunsetElevation
+	return lightReading{dark: dark, light: !dark, desc: fmt.Sprintf("sun at %.1f°", elevation)}
+}
+
+// startAutoNight starts switching night mode from [auto_night] source.
+func (a *App) startAutoNight() {
+	var read func(now time.Time) (lightReading, error)
+	hold := time.Duration(a.cfg.AutoNightHoldSec * float64(time.Second))
+	switch a.cfg.AutoNightSource {
+	case "luma":
+		read = func(time.Time) (lightReading, error) {
+			luma, ok := a.meanCameraLuma()
+			if !ok {
+				return lightReading{}, fmt.Errorf("no camera picture")
+			}
+			return lumaReading(luma, a.cfg.AutoNightDarkLuma), nil
+		}
+		log.Printf("[AutoNight] Source: camera luma (night below %d)", a.cfg.AutoNightDarkLuma)
+	case "schedule":
+		lat, lon := a.cfg.AutoNightLatitude, a.cfg.AutoNightLongitude
+		if lat == 0 && lon == 0 {
+			return // Validate warned
+		}
+		hold = 0
+		read = func(now time.Time) (lightReading, error) {
+			return sunReading(now, lat, lon), nil
+		}
+		log.Printf("[AutoNight] Source: sunset to sunrise at %.3f, %.3f", lat, lon)
+	case "sensor":
+		sensor, err := sensors.OpenBH1750(a.cfg.AutoNightSensorBus, a.cfg.AutoNightSensorAddr)
+		if err != nil {
+			log.Printf("[AutoNight] WARNING: light sensor unavailable: %v - automatic night mode is off", err)
+			return
+		}
+		go func() {
+			<-a.ctx.Done()
+			sensor.Close()
+		}()
+		read = func(time.Time) (lightReading, error) {
+			lux, err := sensor.Lux()
+			if err != nil {
+				return lightReading{}, err
+			}
+			return luxReading(lux, a.cfg.AutoNightDarkLux), nil
+		}
+		log.Printf("[AutoNight] Source: BH1750 at %s 0x%02x (night below %.1f lx)",
+			a.cfg.AutoNightSensorBus, a.cfg.AutoNightSensorAddr, a.cfg.AutoNightDarkLux)
+	default:
+		return
+	}
+	go a.runAutoNight(read, hold)
+}
+
+// runAutoNight applies readings until shutdown.
+func (a *App) runAutoNight(read func(time.Time) (lightReading, error), hold time.Duration) {
+	ticker := time.NewTicker(autoNightInterval)
+	defer ticker.Stop()
+
+	var sw nightSwitch
+	failing := false
+	for {
+		now := time.Now()
+		if r, err := read(now); err != nil {
+			if !failing {
+				log.Printf("[AutoNight] WARNING: no reading: %v", err)
+			}
+			failing = true
+		} else {
+			failing = false
+			if night, changed := sw.update(r, hold, now); changed {
+				if night {
+					log.Printf("[AutoNight] Night (%s)", r.desc)
+				} else {
+					log.Printf("[AutoNight] Day (%s)", r.desc)
+				}
+				a.setNightMode(night)
+			}
+		}
+
+		select {
+		case <-a.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// meanCameraLuma averages the brightness of the connected cameras'
+// current pictures.
+func (a *App) meanCameraLuma() (float64, bool) {
+	a.frameLock.RLock()
+	defer a.frameLock.RUnlock()
+	var sum float64
+	n := 0
+	for i, frame := range a.cameraFrames {
+		if frame != nil && i < len(a.cameraStatus) && a.cameraStatus[i] {
+			sum += meanLuma(frame)
+			n++
+		}
+	}
+	if n == 0 {
+		return 0, false
+	}
+	return sum / float64(n), true
+}
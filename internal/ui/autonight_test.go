@@ -0,0 +1,59 @@
+package ui
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNightSwitch(t *testing.T) {
+	var s nightSwitch
+	start := time.Unix(1000, 0)
+	hold := 60 * time.Second
+	step := func(luma float64, after time.Duration) (bool, bool) {
+		return s.update(lumaReading(luma, 50), hold, start.Add(after))
+	}
+
+	// The first reading applies at once
+	if night, changed := step(30, 0); !night || !changed {
+		t.Fatalf("first dark reading = %v, %v; want night, changed", night, changed)
+	}
+	// Inside the hysteresis band nothing changes
+	if night, changed := step(60, 10*time.Second); !night || changed {
+		t.Errorf("luma 60 = %v, %v; want night kept", night, changed)
+	}
+	// Daylight must hold for hold_sec
+	if _, changed := step(120, 20*time.Second); changed {
+		t.Error("changed before hold")
+	}
+	if _, changed := step(30, 50*time.Second); changed {
+		t.Error("dark again should cancel the pending change")
+	}
+	if _, changed := step(120, 60*time.Second); changed {
+		t.Error("hold should restart after a cancelled change")
+	}
+	if night, changed := step(120, 120*time.Second); night || !changed {
+		t.Errorf("after hold = %v, %v; want day, changed", night, changed)
+	}
+	if _, changed := step(120, 125*time.Second); changed {
+		t.Error("changed again without a new decision")
+	}
+}
+
+func TestLightReadings(t *testing.T) {
+	if r := luxReading(5, 10); !r.dark || r.light {
+		t.Errorf("5 lx = %+v, want dark", r)
+	}
+	if r := luxReading(15, 10); r.dark || r.light {
+		t.Errorf("15 lx = %+v, want the hysteresis band", r)
+	}
+	if r := luxReading(25, 10); r.dark || !r.light {
+		t.Errorf("25 lx = %+v, want light", r)
+	}
+	// London midnight and noon
+	if r := sunReading(time.Date(2024, 6, 20, 0, 0, 0, 0, time.UTC), 51.5, -0.13); !r.dark {
+		t.Errorf("midnight = %+v, want dark", r)
+	}
+	if r := sunReading(time.Date(2024, 6, 20, 12, 0, 0, 0, time.UTC), 51.5, -0.13); !r.light {
+		t.Errorf("noon = %+v, want light", r)
+	}
+}
@@ -0,0 +1,124 @@
+package ui
+
+import (
+	"camera-dashboard-go/internal/sensors"
+	"fmt"
+	"log"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// =============================================================================
+// Battery monitor
+// =============================================================================
+// With [battery] enabled, the vehicle battery voltage is read from an
+// I2C ADC (INA219, or ADS1115 behind a divider; see internal/sensors)
+// every batterySampleInterval and shown on the settings tile. Cameras
+// and a Pi left running with the engine off drain a car battery in a
+// day, so once the average voltage has stayed below shutdown_v for
+// shutdown_delay_sec the dashboard shuts down: it publishes an MQTT
+// "low_battery" event, runs shutdown_command (e.g. "systemctl poweroff")
+// if set, and exits cleanly, which also switches the camera power rails
+// off when [power] off_on_exit is set.
+// =============================================================================
+
+const batterySampleInterval = 2 * time.Second
+
+// startBattery opens the ADC and starts monitoring if [battery] is
+// enabled. A missing ADC is logged, not fatal.
+func (a *App) startBattery() {
+	if !a.cfg.BatteryEnabled {
+		return
+	}
+	sensor, err := sensors.OpenBatterySensor(a.cfg.BatteryADC, a.cfg.BatteryBus,
+		a.cfg.BatteryAddress, a.cfg.BatteryChannel, a.cfg.BatteryDivider)
+	if err != nil {
+		log.Printf("[Battery] WARNING: battery monitor disabled: %v", err)
+		return
+	}
+	if a.cfg.BatteryShutdownV > 0 {
+		log.Printf("[Battery] %s on %s, shutdown below %.1f V for %.0fs",
+			a.cfg.BatteryADC, a.cfg.BatteryBus, a.cfg.BatteryShutdownV, a.cfg.BatteryShutdownDelaySec)
+	} else {
+		log.Printf("[Battery] %s on %s, no low-voltage shutdown", a.cfg.BatteryADC, a.cfg.BatteryBus)
+	}
+	guard := sensors.NewBatteryGuard(a.cfg.BatteryShutdownV, secondsToDuration(a.cfg.BatteryShutdownDelaySec))
+	go func() {
+		defer sensor.Close()
+		a.monitorBattery(sensor, guard)
+	}()
+}
+
+// monitorBattery reads the voltage until shutdown or a low battery.
+func (a *App) monitorBattery(sensor sensors.VoltageSensor, guard *sensors.BatteryGuard) {
+	ticker := time.NewTicker(batterySampleInterval)
+	defer ticker.Stop()
+
+	failing, wasBelow := false, false
+	for {
+		if volts, err := sensor.Voltage(); err != nil {
+			if !failing {
+				log.Printf("[Battery] WARNING: read failed: %v", err)
+				a.showBattery("Battery: no reading", false)
+			}
+			failing = true
+		} else {
+			failing = false
+			average, below, low := guard.Update(volts, time.Now())
+			if below && !wasBelow {
+				log.Printf("[Battery] Low: %.2f V (shutdown below %.1f V)", average, a.cfg.BatteryShutdownV)
+			} else if !below && wasBelow {
+				log.Printf("[Battery] Recovered: %.2f V", average)
+			}
+			wasBelow = below
+			a.showBattery(batteryLabel(average, below), below)
+			if low {
+				a.lowBatteryShutdown(average)
+				return
+			}
+		}
+
+		select {
+		case <-a.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// batteryLabel is the settings tile text for a voltage.
+func batteryLabel(volts float64, low bool) string {
+	if low {
+		return fmt.Sprintf("Battery %.1f V LOW", volts)
+	}
+	return fmt.Sprintf("Battery %.1f V", volts)
+}
+
+// showBattery updates the settings tile (no-op headless).
+func (a *App) showBattery(text string, low bool) {
+	if a.settingsWidget != nil {
+		a.settingsWidget.SetBattery(text, low)
+	}
+}
+
+// lowBatteryShutdown stops the dashboard to protect the battery.
+func (a *App) lowBatteryShutdown(volts float64) {
+	log.Printf("[Battery] %.2f V below %.1f V for %.0fs - shutting down",
+		volts, a.cfg.BatteryShutdownV, a.cfg.BatteryShutdownDelaySec)
+	a.publishJSON("event", map[string]interface{}{
+		"type":      "low_battery",
+		"voltage":   volts,
+		"threshold": a.cfg.BatteryShutdownV,
+		"timestamp": a.clock.Now().Unix(),
+	}, false)
+
+	if args := strings.Fields(a.cfg.BatteryShutdownCmd); len(args) > 0 {
+		// Started, not waited for: a poweroff stops this process too
+		log.Printf("[Battery] Running %s", a.cfg.BatteryShutdownCmd)
+		if err := exec.Command(args[0], args[1:]...).Start(); err != nil {
+			log.Printf("[Battery] WARNING: shutdown command failed: %v", err)
+		}
+	}
+	a.cleanup()
+}
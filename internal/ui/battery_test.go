@@ -0,0 +1,39 @@
+package ui
+
+import (
+	"camera-dashboard-go/internal/config"
+	"camera-dashboard-go/internal/sensors"
+	"testing"
+	"time"
+)
+
+// fakeVoltage is a VoltageSensor with a fixed reading.
+type fakeVoltage float64
+
+func (v fakeVoltage) Voltage() (float64, error) { return float64(v), nil }
+func (v fakeVoltage) Close() error              { return nil }
+
+func TestLowBatteryShutdown(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.TripReportDir = ""
+	a := NewHeadlessApp(cfg)
+	done := make(chan struct{})
+	go func() {
+		a.monitorBattery(fakeVoltage(10.9), sensors.NewBatteryGuard(11.5, 0))
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("monitor did not stop on a low battery")
+	}
+	select {
+	case <-a.doneCh:
+	default:
+		t.Error("low battery should shut the dashboard down")
+	}
+
+	if got := batteryLabel(11.04, true); got != "Battery 11.0 V LOW" {
+		t.Errorf("batteryLabel = %q", got)
+	}
+}
@@ -0,0 +1,190 @@
+package ui
+
+import (
+	"camera-dashboard-go/internal/camera"
+	"camera-dashboard-go/internal/helpers"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// =============================================================================
+// Saved clips
+// =============================================================================
+// With [clips] seconds > 0 each capture worker buffers the last N seconds
+// of JPEG frames (camera.ClipBuffer). "Save clip" (fullscreen button,
+// MQTT clip command, G-sensor incidents) writes them as a raw MJPEG
+// stream (<time>_<camera>.mjpeg, playable with ffplay or convertible with
+// ffmpeg) plus a JSON sidecar with the camera, time span and, when [gps]
+// has a fix, the position. Files are written read-only; only the disk
+// manager (diskspace.go) deletes them, and never incident clips.
+// =============================================================================
+
+// clipMeta is the JSON sidecar written next to each clip.
+type clipMeta struct {
+	Camera   int       `json:"camera"`
+	DeviceID string    `json:"device_id"`
+	Name     string    `json:"name"`
+	Trigger  string    `json:"trigger"` // "button", "mqtt" or "incident"
+	SavedAt  time.Time `json:"saved_at"`
+	Start    time.Time `json:"start"`
+	End      time.Time `json:"end"`
+	Frames   int       `json:"frames"`
+	FPS      float64   `json:"fps"`
+	GPS      *clipGPS  `json:"gps,omitempty"`
+}
+
+type clipGPS struct {
+	Lat     float64 `json:"lat"`
+	Lon     float64 `json:"lon"`
+	SpeedMS float64 `json:"speed_ms"`
+}
+
+// clipWindow is the history each capture worker keeps (0 = clips off).
+func (a *App) clipWindow() time.Duration {
+	if a.cfg.ClipsDir == "" {
+		return 0
+	}
+	return time.Duration(a.cfg.ClipSeconds) * time.Second
+}
+
+// saveClip writes the buffered frames of camIndex and returns the clip path.
+func (a *App) saveClip(camIndex int, trigger string) (string, error) {
+	defer a.trackWrite()()
+	if a.clipWindow() == 0 {
+		return "", fmt.Errorf("clips disabled ([clips] seconds = 0 or no writable directory)")
+	}
+	a.frameLock.RLock()
+	if camIndex < 0 || camIndex >= len(a.cameras) {
+		a.frameLock.RUnlock()
+		return "", fmt.Errorf("no camera at index %d", camIndex)
+	}
+	cam := a.cameras[camIndex]
+	a.frameLock.RUnlock()
+
+	manager := a.manager.Load()
+	if manager == nil {
+		return "", fmt.Errorf("cameras not started")
+	}
+	worker := manager.GetWorker(cam.DeviceID)
+	if worker == nil {
+		return "", fmt.Errorf("camera %s has no capture worker", cam.DeviceID)
+	}
+	frames := recentFrames(worker.ClipFrames(), a.clipWindow()) // The ring may be longer for replay
+	if len(frames) == 0 {
+		return "", fmt.Errorf("camera %s has no buffered frames", cam.DeviceID)
+	}
+
+	savedAt := time.Now()
+	meta := clipMeta{
+		Camera:   camIndex,
+		DeviceID: cam.DeviceID,
+		Name:     cam.Name,
+		Trigger:  trigger,
+		SavedAt:  a.clock.At(savedAt),
+		Start:    a.clock.At(frames[0].At),
+		End:      a.clock.At(frames[len(frames)-1].At),
+		Frames:   len(frames),
+	}
+	if span := meta.End.Sub(meta.Start).Seconds(); span > 0 {
+		meta.FPS = float64(len(frames)-1) / span
+	}
+	if fix, ok := a.currentFix(); ok {
+		meta.GPS = &clipGPS{Lat: fix.Lat, Lon: fix.Lon, SpeedMS: fix.SpeedMS}
+	}
+
+	if err := a.recordingBlocked(a.cfg.ClipsDir); err != nil {
+		return "", err
+	}
+	dir := a.recordingDir("clips", a.cfg.ClipsDir)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("create clip dir: %w", err)
+	}
+	base := filepath.Join(dir, a.recordingKey("clips", a.cfg.ClipsDir, savedAt, cam.DeviceID))
+	path := base + ".mjpeg"
+	ioClass, _ := helpers.ParseIOClass(a.cfg.WriteIOClass)
+	err := helpers.RunWithIOPriority(ioClass, a.cfg.WriteIOLevel, func() error {
+		if err := writeClip(path, frames); err != nil {
+			return err
+		}
+		return writeClipMeta(base+".json", meta)
+	})
+	if err != nil {
+		return "", err
+	}
+
+	log.Printf("[Clip] Camera %d (%s): %d frames (%.1fs) saved to %s",
+		camIndex, cam.DeviceID, meta.Frames, meta.End.Sub(meta.Start).Seconds(), path)
+	a.toast("clip", fmt.Sprintf("Clip saved: %s (%.0fs)", a.cameraLabel(camIndex), meta.End.Sub(meta.Start).Seconds()), toastInfo)
+	return path, nil
+}
+
+// saveFullscreenClip is the fullscreen "Save clip" button. The button
+// stays disabled while the files are written.
+func (a *App) saveFullscreenClip() {
+	if !a.isFullscreen.Load() {
+		return
+	}
+	camIndex := a.gridSlots[a.fullscreenSlot]
+	a.fullscreenClip.Disable()
+	go func() {
+		defer a.fullscreenClip.Enable()
+		if _, err := a.saveClip(camIndex, "button"); err != nil {
+			log.Printf("[Clip] WARNING: save failed: %v", err)
+			a.toast("clip", "Clip not saved", toastWarning)
+		}
+	}()
+}
+
+// saveAllClips saves a clip of every connected camera, returning the paths.
+func (a *App) saveAllClips(trigger string) []string {
+	a.frameLock.RLock()
+	count := len(a.cameras)
+	a.frameLock.RUnlock()
+
+	var paths []string
+	for i := 0; i < minInt(count, a.effectiveSlots()); i++ {
+		path, err := a.saveClip(i, trigger)
+		if err != nil {
+			log.Printf("[Clip] Camera %d skipped: %v", i, err)
+			continue
+		}
+		paths = append(paths, path)
+	}
+	if len(paths) > 1 {
+		a.toast("clip", fmt.Sprintf("%d clips saved", len(paths)), toastInfo)
+	}
+	return paths
+}
+
+// writeClip concatenates the JPEG frames into a read-only MJPEG file.
+func writeClip(path string, frames []camera.ClipFrame) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o444)
+	if err != nil {
+		return err
+	}
+	for _, frame := range frames {
+		if _, err := f.Write(frame.JPEG); err != nil {
+			f.Close()
+			os.Remove(path)
+			return err
+		}
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// writeClipMeta writes the read-only JSON sidecar.
+func writeClipMeta(path string, meta clipMeta) error {
+	data, err := json.MarshalIndent(meta, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o444)
+}
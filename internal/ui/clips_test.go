@@ -0,0 +1,45 @@
+package ui
+
+import (
+	"bytes"
+	"camera-dashboard-go/internal/camera"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWriteClip(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "clip.mjpeg")
+	frames := []camera.ClipFrame{{JPEG: []byte{0xFF, 0xD8, 1}}, {JPEG: []byte{0xFF, 0xD8, 2}}}
+	if err := writeClip(path, frames); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil || !bytes.Equal(data, []byte{0xFF, 0xD8, 1, 0xFF, 0xD8, 2}) {
+		t.Errorf("clip = % x, %v", data, err)
+	}
+	if info, _ := os.Stat(path); info.Mode().Perm()&0o222 != 0 {
+		t.Errorf("clip mode = %v, want read-only", info.Mode())
+	}
+	if err := writeClip(path, frames); err == nil {
+		t.Error("an existing clip should not be overwritten")
+	}
+
+	start := time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+	meta := clipMeta{Camera: 1, DeviceID: "video2", Trigger: "button", Start: start, End: start.Add(time.Second),
+		Frames: 2, GPS: &clipGPS{Lat: 52.1, Lon: 4.3}}
+	if err := writeClipMeta(filepath.Join(dir, "clip.json"), meta); err != nil {
+		t.Fatal(err)
+	}
+	var got map[string]interface{}
+	data, _ = os.ReadFile(filepath.Join(dir, "clip.json"))
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("sidecar is not JSON: %v", err)
+	}
+	if got["device_id"] != "video2" || got["gps"].(map[string]interface{})["lat"] != 52.1 {
+		t.Errorf("sidecar = %s", data)
+	}
+}
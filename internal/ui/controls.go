@@ -0,0 +1,192 @@
+package ui
+
+import (
+	"camera-dashboard-go/internal/camera"
+	"fmt"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+	"image/color"
+	"log"
+)
+
+// =============================================================================
+// Camera controls panel
+// =============================================================================
+// The Adjust button in fullscreen opens a side panel with the camera's
+// image controls (brightness, contrast, saturation, exposure, auto white
+// balance) and the aim assist overlay (aim.go). Changes go to the camera as soon as a slider is released and
+// last until the camera is unplugged; [controls] in config.ini sets the
+// values applied at startup (and on every connect for cameras listed in
+// reapply_on_connect, which overrides live adjustments). Tapping the picture closes the panel along
+// with fullscreen.
+// =============================================================================
+
+// controlsPanel is the fullscreen image-controls overlay.
+type controlsPanel struct {
+	app      *App
+	content  *fyne.Container // Overlay root; hidden while closed
+	title    *widget.Label
+	status   *widget.Label
+	controls *fyne.Container // Rebuilt for each camera
+	aim      *widget.Check   // Aim assist overlay (see aim.go)
+	device   *camera.Controls
+}
+
+func newControlsPanel(a *App) *controlsPanel {
+	p := &controlsPanel{app: a}
+	p.title = widget.NewLabel("")
+	p.title.TextStyle = fyne.TextStyle{Bold: true}
+	p.status = widget.NewLabel("")
+	p.status.Wrapping = fyne.TextWrapWord
+	p.controls = container.NewVBox()
+	p.aim = widget.NewCheck("Aim assist (grid + level)", a.setAimAssist)
+
+	buttons := container.NewGridWithColumns(2,
+		widget.NewButton("Defaults", p.reset),
+		widget.NewButton("Close", p.close),
+	)
+	body := container.NewBorder(p.title, container.NewVBox(p.aim, p.status, buttons), nil, nil,
+		container.NewVScroll(p.controls))
+
+	bg := canvas.NewRectangle(color.RGBA{30, 30, 35, 220})
+	panel := container.NewStack(bg, container.NewPadded(body))
+	// Right-hand column; the rest of the picture stays visible and tappable
+	p.content = container.NewBorder(nil, nil, nil, container.NewGridWrap(fyne.NewSize(320, 480), panel))
+	p.content.Hide()
+	return p
+}
+
+// open shows the controls of the camera at camIndex.
+func (p *controlsPanel) open(camIndex int) {
+	a := p.app
+	a.frameLock.RLock()
+	if camIndex < 0 || camIndex >= len(a.cameras) {
+		a.frameLock.RUnlock()
+		return
+	}
+	cam := a.cameras[camIndex]
+	a.frameLock.RUnlock()
+
+	p.title.SetText(fmt.Sprintf("%s - %s", cam.DeviceID, cam.Name))
+	p.aim.SetChecked(a.aimAssist.Load())
+	if !cam.HasDeviceNode() {
+		p.device = nil
+		p.controls.RemoveAll()
+		p.status.SetText("Image controls are only available for V4L2 cameras.")
+		p.content.Show()
+		return
+	}
+	log.Printf("[UI] Camera controls: %s (%s)", cam.DeviceID, cam.DevicePath)
+	p.device = camera.NewControls(cam.DevicePath)
+	p.status.SetText("Reading controls...")
+	p.controls.RemoveAll()
+	p.content.Show()
+
+	// v4l2-ctl takes a moment; keep the tap responsive
+	device := p.device
+	go func() {
+		list, err := device.List()
+		if device != p.device {
+			return // Panel reopened for another camera meanwhile
+		}
+		if err != nil {
+			log.Printf("[UI] WARNING: reading camera controls failed: %v", err)
+			p.status.SetText("Controls unavailable: " + err.Error())
+			return
+		}
+		p.build(list)
+	}()
+}
+
+func (p *controlsPanel) close() {
+	p.device = nil
+	p.content.Hide()
+}
+
+// build creates one widget per control.
+func (p *controlsPanel) build(list []camera.ControlInfo) {
+	p.controls.RemoveAll()
+	if len(list) == 0 {
+		p.status.SetText("This camera has no adjustable controls.")
+		return
+	}
+	p.status.SetText("")
+	for _, info := range list {
+		info := info
+		if info.IsBool() {
+			check := widget.NewCheck(controlLabel(info.Name), nil)
+			check.SetChecked(info.Value != 0)
+			check.OnChanged = func(on bool) {
+				value := 0
+				if on {
+					value = 1
+				}
+				p.set(info.Name, value)
+			}
+			p.controls.Add(check)
+			continue
+		}
+
+		label := widget.NewLabel(fmt.Sprintf("%s: %d", controlLabel(info.Name), info.Value))
+		slider := widget.NewSlider(float64(info.Min), float64(info.Max))
+		if info.Step > 0 {
+			slider.Step = float64(info.Step)
+		}
+		slider.SetValue(float64(info.Value))
+		slider.OnChanged = func(v float64) {
+			label.SetText(fmt.Sprintf("%s: %d", controlLabel(info.Name), int(v)))
+		}
+		slider.OnChangeEnded = func(v float64) { p.set(info.Name, int(v)) }
+		p.controls.Add(label)
+		p.controls.Add(slider)
+	}
+}
+
+func (p *controlsPanel) set(name string, value int) {
+	if p.device == nil {
+		return
+	}
+	if err := p.device.Set(name, value); err != nil {
+		log.Printf("[UI] WARNING: %v", err)
+		p.status.SetText("Failed: " + err.Error())
+		return
+	}
+	p.status.SetText("")
+}
+
+// reset restores the driver defaults and reloads the controls.
+func (p *controlsPanel) reset() {
+	device := p.device
+	if device == nil {
+		return
+	}
+	if err := device.Reset(); err != nil {
+		log.Printf("[UI] WARNING: %v", err)
+		p.status.SetText("Failed: " + err.Error())
+		return
+	}
+	list, err := device.List()
+	if err != nil {
+		p.status.SetText("Controls unavailable: " + err.Error())
+		return
+	}
+	p.build(list)
+}
+
+func controlLabel(name string) string {
+	switch name {
+	case camera.ControlBrightness:
+		return "Brightness"
+	case camera.ControlContrast:
+		return "Contrast"
+	case camera.ControlSaturation:
+		return "Saturation"
+	case camera.ControlExposure:
+		return "Exposure"
+	case camera.ControlAutoWhiteBalance:
+		return "Auto white balance"
+	}
+	return name
+}
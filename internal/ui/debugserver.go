@@ -0,0 +1,142 @@
+package ui
+
+import (
+	"camera-dashboard-go/internal/camera"
+	"camera-dashboard-go/internal/helpers"
+	"errors"
+	"expvar"
+	"log"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// =============================================================================
+// Debug endpoint
+// =============================================================================
+// Optional HTTP listener for profiling on the Pi without rebuilding
+// ([health] debug_addr, e.g. "127.0.0.1:6060"; empty = off):
+//
+//   /debug/pprof/        net/http/pprof (go tool pprof http://.../profile)
+//   /debug/vars          expvar: memstats, cmdline and "dashboard", the
+//                        counters below
+//   /debug/goroutines    POST: logs the stack of every goroutine and
+//                        returns it (a hung loop shows up here)
+//
+// The "dashboard" counters cover the life of the process: frames_decoded,
+// frames_dropped (FPS limit, off screen, decode errors), decode_errors,
+// camera_restarts (capture workers), restarts (soft restarts, see
+// restart.go) and goroutines.
+//
+// There is no authentication, and pprof can stall capture while it
+// profiles: bind it to localhost, or limit it to a trusted network with
+// [health] allowed_cidrs (see healthserver.go). Any other address is
+// refused. It is separate from
+// /healthz so the health port can be exposed without it.
+// =============================================================================
+
+// debugApp is the app the "dashboard" expvar reads; expvar names are
+// global, so it is published once.
+var (
+	debugApp     atomic.Pointer[App]
+	debugVarOnce sync.Once
+)
+
+// startDebugServer serves the debug endpoint if [health] debug_addr is
+// set. An address other clients can reach needs [health] allowed_cidrs.
+func (a *App) startDebugServer() {
+	if a.cfg.DebugHTTPAddr == "" {
+		return
+	}
+	if !helpers.IsLoopbackAddr(a.cfg.DebugHTTPAddr) && len(a.cfg.HealthAllowedCIDRs) == 0 {
+		log.Printf("[Debug] WARNING: debug endpoint disabled: %s is not loopback and [health] allowed_cidrs is empty", a.cfg.DebugHTTPAddr)
+		return
+	}
+	ln, err := net.Listen("tcp", a.cfg.DebugHTTPAddr)
+	if err != nil {
+		log.Printf("[Debug] WARNING: debug endpoint disabled, listen %s: %v", a.cfg.DebugHTTPAddr, err)
+		return
+	}
+	ln = a.allowListener(ln, "[Debug]")
+	a.debugServer = &http.Server{Handler: a.debugMux(), ReadHeaderTimeout: 5 * time.Second}
+	log.Printf("[Debug] Serving /debug/pprof/, /debug/vars and /debug/goroutines on %s", ln.Addr())
+	go func() {
+		if err := a.debugServer.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Printf("[Debug] WARNING: debug endpoint stopped: %v", err)
+		}
+	}()
+}
+
+// stopDebugServer frees the port (before a relaunch).
+func (a *App) stopDebugServer() {
+	if a.debugServer != nil {
+		a.debugServer.Close()
+	}
+}
+
+// debugMux routes the debug endpoint and publishes the "dashboard"
+// expvar for a.
+func (a *App) debugMux() *http.ServeMux {
+	debugApp.Store(a)
+	debugVarOnce.Do(func() {
+		expvar.Publish("dashboard", expvar.Func(func() interface{} {
+			if a := debugApp.Load(); a != nil {
+				return a.debugCounters()
+			}
+			return nil
+		}))
+	})
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	mux.Handle("/debug/vars", expvar.Handler())
+	mux.HandleFunc("/debug/goroutines", handleGoroutineDump)
+	return mux
+}
+
+// debugCounters returns the "dashboard" expvar.
+func (a *App) debugCounters() map[string]uint64 {
+	t := camera.ReadTotals()
+	return map[string]uint64{
+		"frames_decoded":  t.FramesDecoded,
+		"frames_dropped":  t.FramesDropped,
+		"decode_errors":   t.DecodeErrors,
+		"camera_restarts": t.WorkerRestarts,
+		"restarts":        a.restarts.Load(),
+		"goroutines":      uint64(runtime.NumGoroutine()),
+	}
+}
+
+// handleGoroutineDump logs every goroutine's stack and returns it. POST
+// only, so a crawler or a browser prefetch can't fill the log.
+func handleGoroutineDump(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "POST to dump the goroutines", http.StatusMethodNotAllowed)
+		return
+	}
+	dump := goroutineDump()
+	log.Printf("[Debug] Goroutine dump (%d goroutines):\n%s", runtime.NumGoroutine(), dump)
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Write(dump)
+}
+
+// goroutineDump returns the stacks of all goroutines.
+func goroutineDump() []byte {
+	buf := make([]byte, 64<<10)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			return buf[:n]
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+}
@@ -0,0 +1,63 @@
+package ui
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDebugMux(t *testing.T) {
+	a := &App{}
+	a.restarts.Store(2)
+	srv := httptest.NewServer(a.debugMux())
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/debug/vars")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var vars struct {
+		Dashboard map[string]uint64 `json:"dashboard"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&vars)
+	resp.Body.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vars.Dashboard["restarts"] != 2 || vars.Dashboard["goroutines"] == 0 {
+		t.Errorf("dashboard = %v, want restarts 2 and a goroutine count", vars.Dashboard)
+	}
+	if _, ok := vars.Dashboard["frames_decoded"]; !ok {
+		t.Errorf("dashboard = %v, want frames_decoded", vars.Dashboard)
+	}
+
+	resp, err = http.Get(srv.URL + "/debug/goroutines")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("GET /debug/goroutines = %d, want 405", resp.StatusCode)
+	}
+	resp, err = http.Post(srv.URL+"/debug/goroutines", "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dump, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil || !strings.Contains(string(dump), "goroutine ") {
+		t.Errorf("POST /debug/goroutines returned %q, %v; want a stack dump", dump, err)
+	}
+
+	resp, err = http.Get(srv.URL + "/debug/pprof/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("GET /debug/pprof/ = %d, want 200", resp.StatusCode)
+	}
+}
@@ -0,0 +1,229 @@
+package ui
+
+import (
+	"camera-dashboard-go/internal/camera"
+	"fmt"
+	"image"
+	"log"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// =============================================================================
+// Fisheye dewarp (lens correction)
+// =============================================================================
+// Wide-angle rear cameras compress everything near the edge of the frame,
+// which makes distances hard to judge. [dewarp] in config.ini maps a
+// camera (same keys as [transform]) to radial distortion coefficients:
+//
+//   k1=-0.30, k2=0.08, zoom=1.1
+//
+// For every output pixel at normalised radius r (1 = frame corner) the
+// source pixel is read from radius r * (1 + k1*r^2 + k2*r^4) / zoom.
+// Negative k1 straightens barrel (fisheye) distortion; zoom > 1 crops
+// the black border that correction leaves at the edges.
+//
+// The mapping is precomputed once per camera and frame geometry into a
+// remap table (one source offset per output pixel, nearest neighbour),
+// so each frame costs one table lookup per byte. Dewarp runs before the
+// [transform] mirror/rotation, on the decoded frame.
+// =============================================================================
+
+// lensParams is a parsed [dewarp] entry.
+type lensParams struct {
+	k1, k2 float64
+	zoom   float64
+}
+
+// parseDewarp parses a [dewarp] value such as "k1=-0.3, k2=0.05".
+func parseDewarp(spec string) (lensParams, error) {
+	p := lensParams{zoom: 1}
+	for _, option := range strings.Split(spec, ",") {
+		option = strings.ToLower(strings.TrimSpace(option))
+		if option == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(option, "=")
+		if !ok {
+			return p, fmt.Errorf("option %q needs a value", option)
+		}
+		v, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			return p, fmt.Errorf("%s: invalid number %q", key, value)
+		}
+		switch strings.TrimSpace(key) {
+		case "k1":
+			p.k1 = v
+		case "k2":
+			p.k2 = v
+		case "zoom":
+			if v < 0.5 || v > 4 {
+				return p, fmt.Errorf("zoom must be between 0.5 and 4, got %g", v)
+			}
+			p.zoom = v
+		default:
+			return p, fmt.Errorf("unknown option %q", key)
+		}
+	}
+	return p, nil
+}
+
+// remapKey identifies a plane geometry a table was built for.
+type remapKey struct {
+	w, h, stride, bpp int
+}
+
+// remapTable holds, for each output pixel, the byte offset of its source
+// pixel in the input plane, or -1 when it falls outside the frame.
+type remapTable struct {
+	key remapKey
+	src []int32
+}
+
+// dewarper owns one camera's lens parameters and cached remap tables.
+// Tables are shared by the grid and fullscreen paths, so lookups lock.
+type dewarper struct {
+	params lensParams
+
+	mu     sync.Mutex
+	tables map[remapKey]*remapTable
+}
+
+func newDewarper(p lensParams) *dewarper {
+	return &dewarper{params: p, tables: make(map[remapKey]*remapTable)}
+}
+
+// table returns the remap table for a plane, building it on first use.
+func (d *dewarper) table(key remapKey) *remapTable {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if t, ok := d.tables[key]; ok {
+		return t
+	}
+	if len(d.tables) >= 8 {
+		// Resolution changed repeatedly; drop stale geometries
+		d.tables = make(map[remapKey]*remapTable)
+	}
+	t := buildRemapTable(d.params, key)
+	d.tables[key] = t
+	return t
+}
+
+// buildRemapTable computes the source offset of every output pixel.
+func buildRemapTable(p lensParams, key remapKey) *remapTable {
+	w, h := key.w, key.h
+	t := &remapTable{key: key, src: make([]int32, w*h)}
+	cx, cy := float64(w-1)/2, float64(h-1)/2
+	norm := math.Hypot(cx, cy)
+	if norm == 0 {
+		norm = 1
+	}
+	for y := 0; y < h; y++ {
+		ny := (float64(y) - cy) / norm
+		for x := 0; x < w; x++ {
+			nx := (float64(x) - cx) / norm
+			r2 := nx*nx + ny*ny
+			scale := (1 + p.k1*r2 + p.k2*r2*r2) / p.zoom
+			sx := int(math.Round(cx + nx*scale*norm))
+			sy := int(math.Round(cy + ny*scale*norm))
+			if sx < 0 || sx >= w || sy < 0 || sy >= h {
+				t.src[y*w+x] = -1
+				continue
+			}
+			t.src[y*w+x] = int32(sy*key.stride + sx*key.bpp)
+		}
+	}
+	return t
+}
+
+// remapPlane fills dst (w*bpp bytes per row, dstStride apart) from src
+// through t; pixels outside the source frame are set to fill.
+func remapPlane(t *remapTable, src, dst []byte, dstStride int, fill []byte) {
+	w, h, bpp := t.key.w, t.key.h, t.key.bpp
+	i := 0
+	for y := 0; y < h; y++ {
+		row := y * dstStride
+		for x := 0; x < w; x++ {
+			off := t.src[i]
+			i++
+			d := row + x*bpp
+			if off < 0 {
+				copy(dst[d:d+bpp], fill)
+				continue
+			}
+			if bpp == 1 {
+				dst[d] = src[off]
+			} else {
+				copy(dst[d:d+bpp], src[off:int(off)+bpp])
+			}
+		}
+	}
+}
+
+var (
+	fillLuma   = []byte{0}
+	fillChroma = []byte{128}
+	fillRGBA   = []byte{0, 0, 0, 255}
+)
+
+// apply returns src with lens correction, reusing dst when it has the
+// same type and geometry.
+func (d *dewarper) apply(src image.Image, dst image.Image) image.Image {
+	if s, ok := src.(*image.YCbCr); ok {
+		w, h := s.Rect.Dx(), s.Rect.Dy()
+		out, _ := dst.(*image.YCbCr)
+		out = reuseYCbCr(out, w, h, s.SubsampleRatio)
+		cw, ch := chromaSize(s)
+		remapPlane(d.table(remapKey{w, h, s.YStride, 1}), s.Y, out.Y, out.YStride, fillLuma)
+		ct := d.table(remapKey{cw, ch, s.CStride, 1})
+		remapPlane(ct, s.Cb, out.Cb, out.CStride, fillChroma)
+		remapPlane(ct, s.Cr, out.Cr, out.CStride, fillChroma)
+		return out
+	}
+
+	rgba, ok := src.(*image.RGBA)
+	if !ok || rgba.Rect.Min != (image.Point{}) {
+		rgba = toRGBA(src)
+	}
+	w, h := rgba.Rect.Dx(), rgba.Rect.Dy()
+	out, _ := dst.(*image.RGBA)
+	if out == nil || out.Rect != image.Rect(0, 0, w, h) {
+		out = image.NewRGBA(image.Rect(0, 0, w, h))
+	}
+	remapPlane(d.table(remapKey{w, h, rgba.Stride, 4}), rgba.Pix, out.Pix, out.Stride, fillRGBA)
+	return out
+}
+
+// updateSlotDewarp resolves [dewarp] entries for the discovered cameras
+// (called whenever a.cameras changes, and on config reload).
+func (a *App) updateSlotDewarp(cams []camera.Camera) {
+	a.cfgMu.RLock()
+	specs := a.cfg.CameraDewarp // Reloads replace the map, never modify it
+	a.cfgMu.RUnlock()
+	dewarpers := make([]*dewarper, len(cams))
+	for entry, spec := range specs {
+		p, err := parseDewarp(spec)
+		if err != nil {
+			log.Printf("[UI] WARNING: [dewarp] %s: %v", entry, err)
+			continue
+		}
+		for i, cam := range cams {
+			if camera.MatchesCamera(entry, cam) {
+				dewarpers[i] = newDewarper(p)
+				log.Printf("[UI] Camera %s: dewarp %s", cam.DeviceID, spec)
+			}
+		}
+	}
+	a.slotDewarpers.Store(&dewarpers)
+}
+
+// slotDewarper returns the dewarper for camIndex, or nil.
+func (a *App) slotDewarper(camIndex int) *dewarper {
+	dewarpers := a.slotDewarpers.Load()
+	if dewarpers == nil || camIndex < 0 || camIndex >= len(*dewarpers) {
+		return nil
+	}
+	return (*dewarpers)[camIndex]
+}
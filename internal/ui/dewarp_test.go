@@ -0,0 +1,70 @@
+package ui
+
+import (
+	"image"
+	"testing"
+)
+
+func TestParseDewarp(t *testing.T) {
+	got, err := parseDewarp("k1=-0.3, K2=0.05")
+	if err != nil || got != (lensParams{k1: -0.3, k2: 0.05, zoom: 1}) {
+		t.Errorf("parseDewarp = %+v, %v", got, err)
+	}
+	for _, bad := range []string{"k1", "k1=strong", "zoom=10", "k3=0.1"} {
+		if _, err := parseDewarp(bad); err == nil {
+			t.Errorf("parseDewarp(%q) should fail", bad)
+		}
+	}
+}
+
+func TestBuildRemapTable_IdentityWithoutDistortion(t *testing.T) {
+	key := remapKey{w: 8, h: 6, stride: 10, bpp: 1}
+	table := buildRemapTable(lensParams{zoom: 1}, key)
+	for y := 0; y < key.h; y++ {
+		for x := 0; x < key.w; x++ {
+			if got := table.src[y*key.w+x]; got != int32(y*key.stride+x) {
+				t.Fatalf("pixel (%d,%d) reads offset %d, want %d", x, y, got, y*key.stride+x)
+			}
+		}
+	}
+}
+
+func TestBuildRemapTable_BarrelCorrection(t *testing.T) {
+	key := remapKey{w: 101, h: 101, stride: 101, bpp: 1}
+
+	// Negative k1 pulls edge pixels from nearer the centre
+	table := buildRemapTable(lensParams{k1: -0.4, zoom: 1}, key)
+	if got := table.src[0]; got < 0 || got%101 == 0 || got/101 == 0 {
+		t.Errorf("corner reads offset %d, want a pixel inside the frame edge", got)
+	}
+	if got := table.src[50*101+50]; got != 50*101+50 {
+		t.Errorf("centre reads offset %d, want itself", got)
+	}
+
+	// Positive k1 pushes corners outside the source frame
+	table = buildRemapTable(lensParams{k1: 0.5, zoom: 1}, key)
+	if got := table.src[0]; got != -1 {
+		t.Errorf("corner reads offset %d, want -1 (outside)", got)
+	}
+}
+
+func TestDewarper_YCbCrKeepsGeometry(t *testing.T) {
+	src := image.NewYCbCr(image.Rect(0, 0, 16, 8), image.YCbCrSubsampleRatio420)
+	for i := range src.Y {
+		src.Y[i] = 200
+	}
+	d := newDewarper(lensParams{k1: 0.5, zoom: 1})
+	out, ok := d.apply(src, nil).(*image.YCbCr)
+	if !ok || out.Rect != src.Rect || out.SubsampleRatio != src.SubsampleRatio {
+		t.Fatalf("dewarped frame = %T %v", out, out.Rect)
+	}
+	if out.Y[0] != 0 || out.Cb[0] != 128 {
+		t.Errorf("corner outside the source = Y %d Cb %d, want black (0, 128)", out.Y[0], out.Cb[0])
+	}
+	if out.Y[4*out.YStride+8] != 200 {
+		t.Errorf("centre luma = %d, want 200", out.Y[4*out.YStride+8])
+	}
+	if again := d.apply(src, out); again != image.Image(out) {
+		t.Error("same-sized frame should reuse the output buffer")
+	}
+}
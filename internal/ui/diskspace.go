@@ -0,0 +1,151 @@
+package ui
+
+import (
+	"camera-dashboard-go/internal/storage"
+	"fmt"
+	"log"
+	"path/filepath"
+	"time"
+)
+
+// =============================================================================
+// Disk space manager ([storage])
+// =============================================================================
+// Every check_interval_sec the snapshot, clip, trip report and log
+// directories are checked by internal/storage: each is trimmed to its
+// *_quota_mb (oldest recordings first), and when a filesystem has less
+// than low_space_mb free a warning toast is shown, the /healthz and MQTT
+// health payloads report disk_low, and with free_space = true the oldest
+// recordings are deleted until there is enough room again. Recordings
+// saved for a G-sensor incident are marked .keep and never deleted; the
+// log being written is kept too (only rotated backups go).
+// =============================================================================
+
+// startDiskManager starts the periodic disk checks, unless there is no
+// threshold and no quota.
+func (a *App) startDiskManager() {
+	dirs := a.managedDirs()
+	if len(dirs) == 0 || (a.cfg.LowSpaceMB == 0 && !hasQuota(dirs)) {
+		return
+	}
+	m := storage.NewManager(dirs, uint64(a.cfg.LowSpaceMB)<<20, a.cfg.FreeSpace)
+	for _, d := range dirs {
+		log.Printf("[Storage] Managing %s (%s), quota %s", d.Name, d.Path, formatQuota(d.Quota))
+	}
+	log.Printf("[Storage] Low-space warning below %d MB (free up: %v)", a.cfg.LowSpaceMB, a.cfg.FreeSpace)
+
+	go func() {
+		interval := secondsToDuration(a.cfg.StorageIntervalSec)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		failing := false
+		for {
+			a.applyDiskStatus(m.Check(), &failing)
+			select {
+			case <-a.ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// managedDirs lists the directories the dashboard writes, each once.
+func (a *App) managedDirs() []storage.Dir {
+	mb := func(n int) int64 { return int64(n) << 20 }
+	candidates := []storage.Dir{
+		{Name: "snapshots", Path: a.cfg.SnapshotDir, Quota: mb(a.cfg.SnapshotsQuotaMB)},
+		{Name: "clips", Path: a.cfg.ClipsDir, Quota: mb(a.cfg.ClipsQuotaMB)},
+		{Name: "trips", Path: a.cfg.TripReportDir, Quota: mb(a.cfg.TripsQuotaMB)},
+	}
+	if a.cfg.LogFile != "" {
+		candidates = append(candidates, storage.Dir{
+			Name:  "logs",
+			Path:  filepath.Dir(a.cfg.LogFile),
+			Quota: mb(a.cfg.LogsQuotaMB),
+			Log:   filepath.Base(a.cfg.LogFile),
+		})
+	}
+	var dirs []storage.Dir
+	seen := make(map[string]bool)
+	for _, d := range candidates {
+		if d.Path == "" {
+			continue
+		}
+		clean := filepath.Clean(d.Path)
+		if seen[clean] {
+			log.Printf("[Storage] WARNING: %s shares %s with another directory, not managed separately", d.Name, d.Path)
+			continue
+		}
+		seen[clean] = true
+		dirs = append(dirs, d)
+	}
+	return dirs
+}
+
+func hasQuota(dirs []storage.Dir) bool {
+	for _, d := range dirs {
+		if d.Quota > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func formatQuota(bytes int64) string {
+	if bytes == 0 {
+		return "unlimited"
+	}
+	return formatBytes(uint64(bytes))
+}
+
+// applyDiskStatus logs a check's deletions and errors and raises or
+// clears the low-space warning.
+func (a *App) applyDiskStatus(st storage.Status, failing *bool) {
+	if len(st.Deleted) > 0 {
+		log.Printf("[Storage] Deleted %d old files, oldest first (first: %s)", len(st.Deleted), st.Deleted[0])
+	}
+	if len(st.Errors) > 0 && !*failing {
+		log.Printf("[Storage] WARNING: %v", st.Errors[0])
+	}
+	*failing = len(st.Errors) > 0
+
+	if len(st.Dirs) > 0 {
+		a.diskFree.Store(int64(st.FreeBytes))
+	}
+	if a.diskLow.Swap(st.Low) == st.Low {
+		return
+	}
+	if st.Low {
+		log.Printf("[Storage] WARNING: low disk space, %s free (threshold %d MB)", formatBytes(st.FreeBytes), a.cfg.LowSpaceMB)
+		a.toast("disk", lowSpaceMessage(st.FreeBytes, a.cfg.FreeSpace), toastWarning)
+	} else {
+		log.Printf("[Storage] Disk space OK again, %s free", formatBytes(st.FreeBytes))
+	}
+}
+
+// lowSpaceMessage is the low-space toast.
+func lowSpaceMessage(free uint64, freeUp bool) string {
+	if freeUp {
+		return fmt.Sprintf("Low disk space (%s free): deleting oldest recordings", formatBytes(free))
+	}
+	return fmt.Sprintf("Low disk space: %s free", formatBytes(free))
+}
+
+// diskMetrics adds the disk fields to a health payload once a check has
+// run.
+func (a *App) diskMetrics(payload map[string]interface{}) {
+	if free := a.diskFree.Load(); free >= 0 {
+		payload["disk_free_mb"] = free >> 20
+		payload["disk_low"] = a.diskLow.Load()
+	}
+}
+
+// protectRecordings marks saved recordings so the disk manager keeps them.
+func protectRecordings(paths []string) {
+	for _, p := range paths {
+		if err := storage.Protect(p); err != nil {
+			log.Printf("[Storage] WARNING: protecting %s: %v", p, err)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package ui
+
+import (
+	"camera-dashboard-go/internal/config"
+	"camera-dashboard-go/internal/storage"
+	"reflect"
+	"testing"
+)
+
+func TestManagedDirs(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.SnapshotDir = "/data/rec"
+	cfg.ClipsDir = "/data/rec/"
+	cfg.ClipsQuotaMB = 100
+	cfg.TripReportDir = ""
+	cfg.LogFile = "/var/log/dash/camera_dashboard.log"
+	cfg.LogsQuotaMB = 20
+
+	got := (&App{cfg: cfg}).managedDirs()
+	want := []storage.Dir{
+		{Name: "snapshots", Path: "/data/rec"},
+		{Name: "logs", Path: "/var/log/dash", Quota: 20 << 20, Log: "camera_dashboard.log"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("managedDirs() = %+v, want %+v", got, want)
+	}
+}
+
+func TestDiskMetrics(t *testing.T) {
+	a := &App{}
+	a.diskFree.Store(-1)
+	payload := map[string]interface{}{}
+	a.diskMetrics(payload)
+	if len(payload) != 0 {
+		t.Errorf("before a check: %v", payload)
+	}
+	a.diskFree.Store(300 << 20)
+	a.diskLow.Store(true)
+	a.diskMetrics(payload)
+	if payload["disk_free_mb"] != int64(300) || payload["disk_low"] != true {
+		t.Errorf("payload = %v", payload)
+	}
+}
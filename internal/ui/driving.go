@@ -0,0 +1,154 @@
+package ui
+
+import (
+	"log"
+	"time"
+)
+
+// =============================================================================
+// Driving mode (do not disturb)
+// =============================================================================
+// Hides everything but the camera feeds while driving: the settings tile
+// (and the grid reflows to cameras only), the settings and camera
+// controls panels, the aim assist overlay, swap mode and info toasts.
+// Disconnected overlays on camera tiles and warning toasts stay, since
+// they are the critical alerts. Tap-to-fullscreen keeps working;
+// long-press on a camera leaves driving mode instead of starting a swap.
+//
+// Toggled from the settings panel, [display] driving_mode (startup
+// default and reloadable), or the MQTT cmd/drivingmode command. With
+// [gps] on, [display] driving_speed also turns it on when the car
+// reaches that speed, and off again once it has been slower for
+// drivingStopDelay. Only those crossings change the mode, so a manual
+// toggle holds until the next one.
+// =============================================================================
+
+// drivingStopDelay is how long the car must stay below driving_speed
+// before driving mode turns off again (traffic lights don't count).
+const drivingStopDelay = 30 * time.Second
+
+// setDrivingMode enables or disables driving mode.
+func (a *App) setDrivingMode(enabled bool) {
+	was := a.drivingMode.Swap(enabled)
+	if was != enabled {
+		if enabled {
+			log.Println("[UI] Driving mode enabled")
+		} else {
+			log.Println("[UI] Driving mode disabled")
+		}
+	}
+	if a.grid == nil {
+		return // Headless, or before setupUI (which applies the mode)
+	}
+	a.applyDrivingModeUI(enabled)
+}
+
+// drivingSpeedSwitch follows the GPS speed against driving_speed.
+type drivingSpeedSwitch struct {
+	moving bool      // At or above the threshold (or below it for less than drivingStopDelay)
+	slow   time.Time // When the speed dropped below the threshold; zero while at speed
+}
+
+// update takes a GPS reading (ok = recent fix) and reports whether
+// driving mode should change, and to what. threshold is in m/s; 0 = off.
+func (s *drivingSpeedSwitch) update(speedMS, threshold float64, ok bool, now time.Time) (enable, change bool) {
+	if threshold <= 0 {
+		*s = drivingSpeedSwitch{}
+		return false, false
+	}
+	if ok && speedMS >= threshold {
+		s.slow = time.Time{}
+		if s.moving {
+			return false, false
+		}
+		s.moving = true
+		return true, true
+	}
+	if !s.moving {
+		return false, false
+	}
+	if s.slow.IsZero() {
+		s.slow = now
+	}
+	if now.Sub(s.slow) < drivingStopDelay {
+		return false, false
+	}
+	*s = drivingSpeedSwitch{}
+	return false, true
+}
+
+// watchDrivingSpeed switches driving mode by GPS speed, once a second
+// ([display] driving_speed, read each time since it reloads). It only
+// turns off a driving mode it turned on itself.
+func (a *App) watchDrivingSpeed() {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	var sw drivingSpeedSwitch
+	auto := false
+	for {
+		select {
+		case <-a.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		a.cfgMu.RLock()
+		threshold := speedToMS(float64(a.cfg.DrivingSpeed), a.cfg.GPSUnits)
+		a.cfgMu.RUnlock()
+		fix, ok := a.gps.Latest()
+		enable, change := sw.update(fix.SpeedMS, threshold, ok, time.Now())
+		switch {
+		case !change:
+		case enable && !a.drivingMode.Load():
+			log.Printf("[UI] Driving at %s", formatSpeed(fix.SpeedMS, a.cfg.GPSUnits))
+			auto = true
+			a.setDrivingMode(true)
+		case !enable && auto:
+			auto = false
+			if a.drivingMode.Load() {
+				log.Println("[UI] Stopped driving")
+				a.setDrivingMode(false)
+			}
+		}
+	}
+}
+
+// applyDrivingModeUI shows or hides the non-essential UI.
+func (a *App) applyDrivingModeUI(enabled bool) {
+	if enabled {
+		a.cancelSwapMode()
+		if a.settingsPanel != nil {
+			a.settingsPanel.close()
+		}
+		if a.lockScreen != nil {
+			a.lockScreen.close()
+		}
+		if a.controlsPanel != nil {
+			a.controlsPanel.close()
+			a.fullscreenAdjust.Hide()
+		}
+		a.setAimAssist(false)
+		a.showSettingsEntry(false)
+	} else {
+		a.showSettingsEntry(true)
+		if a.fullscreenAdjust != nil {
+			a.fullscreenAdjust.Show()
+		}
+	}
+	if a.grid != nil {
+		a.grid.Refresh()
+	}
+}
+
+// cancelSwapMode leaves swap mode and clears highlights.
+func (a *App) cancelSwapMode() {
+	if !a.swapMode {
+		return
+	}
+	for _, w := range a.gridWidgets {
+		if w != nil {
+			w.SetHighlight(false)
+		}
+	}
+	a.swapMode = false
+	a.swapSourceSlot = -1
+}
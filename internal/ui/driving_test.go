@@ -0,0 +1,99 @@
+package ui
+
+import (
+	"camera-dashboard-go/internal/config"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"testing"
+	"time"
+)
+
+func TestSetDrivingMode_ReflowsGrid(t *testing.T) {
+	a := &App{cfg: config.DefaultConfig()}
+	a.settingsWidget = NewTappableSettings(nil, nil, nil, nil, nil)
+	cams := []fyne.CanvasObject{canvas.NewRectangle(nil), canvas.NewRectangle(nil), canvas.NewRectangle(nil)}
+	a.grid = container.New(&fillGridLayout{}, append([]fyne.CanvasObject{a.settingsWidget}, cams...)...)
+	a.grid.Resize(fyne.NewSize(900, 600))
+
+	// Settings tile + 3 cameras: 2x2
+	if size := cams[0].Size(); size != fyne.NewSize(450, 300) {
+		t.Fatalf("camera size %v before driving mode, want 450x300", size)
+	}
+
+	a.setDrivingMode(true)
+	if !a.drivingMode.Load() || a.settingsWidget.Visible() {
+		t.Fatal("driving mode on, want the settings tile hidden")
+	}
+	// 3 cameras: 1x3, the hidden tile leaves no gap
+	for i, cam := range cams {
+		if pos, size := cam.Position(), cam.Size(); pos != fyne.NewPos(float32(i*300), 0) || size != fyne.NewSize(300, 600) {
+			t.Errorf("camera %d at %v size %v in driving mode, want (%d, 0) size 300x600", i, pos, size, i*300)
+		}
+	}
+
+	a.setDrivingMode(false)
+	if a.drivingMode.Load() || !a.settingsWidget.Visible() {
+		t.Fatal("driving mode off, want the settings tile back")
+	}
+	if pos := cams[0].Position(); pos != fyne.NewPos(450, 0) {
+		t.Errorf("camera 0 at %v after driving mode, want (450, 0)", pos)
+	}
+}
+
+func TestMQTTDrivingMode(t *testing.T) {
+	a := &App{cfg: config.DefaultConfig()}
+	for _, tc := range []struct {
+		arg  string
+		want bool
+	}{
+		{"on", true}, {"off", false}, {"1", true}, {"0", false}, {"TRUE", true}, {"false", false},
+		{"toggle", true}, {"", false}, {"maybe", false},
+	} {
+		a.handleMQTTCommand("dashboard/cmd/drivingmode", []byte(tc.arg))
+		if got := a.drivingMode.Load(); got != tc.want {
+			t.Errorf("drivingmode %q: driving mode %v, want %v", tc.arg, got, tc.want)
+		}
+	}
+}
+
+func TestDrivingSpeedSwitch(t *testing.T) {
+	const threshold = 5 // m/s
+	start := time.Unix(0, 0)
+	var s drivingSpeedSwitch
+	steps := []struct {
+		at             time.Duration
+		speed          float64
+		ok             bool
+		enable, change bool
+	}{
+		{0, 2, true, false, false},
+		{1 * time.Second, 6, true, true, true}, // Reached the speed
+		{2 * time.Second, 8, true, false, false},
+		{3 * time.Second, 0, true, false, false},  // Traffic light
+		{20 * time.Second, 7, true, false, false}, // Moving again before the delay
+		{21 * time.Second, 0, false, false, false},
+		{50 * time.Second, 0, false, false, false},
+		{51 * time.Second, 0, false, false, true}, // No fix for drivingStopDelay
+		{52 * time.Second, 1, true, false, false},
+	}
+	for _, st := range steps {
+		enable, change := s.update(st.speed, threshold, st.ok, start.Add(st.at))
+		if enable != st.enable || change != st.change {
+			t.Errorf("at %v, %.0f m/s: enable %v change %v, want %v %v", st.at, st.speed, enable, change, st.enable, st.change)
+		}
+	}
+
+	if _, change := s.update(50, 0, true, start); change {
+		t.Error("threshold 0 changed driving mode")
+	}
+}
+
+func TestSpeedToMS(t *testing.T) {
+	if got := speedToMS(36, "kmh"); got != 10 {
+		t.Errorf("speedToMS(36 km/h) = %v, want 10", got)
+	}
+	if got := formatSpeed(speedToMS(20, "mph"), "mph"); got != "20 mph" {
+		t.Errorf("20 mph round trip = %q", got)
+	}
+}
@@ -0,0 +1,223 @@
+package ui
+
+import (
+	"camera-dashboard-go/internal/helpers"
+	"camera-dashboard-go/internal/storage"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// =============================================================================
+// SD-card write endurance ([endurance])
+// =============================================================================
+// For always-on installs booting from an SD card:
+//   - snapshots and clips are written to ram_dir (a tmpfs) and moved to
+//     their real directories in one sequential pass every
+//     flush_interval_sec, as soon as flush_mb is waiting, and at exit.
+//     A power cut loses what is still in RAM.
+//   - log lines are written out in batches every log_flush_sec (see
+//     config.RotatingFileWriter); ERROR lines go out at once
+//   - recording is refused (snapshots and clips fail with a warning
+//     toast) while the recording filesystem has less than
+//     stop_recording_mb free, or wear_device reports
+//     stop_recording_wear_pct of its rated life used
+// =============================================================================
+
+const enduranceCheckInterval = 5 * time.Second
+
+// stagedDir is a RAM directory and where its recordings belong.
+type stagedDir struct {
+	ram, final string
+}
+
+// stagedDirs lists the RAM directories in use (none unless endurance
+// mode is on).
+func (a *App) stagedDirs() []stagedDir {
+	if !a.cfg.EnduranceEnabled {
+		return nil
+	}
+	var dirs []stagedDir
+	for kind, final := range map[string]string{"snapshots": a.cfg.SnapshotDir, "clips": a.cfg.ClipsDir} {
+		if final != "" {
+			dirs = append(dirs, stagedDir{ram: filepath.Join(a.cfg.EnduranceRAMDir, kind), final: final})
+		}
+	}
+	return dirs
+}
+
+// recordingDir is where a recording of kind ("snapshots" or "clips")
+// headed for final is written: final, or its RAM directory in endurance
+// mode.
+func (a *App) recordingDir(kind, final string) string {
+	if !a.cfg.EnduranceEnabled || final == "" {
+		return final
+	}
+	return filepath.Join(a.cfg.EnduranceRAMDir, kind)
+}
+
+// recordingBlocked returns why recordings to dir are refused, or nil.
+func (a *App) recordingBlocked(dir string) error {
+	if !a.cfg.EnduranceEnabled {
+		return nil
+	}
+	if a.cfg.StopRecordingMB > 0 {
+		free, _, err := helpers.DiskFree(existingDir(dir))
+		if err == nil && free < uint64(a.cfg.StopRecordingMB)<<20 {
+			return fmt.Errorf("recording stopped: %s free (stop_recording_mb = %d)", formatBytes(free), a.cfg.StopRecordingMB)
+		}
+	}
+	if a.cfg.WearDevice != "" && a.cfg.StopRecordingWearPct > 0 {
+		pct, err := storage.Wear(a.cfg.WearDevice)
+		if err == nil && pct >= a.cfg.StopRecordingWearPct {
+			return fmt.Errorf("recording stopped: %s wear estimate %d%% (stop_recording_wear_pct = %d)",
+				a.cfg.WearDevice, pct, a.cfg.StopRecordingWearPct)
+		}
+	}
+	return nil
+}
+
+// startEndurance starts flushing staged recordings and watching the
+// recording thresholds.
+func (a *App) startEndurance() {
+	if !a.cfg.EnduranceEnabled {
+		return
+	}
+	log.Printf("[Endurance] Recordings staged in %s, flushed every %.0fs or at %d MB; log flushed every %.0fs",
+		a.cfg.EnduranceRAMDir, a.cfg.EnduranceFlushSec, a.cfg.EnduranceFlushMB, a.cfg.EnduranceLogFlushSec)
+	if a.cfg.WearDevice != "" {
+		if pct, err := storage.Wear(a.cfg.WearDevice); err != nil {
+			log.Printf("[Endurance] WARNING: no wear estimate for %s: %v", a.cfg.WearDevice, err)
+		} else {
+			log.Printf("[Endurance] %s wear estimate: %d%% of rated life", a.cfg.WearDevice, pct)
+		}
+	}
+
+	go func() {
+		ticker := time.NewTicker(enduranceCheckInterval)
+		defer ticker.Stop()
+		interval := secondsToDuration(a.cfg.EnduranceFlushSec)
+		limit := int64(a.cfg.EnduranceFlushMB) << 20
+		var lastFlush time.Time // Leftovers from a crashed run go out on the first tick
+		var lastBlocked string
+		for {
+			select {
+			case <-a.ctx.Done():
+				return
+			case now := <-ticker.C:
+				blocked := ""
+				if err := a.recordingBlocked(a.cfg.SnapshotDir); err != nil {
+					blocked = err.Error()
+				}
+				if blocked != lastBlocked {
+					if blocked != "" {
+						log.Printf("[Endurance] WARNING: %s", blocked)
+						a.toast("recording", "Recording stopped: low space or worn storage", toastWarning)
+					} else {
+						log.Printf("[Endurance] Recording allowed again")
+					}
+					lastBlocked = blocked
+				}
+
+				if stagedBytes(a.stagedDirs()) < limit && now.Sub(lastFlush) < interval {
+					continue
+				}
+				if a.pendingWrites.Load() > 0 {
+					continue // Don't move a recording still being written
+				}
+				a.flushStaged()
+				lastFlush = now
+			}
+		}
+	}()
+}
+
+// stagedBytes is the size of everything waiting in RAM.
+func stagedBytes(dirs []stagedDir) int64 {
+	var n int64
+	for _, d := range dirs {
+		entries, _ := os.ReadDir(d.ram)
+		for _, e := range entries {
+			if info, err := e.Info(); err == nil && info.Mode().IsRegular() {
+				n += info.Size()
+			}
+		}
+	}
+	return n
+}
+
+// flushStaged moves every staged recording to its real directory,
+// oldest first.
+func (a *App) flushStaged() {
+	a.stageMu.Lock()
+	defer a.stageMu.Unlock()
+	ioClass, _ := helpers.ParseIOClass(a.cfg.WriteIOClass)
+	files, bytes := 0, int64(0)
+	for _, d := range a.stagedDirs() {
+		entries, err := os.ReadDir(d.ram) // Sorted by name, which starts with the time
+		if err != nil || len(entries) == 0 {
+			continue
+		}
+		if err := os.MkdirAll(d.final, 0o755); err != nil {
+			log.Printf("[Endurance] ERROR: create %s: %v", d.final, err)
+			continue
+		}
+		for _, e := range entries {
+			if !e.Type().IsRegular() {
+				continue
+			}
+			src := filepath.Join(d.ram, e.Name())
+			var n int64
+			err := helpers.RunWithIOPriority(ioClass, a.cfg.WriteIOLevel, func() error {
+				var err error
+				n, err = moveFile(src, filepath.Join(d.final, e.Name()))
+				return err
+			})
+			if err != nil {
+				log.Printf("[Endurance] ERROR: flushing %s: %v", src, err)
+				continue
+			}
+			files++
+			bytes += n
+		}
+	}
+	if files > 0 {
+		log.Printf("[Endurance] Flushed %d files (%s) from RAM to disk", files, formatBytes(uint64(bytes)))
+	}
+}
+
+// moveFile copies src to dst in one pass, syncs it, keeps src's mode
+// and removes src. It returns the bytes written.
+func moveFile(src, dst string) (int64, error) {
+	in, err := os.Open(src)
+	if err != nil {
+		return 0, err
+	}
+	defer in.Close()
+	info, err := in.Stat()
+	if err != nil {
+		return 0, err
+	}
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return 0, err
+	}
+	n, err := io.Copy(out, in)
+	if err == nil {
+		err = out.Sync()
+	}
+	if cerr := out.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(dst)
+		return 0, err
+	}
+	if err := os.Chmod(dst, info.Mode().Perm()); err != nil {
+		return n, err
+	}
+	return n, os.Remove(src)
+}
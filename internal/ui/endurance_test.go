@@ -0,0 +1,149 @@
+package ui
+
+import (
+	"camera-dashboard-go/internal/config"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRecordingDir(t *testing.T) {
+	cfg := config.DefaultConfig()
+	a := &App{cfg: cfg}
+	if got := a.recordingDir("clips", "/data/clips"); got != "/data/clips" {
+		t.Errorf("without endurance mode: %q", got)
+	}
+	cfg.EnduranceEnabled, cfg.EnduranceRAMDir = true, "/dev/shm/dash"
+	if got := a.recordingDir("clips", "/data/clips"); got != "/dev/shm/dash/clips" {
+		t.Errorf("in endurance mode: %q", got)
+	}
+	if got := a.recordingDir("clips", ""); got != "" {
+		t.Errorf("disabled recordings should stay disabled: %q", got)
+	}
+}
+
+func TestFlushStaged(t *testing.T) {
+	root := t.TempDir()
+	cfg := config.DefaultConfig()
+	cfg.EnduranceEnabled = true
+	cfg.EnduranceRAMDir = filepath.Join(root, "ram")
+	cfg.SnapshotDir = filepath.Join(root, "snapshots")
+	cfg.ClipsDir = filepath.Join(root, "clips")
+	a := &App{cfg: cfg}
+
+	ram := a.recordingDir("clips", cfg.ClipsDir)
+	if err := os.MkdirAll(ram, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(ram, "a.mjpeg"), []byte("frames"), 0o444); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(ram, "a.json"), []byte("{}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if n := stagedBytes(a.stagedDirs()); n != 8 {
+		t.Errorf("stagedBytes() = %d, want 8", n)
+	}
+
+	a.flushStaged()
+	if n := stagedBytes(a.stagedDirs()); n != 0 {
+		t.Errorf("%d bytes left in RAM after a flush", n)
+	}
+	data, err := os.ReadFile(filepath.Join(cfg.ClipsDir, "a.mjpeg"))
+	if err != nil || string(data) != "frames" {
+		t.Fatalf("flushed clip = %q, %v", data, err)
+	}
+	if info, _ := os.Stat(filepath.Join(cfg.ClipsDir, "a.mjpeg")); info.Mode().Perm() != 0o444 {
+		t.Errorf("mode = %v, want the read-only mode kept", info.Mode().Perm())
+	}
+}
+
+func TestFlushStaged_Disabled(t *testing.T) {
+	root := t.TempDir()
+	cfg := config.DefaultConfig()
+	cfg.EnduranceRAMDir = filepath.Join(root, "ram")
+	cfg.ClipsDir = filepath.Join(root, "clips")
+	ram := filepath.Join(cfg.EnduranceRAMDir, "clips")
+	if err := os.MkdirAll(ram, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(ram, "a.mjpeg"), []byte("frames"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	(&App{cfg: cfg}).flushStaged()
+	if _, err := os.Stat(filepath.Join(ram, "a.mjpeg")); err != nil {
+		t.Errorf("files moved without endurance mode: %v", err)
+	}
+}
+
+func TestMoveFile(t *testing.T) {
+	dir := t.TempDir()
+	src, dst := filepath.Join(dir, "src.jpg"), filepath.Join(dir, "dst.jpg")
+	if err := os.WriteFile(src, []byte("jpeg"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	n, err := moveFile(src, dst)
+	if err != nil || n != 4 {
+		t.Fatalf("moveFile() = %d, %v", n, err)
+	}
+	if _, err := os.Stat(src); !os.IsNotExist(err) {
+		t.Error("source left behind")
+	}
+	if data, _ := os.ReadFile(dst); string(data) != "jpeg" {
+		t.Errorf("destination = %q", data)
+	}
+	if info, _ := os.Stat(dst); info.Mode().Perm() != 0o600 {
+		t.Errorf("mode = %v, want 0600", info.Mode().Perm())
+	}
+}
+
+func TestMoveFile_FailureKeepsSource(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "src.jpg")
+	if err := os.WriteFile(src, []byte("jpeg"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := moveFile(src, filepath.Join(dir, "missing", "dst.jpg")); err == nil {
+		t.Fatal("expected an error for a missing destination directory")
+	}
+	if data, _ := os.ReadFile(src); string(data) != "jpeg" {
+		t.Errorf("source = %q after a failed move", data)
+	}
+	if _, err := moveFile(filepath.Join(dir, "gone.jpg"), filepath.Join(dir, "dst.jpg")); err == nil {
+		t.Error("expected an error for a missing source")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "dst.jpg")); !os.IsNotExist(err) {
+		t.Error("destination created for a missing source")
+	}
+}
+
+func TestRecordingBlocked(t *testing.T) {
+	dir := t.TempDir()
+	cfg := config.DefaultConfig()
+	cfg.StopRecordingMB = 1 << 30 // 1 PB: more than any test machine has free
+	a := &App{cfg: cfg}
+	if err := a.recordingBlocked(dir); err != nil {
+		t.Errorf("without endurance mode: %v", err)
+	}
+
+	cfg.EnduranceEnabled = true
+	if err := a.recordingBlocked(filepath.Join(dir, "not", "created")); err == nil {
+		t.Error("expected recording to stop below stop_recording_mb")
+	}
+	cfg.StopRecordingMB = 1
+	if err := a.recordingBlocked(dir); err != nil {
+		t.Errorf("with space to spare: %v", err)
+	}
+	cfg.StopRecordingMB = 0
+	if err := a.recordingBlocked(dir); err != nil {
+		t.Errorf("stop_recording_mb = 0: %v", err)
+	}
+
+	// A device without a wear estimate doesn't stop recording
+	cfg.WearDevice, cfg.StopRecordingWearPct = "no-such-device", 10
+	if err := a.recordingBlocked(dir); err != nil {
+		t.Errorf("unknown wear device: %v", err)
+	}
+}
@@ -0,0 +1,124 @@
+package ui
+
+import (
+	"fmt"
+	"image"
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// Framebuffer ioctls (linux/fb.h)
+const (
+	fbioGetVScreenInfo = 0x4600
+	fbioGetFScreenInfo = 0x4602
+)
+
+// fbVarScreenInfo is struct fb_var_screeninfo.
+type fbVarScreenInfo struct {
+	XRes, YRes, XResVirtual, YResVirtual uint32
+	XOffset, YOffset                     uint32
+	BitsPerPixel, Grayscale              uint32
+	Red, Green, Blue, Transp             fbBitfieldC
+	NonStd, Activate, Height, Width      uint32
+	AccelFlags, PixClock                 uint32
+	LeftMargin, RightMargin              uint32
+	UpperMargin, LowerMargin             uint32
+	HSyncLen, VSyncLen, Sync, VMode      uint32
+	Rotate, Colorspace                   uint32
+	Reserved                             [4]uint32
+}
+
+// fbBitfieldC is struct fb_bitfield.
+type fbBitfieldC struct {
+	Offset, Length, MSBRight uint32
+}
+
+// fbFixScreenInfo is struct fb_fix_screeninfo.
+type fbFixScreenInfo struct {
+	ID                            [16]byte
+	SMemStart                     uintptr
+	SMemLen                       uint32
+	Type, TypeAux, Visual         uint32
+	XPanStep, YPanStep, YWrapStep uint16
+	LineLength                    uint32
+	MMIOStart                     uintptr
+	MMIOLen                       uint32
+	Accel                         uint32
+	Capabilities                  uint16
+	Reserved                      [2]uint16
+}
+
+// framebuffer draws to a Linux fbdev device such as /dev/fb0.
+type framebuffer struct {
+	path          string
+	f             *os.File
+	width, height int
+	format        fbFormat
+	offset        int64 // Byte offset of the visible area
+	buf           []byte
+}
+
+// OpenFramebuffer opens a framebuffer device for drawing. The console
+// on it should be blanked (e.g. vt.global_cursor_default=0 and no getty
+// on that VT) or it will draw over the cameras.
+func OpenFramebuffer(path string) (RenderBackend, error) {
+	f, err := os.OpenFile(path, os.O_RDWR, 0)
+	if err != nil {
+		return nil, err
+	}
+	var v fbVarScreenInfo
+	var fix fbFixScreenInfo
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, f.Fd(), fbioGetVScreenInfo, uintptr(unsafe.Pointer(&v))); errno != 0 {
+		f.Close()
+		return nil, fmt.Errorf("read %s screen info: %w", path, errno)
+	}
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, f.Fd(), fbioGetFScreenInfo, uintptr(unsafe.Pointer(&fix))); errno != 0 {
+		f.Close()
+		return nil, fmt.Errorf("read %s fixed info: %w", path, errno)
+	}
+	switch v.BitsPerPixel {
+	case 16, 24, 32:
+	default:
+		f.Close()
+		return nil, fmt.Errorf("%s: %d bits per pixel not supported (want 16, 24 or 32)", path, v.BitsPerPixel)
+	}
+
+	bitfield := func(b fbBitfieldC) fbBitfield { return fbBitfield{Offset: b.Offset, Length: b.Length} }
+	fb := &framebuffer{
+		path:   path,
+		f:      f,
+		width:  int(v.XRes),
+		height: int(v.YRes),
+		format: fbFormat{
+			BytesPerPixel: int(v.BitsPerPixel / 8),
+			Stride:        int(fix.LineLength),
+			Red:           bitfield(v.Red),
+			Green:         bitfield(v.Green),
+			Blue:          bitfield(v.Blue),
+			Transp:        bitfield(v.Transp),
+		},
+		offset: int64(v.YOffset)*int64(fix.LineLength) + int64(v.XOffset*v.BitsPerPixel/8),
+	}
+	fb.buf = make([]byte, fb.format.Stride*fb.height)
+	return fb, nil
+}
+
+func (fb *framebuffer) Name() string {
+	return fb.path
+}
+
+func (fb *framebuffer) Size() (int, int) {
+	return fb.width, fb.height
+}
+
+// Present writes frame to the visible screen area.
+func (fb *framebuffer) Present(frame *image.RGBA) error {
+	fb.format.pack(fb.buf, frame)
+	_, err := fb.f.WriteAt(fb.buf, fb.offset)
+	return err
+}
+
+func (fb *framebuffer) Close() error {
+	return fb.f.Close()
+}
@@ -0,0 +1,20 @@
+package ui
+
+import (
+	"testing"
+	"unsafe"
+)
+
+func TestFramebufferStructSizes(t *testing.T) {
+	// Sizes from linux/fb.h; the fixed info holds two unsigned longs
+	if n := unsafe.Sizeof(fbVarScreenInfo{}); n != 160 {
+		t.Errorf("fb_var_screeninfo is %d bytes, want 160", n)
+	}
+	want := uintptr(68)
+	if unsafe.Sizeof(uintptr(0)) == 8 {
+		want = 80
+	}
+	if n := unsafe.Sizeof(fbFixScreenInfo{}); n != want {
+		t.Errorf("fb_fix_screeninfo is %d bytes, want %d", n, want)
+	}
+}
@@ -0,0 +1,12 @@
+//go:build !linux
+
+package ui
+
+import (
+	"errors"
+)
+
+// OpenFramebuffer is only supported on Linux.
+func OpenFramebuffer(path string) (RenderBackend, error) {
+	return nil, errors.New("framebuffer output is only supported on Linux")
+}
@@ -0,0 +1,174 @@
+package ui
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"log"
+	"time"
+)
+
+// =============================================================================
+// Freeze indicator
+// =============================================================================
+// A camera that stops delivering frames leaves its last picture on the
+// tile, and until the stale detector marks it disconnected
+// ([performance] stale_frame_timeout_sec) that picture looks live. For a
+// rear view that is a safety hazard, so once a tile's picture is older
+// than [display] freeze_indicator_ms it turns a dimmed grey and a FROZEN
+// badge counts the seconds since the last frame. The next frame restores
+// the colour picture. The fullscreen view does the same for its camera.
+//
+// The threshold is never shorter than freezeMinFrames frame intervals at
+// the current capture rate, and cameras decoded at the off-screen rate
+// get the longer hidden timeout (see offscreen.go), so slow cameras
+// aren't flagged between normal frames.
+// =============================================================================
+
+const (
+	freezeMinFrames = 3  // Frame intervals before a picture can count as frozen
+	freezeLumaScale = 60 // Percent of the luminance kept in the frozen picture
+)
+
+var (
+	freezeColor   = color.RGBA{255, 190, 0, 255}
+	freezeBadgeBg = color.NRGBA{0, 0, 0, 180}
+)
+
+// freezeTimeout returns how long camIndex's picture may stay unchanged
+// before it is shown frozen (0 = indicator off).
+func (a *App) freezeTimeout(camIndex int) time.Duration {
+	a.cfgMu.RLock()
+	ms := a.cfg.FreezeIndicatorMS
+	fps := a.cfg.CaptureFPS
+	dynamic := a.cfg.DynamicFPSEnabled
+	a.cfgMu.RUnlock()
+	if ms <= 0 {
+		return 0
+	}
+	timeout := time.Duration(ms) * time.Millisecond
+	if pc := a.perfController.Load(); pc != nil && dynamic {
+		fps = pc.GetCurrentFPS()
+	}
+	if fps > 0 {
+		if frames := freezeMinFrames * time.Second / time.Duration(fps); frames > timeout {
+			timeout = frames
+		}
+	}
+	return a.staleTimeout(camIndex, timeout)
+}
+
+// frozenFor returns how long a picture last updated at lastFrame has been
+// frozen at now, or 0 while it is within timeout (or never arrived).
+func frozenFor(lastFrame, now time.Time, timeout time.Duration) time.Duration {
+	if timeout <= 0 || lastFrame.IsZero() {
+		return 0
+	}
+	if age := now.Sub(lastFrame); age > timeout {
+		return age
+	}
+	return 0
+}
+
+// freezeLabel is the badge text for a picture frozen for age.
+func freezeLabel(age time.Duration) string {
+	return fmt.Sprintf("FROZEN %.1fs", age.Seconds())
+}
+
+// slotFrozenFor returns how long camIndex's tile has been frozen at now
+// (0 = live). Disconnected slots and slots without a signal have their
+// own overlays and never count as frozen.
+func (a *App) slotFrozenFor(camIndex int, now time.Time) time.Duration {
+	a.frameLock.RLock()
+	if camIndex < 0 || camIndex >= len(a.lastFrameTime) || !a.cameraStatus[camIndex] {
+		a.frameLock.RUnlock()
+		return 0
+	}
+	lastFrame := a.lastFrameTime[camIndex]
+	a.frameLock.RUnlock()
+	if a.cameraSignalLost(camIndex) {
+		return 0
+	}
+	return frozenFor(lastFrame, now, a.freezeTimeout(camIndex))
+}
+
+// updateSlotFreeze shows or clears the freeze indicator of camIndex's
+// tile (refresh loop, for slots without a new frame). The grey picture
+// is made once, when the tile freezes; the badge counts up every call.
+func (a *App) updateSlotFreeze(camIndex int, now time.Time) {
+	if camIndex >= len(a.slotFrozen) {
+		return
+	}
+	age := a.slotFrozenFor(camIndex, now)
+	if age == 0 {
+		a.clearSlotFreeze(camIndex, false)
+		return
+	}
+	if !a.slotFrozen[camIndex] {
+		a.slotFrozen[camIndex] = true
+		log.Printf("[UI] Camera %s: picture frozen (no frame for %.1fs)", a.cameraTag(camIndex), age.Seconds())
+		if img := a.cameraImages[camIndex]; img != nil && img.Image != nil {
+			a.freezeBufs[camIndex] = applyFreezeReuse(img.Image, a.freezeBufs[camIndex])
+			img.Image = a.freezeBufs[camIndex]
+			img.Refresh()
+		}
+	}
+	if w := a.cameraWidgets[camIndex]; w != nil {
+		w.SetFrozen(age)
+	}
+}
+
+// clearSlotFreeze removes the freeze indicator of camIndex's tile: live
+// when a new frame arrived (the caller shows it), otherwise the tile went
+// disconnected or lost its signal and shows that overlay instead.
+func (a *App) clearSlotFreeze(camIndex int, live bool) {
+	if camIndex >= len(a.slotFrozen) || !a.slotFrozen[camIndex] {
+		return
+	}
+	a.slotFrozen[camIndex] = false
+	if live {
+		log.Printf("[UI] Camera %s: picture live again", a.cameraTag(camIndex))
+	}
+	if w := a.cameraWidgets[camIndex]; w != nil {
+		w.SetFrozen(0)
+	}
+}
+
+// applyFreezeReuse renders src as the dimmed grey frozen picture, reusing
+// dst if it is large enough.
+func applyFreezeReuse(src image.Image, dst *image.RGBA) *image.RGBA {
+	bounds := src.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	neededLen := w * h * 4
+	if dst != nil && cap(dst.Pix) >= neededLen {
+		dst.Pix = dst.Pix[:neededLen]
+		dst.Stride = w * 4
+		dst.Rect = image.Rect(0, 0, w, h)
+	} else {
+		dst = image.NewRGBA(image.Rect(0, 0, w, h))
+	}
+
+	for y := 0; y < h; y++ {
+		dstOff := y * dst.Stride
+		for x := 0; x < w; x++ {
+			var luma uint32
+			switch s := src.(type) {
+			case *image.RGBA:
+				off := s.PixOffset(x+bounds.Min.X, y+bounds.Min.Y)
+				luma = (299*uint32(s.Pix[off]) + 587*uint32(s.Pix[off+1]) + 114*uint32(s.Pix[off+2])) / 1000
+			case *image.YCbCr:
+				luma = uint32(s.Y[s.YOffset(x+bounds.Min.X, y+bounds.Min.Y)])
+			default:
+				r, g, b, _ := src.At(x+bounds.Min.X, y+bounds.Min.Y).RGBA()
+				luma = (299*(r>>8) + 587*(g>>8) + 114*(b>>8)) / 1000
+			}
+			v := uint8(luma * freezeLumaScale / 100)
+			dst.Pix[dstOff+0] = v
+			dst.Pix[dstOff+1] = v
+			dst.Pix[dstOff+2] = v
+			dst.Pix[dstOff+3] = 255
+			dstOff += 4
+		}
+	}
+	return dst
+}
@@ -0,0 +1,77 @@
+package ui
+
+import (
+	"camera-dashboard-go/internal/config"
+	"image"
+	"image/color"
+	"testing"
+	"time"
+)
+
+func TestFrozenFor(t *testing.T) {
+	last := time.Unix(1000, 0)
+	for _, tc := range []struct {
+		last    time.Time
+		now     time.Duration
+		timeout time.Duration
+		want    time.Duration
+	}{
+		{last, 400 * time.Millisecond, 500 * time.Millisecond, 0},
+		{last, 2300 * time.Millisecond, 500 * time.Millisecond, 2300 * time.Millisecond},
+		{last, 2300 * time.Millisecond, 0, 0},       // Indicator off
+		{time.Time{}, 0, 500 * time.Millisecond, 0}, // No frame yet
+	} {
+		if got := frozenFor(tc.last, last.Add(tc.now), tc.timeout); got != tc.want {
+			t.Errorf("frozenFor(+%v, timeout %v) = %v, want %v", tc.now, tc.timeout, got, tc.want)
+		}
+	}
+	if got := freezeLabel(2345 * time.Millisecond); got != "FROZEN 2.3s" {
+		t.Errorf("freezeLabel = %q", got)
+	}
+}
+
+func TestFreezeTimeout(t *testing.T) {
+	cfg := config.DefaultConfig()
+	a := newApp(cfg)
+	if got := a.freezeTimeout(0); got != 500*time.Millisecond {
+		t.Errorf("default = %v, want 500ms", got)
+	}
+
+	cfg.CaptureFPS = 5
+	if got := a.freezeTimeout(0); got != 600*time.Millisecond {
+		t.Errorf("5 FPS = %v, want 600ms (3 frames)", got)
+	}
+
+	// Off-screen cameras decode at hidden_camera_fps
+	a.isFullscreen.Store(true)
+	a.fullscreenSlot = 2 // Camera 1
+	a.updateCaptureVisibility()
+	if got := a.freezeTimeout(0); got != 1500*time.Millisecond {
+		t.Errorf("hidden = %v, want 1.5s", got)
+	}
+
+	cfg.FreezeIndicatorMS = 0
+	if got := a.freezeTimeout(1); got != 0 {
+		t.Errorf("off = %v, want 0", got)
+	}
+}
+
+func TestApplyFreezeReuse(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 2, 1))
+	src.Set(0, 0, color.RGBA{255, 255, 255, 255})
+	src.Set(1, 0, color.RGBA{255, 0, 0, 255})
+
+	dst := applyFreezeReuse(src, nil)
+	if got := dst.RGBAAt(0, 0); got != (color.RGBA{153, 153, 153, 255}) {
+		t.Errorf("white = %v, want 60%% grey", got)
+	}
+	if got := dst.RGBAAt(1, 0); got.R != got.G || got.G != got.B || got.R != 45 {
+		t.Errorf("red = %v, want grey 45", got)
+	}
+
+	ycc := image.NewYCbCr(image.Rect(0, 0, 2, 1), image.YCbCrSubsampleRatio420)
+	ycc.Y[0] = 200
+	if again := applyFreezeReuse(ycc, dst); again != dst || again.RGBAAt(0, 0).R != 120 || again.Bounds().Dx() != 2 {
+		t.Errorf("YCbCr into reused buffer = %v, pixel %v", again.Bounds(), again.RGBAAt(0, 0))
+	}
+}
@@ -0,0 +1,277 @@
+package ui
+
+import (
+	"fyne.io/fyne/v2"
+	"log"
+	"math"
+	"time"
+)
+
+// =============================================================================
+// Gestures
+// =============================================================================
+// Camera tiles in the grid map four gestures to actions through
+// [gestures] (config.GestureActions): tap, double tap, long press and
+// two-finger tap (a right click with a mouse). The defaults keep the
+// classic mapping: tap for fullscreen, long press (or right click) for
+// swap, no double tap. With a double_tap action a single tap waits
+// doubleTapWindow for a second one, so single taps are only delayed when
+// double taps are in use. Swap mode, once entered, still finishes with a
+// tap on the other tile, and a long press always leaves driving mode.
+// The settings tile and the fullscreen picture aren't remapped.
+//
+// In fullscreen, a horizontal swipe moves to the next (swipe left) or
+// previous (swipe right) camera in grid order without going back to the
+// grid, and a swipe down returns to the grid. A swipe must travel
+// swipeMinDistance along one axis, at least twice as far as along the
+// other, at swipeMinVelocity or faster; slower or shorter drags are
+// ignored. Once the finger has moved swipeSlop the touch no longer
+// counts as a tap or long press, so a shaky tap still taps.
+//
+// Only tiles with a swipe handler (the fullscreen picture) track drags;
+// grid tiles ignore them.
+// =============================================================================
+
+const (
+	doubleTapWindow  = 300 * time.Millisecond
+	swipeSlop        = 20  // px moved before a touch stops being a tap
+	swipeMinDistance = 80  // px along the swipe axis
+	swipeMinVelocity = 300 // px/s along the swipe axis
+)
+
+// swipeDirection is the direction the finger moved.
+type swipeDirection int
+
+const (
+	swipeNone swipeDirection = iota
+	swipeLeft
+	swipeRight
+	swipeUp
+	swipeDown
+)
+
+func (d swipeDirection) String() string {
+	switch d {
+	case swipeLeft:
+		return "left"
+	case swipeRight:
+		return "right"
+	case swipeUp:
+		return "up"
+	case swipeDown:
+		return "down"
+	}
+	return "none"
+}
+
+// classifySwipe decides which swipe, if any, a drag of (dx, dy) pixels
+// over elapsed was.
+func classifySwipe(dx, dy float32, elapsed time.Duration) swipeDirection {
+	ax, ay := math.Abs(float64(dx)), math.Abs(float64(dy))
+	distance, other := ax, ay
+	if ay > ax {
+		distance, other = ay, ax
+	}
+	if distance < swipeMinDistance || distance < 2*other || elapsed <= 0 {
+		return swipeNone
+	}
+	if distance/elapsed.Seconds() < swipeMinVelocity {
+		return swipeNone
+	}
+	switch {
+	case ax > ay && dx < 0:
+		return swipeLeft
+	case ax > ay:
+		return swipeRight
+	case dy < 0:
+		return swipeUp
+	}
+	return swipeDown
+}
+
+// Gesture names, as the [gestures] keys.
+const (
+	gestureTap          = "tap"
+	gestureDoubleTap    = "double_tap"
+	gestureLongPress    = "long_press"
+	gestureTwoFingerTap = "two_finger_tap"
+)
+
+// SetOnDoubleTap makes single taps wait for a possible second tap.
+func (t *TappableImage) SetOnDoubleTap(onDoubleTap func()) {
+	t.mu.Lock()
+	t.onDoubleTap = onDoubleTap
+	t.mu.Unlock()
+}
+
+// SetOnSecondaryTap sets the two-finger tap / right click action
+// (default: the long-press action).
+func (t *TappableImage) SetOnSecondaryTap(onSecondaryTap func()) {
+	t.mu.Lock()
+	t.onSecondaryTap = onSecondaryTap
+	t.mu.Unlock()
+}
+
+// fireTap runs the tap action, or the double-tap action for a second tap
+// within doubleTapWindow.
+func (t *TappableImage) fireTap() {
+	t.mu.Lock()
+	if t.onDoubleTap == nil {
+		t.mu.Unlock()
+		if t.onTap != nil {
+			t.onTap()
+		}
+		return
+	}
+	if t.tapTimer != nil && t.tapTimer.Stop() {
+		t.tapTimer = nil
+		onDoubleTap := t.onDoubleTap
+		t.mu.Unlock()
+		log.Println("[UI] Double tap")
+		onDoubleTap()
+		return
+	}
+	t.tapTimer = time.AfterFunc(doubleTapWindow, func() {
+		t.mu.Lock()
+		t.tapTimer = nil
+		t.mu.Unlock()
+		if t.onTap != nil {
+			t.onTap()
+		}
+	})
+	t.mu.Unlock()
+}
+
+// bindCameraGestures wires a camera tile's gestures to the [gestures]
+// actions.
+func (a *App) bindCameraGestures(w *TappableImage) {
+	w.onTap = func() { a.onCameraGesture(w, gestureTap, a.cfg.GestureTap) }
+	w.onLongTap = func() { a.onCameraGesture(w, gestureLongPress, a.cfg.GestureLongPress) }
+	w.SetOnSecondaryTap(func() { a.onCameraGesture(w, gestureTwoFingerTap, a.cfg.GestureTwoFingerTap) })
+	if a.cfg.GestureDoubleTap != "none" {
+		w.SetOnDoubleTap(func() { a.onCameraGesture(w, gestureDoubleTap, a.cfg.GestureDoubleTap) })
+	}
+}
+
+// onCameraGesture runs action for a gesture on a camera tile.
+func (a *App) onCameraGesture(w *TappableImage, gesture, action string) {
+	gridPos := a.findWidgetPosition(w)
+	if gridPos < 0 {
+		log.Printf("[UI] %s: widget not found in grid", gesture)
+		return
+	}
+	switch {
+	case a.swapMode && gesture != gestureLongPress:
+		a.handleSwapTap(gridPos)
+		return
+	case gesture == gestureLongPress && a.drivingMode.Load():
+		a.setDrivingMode(false) // Swap is hidden while driving; long-press is the way out
+		return
+	}
+	log.Printf("[UI] %s on grid position %d: %s", gesture, gridPos, action)
+
+	camIndex := a.gridSlots[gridPos]
+	switch action {
+	case "fullscreen":
+		a.showFullscreen(gridPos)
+	case "swap":
+		if !a.drivingMode.Load() {
+			a.enterSwapMode(gridPos)
+		}
+	case "snapshot":
+		go func() {
+			if _, err := a.saveSnapshot(camIndex); err != nil {
+				log.Printf("[Snapshot] Camera %d skipped: %v", camIndex, err)
+			}
+		}()
+	case "info":
+		a.toggleTileInfo(w)
+	case "mute_overlay":
+		a.toggleOverlayMute()
+	}
+}
+
+// SetOnSwipe makes the image track drags and report swipes.
+func (t *TappableImage) SetOnSwipe(onSwipe func(swipeDirection)) {
+	t.mu.Lock()
+	t.onSwipe = onSwipe
+	t.mu.Unlock()
+}
+
+// Dragged accumulates the finger's movement since MouseDown.
+func (t *TappableImage) Dragged(ev *fyne.DragEvent) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.onSwipe == nil {
+		return
+	}
+	t.dragDX += ev.Dragged.DX
+	t.dragDY += ev.Dragged.DY
+	if !t.swiping && math.Hypot(float64(t.dragDX), float64(t.dragDY)) >= swipeSlop {
+		t.swiping = true
+		t.tapHandled = true // Moved too far for a tap (MouseUp comes first)
+		if t.longPressTimer != nil {
+			t.longPressTimer.Stop()
+			t.longPressTimer = nil
+		}
+	}
+}
+
+// DragEnd reports the swipe, if the drag was one.
+func (t *TappableImage) DragEnd() {
+	t.mu.Lock()
+	dir := swipeNone
+	if t.swiping {
+		dir = classifySwipe(t.dragDX, t.dragDY, time.Since(t.pressStart))
+	}
+	t.dragDX, t.dragDY, t.swiping = 0, 0, false
+	onSwipe := t.onSwipe
+	t.mu.Unlock()
+
+	if dir != swipeNone && onSwipe != nil {
+		log.Printf("[UI] Swipe %s", dir)
+		onSwipe(dir)
+	}
+}
+
+// onFullscreenSwipe switches cameras or leaves fullscreen.
+func (a *App) onFullscreenSwipe(dir swipeDirection) {
+	if !a.isFullscreen.Load() {
+		return
+	}
+	switch dir {
+	case swipeDown:
+		a.hideFullscreen()
+	case swipeLeft, swipeRight:
+		step := 1
+		if dir == swipeRight {
+			step = -1
+		}
+		a.frameLock.RLock()
+		camCount := len(a.cameras)
+		a.frameLock.RUnlock()
+		next := nextFullscreenSlot(a.gridSlots, camCount, a.fullscreenSlot, step)
+		if next < 0 || next == a.fullscreenSlot {
+			return
+		}
+		a.hideFullscreen()
+		a.showFullscreen(next)
+	}
+}
+
+// nextFullscreenSlot returns the grid position step places from current
+// (wrapping around) that shows a camera, or -1 if none does.
+func nextFullscreenSlot(gridSlots []int, camCount, current, step int) int {
+	n := len(gridSlots)
+	if n == 0 {
+		return -1
+	}
+	pos := current
+	for i := 0; i < n; i++ {
+		pos = ((pos+step)%n + n) % n
+		if cam := gridSlots[pos]; cam >= 0 && cam < camCount {
+			return pos
+		}
+	}
+	return -1
+}
@@ -0,0 +1,109 @@
+package ui
+
+import (
+	"fyne.io/fyne/v2"
+	"testing"
+	"time"
+)
+
+func TestClassifySwipe(t *testing.T) {
+	tests := []struct {
+		dx, dy  float32
+		elapsed time.Duration
+		want    swipeDirection
+	}{
+		{-200, 10, 200 * time.Millisecond, swipeLeft},
+		{200, -30, 200 * time.Millisecond, swipeRight},
+		{20, 150, 150 * time.Millisecond, swipeDown},
+		{0, -150, 150 * time.Millisecond, swipeUp},
+		{-50, 0, 50 * time.Millisecond, swipeNone},     // Too short
+		{-200, 0, 2 * time.Second, swipeNone},          // Too slow
+		{-150, 100, 100 * time.Millisecond, swipeNone}, // Diagonal
+		{-200, 0, 0, swipeNone},
+	}
+	for _, tt := range tests {
+		if got := classifySwipe(tt.dx, tt.dy, tt.elapsed); got != tt.want {
+			t.Errorf("classifySwipe(%v, %v, %v) = %v, want %v", tt.dx, tt.dy, tt.elapsed, got, tt.want)
+		}
+	}
+}
+
+func TestNextFullscreenSlot(t *testing.T) {
+	// Settings tile at 0, cameras 0-2 at 1-3, an empty slot (camera 3) at 4
+	slots := []int{-1, 0, 1, 2, 3}
+	if got := nextFullscreenSlot(slots, 3, 1, 1); got != 2 {
+		t.Errorf("next from 1 = %d, want 2", got)
+	}
+	if got := nextFullscreenSlot(slots, 3, 3, 1); got != 1 {
+		t.Errorf("next from 3 = %d, want to wrap to 1", got)
+	}
+	if got := nextFullscreenSlot(slots, 3, 1, -1); got != 3 {
+		t.Errorf("previous from 1 = %d, want to wrap to 3", got)
+	}
+	if got := nextFullscreenSlot(slots, 1, 1, 1); got != 1 {
+		t.Errorf("one camera = %d, want itself", got)
+	}
+	if got := nextFullscreenSlot(slots, 0, 1, 1); got != -1 {
+		t.Errorf("no cameras = %d, want -1", got)
+	}
+}
+
+func TestTappableImage_SwipeIsNotATap(t *testing.T) {
+	taps := 0
+	var swiped swipeDirection
+	img := &TappableImage{onTap: func() { taps++ }}
+	img.SetOnSwipe(func(d swipeDirection) { swiped = d })
+
+	img.MouseDown(nil)
+	for i := 0; i < 4; i++ {
+		img.Dragged(&fyne.DragEvent{Dragged: fyne.NewDelta(-50, 2)})
+	}
+	img.MouseUp(nil)
+	img.DragEnd()
+	if taps != 0 || swiped != swipeLeft {
+		t.Errorf("swipe: %d taps, swiped %v; want 0 taps, left", taps, swiped)
+	}
+
+	// A few pixels of jitter is still a tap
+	swiped = swipeNone
+	img.MouseDown(nil)
+	img.Dragged(&fyne.DragEvent{Dragged: fyne.NewDelta(3, 4)})
+	img.MouseUp(nil)
+	img.DragEnd()
+	if taps != 1 || swiped != swipeNone {
+		t.Errorf("jitter: %d taps, swiped %v; want a tap", taps, swiped)
+	}
+}
+
+func TestTappableImage_DoubleTap(t *testing.T) {
+	taps, doubles := make(chan struct{}, 4), make(chan struct{}, 4)
+	img := &TappableImage{onTap: func() { taps <- struct{}{} }}
+
+	// Without a double-tap action a tap fires at once
+	img.fireTap()
+	if len(taps) != 1 {
+		t.Fatal("tap should fire at once without a double-tap action")
+	}
+	<-taps
+
+	img.SetOnDoubleTap(func() { doubles <- struct{}{} })
+	img.fireTap()
+	img.fireTap()
+	select {
+	case <-doubles:
+	case <-time.After(time.Second):
+		t.Fatal("two quick taps should be a double tap")
+	}
+	select {
+	case <-taps:
+		t.Error("a double tap should not also tap")
+	case <-time.After(2 * doubleTapWindow):
+	}
+
+	img.fireTap()
+	select {
+	case <-taps:
+	case <-time.After(time.Second):
+		t.Fatal("a single tap should fire after the double-tap window")
+	}
+}
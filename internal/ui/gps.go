@@ -0,0 +1,103 @@
+package ui
+
+import (
+	"camera-dashboard-go/internal/sensors"
+	"fmt"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/layout"
+	"image/color"
+	"log"
+	"time"
+)
+
+// =============================================================================
+// GPS speed / position overlay
+// =============================================================================
+// With [gps] source set, fixes come from gpsd or a serial NMEA receiver
+// (see internal/sensors/gps.go). The overlay in the bottom-left corner
+// (over both the grid and fullscreen) shows speed in the configured
+// units and the position; it hides while there is no recent fix.
+// Incidents carry the position of the moment of impact.
+// =============================================================================
+
+// newGPSOverlay creates the (hidden) speed/position box.
+func (a *App) newGPSOverlay() *fyne.Container {
+	a.gpsText = canvas.NewText("", color.White)
+	a.gpsText.TextSize = 18
+	a.gpsText.TextStyle = fyne.TextStyle{Bold: true, Monospace: true}
+	bg := canvas.NewRectangle(color.RGBA{0, 0, 0, 160})
+	box := container.NewStack(bg, container.NewPadded(a.gpsText))
+	overlay := container.NewVBox(layout.NewSpacer(), container.NewHBox(box))
+	overlay.Hide()
+	return overlay
+}
+
+// startGPS starts reading fixes if [gps] source is set.
+func (a *App) startGPS() {
+	if a.cfg.GPSSource == "" {
+		return
+	}
+	log.Printf("[GPS] Source %s, speed in %s", a.cfg.GPSSource, a.cfg.GPSUnits)
+	a.gps = sensors.NewGPS(a.cfg.GPSSource)
+	go a.gps.Run(a.ctx.Done())
+	go a.watchDrivingSpeed()
+	if a.gpsOverlay != nil {
+		go a.updateGPSOverlay()
+	}
+}
+
+// updateGPSOverlay refreshes the overlay once a second.
+func (a *App) updateGPSOverlay() {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-a.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		fix, ok := a.gps.Latest()
+		if !ok || a.overlaysMuted.Load() {
+			if a.gpsOverlay.Visible() {
+				a.gpsOverlay.Hide()
+			}
+			continue
+		}
+		a.gpsText.Text = formatFix(fix, a.cfg.GPSUnits)
+		a.gpsText.Refresh()
+		if !a.gpsOverlay.Visible() {
+			a.gpsOverlay.Show()
+		}
+	}
+}
+
+// currentFix returns the latest GPS fix, if GPS is on and has one.
+func (a *App) currentFix() (sensors.Fix, bool) {
+	if a.gps == nil {
+		return sensors.Fix{}, false
+	}
+	return a.gps.Latest()
+}
+
+// formatSpeed renders a speed in m/s as "42 km/h" or "26 mph".
+func formatSpeed(ms float64, units string) string {
+	if units == "mph" {
+		return fmt.Sprintf("%.0f mph", ms*2.236936)
+	}
+	return fmt.Sprintf("%.0f km/h", ms*3.6)
+}
+
+// speedToMS converts a speed in units ("kmh" or "mph") to m/s.
+func speedToMS(speed float64, units string) float64 {
+	if units == "mph" {
+		return speed / 2.236936
+	}
+	return speed / 3.6
+}
+
+// formatFix renders the overlay text: speed and position.
+func formatFix(fix sensors.Fix, units string) string {
+	return fmt.Sprintf("%s  %.5f, %.5f", formatSpeed(fix.SpeedMS, units), fix.Lat, fix.Lon)
+}
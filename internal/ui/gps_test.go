@@ -0,0 +1,16 @@
+package ui
+
+import (
+	"camera-dashboard-go/internal/sensors"
+	"testing"
+)
+
+func TestFormatFix(t *testing.T) {
+	fix := sensors.Fix{Lat: 52.370216, Lon: -4.895168, SpeedMS: 13.9}
+	if got := formatFix(fix, "kmh"); got != "50 km/h  52.37022, -4.89517" {
+		t.Errorf("formatFix(kmh) = %q", got)
+	}
+	if got := formatSpeed(13.9, "mph"); got != "31 mph" {
+		t.Errorf("formatSpeed(mph) = %q", got)
+	}
+}
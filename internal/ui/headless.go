@@ -0,0 +1,56 @@
+package ui
+
+import (
+	"camera-dashboard-go/internal/config"
+	"log"
+)
+
+// =============================================================================
+// Headless mode
+// =============================================================================
+// Runs the capture pipeline and its supervision (stale-frame restarts,
+// hotplug, health logging, MQTT, watchdog, snapshots via MQTT) without
+// creating the Fyne app, so the binary works on boxes with no display.
+//
+// The camera refresh loop still runs: it moves frames out of the
+// capture buffers and timestamps them for stale detection and
+// snapshots, it just has no widgets to draw into. Filters (night mode,
+// brightness) are only applied for display and are skipped.
+//
+// The framebuffer backend (see render.go) is headless mode plus a render
+// loop that draws the camera grid without Fyne.
+// =============================================================================
+
+// NewHeadlessApp creates the application without a window. Start blocks
+// until Cleanup is called (e.g. from the signal handler).
+func NewHeadlessApp(cfg *config.Config) *App {
+	a := newApp(cfg)
+	a.headless = true
+	return a
+}
+
+// startHeadless is Start for headless mode.
+func (a *App) startHeadless() {
+	log.Printf("[Headless] Starting without display (%d camera slots)", a.effectiveSlots())
+	a.startCapture()
+	a.startCameraRefresh()
+	go a.startTripRecorder()
+	a.startHealthServer()
+	a.startDebugServer()
+	a.startGSensor()
+	a.startGPS()
+	a.startWatchdog()
+	a.startSystemdKeepAlive()
+	a.startBattery()
+	a.startAlerts()
+	a.startDiskManager()
+	a.startEndurance()
+	a.startUpload()
+	a.startTimeSync()
+	if a.render != nil {
+		a.refreshLoops.Go(a.startRenderLoop)
+		a.startAutoNight() // Night mode only shows on a display
+	}
+	<-a.doneCh
+	log.Println("[Headless] Stopped")
+}
@@ -0,0 +1,148 @@
+package ui
+
+import (
+	"camera-dashboard-go/internal/helpers"
+	"camera-dashboard-go/internal/systemd"
+	"encoding/json"
+	"errors"
+	"log"
+	"net"
+	"net/http"
+	"time"
+)
+
+// =============================================================================
+// Health endpoint
+// =============================================================================
+// Optional HTTP endpoint for container health checks and orchestrators
+// ([health] http_addr, e.g. ":8080"; empty = off):
+//
+//   GET /healthz  200 while the refresh loop is running, 503 once it has
+//                 stalled for refreshStallTimeout. The JSON body carries
+//                 the same camera counts as the [Health] log line.
+//
+// Camera outages alone don't fail the check: restarting the container
+// doesn't bring back an unplugged camera, and the stale-frame recovery
+// already handles wedged captures.
+//
+// Started by a socket unit (camera-dashboard.socket), the endpoint
+// serves the socket systemd passes in instead, even if http_addr is empty.
+//
+// [health] allowed_cidrs limits the clients of this and the debug
+// endpoint to those networks (plus loopback); others are disconnected
+// before they send a request.
+// =============================================================================
+
+// refreshStallTimeout is how old the refresh loop's heartbeat may get
+// before /healthz reports unhealthy.
+const refreshStallTimeout = 10 * time.Second
+
+// startHealthServer serves /healthz if [health] http_addr is set.
+func (a *App) startHealthServer() {
+	ln := a.activatedListener()
+	if ln == nil {
+		if a.cfg.HealthHTTPAddr == "" {
+			return
+		}
+		var err error
+		ln, err = net.Listen("tcp", a.cfg.HealthHTTPAddr)
+		if err != nil {
+			log.Printf("[Health] WARNING: health endpoint disabled, listen %s: %v", a.cfg.HealthHTTPAddr, err)
+			return
+		}
+	}
+	ln = a.allowListener(ln, "[Health]")
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", a.handleHealthz)
+	a.healthServer = &http.Server{Handler: mux, ReadHeaderTimeout: 5 * time.Second}
+	log.Printf("[Health] Serving /healthz on %s", ln.Addr())
+	go func() {
+		if err := a.healthServer.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Printf("[Health] WARNING: health endpoint stopped: %v", err)
+		}
+	}()
+}
+
+// activatedListener returns the socket passed in by systemd socket
+// activation, or nil.
+func (a *App) activatedListener() net.Listener {
+	listeners, err := systemd.Listeners()
+	if err != nil {
+		log.Printf("[Health] WARNING: socket activation: %v", err)
+	}
+	if len(listeners) == 0 {
+		return nil
+	}
+	for _, extra := range listeners[1:] {
+		extra.Close()
+	}
+	return listeners[0]
+}
+
+// allowedListener accepts only clients in nets (see helpers.AllowedIP).
+type allowedListener struct {
+	net.Listener
+	nets []*net.IPNet
+	tag  string // Log prefix of the endpoint
+}
+
+// allowListener restricts ln to [health] allowed_cidrs. An invalid list
+// (reported by Config.Validate) allows loopback only.
+func (a *App) allowListener(ln net.Listener, tag string) net.Listener {
+	if len(a.cfg.HealthAllowedCIDRs) == 0 {
+		return ln
+	}
+	nets, _ := helpers.ParseCIDRs(a.cfg.HealthAllowedCIDRs)
+	return &allowedListener{Listener: ln, nets: nets, tag: tag}
+}
+
+// Accept returns the next allowed connection, closing the others.
+// Unix sockets (socket activation) are local and always allowed.
+func (l *allowedListener) Accept() (net.Conn, error) {
+	for {
+		c, err := l.Listener.Accept()
+		if err != nil {
+			return nil, err
+		}
+		addr, ok := c.RemoteAddr().(*net.TCPAddr)
+		if !ok || helpers.AllowedIP(addr.IP, l.nets) {
+			return c, nil
+		}
+		log.Printf("%s Refused %s: not in [health] allowed_cidrs", l.tag, addr.IP)
+		c.Close()
+	}
+}
+
+// stopHealthServer frees the port (before a restart relaunches us).
+func (a *App) stopHealthServer() {
+	if a.healthServer != nil {
+		a.healthServer.Close()
+	}
+}
+
+func (a *App) handleHealthz(w http.ResponseWriter, r *http.Request) {
+	now := time.Now()
+	online, stale, disconnected := a.countCameraHealth(a.clk.Now(), false)
+
+	status := "ok"
+	code := http.StatusOK
+	last := a.uiHeartbeat.Last()
+	if !last.IsZero() && now.Sub(last) > refreshStallTimeout {
+		status = "refresh loop stalled"
+		code = http.StatusServiceUnavailable
+	}
+
+	payload := map[string]interface{}{
+		"status":       status,
+		"online":       online,
+		"stale":        stale,
+		"disconnected": disconnected,
+		"total_slots":  a.cfg.CameraSlotCount,
+		"headless":     a.headless,
+		"timestamp":    now.Unix(),
+	}
+	a.diskMetrics(payload)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(payload)
+}
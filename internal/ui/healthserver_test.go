@@ -0,0 +1,63 @@
+package ui
+
+import (
+	"camera-dashboard-go/internal/config"
+	"net"
+	"testing"
+)
+
+// remoteConn is a connection from addr.
+type remoteConn struct {
+	net.Conn
+	addr   net.Addr
+	closed bool
+}
+
+func (c *remoteConn) RemoteAddr() net.Addr { return c.addr }
+func (c *remoteConn) Close() error         { c.closed = true; return nil }
+
+// queuedListener hands out conns in order.
+type queuedListener struct {
+	net.Listener
+	conns []net.Conn
+}
+
+func (l *queuedListener) Accept() (net.Conn, error) {
+	if len(l.conns) == 0 {
+		return nil, net.ErrClosed
+	}
+	c := l.conns[0]
+	l.conns = l.conns[1:]
+	return c, nil
+}
+
+func TestAllowListener(t *testing.T) {
+	from := func(ip string) *remoteConn {
+		return &remoteConn{addr: &net.TCPAddr{IP: net.ParseIP(ip), Port: 40000}}
+	}
+	lte, home, local := from("100.64.3.2"), from("192.168.1.9"), from("127.0.0.1")
+
+	a := &App{cfg: config.DefaultConfig()}
+	ln := &queuedListener{conns: []net.Conn{lte, home}}
+	if got := a.allowListener(ln, "[Health]"); got != net.Listener(ln) {
+		t.Error("empty allowed_cidrs wrapped the listener")
+	}
+
+	a.cfg.HealthAllowedCIDRs = []string{"192.168.1.0/24"}
+	ln = &queuedListener{conns: []net.Conn{lte, home, local}}
+	allowed := a.allowListener(ln, "[Health]")
+	for _, want := range []*remoteConn{home, local} {
+		if c, err := allowed.Accept(); err != nil || c != want {
+			t.Fatalf("Accept = %v, %v; want the conn from %v", c, err, want.addr)
+		}
+	}
+	if !lte.closed || home.closed {
+		t.Errorf("closed: LTE %v, home %v; want only the LTE client", lte.closed, home.closed)
+	}
+
+	a.cfg.HealthAllowedCIDRs = []string{"lte0"} // Invalid: loopback only
+	ln = &queuedListener{conns: []net.Conn{from("192.168.1.9"), local}}
+	if c, err := a.allowListener(ln, "[Health]").Accept(); err != nil || c != local {
+		t.Errorf("invalid list: Accept = %v, %v; want the loopback conn", c, err)
+	}
+}
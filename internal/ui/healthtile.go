@@ -0,0 +1,365 @@
+package ui
+
+import (
+	"camera-dashboard-go/internal/helpers"
+	"fmt"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/widget"
+	"image/color"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// =============================================================================
+// Health tile ([display] health_tile)
+// =============================================================================
+// A grid tile with live system stats, refreshed every
+// healthTileInterval while the grid is on screen:
+//   - per camera: capture FPS and frames dropped, or offline
+//   - CPU temperature with a trend arrow, normalized load, memory use
+//   - free space where snapshots and clips are written
+//   - dashboard uptime
+// "replace" puts it where the settings tile was (tap it for the
+// settings panel; night mode and brightness stay on the panel's display
+// page), "extra" adds it as one more tile after the cameras. Like the
+// settings tile it can be swapped and is hidden in driving mode and in
+// the strip layout. CPU figures come from the adaptive FPS controller,
+// so they read "--" until cameras have started.
+// =============================================================================
+
+const (
+	healthSlot          = -2 // gridSlots entry of the health tile (-1 = settings)
+	healthTileInterval  = 2 * time.Second
+	healthTrendDeadband = 0.05 // °C per control tick treated as steady
+)
+
+// healthStats is one reading for the health tile.
+type healthStats struct {
+	cameras   []healthCamera
+	tempC     float64 // 0 = unknown
+	tempTrend float64 // °C per control tick
+	load      float64 // Normalized 0-1
+	memPct    float64 // 0 = unknown
+	diskFree  uint64
+	diskTotal uint64 // 0 = unknown
+	uptime    time.Duration
+}
+
+type healthCamera struct {
+	name    string
+	online  bool
+	fps     float64
+	dropped uint64
+}
+
+// startHealthTile refreshes the health tile, if there is one.
+func (a *App) startHealthTile() {
+	if a.healthTile == nil {
+		return
+	}
+	a.refreshHealthTile()
+	go func() {
+		ticker := time.NewTicker(healthTileInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-a.ctx.Done():
+				return
+			case <-ticker.C:
+				if a.healthTile.Visible() && !a.isFullscreen.Load() {
+					a.refreshHealthTile()
+				}
+			}
+		}
+	}()
+}
+
+func (a *App) refreshHealthTile() {
+	a.healthTile.SetLines(formatHealthLines(a.collectHealthStats()))
+}
+
+// collectHealthStats reads the tile's figures.
+func (a *App) collectHealthStats() healthStats {
+	s := healthStats{uptime: time.Since(a.trip.start)}
+
+	a.frameLock.RLock()
+	cams := a.cameras
+	status := append([]bool(nil), a.cameraStatus...)
+	a.frameLock.RUnlock()
+	manager := a.manager.Load()
+	for i, cam := range cams {
+		c := healthCamera{name: cam.DeviceID}
+		if name := a.cameraName(i); name != "" {
+			c.name = name
+		}
+		c.online = i < len(status) && status[i]
+		if manager != nil {
+			if worker := manager.GetWorker(cam.DeviceID); worker != nil {
+				_, c.fps, _ = worker.GetStats()
+			}
+			if buffer := manager.GetFrameBuffer(cam.DeviceID); buffer != nil {
+				c.dropped = buffer.GetDroppedCount()
+			}
+		}
+		s.cameras = append(s.cameras, c)
+	}
+
+	if pc := a.perfController.Load(); pc != nil {
+		s.tempC = pc.GetTemperature()
+		s.tempTrend = pc.GetTempTrend()
+		s.load = pc.GetLoadAverage()
+		s.memPct = pc.GetMemoryUsage()
+	}
+	if free, total, err := helpers.DiskFree(existingDir(a.recordingsDir())); err == nil {
+		s.diskFree, s.diskTotal = free, total
+	}
+	return s
+}
+
+// recordingsDir is where snapshots (or else clips) are written.
+func (a *App) recordingsDir() string {
+	if a.cfg.SnapshotDir != "" {
+		return a.cfg.SnapshotDir
+	}
+	return a.cfg.ClipsDir
+}
+
+// existingDir returns dir, or its nearest existing parent (the
+// recordings directory is only created by the first recording).
+func existingDir(dir string) string {
+	if dir == "" {
+		dir = "."
+	}
+	for {
+		if _, err := os.Stat(dir); err == nil {
+			return dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return dir
+		}
+		dir = parent
+	}
+}
+
+// formatHealthLines renders the tile's text, one string per line.
+func formatHealthLines(s healthStats) []string {
+	lines := []string{fmt.Sprintf("SYSTEM     up %s", formatUptime(s.uptime))}
+
+	cpu := "CPU --"
+	if s.tempC > 0 {
+		cpu = fmt.Sprintf("CPU %.1f°C %s", s.tempC, tempArrow(s.tempTrend))
+	}
+	lines = append(lines, fmt.Sprintf("%-13s load %3.0f%%", cpu, s.load*100))
+
+	mem := "Mem --"
+	if s.memPct > 0 {
+		mem = fmt.Sprintf("Mem %.0f%%", s.memPct)
+	}
+	disk := "disk --"
+	if s.diskTotal > 0 {
+		disk = fmt.Sprintf("disk %s free", formatBytes(s.diskFree))
+	}
+	lines = append(lines, fmt.Sprintf("%-8s %s", mem, disk))
+
+	if len(s.cameras) == 0 {
+		return append(lines, "No cameras")
+	}
+	for _, c := range s.cameras {
+		if !c.online {
+			lines = append(lines, fmt.Sprintf("%-9.9s offline", c.name))
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("%-9.9s %4.1f fps %4d drop", c.name, c.fps, c.dropped))
+	}
+	return lines
+}
+
+// tempArrow shows which way the CPU temperature is heading.
+func tempArrow(trend float64) string {
+	switch {
+	case trend > healthTrendDeadband:
+		return "↑"
+	case trend < -healthTrendDeadband:
+		return "↓"
+	}
+	return "→"
+}
+
+// formatBytes shows a size in GB or MB.
+func formatBytes(n uint64) string {
+	if n >= 1<<30 {
+		return fmt.Sprintf("%.1f GB", float64(n)/(1<<30))
+	}
+	return fmt.Sprintf("%d MB", n>>20)
+}
+
+// formatUptime shows a duration as 2d 4h, 3h12m or 7m.
+func formatUptime(d time.Duration) string {
+	switch {
+	case d >= 24*time.Hour:
+		return fmt.Sprintf("%dd %dh", int(d.Hours())/24, int(d.Hours())%24)
+	case d >= time.Hour:
+		return fmt.Sprintf("%dh%02dm", int(d.Hours()), int(d.Minutes())%60)
+	}
+	return fmt.Sprintf("%dm", int(d.Minutes()))
+}
+
+// HealthTile is the grid tile showing health stats; it can be tapped,
+// long-pressed and highlighted like the settings tile.
+type HealthTile struct {
+	widget.BaseWidget
+	bg             *canvas.Rectangle
+	border         *canvas.Rectangle
+	outline        color.Color // Border color when not highlighted
+	box            *fyne.Container
+	lines          []*canvas.Text
+	textColor      color.Color
+	onTap          func()
+	onLongTap      func()
+	longPressTimer *time.Timer
+	longPressFired bool
+	tapHandled     bool
+	highlighted    bool
+	focused        bool // Keyboard focus (see keyboard.go)
+	mu             sync.Mutex
+}
+
+func newHealthTile(onTap, onLongTap func()) *HealthTile {
+	t := &HealthTile{
+		bg:        canvas.NewRectangle(activeTheme().SettingsTile),
+		border:    canvas.NewRectangle(color.Transparent),
+		outline:   color.Transparent,
+		box:       container.NewVBox(),
+		textColor: activeTheme().DetailText,
+		onTap:     onTap,
+		onLongTap: onLongTap,
+	}
+	t.border.StrokeWidth = 4
+	t.border.StrokeColor = color.Transparent
+	t.ExtendBaseWidget(t)
+	return t
+}
+
+func (t *HealthTile) CreateRenderer() fyne.WidgetRenderer {
+	return widget.NewSimpleRenderer(container.NewStack(t.bg, container.NewCenter(t.box), t.border))
+}
+
+// SetLines replaces the tile's text.
+func (t *HealthTile) SetLines(lines []string) {
+	for len(t.lines) < len(lines) {
+		text := canvas.NewText("", t.textColor)
+		text.TextSize = 13
+		text.TextStyle = fyne.TextStyle{Monospace: true}
+		t.lines = append(t.lines, text)
+		t.box.Add(text)
+	}
+	for i, text := range t.lines {
+		switch {
+		case i >= len(lines):
+			text.Hide()
+		case text.Text != lines[i] || !text.Visible():
+			text.Text = lines[i]
+			text.Show()
+			text.Refresh()
+		}
+	}
+}
+
+// SetTheme recolors the tile (night mode switches the palette).
+func (t *HealthTile) SetTheme(th uiTheme) {
+	t.bg.FillColor = th.SettingsTile
+	t.bg.Refresh()
+	t.textColor = th.DetailText
+	for _, text := range t.lines {
+		text.Color = th.DetailText
+		text.Refresh()
+	}
+	t.SetOutline(th.Border)
+}
+
+// SetOutline sets the border color shown while neither highlighted nor
+// focused.
+func (t *HealthTile) SetOutline(c color.Color) {
+	t.mu.Lock()
+	t.outline = c
+	t.mu.Unlock()
+	t.updateBorder()
+}
+
+// SetHighlight sets the border highlight for swap mode
+func (t *HealthTile) SetHighlight(on bool) {
+	t.mu.Lock()
+	t.highlighted = on
+	t.mu.Unlock()
+	t.updateBorder()
+}
+
+// SetFocused sets the keyboard focus outline (see keyboard.go).
+func (t *HealthTile) SetFocused(on bool) {
+	t.mu.Lock()
+	t.focused = on
+	t.mu.Unlock()
+	t.updateBorder()
+}
+
+func (t *HealthTile) updateBorder() {
+	t.mu.Lock()
+	c := tileBorderColor(t.highlighted, t.focused, t.outline)
+	t.mu.Unlock()
+	t.border.StrokeColor = c
+	t.border.Refresh()
+}
+
+// MouseDown starts the long-press timer
+func (t *HealthTile) MouseDown(_ *desktop.MouseEvent) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.longPressFired = false
+	t.tapHandled = false
+	if t.longPressTimer != nil {
+		t.longPressTimer.Stop()
+	}
+	t.longPressTimer = time.AfterFunc(holdThreshold, func() {
+		t.mu.Lock()
+		t.longPressFired = true
+		t.tapHandled = true
+		t.mu.Unlock()
+		log.Println("[UI] Health tile: long press")
+		if t.onLongTap != nil {
+			t.onLongTap()
+		}
+	})
+}
+
+// MouseUp taps unless the long press fired
+func (t *HealthTile) MouseUp(_ *desktop.MouseEvent) {
+	t.mu.Lock()
+	if t.longPressTimer != nil {
+		t.longPressTimer.Stop()
+		t.longPressTimer = nil
+	}
+	tap := !t.longPressFired && !t.tapHandled
+	t.tapHandled = true
+	t.mu.Unlock()
+	if tap && t.onTap != nil {
+		t.onTap()
+	}
+}
+
+// Tapped handles touch taps that arrive without mouse events
+func (t *HealthTile) Tapped(_ *fyne.PointEvent) {
+	t.mu.Lock()
+	tap := !t.longPressFired && !t.tapHandled
+	t.tapHandled = true
+	t.mu.Unlock()
+	if tap && t.onTap != nil {
+		t.onTap()
+	}
+}
@@ -0,0 +1,74 @@
+package ui
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestFormatHealthLines(t *testing.T) {
+	s := healthStats{
+		cameras: []healthCamera{
+			{name: "Rear", online: true, fps: 14.96, dropped: 3},
+			{name: "Left blind spot", online: false},
+		},
+		tempC:     61.24,
+		tempTrend: 0.2,
+		load:      0.42,
+		memPct:    37.4,
+		diskFree:  12 << 30,
+		diskTotal: 32 << 30,
+		uptime:    3*time.Hour + 12*time.Minute,
+	}
+	want := []string{
+		"SYSTEM     up 3h12m",
+		"CPU 61.2°C ↑  load  42%",
+		"Mem 37%  disk 12.0 GB free",
+		"Rear      15.0 fps    3 drop",
+		"Left blin offline",
+	}
+	if got := formatHealthLines(s); !reflect.DeepEqual(got, want) {
+		t.Errorf("formatHealthLines() =\n%q\nwant\n%q", got, want)
+	}
+
+	// Nothing known yet
+	want = []string{
+		"SYSTEM     up 0m",
+		"CPU --        load   0%",
+		"Mem --   disk --",
+		"No cameras",
+	}
+	if got := formatHealthLines(healthStats{}); !reflect.DeepEqual(got, want) {
+		t.Errorf("formatHealthLines(empty) =\n%q\nwant\n%q", got, want)
+	}
+}
+
+func TestTempArrow(t *testing.T) {
+	for trend, want := range map[float64]string{0.3: "↑", -0.3: "↓", 0.02: "→", 0: "→"} {
+		if got := tempArrow(trend); got != want {
+			t.Errorf("tempArrow(%v) = %s, want %s", trend, got, want)
+		}
+	}
+}
+
+func TestFormatUptime(t *testing.T) {
+	for d, want := range map[time.Duration]string{
+		7*time.Minute + 30*time.Second: "7m",
+		time.Hour + 5*time.Minute:      "1h05m",
+		52 * time.Hour:                 "2d 4h",
+	} {
+		if got := formatUptime(d); got != want {
+			t.Errorf("formatUptime(%v) = %q, want %q", d, got, want)
+		}
+	}
+	if got := formatBytes(700 << 20); got != "700 MB" {
+		t.Errorf("formatBytes(700 MiB) = %q", got)
+	}
+}
+
+func TestExistingDir(t *testing.T) {
+	dir := t.TempDir()
+	if got := existingDir(dir + "/snapshots/today"); got != dir {
+		t.Errorf("existingDir() = %q, want %q", got, dir)
+	}
+}
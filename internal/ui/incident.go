@@ -0,0 +1,80 @@
+package ui
+
+import (
+	"camera-dashboard-go/internal/sensors"
+	"fmt"
+	"log"
+)
+
+// =============================================================================
+// Incidents (G-sensor)
+// =============================================================================
+// With [gsensor] enabled, an I2C accelerometer (MPU6050) is polled for
+// impacts (see internal/sensors). An impact is logged as an [Incident],
+// snapshots every live camera (and saves clips when [clips] is on, so
+// the moment is kept even when nobody presses a button) and is
+// published as an MQTT "incident" event with the saved paths (and the GPS position when [gps] has a fix).
+// The saved files are marked .keep so the disk manager never deletes them.
+// =============================================================================
+
+// startGSensor opens the accelerometer and starts impact detection if
+// [gsensor] is enabled. A missing sensor is logged, not fatal.
+func (a *App) startGSensor() {
+	if !a.cfg.GSensorEnabled {
+		return
+	}
+	sensor, err := sensors.OpenMPU6050(a.cfg.GSensorBus, a.cfg.GSensorAddress)
+	if err != nil {
+		log.Printf("[Incident] WARNING: G-sensor disabled: %v", err)
+		return
+	}
+	log.Printf("[Incident] G-sensor on %s 0x%02x, threshold %.1f g at %d Hz",
+		a.cfg.GSensorBus, a.cfg.GSensorAddress, a.cfg.GSensorThresholdG, a.cfg.GSensorSampleHz)
+
+	detector := sensors.NewImpactDetector(a.cfg.GSensorThresholdG, secondsToDuration(a.cfg.GSensorCooldownSec))
+	monitor := sensors.NewMonitor(sensor, detector, a.cfg.GSensorSampleHz, a.handleImpact)
+	a.gsensorMonitor.Store(monitor)
+	go func() {
+		monitor.Run(a.ctx.Done())
+		a.gsensorMonitor.Store(nil)
+		sensor.Close()
+	}()
+}
+
+// handleImpact records an incident. Runs on the sensor goroutine, so the
+// snapshot writes are moved off it.
+func (a *App) handleImpact(impact sensors.Impact) {
+	log.Printf("[Incident] Impact %.1f g (x=%.2f y=%.2f z=%.2f)",
+		impact.PeakG, impact.Raw.X, impact.Raw.Y, impact.Raw.Z)
+	a.toast("incident", fmt.Sprintf("Impact %.1f g: saving snapshots", impact.PeakG), toastWarning)
+	fix, hasFix := a.currentFix()
+	if hasFix {
+		log.Printf("[Incident] Position %.5f, %.5f at %s", fix.Lat, fix.Lon, formatSpeed(fix.SpeedMS, a.cfg.GPSUnits))
+	}
+
+	go func() {
+		paths := a.saveAllSnapshots()
+		log.Printf("[Incident] Saved %d snapshots", len(paths))
+		if a.clipWindow() > 0 {
+			clips := a.saveAllClips("incident")
+			log.Printf("[Incident] Saved %d clips", len(clips))
+			paths = append(paths, clips...)
+		}
+		protectRecordings(paths)
+		if a.mqttClient.Load() == nil {
+			return
+		}
+		event := map[string]interface{}{
+			"type":      "incident",
+			"peak_g":    impact.PeakG,
+			"snapshots": paths,
+			"timestamp": impact.Time.Unix(),
+		}
+		if hasFix {
+			event["lat"] = fix.Lat
+			event["lon"] = fix.Lon
+			event["speed_ms"] = fix.SpeedMS
+		}
+		a.publishJSON("event", event, false)
+	}()
+}
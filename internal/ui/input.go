@@ -0,0 +1,159 @@
+package ui
+
+import (
+	"camera-dashboard-go/internal/input"
+	"log"
+	"sort"
+	"time"
+)
+
+// =============================================================================
+// Hardware buttons and rotary encoders ([input])
+// =============================================================================
+// Push buttons on GPIO lines and evdev keys/rotary encoders (see
+// internal/input) drive the dashboard with gloves on:
+//   next_camera / prev_camera  step through the cameras in fullscreen,
+//                              starting from the grid (an encoder's
+//                              natural binding)
+//   fullscreen                 fullscreen the keyboard-focused (or first)
+//                              camera, or go back to the grid
+//   night_mode                 toggle night mode
+//   snapshot                   snapshot the fullscreen camera, or all
+//                              cameras from the grid
+// Each device is read on its own goroutine; actions are run one at a time
+// on a single dispatcher, and dropped if it falls behind.
+// =============================================================================
+
+// startInput opens the configured buttons and encoders.
+func (a *App) startInput() {
+	if len(a.cfg.InputBindings) == 0 {
+		return
+	}
+	keys := make([]string, 0, len(a.cfg.InputBindings))
+	for key := range a.cfg.InputBindings {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var bindings []input.Binding
+	for _, key := range keys {
+		b, err := input.ParseBinding(key, a.cfg.InputBindings[key])
+		if err != nil {
+			log.Printf("[Input] WARNING: [input] %s: %v", key, err)
+			continue
+		}
+		bindings = append(bindings, b)
+	}
+	if len(bindings) == 0 {
+		return
+	}
+	mapper := input.NewMapper(bindings, time.Duration(a.cfg.InputDebounceMs)*time.Millisecond)
+
+	devices := make(map[string]input.Device)
+	for _, path := range a.cfg.InputDevices {
+		dev, err := input.OpenEvdev(path)
+		if err != nil {
+			log.Printf("[Input] WARNING: %s: %v", path, err)
+			continue
+		}
+		devices[path] = dev
+	}
+	for _, b := range bindings {
+		if b.Control.Kind != input.GPIO {
+			continue
+		}
+		dev, err := input.OpenGPIOButton(a.cfg.InputChip, b.Control.Code, b.ActiveHigh)
+		if err != nil {
+			log.Printf("[Input] WARNING: %s: %v", b.Control, err)
+			continue
+		}
+		devices[b.Control.String()] = dev
+	}
+	if len(devices) == 0 {
+		log.Println("[Input] WARNING: no usable input devices")
+		return
+	}
+	log.Printf("[Input] %d bindings on %d devices", len(bindings), len(devices))
+
+	actions := make(chan string, 8)
+	for name, dev := range devices {
+		go mapper.Run(name, dev, func(action string) {
+			select {
+			case actions <- action:
+			default:
+				log.Printf("[Input] Busy, dropped %s", action)
+			}
+		})
+	}
+	go func() {
+		for {
+			select {
+			case <-a.ctx.Done():
+				for _, dev := range devices {
+					dev.Close()
+				}
+				return
+			case action := <-actions:
+				a.onInputAction(action)
+			}
+		}
+	}()
+}
+
+// onInputAction runs a button or encoder action.
+func (a *App) onInputAction(action string) {
+	switch action {
+	case input.ActionNextCamera, input.ActionPrevCamera:
+		if a.isFullscreen.Load() {
+			if action == input.ActionNextCamera {
+				a.onFullscreenSwipe(swipeLeft)
+			} else {
+				a.onFullscreenSwipe(swipeRight)
+			}
+			return
+		}
+		// From the grid: the first camera, or the last going backwards
+		current, step := -1, 1
+		if action == input.ActionPrevCamera {
+			current, step = 0, -1
+		}
+		if pos := nextFullscreenSlot(a.gridSlots, a.cameraCount(), current, step); pos >= 0 {
+			a.showFullscreen(pos)
+		}
+	case input.ActionFullscreen:
+		if a.isFullscreen.Load() {
+			pos := a.fullscreenSlot
+			a.hideFullscreen()
+			a.setKeyFocus(pos)
+			return
+		}
+		pos := a.keyFocus
+		camCount := a.cameraCount()
+		if pos < 0 || pos >= len(a.gridSlots) || a.gridSlots[pos] < 0 || a.gridSlots[pos] >= camCount {
+			pos = nextFullscreenSlot(a.gridSlots, camCount, -1, 1)
+		}
+		if pos >= 0 {
+			a.showFullscreen(pos)
+		}
+	case input.ActionNightMode:
+		a.toggleNightMode()
+	case input.ActionSnapshot:
+		if !a.isFullscreen.Load() {
+			go a.saveAllSnapshots()
+			return
+		}
+		camIndex := a.gridSlots[a.fullscreenSlot]
+		go func() {
+			if _, err := a.saveSnapshot(camIndex); err != nil {
+				log.Printf("[Snapshot] Camera %d skipped: %v", camIndex, err)
+			}
+		}()
+	}
+}
+
+// cameraCount returns the number of cameras found.
+func (a *App) cameraCount() int {
+	a.frameLock.RLock()
+	defer a.frameLock.RUnlock()
+	return len(a.cameras)
+}
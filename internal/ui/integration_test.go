@@ -0,0 +1,275 @@
+//go:build integration
+
+package ui
+
+import (
+	"camera-dashboard-go/internal/config"
+	"camera-dashboard-go/internal/testsupport"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Supervision end to end: a headless App on fake cameras, with short
+// timeouts so stale detection, restart policies and hotplug act within
+// seconds.
+//
+//   go test -race -tags integration ./internal/ui/
+
+// startFakeApp starts capture, stale detection and hotplug polling on the
+// backend's cameras and waits until they are connected.
+func startFakeApp(t *testing.T, backend *testsupport.FakeBackend, slots, cameras int) *App {
+	t.Helper()
+	cfg := config.DefaultConfig()
+	cfg.CaptureWidth, cfg.CaptureHeight, cfg.CaptureFPS = 64, 48, 20
+	cfg.CameraSlotCount = slots
+	cfg.StaleFrameTimeoutSec = 0.5
+	cfg.RestartCooldownSec = 0.5
+	cfg.RestartWindowSec = 30
+	cfg.MaxRestartsPerWindow = 2
+	cfg.RescanIntervalMS = 200
+	cfg.FailedCameraCooldownS = 1
+	cfg.KillDeviceHolders = false
+
+	a := NewHeadlessApp(cfg)
+	a.captureBackend = backend
+	a.initializeCamerasAsync()
+	a.startCameraRefresh()
+	a.captureLoops = newLoopGroup(a.ctx, "capture")
+	a.captureLoops.Go(a.startHotplugDetection)
+	a.captureLoops.Go(a.startStaleFrameDetection)
+	t.Cleanup(func() {
+		a.cancel()
+		a.stopLoops()
+		if m := a.manager.Load(); m != nil {
+			m.Stop()
+		}
+	})
+	waitForApp(t, 3*time.Second, "cameras connected", func() bool {
+		return a.connectedCameras() == cameras && a.hasFrame(0)
+	})
+	return a
+}
+
+func waitForApp(t *testing.T, timeout time.Duration, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(timeout)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out after %v waiting for %s", timeout, what)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+func (a *App) connectedCameras() int {
+	a.frameLock.RLock()
+	defer a.frameLock.RUnlock()
+	n := 0
+	for i := range a.cameras {
+		if i < len(a.cameraStatus) && a.cameraStatus[i] {
+			n++
+		}
+	}
+	return n
+}
+
+func (a *App) hasFrame(camIndex int) bool {
+	a.frameLock.RLock()
+	defer a.frameLock.RUnlock()
+	return !a.lastFrameTime[camIndex].IsZero()
+}
+
+func (a *App) recoveryAction(camIndex int) string {
+	a.frameLock.RLock()
+	defer a.frameLock.RUnlock()
+	return a.slotRecovery[camIndex].action
+}
+
+func TestIntegration_StaleRestartAndLimit(t *testing.T) {
+	backend := testsupport.NewFakeBackend()
+	dev := backend.Add("fake0", "USB Fake Front")
+	a := startFakeApp(t, backend, 1, 1)
+
+	// A hung camera: restarted twice, then the window's limit holds
+	dev.Stall()
+	waitForApp(t, 5*time.Second, "two stale restarts", func() bool { return dev.Opens() == 3 })
+	waitForApp(t, 5*time.Second, "restart limit", func() bool {
+		return a.recoveryAction(0) == "restart limit reached, backing off"
+	})
+	time.Sleep(2 * time.Second)
+	if n := dev.Opens(); n != 3 {
+		t.Errorf("stream opened %d times, want 3 (limit of 2 restarts)", n)
+	}
+	if a.connectedCameras() != 0 {
+		t.Error("stalled camera shown connected")
+	}
+}
+
+func TestIntegration_StaleRestartRecovers(t *testing.T) {
+	backend := testsupport.NewFakeBackend()
+	dev := backend.Add("fake0", "USB Fake Front")
+	a := startFakeApp(t, backend, 1, 1)
+
+	// A hiccup: the first restart brings the picture back
+	dev.Stall()
+	waitForApp(t, 3*time.Second, "stale restart", func() bool { return dev.Opens() == 2 })
+	dev.Resume()
+	time.Sleep(2 * time.Second)
+	if a.connectedCameras() != 1 || dev.Opens() != 2 {
+		t.Errorf("connected %d, opens %d; want 1 camera restarted once", a.connectedCameras(), dev.Opens())
+	}
+}
+
+func TestIntegration_HotplugUnplugAndNewCamera(t *testing.T) {
+	backend := testsupport.NewFakeBackend()
+	front := backend.Add("fake0", "USB Fake Front")
+	a := startFakeApp(t, backend, 2, 1)
+
+	front.Unplug()
+	waitForApp(t, 3*time.Second, "unplug noticed", func() bool {
+		return a.recoveryAction(0) == "unplugged, waiting for device"
+	})
+	front.Plug()
+	waitForApp(t, 10*time.Second, "replug", func() bool { return a.connectedCameras() == 1 })
+	worker := a.manager.Load().GetWorker("fake0")
+	waitForApp(t, 5*time.Second, "picture after replug", func() bool { return !worker.SignalLost() })
+
+	// A camera plugged into the free slot is picked up
+	backend.Add("fake1", "USB Fake Rear")
+	waitForApp(t, 10*time.Second, "new camera", func() bool { return a.connectedCameras() == 2 })
+}
+
+func TestIntegration_SoftRestart(t *testing.T) {
+	backend := testsupport.NewFakeBackend()
+	dev := backend.Add("fake0", "USB Fake Front")
+	a := startFakeApp(t, backend, 1, 1)
+
+	path := filepath.Join(t.TempDir(), "config.ini")
+	content := "[profile]\ncapture_width = 64\ncapture_height = 48\ncapture_fps = 10\n\n" +
+		"[camera]\nslot_count = 1\nrescan_interval_ms = 200\nkill_device_holders = false\n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	a.cfg.Path = path
+	a.WatchConfig(config.NewWatcher(path, a.cfg))
+
+	old := a.manager.Load()
+	a.restart()
+	if a.cfg.CaptureFPS != 10 {
+		t.Errorf("CaptureFPS = %d after restart, want 10 from config.ini", a.cfg.CaptureFPS)
+	}
+	waitForApp(t, 5*time.Second, "cameras after restart", func() bool {
+		m := a.manager.Load()
+		return m != nil && m != old && a.connectedCameras() == 1 && a.hasFrame(0)
+	})
+	if n := dev.Opens(); n != 2 {
+		t.Errorf("stream opened %d times, want 2 (once per start)", n)
+	}
+}
+
+func TestIntegration_ProfileSwitch(t *testing.T) {
+	backend := testsupport.NewFakeBackend()
+	backend.Add("fake0", "USB Fake Rear")
+	a := startFakeApp(t, backend, 1, 1)
+
+	path := filepath.Join(t.TempDir(), "config.ini")
+	content := "[profile]\ncapture_width = 64\ncapture_height = 48\ncapture_fps = 20\n\n" +
+		"[camera]\nslot_count = 1\nrescan_interval_ms = 200\nkill_device_holders = false\n\n" +
+		"[camera.rear]\ndevice = fake0\n\n" +
+		"[profile.towing]\ncamera.rear.fps = 30\n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	a.cfg.Path = path
+	a.WatchConfig(config.NewWatcher(path, a.cfg))
+	a.configWatcher.Reload() // Picks up the profiles
+
+	old := a.manager.Load()
+	if fps := old.GetWorker("fake0").GetMaxFPS(); fps != 20 {
+		t.Fatalf("rear camera at %d fps before the switch, want 20", fps)
+	}
+	if _, err := a.selectProfile("towing"); err != nil {
+		t.Fatal(err)
+	}
+	waitForApp(t, 5*time.Second, "cameras after the profile switch", func() bool {
+		m := a.manager.Load()
+		return m != nil && m != old && a.connectedCameras() == 1 && a.hasFrame(0)
+	})
+	if fps := a.manager.Load().GetWorker("fake0").GetMaxFPS(); fps != 30 {
+		t.Errorf("rear camera at %d fps after switching to towing, want 30", fps)
+	}
+}
+
+func TestIntegration_Headless(t *testing.T) {
+	backend := testsupport.NewFakeBackend()
+	backend.Add("fake0", "USB Fake Front")
+	dir := t.TempDir()
+
+	cfg := config.DefaultConfig()
+	cfg.CaptureWidth, cfg.CaptureHeight, cfg.CaptureFPS = 64, 48, 20
+	cfg.CameraSlotCount = 1
+	cfg.KillDeviceHolders = false
+	cfg.SnapshotDir = filepath.Join(dir, "snapshots")
+	cfg.ClipsDir = filepath.Join(dir, "clips")
+	cfg.TripReportDir = filepath.Join(dir, "trips")
+	a := NewHeadlessApp(cfg)
+	a.captureBackend = backend
+	t.Cleanup(a.Cleanup)
+
+	done := make(chan struct{})
+	go func() {
+		a.Start()
+		close(done)
+	}()
+	waitForApp(t, 3*time.Second, "camera connected", func() bool {
+		return a.connectedCameras() == 1 && a.hasFrame(0)
+	})
+	select {
+	case <-done:
+		t.Fatal("Start returned before quit")
+	default:
+	}
+
+	a.quit()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Start still blocked 2s after quit")
+	}
+}
+
+func TestIntegration_TraceFrames(t *testing.T) {
+	backend := testsupport.NewFakeBackend()
+	backend.Add("fake0", "USB Fake Front")
+	path := filepath.Join(t.TempDir(), "trace.csv")
+
+	a := NewHeadlessApp(config.DefaultConfig())
+	a.cfg.CaptureWidth, a.cfg.CaptureHeight, a.cfg.CaptureFPS = 64, 48, 20
+	a.cfg.CameraSlotCount = 1
+	a.cfg.KillDeviceHolders = false
+	a.cfg.TraceFrames, a.cfg.TraceCSV, a.cfg.TraceSampleEvery = true, path, 2
+	a.captureBackend = backend
+	a.initializeCamerasAsync()
+	a.startCameraRefresh()
+	t.Cleanup(func() {
+		a.cancel()
+		a.stopLoops()
+		if m := a.manager.Load(); m != nil {
+			m.Stop()
+		}
+	})
+
+	waitForApp(t, 5*time.Second, "traced frames", func() bool {
+		data, _ := os.ReadFile(path)
+		return strings.Count(string(data), "\n") >= 3 // Header + 2 samples
+	})
+	data, _ := os.ReadFile(path)
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n")[1:] {
+		if fields := strings.Split(line, ","); len(fields) != len(traceCSVHeader) || fields[1] != "fake0" {
+			t.Errorf("trace row %q, want %d fields for fake0", line, len(traceCSVHeader))
+		}
+	}
+}
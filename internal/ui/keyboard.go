@@ -0,0 +1,212 @@
+package ui
+
+import (
+	"fyne.io/fyne/v2"
+	"image/color"
+	"math"
+)
+
+// =============================================================================
+// Keyboard control
+// =============================================================================
+// Everything the touchscreen does can be done from a keyboard, e.g. a
+// steering-wheel keypad mapped to HID keys:
+//   Arrow keys   move the focus outline between tiles (theme "focus"
+//                color); in fullscreen, Left/Right change camera
+//   Enter        fullscreen for the focused camera, the settings panel
+//                for the settings tile, or finish a swap
+//   F            toggle fullscreen for the focused tile
+//   N            toggle night mode
+//   S            pick the focused tile for a swap, then S (or Enter) on
+//                another tile swaps them
+//   Esc          back out: PIN keypad, panels, fullscreen, swap mode,
+//                then the focus outline itself
+// The PIN keypad (lock.go) takes digits, Enter and Backspace. While the
+// settings or controls panel is open only Esc is handled here; Fyne
+// moves between their widgets with Tab.
+// =============================================================================
+
+// setupKeyboard routes unhandled key presses to onKey.
+func (a *App) setupKeyboard() {
+	a.window.Canvas().SetOnTypedKey(a.onKey)
+}
+
+// onKey handles a key press no focused widget took.
+func (a *App) onKey(ev *fyne.KeyEvent) {
+	switch {
+	case a.lockScreen != nil && a.lockScreen.content.Visible():
+		a.lockScreenKey(ev.Name)
+		return
+	case a.settingsPanel != nil && a.settingsPanel.content.Visible():
+		if ev.Name == fyne.KeyEscape {
+			a.settingsPanel.close()
+		}
+		return
+	case a.controlsPanel != nil && a.controlsPanel.content.Visible():
+		if ev.Name == fyne.KeyEscape {
+			a.controlsPanel.close()
+		}
+		return
+	}
+
+	if a.isFullscreen.Load() {
+		a.fullscreenKey(ev.Name)
+		return
+	}
+
+	switch ev.Name {
+	case fyne.KeyLeft:
+		a.moveKeyFocus(swipeLeft)
+	case fyne.KeyRight:
+		a.moveKeyFocus(swipeRight)
+	case fyne.KeyUp:
+		a.moveKeyFocus(swipeUp)
+	case fyne.KeyDown:
+		a.moveKeyFocus(swipeDown)
+	case fyne.KeyReturn, fyne.KeyEnter:
+		if pos := a.focusedTile(); pos >= 0 {
+			if a.gridSlots[pos] == -1 && !a.swapMode {
+				a.openSettings()
+			} else {
+				a.onGridTap(pos)
+			}
+		}
+	case fyne.KeyF:
+		if pos := a.focusedTile(); pos >= 0 {
+			a.showFullscreen(pos)
+		}
+	case fyne.KeyN:
+		a.toggleNightMode()
+	case fyne.KeyS:
+		if pos := a.focusedTile(); pos >= 0 {
+			if a.swapMode {
+				a.handleSwapTap(pos)
+			} else if !a.drivingMode.Load() {
+				a.enterSwapMode(pos)
+			}
+		}
+	case fyne.KeyEscape:
+		if a.swapMode {
+			a.cancelSwapMode()
+		} else {
+			a.setKeyFocus(-1)
+		}
+	}
+}
+
+// fullscreenKey handles a key press in fullscreen.
+func (a *App) fullscreenKey(key fyne.KeyName) {
+	switch key {
+	case fyne.KeyLeft:
+		a.onFullscreenSwipe(swipeRight) // Previous camera, as swiping right
+	case fyne.KeyRight:
+		a.onFullscreenSwipe(swipeLeft)
+	case fyne.KeyN:
+		a.toggleNightMode()
+	case fyne.KeyF, fyne.KeyEscape:
+		pos := a.fullscreenSlot
+		a.hideFullscreen()
+		a.setKeyFocus(pos) // Focus stays on the camera last shown
+	}
+}
+
+// lockScreenKey types on the PIN keypad.
+func (a *App) lockScreenKey(key fyne.KeyName) {
+	switch key {
+	case fyne.KeyEscape:
+		a.lockScreen.close()
+	case fyne.KeyReturn, fyne.KeyEnter:
+		a.lockScreen.press("OK")
+	case fyne.KeyBackspace, fyne.KeyDelete:
+		a.lockScreen.press("Clear")
+	case fyne.Key0, fyne.Key1, fyne.Key2, fyne.Key3, fyne.Key4,
+		fyne.Key5, fyne.Key6, fyne.Key7, fyne.Key8, fyne.Key9:
+		a.lockScreen.press(string(key))
+	}
+}
+
+// focusedTile returns the focused grid position, focusing the first
+// tile if nothing is focused yet (-1 = no visible tile).
+func (a *App) focusedTile() int {
+	if a.keyFocus < 0 || a.keyFocus >= len(a.grid.Objects) || !a.grid.Objects[a.keyFocus].Visible() {
+		a.moveKeyFocus(swipeNone)
+	}
+	return a.keyFocus
+}
+
+// moveKeyFocus moves the focus to the nearest tile in dir.
+func (a *App) moveKeyFocus(dir swipeDirection) {
+	objects := a.grid.Objects
+	centers := make([]fyne.Position, len(objects))
+	visible := make([]bool, len(objects))
+	for i, obj := range objects {
+		centers[i] = obj.Position().Add(fyne.NewPos(obj.Size().Width/2, obj.Size().Height/2))
+		visible[i] = obj.Visible()
+	}
+	a.setKeyFocus(nextFocus(centers, visible, a.keyFocus, dir))
+}
+
+// setKeyFocus moves the focus outline to grid position pos (-1 = none).
+func (a *App) setKeyFocus(pos int) {
+	a.keyFocus = pos
+	for i, w := range a.gridWidgets {
+		if w != nil {
+			w.SetFocused(i == pos)
+		}
+	}
+}
+
+// nextFocus returns the visible tile nearest to current in dir, given
+// the tile centers. Without a usable current tile it returns the first
+// visible one; with none in dir it stays on current.
+func nextFocus(centers []fyne.Position, visible []bool, current int, dir swipeDirection) int {
+	if current < 0 || current >= len(centers) || !visible[current] {
+		for i, v := range visible {
+			if v {
+				return i
+			}
+		}
+		return -1
+	}
+	best, bestScore := current, math.MaxFloat64
+	from := centers[current]
+	for i, c := range centers {
+		if i == current || !visible[i] {
+			continue
+		}
+		dx, dy := float64(c.X-from.X), float64(c.Y-from.Y)
+		var along, across float64
+		switch dir {
+		case swipeLeft:
+			along, across = -dx, dy
+		case swipeRight:
+			along, across = dx, dy
+		case swipeUp:
+			along, across = -dy, dx
+		case swipeDown:
+			along, across = dy, dx
+		default:
+			continue
+		}
+		if along < 1 || math.Abs(across) >= along {
+			continue // Behind, or more to the side than ahead
+		}
+		// Prefer tiles straight ahead over nearer ones off to the side
+		if score := along + 2*math.Abs(across); score < bestScore {
+			best, bestScore = i, score
+		}
+	}
+	return best
+}
+
+// tileBorderColor is a tile's outline: the swap highlight, else the
+// keyboard focus, else its normal outline.
+func tileBorderColor(highlighted, focused bool, outline color.Color) color.Color {
+	switch {
+	case highlighted:
+		return activeTheme().Highlight
+	case focused:
+		return activeTheme().Focus
+	}
+	return outline
+}
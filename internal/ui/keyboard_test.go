@@ -0,0 +1,54 @@
+package ui
+
+import (
+	"fyne.io/fyne/v2"
+	"image/color"
+	"testing"
+)
+
+func TestNextFocus(t *testing.T) {
+	// 3x2 grid: settings tile hidden at 0, cameras at 1-5
+	//   0 1 2
+	//   3 4 5
+	centers := []fyne.Position{
+		{X: 100, Y: 100}, {X: 300, Y: 100}, {X: 500, Y: 100},
+		{X: 100, Y: 300}, {X: 300, Y: 300}, {X: 500, Y: 300},
+	}
+	visible := []bool{false, true, true, true, true, true}
+
+	tests := []struct {
+		current int
+		dir     swipeDirection
+		want    int
+	}{
+		{-1, swipeRight, 1}, // First visible tile
+		{1, swipeRight, 2},
+		{1, swipeDown, 4},
+		{4, swipeLeft, 3},
+		{4, swipeUp, 1},
+		{1, swipeLeft, 1},  // Hidden settings tile is skipped
+		{2, swipeRight, 2}, // Edge: stay
+		{0, swipeDown, 1},  // Focus on a hidden tile restarts
+	}
+	for _, tt := range tests {
+		if got := nextFocus(centers, visible, tt.current, tt.dir); got != tt.want {
+			t.Errorf("nextFocus(%d, %v) = %d, want %d", tt.current, tt.dir, got, tt.want)
+		}
+	}
+	if got := nextFocus(centers, make([]bool, len(centers)), -1, swipeRight); got != -1 {
+		t.Errorf("no visible tiles = %d, want -1", got)
+	}
+}
+
+func TestTileBorderColor(t *testing.T) {
+	outline := color.RGBA{1, 2, 3, 255}
+	if got := tileBorderColor(false, false, outline); got != outline {
+		t.Errorf("plain = %v, want the outline", got)
+	}
+	if got := tileBorderColor(false, true, outline); got != activeTheme().Focus {
+		t.Errorf("focused = %v, want the focus color", got)
+	}
+	if got := tileBorderColor(true, true, outline); got != activeTheme().Highlight {
+		t.Errorf("highlighted and focused = %v, want the swap highlight", got)
+	}
+}
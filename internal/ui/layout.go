@@ -0,0 +1,162 @@
+package ui
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+)
+
+// =============================================================================
+// Startup layouts
+// =============================================================================
+// The dashboard can start in a different layout depending on what
+// launched it: a normal boot shows the grid, a reverse-gear wake can go
+// straight to the rear camera fullscreen, a remote request can put a
+// particular camera first. Presets live in [layouts] in config.ini; the
+// launcher picks one with -layout <name> or CAMERA_DASHBOARD_LAYOUT, and
+// the "default" preset (if any) is used otherwise.
+//
+// A preset is a comma-separated list of options:
+//   order=2 1 3    cameras in grid order after the settings tile
+//                  (1-based discovery order; unlisted cameras follow)
+//   fullscreen=2   open camera 2 fullscreen as soon as it is discovered
+//   driving        start in driving mode
+// =============================================================================
+
+// defaultLayoutName is the preset used when none is requested.
+const defaultLayoutName = "default"
+
+// startupLayout is a parsed [layouts] preset.
+type startupLayout struct {
+	order      []int // Camera indexes (0-based) in grid order
+	fullscreen int   // Camera index to open fullscreen; -1 = none
+	driving    bool
+}
+
+// parseLayout parses a preset spec for a dashboard with slots cameras.
+func parseLayout(spec string, slots int) (startupLayout, error) {
+	l := startupLayout{fullscreen: -1}
+	camIndex := func(s string) (int, error) {
+		n, err := strconv.Atoi(strings.TrimSpace(s))
+		if err != nil || n < 1 || n > slots {
+			return 0, fmt.Errorf("camera %q must be 1..%d", s, slots)
+		}
+		return n - 1, nil
+	}
+
+	for _, option := range strings.Split(spec, ",") {
+		option = strings.TrimSpace(option)
+		if option == "" {
+			continue
+		}
+		key, value, _ := strings.Cut(option, "=")
+		switch strings.ToLower(strings.TrimSpace(key)) {
+		case "order":
+			seen := make(map[int]bool)
+			for _, field := range strings.Fields(value) {
+				idx, err := camIndex(field)
+				if err != nil {
+					return l, fmt.Errorf("order: %w", err)
+				}
+				if seen[idx] {
+					return l, fmt.Errorf("order: camera %d listed twice", idx+1)
+				}
+				seen[idx] = true
+				l.order = append(l.order, idx)
+			}
+		case "fullscreen":
+			idx, err := camIndex(value)
+			if err != nil {
+				return l, fmt.Errorf("fullscreen: %w", err)
+			}
+			l.fullscreen = idx
+		case "driving":
+			l.driving = true
+		default:
+			return l, fmt.Errorf("unknown option %q", option)
+		}
+	}
+	return l, nil
+}
+
+// SetStartupLayout selects the [layouts] preset to apply on Start
+// ("" = the "default" preset, if configured).
+func (a *App) SetStartupLayout(name string) {
+	a.startupLayoutName = name
+}
+
+// resolveStartupLayout looks up and parses the requested preset. A
+// missing or invalid preset is logged and the plain grid is used.
+func (a *App) resolveStartupLayout() (startupLayout, bool) {
+	name := a.startupLayoutName
+	spec, ok := a.cfg.Layouts[name]
+	if name == "" {
+		name = defaultLayoutName
+		spec, ok = a.cfg.Layouts[name]
+		if !ok {
+			return startupLayout{}, false
+		}
+	}
+	if !ok {
+		log.Printf("[UI] WARNING: layout %q not found in [layouts], using the default grid", name)
+		return startupLayout{}, false
+	}
+	l, err := parseLayout(spec, a.effectiveSlots())
+	if err != nil {
+		log.Printf("[UI] WARNING: layout %q: %v, using the default grid", name, err)
+		return startupLayout{}, false
+	}
+	log.Printf("[UI] Startup layout %q: %s", name, spec)
+	return l, true
+}
+
+// applyStartupLayout applies the selected preset. Called from setupUI
+// once the grid exists; fullscreen waits for camera discovery.
+func (a *App) applyStartupLayout() {
+	l, ok := a.resolveStartupLayout()
+	if !ok {
+		return
+	}
+	if l.driving {
+		a.setDrivingMode(true)
+	}
+	for i, camIndex := range l.order {
+		from := a.gridPositionOfCamera(camIndex)
+		if to := i + 1; from >= 0 && from != to {
+			a.swapGridPositions(from, to)
+		}
+	}
+	a.pendingFullscreen.Store(int32(l.fullscreen))
+}
+
+// showPendingFullscreen opens the preset's fullscreen camera once it
+// has been discovered.
+func (a *App) showPendingFullscreen() {
+	camIndex := int(a.pendingFullscreen.Load())
+	if camIndex < 0 {
+		return
+	}
+	a.frameLock.RLock()
+	found := camIndex < len(a.cameras)
+	a.frameLock.RUnlock()
+	if !found {
+		return
+	}
+	if !a.pendingFullscreen.CompareAndSwap(int32(camIndex), -1) {
+		return
+	}
+	if pos := a.gridPositionOfCamera(camIndex); pos >= 0 {
+		a.showFullscreen(pos)
+	}
+}
+
+// gridPositionOfCamera returns the grid position showing camIndex, or -1.
+func (a *App) gridPositionOfCamera(camIndex int) int {
+	for pos, content := range a.gridSlots {
+		if content == camIndex {
+			return pos
+		}
+	}
+	return -1
+}
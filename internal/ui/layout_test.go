@@ -0,0 +1,27 @@
+package ui
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseLayout(t *testing.T) {
+	l, err := parseLayout("order=3 1, fullscreen=2, driving", 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(l.order, []int{2, 0}) || l.fullscreen != 1 || !l.driving {
+		t.Errorf("layout = %+v", l)
+	}
+
+	l, err = parseLayout("", 3)
+	if err != nil || l.order != nil || l.fullscreen != -1 || l.driving {
+		t.Errorf("empty layout = %+v, %v", l, err)
+	}
+
+	for _, bad := range []string{"order=1 1", "order=4", "fullscreen=0", "fullscreen", "zoom=2"} {
+		if _, err := parseLayout(bad, 3); err == nil {
+			t.Errorf("parseLayout(%q) should fail", bad)
+		}
+	}
+}
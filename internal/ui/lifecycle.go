@@ -0,0 +1,87 @@
+package ui
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// =============================================================================
+// Goroutine lifecycle
+// =============================================================================
+// Every background loop stops through a context. a.ctx is the root,
+// cancelled once by cleanup (or relaunch); services that run for the
+// whole process (alerts, GPS, battery, ...) select on a.ctx.Done().
+//
+// Loops that must be gone before the next step are run in a loopGroup,
+// a child context plus a WaitGroup, and stopped with Stop, which cancels
+// the group and waits for its goroutines:
+//   - refreshLoops: the camera refresh loop (or the render loop), so
+//     nothing reads the camera manager's buffers once it is stopped
+//   - captureLoops: hot-plug, stale frame and health loops; replaced by
+//     a soft restart (see restart.go) together with the camera manager
+//   - fullscreenLoop: the fullscreen update loop, replaced each time a
+//     camera goes fullscreen
+//
+// Stop waits a bounded time, so one stuck loop is logged instead of
+// hanging shutdown.
+// =============================================================================
+
+const loopStopWait = 2 * time.Second
+
+// loopGroup runs a subsystem's goroutines under one child context.
+type loopGroup struct {
+	name   string
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// newLoopGroup creates a group that also stops when parent is done.
+func newLoopGroup(parent context.Context, name string) *loopGroup {
+	ctx, cancel := context.WithCancel(parent)
+	return &loopGroup{name: name, ctx: ctx, cancel: cancel}
+}
+
+// Go runs fn in a goroutine of the group; fn returns when ctx is done.
+func (g *loopGroup) Go(fn func(ctx context.Context)) {
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		fn(g.ctx)
+	}()
+}
+
+// Stop cancels the group and waits up to loopStopWait for its
+// goroutines. It reports whether they all returned. A nil group is
+// already stopped.
+func (g *loopGroup) Stop() bool {
+	if g == nil {
+		return true
+	}
+	g.cancel()
+	done := make(chan struct{})
+	go func() {
+		g.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return true
+	case <-time.After(loopStopWait):
+		log.Printf("[UI] WARNING: %s loops did not stop within %v", g.name, loopStopWait)
+		return false
+	}
+}
+
+// stopLoops stops the refresh, capture and fullscreen loops and waits
+// for them (cleanup, after cancelling a.ctx).
+func (a *App) stopLoops() {
+	a.refreshLoops.Stop()
+	a.stopCaptureLoops()
+	a.fullscreenMu.Lock()
+	a.fullscreenLoop.Stop()
+	a.fullscreenLoop = nil
+	a.fullscreenMu.Unlock()
+}
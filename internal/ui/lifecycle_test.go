@@ -0,0 +1,45 @@
+package ui
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestLoopGroup_StopWaits(t *testing.T) {
+	g := newLoopGroup(context.Background(), "test")
+	var stopped atomic.Int32
+	for i := 0; i < 3; i++ {
+		g.Go(func(ctx context.Context) {
+			<-ctx.Done()
+			time.Sleep(10 * time.Millisecond)
+			stopped.Add(1)
+		})
+	}
+	if !g.Stop() {
+		t.Fatal("Stop timed out")
+	}
+	if n := stopped.Load(); n != 3 {
+		t.Errorf("%d loops returned before Stop, want 3", n)
+	}
+	if !(*loopGroup)(nil).Stop() {
+		t.Error("nil group should count as stopped")
+	}
+}
+
+func TestLoopGroup_ParentCancel(t *testing.T) {
+	root, cancel := context.WithCancel(context.Background())
+	g := newLoopGroup(root, "test")
+	done := make(chan struct{})
+	g.Go(func(ctx context.Context) {
+		<-ctx.Done()
+		close(done)
+	})
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("loop did not stop when the root context was cancelled")
+	}
+}
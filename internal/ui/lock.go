@@ -0,0 +1,223 @@
+package ui
+
+import (
+	"camera-dashboard-go/internal/config"
+	"crypto/subtle"
+	"fmt"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+	"image/color"
+	"log"
+	"math"
+	"strings"
+	"time"
+)
+
+// =============================================================================
+// PIN lock
+// =============================================================================
+// With [lock] pin set, the settings panel (and with it restart/exit) and
+// the fullscreen camera controls ask for the PIN first, on an on-screen
+// numeric keypad sized for the 800x480 touchscreen. Cameras, fullscreen,
+// swapping and the quick night mode/brightness buttons on the settings
+// tile stay free, so the driver can still see and dim the picture.
+//
+// A correct PIN unlocks for unlock_sec. After max_attempts wrong PINs
+// the keypad refuses input for lockout_sec. The PIN sits in config.ini
+// in plain text: the lock keeps passengers out of the settings, it is
+// not a security boundary (MQTT commands are not affected).
+// =============================================================================
+
+const pinKeySize = 72 // Keypad button height, a comfortable fingertip target
+
+// pinLock decides whether the locked controls may be used. It has no
+// UI, so the attempt counting can be tested without a display.
+type pinLock struct {
+	pin         string
+	unlockFor   time.Duration
+	maxAttempts int
+	lockout     time.Duration
+
+	failures      int
+	blockedUntil  time.Time
+	unlockedUntil time.Time
+}
+
+// newPINLock returns the lock configured in [lock], or nil if no valid
+// PIN is set.
+func newPINLock(cfg *config.Config) *pinLock {
+	if !config.ValidPIN(cfg.LockPIN) {
+		return nil
+	}
+	return &pinLock{
+		pin:         cfg.LockPIN,
+		unlockFor:   secondsToDuration(cfg.LockUnlockSec),
+		maxAttempts: cfg.LockMaxAttempts,
+		lockout:     secondsToDuration(cfg.LockLockoutSec),
+	}
+}
+
+// unlocked reports whether a PIN was entered within the unlock period.
+func (l *pinLock) unlocked(now time.Time) bool {
+	return now.Before(l.unlockedUntil)
+}
+
+// try checks an entered PIN. On failure wait is how long the keypad is
+// locked out (0 = the next attempt may follow at once).
+func (l *pinLock) try(entry string, now time.Time) (ok bool, wait time.Duration) {
+	if now.Before(l.blockedUntil) {
+		return false, l.blockedUntil.Sub(now)
+	}
+	if subtle.ConstantTimeCompare([]byte(entry), []byte(l.pin)) == 1 {
+		l.failures = 0
+		l.unlockedUntil = now.Add(l.unlockFor)
+		return true, 0
+	}
+	l.failures++
+	if l.failures >= l.maxAttempts {
+		l.failures = 0
+		l.blockedUntil = now.Add(l.lockout)
+		return false, l.lockout
+	}
+	return false, 0
+}
+
+// relock ends the unlock period early.
+func (l *pinLock) relock() {
+	l.unlockedUntil = time.Time{}
+}
+
+// lockScreen is the PIN keypad overlay.
+type lockScreen struct {
+	lock     *pinLock
+	content  *fyne.Container // Overlay root; hidden while closed
+	dots     *widget.Label
+	message  *widget.Label
+	entry    string
+	onUnlock func()
+}
+
+func newLockScreen(lock *pinLock) *lockScreen {
+	s := &lockScreen{lock: lock}
+	title := widget.NewLabelWithStyle("Enter PIN", fyne.TextAlignCenter, fyne.TextStyle{Bold: true})
+	s.dots = widget.NewLabelWithStyle("", fyne.TextAlignCenter, fyne.TextStyle{Monospace: true})
+	s.message = widget.NewLabelWithStyle("", fyne.TextAlignCenter, fyne.TextStyle{})
+
+	keys := container.NewGridWithColumns(3)
+	for _, key := range []string{"1", "2", "3", "4", "5", "6", "7", "8", "9", "Clear", "0", "OK"} {
+		key := key
+		button := widget.NewButton(key, func() { s.press(key) })
+		if key == "OK" {
+			button.Importance = widget.HighImportance
+		}
+		keys.Add(button)
+	}
+	cancel := widget.NewButton("Cancel", s.close)
+
+	body := container.NewBorder(container.NewVBox(title, s.dots, s.message), cancel, nil, nil, keys)
+	bg := canvas.NewRectangle(color.RGBA{30, 30, 35, 255})
+	panel := container.NewGridWrap(fyne.NewSize(300, 4*pinKeySize+170), container.NewStack(bg, container.NewPadded(body)))
+
+	// The shield swallows taps meant for the cameras underneath
+	s.content = container.NewStack(newTapShield(color.RGBA{0, 0, 0, 200}), container.NewCenter(panel))
+	s.content.Hide()
+	return s
+}
+
+// open asks for the PIN and calls onUnlock once it is entered.
+func (s *lockScreen) open(onUnlock func()) {
+	s.onUnlock = onUnlock
+	s.entry = ""
+	s.message.SetText("")
+	s.showEntry()
+	s.content.Show()
+}
+
+func (s *lockScreen) close() {
+	s.entry = ""
+	s.onUnlock = nil
+	s.content.Hide()
+}
+
+// press handles one keypad button.
+func (s *lockScreen) press(key string) {
+	switch key {
+	case "Clear":
+		s.entry = ""
+	case "OK":
+		s.submit()
+		return
+	default:
+		if len(s.entry) < 8 {
+			s.entry += key
+		}
+	}
+	s.message.SetText("")
+	s.showEntry()
+}
+
+// submit checks the entered PIN.
+func (s *lockScreen) submit() {
+	ok, wait := s.lock.try(s.entry, time.Now())
+	s.entry = ""
+	s.showEntry()
+	if ok {
+		log.Println("[Lock] Unlocked")
+		onUnlock := s.onUnlock
+		s.close()
+		if onUnlock != nil {
+			onUnlock()
+		}
+		return
+	}
+	if wait > 0 {
+		log.Printf("[Lock] Too many wrong PINs, locked for %v", wait.Round(time.Second))
+		s.message.SetText(fmt.Sprintf("Try again in %d s", int(math.Ceil(wait.Seconds()))))
+	} else {
+		log.Println("[Lock] Wrong PIN")
+		s.message.SetText("Wrong PIN")
+	}
+}
+
+// showEntry shows one dot per entered digit.
+func (s *lockScreen) showEntry() {
+	s.dots.SetText(strings.Repeat("* ", len(s.entry)))
+}
+
+// withUnlock runs action now if no PIN is set or the lock is open, and
+// otherwise after the PIN has been entered.
+func (a *App) withUnlock(action func()) {
+	if a.pinLock == nil || a.lockScreen == nil || a.pinLock.unlocked(time.Now()) {
+		action()
+		return
+	}
+	a.lockScreen.open(action)
+}
+
+// openSettings opens the settings panel, behind the PIN lock.
+func (a *App) openSettings() {
+	if a.settingsPanel == nil {
+		return
+	}
+	a.withUnlock(a.settingsPanel.open)
+}
+
+// tapShield is a plain rectangle that absorbs taps.
+type tapShield struct {
+	widget.BaseWidget
+	fill color.Color
+}
+
+func newTapShield(fill color.Color) *tapShield {
+	s := &tapShield{fill: fill}
+	s.ExtendBaseWidget(s)
+	return s
+}
+
+func (s *tapShield) Tapped(_ *fyne.PointEvent) {}
+
+func (s *tapShield) CreateRenderer() fyne.WidgetRenderer {
+	return widget.NewSimpleRenderer(canvas.NewRectangle(s.fill))
+}
@@ -0,0 +1,53 @@
+package ui
+
+import (
+	"camera-dashboard-go/internal/config"
+	"testing"
+	"time"
+)
+
+func TestPINLock(t *testing.T) {
+	cfg := config.DefaultConfig()
+	if newPINLock(cfg) != nil {
+		t.Fatal("no PIN should mean no lock")
+	}
+	cfg.LockPIN = "12x4"
+	if newPINLock(cfg) != nil {
+		t.Fatal("an invalid PIN should mean no lock")
+	}
+
+	cfg.LockPIN = "2468"
+	cfg.LockMaxAttempts = 3
+	cfg.LockLockoutSec = 30
+	cfg.LockUnlockSec = 120
+	l := newPINLock(cfg)
+	now := time.Unix(1000, 0)
+	if l.unlocked(now) {
+		t.Fatal("locked at start")
+	}
+
+	// Wrong PINs up to the limit, then a lockout that refuses even the right one
+	for i := 0; i < 2; i++ {
+		if ok, wait := l.try("1111", now); ok || wait != 0 {
+			t.Fatalf("wrong PIN %d = %v, %v", i+1, ok, wait)
+		}
+	}
+	if ok, wait := l.try("1111", now); ok || wait != 30*time.Second {
+		t.Fatalf("third wrong PIN = %v, %v; want a 30s lockout", ok, wait)
+	}
+	if ok, wait := l.try("2468", now.Add(10*time.Second)); ok || wait != 20*time.Second {
+		t.Fatalf("during lockout = %v, %v", ok, wait)
+	}
+
+	later := now.Add(31 * time.Second)
+	if ok, _ := l.try("2468", later); !ok {
+		t.Fatal("right PIN after the lockout should unlock")
+	}
+	if !l.unlocked(later.Add(time.Minute)) || l.unlocked(later.Add(121*time.Second)) {
+		t.Error("unlock should last unlock_sec")
+	}
+	l.relock()
+	if l.unlocked(later) {
+		t.Error("relock should lock at once")
+	}
+}
@@ -0,0 +1,206 @@
+package ui
+
+import (
+	"camera-dashboard-go/internal/camera"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/widget"
+	"image"
+	"log"
+	"sync"
+	"time"
+)
+
+// =============================================================================
+// Mirror-replacement mode
+// =============================================================================
+// With [mirror] enabled a Mirror button sits in the top right corner of
+// the grid. One tap shows the rear camera fullscreen like a digital
+// rear-view mirror, with the left and right cameras as small inserts in
+// the bottom corners (the PiP overlays, pinned to fixed corners). Tapping
+// the picture returns to the grid like any fullscreen view.
+//
+// With auto_dim the rear picture is dimmed to dim_percent of the normal
+// brightness while the scene is dark (mean luma below dark_luma), so
+// headlights behind don't glare at night. The scene is measured once a
+// second; it has to stay on the other side of the threshold (plus
+// mirrorDimHysteresis when brightening) for mirrorDimHold before the
+// picture changes, so street lights passing by don't make it flicker.
+// The inserts keep the grid brightness.
+// =============================================================================
+
+const (
+	mirrorLumaInterval  = time.Second     // How often the scene is measured
+	mirrorDimHysteresis = 15              // Luma above dark_luma needed to undim
+	mirrorDimHold       = 3 * time.Second // A change must hold this long
+	mirrorLumaSamples   = 32              // Sample grid per axis
+)
+
+// mirrorCameras picks the rear camera and the left and right inserts
+// (-1 = none) among cams. An empty match takes the first camera not
+// already picked, in order.
+func mirrorCameras(cams []camera.Camera, rear, left, right string) (int, int, int) {
+	used := make(map[int]bool)
+	pick := func(entry string) int {
+		for i, cam := range cams {
+			if !used[i] && (entry == "" || camera.MatchesCamera(entry, cam)) {
+				used[i] = true
+				return i
+			}
+		}
+		return -1
+	}
+	r := pick(rear)
+	if r < 0 {
+		return -1, -1, -1
+	}
+	return r, pick(left), pick(right)
+}
+
+// meanLuma returns the average brightness (0-255) of img, sampled on a
+// mirrorLumaSamples grid.
+func meanLuma(img image.Image) float64 {
+	b := img.Bounds()
+	if b.Empty() {
+		return 0
+	}
+	var sum float64
+	n := 0
+	for sy := 0; sy < mirrorLumaSamples; sy++ {
+		y := b.Min.Y + (2*sy+1)*b.Dy()/(2*mirrorLumaSamples)
+		for sx := 0; sx < mirrorLumaSamples; sx++ {
+			x := b.Min.X + (2*sx+1)*b.Dx()/(2*mirrorLumaSamples)
+			switch src := img.(type) {
+			case *image.YCbCr:
+				sum += float64(src.Y[src.YOffset(x, y)])
+			case *image.RGBA:
+				i := src.PixOffset(x, y)
+				sum += (299*float64(src.Pix[i]) + 587*float64(src.Pix[i+1]) + 114*float64(src.Pix[i+2])) / 1000
+			default:
+				r, g, bl, _ := img.At(x, y).RGBA()
+				sum += (299*float64(r>>8) + 587*float64(g>>8) + 114*float64(bl>>8)) / 1000
+			}
+			n++
+		}
+	}
+	return sum / float64(n)
+}
+
+// mirrorDimmer decides when the rear picture is dimmed.
+type mirrorDimmer struct {
+	mu       sync.Mutex
+	measured bool // False until the first measurement, which applies at once
+	dimmed   bool
+	pending  time.Time // When the scene first called for the other state; zero = none
+}
+
+// update takes a scene measurement and returns whether to dim.
+func (d *mirrorDimmer) update(luma float64, darkLuma int, now time.Time) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	want := d.dimmed
+	switch {
+	case luma < float64(darkLuma):
+		want = true
+	case luma > float64(darkLuma+mirrorDimHysteresis):
+		want = false
+	}
+	if !d.measured {
+		d.measured, d.dimmed = true, want
+		return d.dimmed
+	}
+	if want == d.dimmed {
+		d.pending = time.Time{}
+		return d.dimmed
+	}
+	if d.pending.IsZero() {
+		d.pending = now
+	}
+	if now.Sub(d.pending) >= mirrorDimHold {
+		d.dimmed, d.pending = want, time.Time{}
+	}
+	return d.dimmed
+}
+
+// reset forgets the scene (entering mirror mode).
+func (d *mirrorDimmer) reset() {
+	d.mu.Lock()
+	d.measured, d.dimmed, d.pending = false, false, time.Time{}
+	d.mu.Unlock()
+}
+
+// newMirrorButton creates the grid's Mirror button overlay.
+func (a *App) newMirrorButton() *fyne.Container {
+	return container.NewVBox(container.NewHBox(layout.NewSpacer(), widget.NewButton("Mirror", a.enterMirrorMode)))
+}
+
+// enterMirrorMode shows the rear camera fullscreen with the side inserts.
+func (a *App) enterMirrorMode() {
+	if a.isFullscreen.Load() || a.pipLayout == nil {
+		return
+	}
+	a.frameLock.RLock()
+	rear, left, right := mirrorCameras(a.cameras, a.cfg.MirrorCamera, a.cfg.MirrorLeft, a.cfg.MirrorRight)
+	a.frameLock.RUnlock()
+	pos := -1
+	if rear >= 0 {
+		pos = a.gridPositionOfCamera(rear)
+	}
+	if pos < 0 {
+		log.Println("[UI] Mirror mode: rear camera not found")
+		return
+	}
+
+	corners := make([]string, len(a.pipImages))
+	if left >= 0 && left < len(corners) {
+		corners[left] = "bottom-left"
+	}
+	if right >= 0 && right < len(corners) {
+		corners[right] = "bottom-right"
+	}
+	a.pipLayout.fixed.Store(&corners)
+	a.mirrorDim.reset()
+	a.mirrorDimmed.Store(false)
+	a.mirrorMode.Store(true)
+	log.Printf("[UI] Mirror mode: rear camera %d, left %d, right %d", rear, left, right)
+
+	a.showFullscreen(pos)
+	if !a.isFullscreen.Load() {
+		a.exitMirrorMode()
+	}
+}
+
+// exitMirrorMode drops the mirror state (leaving fullscreen).
+func (a *App) exitMirrorMode() {
+	if !a.mirrorMode.Swap(false) {
+		return
+	}
+	log.Println("[UI] Mirror mode off")
+	a.mirrorDimmed.Store(false)
+	if a.pipLayout != nil {
+		a.pipLayout.fixed.Store(nil)
+	}
+}
+
+// updateMirrorDim measures the rear camera's frame and updates the dim
+// state (fullscreen loop, once per mirrorLumaInterval).
+func (a *App) updateMirrorDim(frame image.Image) {
+	luma := meanLuma(frame)
+	dimmed := a.mirrorDim.update(luma, a.cfg.MirrorDarkLuma, time.Now())
+	if a.mirrorDimmed.Swap(dimmed) != dimmed {
+		if dimmed {
+			log.Printf("[UI] Mirror dimmed to %d%% (scene luma %.0f)", a.cfg.MirrorDimPercent, luma)
+		} else {
+			log.Printf("[UI] Mirror brightness restored (scene luma %.0f)", luma)
+		}
+	}
+}
+
+// mirrorBrightness scales the fullscreen brightness while dimmed.
+func (a *App) mirrorBrightness(percent int) int {
+	if a.mirrorMode.Load() && a.mirrorDimmed.Load() {
+		return percent * a.cfg.MirrorDimPercent / 100
+	}
+	return percent
+}
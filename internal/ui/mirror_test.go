@@ -0,0 +1,100 @@
+package ui
+
+import (
+	"camera-dashboard-go/internal/camera"
+	"image"
+	"image/color"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestMirrorCameras(t *testing.T) {
+	cams := []camera.Camera{
+		{DeviceID: "video0", DevicePath: "/dev/video0"},
+		{DeviceID: "video2", DevicePath: "/dev/video2"},
+		{DeviceID: "video4", DevicePath: "/dev/video4"},
+	}
+	for _, tc := range []struct {
+		rear, left, right string
+		want              [3]int
+	}{
+		{"", "", "", [3]int{0, 1, 2}},
+		{"video4", "", "", [3]int{2, 0, 1}},
+		{"/dev/video2", "video4", "video0", [3]int{1, 2, 0}},
+		{"video2", "video2", "", [3]int{1, -1, 0}}, // Rear can't be an insert too
+		{"video9", "", "", [3]int{-1, -1, -1}},
+	} {
+		r, l, rt := mirrorCameras(cams, tc.rear, tc.left, tc.right)
+		if got := [3]int{r, l, rt}; got != tc.want {
+			t.Errorf("mirrorCameras(%q, %q, %q) = %v, want %v", tc.rear, tc.left, tc.right, got, tc.want)
+		}
+	}
+	if r, l, rt := mirrorCameras(cams[:1], "", "", ""); r != 0 || l != -1 || rt != -1 {
+		t.Errorf("single camera = %d/%d/%d, want 0/-1/-1", r, l, rt)
+	}
+}
+
+func TestMeanLuma(t *testing.T) {
+	rgba := image.NewRGBA(image.Rect(0, 0, 64, 48))
+	for y := 0; y < 48; y++ {
+		for x := 0; x < 64; x++ {
+			v := uint8(0)
+			if x >= 32 {
+				v = 200
+			}
+			rgba.Set(x, y, color.RGBA{v, v, v, 255})
+		}
+	}
+	if got := meanLuma(rgba); math.Abs(got-100) > 0.5 {
+		t.Errorf("meanLuma(half black, half 200) = %.1f, want 100", got)
+	}
+
+	ycc := image.NewYCbCr(image.Rect(0, 0, 64, 48), image.YCbCrSubsampleRatio420)
+	for i := range ycc.Y {
+		ycc.Y[i] = 30
+	}
+	if got := meanLuma(ycc); got != 30 {
+		t.Errorf("meanLuma(YCbCr Y=30) = %.1f, want 30", got)
+	}
+
+	gray := image.NewGray(image.Rect(0, 0, 8, 8))
+	for i := range gray.Pix {
+		gray.Pix[i] = 80
+	}
+	if got := meanLuma(gray); math.Abs(got-80) > 0.5 {
+		t.Errorf("meanLuma(Gray 80) = %.1f, want 80", got)
+	}
+	if got := meanLuma(image.NewRGBA(image.Rectangle{})); got != 0 {
+		t.Errorf("meanLuma(empty) = %.1f, want 0", got)
+	}
+}
+
+func TestMirrorDimmer(t *testing.T) {
+	var d mirrorDimmer
+	start := time.Unix(0, 0)
+	at := func(s float64) time.Time { return start.Add(time.Duration(s * float64(time.Second))) }
+
+	// The first measurement applies at once
+	if !d.update(20, 50, at(0)) {
+		t.Fatal("dark first measurement should dim at once")
+	}
+	// A headlight sweep shorter than the hold doesn't undim
+	if !d.update(120, 50, at(1)) || !d.update(20, 50, at(2)) || !d.update(120, 50, at(3)) {
+		t.Error("brief bright scene should not undim")
+	}
+	// Inside the hysteresis band nothing changes
+	if !d.update(60, 50, at(10)) || !d.update(60, 50, at(20)) {
+		t.Error("luma within the hysteresis band should keep the picture dimmed")
+	}
+	// Bright for the hold time undims
+	d.update(120, 50, at(30))
+	if d.update(120, 50, at(30+mirrorDimHold.Seconds())) {
+		t.Error("bright scene held for mirrorDimHold should undim")
+	}
+
+	d.reset()
+	if d.update(120, 50, at(40)) {
+		t.Error("bright first measurement after reset should not dim")
+	}
+}
@@ -0,0 +1,209 @@
+package ui
+
+import (
+	"camera-dashboard-go/internal/camera"
+	"camera-dashboard-go/internal/mqtt"
+	"encoding/json"
+	"log"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// =============================================================================
+// MQTT integration
+// =============================================================================
+// Publishes (all under [mqtt] topic_prefix):
+//   <prefix>/health       - camera online/stale/disconnected counts,
+//                           per-slot first-frame latency, stream format
+//                           and corruption rate (retained)
+//   <prefix>/temperature  - CPU temperature, load and current capture FPS
+//   <prefix>/event        - restart and signal lost events
+//   <prefix>/trip_report  - reliability summary when the run ends
+//                           (see tripreport.go)
+// Subscribes:
+//   <prefix>/cmd/nightmode - "on" / "off" / "toggle"
+//   <prefix>/cmd/drivingmode - "on" / "off" / "toggle"
+//   <prefix>/cmd/snapshot  - camera index, or "all" / empty for every camera
+//   <prefix>/cmd/clip      - same, saves the last [clips] seconds (see clips.go)
+//   <prefix>/cmd/profile   - [profile.<name>] to switch to, "none" / empty for
+//                            none (see profiles.go)
+//   <prefix>/cmd/record    - not supported (no recorder in this build)
+// =============================================================================
+
+// startMQTT connects to the broker if [mqtt] is enabled.
+func (a *App) startMQTT() {
+	if !a.cfg.MQTTEnabled {
+		return
+	}
+	if a.cfg.MQTTBroker == "" || a.cfg.MQTTTopicPrefix == "" {
+		log.Println("[MQTT] WARNING: broker or topic_prefix not set, MQTT disabled")
+		return
+	}
+
+	c := mqtt.NewClient(mqtt.Options{
+		Broker:    a.cfg.MQTTBroker,
+		ClientID:  a.cfg.MQTTClientID,
+		Username:  a.cfg.MQTTUsername,
+		Password:  a.cfg.MQTTPassword,
+		KeepAlive: time.Duration(a.cfg.MQTTKeepAliveSec) * time.Second,
+	})
+	c.Subscribe(a.mqttTopic("cmd/+"), a.handleMQTTCommand)
+	c.Start()
+	a.mqttClient.Store(c)
+	log.Printf("[MQTT] Publishing to %s/# on %s", a.cfg.MQTTTopicPrefix, a.cfg.MQTTBroker)
+}
+
+func (a *App) mqttTopic(suffix string) string {
+	return a.cfg.MQTTTopicPrefix + "/" + suffix
+}
+
+// publishJSON marshals v and publishes it; failures are logged at DEBUG
+// since the broker being unreachable is routine in a vehicle.
+func (a *App) publishJSON(suffix string, v interface{}, retain bool) {
+	c := a.mqttClient.Load()
+	if c == nil || !c.IsConnected() {
+		return
+	}
+	payload, err := json.Marshal(v)
+	if err != nil {
+		log.Printf("[MQTT] ERROR: marshal %s: %v", suffix, err)
+		return
+	}
+	if err := c.Publish(a.mqttTopic(suffix), payload, retain); err != nil {
+		log.Printf("[MQTT] DEBUG: publish %s failed: %v", suffix, err)
+	}
+}
+
+// publishHealth publishes the health summary and the current thermal state.
+func (a *App) publishHealth(online, stale, disconnected, totalSlots int, firstFrameMS []int64, streams []camera.StreamHealth) {
+	if a.mqttClient.Load() == nil {
+		return
+	}
+	formats := make([]string, len(streams))
+	corruptPct := make([]float64, len(streams))
+	for i, s := range streams {
+		formats[i] = s.Format
+		if s.Fallback {
+			formats[i] += "(fallback)"
+		}
+		corruptPct[i] = math.Round(s.CorruptRate*1000) / 10
+	}
+	now := a.clock.Now().Unix()
+	health := map[string]interface{}{
+		"online":         online,
+		"stale":          stale,
+		"disconnected":   disconnected,
+		"total_slots":    totalSlots,
+		"first_frame_ms": firstFrameMS, // Per slot, -1 = no frame yet this session
+		"formats":        formats,      // Per slot active input format, "" = not streaming
+		"corrupt_pct":    corruptPct,   // Per slot corrupt frames over the health window
+		"timestamp":      now,
+	}
+	a.diskMetrics(health)
+	a.publishJSON("health", health, true)
+
+	if pc := a.perfController.Load(); pc != nil {
+		a.publishJSON("temperature", map[string]interface{}{
+			"temperature_c": pc.GetTemperature(),
+			"load":          pc.GetLoadAverage(),
+			"capture_fps":   pc.GetCurrentFPS(),
+			"state":         pc.GetState(),
+			"res_tier":      pc.GetResolutionTier(),
+			"timestamp":     now,
+		}, false)
+	}
+}
+
+// publishRestartEvent reports a capture worker restart and counts it in
+// the trip report.
+func (a *App) publishRestartEvent(camIndex int, reason string) {
+	a.trip.restart(camIndex, reason)
+	if a.mqttClient.Load() == nil {
+		return
+	}
+	a.publishJSON("event", map[string]interface{}{
+		"type":      "restart",
+		"camera":    camIndex,
+		"reason":    reason,
+		"timestamp": a.clock.Now().Unix(),
+	}, false)
+}
+
+// handleMQTTCommand dispatches <prefix>/cmd/<name> messages. Runs on the
+// MQTT read goroutine, so slow work is moved off it.
+func (a *App) handleMQTTCommand(topic string, payload []byte) {
+	cmd := topic[strings.LastIndex(topic, "/")+1:]
+	arg := strings.ToLower(strings.TrimSpace(string(payload)))
+	log.Printf("[MQTT] Command %s %q", cmd, arg)
+
+	switch cmd {
+	case "nightmode":
+		switch arg {
+		case "on", "1", "true":
+			a.setNightMode(true)
+		case "off", "0", "false":
+			a.setNightMode(false)
+		case "toggle", "":
+			a.toggleNightMode()
+		default:
+			log.Printf("[MQTT] WARNING: unknown nightmode argument %q", arg)
+		}
+	case "drivingmode":
+		switch arg {
+		case "on", "1", "true":
+			a.setDrivingMode(true)
+		case "off", "0", "false":
+			a.setDrivingMode(false)
+		case "toggle", "":
+			a.setDrivingMode(!a.drivingMode.Load())
+		default:
+			log.Printf("[MQTT] WARNING: unknown drivingmode argument %q", arg)
+		}
+	case "snapshot":
+		go func() {
+			if arg == "" || arg == "all" {
+				a.saveAllSnapshots()
+				return
+			}
+			idx, err := strconv.Atoi(arg)
+			if err != nil {
+				log.Printf("[MQTT] WARNING: invalid snapshot camera %q", arg)
+				return
+			}
+			if _, err := a.saveSnapshot(idx); err != nil {
+				log.Printf("[MQTT] WARNING: snapshot failed: %v", err)
+			}
+		}()
+	case "clip":
+		go func() {
+			if arg == "" || arg == "all" {
+				a.saveAllClips("mqtt")
+				return
+			}
+			idx, err := strconv.Atoi(arg)
+			if err != nil {
+				log.Printf("[MQTT] WARNING: invalid clip camera %q", arg)
+				return
+			}
+			if _, err := a.saveClip(idx, "mqtt"); err != nil {
+				log.Printf("[MQTT] WARNING: clip failed: %v", err)
+			}
+		}()
+	case "profile":
+		name := strings.TrimSpace(string(payload)) // Section names keep their case
+		if arg == noProfile {
+			name = ""
+		}
+		go func() {
+			if _, err := a.selectProfile(name); err != nil {
+				log.Printf("[MQTT] WARNING: profile %q not switched: %v", name, err)
+			}
+		}()
+	case "record":
+		log.Println("[MQTT] WARNING: record command ignored - recording is not available in this build")
+	default:
+		log.Printf("[MQTT] WARNING: unknown command %q", cmd)
+	}
+}
@@ -0,0 +1,114 @@
+package ui
+
+import (
+	"camera-dashboard-go/internal/camera"
+	"fmt"
+	"image/color"
+	"log"
+	"sort"
+	"strings"
+)
+
+// =============================================================================
+// Camera names ([names])
+// =============================================================================
+// [names] gives cameras human-readable names ("Rear", "Left blind
+// spot"). Keys match cameras like [transform]: device path, device ID
+// or USB vendor:product:serial, so a name follows a camera that reports
+// a serial to any port; a serial entry wins over a path entry for the
+// same camera. A named camera shows its name in a small label at the
+// top center of its tile and of the fullscreen view, and status log
+// lines read "Camera 1 (Rear)". mute_overlay hides the labels with the
+// other overlays. Names reload with config.ini.
+// =============================================================================
+
+var nameLabelBg = color.NRGBA{0, 0, 0, 140}
+
+// updateSlotNames resolves [names] entries for the discovered cameras
+// (called whenever a.cameras changes, and on config reload).
+func (a *App) updateSlotNames(cams []camera.Camera) {
+	a.cfgMu.RLock()
+	entries := a.cfg.CameraNames // Reloads replace the map, never modify it
+	a.cfgMu.RUnlock()
+	names := resolveCameraNames(entries, cams)
+	for i, name := range names {
+		if name != "" {
+			log.Printf("[UI] Camera %s: name %q", cams[i].DeviceID, name)
+		}
+	}
+	a.slotNames.Store(&names)
+	a.refreshNameLabels()
+}
+
+// resolveCameraNames returns the configured name of each camera ("" =
+// unnamed). USB identity entries take precedence.
+func resolveCameraNames(entries map[string]string, cams []camera.Camera) []string {
+	keys := make([]string, 0, len(entries))
+	for entry := range entries {
+		keys = append(keys, entry)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		ki, kj := strings.Count(keys[i], ":") == 2, strings.Count(keys[j], ":") == 2
+		if ki != kj {
+			return ki
+		}
+		return keys[i] < keys[j]
+	})
+
+	names := make([]string, len(cams))
+	for _, entry := range keys {
+		for i, cam := range cams {
+			if names[i] == "" && camera.MatchesCamera(entry, cam) {
+				names[i] = entries[entry]
+			}
+		}
+	}
+	return names
+}
+
+// cameraName returns the configured name of camIndex ("" = unnamed).
+func (a *App) cameraName(camIndex int) string {
+	names := a.slotNames.Load()
+	if names == nil || camIndex < 0 || camIndex >= len(*names) {
+		return ""
+	}
+	return (*names)[camIndex]
+}
+
+// cameraTag identifies camIndex in log lines: "1", or "1 (Rear)".
+func (a *App) cameraTag(camIndex int) string {
+	if name := a.cameraName(camIndex); name != "" {
+		return fmt.Sprintf("%d (%s)", camIndex, name)
+	}
+	return fmt.Sprint(camIndex)
+}
+
+// cameraLabel names camIndex for the driver: its name, or "Camera 2".
+func (a *App) cameraLabel(camIndex int) string {
+	if name := a.cameraName(camIndex); name != "" {
+		return name
+	}
+	return fmt.Sprintf("Camera %d", camIndex+1)
+}
+
+// refreshNameLabels puts each camera's name on its tile.
+func (a *App) refreshNameLabels() {
+	for i, w := range a.cameraWidgets {
+		if w != nil {
+			w.SetName(a.cameraName(i))
+		}
+	}
+}
+
+// SetName sets the name label ("" hides it).
+func (t *TappableImage) SetName(name string) {
+	t.mu.Lock()
+	changed := t.name != name
+	t.name = name
+	t.mu.Unlock()
+	if changed {
+		t.nameLabel.Text = name
+		t.nameLabel.Refresh()
+	}
+	t.updateInfoBadge()
+}
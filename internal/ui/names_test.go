@@ -0,0 +1,52 @@
+package ui
+
+import (
+	"camera-dashboard-go/internal/camera"
+	"reflect"
+	"testing"
+)
+
+func TestResolveCameraNames(t *testing.T) {
+	cams := []camera.Camera{
+		{DeviceID: "video0", DevicePath: "/dev/video0", USB: camera.USBDescriptor{VendorID: "046d", ProductID: "0825", Serial: "ABC"}},
+		{DeviceID: "video2", DevicePath: "/dev/video2"},
+		{DeviceID: "video4", DevicePath: "/dev/video4"},
+	}
+	entries := map[string]string{
+		"/dev/video0":   "Port 1",
+		"046D:0825:abc": "Rear", // Serial entries win, case-insensitively
+		"video2":        "Left blind spot",
+		"video9":        "Gone",
+	}
+	want := []string{"Rear", "Left blind spot", ""}
+	if got := resolveCameraNames(entries, cams); !reflect.DeepEqual(got, want) {
+		t.Errorf("resolveCameraNames() = %q, want %q", got, want)
+	}
+	if got := resolveCameraNames(nil, cams); !reflect.DeepEqual(got, []string{"", "", ""}) {
+		t.Errorf("no [names] = %q", got)
+	}
+}
+
+func TestCameraTag(t *testing.T) {
+	a := &App{}
+	if got := a.cameraTag(1); got != "1" {
+		t.Errorf("cameraTag before discovery = %q, want 1", got)
+	}
+	names := []string{"", "Rear"}
+	a.slotNames.Store(&names)
+	if got := a.cameraTag(1); got != "1 (Rear)" {
+		t.Errorf("cameraTag(1) = %q, want 1 (Rear)", got)
+	}
+	if got := a.cameraTag(0); got != "0" {
+		t.Errorf("cameraTag(0) = %q, want 0", got)
+	}
+	if got := a.cameraTag(5); got != "5" {
+		t.Errorf("cameraTag(5) = %q, want 5", got)
+	}
+	if got := a.cameraLabel(1); got != "Rear" {
+		t.Errorf("cameraLabel(1) = %q, want Rear", got)
+	}
+	if got := a.cameraLabel(0); got != "Camera 1" {
+		t.Errorf("cameraLabel(0) = %q, want Camera 1", got)
+	}
+}
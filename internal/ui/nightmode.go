@@ -3,22 +3,38 @@ package ui
 import (
 	"image"
 	"image/color"
+	"math"
 )
 
 // =============================================================================
 // Night Mode Filter
 // =============================================================================
-// Red-tinted, brightness-enhanced rendering for night driving.
-// Matches Python's LUT-based grayscale-to-red conversion (1.6x brightness gain).
-// Algorithm:
+// Tinted, brightness-enhanced rendering for night driving.
+// By default matches Python's LUT-based grayscale-to-red conversion (1.6x
+// brightness gain). Algorithm:
 //   1. Convert pixel to grayscale luminance
-//   2. Apply 1.6x brightness boost (clamped to 255)
-//   3. Map result to red channel only (R = boosted, G = 0, B = 0)
+//   2. Apply the optional gamma curve ([display] night_gamma, 1 = off)
+//   3. Apply the brightness gain ([display] night_gain, clamped to 255)
+//   4. Scale the result by the tint ([display] night_tint: red, amber or
+//      green), so red keeps only the red channel
+// The same palette recolors the UI chrome while night mode is on (see
+// nightThemeOf and nightFyneTheme in theme.go).
 // =============================================================================
 
 // nightModeLUT is a pre-computed lookup table: grayscale value -> boosted value.
-// Equivalent to Python's: np.clip(np.arange(256) * 1.6, 0, 255).astype(np.uint8)
+// With the defaults it is Python's: np.clip(np.arange(256) * 1.6, 0, 255).astype(np.uint8)
 var nightModeLUT [256]uint8
+
+// nightModeTintLUTs map a grayscale value to the tinted R, G and B outputs.
+var nightModeTintLUTs [3][256]uint8
+
+// nightTints are the [display] night_tint colors.
+var nightTints = map[string]color.RGBA{
+	"red":   {255, 0, 0, 255},
+	"amber": {255, 150, 0, 255},
+	"green": {0, 255, 0, 255},
+}
+
 var brightnessLUTs = map[int][256]uint8{}
 
 func buildBrightnessLUT(factor float64) [256]uint8 {
@@ -36,29 +52,66 @@ func buildBrightnessLUT(factor float64) [256]uint8 {
 	return lut
 }
 
-func init() {
+// buildNightModeLUT returns the grayscale -> boosted table for gain and
+// gamma. Above 1, gamma lifts the shadows before the gain.
+func buildNightModeLUT(gain, gamma float64) [256]uint8 {
+	var lut [256]uint8
 	for i := 0; i < 256; i++ {
-		v := float64(i) * 1.6
+		v := float64(i)
+		if gamma > 0 && gamma != 1 {
+			v = 255 * math.Pow(v/255, 1/gamma)
+		}
+		v *= gain
 		if v > 255 {
 			v = 255
 		}
-		nightModeLUT[i] = uint8(v)
+		lut[i] = uint8(v)
+	}
+	return lut
+}
+
+// setNightPalette builds the night mode tables (newApp, from [display]
+// night_gain, night_gamma and night_tint; unknown tints are red). The
+// tables are only written when they change.
+func setNightPalette(gain, gamma float64, tint string) {
+	tc, ok := nightTints[tint]
+	if !ok {
+		tc = nightTints["red"]
+	}
+	lut := buildNightModeLUT(gain, gamma)
+	var tinted [3][256]uint8
+	for i, v := range lut {
+		tinted[0][i] = uint8(uint32(v) * uint32(tc.R) / 255)
+		tinted[1][i] = uint8(uint32(v) * uint32(tc.G) / 255)
+		tinted[2][i] = uint8(uint32(v) * uint32(tc.B) / 255)
+	}
+	if lut != nightModeLUT || tinted != nightModeTintLUTs {
+		nightModeLUT, nightModeTintLUTs = lut, tinted
 	}
+}
+
+func init() {
+	setNightPalette(1.6, 1, "red")
 
 	for _, pct := range []int{15, 60, 80, 100, 150} {
 		brightnessLUTs[pct] = buildBrightnessLUT(float64(pct) / 100.0)
 	}
 }
 
-// applyNightMode converts an image to a red-tinted night vision image.
-// It converts to grayscale, applies 1.6x brightness gain, and maps
-// the result to the red channel only.
+// applyNightMode converts an image to a tinted night vision image.
+// It converts to grayscale, applies the gamma curve and brightness gain,
+// and maps the result through the tint (red channel only by default).
 //
 // If dst is non-nil and has sufficient capacity, it will be reused to avoid
 // allocation. The caller can maintain a per-slot buffer for this purpose.
 //
-// This is optimized for the common case of *image.RGBA and *image.NRGBA
-// but falls back to the generic color.Model interface for other types.
+// Decoded camera frames are *image.YCbCr, whose Y plane already is the
+// BT.601 luminance, so they skip the color conversion entirely. *image.RGBA
+// and *image.NRGBA take a fixed-point path; other types fall back to the
+// generic color.Model interface. The row loops handle 4 pixels per
+// iteration on bounds-check-free slices. Each output pixel is three table
+// lookups, which NEON can't do for 256-entry tables faster than the
+// scalar loop, so there is no assembly version.
 func applyNightMode(src image.Image) *image.RGBA {
 	return applyNightModeReuse(src, nil)
 }
@@ -79,21 +132,90 @@ func applyNightModeReuse(src image.Image, dst *image.RGBA) *image.RGBA {
 		dst = image.NewRGBA(image.Rect(0, 0, w, h))
 	}
 
-	// Fast path for RGBA source
-	if rgba, ok := src.(*image.RGBA); ok {
-		applyNightModeRGBA(rgba, dst)
-		return dst
+	switch s := src.(type) {
+	case *image.YCbCr:
+		for y := 0; y < h; y++ {
+			off := s.YOffset(bounds.Min.X, bounds.Min.Y+y)
+			nightRowGray(dst.Pix[y*dst.Stride:y*dst.Stride+w*4], s.Y[off:off+w])
+		}
+	case *image.Gray:
+		for y := 0; y < h; y++ {
+			off := s.PixOffset(bounds.Min.X, bounds.Min.Y+y)
+			nightRowGray(dst.Pix[y*dst.Stride:y*dst.Stride+w*4], s.Pix[off:off+w])
+		}
+	case *image.RGBA:
+		for y := 0; y < h; y++ {
+			off := s.PixOffset(bounds.Min.X, bounds.Min.Y+y)
+			nightRowRGBA(dst.Pix[y*dst.Stride:y*dst.Stride+w*4], s.Pix[off:off+w*4])
+		}
+	case *image.NRGBA:
+		// Same layout; alpha is ignored as in the RGBA path
+		for y := 0; y < h; y++ {
+			off := s.PixOffset(bounds.Min.X, bounds.Min.Y+y)
+			nightRowRGBA(dst.Pix[y*dst.Stride:y*dst.Stride+w*4], s.Pix[off:off+w*4])
+		}
+	default:
+		applyNightModeGeneric(src, dst)
 	}
+	return dst
+}
 
-	// Fast path for NRGBA source
-	if nrgba, ok := src.(*image.NRGBA); ok {
-		applyNightModeNRGBA(nrgba, dst)
-		return dst
+// BT.601 luminance weights in 16.16 fixed point (0.299, 0.587, 0.114;
+// they sum to 1 << 16, so white stays 255).
+const (
+	lumaR = 19595
+	lumaG = 38470
+	lumaB = 7471
+)
+
+// nightRowGray tints one row of grayscale values into RGBA dst
+// (len(dst) = 4 * len(gray)).
+func nightRowGray(dst, gray []byte) {
+	r, g, b := &nightModeTintLUTs[0], &nightModeTintLUTs[1], &nightModeTintLUTs[2]
+	for len(gray) >= 4 && len(dst) >= 16 {
+		d := dst[:16:16]
+		v0, v1, v2, v3 := gray[0], gray[1], gray[2], gray[3]
+		d[0], d[1], d[2], d[3] = r[v0], g[v0], b[v0], 255
+		d[4], d[5], d[6], d[7] = r[v1], g[v1], b[v1], 255
+		d[8], d[9], d[10], d[11] = r[v2], g[v2], b[v2], 255
+		d[12], d[13], d[14], d[15] = r[v3], g[v3], b[v3], 255
+		gray, dst = gray[4:], dst[16:]
+	}
+	for i, v := range gray {
+		d := dst[i*4 : i*4+4 : i*4+4]
+		d[0], d[1], d[2], d[3] = r[v], g[v], b[v], 255
 	}
+}
 
-	// Generic fallback
-	for y := 0; y < h; y++ {
-		for x := 0; x < w; x++ {
+// nightRowRGBA tints one row of RGBA pixels into dst (same length).
+func nightRowRGBA(dst, src []byte) {
+	r, g, b := &nightModeTintLUTs[0], &nightModeTintLUTs[1], &nightModeTintLUTs[2]
+	for len(src) >= 16 && len(dst) >= 16 {
+		s, d := src[:16:16], dst[:16:16]
+		v0 := uint8((lumaR*uint32(s[0]) + lumaG*uint32(s[1]) + lumaB*uint32(s[2])) >> 16)
+		v1 := uint8((lumaR*uint32(s[4]) + lumaG*uint32(s[5]) + lumaB*uint32(s[6])) >> 16)
+		v2 := uint8((lumaR*uint32(s[8]) + lumaG*uint32(s[9]) + lumaB*uint32(s[10])) >> 16)
+		v3 := uint8((lumaR*uint32(s[12]) + lumaG*uint32(s[13]) + lumaB*uint32(s[14])) >> 16)
+		d[0], d[1], d[2], d[3] = r[v0], g[v0], b[v0], 255
+		d[4], d[5], d[6], d[7] = r[v1], g[v1], b[v1], 255
+		d[8], d[9], d[10], d[11] = r[v2], g[v2], b[v2], 255
+		d[12], d[13], d[14], d[15] = r[v3], g[v3], b[v3], 255
+		src, dst = src[16:], dst[16:]
+	}
+	for len(src) >= 4 && len(dst) >= 4 {
+		s, d := src[:4:4], dst[:4:4]
+		v := uint8((lumaR*uint32(s[0]) + lumaG*uint32(s[1]) + lumaB*uint32(s[2])) >> 16)
+		d[0], d[1], d[2], d[3] = r[v], g[v], b[v], 255
+		src, dst = src[4:], dst[4:]
+	}
+}
+
+// applyNightModeGeneric is the per-pixel path for any image type (and
+// the baseline of the benchmarks).
+func applyNightModeGeneric(src image.Image, dst *image.RGBA) {
+	bounds := src.Bounds()
+	for y := 0; y < bounds.Dy(); y++ {
+		for x := 0; x < bounds.Dx(); x++ {
 			r, g, b, _ := src.At(x+bounds.Min.X, y+bounds.Min.Y).RGBA()
 			// Convert to 8-bit
 			r8 := uint8(r >> 8)
@@ -101,88 +223,28 @@ func applyNightModeReuse(src image.Image, dst *image.RGBA) *image.RGBA {
 			b8 := uint8(b >> 8)
 			// ITU-R BT.601 luminance
 			gray := uint8((299*uint32(r8) + 587*uint32(g8) + 114*uint32(b8)) / 1000)
-			boosted := nightModeLUT[gray]
 			off := (y*dst.Stride + x*4)
-			dst.Pix[off+0] = boosted // R
-			dst.Pix[off+1] = 0       // G
-			dst.Pix[off+2] = 0       // B
-			dst.Pix[off+3] = 255     // A
+			dst.Pix[off+0] = nightModeTintLUTs[0][gray] // R
+			dst.Pix[off+1] = nightModeTintLUTs[1][gray] // G
+			dst.Pix[off+2] = nightModeTintLUTs[2][gray] // B
+			dst.Pix[off+3] = 255                        // A
 		}
 	}
-
-	return dst
 }
 
-// applyNightModeRGBA is the fast path for *image.RGBA sources.
-func applyNightModeRGBA(src *image.RGBA, dst *image.RGBA) {
-	bounds := src.Bounds()
-	w := bounds.Dx()
-	h := bounds.Dy()
-
-	for y := 0; y < h; y++ {
-		srcOff := (y+bounds.Min.Y-src.Rect.Min.Y)*src.Stride + (bounds.Min.X-src.Rect.Min.X)*4
-		dstOff := y * dst.Stride
-
-		for x := 0; x < w; x++ {
-			r := src.Pix[srcOff+0]
-			g := src.Pix[srcOff+1]
-			b := src.Pix[srcOff+2]
-
-			gray := uint8((299*uint32(r) + 587*uint32(g) + 114*uint32(b)) / 1000)
-			boosted := nightModeLUT[gray]
-
-			dst.Pix[dstOff+0] = boosted
-			dst.Pix[dstOff+1] = 0
-			dst.Pix[dstOff+2] = 0
-			dst.Pix[dstOff+3] = 255
-
-			srcOff += 4
-			dstOff += 4
-		}
+// nightModeColor returns the night-mode equivalent of a single color,
+// keeping its alpha. Used for the UI chrome (see theme.go).
+func nightModeColor(c color.Color) color.NRGBA {
+	n := color.NRGBAModel.Convert(c).(color.NRGBA)
+	gray := uint8((299*uint32(n.R) + 587*uint32(n.G) + 114*uint32(n.B)) / 1000)
+	return color.NRGBA{
+		R: nightModeTintLUTs[0][gray],
+		G: nightModeTintLUTs[1][gray],
+		B: nightModeTintLUTs[2][gray],
+		A: n.A,
 	}
 }
 
-// applyNightModeNRGBA is the fast path for *image.NRGBA sources.
-func applyNightModeNRGBA(src *image.NRGBA, dst *image.RGBA) {
-	bounds := src.Bounds()
-	w := bounds.Dx()
-	h := bounds.Dy()
-
-	for y := 0; y < h; y++ {
-		srcOff := (y+bounds.Min.Y-src.Rect.Min.Y)*src.Stride + (bounds.Min.X-src.Rect.Min.X)*4
-		dstOff := y * dst.Stride
-
-		for x := 0; x < w; x++ {
-			r := src.Pix[srcOff+0]
-			g := src.Pix[srcOff+1]
-			b := src.Pix[srcOff+2]
-
-			gray := uint8((299*uint32(r) + 587*uint32(g) + 114*uint32(b)) / 1000)
-			boosted := nightModeLUT[gray]
-
-			dst.Pix[dstOff+0] = boosted
-			dst.Pix[dstOff+1] = 0
-			dst.Pix[dstOff+2] = 0
-			dst.Pix[dstOff+3] = 255
-
-			srcOff += 4
-			dstOff += 4
-		}
-	}
-}
-
-// nightModeColor returns the night-mode equivalent of a single color.
-// Useful for UI elements that should respect night mode.
-func nightModeColor(c color.Color) color.RGBA {
-	r, g, b, _ := c.RGBA()
-	r8 := uint8(r >> 8)
-	g8 := uint8(g >> 8)
-	b8 := uint8(b >> 8)
-	gray := uint8((299*uint32(r8) + 587*uint32(g8) + 114*uint32(b8)) / 1000)
-	boosted := nightModeLUT[gray]
-	return color.RGBA{R: boosted, G: 0, B: 0, A: 255}
-}
-
 func brightnessLUTForPercent(percent int) [256]uint8 {
 	if lut, ok := brightnessLUTs[percent]; ok {
 		return lut
@@ -165,6 +165,33 @@ func TestNightModeColor(t *testing.T) {
 	}
 }
 
+func TestNightPalette(t *testing.T) {
+	defer setNightPalette(1.6, 1, "red")
+
+	// Gamma above 1 lifts the shadows; the gain still clamps
+	setNightPalette(1.0, 2.0, "red")
+	if nightModeLUT[64] != 127 || nightModeLUT[255] != 255 {
+		t.Errorf("gamma 2: LUT[64] = %d, LUT[255] = %d; want 127, 255", nightModeLUT[64], nightModeLUT[255])
+	}
+
+	setNightPalette(2.0, 1, "amber")
+	src := image.NewRGBA(image.Rect(0, 0, 1, 1))
+	src.Set(0, 0, color.RGBA{100, 100, 100, 255})
+	got := applyNightMode(src)
+	if c := got.RGBAAt(0, 0); c.R != 200 || c.G != 117 || c.B != 0 {
+		t.Errorf("amber gain 2 = %v, want {200 117 0}", c)
+	}
+	// Chrome keeps its alpha
+	if c := nightModeColor(color.NRGBA{255, 255, 255, 128}); c != (color.NRGBA{255, 150, 0, 128}) {
+		t.Errorf("nightModeColor(white 50%%) = %v", c)
+	}
+
+	setNightPalette(1.6, 1, "blue")
+	if nightModeTintLUTs[0][100] != 160 || nightModeTintLUTs[1][100] != 0 {
+		t.Error("unknown tint should be red")
+	}
+}
+
 func TestBrightnessLUTPresets(t *testing.T) {
 	// 100% should be identity
 	if brightnessLUTs[100][200] != 200 {
@@ -209,3 +236,112 @@ func TestApplyBrightnessPercentReuse(t *testing.T) {
 			uint8(r>>8), uint8(g>>8), uint8(b>>8))
 	}
 }
+
+func TestApplyNightMode_FastPathsMatchGeneric(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		src  image.Image
+		tol  int // YCbCr uses Y; the generic path re-derives it from RGB
+	}{
+		{"rgba", benchFrame(true), 1},
+		{"nrgba", benchFrameNRGBA(), 1},
+		{"ycbcr", benchFrame(false), 3},
+		{"subimage", benchFrame(true).(*image.RGBA).SubImage(image.Rect(3, 5, 210, 100)), 1},
+		{"ycbcr subimage", benchFrame(false).(*image.YCbCr).SubImage(image.Rect(3, 5, 210, 100)), 3},
+	} {
+		got := applyNightMode(tc.src)
+		want := image.NewRGBA(got.Rect)
+		applyNightModeGeneric(tc.src, want)
+		for i := range got.Pix {
+			if d := int(got.Pix[i]) - int(want.Pix[i]); d > tc.tol || d < -tc.tol {
+				t.Fatalf("%s: byte %d = %d, generic %d", tc.name, i, got.Pix[i], want.Pix[i])
+			}
+		}
+	}
+}
+
+func TestApplyNightMode_YCbCrReadsYPlane(t *testing.T) {
+	// Decoded JPEGs are YCbCr; their night image is the tint of Y alone,
+	// without a per-pixel At() (which allocates for every pixel)
+	src := benchFrame(false).(*image.YCbCr)
+	dst := applyNightModeReuse(src, nil)
+	for y := 0; y < 480; y++ {
+		for x := 0; x < 640; x++ {
+			v := src.Y[src.YOffset(x, y)]
+			p := dst.Pix[dst.PixOffset(x, y):]
+			want := [4]uint8{nightModeTintLUTs[0][v], nightModeTintLUTs[1][v], nightModeTintLUTs[2][v], 255}
+			if got := [4]uint8{p[0], p[1], p[2], p[3]}; got != want {
+				t.Fatalf("pixel (%d,%d) = %v, want %v (Y=%d)", x, y, got, want, v)
+			}
+		}
+	}
+	// The generic path allocates once per pixel (307200 here); -race
+	// instrumentation adds a few of its own, so only that is ruled out
+	if n := testing.AllocsPerRun(5, func() { dst = applyNightModeReuse(src, dst) }); n > 16 {
+		t.Errorf("YCbCr night mode allocates %v times per frame, want none", n)
+	}
+}
+
+// benchFrame returns a 640x480 test picture (the default capture size)
+// as a decoded JPEG would be: YCbCr 4:2:0, or RGBA.
+func benchFrame(rgba bool) image.Image {
+	ycc := image.NewYCbCr(image.Rect(0, 0, 640, 480), image.YCbCrSubsampleRatio420)
+	for i := range ycc.Y {
+		ycc.Y[i] = uint8(32 + i*7%192)
+	}
+	for i := range ycc.Cb {
+		// Moderate chroma: saturated colors clip in RGB
+		ycc.Cb[i], ycc.Cr[i] = uint8(112+i%32), uint8(112+i*3%32)
+	}
+	if !rgba {
+		return ycc
+	}
+	dst := image.NewRGBA(ycc.Rect)
+	for y := 0; y < 480; y++ {
+		for x := 0; x < 640; x++ {
+			dst.Set(x, y, ycc.At(x, y))
+		}
+	}
+	return dst
+}
+
+// benchFrameNRGBA is benchFrame's picture as *image.NRGBA (opaque, so
+// the same bytes as the RGBA one).
+func benchFrameNRGBA() *image.NRGBA {
+	rgba := benchFrame(true).(*image.RGBA)
+	return &image.NRGBA{Pix: rgba.Pix, Stride: rgba.Stride, Rect: rgba.Rect}
+}
+
+func BenchmarkNightMode_YCbCr(b *testing.B) {
+	src, dst := benchFrame(false), (*image.RGBA)(nil)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		dst = applyNightModeReuse(src, dst)
+	}
+}
+
+func BenchmarkNightMode_RGBA(b *testing.B) {
+	src, dst := benchFrame(true), (*image.RGBA)(nil)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		dst = applyNightModeReuse(src, dst)
+	}
+}
+
+func BenchmarkNightMode_NRGBA(b *testing.B) {
+	src, dst := image.Image(benchFrameNRGBA()), (*image.RGBA)(nil)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		dst = applyNightModeReuse(src, dst)
+	}
+}
+
+// BenchmarkNightMode_Generic is the per-pixel path YCbCr frames took
+// before they had their own.
+func BenchmarkNightMode_Generic(b *testing.B) {
+	src, dst := benchFrame(false), image.NewRGBA(image.Rect(0, 0, 640, 480))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		applyNightModeGeneric(src, dst)
+	}
+}
@@ -0,0 +1,70 @@
+package ui
+
+import (
+	"time"
+)
+
+// =============================================================================
+// Off-screen decode throttle
+// =============================================================================
+// With one camera fullscreen (and PiP off) the others are not drawn, yet
+// they would still be decoded at the full rate. Those workers are marked
+// hidden and decode only [display] hidden_camera_fps frames per second;
+// FFmpeg's stream is still read in full, so leaving fullscreen shows a
+// current picture within a frame or two, and clips keep every frame.
+//
+// Hidden cameras get a longer stale timeout (a few hidden frame
+// intervals) and don't take part in soft-sync, which would otherwise
+// hold the fullscreen camera back to their slow frames.
+// =============================================================================
+
+// hiddenStaleFrames is how many hidden frame intervals may pass before an
+// off-screen camera counts as stale.
+const hiddenStaleFrames = 3
+
+// updateCaptureVisibility marks the capture workers of cameras that are
+// not on screen as hidden. Call after fullscreen/PiP changes and when the
+// workers are recreated.
+func (a *App) updateCaptureVisibility() {
+	only := -1
+	if a.cfg.HiddenCameraFPS > 0 && a.isFullscreen.Load() && !a.pipEnabled.Load() && !a.mirrorMode.Load() &&
+		a.fullscreenSlot >= 0 && a.fullscreenSlot < len(a.gridSlots) {
+		only = a.gridSlots[a.fullscreenSlot]
+	}
+	a.onScreenOnly.Store(int32(only))
+
+	manager := a.manager.Load()
+	if manager == nil {
+		return
+	}
+	a.frameLock.RLock()
+	ids := make([]string, len(a.cameras))
+	for i, cam := range a.cameras {
+		ids[i] = cam.DeviceID
+	}
+	a.frameLock.RUnlock()
+
+	for i, id := range ids {
+		if worker := manager.GetWorker(id); worker != nil {
+			worker.SetHidden(a.captureHidden(i))
+		}
+	}
+}
+
+// captureHidden reports whether camIndex is decoded at the off-screen rate.
+func (a *App) captureHidden(camIndex int) bool {
+	only := int(a.onScreenOnly.Load())
+	return only >= 0 && camIndex != only
+}
+
+// staleTimeout returns the stale-frame timeout of camIndex: base, or
+// longer while it decodes at the off-screen rate.
+func (a *App) staleTimeout(camIndex int, base time.Duration) time.Duration {
+	if !a.captureHidden(camIndex) || a.cfg.HiddenCameraFPS <= 0 {
+		return base
+	}
+	if hidden := hiddenStaleFrames * time.Second / time.Duration(a.cfg.HiddenCameraFPS); hidden > base {
+		return hidden
+	}
+	return base
+}
@@ -0,0 +1,34 @@
+package ui
+
+import (
+	"camera-dashboard-go/internal/config"
+	"testing"
+	"time"
+)
+
+func TestUpdateCaptureVisibility_FullscreenHidesOthers(t *testing.T) {
+	a := newApp(config.DefaultConfig())
+	a.updateCaptureVisibility()
+	if a.captureHidden(1) {
+		t.Error("grid view: camera 1 hidden, want all on screen")
+	}
+
+	a.isFullscreen.Store(true)
+	a.fullscreenSlot = 2 // Camera 1
+	a.updateCaptureVisibility()
+	if a.captureHidden(1) || !a.captureHidden(0) {
+		t.Errorf("fullscreen camera 1: hidden(0)=%v hidden(1)=%v, want true, false", a.captureHidden(0), a.captureHidden(1))
+	}
+	if got := a.staleTimeout(0, time.Second); got != 1500*time.Millisecond {
+		t.Errorf("hidden stale timeout = %v, want 1.5s (3 frames at 2 FPS)", got)
+	}
+	if got := a.staleTimeout(1, time.Second); got != time.Second {
+		t.Errorf("visible stale timeout = %v, want 1s", got)
+	}
+
+	a.pipEnabled.Store(true)
+	a.updateCaptureVisibility()
+	if a.captureHidden(0) {
+		t.Error("PiP on: camera 0 hidden, want on screen")
+	}
+}
@@ -0,0 +1,144 @@
+package ui
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"log"
+	"strings"
+	"sync/atomic"
+)
+
+// =============================================================================
+// Picture-in-picture
+// =============================================================================
+// In fullscreen, long-press toggles PiP: the fullscreen camera stays
+// large and the other connected cameras are drawn as small overlays in
+// the corners listed in [display] pip_corners (one camera per corner, in
+// camera order). The overlays show the grid tiles' already-filtered
+// images, so they cost a Refresh each, not another filter pass. The
+// choice sticks for later fullscreen views until toggled off. Mirror mode
+// (mirror.go) uses the same overlays with each insert pinned to a corner.
+// =============================================================================
+
+// pipCorners are the valid [display] pip_corners values.
+var pipCorners = []string{"top-left", "top-right", "bottom-left", "bottom-right"}
+
+const pipMargin = 8 // Gap between an overlay and the screen edges
+
+// pipLayout places each visible object in its corner, scaled to a
+// fraction of the container.
+type pipLayout struct {
+	corners []string                 // One per visible object, in order
+	fixed   atomic.Pointer[[]string] // Corner per object in mirror mode ("" = none); nil = corners in order
+	scale   float32                  // Overlay size as a fraction of the container
+}
+
+func (l *pipLayout) MinSize(objects []fyne.CanvasObject) fyne.Size {
+	return fyne.NewSize(0, 0)
+}
+
+func (l *pipLayout) Layout(objects []fyne.CanvasObject, size fyne.Size) {
+	w, h := size.Width*l.scale, size.Height*l.scale
+	fixed := l.fixed.Load()
+	corner := 0
+	for i, obj := range objects {
+		if !obj.Visible() {
+			continue
+		}
+		var place string
+		if fixed != nil {
+			if i >= len(*fixed) || (*fixed)[i] == "" {
+				continue
+			}
+			place = (*fixed)[i]
+		} else {
+			if corner >= len(l.corners) {
+				break // updatePIPOverlays never shows more than there are corners
+			}
+			place = l.corners[corner]
+			corner++
+		}
+		x, y := float32(pipMargin), float32(pipMargin)
+		if strings.HasSuffix(place, "right") {
+			x = size.Width - w - pipMargin
+		}
+		if strings.HasPrefix(place, "bottom") {
+			y = size.Height - h - pipMargin
+		}
+		obj.Move(fyne.NewPos(x, y))
+		obj.Resize(fyne.NewSize(w, h))
+	}
+}
+
+// newPIPOverlay creates one (hidden) overlay image per camera slot.
+func (a *App) newPIPOverlay() *fyne.Container {
+	corners := a.cfg.PIPCorners
+	if len(corners) == 0 {
+		corners = []string{"top-right", "bottom-right", "bottom-left"}
+	}
+	a.pipImages = make([]*canvas.Image, a.effectiveSlots())
+	objects := make([]fyne.CanvasObject, len(a.pipImages))
+	for i := range a.pipImages {
+		img := canvas.NewImageFromImage(nil)
+		img.FillMode = canvas.ImageFillStretch
+		img.Hide()
+		a.pipImages[i] = img
+		objects[i] = img
+	}
+	a.pipLayout = &pipLayout{corners: corners, scale: float32(a.cfg.PIPSizePercent) / 100}
+	return container.New(a.pipLayout, objects...)
+}
+
+// togglePIP switches picture-in-picture on or off (long-press in fullscreen).
+func (a *App) togglePIP() {
+	if !a.isFullscreen.Load() {
+		return
+	}
+	enabled := !a.pipEnabled.Load()
+	a.pipEnabled.Store(enabled)
+	if enabled {
+		log.Println("[UI] Picture-in-picture enabled")
+	} else {
+		log.Println("[UI] Picture-in-picture disabled")
+	}
+	a.updatePIPOverlays(a.gridSlots[a.fullscreenSlot])
+	a.updateCaptureVisibility()
+}
+
+// updatePIPOverlays shows the connected cameras other than mainCam (only
+// the inserts in mirror mode, nothing when PiP is off) and copies their
+// current grid images.
+func (a *App) updatePIPOverlays(mainCam int) {
+	if a.pipLayout == nil {
+		return
+	}
+	fixed := a.pipLayout.fixed.Load()
+	enabled := (a.pipEnabled.Load() || fixed != nil) && a.isFullscreen.Load()
+	a.frameLock.RLock()
+	camCount := len(a.cameras)
+	a.frameLock.RUnlock()
+
+	shown := 0
+	for i, img := range a.pipImages {
+		show := enabled && i != mainCam && i < camCount && !a.cameraWidgets[i].IsDisconnected()
+		if fixed != nil {
+			show = show && i < len(*fixed) && (*fixed)[i] != ""
+		} else {
+			show = show && shown < len(a.pipLayout.corners)
+		}
+		if !show {
+			if img.Visible() {
+				img.Hide()
+			}
+			continue
+		}
+		shown++
+		img.Image = a.cameraImages[i].Image
+		if !img.Visible() {
+			img.Show()
+		}
+		img.Refresh()
+	}
+	a.pipOverlay.Refresh()
+}
@@ -0,0 +1,22 @@
+package ui
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"testing"
+)
+
+func TestPIPLayout_Corners(t *testing.T) {
+	l := &pipLayout{corners: []string{"top-right", "bottom-left"}, scale: 0.25}
+	hidden := canvas.NewRectangle(nil)
+	hidden.Hide()
+	a, b := canvas.NewRectangle(nil), canvas.NewRectangle(nil)
+	l.Layout([]fyne.CanvasObject{hidden, a, b}, fyne.NewSize(800, 480))
+
+	if a.Position() != fyne.NewPos(800-200-pipMargin, pipMargin) || a.Size() != fyne.NewSize(200, 120) {
+		t.Errorf("top-right overlay at %v size %v", a.Position(), a.Size())
+	}
+	if b.Position() != fyne.NewPos(pipMargin, 480-120-pipMargin) {
+		t.Errorf("bottom-left overlay at %v", b.Position())
+	}
+}
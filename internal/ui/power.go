@@ -0,0 +1,105 @@
+package ui
+
+import (
+	"camera-dashboard-go/internal/camera"
+	"camera-dashboard-go/internal/power"
+	"log"
+	"time"
+)
+
+// =============================================================================
+// Camera power rails ([power])
+// =============================================================================
+// Cameras on GPIO-switched rails (see internal/power) are powered on at
+// the start of camera init, before discovery, and the dashboard waits
+// warmup_sec for them to enumerate.
+//
+// When a camera reaches its restart limit, and its [power] entry matches
+// it, the rail is switched off for cycle_off_sec and back on as a last
+// recovery step, then the worker restarts after the warm-up. The normal
+// back-off continues if that doesn't bring it back.
+//
+// With off_on_exit (the default) all rails are switched off when the
+// dashboard exits, e.g. when a parking/ignition script stops the
+// service. Self-restarts (watchdog, settings) leave them on.
+// =============================================================================
+
+// startPower requests the configured rails (switching them on) and waits
+// for the cameras to boot.
+func (a *App) startPower() {
+	if len(a.cfg.CameraPower) == 0 || a.powerRails.Load() != nil {
+		return
+	}
+	c := power.NewController(a.cfg.CameraPower, power.OpenGPIO(a.cfg.PowerChip))
+	if c.Len() == 0 {
+		log.Printf("[Power] WARNING: no usable rails on %s", a.cfg.PowerChip)
+		return
+	}
+	a.powerRails.Store(c)
+
+	warmup := secondsToDuration(a.cfg.PowerWarmupSec)
+	log.Printf("[Power] %d rails on, waiting %.1fs for cameras to boot", c.Len(), warmup.Seconds())
+	time.Sleep(warmup)
+}
+
+// stopPower releases the rails, switching them off first when off is
+// set. A self-restart releases them (on) so the new process can take
+// them over.
+func (a *App) stopPower(off bool) {
+	c := a.powerRails.Swap(nil)
+	if c == nil {
+		return
+	}
+	if off {
+		if err := c.SetAll(false); err != nil {
+			log.Printf("[Power] WARNING: switching rails off: %v", err)
+		} else {
+			log.Println("[Power] All camera rails off")
+		}
+	}
+	c.Close()
+}
+
+// powerCycleCamera power cycles camIndex's rail and restarts its worker.
+// It returns false when the camera has no rail.
+func (a *App) powerCycleCamera(camIndex int) bool {
+	c := a.powerRails.Load()
+	if c == nil {
+		return false
+	}
+	a.frameLock.RLock()
+	if camIndex < 0 || camIndex >= len(a.cameras) {
+		a.frameLock.RUnlock()
+		return false
+	}
+	cam := a.cameras[camIndex]
+	a.frameLock.RUnlock()
+
+	entry := ""
+	for _, e := range c.Entries() {
+		if camera.MatchesCamera(e, cam) {
+			entry = e
+			break
+		}
+	}
+	if entry == "" {
+		return false
+	}
+
+	log.Printf("[Power] Camera %d (%s): restarts not helping, power cycling", camIndex, cam.DeviceID)
+	a.publishRestartEvent(camIndex, "power_cycle")
+	a.setRecovery(camIndex, recoveryStatus{action: "power cycling camera", holdOff: true})
+	a.cfgMu.RLock()
+	cooldown := secondsToDuration(a.cfg.RestartCooldownSec)
+	a.cfgMu.RUnlock()
+	go func() {
+		if err := c.Cycle(entry, secondsToDuration(a.cfg.PowerCycleOffSec)); err != nil {
+			log.Printf("[Power] WARNING: camera %d: %v", camIndex, err)
+			a.setRecovery(camIndex, recoveryStatus{action: "retrying in", until: a.clk.Now().Add(cooldown), holdOff: true})
+			return
+		}
+		time.Sleep(secondsToDuration(a.cfg.PowerWarmupSec))
+		a.restartWorker(camIndex, cooldown)
+	}()
+	return true
+}
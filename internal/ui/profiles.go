@@ -0,0 +1,59 @@
+package ui
+
+import (
+	"camera-dashboard-go/internal/config"
+	"errors"
+	"fmt"
+	"log"
+)
+
+// =============================================================================
+// Profiles
+// =============================================================================
+// Switching the active [profile.<name>] (settings panel, MQTT
+// cmd/profile) saves [profiles] active and reloads config.ini through
+// the config watcher, so the profile applies exactly like an edit of the
+// file: reloadable settings change at once and the subscribers in
+// WatchConfig update the display. A profile that changes capture
+// settings (e.g. camera.rear.fps for towing) also soft-restarts capture
+// (see restart.go) to apply them; the rest wait for a process restart.
+// =============================================================================
+
+// selectProfile makes name the active profile ("" = none). It returns
+// the changed settings that need the process restarted. It blocks
+// while a soft restart applies capture settings.
+func (a *App) selectProfile(name string) (needRestart []string, err error) {
+	a.cfgMu.RLock()
+	profiles := a.cfg.Profiles
+	a.cfgMu.RUnlock()
+	if name != "" && !containsString(profiles, name) {
+		return nil, fmt.Errorf("no profile %q in %s", name, a.cfg.Path)
+	}
+	if a.configWatcher == nil {
+		return nil, errors.New("config reload is not running")
+	}
+	if a.cfg.ConfigReadOnly {
+		return nil, fmt.Errorf("%s is read-only", a.cfg.Path)
+	}
+	if err := config.SaveINI(a.cfg.Path, []config.INIUpdate{{Section: "profiles", Key: "active", Value: name}}); err != nil {
+		return nil, err
+	}
+	log.Printf("[UI] Profile %s selected", profileLabel(name))
+	needRestart = a.configWatcher.Reload()
+	if config.NeedsSoftRestart(needRestart) {
+		log.Printf("[UI] Profile %s changes capture settings, restarting capture", profileLabel(name))
+		needRestart = a.restart()
+	}
+	return needRestart, nil
+}
+
+// profileLabel names a profile for logs and the settings panel.
+func profileLabel(name string) string {
+	if name == "" {
+		return noProfile
+	}
+	return name
+}
+
+// noProfile is the settings panel's choice for no profile.
+const noProfile = "none"
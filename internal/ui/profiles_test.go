@@ -0,0 +1,43 @@
+package ui
+
+import (
+	"camera-dashboard-go/internal/config"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSelectProfile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.ini")
+	content := "[display]\nbrightness = 100\n\n[profile.night]\ndisplay.brightness = 15\n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := config.Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := &App{cfg: cfg, configWatcher: config.NewWatcher(path, cfg)}
+
+	if _, err := a.selectProfile("towing"); err == nil {
+		t.Error("selectProfile(towing) succeeded without a [profile.towing] section")
+	}
+	if _, err := a.selectProfile("night"); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.ActiveProfile() != "night" || cfg.BrightnessPercent != 15 {
+		t.Errorf("profile %q brightness %d, want night / 15", cfg.ActiveProfile(), cfg.BrightnessPercent)
+	}
+	data, _ := os.ReadFile(path)
+	if !strings.Contains(string(data), "[profiles]\nactive = night\n") {
+		t.Errorf("config.ini lacks the active profile:\n%s", data)
+	}
+
+	if _, err := a.selectProfile(""); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.ActiveProfile() != "" || cfg.BrightnessPercent != 100 {
+		t.Errorf("profile %q brightness %d, want none / 100", cfg.ActiveProfile(), cfg.BrightnessPercent)
+	}
+}
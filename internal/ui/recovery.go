@@ -0,0 +1,147 @@
+package ui
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"time"
+)
+
+// =============================================================================
+// Recovery status on disconnected tiles
+// =============================================================================
+// A "Disconnected" tile also says what the supervisor is doing about it,
+// so a camera that is being retried doesn't look dead:
+//
+//   restarting capture...                  stale restart in progress
+//   retrying in 7s                         restart cooldown
+//   restart limit reached, backing off 60s restart window exhausted
+//   unplugged, waiting for device          hotplug saw the node vanish
+//   reconnecting in 2s / reconnecting...   hotplug debounce / restart
+//
+// The stale detector and hotplug set the status; the stale ticker
+// re-renders countdowns and, when a stale hold-off (cooldown or
+// back-off) runs out, retries the camera. Until then hotplug doesn't
+// restart it behind the policy's back.
+//
+// The Restart button on a disconnected tile (for a re-seated cable)
+// restarts the worker at once, skipping the cooldown and back-off that
+// one time. It still counts as a restart, so if it fails the automatic
+// retries carry on from there.
+// =============================================================================
+
+// recoveryStatus is what the supervisor is doing for one slot.
+type recoveryStatus struct {
+	action  string    // "retrying in", "restarting capture", ...
+	until   time.Time // Countdown target; zero = no countdown
+	holdOff bool      // Stale restart policy owns the slot: hotplug must not restart it
+}
+
+// text renders the status at now, e.g. "retrying in 7s".
+func (r recoveryStatus) text(now time.Time) string {
+	if r.action == "" {
+		return ""
+	}
+	if r.until.IsZero() {
+		return r.action + "..."
+	}
+	secs := int(math.Ceil(r.until.Sub(now).Seconds()))
+	if secs < 1 {
+		secs = 1 // Due now; the next tick replaces the status
+	}
+	return fmt.Sprintf("%s %ds", r.action, secs)
+}
+
+// setRecovery records the recovery status of camIndex and updates its tile.
+func (a *App) setRecovery(camIndex int, r recoveryStatus) {
+	a.frameLock.Lock()
+	if camIndex < 0 || camIndex >= len(a.slotRecovery) {
+		a.frameLock.Unlock()
+		return
+	}
+	a.slotRecovery[camIndex] = r
+	a.frameLock.Unlock()
+	a.renderRecovery(camIndex, a.clk.Now())
+}
+
+// inRestartHoldOff reports whether the stale restart policy owns camIndex
+// at now: a restart is running or its hold-off hasn't run out.
+func (a *App) inRestartHoldOff(camIndex int, now time.Time) bool {
+	a.frameLock.RLock()
+	defer a.frameLock.RUnlock()
+	if camIndex < 0 || camIndex >= len(a.slotRecovery) {
+		return false
+	}
+	r := a.slotRecovery[camIndex]
+	return r.holdOff && (r.until.IsZero() || now.Before(r.until))
+}
+
+// restartHoldOffExpired reports whether camIndex's restart cooldown or
+// back-off has run out, so the stale detector should retry it.
+func (a *App) restartHoldOffExpired(camIndex int, now time.Time) bool {
+	a.frameLock.RLock()
+	defer a.frameLock.RUnlock()
+	if camIndex < 0 || camIndex >= len(a.slotRecovery) {
+		return false
+	}
+	r := a.slotRecovery[camIndex]
+	return r.holdOff && !r.until.IsZero() && !now.Before(r.until)
+}
+
+// renderRecovery shows the recovery text on a disconnected tile.
+func (a *App) renderRecovery(camIndex int, now time.Time) {
+	if camIndex < 0 || camIndex >= len(a.cameraWidgets) || a.cameraWidgets[camIndex] == nil {
+		return
+	}
+	a.frameLock.RLock()
+	var r recoveryStatus
+	if camIndex < len(a.slotRecovery) {
+		r = a.slotRecovery[camIndex]
+	}
+	hasCamera := camIndex < len(a.cameras)
+	a.frameLock.RUnlock()
+
+	text := r.text(now)
+	if text == "" && !hasCamera {
+		text = "no camera"
+	}
+	a.cameraWidgets[camIndex].SetDisconnectDetail(text)
+	a.cameraWidgets[camIndex].SetRestartable(hasCamera)
+}
+
+// renderAllRecovery refreshes the countdowns of every tile.
+func (a *App) renderAllRecovery(now time.Time) {
+	for i := range a.cameraWidgets {
+		a.renderRecovery(i, now)
+	}
+}
+
+// forceRestart restarts camIndex's capture worker now, bypassing the
+// restart cooldown once (Restart button on a disconnected tile).
+func (a *App) forceRestart(camIndex int) {
+	if a.manager.Load() == nil || camIndex < 0 || camIndex >= len(a.restartPolicies) {
+		return
+	}
+	a.frameLock.RLock()
+	hasCamera := camIndex < len(a.cameras)
+	r := a.slotRecovery[camIndex]
+	a.frameLock.RUnlock()
+	if !hasCamera {
+		return
+	}
+	if r.holdOff && r.until.IsZero() {
+		log.Printf("[UI] Camera %d: restart already in progress", camIndex)
+		return
+	}
+
+	a.restartMu.Lock()
+	a.restartPolicies[camIndex].force(a.clk.Now())
+	a.restartMu.Unlock()
+	log.Printf("[UI] Camera %d: manual restart (cooldown bypassed)", camIndex)
+	a.publishRestartEvent(camIndex, "manual")
+	a.setRecovery(camIndex, recoveryStatus{action: "restarting capture", holdOff: true})
+	a.cfgMu.RLock()
+	cooldown := time.Duration(a.cfg.RestartCooldownSec * float64(time.Second))
+	a.cfgMu.RUnlock()
+	go a.restartWorker(camIndex, cooldown)
+}
@@ -0,0 +1,162 @@
+package ui
+
+import (
+	"camera-dashboard-go/internal/camera"
+	"camera-dashboard-go/internal/clock"
+	"camera-dashboard-go/internal/config"
+	"testing"
+	"time"
+)
+
+func TestRecoveryStatusText(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		r    recoveryStatus
+		want string
+	}{
+		{recoveryStatus{}, ""},
+		{recoveryStatus{action: "restarting capture"}, "restarting capture..."},
+		{recoveryStatus{action: "retrying in", until: now.Add(6200 * time.Millisecond)}, "retrying in 7s"},
+		{recoveryStatus{action: "retrying in", until: now.Add(-time.Second)}, "retrying in 1s"},
+	}
+	for _, tt := range tests {
+		if got := tt.r.text(now); got != tt.want {
+			t.Errorf("%+v.text() = %q, want %q", tt.r, got, tt.want)
+		}
+	}
+}
+
+func TestRestartHoldOff(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	a := &App{slotRecovery: []recoveryStatus{
+		{action: "retrying in", until: now.Add(5 * time.Second), holdOff: true},
+		{action: "retrying in", until: now.Add(-time.Second), holdOff: true},
+		{action: "restarting capture", holdOff: true},
+		{action: "reconnecting in", until: now.Add(5 * time.Second)},
+	}}
+	wantHeld := []bool{true, false, true, false}
+	wantExpired := []bool{false, true, false, false}
+	for i := range a.slotRecovery {
+		if got := a.inRestartHoldOff(i, now); got != wantHeld[i] {
+			t.Errorf("slot %d: inRestartHoldOff = %v, want %v", i, got, wantHeld[i])
+		}
+		if got := a.restartHoldOffExpired(i, now); got != wantExpired[i] {
+			t.Errorf("slot %d: restartHoldOffExpired = %v, want %v", i, got, wantExpired[i])
+		}
+	}
+	if a.inRestartHoldOff(9, now) || a.restartHoldOffExpired(9, now) {
+		t.Error("out-of-range slot should not be held off")
+	}
+}
+
+// fakeClockApp returns a one-camera App on a fake clock whose camera
+// just delivered a frame. Its manager has no workers, so restarts fail
+// and leave the "retrying in" hold-off.
+func fakeClockApp() (*App, *clock.Fake) {
+	cfg := config.DefaultConfig()
+	cfg.CameraSlotCount = 1
+	a := newApp(cfg)
+	clk := clock.NewFake(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	a.clk = clk
+	a.manager.Store(camera.NewManagerWithSettings(camera.DefaultSettings(), true))
+	a.cameras = []camera.Camera{{DeviceID: "video0", DevicePath: "/dev/video0"}}
+	a.cameraStatus[0] = true
+	a.lastFrameTime[0] = clk.Now()
+	return a, clk
+}
+
+// waitRecovery waits for the restart goroutine to set camIndex's action.
+func waitRecovery(t *testing.T, a *App, camIndex int, action string) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		a.frameLock.RLock()
+		got := a.slotRecovery[camIndex].action
+		a.frameLock.RUnlock()
+		if got == action {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("recovery action = %q, want %q", got, action)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestCheckStaleFrames_FakeClock(t *testing.T) {
+	a, clk := fakeClockApp()
+	restarts := func() int {
+		a.restartMu.Lock()
+		defer a.restartMu.Unlock()
+		return len(a.restartPolicies[0].events)
+	}
+
+	// Fresh within stale_frame_timeout_sec (1.5s)
+	clk.Advance(time.Second)
+	a.checkStaleFrames()
+	if !a.cameraStatus[0] || restarts() != 0 {
+		t.Fatal("camera marked stale after 1s")
+	}
+
+	// Stale: restarted at once, then once per restart_cooldown_sec (5s)
+	clk.Advance(time.Second)
+	a.checkStaleFrames()
+	if a.cameraStatus[0] || restarts() != 1 {
+		t.Fatalf("after 2s: connected %v, %d restarts; want stale, 1", a.cameraStatus[0], restarts())
+	}
+	waitRecovery(t, a, 0, "retrying in")
+	clk.Advance(4 * time.Second)
+	a.checkStaleFrames()
+	if restarts() != 1 {
+		t.Error("restarted within the cooldown")
+	}
+	for want := 2; want <= 3; want++ {
+		clk.Advance(time.Second)
+		a.checkStaleFrames()
+		if restarts() != want {
+			t.Fatalf("%d restarts, want %d", restarts(), want)
+		}
+		waitRecovery(t, a, 0, "retrying in")
+		clk.Advance(4 * time.Second)
+	}
+
+	// max_restarts_per_window (3 in 30s) reached: back off for 2x the
+	// window from the last restart
+	clk.Advance(time.Second)
+	a.checkStaleFrames()
+	waitRecovery(t, a, 0, "restart limit reached, backing off")
+	clk.Advance(54 * time.Second)
+	a.checkStaleFrames()
+	if restarts() != 3 {
+		t.Errorf("%d restarts during the back-off, want 3", restarts())
+	}
+	clk.Advance(time.Second)
+	a.checkStaleFrames()
+	a.restartMu.Lock()
+	last := a.restartPolicies[0].last
+	a.restartMu.Unlock()
+	if !last.Equal(clk.Now()) {
+		t.Errorf("no restart after the back-off (last %v)", last)
+	}
+}
+
+func TestHandleCameraReconnect_DebounceFakeClock(t *testing.T) {
+	a, clk := fakeClockApp()
+	a.cfg.FailedCameraCooldownS = 2 // Shorter than defaultReconnectDebounce
+	a.cameraStatus[0] = false
+	a.lastDisconnectTime[0] = clk.Now()
+
+	clk.Advance(1900 * time.Millisecond)
+	a.handleCameraReconnect(0)
+	waitRecovery(t, a, 0, "reconnecting in")
+	a.reinitLock.Lock()
+	busy := a.reinitInProgress
+	a.reinitLock.Unlock()
+	if busy {
+		t.Fatal("reconnect started within the debounce")
+	}
+
+	clk.Advance(100 * time.Millisecond)
+	a.handleCameraReconnect(0)
+	waitRecovery(t, a, 0, "reconnecting")
+}
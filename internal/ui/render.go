@@ -0,0 +1,302 @@
+package ui
+
+import (
+	"camera-dashboard-go/internal/config"
+	"camera-dashboard-go/internal/helpers"
+	"context"
+	"image"
+	"image/color"
+	"image/draw"
+	"log"
+	"time"
+)
+
+// =============================================================================
+// Render backends (no Fyne)
+// =============================================================================
+// Fyne needs X11/Wayland and OpenGL, which on Pi Zero 2-class boards
+// costs seconds of startup and GPU memory the capture pipeline would
+// rather have. With [display] backend = framebuffer the dashboard runs
+// the headless pipeline (see headless.go) and a render loop that draws
+// the camera grid itself and hands each composed frame to a
+// RenderBackend, such as the Linux framebuffer (fbdev_linux.go).
+//
+// The render loop draws the cameras only, in the grid Fyne would use
+// without the settings tile ([display] grid_layouts / strip_layout, and
+// the startup layout's camera order; its fullscreen camera fills the
+// screen). There is no text or touch input, so states are shown by the
+// tile: a disconnected camera is an empty tile outlined in the theme's
+// disconnected text color (see theme.go), a
+// frozen picture is grey with an amber outline (see freeze.go) and a
+// lost signal dims the last frame under a red outline (see
+// signallost.go). Night mode, brightness, transforms and dewarping apply
+// as on screen; MQTT commands still work.
+// =============================================================================
+
+// RenderBackend presents composed dashboard frames on a display.
+type RenderBackend interface {
+	Name() string
+	Size() (width, height int)
+	Present(frame *image.RGBA) error
+	Close() error
+}
+
+const renderOutline = 3 // Pixels of the state outline around a tile
+
+var renderLostDim = image.NewUniform(signalLostDim)
+
+// NewRenderApp creates the application drawing through backend instead
+// of a Fyne window. Like headless mode, Start blocks until Cleanup.
+func NewRenderApp(cfg *config.Config, backend RenderBackend) *App {
+	a := NewHeadlessApp(cfg)
+	a.render = backend
+	return a
+}
+
+// startRenderLoop draws the cameras at the UI frame rate until ctx is
+// done, then blanks the display and closes the backend. It runs in
+// refreshLoops, so cleanup waits for the blank frame.
+func (a *App) startRenderLoop(ctx context.Context) {
+	w, h := a.render.Size()
+	frame := image.NewRGBA(image.Rect(0, 0, w, h))
+	order := a.renderOrder()
+	freezeBufs := make([]*image.RGBA, a.effectiveSlots())
+	log.Printf("[Render] Drawing %d cameras to %s (%dx%d)", len(order), a.render.Name(), w, h)
+
+	failing := false
+	for {
+		select {
+		case <-ctx.Done():
+			draw.Draw(frame, frame.Rect, image.Black, image.Point{}, draw.Src)
+			a.render.Present(frame)
+			if err := a.render.Close(); err != nil {
+				log.Printf("[Render] WARNING: close %s: %v", a.render.Name(), err)
+			}
+			return
+		default:
+		}
+
+		a.renderGrid(frame, order, freezeBufs, a.clk.Now())
+		if err := a.render.Present(frame); err != nil {
+			if !failing {
+				log.Printf("[Render] WARNING: present to %s failed: %v", a.render.Name(), err)
+			}
+			failing = true
+		} else if failing {
+			log.Printf("[Render] %s recovered", a.render.Name())
+			failing = false
+		}
+
+		uiFPS := a.currentUIFPS()
+		if uiFPS < 1 {
+			uiFPS = 1
+		}
+		select {
+		case <-ctx.Done():
+		case <-time.After(time.Second / time.Duration(uiFPS)):
+		}
+	}
+}
+
+// renderOrder returns the camera indexes to draw, in grid order: the
+// startup layout's order, then the remaining cameras. A startup layout
+// with a fullscreen camera draws only that one.
+func (a *App) renderOrder() []int {
+	slots := a.effectiveSlots()
+	l, ok := a.resolveStartupLayout()
+	if ok && l.fullscreen >= 0 {
+		return []int{l.fullscreen}
+	}
+	order := make([]int, 0, slots)
+	listed := make(map[int]bool)
+	if ok {
+		for _, camIndex := range l.order {
+			order = append(order, camIndex)
+			listed[camIndex] = true
+		}
+	}
+	for i := 0; i < slots; i++ {
+		if !listed[i] {
+			order = append(order, i)
+		}
+	}
+	return order
+}
+
+// renderGrid draws the cameras in order into frame.
+func (a *App) renderGrid(frame *image.RGBA, order []int, freezeBufs []*image.RGBA, now time.Time) {
+	var rows, cols int
+	if a.cfg.StripLayout {
+		rows, cols = 1, len(order)
+	} else {
+		overrides, _ := helpers.ParseGridLayouts(a.cfg.GridLayouts)
+		rows, cols = helpers.GridFor(len(order), overrides)
+	}
+	draw.Draw(frame, frame.Rect, image.NewUniform(activeTheme().Background), image.Point{}, draw.Src)
+	tile := image.NewUniform(activeTheme().Tile)
+	for i, camIndex := range order {
+		cell := gridCell(frame.Rect, rows, cols, i)
+		draw.Draw(frame, cell, tile, image.Point{}, draw.Src)
+
+		a.frameLock.RLock()
+		var src image.Image
+		connected, hasCamera := false, camIndex < len(a.cameras)
+		if camIndex < len(a.cameraFrames) {
+			src, connected = a.cameraFrames[camIndex], a.cameraStatus[camIndex]
+		}
+		a.frameLock.RUnlock()
+
+		lost := a.cameraSignalLost(camIndex)
+		if src == nil || (!connected && !lost) {
+			if hasCamera {
+				drawOutline(frame, cell, activeTheme().DisconnectedText)
+			}
+			continue
+		}
+
+		picture := a.applyFullscreenFilters(camIndex, src)
+		frozen := !lost && a.slotFrozenFor(camIndex, now) > 0
+		if frozen && camIndex < len(freezeBufs) {
+			freezeBufs[camIndex] = applyFreezeReuse(picture, freezeBufs[camIndex])
+			picture = freezeBufs[camIndex]
+		}
+		drawFit(frame, cell, picture)
+
+		switch {
+		case lost:
+			draw.Draw(frame, cell, renderLostDim, image.Point{}, draw.Over)
+			drawOutline(frame, cell, signalLostColor)
+		case frozen:
+			drawOutline(frame, cell, freezeColor)
+		}
+	}
+}
+
+// gridCell returns the rectangle of cell i in a rows x cols grid over
+// bounds, with a one pixel gap between cells.
+func gridCell(bounds image.Rectangle, rows, cols, i int) image.Rectangle {
+	if rows < 1 || cols < 1 {
+		return image.Rectangle{}
+	}
+	row, col := i/cols, i%cols
+	w, h := bounds.Dx(), bounds.Dy()
+	x0, x1 := bounds.Min.X+col*w/cols, bounds.Min.X+(col+1)*w/cols
+	y0, y1 := bounds.Min.Y+row*h/rows, bounds.Min.Y+(row+1)*h/rows
+	if col < cols-1 {
+		x1--
+	}
+	if row < rows-1 {
+		y1--
+	}
+	return image.Rect(x0, y0, x1, y1)
+}
+
+// drawOutline draws a renderOutline-wide border inside cell.
+func drawOutline(dst *image.RGBA, cell image.Rectangle, c color.Color) {
+	u := image.NewUniform(c)
+	n := renderOutline
+	for _, r := range []image.Rectangle{
+		image.Rect(cell.Min.X, cell.Min.Y, cell.Max.X, cell.Min.Y+n),
+		image.Rect(cell.Min.X, cell.Max.Y-n, cell.Max.X, cell.Max.Y),
+		image.Rect(cell.Min.X, cell.Min.Y, cell.Min.X+n, cell.Max.Y),
+		image.Rect(cell.Max.X-n, cell.Min.Y, cell.Max.X, cell.Max.Y),
+	} {
+		draw.Draw(dst, r.Intersect(cell), u, image.Point{}, draw.Src)
+	}
+}
+
+// fitRect returns the largest rectangle with the aspect ratio of a
+// w x h picture centered in cell.
+func fitRect(cell image.Rectangle, w, h int) image.Rectangle {
+	cw, ch := cell.Dx(), cell.Dy()
+	if w <= 0 || h <= 0 || cw <= 0 || ch <= 0 {
+		return image.Rectangle{}
+	}
+	fw, fh := cw, cw*h/w
+	if fh > ch {
+		fw, fh = ch*w/h, ch
+	}
+	x0 := cell.Min.X + (cw-fw)/2
+	y0 := cell.Min.Y + (ch-fh)/2
+	return image.Rect(x0, y0, x0+fw, y0+fh)
+}
+
+// drawFit scales src into cell (nearest neighbour, aspect kept,
+// letterboxed). RGBA and YCbCr frames take a fast path.
+func drawFit(dst *image.RGBA, cell image.Rectangle, src image.Image) {
+	sb := src.Bounds()
+	fit := fitRect(cell, sb.Dx(), sb.Dy())
+	r := fit.Intersect(dst.Rect)
+	if r.Empty() {
+		return
+	}
+	xs := make([]int, r.Dx())
+	for x := range xs {
+		xs[x] = sb.Min.X + (r.Min.X+x-fit.Min.X)*sb.Dx()/fit.Dx()
+	}
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		sy := sb.Min.Y + (y-fit.Min.Y)*sb.Dy()/fit.Dy()
+		off := dst.PixOffset(r.Min.X, y)
+		for _, sx := range xs {
+			var cr, cg, cb uint8
+			switch s := src.(type) {
+			case *image.RGBA:
+				p := s.PixOffset(sx, sy)
+				cr, cg, cb = s.Pix[p], s.Pix[p+1], s.Pix[p+2]
+			case *image.YCbCr:
+				c := s.COffset(sx, sy)
+				cr, cg, cb = color.YCbCrToRGB(s.Y[s.YOffset(sx, sy)], s.Cb[c], s.Cr[c])
+			default:
+				r, g, b, _ := src.At(sx, sy).RGBA()
+				cr, cg, cb = uint8(r>>8), uint8(g>>8), uint8(b>>8)
+			}
+			dst.Pix[off], dst.Pix[off+1], dst.Pix[off+2], dst.Pix[off+3] = cr, cg, cb, 255
+			off += 4
+		}
+	}
+}
+
+// fbBitfield is the position of one color channel in a framebuffer pixel.
+type fbBitfield struct {
+	Offset, Length uint32
+}
+
+// fbFormat is a framebuffer's pixel layout (little-endian pixels, as on
+// the Pi): 16 (RGB565), 24 or 32 bits per pixel. Alpha bits, if any,
+// are set opaque.
+type fbFormat struct {
+	BytesPerPixel            int
+	Stride                   int // Bytes per line
+	Red, Green, Blue, Transp fbBitfield
+}
+
+// pack converts img into the framebuffer layout in dst (Stride * height
+// bytes). Pixels outside img are left alone.
+func (f fbFormat) pack(dst []byte, img *image.RGBA) {
+	channel := func(v uint8, b fbBitfield) uint32 {
+		if b.Length == 0 {
+			return 0
+		}
+		if b.Length < 8 {
+			return uint32(v>>(8-b.Length)) << b.Offset
+		}
+		return uint32(v) << b.Offset
+	}
+	opaque := (uint32(1)<<f.Transp.Length - 1) << f.Transp.Offset
+	b := img.Rect
+	for y := 0; y < b.Dy(); y++ {
+		src := img.PixOffset(b.Min.X, b.Min.Y+y)
+		off := y * f.Stride
+		if off+b.Dx()*f.BytesPerPixel > len(dst) {
+			return
+		}
+		for x := 0; x < b.Dx(); x++ {
+			p := opaque | channel(img.Pix[src], f.Red) | channel(img.Pix[src+1], f.Green) | channel(img.Pix[src+2], f.Blue)
+			for i := 0; i < f.BytesPerPixel; i++ {
+				dst[off+i] = byte(p >> (8 * i))
+			}
+			src += 4
+			off += f.BytesPerPixel
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package ui
+
+import (
+	"camera-dashboard-go/internal/camera"
+	"camera-dashboard-go/internal/config"
+	"image"
+	"image/color"
+	"testing"
+	"time"
+)
+
+func TestGridCellAndFit(t *testing.T) {
+	screen := image.Rect(0, 0, 800, 480)
+	if got := gridCell(screen, 2, 2, 3); got != image.Rect(400, 240, 800, 480) {
+		t.Errorf("cell 3 = %v", got)
+	}
+	if got := gridCell(screen, 2, 2, 0); got != image.Rect(0, 0, 399, 239) {
+		t.Errorf("cell 0 = %v, want a gap on the right and bottom", got)
+	}
+	// 4:3 picture in a wide cell is pillarboxed
+	if got := fitRect(image.Rect(0, 0, 800, 300), 640, 480); got != image.Rect(200, 0, 600, 300) {
+		t.Errorf("fit = %v", got)
+	}
+}
+
+func TestDrawFit(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 2, 2))
+	src.SetRGBA(0, 0, color.RGBA{255, 0, 0, 255})
+	src.SetRGBA(1, 1, color.RGBA{0, 0, 255, 255})
+	dst := image.NewRGBA(image.Rect(0, 0, 8, 4))
+	drawFit(dst, dst.Rect, src)
+	if got := dst.RGBAAt(2, 0); got != (color.RGBA{255, 0, 0, 255}) {
+		t.Errorf("top left = %v, want red", got)
+	}
+	if got := dst.RGBAAt(5, 3); got != (color.RGBA{0, 0, 255, 255}) {
+		t.Errorf("bottom right = %v, want blue", got)
+	}
+	if got := dst.RGBAAt(0, 0); got.A != 0 {
+		t.Errorf("letterbox = %v, want untouched", got)
+	}
+}
+
+func TestFBFormatPack(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 1, 1))
+	img.SetRGBA(0, 0, color.RGBA{0xff, 0x80, 0x10, 0xff})
+
+	rgb565 := fbFormat{BytesPerPixel: 2, Stride: 2, Red: fbBitfield{11, 5}, Green: fbBitfield{5, 6}, Blue: fbBitfield{0, 5}}
+	buf := make([]byte, 2)
+	rgb565.pack(buf, img)
+	if p := uint16(buf[0]) | uint16(buf[1])<<8; p != 0xfc02 {
+		t.Errorf("RGB565 = %#04x, want 0xfc02", p)
+	}
+
+	argb := fbFormat{BytesPerPixel: 4, Stride: 4, Red: fbBitfield{16, 8}, Green: fbBitfield{8, 8}, Blue: fbBitfield{0, 8}, Transp: fbBitfield{24, 8}}
+	buf = make([]byte, 4)
+	argb.pack(buf, img)
+	if buf[0] != 0x10 || buf[1] != 0x80 || buf[2] != 0xff || buf[3] != 0xff {
+		t.Errorf("ARGB8888 = % x, want 10 80 ff ff", buf)
+	}
+}
+
+func TestRenderGrid(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Layouts = map[string]string{"default": "order=2"}
+	a := newApp(cfg)
+	if got := a.renderOrder(); len(got) != 3 || got[0] != 1 || got[1] != 0 || got[2] != 2 {
+		t.Fatalf("order = %v, want [1 0 2]", got)
+	}
+
+	green := image.NewRGBA(image.Rect(0, 0, 4, 3))
+	for i := 0; i < len(green.Pix); i += 4 {
+		copy(green.Pix[i:], []byte{0, 200, 0, 255})
+	}
+	a.cameras = []camera.Camera{{DeviceID: "a"}, {DeviceID: "b"}}
+	a.cameraFrames[1], a.cameraStatus[1] = green, true
+
+	frame := image.NewRGBA(image.Rect(0, 0, 300, 200))
+	a.renderGrid(frame, []int{1, 0, 2}, make([]*image.RGBA, 3), time.Now())
+	// 3 cameras on a 1x3 grid: camera 1 first, camera 0 disconnected
+	if got := frame.RGBAAt(50, 100); got != (color.RGBA{0, 200, 0, 255}) {
+		t.Errorf("camera 1 = %v, want its picture", got)
+	}
+	if got := frame.RGBAAt(101, 100); got != currentTheme.DisconnectedText {
+		t.Errorf("camera 0 edge = %v, want the disconnected outline", got)
+	}
+	if got := frame.RGBAAt(250, 100); got != (color.RGBA{25, 25, 25, 255}) {
+		t.Errorf("empty slot = %v, want the tile background", got)
+	}
+}
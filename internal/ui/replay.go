@@ -0,0 +1,192 @@
+package ui
+
+import (
+	"bytes"
+	"camera-dashboard-go/internal/camera"
+	"fmt"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"log"
+	"sort"
+	"sync/atomic"
+	"time"
+)
+
+// =============================================================================
+// Instant replay
+// =============================================================================
+// With [replay] seconds > 0 the Replay button in fullscreen freezes the
+// picture on the newest frame and shows a scrub bar: drag it left to look
+// up to N seconds back ("did I just clip that curb?"), Live (or leaving
+// fullscreen) returns to the live picture.
+//
+// The history is the capture worker's JPEG ring (camera.ClipBuffer), the
+// same one saved clips use, so replay needs no recording and no second
+// copy: the ring keeps the longer of [clips] and [replay] seconds. The
+// frames are snapshotted when Replay opens and decoded one at a time as
+// the bar moves; the fullscreen loop shows the decoded frame with the
+// usual filters instead of the live one.
+// =============================================================================
+
+// replayWindow is how far back Replay can go (0 = off).
+func (a *App) replayWindow() time.Duration {
+	return time.Duration(a.cfg.ReplaySeconds) * time.Second
+}
+
+// historyWindow is the JPEG history each capture worker keeps, for clips
+// and replay (0 = none).
+func (a *App) historyWindow() time.Duration {
+	if clip, replay := a.clipWindow(), a.replayWindow(); clip > replay {
+		return clip
+	}
+	return a.replayWindow()
+}
+
+// recentFrames returns the frames within window of the newest one.
+func recentFrames(frames []camera.ClipFrame, window time.Duration) []camera.ClipFrame {
+	if len(frames) == 0 || window <= 0 {
+		return frames
+	}
+	start := frames[len(frames)-1].At.Add(-window)
+	i := sort.Search(len(frames), func(i int) bool { return !frames[i].At.Before(start) })
+	return frames[i:]
+}
+
+// frameAt returns the index of the newest frame captured at or before t,
+// or the oldest frame when all are newer.
+func frameAt(frames []camera.ClipFrame, t time.Time) int {
+	i := sort.Search(len(frames), func(i int) bool { return frames[i].At.After(t) })
+	if i > 0 {
+		i--
+	}
+	return i
+}
+
+// replayFrame is the decoded frame the fullscreen loop shows while
+// replaying.
+type replayFrame struct {
+	img image.Image
+}
+
+// replayBar is the fullscreen scrub bar.
+type replayBar struct {
+	app      *App
+	content  *fyne.Container // Overlay root; hidden while closed
+	slider   *widget.Slider
+	label    *widget.Label
+	frames   []camera.ClipFrame // Snapshot taken when the bar opened
+	camIndex int
+	shown    int                         // Index of the frame on screen
+	current  atomic.Pointer[replayFrame] // nil = live
+}
+
+func newReplayBar(a *App) *replayBar {
+	b := &replayBar{app: a, shown: -1}
+	b.label = widget.NewLabel("")
+	b.slider = widget.NewSlider(0, 1)
+	b.slider.Step = 0.1
+	b.slider.OnChanged = b.seek
+
+	body := container.NewBorder(nil, nil, b.label, widget.NewButton("Live", b.close), b.slider)
+	bg := canvas.NewRectangle(color.RGBA{30, 30, 35, 220})
+	// Bottom strip; the picture above stays tappable (tap leaves fullscreen)
+	b.content = container.NewBorder(nil, container.NewStack(bg, container.NewPadded(body)), nil, nil)
+	b.content.Hide()
+	return b
+}
+
+// open freezes the fullscreen camera on its newest buffered frame.
+func (b *replayBar) open(camIndex int) {
+	a := b.app
+	a.frameLock.RLock()
+	if camIndex < 0 || camIndex >= len(a.cameras) {
+		a.frameLock.RUnlock()
+		return
+	}
+	cam := a.cameras[camIndex]
+	a.frameLock.RUnlock()
+
+	var worker *camera.CaptureWorker
+	if manager := a.manager.Load(); manager != nil {
+		worker = manager.GetWorker(cam.DeviceID)
+	}
+	if worker == nil {
+		log.Printf("[Replay] Camera %d (%s) has no capture worker", camIndex, cam.DeviceID)
+		return
+	}
+	frames := recentFrames(worker.ClipFrames(), a.replayWindow())
+	if len(frames) == 0 {
+		log.Printf("[Replay] Camera %d (%s) has no buffered frames yet", camIndex, cam.DeviceID)
+		return
+	}
+	span := frames[len(frames)-1].At.Sub(frames[0].At)
+	log.Printf("[Replay] Camera %d (%s): %d frames over %.1fs", camIndex, cam.DeviceID, len(frames), span.Seconds())
+
+	b.frames, b.camIndex, b.shown = frames, camIndex, -1
+	b.slider.Min = -span.Seconds()
+	b.slider.Max = 0
+	b.slider.Value = 0
+	b.slider.Refresh()
+	b.seek(0)
+	b.content.Show()
+}
+
+// seek shows the frame offset seconds before the newest buffered one.
+func (b *replayBar) seek(offset float64) {
+	if len(b.frames) == 0 {
+		return
+	}
+	newest := b.frames[len(b.frames)-1].At
+	i := frameAt(b.frames, newest.Add(time.Duration(offset*float64(time.Second))))
+	if i == b.shown {
+		return
+	}
+	img, err := jpeg.Decode(bytes.NewReader(b.frames[i].JPEG))
+	if err != nil {
+		return // Keep the previous frame
+	}
+	b.shown = i
+	b.current.Store(&replayFrame{img: img})
+	b.label.SetText(fmt.Sprintf("-%.1fs  %s", newest.Sub(b.frames[i].At).Seconds(), b.app.clock.At(b.frames[i].At).Format("15:04:05")))
+}
+
+// active reports whether the bar is open.
+func (b *replayBar) active() bool {
+	return b.current.Load() != nil
+}
+
+// close returns to the live picture.
+func (b *replayBar) close() {
+	b.current.Store(nil)
+	b.frames = nil
+	b.shown = -1
+	b.content.Hide()
+}
+
+// toggleReplay is the fullscreen Replay button.
+func (a *App) toggleReplay() {
+	if !a.isFullscreen.Load() || a.replayBar == nil {
+		return
+	}
+	if a.replayBar.active() {
+		a.replayBar.close()
+		return
+	}
+	a.replayBar.open(a.gridSlots[a.fullscreenSlot])
+}
+
+// replayImage returns the frame to show instead of the live one, if any.
+func (a *App) replayImage() image.Image {
+	if a.replayBar == nil {
+		return nil
+	}
+	if f := a.replayBar.current.Load(); f != nil {
+		return f.img
+	}
+	return nil
+}
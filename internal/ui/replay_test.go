@@ -0,0 +1,49 @@
+package ui
+
+import (
+	"camera-dashboard-go/internal/camera"
+	"camera-dashboard-go/internal/config"
+	"testing"
+	"time"
+)
+
+func TestRecentFramesAndFrameAt(t *testing.T) {
+	start := time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+	var frames []camera.ClipFrame
+	for i := 0; i < 10; i++ {
+		frames = append(frames, camera.ClipFrame{JPEG: []byte{byte(i)}, At: start.Add(time.Duration(i) * time.Second)})
+	}
+
+	if got := recentFrames(frames, 3*time.Second); len(got) != 4 || got[0].JPEG[0] != 6 {
+		t.Errorf("recentFrames(3s) = %d frames from %v, want 4 from frame 6", len(got), got[0].JPEG)
+	}
+	if got := recentFrames(frames, 0); len(got) != len(frames) {
+		t.Errorf("recentFrames(0) = %d frames, want all %d", len(got), len(frames))
+	}
+
+	for _, tc := range []struct {
+		at   time.Duration
+		want int
+	}{{-time.Second, 0}, {0, 0}, {2500 * time.Millisecond, 2}, {3 * time.Second, 3}, {time.Minute, 9}} {
+		if got := frameAt(frames, start.Add(tc.at)); got != tc.want {
+			t.Errorf("frameAt(+%v) = %d, want %d", tc.at, got, tc.want)
+		}
+	}
+}
+
+func TestHistoryWindow(t *testing.T) {
+	cfg := config.DefaultConfig()
+	a := &App{cfg: cfg}
+	cfg.ClipSeconds, cfg.ClipsDir, cfg.ReplaySeconds = 30, "/tmp/clips", 10
+	if got := a.historyWindow(); got != 30*time.Second {
+		t.Errorf("historyWindow() = %v, want the clip window 30s", got)
+	}
+	cfg.ClipSeconds = 0
+	if got := a.historyWindow(); got != 10*time.Second {
+		t.Errorf("historyWindow() = %v, want the replay window 10s", got)
+	}
+	cfg.ReplaySeconds = 0
+	if got := a.historyWindow(); got != 0 {
+		t.Errorf("historyWindow() = %v, want 0 with clips and replay off", got)
+	}
+}
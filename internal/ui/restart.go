@@ -0,0 +1,160 @@
+package ui
+
+import (
+	"log"
+	"os"
+	"os/exec"
+	"time"
+)
+
+// =============================================================================
+// Soft restart
+// =============================================================================
+// Restart (settings panel) restarts the capture side in place rather
+// than launching a new process, which under systemd left the unit
+// tracking a process that exits while an unsupervised copy kept
+// running. It stops the soak test, the adaptive FPS controller, the
+// camera manager (FFmpeg), the hotplug, stale and health loops and the
+// MQTT client, re-reads config.ini (config.Watcher.ReloadForRestart
+// applies the settings they read at startup) and starts them again, in
+// the same window. Camera power rails stay on, and the refresh loop,
+// watchdog and other services keep running; settings only they read
+// are logged as needing the process restarted (systemctl restart).
+//
+// relaunch, exec-ing a new process, is only left for a hung refresh loop
+// without systemd supervision (see onWatchdogFatal), which an
+// in-process restart can't recover.
+// =============================================================================
+
+// restart stops the capture pipeline, reloads config.ini and starts the
+// pipeline again. It blocks until the new pipeline is starting; a
+// restart already in progress makes it return at once. It returns the
+// changed settings that need the process restarted.
+func (a *App) restart() (needRestart []string) {
+	if !a.restarting.CompareAndSwap(false, true) {
+		log.Println("[UI] Restart: already in progress")
+		return nil
+	}
+	defer a.restarting.Store(false)
+
+	log.Println("[UI] Restart: stopping capture...")
+	start := time.Now()
+	a.stopCapture()
+
+	if a.configWatcher != nil {
+		needRestart = a.configWatcher.ReloadForRestart()
+	} else {
+		log.Println("[UI] Restart: config reload not running, keeping current settings")
+	}
+
+	a.startCapture()
+	a.restarts.Add(1)
+	log.Printf("[UI] Restart: capture restarted in %v", time.Since(start).Round(time.Millisecond))
+	return needRestart
+}
+
+// stopCapture stops what startCapture starts and resets the camera
+// slots.
+func (a *App) stopCapture() {
+	a.stopCaptureLoops()
+	if f := a.faults.Swap(nil); f != nil {
+		f.Stop()
+	}
+	if pc := a.perfController.Swap(nil); pc != nil {
+		pc.Stop()
+	}
+	if m := a.manager.Swap(nil); m != nil {
+		m.Stop()
+		log.Println("[UI] Restart: stopped camera manager")
+	}
+	if c := a.mqttClient.Swap(nil); c != nil {
+		c.Stop()
+	}
+
+	// The new manager's buffers count frames from zero again
+	a.frameLock.Lock()
+	a.cameras = nil
+	for i := range a.lastFrameRead {
+		a.lastFrameRead[i] = 0
+		a.lastFrameTime[i] = time.Time{}
+		a.shownFrameAt[i] = time.Time{}
+		a.slotRecovery[i] = recoveryStatus{}
+	}
+	a.frameLock.Unlock()
+	a.restartMu.Lock()
+	for i := range a.restartPolicies {
+		a.restartPolicies[i] = restartPolicy{}
+	}
+	a.restartMu.Unlock()
+	for i := 0; i < a.effectiveSlots(); i++ {
+		a.updateCameraStatus(i, false)
+	}
+}
+
+// startCapture starts the capture pipeline and the loops that supervise
+// it with the current config.
+func (a *App) startCapture() {
+	loops := newLoopGroup(a.ctx, "capture")
+	a.captureMu.Lock()
+	a.captureLoops = loops
+	a.captureMu.Unlock()
+
+	a.startSoakTest()
+	go a.initializeCamerasAsync()
+	loops.Go(a.startHotplugDetection)
+	loops.Go(a.startStaleFrameDetection)
+	loops.Go(a.startHealthLogging)
+	a.startMQTT()
+}
+
+// stopCaptureLoops stops the hot-plug, stale and health loops and waits
+// for them, so none acts on the camera manager being stopped.
+func (a *App) stopCaptureLoops() {
+	a.captureMu.Lock()
+	loops := a.captureLoops
+	a.captureLoops = nil
+	a.captureMu.Unlock()
+	loops.Stop()
+}
+
+// relaunch replaces the process with a new instance started with the
+// same flags, for when the dashboard can't restart in place.
+func (a *App) relaunch() {
+	log.Println("[UI] Relaunch: stopping all processes...")
+
+	if a.watchdog != nil {
+		a.watchdog.Stop()
+	}
+	a.finishTrip("restart")
+	a.stopCapture()
+	a.stopPower(false)
+	a.flushStaged()
+	a.stopHealthServer()
+	a.stopDebugServer()
+
+	// Stop the remaining background goroutines
+	a.cleanupOnce.Do(func() {
+		a.cancel()
+		a.stopLoops()
+	})
+
+	executable, err := os.Executable()
+	if err != nil {
+		log.Printf("[UI] Relaunch: failed to get executable path: %v", err)
+		return
+	}
+
+	// Launch new instance with the same flags (-config, -headless, ...)
+	cmd := exec.Command(executable, os.Args[1:]...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Env = os.Environ()
+
+	if err := cmd.Start(); err != nil {
+		log.Printf("[UI] Relaunch: failed to start new instance: %v", err)
+		return
+	}
+
+	log.Println("[UI] Relaunch: new instance started, exiting current...")
+	a.quit()
+}
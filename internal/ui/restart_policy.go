@@ -0,0 +1,110 @@
+package ui
+
+import "time"
+
+// =============================================================================
+// Bounded restart policy (per camera)
+// =============================================================================
+// Matches Python's _restart_capture_if_stale() policy:
+//   - Cooldown between restarts for one camera
+//   - At most maxRestarts within a sliding window
+//   - Extended cooldown (2x window) once the limit is reached
+//
+// The caller passes "now" explicitly. Timestamps normally come from
+// time.Now() and compare on the monotonic clock, but the policy also
+// guards against wall-clock steps: if now is earlier than the last
+// recorded restart (clock stepped backwards), history is shifted so the
+// last restart is "now" rather than blocking until the clock catches up.
+// =============================================================================
+
+// restartDecision is the outcome of restartPolicy.check.
+type restartDecision int
+
+const (
+	restartAllowed   restartDecision = iota // Restart now (already recorded)
+	restartRecovered                        // Allowed after the extended cooldown cleared the limit
+	restartCooldown                         // Too soon after the last restart
+	restartLimited                          // Window limit reached, in extended cooldown
+)
+
+// restartPolicy holds restart history for one camera.
+type restartPolicy struct {
+	events   []time.Time // Restart timestamps within ~2x window
+	last     time.Time   // Last restart
+	limitHit bool        // Limit reached and not yet cleared
+}
+
+// restartLimits are the config-derived policy parameters.
+type restartLimits struct {
+	cooldown    time.Duration
+	window      time.Duration
+	maxRestarts int
+}
+
+// check decides whether a restart may happen at now and, if so,
+// records it. recent is the number of restarts counted in the window
+// (for logging), and firstLimitHit is true only on the tick the limit
+// was first reached so callers can log once.
+func (p *restartPolicy) check(now time.Time, lim restartLimits) (decision restartDecision, recent int, firstLimitHit bool) {
+	p.rebase(now)
+	extendedCooldown := lim.window * 2
+
+	if !p.last.IsZero() && now.Sub(p.last) < lim.cooldown {
+		return restartCooldown, 0, false
+	}
+
+	for _, t := range p.events {
+		if now.Sub(t) <= lim.window {
+			recent++
+		}
+	}
+
+	decision = restartAllowed
+	if recent >= lim.maxRestarts {
+		if !p.last.IsZero() && now.Sub(p.last) < extendedCooldown {
+			first := !p.limitHit
+			p.limitHit = true
+			return restartLimited, recent, first
+		}
+		// Extended cooldown passed - clear history and allow restart
+		p.events = nil
+		p.limitHit = false
+		decision = restartRecovered
+	}
+
+	p.events = append(p.events, now)
+	p.last = now
+
+	// Drop events we no longer need (keep slightly more than the window)
+	filtered := p.events[:0]
+	for _, t := range p.events {
+		if now.Sub(t) <= lim.window*2 {
+			filtered = append(filtered, t)
+		}
+	}
+	p.events = filtered
+
+	return decision, recent, false
+}
+
+// rebase shifts history when now is before the last restart, which only
+// happens if the wall clock stepped backwards between timestamps that
+// lack a monotonic reading.
+func (p *restartPolicy) rebase(now time.Time) {
+	if p.last.IsZero() || !now.Before(p.last) {
+		return
+	}
+	shift := now.Sub(p.last) // Negative
+	for i := range p.events {
+		p.events[i] = p.events[i].Add(shift)
+	}
+	p.last = now
+}
+
+// force records a restart at now without checking the policy (manual
+// restart). Automatic restarts then wait a full cooldown from now.
+func (p *restartPolicy) force(now time.Time) {
+	p.rebase(now)
+	p.events = append(p.events, now)
+	p.last = now
+}
@@ -0,0 +1,102 @@
+package ui
+
+import (
+	"testing"
+	"time"
+)
+
+var testLimits = restartLimits{
+	cooldown:    5 * time.Second,
+	window:      30 * time.Second,
+	maxRestarts: 3,
+}
+
+// wallClock returns a time without a monotonic reading, so Sub/Before
+// use wall-clock values the same way they would after an NTP step.
+func wallClock(t time.Time) time.Time {
+	return t.Round(0)
+}
+
+func TestRestartPolicy_CooldownAndLimit(t *testing.T) {
+	var p restartPolicy
+	start := wallClock(time.Now())
+
+	if d, _, _ := p.check(start, testLimits); d != restartAllowed {
+		t.Fatalf("first restart = %v, want allowed", d)
+	}
+	if d, _, _ := p.check(start.Add(2*time.Second), testLimits); d != restartCooldown {
+		t.Fatalf("restart inside cooldown = %v, want cooldown", d)
+	}
+	if d, _, _ := p.check(start.Add(6*time.Second), testLimits); d != restartAllowed {
+		t.Fatalf("restart after cooldown = %v, want allowed", d)
+	}
+	if d, _, _ := p.check(start.Add(12*time.Second), testLimits); d != restartAllowed {
+		t.Fatalf("third restart = %v, want allowed", d)
+	}
+
+	d, recent, first := p.check(start.Add(18*time.Second), testLimits)
+	if d != restartLimited || recent != 3 || !first {
+		t.Fatalf("fourth restart = (%v, %d, %v), want (limited, 3, true)", d, recent, first)
+	}
+	if _, _, first := p.check(start.Add(19*time.Second), testLimits); first {
+		t.Error("limit should only be reported once")
+	}
+
+	// Once the window has slid past the earlier restarts, restarts resume
+	if d, _, _ := p.check(start.Add(73*time.Second), testLimits); d != restartAllowed {
+		t.Fatalf("restart after window expired = %v, want allowed", d)
+	}
+}
+
+func TestRestartPolicy_ClockStepsBackwards(t *testing.T) {
+	var p restartPolicy
+	start := wallClock(time.Now())
+
+	if d, _, _ := p.check(start, testLimits); d != restartAllowed {
+		t.Fatalf("first restart = %v, want allowed", d)
+	}
+
+	// NTP steps the clock back one hour. Without rebasing, now.Sub(last)
+	// would be -1h and the camera would sit in "cooldown" for an hour.
+	jumped := start.Add(-time.Hour)
+	if d, _, _ := p.check(jumped, testLimits); d != restartCooldown {
+		t.Fatalf("restart right after step back = %v, want cooldown", d)
+	}
+	if d, _, _ := p.check(jumped.Add(6*time.Second), testLimits); d != restartAllowed {
+		t.Fatalf("restart one cooldown after step back = %v, want allowed", d)
+	}
+}
+
+func TestRestartPolicy_ClockStepsForward(t *testing.T) {
+	var p restartPolicy
+	start := wallClock(time.Now())
+
+	for i := 0; i < 3; i++ {
+		p.check(start.Add(time.Duration(i)*6*time.Second), testLimits)
+	}
+	if d, _, _ := p.check(start.Add(18*time.Second), testLimits); d != restartLimited {
+		t.Fatalf("expected limit before the jump, got %v", d)
+	}
+
+	// A forward step only makes history look older; the policy recovers
+	// instead of wedging.
+	if d, _, _ := p.check(start.Add(2*time.Hour), testLimits); d != restartAllowed {
+		t.Fatalf("restart after forward step = %v, want allowed", d)
+	}
+}
+
+func TestRestartPolicy_ForceRestartsCooldown(t *testing.T) {
+	var p restartPolicy
+	start := wallClock(time.Now())
+
+	p.check(start, testLimits)
+	// Manual restart inside the cooldown is recorded...
+	p.force(start.Add(2 * time.Second))
+	// ...so the next automatic restart waits a full cooldown from it
+	if d, _, _ := p.check(start.Add(6*time.Second), testLimits); d != restartCooldown {
+		t.Fatalf("restart 4s after manual restart = %v, want cooldown", d)
+	}
+	if d, _, _ := p.check(start.Add(8*time.Second), testLimits); d != restartAllowed {
+		t.Fatalf("restart 6s after manual restart = %v, want allowed", d)
+	}
+}
@@ -0,0 +1,429 @@
+package ui
+
+import (
+	"camera-dashboard-go/internal/camera"
+	"camera-dashboard-go/internal/config"
+	"fmt"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/widget"
+	"image/color"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// =============================================================================
+// Settings panel
+// =============================================================================
+// Full-window overlay opened from the settings tile, so tuning no longer
+// needs SSH. Pages:
+//   Display  - profile, night/driving mode, brightness, UI FPS, alert
+//              mute (applied immediately)
+//   Capture  - resolution, capture FPS           (applied after restart)
+//   Cameras  - per-camera enable                 (applied after restart)
+//   System   - frame sync report, restart (capture, in place), exit
+//
+// Save writes the values back to config.ini with config.SaveINI, which
+// keeps comments and unrelated keys. Display values are also applied
+// live; the config watcher then sees them already in effect. On a
+// read-only root (config.ConfigReadOnly) only the live part happens.
+// Keys the active profile sets are left alone, so saving doesn't copy
+// the profile's values into the file's own sections.
+// =============================================================================
+
+// resolutionOptions are the capture sizes offered in the panel (see the
+// [profile] notes in config.ini).
+var resolutionOptions = []string{"320x240", "640x480", "800x600", "1280x720", "1920x1080"}
+
+var brightnessOptions = []string{"15%", "60%", "80%", "100%", "150%"}
+
+// settingsPanel is the settings editor overlay.
+type settingsPanel struct {
+	app     *App
+	content *fyne.Container // Overlay root; hidden while closed
+	tabs    *container.AppTabs
+	status  *widget.Label
+
+	// Display
+	profile     *widget.Select // nil without [profile.<name>] sections
+	nightMode   *widget.Check
+	drivingMode *widget.Check
+	brightness  *widget.RadioGroup
+	uiFPS       *widget.Slider
+	uiFPSLabel  *widget.Label
+	alertsMuted *widget.Check // nil unless [alerts] is enabled
+
+	// Capture
+	resolution      *widget.Select
+	captureFPS      *widget.Slider
+	captureFPSLabel *widget.Label
+
+	// Cameras: config key (camera.DisableKey or a disabled entry) -> enabled
+	cameraBox     *fyne.Container
+	cameraEnabled map[string]bool
+	cameraOrder   []string
+
+	// System
+	syncInfo *widget.Label
+	perfInfo *widget.Label
+}
+
+func newSettingsPanel(a *App) *settingsPanel {
+	p := &settingsPanel{app: a, cameraEnabled: make(map[string]bool)}
+
+	// Display page
+	p.nightMode = widget.NewCheck("Night mode", nil)
+	p.drivingMode = widget.NewCheck("Driving mode (hide settings; long-press a camera to leave)", nil)
+	p.brightness = widget.NewRadioGroup(brightnessOptions, nil)
+	p.brightness.Horizontal = true
+	p.brightness.Required = true
+	p.uiFPSLabel = widget.NewLabel("")
+	p.uiFPS = widget.NewSlider(1, 60)
+	p.uiFPS.Step = 1
+	p.uiFPS.OnChanged = func(v float64) { p.uiFPSLabel.SetText(fmt.Sprintf("UI FPS: %d", int(v))) }
+	displayPage := container.NewVBox()
+	a.cfgMu.RLock()
+	hasProfiles := len(a.cfg.Profiles) > 0
+	a.cfgMu.RUnlock()
+	if hasProfiles {
+		p.profile = widget.NewSelect(nil, func(choice string) { p.selectProfile(choice) })
+		displayPage.Add(widget.NewLabel("Profile"))
+		displayPage.Add(p.profile)
+	}
+	displayPage.Objects = append(displayPage.Objects,
+		p.nightMode,
+		p.drivingMode,
+		widget.NewLabel("Brightness"),
+		p.brightness,
+		p.uiFPSLabel,
+		p.uiFPS,
+	)
+	if a.cfg.AlertsEnabled {
+		p.alertsMuted = widget.NewCheck("Mute alert sounds", nil)
+		displayPage.Add(p.alertsMuted)
+	}
+
+	// Capture page
+	p.resolution = widget.NewSelect(resolutionOptions, nil)
+	p.captureFPSLabel = widget.NewLabel("")
+	p.captureFPS = widget.NewSlider(1, 60)
+	p.captureFPS.Step = 1
+	p.captureFPS.OnChanged = func(v float64) { p.captureFPSLabel.SetText(fmt.Sprintf("Capture FPS: %d", int(v))) }
+	capturePage := container.NewVBox(
+		widget.NewLabel("Resolution"),
+		p.resolution,
+		p.captureFPSLabel,
+		p.captureFPS,
+		widget.NewLabel("Capture changes apply after a restart."),
+	)
+
+	// Cameras page (rebuilt on open)
+	p.cameraBox = container.NewVBox()
+	camerasPage := container.NewVBox(
+		p.cameraBox,
+		widget.NewLabel("Disabled cameras are not captured after a restart."),
+	)
+
+	// System page
+	p.syncInfo = widget.NewLabel("")
+	p.syncInfo.TextStyle = fyne.TextStyle{Monospace: true}
+	p.perfInfo = widget.NewLabel("")
+	p.perfInfo.TextStyle = fyne.TextStyle{Monospace: true}
+	p.perfInfo.Wrapping = fyne.TextWrapWord
+	systemPage := container.NewVBox(
+		widget.NewLabel("Frame sync"),
+		p.syncInfo,
+		widget.NewLabel("Adaptive FPS"),
+		p.perfInfo,
+		widget.NewButton("Refresh", func() {
+			p.loadSyncInfo()
+			p.loadPerfInfo()
+		}),
+		widget.NewSeparator(),
+		widget.NewButton("Restart", func() {
+			log.Println("[UI] Restart clicked")
+			p.close()
+			go a.restart()
+		}),
+		widget.NewButton("Exit", func() {
+			log.Println("[UI] Exit clicked")
+			a.cleanup()
+		}),
+	)
+
+	p.tabs = container.NewAppTabs(
+		container.NewTabItem("Display", container.NewVScroll(displayPage)),
+		container.NewTabItem("Capture", container.NewVScroll(capturePage)),
+		container.NewTabItem("Cameras", container.NewVScroll(camerasPage)),
+		container.NewTabItem("System", container.NewVScroll(systemPage)),
+	)
+
+	p.status = widget.NewLabel("")
+	saveRestart := widget.NewButton("Save & Restart", func() { p.save(true) })
+	saveRestart.Importance = widget.HighImportance
+	buttons := container.NewHBox(
+		p.status,
+		layout.NewSpacer(),
+		widget.NewButton("Close", p.close),
+		widget.NewButton("Save", func() { p.save(false) }),
+		saveRestart,
+	)
+
+	bg := canvas.NewRectangle(color.RGBA{30, 30, 35, 255})
+	p.content = container.NewStack(bg, container.NewBorder(nil, buttons, nil, nil, p.tabs))
+	p.content.Hide()
+	return p
+}
+
+// open loads the current settings into the controls and shows the panel.
+func (p *settingsPanel) open() {
+	a := p.app
+	p.loadDisplay()
+
+	current := fmt.Sprintf("%dx%d", a.cfg.CaptureWidth, a.cfg.CaptureHeight)
+	options := resolutionOptions
+	if !containsString(options, current) {
+		options = append([]string{current}, options...)
+	}
+	p.resolution.Options = options
+	p.resolution.SetSelected(current)
+	p.captureFPS.SetValue(float64(a.cfg.CaptureFPS))
+
+	p.loadCameras()
+	p.loadSyncInfo()
+	p.loadPerfInfo()
+	p.status.SetText("")
+	p.tabs.SelectIndex(0)
+	p.content.Show()
+}
+
+// loadDisplay loads the Display page's controls.
+func (p *settingsPanel) loadDisplay() {
+	a := p.app
+	a.cfgMu.RLock()
+	profiles, active, uiFPS := a.cfg.Profiles, a.cfg.ActiveProfile(), a.cfg.UIFPS
+	a.cfgMu.RUnlock()
+	if p.profile != nil {
+		p.profile.Options = append([]string{noProfile}, profiles...)
+		p.profile.Selected = profileLabel(active) // Not SetSelected: no switch
+		p.profile.Refresh()
+	}
+	p.nightMode.SetChecked(a.nightModeEnabled.Load())
+	p.drivingMode.SetChecked(a.drivingMode.Load())
+	p.brightness.SetSelected(fmt.Sprintf("%d%%", a.getBrightnessPercent()))
+	p.uiFPS.SetValue(float64(uiFPS))
+	if p.alertsMuted != nil {
+		p.alertsMuted.SetChecked(a.alertsMuted.Load())
+	}
+}
+
+// selectProfile switches to the profile chosen in the panel and shows
+// its display settings.
+func (p *settingsPanel) selectProfile(choice string) {
+	a := p.app
+	name := choice
+	if name == noProfile {
+		name = ""
+	}
+	a.cfgMu.RLock()
+	active := a.cfg.ActiveProfile()
+	a.cfgMu.RUnlock()
+	if name == active {
+		return
+	}
+	p.status.SetText("Switching to profile " + choice + "...")
+	go func() { // Capture settings restart capture, which takes seconds
+		needRestart, err := a.selectProfile(name)
+		if err != nil {
+			log.Printf("[UI] WARNING: switching to profile %s failed: %v", choice, err)
+			p.status.SetText("Profile not switched: " + err.Error())
+			p.loadDisplay()
+			return
+		}
+		p.loadDisplay()
+		if len(needRestart) > 0 {
+			p.status.SetText("Profile " + choice + " active. Some changes apply after restart.")
+		} else {
+			p.status.SetText("Profile " + choice + " active.")
+		}
+	}()
+}
+
+func (p *settingsPanel) close() {
+	p.content.Hide()
+}
+
+// loadSyncInfo shows each camera's latest capture time and skew.
+func (p *settingsPanel) loadSyncInfo() {
+	a := p.app
+	p.syncInfo.SetText(strings.Join(formatSyncReport(a.frameSyncSamples(), a.cfg.SyncEnabled), "\n"))
+}
+
+// perfInfoChanges is how many recent FPS changes the System page lists.
+const perfInfoChanges = 5
+
+// loadPerfInfo shows the adaptive FPS controller's stability report.
+func (p *settingsPanel) loadPerfInfo() {
+	a := p.app
+	pc := a.perfController.Load()
+	if pc == nil || !pc.IsDynamic() {
+		p.perfInfo.SetText("Fixed FPS (dynamic_fps off)")
+		return
+	}
+	lines := pc.StabilityReport().Lines()
+	if len(lines) > 2+perfInfoChanges {
+		lines = append(lines[:2], lines[len(lines)-perfInfoChanges:]...)
+	}
+	p.perfInfo.SetText(strings.Join(lines, "\n"))
+}
+
+// loadCameras lists discovered cameras plus cameras already disabled in
+// config (which discovery skipped, so only their config entry is known).
+func (p *settingsPanel) loadCameras() {
+	a := p.app
+	p.cameraEnabled = make(map[string]bool)
+	p.cameraOrder = nil
+	p.cameraBox.RemoveAll()
+
+	add := func(key, label string, enabled bool) {
+		if _, seen := p.cameraEnabled[key]; seen {
+			return
+		}
+		p.cameraEnabled[key] = enabled
+		p.cameraOrder = append(p.cameraOrder, key)
+		check := widget.NewCheck(label, func(on bool) { p.cameraEnabled[key] = on })
+		check.SetChecked(enabled)
+		p.cameraBox.Add(check)
+	}
+
+	a.frameLock.RLock()
+	cams := make([]camera.Camera, len(a.cameras))
+	copy(cams, a.cameras)
+	a.frameLock.RUnlock()
+	for i, cam := range cams {
+		label := fmt.Sprintf("%s - %s (%s)", cam.DeviceID, cam.Name, cam.USB)
+		if name := a.cameraName(i); name != "" {
+			label = name + ": " + label
+		}
+		add(camera.DisableKey(cam), label, true)
+	}
+	for _, entry := range a.cfg.DisabledCameras {
+		add(entry, entry+" (disabled)", false)
+	}
+	if len(p.cameraOrder) == 0 {
+		p.cameraBox.Add(widget.NewLabel("No cameras detected"))
+	}
+}
+
+// save writes the panel's values to config.ini and applies display
+// settings immediately. restart restarts capture so capture changes
+// take effect.
+func (p *settingsPanel) save(restart bool) {
+	a := p.app
+
+	brightness, _ := strconv.Atoi(strings.TrimSuffix(p.brightness.Selected, "%"))
+	uiFPS := int(p.uiFPS.Value)
+	captureFPS := int(p.captureFPS.Value)
+	width, height := a.cfg.CaptureWidth, a.cfg.CaptureHeight
+	if _, err := fmt.Sscanf(p.resolution.Selected, "%dx%d", &width, &height); err != nil {
+		width, height = a.cfg.CaptureWidth, a.cfg.CaptureHeight
+	}
+	var disabled []string
+	for _, key := range p.cameraOrder {
+		if !p.cameraEnabled[key] {
+			disabled = append(disabled, key)
+		}
+	}
+	sort.Strings(disabled)
+
+	updates := []config.INIUpdate{
+		{Section: "display", Key: "night_mode", Value: strconv.FormatBool(p.nightMode.Checked)},
+		{Section: "display", Key: "brightness", Value: strconv.Itoa(brightness)},
+		{Section: "display", Key: "driving_mode", Value: strconv.FormatBool(p.drivingMode.Checked)},
+		{Section: "profile", Key: "ui_fps", Value: strconv.Itoa(uiFPS)},
+		{Section: "profile", Key: "capture_width", Value: strconv.Itoa(width)},
+		{Section: "profile", Key: "capture_height", Value: strconv.Itoa(height)},
+		{Section: "profile", Key: "capture_fps", Value: strconv.Itoa(captureFPS)},
+		{Section: "camera", Key: "disabled", Value: strings.Join(disabled, ", ")},
+	}
+	if p.alertsMuted != nil {
+		updates = append(updates, config.INIUpdate{Section: "alerts", Key: "muted", Value: strconv.FormatBool(p.alertsMuted.Checked)})
+	}
+	a.cfgMu.RLock()
+	profileKeys := a.cfg.ProfileKeys
+	a.cfgMu.RUnlock()
+	kept := updates[:0]
+	for _, u := range updates {
+		if !containsString(profileKeys, u.Section+"."+u.Key) {
+			kept = append(kept, u)
+		}
+	}
+	updates = kept
+	if a.cfg.ConfigReadOnly {
+		// Read-only root: display settings still apply for this run
+		log.Printf("[UI] %s is read-only, settings not saved", a.cfg.Path)
+	} else if err := config.SaveINI(a.cfg.Path, updates); err != nil {
+		log.Printf("[UI] WARNING: saving settings failed: %v", err)
+		p.status.SetText("Save failed: " + err.Error())
+		return
+	} else {
+		log.Printf("[UI] Settings saved to %s", a.cfg.Path)
+	}
+
+	// Display settings take effect now
+	a.setNightMode(p.nightMode.Checked)
+	a.setBrightness(brightness)
+	if p.alertsMuted != nil {
+		a.setAlertsMuted(p.alertsMuted.Checked)
+	}
+	if a.settingsWidget != nil {
+		a.settingsWidget.SetBrightnessSelection(a.getBrightnessPercent())
+	}
+	a.cfgMu.Lock()
+	a.cfg.UIFPS = uiFPS
+	a.cfgMu.Unlock()
+
+	if a.cfg.ConfigReadOnly {
+		p.status.SetText("Config is read-only: display changes applied until restart, others not saved.")
+		if p.drivingMode.Checked {
+			a.setDrivingMode(true)
+		}
+		return
+	}
+	if restart {
+		p.close()
+		go a.restart()
+		return
+	}
+	if p.drivingMode.Checked {
+		a.setDrivingMode(true) // Also closes the panel
+		return
+	}
+
+	needsRestart := width != a.cfg.CaptureWidth || height != a.cfg.CaptureHeight ||
+		captureFPS != a.cfg.CaptureFPS || strings.Join(disabled, ",") != strings.Join(sortedCopy(a.cfg.DisabledCameras), ",")
+	if needsRestart {
+		p.status.SetText("Saved. Capture changes apply after restart.")
+	} else {
+		p.status.SetText("Saved.")
+	}
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedCopy(list []string) []string {
+	out := append([]string(nil), list...)
+	sort.Strings(out)
+	return out
+}
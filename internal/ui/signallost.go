@@ -0,0 +1,69 @@
+package ui
+
+import (
+	"image/color"
+	"log"
+)
+
+// =============================================================================
+// Signal lost
+// =============================================================================
+// A camera that can't be opened puts its worker in recovery mode. By
+// default the worker fills the slot with a test pattern; with [camera]
+// test_pattern = false it sends nothing, which saves the CPU and can't be
+// mistaken for a picture. Either way the worker reports SignalLost, and
+// the tile (and the fullscreen view) keeps its last real frame, dimmed,
+// under a red SIGNAL LOST banner with the recovery status and Restart
+// button until a real frame arrives again. Changes are logged and
+// published as MQTT events of type "signal_lost".
+// =============================================================================
+
+var (
+	signalLostColor = color.RGBA{255, 70, 70, 255}
+	signalLostDim   = color.NRGBA{0, 0, 0, 150} // Over the last frame
+)
+
+// cameraSignalLost reports whether camIndex's worker has no picture.
+func (a *App) cameraSignalLost(camIndex int) bool {
+	manager := a.manager.Load()
+	if manager == nil {
+		return false
+	}
+	a.frameLock.RLock()
+	if camIndex < 0 || camIndex >= len(a.cameras) {
+		a.frameLock.RUnlock()
+		return false
+	}
+	id := a.cameras[camIndex].DeviceID
+	a.frameLock.RUnlock()
+	worker := manager.GetWorker(id)
+	return worker != nil && worker.SignalLost()
+}
+
+// updateSignalLost refreshes the SIGNAL LOST banners and reports changes
+// (stale ticker).
+func (a *App) updateSignalLost() {
+	for i := range a.slotSignalLost {
+		lost := a.cameraSignalLost(i)
+		if lost == a.slotSignalLost[i] {
+			continue
+		}
+		a.slotSignalLost[i] = lost
+		if i < len(a.cameraWidgets) && a.cameraWidgets[i] != nil {
+			a.cameraWidgets[i].SetSignalLost(lost)
+		}
+		if lost {
+			log.Printf("[UI] Camera %s: signal lost", a.cameraTag(i))
+		} else {
+			log.Printf("[UI] Camera %s: signal restored", a.cameraTag(i))
+		}
+		if a.mqttClient.Load() != nil {
+			a.publishJSON("event", map[string]interface{}{
+				"type":      "signal_lost",
+				"camera":    i,
+				"lost":      lost,
+				"timestamp": a.clock.Now().Unix(),
+			}, false)
+		}
+	}
+}
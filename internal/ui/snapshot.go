@@ -0,0 +1,111 @@
+package ui
+
+import (
+	"camera-dashboard-go/internal/helpers"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// =============================================================================
+// Snapshots
+// =============================================================================
+// Saves the latest unfiltered frame of a camera as a JPEG under
+// [snapshot] dir. Night mode / brightness are display-only and are
+// not baked into snapshots; [dewarp] and the [transform] orientation
+// are, so the image matches the lens/mounting correction on screen.
+// =============================================================================
+
+// saveSnapshot writes the current frame of camIndex to the snapshot
+// directory and returns the file path.
+func (a *App) saveSnapshot(camIndex int) (string, error) {
+	defer a.trackWrite()()
+	a.frameLock.RLock()
+	if camIndex < 0 || camIndex >= len(a.cameras) || camIndex >= len(a.cameraFrames) {
+		a.frameLock.RUnlock()
+		return "", fmt.Errorf("no camera at index %d", camIndex)
+	}
+	deviceID := a.cameras[camIndex].DeviceID
+	frame := a.cameraFrames[camIndex]
+	connected := a.cameraStatus[camIndex]
+	a.frameLock.RUnlock()
+	if d := a.slotDewarper(camIndex); d != nil {
+		frame = d.apply(frame, nil)
+	}
+	frame = applyTransform(frame, a.slotTransform(camIndex), nil)
+
+	if frame == nil || !connected {
+		return "", fmt.Errorf("camera %d has no live frame", camIndex)
+	}
+
+	dir := a.cfg.SnapshotDir
+	if dir == "" {
+		return "", fmt.Errorf("snapshots disabled (no writable snapshot directory)")
+	}
+	if err := a.recordingBlocked(dir); err != nil {
+		return "", err
+	}
+	dir = a.recordingDir("snapshots", dir)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("create snapshot dir: %w", err)
+	}
+
+	name := a.recordingKey("snapshots", a.cfg.SnapshotDir, time.Now(), deviceID) + ".jpg"
+	path := filepath.Join(dir, name)
+	ioClass, _ := helpers.ParseIOClass(a.cfg.WriteIOClass)
+	err := helpers.RunWithIOPriority(ioClass, a.cfg.WriteIOLevel, func() error {
+		return writeJPEG(path, frame)
+	})
+	if err != nil {
+		return "", err
+	}
+
+	log.Printf("[Snapshot] Camera %d (%s) saved to %s", camIndex, deviceID, path)
+	a.toast("snapshot", "Snapshot saved: "+a.cameraLabel(camIndex), toastInfo)
+	return path, nil
+}
+
+// writeJPEG encodes img to path and syncs it. The Sync matters for IO
+// priority: buffered writes are otherwise flushed later by kernel
+// threads at their priority, not ours.
+func writeJPEG(path string, img image.Image) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := jpeg.Encode(f, img, &jpeg.Options{Quality: 90}); err != nil {
+		f.Close()
+		os.Remove(path)
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// saveAllSnapshots snapshots every connected camera, returning saved paths.
+func (a *App) saveAllSnapshots() []string {
+	a.frameLock.RLock()
+	count := len(a.cameras)
+	a.frameLock.RUnlock()
+
+	var paths []string
+	for i := 0; i < minInt(count, a.effectiveSlots()); i++ {
+		path, err := a.saveSnapshot(i)
+		if err != nil {
+			log.Printf("[Snapshot] Camera %d skipped: %v", i, err)
+			continue
+		}
+		paths = append(paths, path)
+	}
+	if len(paths) > 1 {
+		a.toast("snapshot", fmt.Sprintf("%d snapshots saved", len(paths)), toastInfo)
+	}
+	return paths
+}
@@ -0,0 +1,38 @@
+package ui
+
+import (
+	"camera-dashboard-go/internal/camera"
+	"time"
+)
+
+// =============================================================================
+// Soak-test mode
+// =============================================================================
+// Bench-only fault injection, enabled by the undocumented [soak] config
+// section. The injector is shared by every manager the app creates
+// (initial start and hotplug reinitialisation) via camera.Settings.
+// =============================================================================
+
+// startSoakTest creates the fault injector if [soak] is enabled. Must run
+// before the first manager is created so its workers pick it up.
+func (a *App) startSoakTest() {
+	if !a.cfg.SoakEnabled {
+		return
+	}
+	f := camera.NewFaultInjector(camera.FaultConfig{
+		KillFFmpegPerHour:    a.cfg.SoakKillFFmpegPerHour,
+		CorruptJPEGRate:      a.cfg.SoakCorruptJPEGRate,
+		DelayFrameRate:       a.cfg.SoakDelayFrameRate,
+		DelayFrameMax:        time.Duration(a.cfg.SoakDelayFrameMaxMS) * time.Millisecond,
+		DeviceRemovalPerHour: a.cfg.SoakDeviceRemovalPerHour,
+		RemovalDuration:      secondsToDuration(a.cfg.SoakDeviceRemovalSec),
+		Seed:                 int64(a.cfg.SoakSeed),
+	})
+	f.Start(func() []*camera.CaptureWorker {
+		if manager := a.manager.Load(); manager != nil {
+			return manager.GetWorkers()
+		}
+		return nil
+	})
+	a.faults.Store(f)
+}
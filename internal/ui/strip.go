@@ -0,0 +1,63 @@
+package ui
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// =============================================================================
+// Strip layout (ultra-wide bar displays)
+// =============================================================================
+// 1920x480-style bar displays used as mirror replacements are too flat
+// for a grid: with [display] strip_layout the cameras sit side by side
+// in one row (taking precedence over grid_layouts) and the settings tile
+// leaves the grid. In its place a small settings button sits in the top
+// left corner and opens the settings panel, which has the tile's night
+// mode and brightness controls too. Driving mode hides the button like
+// it hides the tile. Applied after restart.
+// =============================================================================
+
+// stripLayout reports whether the grid is a single row of cameras.
+func (a *App) stripLayout() bool {
+	return a.cfg.StripLayout
+}
+
+// newStripSettingsButton creates the corner settings button that stands in
+// for the settings tile in the strip layout.
+func (a *App) newStripSettingsButton() *fyne.Container {
+	a.stripSettings = widget.NewButtonWithIcon("", theme.SettingsIcon(), a.openSettings)
+	a.stripSettings.Importance = widget.LowImportance
+	if a.drivingMode.Load() {
+		a.stripSettings.Hide()
+	}
+	return container.NewVBox(container.NewHBox(a.stripSettings))
+}
+
+// showSettingsEntry shows or hides whatever opens the settings panel from
+// the grid: the settings tile, or the corner button in the strip layout.
+// The health tile goes with it.
+func (a *App) showSettingsEntry(show bool) {
+	if a.healthTile != nil && !a.stripLayout() {
+		if show {
+			a.healthTile.Show()
+		} else {
+			a.healthTile.Hide()
+		}
+	}
+	var entry fyne.CanvasObject
+	switch {
+	case a.stripLayout() && a.stripSettings != nil:
+		entry = a.stripSettings
+	case !a.stripLayout() && a.settingsWidget != nil:
+		entry = a.settingsWidget
+	default:
+		return
+	}
+	if show {
+		entry.Show()
+	} else {
+		entry.Hide()
+	}
+}
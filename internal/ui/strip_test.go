@@ -0,0 +1,20 @@
+package ui
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"testing"
+)
+
+func TestFillGridLayout_Strip(t *testing.T) {
+	tile := canvas.NewRectangle(nil) // Settings tile, hidden in the strip layout
+	tile.Hide()
+	objects := []fyne.CanvasObject{tile, canvas.NewRectangle(nil), canvas.NewRectangle(nil), canvas.NewRectangle(nil)}
+
+	(&fillGridLayout{strip: true}).Layout(objects, fyne.NewSize(1920, 480))
+	for i, obj := range objects[1:] {
+		if pos, size := obj.Position(), obj.Size(); pos != fyne.NewPos(float32(i*640), 0) || size != fyne.NewSize(640, 480) {
+			t.Errorf("camera %d at %v size %v, want (%d, 0) size 640x480", i, pos, size, i*640)
+		}
+	}
+}
@@ -0,0 +1,190 @@
+package ui
+
+import (
+	"camera-dashboard-go/internal/camera"
+	"fmt"
+	"image"
+	"strings"
+	"time"
+)
+
+// =============================================================================
+// Frame sync (capture timestamps, skew, soft-sync)
+// =============================================================================
+// Every FrameBuffer stamps frames with their capture time. The System
+// page and the [Health] log report each camera's latest capture time
+// and its skew to the newest camera, so a lagging feed is visible.
+//
+// With [sync] enabled, each buffer keeps its last max_delay_frames
+// frames and the refresh loop shows every camera's frame closest to a
+// common target time: the newest frame of the slowest camera. Faster
+// feeds are delayed by a frame or two so composite views line up.
+// latency_ms adds a known per-camera pipeline delay (a network camera
+// is usually a few hundred ms behind USB) that timestamps can't see.
+// Cameras without a frame for liveFrameWindow don't hold others back.
+// =============================================================================
+
+// liveFrameWindow is how recent a camera's last frame must be for it to
+// take part in soft-sync.
+const liveFrameWindow = time.Second
+
+// syncSample is one camera's timing for the sync report.
+type syncSample struct {
+	id       string
+	captured time.Time // Latest capture
+	shown    time.Time // Capture time of the frame on screen
+}
+
+// syncHistoryFrames is how many frames each FrameBuffer keeps.
+func (a *App) syncHistoryFrames() int {
+	if !a.cfg.SyncEnabled {
+		return 0
+	}
+	return a.cfg.SyncMaxDelayFrames
+}
+
+// syncLatency returns the configured pipeline latency of cam.
+func (a *App) syncLatency(cam camera.Camera) time.Duration {
+	for entry, ms := range a.cfg.SyncLatencyMS {
+		if camera.MatchesCamera(entry, cam) {
+			return time.Duration(ms) * time.Millisecond
+		}
+	}
+	return 0
+}
+
+// syncTarget returns the latency-corrected time all cameras are aligned
+// to, or the zero Time when soft-sync is off or fewer than two cameras
+// are live.
+func (a *App) syncTarget(buffers []*camera.FrameBuffer, cams []camera.Camera, now time.Time) time.Time {
+	if !a.cfg.SyncEnabled {
+		return time.Time{}
+	}
+	var target time.Time
+	live := 0
+	for i, buffer := range buffers {
+		if buffer == nil || a.captureHidden(i) {
+			continue // Off-screen cameras decode too rarely to sync to
+		}
+		last := buffer.GetLastFrameTime()
+		if last.IsZero() || now.Sub(last) > liveFrameWindow {
+			continue
+		}
+		live++
+		if corrected := last.Add(-a.syncLatency(cams[i])); target.IsZero() || corrected.Before(target) {
+			target = corrected
+		}
+	}
+	if live < 2 {
+		return time.Time{}
+	}
+	return target
+}
+
+// readSlotFrame returns the frame to show for camIndex: the latest one,
+// or with a sync target the one captured closest before it. hasNew is
+// false when that frame is already on screen.
+func (a *App) readSlotFrame(buffer *camera.FrameBuffer, camIndex int, cam camera.Camera, target time.Time) (frame image.Image, seq uint64, captured time.Time, hasNew bool) {
+	if target.IsZero() {
+		frame, seq, hasNew = buffer.ReadIfNew(a.lastFrameRead[camIndex])
+		return frame, seq, buffer.GetLastFrameTime(), hasNew
+	}
+	frame, captured, seq = buffer.ReadAt(target.Add(a.syncLatency(cam)))
+	return frame, seq, captured, seq > a.lastFrameRead[camIndex]
+}
+
+// frameSyncSamples collects the timing of every connected camera.
+func (a *App) frameSyncSamples() []syncSample {
+	manager := a.manager.Load()
+	if manager == nil {
+		return nil
+	}
+	a.frameLock.RLock()
+	cams := make([]camera.Camera, len(a.cameras))
+	copy(cams, a.cameras)
+	limit := minInt(len(cams), minInt(len(a.cameraStatus), len(a.shownFrameAt)))
+	var samples []syncSample
+	for i := 0; i < limit; i++ {
+		if a.cameraStatus[i] {
+			samples = append(samples, syncSample{id: cams[i].DeviceID, shown: a.shownFrameAt[i]})
+		}
+	}
+	a.frameLock.RUnlock()
+
+	for i := range samples {
+		if buffer := manager.GetFrameBuffer(samples[i].id); buffer != nil {
+			samples[i].captured = a.clock.At(buffer.GetLastFrameTime())
+		}
+	}
+	return samples
+}
+
+// formatSyncReport renders one line per camera: latest capture time,
+// skew to the newest camera and, with soft-sync, the skew of the frame
+// on screen. The last line is the overall spread.
+func formatSyncReport(samples []syncSample, softSync bool) []string {
+	var newest, newestShown time.Time
+	for _, s := range samples {
+		if s.captured.After(newest) {
+			newest = s.captured
+		}
+		if s.shown.After(newestShown) {
+			newestShown = s.shown
+		}
+	}
+	if newest.IsZero() {
+		return []string{"No frames yet"}
+	}
+
+	var lines []string
+	var spread, shownSpread time.Duration
+	for _, s := range samples {
+		if s.captured.IsZero() {
+			lines = append(lines, fmt.Sprintf("%s  no frames", s.id))
+			continue
+		}
+		skew := s.captured.Sub(newest)
+		if -skew > spread {
+			spread = -skew
+		}
+		line := fmt.Sprintf("%s  %s  skew %+d ms", s.id, s.captured.Format("15:04:05.000"), skew.Milliseconds())
+		if softSync && !s.shown.IsZero() {
+			shownSkew := s.shown.Sub(newestShown)
+			if -shownSkew > shownSpread {
+				shownSpread = -shownSkew
+			}
+			line += fmt.Sprintf("  shown %+d ms", shownSkew.Milliseconds())
+		}
+		lines = append(lines, line)
+	}
+	summary := fmt.Sprintf("Spread: %d ms", spread.Milliseconds())
+	if softSync {
+		summary += fmt.Sprintf(" captured, %d ms shown (soft-sync on)", shownSpread.Milliseconds())
+	}
+	return append(lines, summary)
+}
+
+// frameSkewSummary returns "video0=+0ms video2=-33ms" for the health log,
+// or "" with fewer than two cameras.
+func frameSkewSummary(samples []syncSample) string {
+	var newest time.Time
+	n := 0
+	for _, s := range samples {
+		if !s.captured.IsZero() {
+			n++
+			if s.captured.After(newest) {
+				newest = s.captured
+			}
+		}
+	}
+	if n < 2 {
+		return ""
+	}
+	parts := make([]string, 0, n)
+	for _, s := range samples {
+		if !s.captured.IsZero() {
+			parts = append(parts, fmt.Sprintf("%s=%+dms", s.id, s.captured.Sub(newest).Milliseconds()))
+		}
+	}
+	return strings.Join(parts, " ")
+}
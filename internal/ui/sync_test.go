@@ -0,0 +1,53 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFormatSyncReport(t *testing.T) {
+	base := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	samples := []syncSample{
+		{id: "video0", captured: base, shown: base.Add(-40 * time.Millisecond)},
+		{id: "net-trailer", captured: base.Add(-120 * time.Millisecond), shown: base.Add(-120 * time.Millisecond)},
+		{id: "video2"},
+	}
+
+	lines := formatSyncReport(samples, false)
+	want := []string{
+		"video0  12:00:00.000  skew +0 ms",
+		"net-trailer  11:59:59.880  skew -120 ms",
+		"video2  no frames",
+		"Spread: 120 ms",
+	}
+	if strings.Join(lines, "\n") != strings.Join(want, "\n") {
+		t.Errorf("formatSyncReport =\n%s\nwant\n%s", strings.Join(lines, "\n"), strings.Join(want, "\n"))
+	}
+
+	lines = formatSyncReport(samples, true)
+	if got := lines[1]; !strings.HasSuffix(got, "shown -80 ms") {
+		t.Errorf("soft-sync line = %q, want shown skew -80 ms", got)
+	}
+	if got := lines[len(lines)-1]; got != "Spread: 120 ms captured, 80 ms shown (soft-sync on)" {
+		t.Errorf("soft-sync summary = %q", got)
+	}
+
+	if got := formatSyncReport(nil, false); len(got) != 1 || got[0] != "No frames yet" {
+		t.Errorf("formatSyncReport(nil) = %v", got)
+	}
+}
+
+func TestFrameSkewSummary(t *testing.T) {
+	base := time.Now()
+	samples := []syncSample{
+		{id: "video0", captured: base.Add(-33 * time.Millisecond)},
+		{id: "video2", captured: base},
+	}
+	if got := frameSkewSummary(samples); got != "video0=-33ms video2=+0ms" {
+		t.Errorf("frameSkewSummary = %q", got)
+	}
+	if got := frameSkewSummary(samples[:1]); got != "" {
+		t.Errorf("single camera summary = %q, want empty", got)
+	}
+}
@@ -0,0 +1,80 @@
+package ui
+
+import (
+	"camera-dashboard-go/internal/systemd"
+	"fmt"
+	"log"
+	"time"
+)
+
+// =============================================================================
+// systemd service integration
+// =============================================================================
+// Under a Type=notify unit (camera-dashboard.service):
+//   - READY=1 is sent once the first camera discovery has finished, so
+//     units ordered After= the dashboard start once cameras are up. A
+//     failed discovery still reports ready: hot-plug keeps looking, and
+//     a unit stuck in "activating" helps nobody.
+//   - With WatchdogSec= but [watchdog] disabled, the refresh loop
+//     heartbeat alone keeps the systemd watchdog fed.
+//   - On SIGTERM, snapshots and clips being written are finished before
+//     exit (up to flushTimeout, with the stop timeout extended to match).
+//   - With a camera-dashboard.socket unit, the health endpoint serves
+//     the socket systemd passes in (see healthserver.go).
+// =============================================================================
+
+// flushTimeout bounds the wait for pending snapshot/clip writes at exit.
+const flushTimeout = 5 * time.Second
+
+// notifyReady reports startup complete to systemd.
+func (a *App) notifyReady() {
+	a.frameLock.RLock()
+	n := len(a.cameras)
+	a.frameLock.RUnlock()
+	if ok, err := systemd.Ready(fmt.Sprintf("%d cameras", n)); err != nil {
+		log.Printf("[Systemd] WARNING: READY notification failed: %v", err)
+	} else if ok {
+		log.Printf("[Systemd] Ready (%d cameras)", n)
+	}
+}
+
+// startSystemdKeepAlive feeds WatchdogSec= from the refresh loop
+// heartbeat when the [watchdog] supervisor isn't doing it.
+func (a *App) startSystemdKeepAlive() {
+	if a.cfg.WatchdogEnabled || !systemd.WatchdogEnabled() {
+		return
+	}
+	log.Printf("[Systemd] Feeding the systemd watchdog (timeout %v)", systemd.WatchdogInterval())
+	go systemd.KeepAlive(a.ctx.Done(), a.refreshHealthy)
+}
+
+// refreshHealthy reports whether the refresh loop has beaten recently
+// (or not started yet).
+func (a *App) refreshHealthy() bool {
+	last := a.uiHeartbeat.Last()
+	return last.IsZero() || time.Since(last) <= refreshStallTimeout
+}
+
+// trackWrite marks a snapshot/clip write in progress; call the returned
+// func when it is done.
+func (a *App) trackWrite() func() {
+	a.pendingWrites.Add(1)
+	return func() { a.pendingWrites.Add(-1) }
+}
+
+// flushWrites waits for pending snapshot/clip writes to finish.
+func (a *App) flushWrites(timeout time.Duration) {
+	if a.pendingWrites.Load() == 0 {
+		return
+	}
+	log.Printf("[UI] Cleanup: waiting for %d recordings to finish", a.pendingWrites.Load())
+	systemd.ExtendTimeout(timeout + 5*time.Second)
+	deadline := time.Now().Add(timeout)
+	for a.pendingWrites.Load() > 0 {
+		if time.Now().After(deadline) {
+			log.Printf("[UI] Cleanup: WARNING: %d recordings still writing, exiting anyway", a.pendingWrites.Load())
+			return
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
@@ -0,0 +1,39 @@
+package ui
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFlushWrites(t *testing.T) {
+	a := &App{}
+	done := a.trackWrite()
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		done()
+	}()
+	start := time.Now()
+	a.flushWrites(2 * time.Second)
+	if waited := time.Since(start); waited < 100*time.Millisecond || waited > time.Second {
+		t.Errorf("waited %v for a 100ms write", waited)
+	}
+
+	// A stuck write doesn't hold up exit past the timeout
+	a.trackWrite()
+	start = time.Now()
+	a.flushWrites(200 * time.Millisecond)
+	if waited := time.Since(start); waited > time.Second {
+		t.Errorf("waited %v with a 200ms timeout", waited)
+	}
+}
+
+func TestRefreshHealthy(t *testing.T) {
+	a := &App{}
+	if !a.refreshHealthy() {
+		t.Error("healthy before the refresh loop starts")
+	}
+	a.uiHeartbeat.Beat()
+	if !a.refreshHealthy() {
+		t.Error("healthy right after a beat")
+	}
+}
@@ -0,0 +1,184 @@
+package ui
+
+import (
+	"camera-dashboard-go/internal/config"
+	"camera-dashboard-go/internal/helpers"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/theme"
+	"image/color"
+	"log"
+	"sync/atomic"
+)
+
+// =============================================================================
+// Themes
+// =============================================================================
+// The dashboard's own colors (grid background, tiles, outlines and the
+// disconnected labels) come from a theme picked with [theme] name:
+//   dark      the default: dark grey tiles on near black
+//   daylight  high contrast for direct sunlight: black tiles with white
+//             outlines, yellow labels, a cyan swap highlight and a
+//             magenta keyboard focus
+// Any of config.ThemeColorKeys can be overridden with #RRGGBB[AA] or
+// "none". Fyne's own widgets (buttons, settings panel) keep the Fyne
+// theme. The theme is read once at startup.
+//
+// While night mode is on, the chrome takes the night palette too: every
+// theme color and Fyne's own widget colors go through nightModeColor
+// (nightmode.go), so buttons, labels and outlines match the tinted
+// cameras instead of glaring white. The SIGNAL LOST and FROZEN warnings
+// keep their colors.
+// =============================================================================
+
+// uiTheme is the set of dashboard colors.
+type uiTheme struct {
+	Background       color.Color
+	Tile             color.Color
+	SettingsTile     color.Color
+	Border           color.Color // Tile outline when not highlighted
+	Highlight        color.Color
+	Focus            color.Color // Keyboard focus outline (see keyboard.go)
+	DisconnectedText color.Color
+	DetailText       color.Color
+}
+
+var (
+	darkTheme = uiTheme{
+		Background:       color.RGBA{20, 20, 20, 255},
+		Tile:             color.RGBA{25, 25, 25, 255},
+		SettingsTile:     color.RGBA{50, 50, 55, 255},
+		Border:           color.Transparent,
+		Highlight:        color.RGBA{255, 200, 0, 255},
+		Focus:            color.RGBA{0, 160, 255, 255},
+		DisconnectedText: color.RGBA{180, 180, 180, 255},
+		DetailText:       color.RGBA{140, 140, 140, 255},
+	}
+	daylightTheme = uiTheme{
+		Background:       color.Black,
+		Tile:             color.Black,
+		SettingsTile:     color.RGBA{30, 30, 30, 255},
+		Border:           color.RGBA{255, 255, 255, 255},
+		Highlight:        color.RGBA{0, 220, 255, 255},
+		Focus:            color.RGBA{255, 0, 200, 255},
+		DisconnectedText: color.RGBA{255, 230, 0, 255},
+		DetailText:       color.White,
+	}
+)
+
+// currentTheme is the configured theme; set by newApp before any widget
+// exists. Widgets use activeTheme, which follows night mode.
+var currentTheme = darkTheme
+
+// nightTheme is currentTheme through the night palette.
+var nightTheme = nightThemeOf(darkTheme)
+
+// nightChrome is set while night mode recolors the chrome.
+var nightChrome atomic.Bool
+
+// activeTheme returns the theme to draw the chrome with.
+func activeTheme() uiTheme {
+	if nightChrome.Load() {
+		return nightTheme
+	}
+	return currentTheme
+}
+
+// nightThemeOf returns t through the night palette.
+func nightThemeOf(t uiTheme) uiTheme {
+	return uiTheme{
+		Background:       nightModeColor(t.Background),
+		Tile:             nightModeColor(t.Tile),
+		SettingsTile:     nightModeColor(t.SettingsTile),
+		Border:           nightModeColor(t.Border),
+		Highlight:        nightModeColor(t.Highlight),
+		Focus:            nightModeColor(t.Focus),
+		DisconnectedText: nightModeColor(t.DisconnectedText),
+		DetailText:       nightModeColor(t.DetailText),
+	}
+}
+
+// nightFyneTheme is a Fyne theme with its colors through the night
+// palette, for Fyne's own widgets while night mode is on.
+type nightFyneTheme struct {
+	fyne.Theme
+}
+
+func (t nightFyneTheme) Color(name fyne.ThemeColorName, variant fyne.ThemeVariant) color.Color {
+	return nightModeColor(t.Theme.Color(name, variant))
+}
+
+// themeFor builds the theme of cfg: the named theme with the [theme]
+// color overrides that parse (config.Validate warns about the others).
+func themeFor(cfg *config.Config) uiTheme {
+	t := darkTheme
+	if cfg.ThemeName == "daylight" {
+		t = daylightTheme
+	}
+	for key, value := range cfg.ThemeColors {
+		c, err := helpers.ParseHexColor(value)
+		if err != nil {
+			continue
+		}
+		switch key {
+		case "background":
+			t.Background = c
+		case "tile":
+			t.Tile = c
+		case "settings_tile":
+			t.SettingsTile = c
+		case "border":
+			t.Border = c
+		case "highlight":
+			t.Highlight = c
+		case "focus":
+			t.Focus = c
+		case "disconnected_text":
+			t.DisconnectedText = c
+		case "detail_text":
+			t.DetailText = c
+		}
+	}
+	return t
+}
+
+// applyTheme makes cfg's theme current (after setNightPalette).
+func applyTheme(cfg *config.Config) {
+	currentTheme = themeFor(cfg)
+	nightTheme = nightThemeOf(currentTheme)
+	if cfg.ThemeName != "dark" || len(cfg.ThemeColors) > 0 {
+		log.Printf("[UI] Theme %s (%d color overrides)", cfg.ThemeName, len(cfg.ThemeColors))
+	}
+}
+
+// applyNightChrome switches the chrome between the day and night
+// palettes (setNightMode). Without a window only the palette changes.
+func (a *App) applyNightChrome(enabled bool) {
+	nightChrome.Store(enabled)
+	if a.fyneApp == nil {
+		return
+	}
+	if enabled {
+		a.fyneApp.Settings().SetTheme(nightFyneTheme{theme.DefaultTheme()})
+	} else {
+		a.fyneApp.Settings().SetTheme(theme.DefaultTheme())
+	}
+	t := activeTheme()
+	if a.gridBackground != nil {
+		a.gridBackground.FillColor = t.Background
+		a.gridBackground.Refresh()
+	}
+	if a.settingsWidget != nil {
+		a.settingsWidget.SetTheme(t)
+	}
+	if a.healthTile != nil {
+		a.healthTile.SetTheme(t)
+	}
+	if a.toasts != nil {
+		a.toasts.restyle()
+	}
+	for _, w := range a.cameraWidgets {
+		if w != nil {
+			w.SetTheme(t)
+		}
+	}
+}
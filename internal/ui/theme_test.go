@@ -0,0 +1,43 @@
+package ui
+
+import (
+	"camera-dashboard-go/internal/config"
+	"image/color"
+	"testing"
+)
+
+func TestThemeFor(t *testing.T) {
+	cfg := config.DefaultConfig()
+	if got := themeFor(cfg); got != darkTheme {
+		t.Errorf("default theme = %+v, want dark", got)
+	}
+
+	cfg.ThemeName = "daylight"
+	cfg.ThemeColors = map[string]string{"highlight": "#ff00ff", "tile": "grey", "border": "none"}
+	got := themeFor(cfg)
+	if got.Highlight != (color.NRGBA{255, 0, 255, 255}) {
+		t.Errorf("highlight = %v, want the override", got.Highlight)
+	}
+	if got.Tile != daylightTheme.Tile {
+		t.Errorf("tile = %v, want daylight's (invalid override)", got.Tile)
+	}
+	if got.Border != (color.NRGBA{}) || got.DisconnectedText != daylightTheme.DisconnectedText {
+		t.Errorf("border/disconnected = %v/%v", got.Border, got.DisconnectedText)
+	}
+}
+
+func TestNightChrome(t *testing.T) {
+	a := newApp(config.DefaultConfig())
+	if activeTheme() != currentTheme {
+		t.Fatal("day chrome should use the configured theme")
+	}
+	a.setNightMode(true)
+	defer a.setNightMode(false)
+	got := activeTheme()
+	if got.Border != (color.NRGBA{}) {
+		t.Errorf("night border = %v, want transparent kept", got.Border)
+	}
+	if got.DisconnectedText != (color.NRGBA{255, 0, 0, 255}) {
+		t.Errorf("night disconnected text = %v, want red", got.DisconnectedText)
+	}
+}
@@ -0,0 +1,171 @@
+package ui
+
+import (
+	"camera-dashboard-go/internal/camera"
+	"fmt"
+	"image/color"
+	"log"
+	"strings"
+	"time"
+)
+
+// =============================================================================
+// Camera info overlay
+// =============================================================================
+// The "info" gesture action ([gestures]) toggles a small badge in the
+// top left corner of a camera tile: the camera's name and device, and
+// its live resolution, input format and capture frame rate, refreshed
+// once a second. "mute_overlay" hides these badges, the camera name
+// labels (names.go) and the GPS overlay everywhere, and brings them back; the disconnected, signal lost and
+// frozen indicators are alerts and always stay.
+// =============================================================================
+
+var infoTextColor = color.NRGBA{255, 255, 255, 230}
+
+// startTileInfo refreshes the info badges once a second, if a gesture
+// can show them.
+func (a *App) startTileInfo() {
+	if a.cfg.GestureTap != "info" && a.cfg.GestureDoubleTap != "info" &&
+		a.cfg.GestureLongPress != "info" && a.cfg.GestureTwoFingerTap != "info" {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(time.Second)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-a.ctx.Done():
+				return
+			case <-ticker.C:
+				a.refreshTileInfo()
+			}
+		}
+	}()
+}
+
+// toggleTileInfo shows or hides the info badge of one tile.
+func (a *App) toggleTileInfo(w *TappableImage) {
+	if w.ToggleInfo() {
+		a.refreshTileInfo()
+	}
+}
+
+// refreshTileInfo updates the text of every info badge that is shown.
+func (a *App) refreshTileInfo() {
+	a.frameLock.RLock()
+	cams := a.cameras
+	a.frameLock.RUnlock()
+	manager := a.manager.Load()
+	for i, w := range a.cameraWidgets {
+		if w == nil || !w.InfoOn() {
+			continue
+		}
+		if i >= len(cams) {
+			w.SetInfo(fmt.Sprintf("Camera %d", i), "no camera")
+			continue
+		}
+		width, height, fps, format := 0, 0, 0.0, ""
+		if manager != nil {
+			if worker := manager.GetWorker(cams[i].DeviceID); worker != nil {
+				width, height = worker.GetResolution()
+				_, fps, _ = worker.GetStats()
+				format = worker.StreamHealth().Format
+			}
+		}
+		cam := cams[i]
+		if name := a.cameraName(i); name != "" {
+			cam.Name = name // [names] over the USB product name
+		}
+		w.SetInfo(formatTileInfo(cam, width, height, fps, format))
+	}
+}
+
+// formatTileInfo returns the two lines of a camera's info badge.
+func formatTileInfo(cam camera.Camera, width, height int, fps float64, format string) (name, stats string) {
+	name = cam.DeviceID
+	if cam.Name != "" && cam.Name != cam.DeviceID {
+		name = fmt.Sprintf("%s (%s)", cam.Name, cam.DeviceID)
+	}
+	if width == 0 {
+		return name, "not streaming"
+	}
+	if format == "" {
+		format = "-"
+	}
+	return name, fmt.Sprintf("%dx%d %s %.1f fps", width, height, strings.ToUpper(format), fps)
+}
+
+// toggleOverlayMute hides or shows the info badges and the GPS overlay.
+func (a *App) toggleOverlayMute() {
+	muted := !a.overlaysMuted.Load()
+	a.overlaysMuted.Store(muted)
+	if muted {
+		log.Println("[UI] Overlays muted")
+	} else {
+		log.Println("[UI] Overlays shown")
+	}
+	for _, w := range a.cameraWidgets {
+		if w != nil {
+			w.SetInfoMuted(muted)
+		}
+	}
+	if a.fullscreenWidget != nil {
+		a.fullscreenWidget.SetInfoMuted(muted)
+	}
+	if muted && a.gpsOverlay != nil {
+		a.gpsOverlay.Hide() // Shown again by updateGPSOverlay with the next fix
+	}
+}
+
+// ToggleInfo shows or hides the info badge and returns whether it is on.
+func (t *TappableImage) ToggleInfo() bool {
+	t.mu.Lock()
+	t.infoOn = !t.infoOn
+	on := t.infoOn
+	t.mu.Unlock()
+	t.updateInfoBadge()
+	return on
+}
+
+// InfoOn reports whether the info badge is toggled on.
+func (t *TappableImage) InfoOn() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.infoOn
+}
+
+// SetInfoMuted hides the info badge while overlays are muted.
+func (t *TappableImage) SetInfoMuted(muted bool) {
+	t.mu.Lock()
+	t.infoMuted = muted
+	t.mu.Unlock()
+	t.updateInfoBadge()
+}
+
+// SetInfo sets the info badge text.
+func (t *TappableImage) SetInfo(name, stats string) {
+	if t.infoName.Text == name && t.infoStats.Text == stats {
+		return
+	}
+	t.infoName.Text = name
+	t.infoStats.Text = stats
+	t.infoName.Refresh()
+	t.infoStats.Refresh()
+}
+
+func (t *TappableImage) updateInfoBadge() {
+	t.mu.Lock()
+	show := t.infoOn && !t.infoMuted
+	showName := t.name != "" && !t.infoMuted
+	t.mu.Unlock()
+	if show {
+		t.infoBadge.Show()
+	} else {
+		t.infoBadge.Hide()
+	}
+	if showName {
+		t.nameBadge.Show()
+	} else {
+		t.nameBadge.Hide()
+	}
+}
@@ -0,0 +1,18 @@
+package ui
+
+import (
+	"camera-dashboard-go/internal/camera"
+	"testing"
+)
+
+func TestFormatTileInfo(t *testing.T) {
+	cam := camera.Camera{DeviceID: "video0", Name: "Rear"}
+	name, stats := formatTileInfo(cam, 640, 480, 24.84, "mjpeg")
+	if name != "Rear (video0)" || stats != "640x480 MJPEG 24.8 fps" {
+		t.Errorf("got %q, %q", name, stats)
+	}
+	name, stats = formatTileInfo(camera.Camera{DeviceID: "video2"}, 0, 0, 0, "")
+	if name != "video2" || stats != "not streaming" {
+		t.Errorf("idle camera: got %q, %q", name, stats)
+	}
+}
@@ -0,0 +1,201 @@
+package ui
+
+import (
+	"encoding/json"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// =============================================================================
+// Time of day ([time])
+// =============================================================================
+// Recording names, clip sidecars, trip reports, MQTT timestamps and the
+// replay label take the time of day from a.clock (internal/timesync),
+// which runs on the monotonic clock from startup, so a system clock
+// stepped mid-trip (NTP, an RTC reset) neither reorders recordings nor
+// upsets stale-frame detection and restart windows, which compare
+// monotonic times anyway. Every timeSyncInterval the first of [time]
+// sources with a valid time corrects the clock:
+//
+//   gps  the UTC time of the latest [gps] fix
+//   ntp  the system clock once systemd-timesyncd has synchronized it
+//
+// On a vehicle that booted offline with the wrong date, recordings saved
+// before the first sync are then renamed to the corrected time
+// (rename_recordings), clip sidecars included.
+// =============================================================================
+
+const (
+	timeSyncInterval = 10 * time.Second
+	// Created by systemd-timesyncd once the system clock is synchronized
+	ntpSyncedFile = "/run/systemd/timesync/synchronized"
+	// recordingStampLayout starts snapshot and clip names
+	recordingStampLayout = "20060102_150405.000"
+)
+
+// unsyncedRecording is a recording named before the clock was synced.
+type unsyncedRecording struct {
+	kind, final string    // "snapshots" or "clips", and its directory
+	camera      string    // Device ID, the end of the name
+	at          time.Time // When it was taken (monotonic)
+	named       time.Time // The time of day its name shows
+}
+
+// recordingKey returns the <time>_<camera> name (without extension) of
+// a recording of kind taken at (a time.Now() value), remembering it for
+// renaming while the clock isn't synced.
+func (a *App) recordingKey(kind, final string, at time.Time, deviceID string) string {
+	unsynced := a.clock.Source() == "" // Before naming: a sync in between renames to the same name
+	named := a.clock.At(at)
+	if unsynced && a.cfg.TimeRenameRecordings && len(a.cfg.TimeSources) > 0 {
+		a.renameMu.Lock()
+		a.unsynced = append(a.unsynced, unsyncedRecording{kind: kind, final: final, camera: deviceID, at: at, named: named})
+		a.renameMu.Unlock()
+	}
+	return named.Format(recordingStampLayout) + "_" + deviceID
+}
+
+// startTimeSync starts correcting the clock from [time] sources.
+func (a *App) startTimeSync() {
+	if len(a.cfg.TimeSources) == 0 {
+		return
+	}
+	log.Printf("[Time] Correcting the clock from %s", strings.Join(a.cfg.TimeSources, ", "))
+	go func() {
+		ticker := time.NewTicker(timeSyncInterval)
+		defer ticker.Stop()
+		renamePending := false
+		for {
+			if ref, at, source, ok := a.timeReference(); ok {
+				if step, moved := a.clock.Sync(ref, at, source); moved {
+					log.Printf("[Time] Clock set from %s (moved %s)", source, step.Round(time.Millisecond))
+					renamePending = true
+				}
+			}
+			// Not while a recording is being written: it may carry an old name
+			if renamePending && a.pendingWrites.Load() == 0 {
+				a.renameUnsynced()
+				renamePending = false
+			}
+			select {
+			case <-a.ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// timeReference returns the time of day ref at the local moment at from
+// the first source that has one.
+func (a *App) timeReference() (ref, at time.Time, source string, ok bool) {
+	for _, src := range a.cfg.TimeSources {
+		switch src {
+		case "gps":
+			if fix, ok := a.currentFix(); ok && !fix.UTC.IsZero() {
+				return fix.UTC, fix.Time, src, true
+			}
+		case "ntp":
+			if _, err := os.Stat(ntpSyncedFile); err == nil {
+				now := time.Now()
+				return now.Round(0), now, src, true
+			}
+		}
+	}
+	return time.Time{}, time.Time{}, "", false
+}
+
+// renameUnsynced renames the recordings saved before the clock was
+// synced to the corrected time.
+func (a *App) renameUnsynced() {
+	a.renameMu.Lock()
+	pending := a.unsynced
+	a.unsynced = nil
+	a.renameMu.Unlock()
+	if len(pending) == 0 {
+		return
+	}
+
+	a.stageMu.Lock() // Files don't move between RAM and disk meanwhile
+	defer a.stageMu.Unlock()
+	renamed := 0
+	for _, r := range pending {
+		corrected := a.clock.At(r.at)
+		oldKey := r.named.Format(recordingStampLayout) + "_" + r.camera
+		newKey := corrected.Format(recordingStampLayout) + "_" + r.camera
+		if newKey == oldKey {
+			continue
+		}
+		dirs := []string{r.final}
+		if staged := a.recordingDir(r.kind, r.final); staged != r.final {
+			dirs = append(dirs, staged)
+		}
+		for _, dir := range dirs {
+			n, err := renameRecording(dir, oldKey, newKey, corrected.Sub(r.named))
+			if err != nil {
+				log.Printf("[Time] WARNING: renaming %s in %s: %v", oldKey, dir, err)
+			}
+			renamed += n
+		}
+	}
+	if renamed > 0 {
+		log.Printf("[Time] Renamed %d recording files to the corrected time", renamed)
+	}
+}
+
+// renameRecording renames the files of recording oldKey in dir (media,
+// clip sidecar, .keep marker) to newKey, shifting the sidecar's times by
+// shift. It returns the number of files renamed.
+func renameRecording(dir, oldKey, newKey string, shift time.Duration) (int, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	n := 0
+	for _, e := range entries {
+		ext := filepath.Ext(e.Name())
+		if e.Name() != oldKey+ext || !e.Type().IsRegular() {
+			continue
+		}
+		src, dst := filepath.Join(dir, e.Name()), filepath.Join(dir, newKey+ext)
+		if _, err := os.Stat(dst); err == nil {
+			continue // Never overwrite a recording
+		}
+		if ext == ".json" {
+			err = shiftClipMeta(src, dst, shift)
+		} else {
+			err = os.Rename(src, dst)
+		}
+		if err != nil {
+			return n, err
+		}
+		n++
+	}
+	return n, nil
+}
+
+// shiftClipMeta rewrites the clip sidecar src as dst with its times
+// moved by shift, and removes src.
+func shiftClipMeta(src, dst string, shift time.Duration) error {
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return err
+	}
+	var meta clipMeta
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return os.Rename(src, dst) // Not ours to fix; keep it with its clip
+	}
+	meta.SavedAt = meta.SavedAt.Add(shift)
+	meta.Start = meta.Start.Add(shift)
+	meta.End = meta.End.Add(shift)
+	if err := writeClipMeta(dst, meta); err != nil {
+		return err
+	}
+	return os.Remove(src)
+}
@@ -0,0 +1,66 @@
+package ui
+
+import (
+	"camera-dashboard-go/internal/config"
+	"camera-dashboard-go/internal/timesync"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRenameUnsynced(t *testing.T) {
+	root := t.TempDir()
+	cfg := config.DefaultConfig()
+	cfg.ClipsDir = filepath.Join(root, "clips")
+	if err := os.MkdirAll(cfg.ClipsDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	a := &App{cfg: cfg, clock: timesync.New()}
+
+	// Saved while the clock still has the boot-time guess
+	at := time.Now()
+	key := a.recordingKey("clips", cfg.ClipsDir, at, "video0")
+	named := a.clock.At(at)
+	meta := clipMeta{DeviceID: "video0", SavedAt: named, Start: named.Add(-time.Second), End: named}
+	if err := writeClipMeta(filepath.Join(cfg.ClipsDir, key+".json"), meta); err != nil {
+		t.Fatal(err)
+	}
+	for _, ext := range []string{".mjpeg", ".keep"} {
+		if err := os.WriteFile(filepath.Join(cfg.ClipsDir, key+ext), nil, 0o444); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// GPS reports the actual date
+	actual := time.Date(2025, 6, 1, 8, 30, 0, 0, time.UTC)
+	if _, ok := a.clock.Sync(actual, at, "gps"); !ok {
+		t.Fatal("clock not synced")
+	}
+	a.renameUnsynced()
+
+	newKey := actual.In(named.Location()).Format(recordingStampLayout) + "_video0"
+	for _, ext := range []string{".mjpeg", ".json", ".keep"} {
+		if _, err := os.Stat(filepath.Join(cfg.ClipsDir, newKey+ext)); err != nil {
+			t.Errorf("%s not renamed: %v", ext, err)
+		}
+		if _, err := os.Stat(filepath.Join(cfg.ClipsDir, key+ext)); err == nil {
+			t.Errorf("old %s still there", ext)
+		}
+	}
+	data, _ := os.ReadFile(filepath.Join(cfg.ClipsDir, newKey+".json"))
+	var got clipMeta
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if !got.SavedAt.Equal(actual) || !got.Start.Equal(actual.Add(-time.Second)) {
+		t.Errorf("sidecar times = %v / %v, want %v", got.SavedAt, got.Start, actual)
+	}
+
+	// Recordings named after the sync aren't remembered
+	a.recordingKey("clips", cfg.ClipsDir, time.Now(), "video0")
+	if len(a.unsynced) != 0 {
+		t.Errorf("%d recordings pending after sync", len(a.unsynced))
+	}
+}
@@ -0,0 +1,214 @@
+package ui
+
+import (
+	"camera-dashboard-go/internal/perf"
+	"fmt"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/layout"
+	"image/color"
+	"sync"
+	"time"
+)
+
+// =============================================================================
+// Toast notifications
+// =============================================================================
+// Short messages at the bottom of the screen, over the grid and the
+// fullscreen view, for events worth a glance:
+//   - a camera disconnecting or reconnecting ("Rear reconnected")
+//   - the adaptive FPS controller backing off ("Reducing FPS: 84°C")
+//   - snapshots, clips and impacts
+// Toasts don't take input and disappear after [display] toast_seconds
+// (0 = off); at most maxToasts are shown, the oldest goes first. Each
+// toast has a key: a new message with the key of a toast still on screen
+// replaces it, so "disconnected" turns into "reconnected" instead of
+// stacking up. Warnings are yellow. The colors go through the night
+// palette while night mode is on. In driving mode only warnings are
+// shown. The messages are already in the log.
+// =============================================================================
+
+const maxToasts = 3
+
+type toastKind int
+
+const (
+	toastInfo toastKind = iota
+	toastWarning
+)
+
+var (
+	toastBg          = color.NRGBA{0, 0, 0, 190}
+	toastInfoText    = color.RGBA{240, 240, 240, 255}
+	toastWarningText = color.RGBA{255, 200, 0, 255}
+)
+
+// toastLayer holds the toasts on screen.
+type toastLayer struct {
+	mu       sync.Mutex
+	box      *fyne.Container // The toasts, oldest on top
+	content  *fyne.Container // Layer over the views (bottom center)
+	items    []*toastItem
+	duration time.Duration
+}
+
+type toastItem struct {
+	key   string
+	kind  toastKind
+	bg    *canvas.Rectangle
+	text  *canvas.Text
+	obj   *fyne.Container
+	timer *time.Timer
+	shown int // Times shown; a timer from an earlier showing doesn't dismiss
+}
+
+func newToastLayer(duration time.Duration) *toastLayer {
+	l := &toastLayer{box: container.NewVBox(), duration: duration}
+	bottom := container.NewHBox(layout.NewSpacer(), l.box, layout.NewSpacer())
+	l.content = container.NewBorder(nil, container.NewPadded(bottom), nil, nil)
+	return l
+}
+
+// show puts msg on screen under key, replacing a toast with the same key.
+func (l *toastLayer) show(key, msg string, kind toastKind) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var item *toastItem
+	for _, it := range l.items {
+		if it.key == key {
+			item = it
+			item.timer.Stop()
+			break
+		}
+	}
+	if item == nil {
+		item = &toastItem{key: key, bg: canvas.NewRectangle(color.Transparent), text: canvas.NewText("", color.White)}
+		item.bg.CornerRadius = 6
+		item.text.TextSize = 16
+		item.obj = container.NewStack(item.bg, container.NewPadded(item.text))
+		l.items = append(l.items, item)
+		if len(l.items) > maxToasts {
+			l.items[0].timer.Stop()
+			l.items = l.items[1:]
+		}
+	}
+	item.kind = kind
+	item.text.Text = msg
+	item.style()
+	item.shown++
+	shown := item.shown
+	item.timer = time.AfterFunc(l.duration, func() { l.dismiss(item, shown) })
+	l.layoutLocked()
+}
+
+// dismiss removes item once its time is up.
+func (l *toastLayer) dismiss(item *toastItem, shown int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for i, it := range l.items {
+		if it == item && it.shown == shown {
+			l.items = append(l.items[:i], l.items[i+1:]...)
+			l.layoutLocked()
+			return
+		}
+	}
+}
+
+// remove takes the toast with key off the screen, if there is one.
+func (l *toastLayer) remove(key string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for i, it := range l.items {
+		if it.key == key {
+			it.timer.Stop()
+			l.items = append(l.items[:i], l.items[i+1:]...)
+			l.layoutLocked()
+			return
+		}
+	}
+}
+
+// restyle recolors the toasts on screen (night mode switched).
+func (l *toastLayer) restyle() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, it := range l.items {
+		it.style()
+	}
+}
+
+func (l *toastLayer) layoutLocked() {
+	objects := make([]fyne.CanvasObject, len(l.items))
+	for i, it := range l.items {
+		objects[i] = it.obj
+	}
+	l.box.Objects = objects
+	l.box.Refresh()
+}
+
+func (it *toastItem) style() {
+	bg, fg := toastColors(it.kind, nightChrome.Load())
+	it.bg.FillColor = bg
+	it.text.Color = fg
+	it.bg.Refresh()
+	it.text.Refresh()
+}
+
+// toastColors returns the background and text colors of a toast.
+func toastColors(kind toastKind, night bool) (bg, fg color.Color) {
+	bg, fg = toastBg, toastInfoText
+	if kind == toastWarning {
+		fg = toastWarningText
+	}
+	if night {
+		return nightModeColor(bg), nightModeColor(fg)
+	}
+	return bg, fg
+}
+
+// toast shows a notification ([display] toast_seconds; no-op when
+// headless or off). In driving mode an info toast isn't shown but still
+// clears the toast with its key ("reconnected" takes "disconnected"
+// away). Safe from any goroutine.
+func (a *App) toast(key, msg string, kind toastKind) {
+	if a.toasts == nil {
+		return
+	}
+	if kind == toastInfo && a.drivingMode.Load() {
+		a.toasts.remove(key)
+		return
+	}
+	a.toasts.show(key, msg, kind)
+}
+
+// onFPSChange tells the driver when the adaptive controller lowers the
+// frame rate (runs on the controller's loop; see SetOnFPSChange).
+func (a *App) onFPSChange(oldFPS, newFPS int, reason string) {
+	tempC := 0.0
+	if pc := a.perfController.Load(); pc != nil {
+		tempC = pc.GetTemperature()
+	}
+	if msg, ok := fpsChangeToast(oldFPS, newFPS, reason, tempC); ok {
+		a.toast("fps", msg, toastWarning)
+	}
+}
+
+// fpsChangeToast returns the toast for an FPS change, if it's worth one:
+// only reductions for heat or load, not probing back up.
+func fpsChangeToast(oldFPS, newFPS int, reason string, tempC float64) (string, bool) {
+	if newFPS >= oldFPS {
+		return "", false
+	}
+	switch reason {
+	case perf.ReasonThermal, perf.ReasonEmergency:
+		if tempC > 0 {
+			return fmt.Sprintf("Reducing FPS: %.0f°C", tempC), true
+		}
+		return "Reducing FPS: CPU too hot", true
+	case perf.ReasonLoad:
+		return "Reducing FPS: CPU load", true
+	}
+	return "", false
+}
@@ -0,0 +1,61 @@
+package ui
+
+import (
+	"camera-dashboard-go/internal/perf"
+	"testing"
+	"time"
+)
+
+func TestFPSChangeToast(t *testing.T) {
+	tests := []struct {
+		oldFPS, newFPS int
+		reason         string
+		tempC          float64
+		want           string
+	}{
+		{25, 20, perf.ReasonThermal, 84.3, "Reducing FPS: 84°C"},
+		{25, 10, perf.ReasonEmergency, 0, "Reducing FPS: CPU too hot"},
+		{25, 20, perf.ReasonLoad, 60, "Reducing FPS: CPU load"},
+		{20, 25, perf.ReasonProbe, 60, ""},
+		{20, 25, perf.ReasonRecovery, 60, ""},
+	}
+	for _, tt := range tests {
+		got, ok := fpsChangeToast(tt.oldFPS, tt.newFPS, tt.reason, tt.tempC)
+		if got != tt.want || ok != (tt.want != "") {
+			t.Errorf("fpsChangeToast(%d, %d, %s) = %q, %v; want %q",
+				tt.oldFPS, tt.newFPS, tt.reason, got, ok, tt.want)
+		}
+	}
+}
+
+func TestToastColors(t *testing.T) {
+	bg, info := toastColors(toastInfo, false)
+	_, warning := toastColors(toastWarning, false)
+	if bg != toastBg || info != toastInfoText || warning != toastWarningText {
+		t.Errorf("day colors = %v, %v, %v", bg, info, warning)
+	}
+	_, nightInfo := toastColors(toastInfo, true)
+	if nightInfo != nightModeColor(toastInfoText) {
+		t.Errorf("night info text = %v, want %v", nightInfo, nightModeColor(toastInfoText))
+	}
+}
+
+func TestToast_DrivingMode(t *testing.T) {
+	a := &App{toasts: newToastLayer(time.Minute)}
+	a.drivingMode.Store(true)
+	a.toast("snapshot", "Snapshot saved", toastInfo)
+	a.toast("camera0", "Rear disconnected", toastWarning)
+	if n := len(a.toasts.items); n != 1 || a.toasts.items[0].key != "camera0" {
+		t.Fatalf("%d toasts in driving mode, want only the warning", n)
+	}
+	a.toast("camera0", "Rear reconnected", toastInfo)
+	if n := len(a.toasts.items); n != 0 {
+		t.Fatalf("%d toasts after the reconnect, want the warning cleared", n)
+	}
+
+	a.drivingMode.Store(false)
+	a.toast("snapshot", "Snapshot saved", toastInfo)
+	if n := len(a.toasts.items); n != 1 {
+		t.Errorf("%d toasts after driving mode, want the info toast", n)
+	}
+}
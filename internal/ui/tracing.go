@@ -0,0 +1,126 @@
+package ui
+
+import (
+	"camera-dashboard-go/internal/camera"
+	"encoding/csv"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"time"
+)
+
+// =============================================================================
+// Frame tracing
+// =============================================================================
+// -trace-frames times sampled frames through the whole pipeline, to find
+// where jitter comes from without logging every frame. Capture workers
+// time one decoded frame in -trace-sample (default 30) per camera (see
+// camera/trace.go); the refresh loop adds its stages when it picks the
+// frame up:
+//
+//   read, parse, decode, write  capture side (FFmpeg pipe, MJPEG split,
+//                               jpeg.Decode, FrameBuffer write)
+//   pickup                      buffer write -> refresh loop reading it
+//   refresh                     display filters and the widget refresh
+//
+// Each sample is a [Trace] log line, or a row of -trace-csv FILE. A
+// sample the refresh loop never sees (a newer frame replaced it first)
+// is not reported: the missing pick-up is the jitter, and the FPS and
+// dropped counts in the logs already cover it.
+// =============================================================================
+
+var traceCSVHeader = []string{"time", "camera", "frame", "read_ms", "parse_ms", "decode_ms", "write_ms", "pickup_ms", "refresh_ms", "total_ms"}
+
+// frameTracer reports frame traces. Used by the refresh loop only.
+type frameTracer struct {
+	file *os.File    // nil = log
+	csv  *csv.Writer // Flushed every row, so a crash keeps the samples
+}
+
+// traceEvery is camera.Settings.TraceEvery: the sample rate with
+// -trace-frames, else 0.
+func (a *App) traceEvery() int {
+	if !a.cfg.TraceFrames {
+		return 0
+	}
+	if a.cfg.TraceSampleEvery < 1 {
+		return 1
+	}
+	return a.cfg.TraceSampleEvery
+}
+
+// newFrameTracer returns the tracer for -trace-frames, or nil when it is
+// off. A CSV file that can't be created falls back to the log.
+func (a *App) newFrameTracer() *frameTracer {
+	if !a.cfg.TraceFrames {
+		return nil
+	}
+	t := &frameTracer{}
+	where := "the log"
+	if a.cfg.TraceCSV != "" {
+		if err := t.openCSV(a.cfg.TraceCSV); err != nil {
+			log.Printf("[Trace] WARNING: %v - tracing to the log", err)
+		} else {
+			where = a.cfg.TraceCSV
+		}
+	}
+	log.Printf("[Trace] Tracing one frame in %d per camera to %s", a.traceEvery(), where)
+	return t
+}
+
+// openCSV creates path and writes the header.
+func (t *frameTracer) openCSV(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	w := csv.NewWriter(f)
+	w.Write(traceCSVHeader)
+	w.Flush()
+	if err := w.Error(); err != nil {
+		f.Close()
+		return err
+	}
+	t.file, t.csv = f, w
+	return nil
+}
+
+// record reports one frame: tr from the capture worker plus the refresh
+// loop's pick-up and refresh times.
+func (t *frameTracer) record(tr *camera.FrameTrace, pickup, refresh time.Duration) {
+	total := tr.Read + tr.Parse + tr.Decode + tr.Write + pickup + refresh
+	if t.csv == nil {
+		log.Printf("[Trace] %s #%d: read %s parse %s decode %s write %s pickup %s refresh %s (total %s)",
+			tr.Camera, tr.Seq, traceMS(tr.Read), traceMS(tr.Parse), traceMS(tr.Decode), traceMS(tr.Write),
+			traceMS(pickup), traceMS(refresh), traceMS(total))
+		return
+	}
+	t.csv.Write([]string{
+		tr.Written.Format(time.RFC3339Nano), tr.Camera, strconv.FormatUint(tr.Seq, 10),
+		traceMSValue(tr.Read), traceMSValue(tr.Parse), traceMSValue(tr.Decode), traceMSValue(tr.Write),
+		traceMSValue(pickup), traceMSValue(refresh), traceMSValue(total),
+	})
+	t.csv.Flush()
+}
+
+// close closes the CSV file (after the refresh loop has stopped).
+func (t *frameTracer) close() {
+	if t == nil || t.file == nil {
+		return
+	}
+	t.csv.Flush()
+	if err := t.file.Close(); err != nil {
+		log.Printf("[Trace] WARNING: close %s: %v", t.file.Name(), err)
+	}
+}
+
+// traceMS formats d for the log, e.g. "4.25ms".
+func traceMS(d time.Duration) string {
+	return traceMSValue(d) + "ms"
+}
+
+// traceMSValue is d in milliseconds with microsecond precision.
+func traceMSValue(d time.Duration) string {
+	return fmt.Sprintf("%.3f", float64(d)/float64(time.Millisecond))
+}
@@ -0,0 +1,63 @@
+package ui
+
+import (
+	"camera-dashboard-go/internal/camera"
+	"camera-dashboard-go/internal/config"
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestFrameTracer_CSV(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.TraceFrames = true
+	cfg.TraceCSV = filepath.Join(t.TempDir(), "trace.csv")
+	a := &App{cfg: cfg}
+
+	if (&App{cfg: config.DefaultConfig()}).newFrameTracer() != nil {
+		t.Error("tracer created without -trace-frames")
+	}
+	tracer := a.newFrameTracer()
+	if tracer == nil {
+		t.Fatal("no tracer with -trace-frames")
+	}
+	tracer.record(&camera.FrameTrace{
+		Camera: "video0", Seq: 30,
+		Read: 12 * time.Millisecond, Parse: 250 * time.Microsecond,
+		Decode: 8 * time.Millisecond, Write: 5 * time.Microsecond,
+		Written: time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC),
+	}, 3*time.Millisecond, 2*time.Millisecond)
+	tracer.close()
+
+	f, err := os.Open(cfg.TraceCSV)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	rows, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]string{
+		traceCSVHeader,
+		{"2026-10-16T12:00:00Z", "video0", "30", "12.000", "0.250", "8.000", "0.005", "3.000", "2.000", "25.255"},
+	}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("trace CSV = %q, want %q", rows, want)
+	}
+}
+
+func TestTraceEvery(t *testing.T) {
+	cfg := config.DefaultConfig()
+	a := &App{cfg: cfg}
+	if n := a.traceEvery(); n != 0 {
+		t.Errorf("traceEvery without -trace-frames = %d, want 0", n)
+	}
+	cfg.TraceFrames, cfg.TraceSampleEvery = true, 0
+	if n := a.traceEvery(); n != 1 {
+		t.Errorf("traceEvery with -trace-sample 0 = %d, want 1", n)
+	}
+}
@@ -0,0 +1,244 @@
+package ui
+
+import (
+	"camera-dashboard-go/internal/camera"
+	"fmt"
+	"image"
+	"log"
+	"strconv"
+	"strings"
+)
+
+// =============================================================================
+// Per-camera mirror / flip / rotation
+// =============================================================================
+// Side cameras mounted upside down, or used as mirrors, are corrected at
+// display time instead of with an FFmpeg filter (which would cost a
+// decode/re-encode per frame). [transform] in config.ini maps a camera
+// (device path, device ID or vendor:product:serial) to options:
+//
+//   mirror      flip left-right
+//   flip        flip top-bottom
+//   rotate=90   rotate clockwise by 90, 180 or 270 degrees
+//
+// Mirror/flip are applied before rotation. The transform runs before
+// night mode and brightness, into a reusable per-slot buffer. MJPEG
+// frames stay YCbCr (each plane is moved as-is, no colour conversion);
+// other formats go through RGBA.
+// =============================================================================
+
+// frameTransform is a parsed [transform] entry.
+type frameTransform struct {
+	mirror bool
+	flip   bool
+	rotate int // 0, 90, 180 or 270
+}
+
+func (t frameTransform) isIdentity() bool {
+	return !t.mirror && !t.flip && t.rotate == 0
+}
+
+// parseTransform parses a [transform] value such as "mirror, rotate=180".
+func parseTransform(spec string) (frameTransform, error) {
+	var t frameTransform
+	for _, option := range strings.Split(spec, ",") {
+		option = strings.ToLower(strings.TrimSpace(option))
+		key, value, _ := strings.Cut(option, "=")
+		switch strings.TrimSpace(key) {
+		case "":
+		case "mirror":
+			t.mirror = true
+		case "flip":
+			t.flip = true
+		case "rotate":
+			deg, err := strconv.Atoi(strings.TrimSpace(value))
+			if err != nil || (deg != 0 && deg != 90 && deg != 180 && deg != 270) {
+				return t, fmt.Errorf("rotate must be 0, 90, 180 or 270, got %q", value)
+			}
+			t.rotate = deg
+		default:
+			return t, fmt.Errorf("unknown option %q", option)
+		}
+	}
+	return t, nil
+}
+
+// updateSlotTransforms resolves [transform] entries for the discovered
+// cameras (called whenever a.cameras changes, and on config reload).
+func (a *App) updateSlotTransforms(cams []camera.Camera) {
+	a.cfgMu.RLock()
+	specs := a.cfg.CameraTransforms // Reloads replace the map, never modify it
+	a.cfgMu.RUnlock()
+	transforms := make([]frameTransform, len(cams))
+	for entry, spec := range specs {
+		t, err := parseTransform(spec)
+		if err != nil {
+			log.Printf("[UI] WARNING: [transform] %s: %v", entry, err)
+			continue
+		}
+		for i, cam := range cams {
+			if camera.MatchesCamera(entry, cam) {
+				transforms[i] = t
+				log.Printf("[UI] Camera %s: transform %s", cam.DeviceID, spec)
+			}
+		}
+	}
+	a.slotTransforms.Store(&transforms)
+}
+
+// slotTransform returns the transform for camIndex.
+func (a *App) slotTransform(camIndex int) frameTransform {
+	transforms := a.slotTransforms.Load()
+	if transforms == nil || camIndex < 0 || camIndex >= len(*transforms) {
+		return frameTransform{}
+	}
+	return (*transforms)[camIndex]
+}
+
+// applyTransform returns src transformed by t, reusing dst when it has
+// the right type and capacity. The identity transform returns src.
+func applyTransform(src image.Image, t frameTransform, dst image.Image) image.Image {
+	if t.isIdentity() {
+		return src
+	}
+	switch s := src.(type) {
+	case *image.YCbCr:
+		ratio, ok := rotatedSubsampleRatio(s.SubsampleRatio, t.rotate)
+		if !ok {
+			break
+		}
+		w, h := s.Rect.Dx(), s.Rect.Dy()
+		if t.rotate == 90 || t.rotate == 270 {
+			w, h = h, w
+		}
+		d, _ := dst.(*image.YCbCr)
+		d = reuseYCbCr(d, w, h, ratio)
+		cw, ch := chromaSize(s)
+		transformPlane(s.Y, s.YStride, s.Rect.Dx(), s.Rect.Dy(), 1, d.Y, d.YStride, t)
+		transformPlane(s.Cb, s.CStride, cw, ch, 1, d.Cb, d.CStride, t)
+		transformPlane(s.Cr, s.CStride, cw, ch, 1, d.Cr, d.CStride, t)
+		return d
+	}
+
+	rgba, ok := src.(*image.RGBA)
+	if !ok || rgba.Rect.Min != (image.Point{}) {
+		rgba = toRGBA(src)
+	}
+	w, h := rgba.Rect.Dx(), rgba.Rect.Dy()
+	if t.rotate == 90 || t.rotate == 270 {
+		w, h = h, w
+	}
+	d, _ := dst.(*image.RGBA)
+	if d == nil || cap(d.Pix) < w*h*4 {
+		d = image.NewRGBA(image.Rect(0, 0, w, h))
+	} else {
+		d.Pix = d.Pix[:w*h*4]
+		d.Stride = w * 4
+		d.Rect = image.Rect(0, 0, w, h)
+	}
+	transformPlane(rgba.Pix, rgba.Stride, rgba.Rect.Dx(), rgba.Rect.Dy(), 4, d.Pix, d.Stride, t)
+	return d
+}
+
+// transformPlane copies a w x h plane of bpp-byte pixels from src to dst
+// with t applied. Each source pixel's destination is a linear function
+// of (x, y), so the inner loop only adds a fixed step.
+func transformPlane(src []byte, srcStride, w, h, bpp int, dst []byte, dstStride int, t frameTransform) {
+	// After mirror/flip: x1 = mx*x + m0, y1 = fy*y + f0
+	mx, m0 := 1, 0
+	if t.mirror {
+		mx, m0 = -1, w-1
+	}
+	fy, f0 := 1, 0
+	if t.flip {
+		fy, f0 = -1, h-1
+	}
+	// Destination: X = ax*x + bx*y + cx, Y = ay*x + by*y + cy
+	var ax, bx, cx, ay, by, cy int
+	switch t.rotate {
+	case 90:
+		ax, bx, cx = 0, -fy, h-1-f0
+		ay, by, cy = mx, 0, m0
+	case 180:
+		ax, bx, cx = -mx, 0, w-1-m0
+		ay, by, cy = 0, -fy, h-1-f0
+	case 270:
+		ax, bx, cx = 0, fy, f0
+		ay, by, cy = -mx, 0, w-1-m0
+	default:
+		ax, bx, cx = mx, 0, m0
+		ay, by, cy = 0, fy, f0
+	}
+	stepX := ay*dstStride + ax*bpp
+	stepY := by*dstStride + bx*bpp
+	base := cy*dstStride + cx*bpp
+
+	for y := 0; y < h; y++ {
+		srcOff := y * srcStride
+		dstOff := base + y*stepY
+		if bpp == 1 {
+			for x := 0; x < w; x++ {
+				dst[dstOff] = src[srcOff+x]
+				dstOff += stepX
+			}
+			continue
+		}
+		for x := 0; x < w; x++ {
+			copy(dst[dstOff:dstOff+bpp], src[srcOff:srcOff+bpp])
+			srcOff += bpp
+			dstOff += stepX
+		}
+	}
+}
+
+// rotatedSubsampleRatio returns the chroma subsampling after rotating by
+// deg. Quarter turns swap horizontal and vertical subsampling; ratios
+// without a rotated counterpart in image.YCbCr return false.
+func rotatedSubsampleRatio(r image.YCbCrSubsampleRatio, deg int) (image.YCbCrSubsampleRatio, bool) {
+	if deg != 90 && deg != 270 {
+		return r, true
+	}
+	switch r {
+	case image.YCbCrSubsampleRatio444, image.YCbCrSubsampleRatio420:
+		return r, true
+	case image.YCbCrSubsampleRatio422:
+		return image.YCbCrSubsampleRatio440, true
+	case image.YCbCrSubsampleRatio440:
+		return image.YCbCrSubsampleRatio422, true
+	}
+	return r, false
+}
+
+// chromaSize returns the Cb/Cr plane dimensions of img.
+func chromaSize(img *image.YCbCr) (int, int) {
+	w, h := img.Rect.Dx(), img.Rect.Dy()
+	switch img.SubsampleRatio {
+	case image.YCbCrSubsampleRatio422:
+		return (w + 1) / 2, h
+	case image.YCbCrSubsampleRatio420:
+		return (w + 1) / 2, (h + 1) / 2
+	case image.YCbCrSubsampleRatio440:
+		return w, (h + 1) / 2
+	}
+	return w, h
+}
+
+// reuseYCbCr returns d if it already has the requested geometry.
+func reuseYCbCr(d *image.YCbCr, w, h int, ratio image.YCbCrSubsampleRatio) *image.YCbCr {
+	if d != nil && d.Rect == image.Rect(0, 0, w, h) && d.SubsampleRatio == ratio {
+		return d
+	}
+	return image.NewYCbCr(image.Rect(0, 0, w, h), ratio)
+}
+
+// toRGBA converts any image to an *image.RGBA at origin (0, 0).
+func toRGBA(src image.Image) *image.RGBA {
+	b := src.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	for y := 0; y < b.Dy(); y++ {
+		for x := 0; x < b.Dx(); x++ {
+			dst.Set(x, y, src.At(b.Min.X+x, b.Min.Y+y))
+		}
+	}
+	return dst
+}
@@ -0,0 +1,144 @@
+package ui
+
+import (
+	"camera-dashboard-go/internal/camera"
+	"camera-dashboard-go/internal/config"
+	"image"
+	"image/color"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseTransform(t *testing.T) {
+	got, err := parseTransform("Mirror, rotate=270")
+	if err != nil || got != (frameTransform{mirror: true, rotate: 270}) {
+		t.Errorf("parseTransform = %+v, %v", got, err)
+	}
+	for _, bad := range []string{"rotate=45", "rotate=x", "upside-down"} {
+		if _, err := parseTransform(bad); err == nil {
+			t.Errorf("parseTransform(%q) should fail", bad)
+		}
+	}
+}
+
+// TestUpdateSlotTransforms_DuringReload runs reloads that replace
+// [transform] while the transforms are resolved; -race catches readers
+// that skip a.cfgMu.
+func TestUpdateSlotTransforms_DuringReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.ini")
+	specs := []string{"rotate=180", "mirror"}
+	write := func(spec string) {
+		if err := os.WriteFile(path, []byte("[transform]\n/dev/video0 = "+spec+"\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(specs[0])
+	cfg, err := config.Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := &App{cfg: cfg}
+	a.WatchConfig(config.NewWatcher(path, cfg))
+	cams := []camera.Camera{{DeviceID: "usb-0", DevicePath: "/dev/video0"}}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 20; i++ {
+			a.updateSlotTransforms(cams)
+		}
+	}()
+	for i := 1; i <= 20; i++ {
+		write(specs[i%2])
+		a.configWatcher.Reload()
+	}
+	<-done
+
+	a.updateSlotTransforms(cams)
+	if got := a.slotTransform(0); got != (frameTransform{rotate: 180}) {
+		t.Errorf("transform after the last reload = %+v, want rotate=180", got)
+	}
+}
+
+// numbered returns a 3x2 RGBA image whose pixel (x, y) has R = 10*y + x.
+func numbered() *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, 3, 2))
+	for y := 0; y < 2; y++ {
+		for x := 0; x < 3; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(10*y + x), A: 255})
+		}
+	}
+	return img
+}
+
+// reds returns the R channel of img row by row.
+func reds(img image.Image) [][]uint8 {
+	b := img.Bounds()
+	rows := make([][]uint8, b.Dy())
+	for y := range rows {
+		for x := 0; x < b.Dx(); x++ {
+			r, _, _, _ := img.At(b.Min.X+x, b.Min.Y+y).RGBA()
+			rows[y] = append(rows[y], uint8(r>>8))
+		}
+	}
+	return rows
+}
+
+func TestApplyTransform_RGBA(t *testing.T) {
+	tests := []struct {
+		name string
+		t    frameTransform
+		want [][]uint8
+	}{
+		{"mirror", frameTransform{mirror: true}, [][]uint8{{2, 1, 0}, {12, 11, 10}}},
+		{"flip", frameTransform{flip: true}, [][]uint8{{10, 11, 12}, {0, 1, 2}}},
+		{"rotate90", frameTransform{rotate: 90}, [][]uint8{{10, 0}, {11, 1}, {12, 2}}},
+		{"rotate180", frameTransform{rotate: 180}, [][]uint8{{12, 11, 10}, {2, 1, 0}}},
+		{"rotate270", frameTransform{rotate: 270}, [][]uint8{{2, 12}, {1, 11}, {0, 10}}},
+		{"mirror+rotate90", frameTransform{mirror: true, rotate: 90}, [][]uint8{{12, 2}, {11, 1}, {10, 0}}},
+	}
+	for _, tt := range tests {
+		got := reds(applyTransform(numbered(), tt.t, nil))
+		if len(got) != len(tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+			continue
+		}
+		for y := range got {
+			for x := range got[y] {
+				if got[y][x] != tt.want[y][x] {
+					t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+				}
+			}
+		}
+	}
+}
+
+func TestApplyTransform_YCbCrRotateSwapsSubsampling(t *testing.T) {
+	src := image.NewYCbCr(image.Rect(0, 0, 4, 2), image.YCbCrSubsampleRatio422)
+	for i := range src.Y {
+		src.Y[i] = uint8(i)
+	}
+	got, ok := applyTransform(src, frameTransform{rotate: 90}, nil).(*image.YCbCr)
+	if !ok {
+		t.Fatal("rotated YCbCr frame should stay YCbCr")
+	}
+	if got.Rect != image.Rect(0, 0, 2, 4) || got.SubsampleRatio != image.YCbCrSubsampleRatio440 {
+		t.Errorf("rotated to %v %v, want 2x4 4:4:0", got.Rect, got.SubsampleRatio)
+	}
+	// Top-left of the rotated frame is the bottom-left source pixel.
+	if got.Y[0] != src.Y[src.YStride] {
+		t.Errorf("Y[0] = %d, want %d", got.Y[0], src.Y[src.YStride])
+	}
+}
+
+func TestApplyTransform_ReusesBuffer(t *testing.T) {
+	tr := frameTransform{flip: true}
+	first := applyTransform(numbered(), tr, nil)
+	if second := applyTransform(numbered(), tr, first); second != first {
+		t.Error("same-sized frame should reuse the buffer")
+	}
+	if src := numbered(); applyTransform(src, frameTransform{}, nil) != image.Image(src) {
+		t.Error("identity transform should return the source frame")
+	}
+}
@@ -0,0 +1,287 @@
+package ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// =============================================================================
+// Trip reliability report
+// =============================================================================
+// A trip is one run of the dashboard, from start to shutdown (ignition
+// off, SIGTERM, Quit) or to a self-restart. During it the trip recorder
+// samples every camera slot each tripSampleInterval (online, stale or
+// disconnected, as in the health log), counts capture restarts by reason
+// and USB incidents, and keeps each camera's worst first-frame latency
+// and the CPU temperature and load peaks.
+//
+// When the trip ends the summary is logged ([Trip] lines), written to
+// [trip_report] dir as trip-YYYYMMDD-HHMMSS.json and, with mqtt = true,
+// published to <prefix>/trip_report, so runs before and after a hardware
+// change (a new hub, cable or heatsink) can be compared. Time before the
+// first sample (startup) isn't counted against any camera.
+// =============================================================================
+
+const tripSampleInterval = 5 * time.Second
+
+// tripCamera is one slot's part of the report.
+type tripCamera struct {
+	Slot              int            `json:"slot"`
+	Camera            string         `json:"camera,omitempty"` // Device ID at the end of the trip
+	OnlinePct         float64        `json:"online_pct"`
+	StaleSec          float64        `json:"stale_sec"`
+	DisconnectedSec   float64        `json:"disconnected_sec"`
+	Restarts          map[string]int `json:"restarts,omitempty"`   // By reason: stale, reconnect, watchdog, manual, power_cycle
+	WorstFirstFrameMS int64          `json:"worst_first_frame_ms"` // -1 = never streamed
+}
+
+// tripReport is the end-of-trip summary.
+type tripReport struct {
+	Start        time.Time    `json:"start"`
+	End          time.Time    `json:"end"`
+	DurationSec  float64      `json:"duration_sec"`
+	EndReason    string       `json:"end_reason"` // shutdown or restart
+	Cameras      []tripCamera `json:"cameras"`
+	Restarts     int          `json:"restarts"`
+	USBIncidents int          `json:"usb_incidents"`
+	PeakTempC    float64      `json:"peak_temp_c"`
+	PeakTempAt   *time.Time   `json:"peak_temp_at,omitempty"`
+	PeakLoad     float64      `json:"peak_load"` // Normalized load average, 1.0 = all cores busy
+}
+
+// tripRecorder accumulates a trip. Safe for concurrent use.
+type tripRecorder struct {
+	mu           sync.Mutex
+	start        time.Time
+	last         time.Time // Last sample; zero = none yet
+	time         [][3]time.Duration
+	restarts     []map[string]int
+	firstFrameMS []int64
+	usbIncidents int
+	peakTemp     float64
+	peakTempAt   time.Time
+	peakLoad     float64
+}
+
+func newTripRecorder(slots int, start time.Time) *tripRecorder {
+	t := &tripRecorder{
+		start:        start,
+		time:         make([][3]time.Duration, slots),
+		restarts:     make([]map[string]int, slots),
+		firstFrameMS: make([]int64, slots),
+	}
+	for i := range t.firstFrameMS {
+		t.firstFrameMS[i] = -1
+	}
+	return t
+}
+
+// sample attributes the time since the last sample to each slot's
+// current health and updates the peaks. firstFrameMS is per slot, -1 =
+// no frame this session.
+func (t *tripRecorder) sample(now time.Time, health []slotHealth, firstFrameMS []int64, tempC, load float64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !t.last.IsZero() {
+		elapsed := now.Sub(t.last)
+		for i, h := range health {
+			if i < len(t.time) {
+				t.time[i][h] += elapsed
+			}
+		}
+	}
+	t.last = now
+	for i, ms := range firstFrameMS {
+		if i < len(t.firstFrameMS) && ms > t.firstFrameMS[i] {
+			t.firstFrameMS[i] = ms
+		}
+	}
+	if tempC > t.peakTemp {
+		t.peakTemp, t.peakTempAt = tempC, now
+	}
+	if load > t.peakLoad {
+		t.peakLoad = load
+	}
+}
+
+// restart counts a capture restart of slot.
+func (t *tripRecorder) restart(slot int, reason string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if slot < 0 || slot >= len(t.restarts) {
+		return
+	}
+	if t.restarts[slot] == nil {
+		t.restarts[slot] = make(map[string]int)
+	}
+	t.restarts[slot][reason]++
+}
+
+// usbIncident counts a correlated USB failure.
+func (t *tripRecorder) usbIncident() {
+	t.mu.Lock()
+	t.usbIncidents++
+	t.mu.Unlock()
+}
+
+// report summarizes the trip; cameras holds each slot's device ID.
+func (t *tripRecorder) report(end time.Time, cameras []string, reason string) tripReport {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	r := tripReport{
+		Start:        t.start,
+		End:          end,
+		DurationSec:  roundTenth(end.Sub(t.start).Seconds()),
+		EndReason:    reason,
+		USBIncidents: t.usbIncidents,
+		PeakTempC:    roundTenth(t.peakTemp),
+		PeakLoad:     float64(int(t.peakLoad*100+0.5)) / 100,
+	}
+	if !t.peakTempAt.IsZero() {
+		at := t.peakTempAt
+		r.PeakTempAt = &at
+	}
+	for i, d := range t.time {
+		c := tripCamera{
+			Slot:              i,
+			StaleSec:          roundTenth(d[slotStale].Seconds()),
+			DisconnectedSec:   roundTenth(d[slotDisconnected].Seconds()),
+			WorstFirstFrameMS: t.firstFrameMS[i],
+		}
+		if i < len(cameras) {
+			c.Camera = cameras[i]
+		}
+		if total := d[slotOnline] + d[slotStale] + d[slotDisconnected]; total > 0 {
+			c.OnlinePct = roundTenth(100 * d[slotOnline].Seconds() / total.Seconds())
+		}
+		if len(t.restarts[i]) > 0 {
+			c.Restarts = make(map[string]int, len(t.restarts[i]))
+			for reason, n := range t.restarts[i] {
+				c.Restarts[reason] = n
+				r.Restarts += n
+			}
+		}
+		r.Cameras = append(r.Cameras, c)
+	}
+	return r
+}
+
+func roundTenth(v float64) float64 {
+	return float64(int64(v*10+0.5)) / 10
+}
+
+// startTripRecorder samples the trip until shutdown.
+func (a *App) startTripRecorder() {
+	ticker := time.NewTicker(tripSampleInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-a.ctx.Done():
+			return
+		case now := <-ticker.C:
+			a.sampleTrip(now)
+		}
+	}
+}
+
+// sampleTrip records the current slot health, latencies and thermal state.
+func (a *App) sampleTrip(now time.Time) {
+	limit := minInt(a.cfg.CameraSlotCount, len(a.cameraStatus))
+	health := make([]slotHealth, limit)
+	for i := range health {
+		health[i], _ = a.cameraHealth(i, now)
+	}
+	var firstFrameMS []int64
+	if a.manager.Load() != nil {
+		firstFrameMS = a.firstFrameLatencies(limit)
+	}
+	var tempC, load float64
+	if pc := a.perfController.Load(); pc != nil {
+		tempC, load = pc.GetTemperature(), pc.GetLoadAverage()
+	}
+	a.trip.sample(now, health, firstFrameMS, tempC, load)
+}
+
+// finishTrip logs, saves and publishes the trip report (shutdown and
+// restart, while cameras and MQTT are still up).
+func (a *App) finishTrip(reason string) {
+	now := time.Now()
+	a.sampleTrip(now)
+
+	a.frameLock.RLock()
+	cameras := make([]string, len(a.cameras))
+	for i, cam := range a.cameras {
+		cameras[i] = cam.DeviceID
+	}
+	a.frameLock.RUnlock()
+	report := a.trip.report(now, cameras, reason)
+	report.Start, report.End = a.clock.At(report.Start), a.clock.At(report.End)
+	if report.PeakTempAt != nil {
+		at := a.clock.At(*report.PeakTempAt)
+		report.PeakTempAt = &at
+	}
+
+	for _, line := range tripSummary(report) {
+		log.Printf("[Trip] %s", line)
+	}
+	if dir := a.cfg.TripReportDir; dir != "" {
+		if path, err := writeTripReport(dir, report); err != nil {
+			log.Printf("[Trip] WARNING: report not saved: %v", err)
+		} else {
+			log.Printf("[Trip] Report saved to %s", path)
+		}
+	}
+	if a.cfg.TripReportMQTT {
+		a.publishJSON("trip_report", report, false)
+	}
+}
+
+// tripSummary renders the report as log lines: the trip, then one per camera.
+func tripSummary(r tripReport) []string {
+	lines := []string{fmt.Sprintf("%s trip (%s): %d restarts, %d USB incidents, peak %.1f°C, peak load %.2f",
+		time.Duration(r.DurationSec*float64(time.Second)).Round(time.Second), r.EndReason,
+		r.Restarts, r.USBIncidents, r.PeakTempC, r.PeakLoad)}
+	for _, c := range r.Cameras {
+		name := c.Camera
+		if name == "" {
+			name = "-"
+		}
+		restarts := "none"
+		if len(c.Restarts) > 0 {
+			reasons := make([]string, 0, len(c.Restarts))
+			for reason, n := range c.Restarts {
+				reasons = append(reasons, fmt.Sprintf("%s=%d", reason, n))
+			}
+			sort.Strings(reasons)
+			restarts = strings.Join(reasons, ",")
+		}
+		firstFrame := "never"
+		if c.WorstFirstFrameMS >= 0 {
+			firstFrame = fmt.Sprintf("%dms", c.WorstFirstFrameMS)
+		}
+		lines = append(lines, fmt.Sprintf("cam%d %s: online %.1f%%, stale %.0fs, disconnected %.0fs, restarts %s, worst first frame %s",
+			c.Slot, name, c.OnlinePct, c.StaleSec, c.DisconnectedSec, restarts, firstFrame))
+	}
+	return lines
+}
+
+// writeTripReport writes the report as JSON under dir.
+func writeTripReport(dir string, r tripReport) (string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("create trip report dir: %w", err)
+	}
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, "trip-"+r.Start.Format("20060102-150405")+".json")
+	return path, os.WriteFile(path, append(data, '\n'), 0o644)
+}
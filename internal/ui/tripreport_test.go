@@ -0,0 +1,74 @@
+package ui
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTripRecorder_Report(t *testing.T) {
+	start := time.Unix(1000, 0)
+	trip := newTripRecorder(2, start)
+
+	// Startup before the first sample isn't counted
+	trip.sample(start.Add(10*time.Second), []slotHealth{slotDisconnected, slotDisconnected}, nil, 50, 0.2)
+	trip.sample(start.Add(100*time.Second), []slotHealth{slotOnline, slotStale}, []int64{800, -1}, 71.26, 0.5)
+	trip.sample(start.Add(110*time.Second), []slotHealth{slotOnline, slotDisconnected}, []int64{400, 1200}, 60, 0.9)
+	trip.restart(1, "stale")
+	trip.restart(1, "stale")
+	trip.restart(1, "reconnect")
+	trip.restart(5, "watchdog") // No such slot
+	trip.usbIncident()
+
+	r := trip.report(start.Add(120*time.Second), []string{"video0"}, "shutdown")
+	if r.DurationSec != 120 || r.EndReason != "shutdown" || r.Restarts != 3 || r.USBIncidents != 1 {
+		t.Errorf("duration/reason/restarts/incidents = %v/%q/%d/%d", r.DurationSec, r.EndReason, r.Restarts, r.USBIncidents)
+	}
+	if r.PeakTempC != 71.3 || r.PeakTempAt == nil || !r.PeakTempAt.Equal(start.Add(100*time.Second)) || r.PeakLoad != 0.9 {
+		t.Errorf("peak temp/at/load = %v/%v/%v", r.PeakTempC, r.PeakTempAt, r.PeakLoad)
+	}
+	if len(r.Cameras) != 2 {
+		t.Fatalf("got %d cameras, want 2", len(r.Cameras))
+	}
+	c0, c1 := r.Cameras[0], r.Cameras[1]
+	if c0.Camera != "video0" || c0.OnlinePct != 100 || c0.WorstFirstFrameMS != 800 || c0.Restarts != nil {
+		t.Errorf("cam0 = %+v", c0)
+	}
+	if c1.Camera != "" || c1.OnlinePct != 0 || c1.StaleSec != 90 || c1.DisconnectedSec != 10 || c1.WorstFirstFrameMS != 1200 {
+		t.Errorf("cam1 = %+v", c1)
+	}
+	if c1.Restarts["stale"] != 2 || c1.Restarts["reconnect"] != 1 {
+		t.Errorf("cam1 restarts = %v", c1.Restarts)
+	}
+
+	lines := tripSummary(r)
+	if len(lines) != 3 || !strings.HasPrefix(lines[0], "2m0s trip (shutdown): 3 restarts") ||
+		!strings.Contains(lines[2], "cam1 -: online 0.0%, stale 90s, disconnected 10s, restarts reconnect=1,stale=2, worst first frame 1200ms") {
+		t.Errorf("summary = %q", lines)
+	}
+}
+
+func TestWriteTripReport(t *testing.T) {
+	dir := t.TempDir()
+	r := newTripRecorder(1, time.Date(2026, 3, 1, 7, 30, 5, 0, time.UTC)).report(time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC), nil, "restart")
+	path, err := writeTripReport(dir, r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(path, "trip-20260301-073005.json") {
+		t.Errorf("path = %q", path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var back tripReport
+	if err := json.Unmarshal(data, &back); err != nil {
+		t.Fatal(err)
+	}
+	if back.EndReason != "restart" || len(back.Cameras) != 1 || back.Cameras[0].WorstFirstFrameMS != -1 || back.PeakTempAt != nil {
+		t.Errorf("round trip = %+v", back)
+	}
+}
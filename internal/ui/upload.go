@@ -0,0 +1,177 @@
+package ui
+
+import (
+	"camera-dashboard-go/internal/storage"
+	"camera-dashboard-go/internal/upload"
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// =============================================================================
+// Remote upload ([upload])
+// =============================================================================
+// Every interval_sec the agent checks for the upload network: connected
+// to WiFi ssid (when set) and able to reach check_host, or the target
+// itself. While it is there, saved recordings not uploaded yet are sent
+// oldest first to the target (see internal/upload) as snapshots/<file>
+// and clips/<file>; with only_protected (default) just the ones kept
+// for an impact. What was sent is recorded in state_file, so nothing is
+// sent twice, and a transfer cut off by driving away resumes on the
+// next connection. Recordings still in [endurance] RAM wait for their
+// flush.
+// =============================================================================
+
+const uploadReachTimeout = 5 * time.Second
+
+// startUpload starts the upload agent if [upload] is enabled.
+func (a *App) startUpload() {
+	if !a.cfg.UploadEnabled {
+		return
+	}
+	journal := upload.LoadJournal(a.cfg.UploadStateFile)
+	target, err := upload.New(upload.Options{
+		Kind:      a.cfg.UploadTarget,
+		URL:       a.cfg.UploadURL,
+		Username:  a.cfg.UploadUsername,
+		Password:  a.cfg.UploadPassword,
+		AccessKey: a.cfg.UploadAccessKey,
+		SecretKey: a.cfg.UploadSecretKey,
+		Region:    a.cfg.UploadRegion,
+		Identity:  a.cfg.UploadIdentity,
+		RateLimit: int64(a.cfg.UploadRateKB) * 1000,
+	}, journal)
+	if err != nil {
+		log.Printf("[Upload] WARNING: uploads disabled: %v", err)
+		return
+	}
+	network := "any network"
+	if a.cfg.UploadSSID != "" {
+		network = "WiFi " + a.cfg.UploadSSID
+	}
+	log.Printf("[Upload] Uploading to %s (%s) on %s, only protected recordings: %v",
+		a.cfg.UploadTarget, target.Host(), network, a.cfg.UploadOnlyProtected)
+
+	// Shutdown aborts a transfer in progress; it resumes next run
+	go a.runUploads(a.ctx, target, journal)
+}
+
+// runUploads checks for the network every interval and uploads while
+// it is there.
+func (a *App) runUploads(ctx context.Context, target upload.Target, journal *upload.Journal) {
+	ticker := time.NewTicker(secondsToDuration(a.cfg.UploadIntervalSec))
+	defer ticker.Stop()
+	online := false
+	for {
+		if now := a.uploadOnline(target); now != online {
+			online = now
+			if online {
+				log.Printf("[Upload] Upload network available")
+			} else {
+				log.Printf("[Upload] Upload network gone")
+			}
+		}
+		if online {
+			a.uploadPending(ctx, target, journal)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// uploadOnline reports whether the upload network is there.
+func (a *App) uploadOnline(target upload.Target) bool {
+	if a.cfg.UploadSSID != "" {
+		ssid, err := upload.CurrentSSID()
+		if err != nil || ssid != a.cfg.UploadSSID {
+			return false
+		}
+	}
+	host := a.cfg.UploadCheckHost
+	if host == "" {
+		host = target.Host()
+	}
+	return upload.Reachable(host, uploadReachTimeout)
+}
+
+// uploadFile is a local recording and its remote name.
+type uploadFile struct {
+	name      string // "clips/<file>"
+	path      string
+	protected bool
+}
+
+// uploadFiles lists the recordings in dirs (Name is the remote prefix),
+// oldest first. .keep markers are left out. A directory listed twice
+// (snapshot_dir = clips_dir) is listed under its first name only, so
+// its files aren't sent twice.
+func uploadFiles(dirs []storage.Dir) []uploadFile {
+	var files []uploadFile
+	seen := make(map[string]bool)
+	for _, d := range dirs {
+		clean := filepath.Clean(d.Path)
+		if d.Path == "" || seen[clean] {
+			continue
+		}
+		seen[clean] = true
+		entries, _ := os.ReadDir(d.Path)
+		for _, e := range entries {
+			if !e.Type().IsRegular() || strings.HasSuffix(e.Name(), storage.KeepExt) {
+				continue
+			}
+			path := filepath.Join(d.Path, e.Name())
+			files = append(files, uploadFile{name: d.Name + "/" + e.Name(), path: path, protected: storage.IsProtected(path)})
+		}
+	}
+	// Names start with the save time
+	sort.Slice(files, func(i, j int) bool { return filepath.Base(files[i].name) < filepath.Base(files[j].name) })
+	return files
+}
+
+// uploadPending sends every recording not uploaded yet, stopping at the
+// first failure (usually the network going away).
+func (a *App) uploadPending(ctx context.Context, target upload.Target, journal *upload.Journal) {
+	if a.pendingWrites.Load() > 0 {
+		return // Next round, once the recording is complete
+	}
+	files := uploadFiles([]storage.Dir{{Name: "snapshots", Path: a.cfg.SnapshotDir}, {Name: "clips", Path: a.cfg.ClipsDir}})
+	present := make(map[string]bool, len(files))
+	for _, f := range files {
+		present[f.name] = true
+	}
+	journal.Forget(present)
+
+	sent := 0
+	for _, f := range files {
+		if a.cfg.UploadOnlyProtected && !f.protected {
+			continue
+		}
+		info, err := os.Stat(f.path)
+		if err != nil || journal.Uploaded(f.name, info) {
+			continue
+		}
+		start := time.Now()
+		if err := target.Upload(ctx, f.name, f.path); err != nil {
+			if ctx.Err() == nil {
+				log.Printf("[Upload] WARNING: %s: %v", f.name, err)
+			}
+			break
+		}
+		if err := journal.MarkUploaded(f.name, info); err != nil {
+			log.Printf("[Upload] WARNING: saving %s: %v", a.cfg.UploadStateFile, err)
+		}
+		log.Printf("[Upload] %s uploaded (%d KB in %.1fs)", f.name, info.Size()>>10, time.Since(start).Seconds())
+		sent++
+	}
+	if sent > 0 {
+		a.toast("upload", fmt.Sprintf("Uploaded %d recordings", sent), toastInfo)
+	}
+}
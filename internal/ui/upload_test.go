@@ -0,0 +1,48 @@
+package ui
+
+import (
+	"camera-dashboard-go/internal/storage"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestUploadFiles(t *testing.T) {
+	root := t.TempDir()
+	snaps, clips := filepath.Join(root, "snapshots"), filepath.Join(root, "clips")
+	for path, data := range map[string]string{
+		filepath.Join(snaps, "20240102_080000.000_video0.jpg"):   "jpg",
+		filepath.Join(clips, "20240101_120000.000_video2.mjpeg"): "mjpeg",
+		filepath.Join(clips, "20240101_120000.000_video2.json"):  "{}",
+	} {
+		os.MkdirAll(filepath.Dir(path), 0o755)
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := storage.Protect(filepath.Join(clips, "20240101_120000.000_video2.mjpeg")); err != nil {
+		t.Fatal(err)
+	}
+
+	files := uploadFiles([]storage.Dir{{Name: "snapshots", Path: snaps}, {Name: "clips", Path: clips}, {Name: "trips"}})
+	if len(files) != 3 {
+		t.Fatalf("uploadFiles() = %+v, want 3 files (no .keep marker)", files)
+	}
+	if files[0].name != "clips/20240101_120000.000_video2.json" || !files[0].protected {
+		t.Errorf("first = %+v, want the older, protected clip", files[0])
+	}
+	if last := files[2]; last.name != "snapshots/20240102_080000.000_video0.jpg" || last.protected {
+		t.Errorf("last = %+v, want the unprotected snapshot", last)
+	}
+}
+
+func TestUploadFiles_SharedDir(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "20240101_120000.000_video0.jpg"), []byte("jpg"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	files := uploadFiles([]storage.Dir{{Name: "snapshots", Path: dir}, {Name: "clips", Path: dir + "/"}})
+	if len(files) != 1 || files[0].name != "snapshots/20240101_120000.000_video0.jpg" {
+		t.Errorf("uploadFiles() = %+v, want the file once, as a snapshot", files)
+	}
+}
@@ -0,0 +1,210 @@
+package ui
+
+import (
+	"camera-dashboard-go/internal/camera"
+	"context"
+	"fmt"
+	"log"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
+)
+
+// =============================================================================
+// Correlated USB failures (hub / power events)
+// =============================================================================
+// Cameras behind one hub fail together when the hub browns out or
+// resets. When [usb] correlation_min_cameras cameras go stale within
+// correlation_window_sec, that is logged and published (MQTT event
+// "usb_incident") once, with the deepest hub they share, instead of as
+// unrelated camera failures. Cameras going stale while an incident is
+// open join it.
+//
+// With hub_power_cycle_cmd set (e.g. "uhubctl -l {hub} -a cycle"), an
+// incident runs it once, and cameras in the incident skip their own
+// restarts while the hub comes back (hotplug and the stale retry pick
+// them up afterwards). {hub} is the uhubctl location of the shared hub
+// ("1-1", or "1" for the root hub of bus 1). Cameras that went stale
+// before the incident was recognised may already have been restarted.
+// =============================================================================
+
+// hubCycleSettle is how long cameras wait for the hub to come back after
+// a power cycle before the stale detector retries them.
+const hubCycleSettle = 15 * time.Second
+
+// hubCycleTimeout bounds the power-cycle command.
+const hubCycleTimeout = 30 * time.Second
+
+// staleCorrelator groups stale events that happen close together.
+type staleCorrelator struct {
+	window  time.Duration
+	minCams int
+
+	recent        map[int]time.Time // Last stale event per camera
+	incident      []int             // Cameras in the open incident
+	incidentUntil time.Time         // Open incident ends without new events
+}
+
+func newStaleCorrelator(window time.Duration, minCams int) *staleCorrelator {
+	return &staleCorrelator{window: window, minCams: minCams, recent: make(map[int]time.Time)}
+}
+
+// record notes that cam went stale at now. It returns the cameras of a
+// new incident when this event opens one, and joined=true when cam is
+// part of an incident (new or already open).
+func (c *staleCorrelator) record(cam int, now time.Time) (opened []int, joined bool) {
+	for i, at := range c.recent {
+		if now.Sub(at) > c.window {
+			delete(c.recent, i)
+		}
+	}
+	c.recent[cam] = now
+
+	if now.Before(c.incidentUntil) {
+		if !containsInt(c.incident, cam) {
+			c.incident = append(c.incident, cam)
+		}
+		c.incidentUntil = now.Add(c.window)
+		return nil, true
+	}
+	if len(c.recent) < c.minCams {
+		return nil, false
+	}
+
+	c.incident = make([]int, 0, len(c.recent))
+	for i := range c.recent {
+		c.incident = append(c.incident, i)
+	}
+	sort.Ints(c.incident)
+	c.incidentUntil = now.Add(c.window)
+	return c.incident, true
+}
+
+func containsInt(list []int, v int) bool {
+	for _, x := range list {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
+
+// uhubctlLocation converts a sysfs hub name to uhubctl's -l form:
+// "usb1" (root hub) -> "1", "1-1.2" stays as it is.
+func uhubctlLocation(hub string) string {
+	if strings.HasPrefix(hub, "usb") {
+		return strings.TrimPrefix(hub, "usb")
+	}
+	return hub
+}
+
+// hubCycleArgs expands {hub} in the configured command and splits it
+// into arguments (no shell).
+func hubCycleArgs(cmd, hub string) ([]string, error) {
+	if strings.Contains(cmd, "{hub}") {
+		if hub == "" {
+			return nil, fmt.Errorf("no shared hub for {hub}")
+		}
+		cmd = strings.ReplaceAll(cmd, "{hub}", uhubctlLocation(hub))
+	}
+	args := strings.Fields(cmd)
+	if len(args) == 0 {
+		return nil, fmt.Errorf("empty command")
+	}
+	return args, nil
+}
+
+// handleCorrelatedStale feeds a stale camera to the correlator. It
+// returns true when a hub power cycle owns the camera's recovery, so the
+// caller must not restart it. Runs on the stale-detection goroutine.
+func (a *App) handleCorrelatedStale(camIndex int, now time.Time) bool {
+	if a.staleCorr == nil {
+		a.staleCorr = newStaleCorrelator(time.Duration(a.cfg.USBCorrelationSec*float64(time.Second)), a.cfg.USBCorrelationMin)
+	}
+	opened, joined := a.staleCorr.record(camIndex, now)
+	if opened != nil {
+		a.reportUSBIncident(opened, now)
+	}
+	if !joined || !now.Before(a.hubCycleUntil) {
+		return false
+	}
+	a.setRecovery(camIndex, recoveryStatus{action: "USB hub power cycle, retrying in", until: a.hubCycleUntil, holdOff: true})
+	return true
+}
+
+// reportUSBIncident logs and publishes one incident for cams and runs
+// the hub power cycle when configured and not in its cooldown.
+func (a *App) reportUSBIncident(cams []int, now time.Time) {
+	a.frameLock.RLock()
+	var topologies []*camera.USBTopology
+	ids := make([]string, 0, len(cams))
+	for _, i := range cams {
+		if i >= len(a.cameras) {
+			continue
+		}
+		ids = append(ids, a.cameras[i].DeviceID)
+		if a.cameras[i].HasDeviceNode() {
+			topologies = append(topologies, camera.ProbeUSBTopology(a.cameras[i].DevicePath))
+		} else {
+			topologies = append(topologies, nil) // Network/CSI camera: no shared hub
+		}
+	}
+	a.frameLock.RUnlock()
+	hub := camera.CommonHub(topologies)
+
+	where := "no shared hub"
+	if hub != "" {
+		where = "hub " + hub
+	}
+	log.Printf("[USB] %d cameras (%s) went stale within %.0fs: probable hub/power event (%s)",
+		len(ids), strings.Join(ids, ", "), a.cfg.USBCorrelationSec, where)
+
+	cycle := a.cfg.USBHubPowerCycleCmd != ""
+	if cycle && !a.lastHubCycle.IsZero() && now.Sub(a.lastHubCycle) < time.Duration(a.cfg.USBHubCycleCooldownSec*float64(time.Second)) {
+		log.Printf("[USB] Hub power cycle skipped: last one was %.0fs ago", now.Sub(a.lastHubCycle).Seconds())
+		cycle = false
+	}
+	var args []string
+	if cycle {
+		var err error
+		if args, err = hubCycleArgs(a.cfg.USBHubPowerCycleCmd, hub); err != nil {
+			log.Printf("[USB] Hub power cycle skipped: %v", err)
+			cycle = false
+		}
+	}
+
+	a.trip.usbIncident()
+	if a.mqttClient.Load() != nil {
+		a.publishJSON("event", map[string]interface{}{
+			"type":        "usb_incident",
+			"cameras":     ids,
+			"hub":         hub,
+			"power_cycle": cycle,
+			"timestamp":   now.Unix(),
+		}, false)
+	}
+
+	if !cycle {
+		return
+	}
+	a.lastHubCycle = now
+	a.hubCycleUntil = now.Add(hubCycleSettle)
+	for _, i := range cams {
+		a.setRecovery(i, recoveryStatus{action: "USB hub power cycle, retrying in", until: a.hubCycleUntil, holdOff: true})
+	}
+	go runHubPowerCycle(args)
+}
+
+// runHubPowerCycle runs the configured power-cycle command.
+func runHubPowerCycle(args []string) {
+	log.Printf("[USB] Power cycling hub: %s", strings.Join(args, " "))
+	ctx, cancel := context.WithTimeout(context.Background(), hubCycleTimeout)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, args[0], args[1:]...).CombinedOutput()
+	if err != nil {
+		log.Printf("[USB] WARNING: hub power cycle failed: %v: %s", err, strings.TrimSpace(string(out)))
+		return
+	}
+	log.Println("[USB] Hub power cycle done")
+}
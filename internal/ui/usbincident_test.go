@@ -0,0 +1,47 @@
+package ui
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestStaleCorrelator(t *testing.T) {
+	c := newStaleCorrelator(5*time.Second, 2)
+	start := time.Now()
+	at := func(sec float64) time.Time { return start.Add(time.Duration(sec * float64(time.Second))) }
+
+	if opened, joined := c.record(0, at(0)); opened != nil || joined {
+		t.Fatalf("single stale camera = (%v, %v), want no incident", opened, joined)
+	}
+	opened, joined := c.record(2, at(2))
+	if !joined || !reflect.DeepEqual(opened, []int{0, 2}) {
+		t.Fatalf("second camera = (%v, %v), want incident [0 2]", opened, joined)
+	}
+	// A third camera joins the open incident without reopening it
+	if opened, joined := c.record(1, at(4)); opened != nil || !joined {
+		t.Fatalf("third camera = (%v, %v), want joined", opened, joined)
+	}
+
+	// Long after, a lone stale camera is just a camera failure
+	if opened, joined := c.record(1, at(30)); opened != nil || joined {
+		t.Fatalf("later stale camera = (%v, %v), want no incident", opened, joined)
+	}
+}
+
+func TestHubCycleArgs(t *testing.T) {
+	got, err := hubCycleArgs("uhubctl -l {hub} -a cycle", "usb1")
+	if err != nil || !reflect.DeepEqual(got, []string{"uhubctl", "-l", "1", "-a", "cycle"}) {
+		t.Errorf("root hub: %v, %v", got, err)
+	}
+	got, err = hubCycleArgs("uhubctl -l {hub} -a cycle -p 2", "1-1.3")
+	if err != nil || got[2] != "1-1.3" {
+		t.Errorf("hub 1-1.3: %v, %v", got, err)
+	}
+	if _, err := hubCycleArgs("uhubctl -l {hub} -a cycle", ""); err == nil {
+		t.Error("{hub} without a shared hub should fail")
+	}
+	if got, err := hubCycleArgs("/usr/local/bin/reset-hub", ""); err != nil || len(got) != 1 {
+		t.Errorf("command without {hub}: %v, %v", got, err)
+	}
+}
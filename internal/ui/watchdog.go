@@ -0,0 +1,111 @@
+package ui
+
+import (
+	"camera-dashboard-go/internal/systemd"
+	"camera-dashboard-go/internal/watchdog"
+	"fmt"
+	"log"
+	"os"
+	"time"
+)
+
+// =============================================================================
+// Watchdog integration
+// =============================================================================
+// Supervised components:
+//   ui-refresh   - the camera refresh loop; critical, a hang restarts the
+//                  process (systemd restarts us if it's managing the
+//                  watchdog, otherwise we relaunch ourselves)
+//   capture-N    - each slot's capture goroutine; a hang restarts that
+//                  worker only, like stale-frame recovery
+// =============================================================================
+
+// startWatchdog registers heartbeats and starts supervision if enabled.
+func (a *App) startWatchdog() {
+	if !a.cfg.WatchdogEnabled {
+		return
+	}
+
+	a.watchdog = watchdog.New(watchdog.Options{
+		CheckInterval: secondsToDuration(a.cfg.WatchdogCheckIntervalSec),
+		MaxRecoveries: a.cfg.WatchdogMaxRecoveries,
+		OnFatal:       a.onWatchdogFatal,
+	})
+
+	a.watchdog.Watch(watchdog.Component{
+		Name:     "ui-refresh",
+		Timeout:  secondsToDuration(a.cfg.WatchdogUITimeoutSec),
+		LastBeat: a.uiHeartbeat.Last,
+		Critical: true,
+	})
+
+	captureTimeout := secondsToDuration(a.cfg.WatchdogCaptureTimeoutSec)
+	for i := 0; i < a.effectiveSlots(); i++ {
+		idx := i
+		a.watchdog.Watch(watchdog.Component{
+			Name:     fmt.Sprintf("capture-%d", idx),
+			Timeout:  captureTimeout,
+			LastBeat: func() time.Time { return a.captureHeartbeat(idx) },
+			Recover:  func() { a.recoverHungCapture(idx) },
+		})
+	}
+
+	a.watchdog.Start()
+	log.Printf("[Watchdog] Supervising UI refresh + %d capture slots", a.effectiveSlots())
+}
+
+// captureHeartbeat returns the capture goroutine heartbeat for a slot,
+// or the zero Time when no worker is running there.
+func (a *App) captureHeartbeat(camIndex int) time.Time {
+	manager := a.manager.Load()
+	if manager == nil {
+		return time.Time{}
+	}
+	a.frameLock.RLock()
+	if camIndex >= len(a.cameras) {
+		a.frameLock.RUnlock()
+		return time.Time{}
+	}
+	cameraID := a.cameras[camIndex].DeviceID
+	a.frameLock.RUnlock()
+
+	worker := manager.GetWorker(cameraID)
+	if worker == nil {
+		return time.Time{}
+	}
+	return worker.LastHeartbeat()
+}
+
+// recoverHungCapture restarts a capture worker whose goroutine stopped
+// looping. Restart kills FFmpeg first, which unblocks a stuck pipe read.
+func (a *App) recoverHungCapture(camIndex int) {
+	manager := a.manager.Load()
+	if manager == nil {
+		return
+	}
+	a.publishRestartEvent(camIndex, "watchdog")
+	if err := manager.RestartCameraByIndex(camIndex); err != nil {
+		log.Printf("[Watchdog] Camera %d: failed to restart: %v", camIndex, err)
+	}
+}
+
+// onWatchdogFatal restarts the process after a critical component hung.
+func (a *App) onWatchdogFatal(component string) {
+	if systemd.WatchdogEnabled() {
+		// Pings have stopped; systemd will kill and restart the unit
+		log.Printf("[Watchdog] %s hung, waiting for systemd to restart the service", component)
+		return
+	}
+
+	// A wedged UI may never let Quit return, so don't wait forever
+	go func() {
+		time.Sleep(10 * time.Second)
+		log.Println("[Watchdog] CRITICAL: restart did not complete, exiting")
+		os.Exit(1)
+	}()
+	a.relaunch()
+}
+
+func secondsToDuration(sec float64) time.Duration {
+	return time.Duration(sec * float64(time.Second))
+}
@@ -0,0 +1,142 @@
+package upload
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Journal remembers which files were uploaded and the state of
+// unfinished S3 multipart uploads. It is saved as JSON after every
+// change, so a restart neither uploads a file twice nor starts an
+// interrupted one over.
+type Journal struct {
+	mu   sync.Mutex
+	path string // "" = in memory only
+	data journalData
+}
+
+type journalData struct {
+	Done    map[string]doneEntry       `json:"done"`
+	Partial map[string]*multipartState `json:"partial,omitempty"`
+}
+
+// doneEntry identifies the local file a remote name was uploaded from.
+type doneEntry struct {
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"mod_time"`
+}
+
+// multipartState is an unfinished S3 multipart upload.
+type multipartState struct {
+	UploadID string   `json:"upload_id"`
+	Size     int64    `json:"size"`  // Local size when started; a changed file starts over
+	ETags    []string `json:"etags"` // Finished parts, in order
+}
+
+// LoadJournal reads the journal at path; a missing or unreadable file
+// gives an empty one.
+func LoadJournal(path string) *Journal {
+	j := &Journal{path: path}
+	if path != "" {
+		if data, err := os.ReadFile(path); err == nil {
+			json.Unmarshal(data, &j.data)
+		}
+	}
+	if j.data.Done == nil {
+		j.data.Done = make(map[string]doneEntry)
+	}
+	if j.data.Partial == nil {
+		j.data.Partial = make(map[string]*multipartState)
+	}
+	return j
+}
+
+// Uploaded reports whether name was uploaded from a file of this size
+// and modification time.
+func (j *Journal) Uploaded(name string, info os.FileInfo) bool {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	e, ok := j.data.Done[name]
+	return ok && e.Size == info.Size() && e.ModTime.Equal(info.ModTime())
+}
+
+// MarkUploaded records a finished upload.
+func (j *Journal) MarkUploaded(name string, info os.FileInfo) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.data.Done[name] = doneEntry{Size: info.Size(), ModTime: info.ModTime()}
+	delete(j.data.Partial, name)
+	return j.saveLocked()
+}
+
+// Forget drops entries whose names keep doesn't list (local files that
+// are gone), and saves if anything changed.
+func (j *Journal) Forget(keep map[string]bool) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	changed := false
+	for name := range j.data.Done {
+		if !keep[name] {
+			delete(j.data.Done, name)
+			changed = true
+		}
+	}
+	for name := range j.data.Partial {
+		if !keep[name] {
+			delete(j.data.Partial, name)
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+	return j.saveLocked()
+}
+
+// partial returns a copy of name's multipart state, or nil.
+func (j *Journal) partial(name string) *multipartState {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	st := j.data.Partial[name]
+	if st == nil {
+		return nil
+	}
+	c := *st
+	c.ETags = append([]string(nil), st.ETags...)
+	return &c
+}
+
+// setPartial saves name's multipart state (nil = none).
+func (j *Journal) setPartial(name string, st *multipartState) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if st == nil {
+		delete(j.data.Partial, name)
+	} else {
+		c := *st
+		c.ETags = append([]string(nil), st.ETags...)
+		j.data.Partial[name] = &c
+	}
+	return j.saveLocked()
+}
+
+func (j *Journal) saveLocked() error {
+	if j.path == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(j.data, "", "  ")
+	if err != nil {
+		return err
+	}
+	if dir := filepath.Dir(j.path); dir != "" {
+		os.MkdirAll(dir, 0o755)
+	}
+	tmp := j.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, j.path)
+}
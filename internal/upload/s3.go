@@ -0,0 +1,283 @@
+package upload
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// =============================================================================
+// S3-compatible target
+// =============================================================================
+// Path-style requests (https://host/bucket/prefix/name, which AWS, MinIO,
+// Backblaze B2 and Wasabi all accept) signed with AWS Signature V4. File
+// bodies are sent as UNSIGNED-PAYLOAD, so they are streamed rather than
+// hashed first; use https.
+// =============================================================================
+
+const (
+	s3PartSize       = 8 << 20 // Multipart above this; S3's minimum part is 5 MB
+	unsignedPayload  = "UNSIGNED-PAYLOAD"
+	maxResponseBytes = 64 << 10
+)
+
+type s3Target struct {
+	endpoint  *url.URL // https://host/bucket[/prefix]
+	accessKey string
+	secretKey string
+	region    string
+	rate      int64
+	journal   *Journal
+	client    *http.Client
+	now       func() time.Time
+}
+
+func newS3(u *url.URL, opts Options, journal *Journal) *s3Target {
+	region := opts.Region
+	if region == "" {
+		region = "us-east-1"
+	}
+	return &s3Target{
+		endpoint:  u,
+		accessKey: opts.AccessKey,
+		secretKey: opts.SecretKey,
+		region:    region,
+		rate:      opts.RateLimit,
+		journal:   journal,
+		client:    httpClient(),
+		now:       time.Now,
+	}
+}
+
+func (t *s3Target) Host() string { return hostPort(t.endpoint) }
+
+// Upload puts small files in one request and larger ones as a multipart
+// upload resumed from the journal.
+func (t *s3Target) Upload(ctx context.Context, name, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	size := info.Size()
+
+	if size <= s3PartSize {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPut, t.objectURL(name, nil), sectionReader(f, 0, size, t.rate))
+		if err != nil {
+			return err
+		}
+		req.ContentLength = size
+		_, _, err = t.do(req, unsignedPayload)
+		return err
+	}
+
+	st := t.journal.partial(name)
+	if st == nil || st.Size != size {
+		id, err := t.createMultipart(ctx, name)
+		if err != nil {
+			return err
+		}
+		st = &multipartState{UploadID: id, Size: size}
+		t.journal.setPartial(name, st)
+	}
+	parts := int((size + s3PartSize - 1) / s3PartSize)
+	for n := len(st.ETags) + 1; n <= parts; n++ {
+		start := int64(n-1) * s3PartSize
+		end := start + s3PartSize
+		if end > size {
+			end = size
+		}
+		query := url.Values{"partNumber": {strconv.Itoa(n)}, "uploadId": {st.UploadID}}
+		req, err := http.NewRequestWithContext(ctx, http.MethodPut, t.objectURL(name, query), sectionReader(f, start, end, t.rate))
+		if err != nil {
+			return err
+		}
+		req.ContentLength = end - start
+		header, _, err := t.do(req, unsignedPayload)
+		if err != nil {
+			if strings.Contains(err.Error(), "NoSuchUpload") {
+				t.journal.setPartial(name, nil) // Expired or aborted: start over next time
+			}
+			return fmt.Errorf("part %d/%d: %w", n, parts, err)
+		}
+		st.ETags = append(st.ETags, header.Get("ETag"))
+		t.journal.setPartial(name, st)
+	}
+	return t.completeMultipart(ctx, name, st)
+}
+
+func (t *s3Target) createMultipart(ctx context.Context, name string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.objectURL(name, url.Values{"uploads": {""}}), nil)
+	if err != nil {
+		return "", err
+	}
+	_, body, err := t.do(req, hexSHA256(nil))
+	if err != nil {
+		return "", err
+	}
+	var result struct {
+		UploadID string `xml:"UploadId"`
+	}
+	if err := xml.Unmarshal(body, &result); err != nil || result.UploadID == "" {
+		return "", fmt.Errorf("no UploadId in %q", body)
+	}
+	return result.UploadID, nil
+}
+
+func (t *s3Target) completeMultipart(ctx context.Context, name string, st *multipartState) error {
+	var buf bytes.Buffer
+	buf.WriteString("<CompleteMultipartUpload>")
+	for i, etag := range st.ETags {
+		fmt.Fprintf(&buf, "<Part><PartNumber>%d</PartNumber><ETag>%s</ETag></Part>", i+1, xmlEscape(etag))
+	}
+	buf.WriteString("</CompleteMultipartUpload>")
+	payload := buf.Bytes()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.objectURL(name, url.Values{"uploadId": {st.UploadID}}), bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/xml")
+	_, _, err = t.do(req, hexSHA256(payload))
+	return err
+}
+
+// objectURL is the URL of name under the endpoint.
+func (t *s3Target) objectURL(name string, query url.Values) string {
+	u := *t.endpoint
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/" + name
+	u.RawPath = ""
+	u.RawQuery = canonicalQuery(query)
+	return u.String()
+}
+
+// do signs and sends req. S3 can report errors in a 200 response, so
+// the body is checked too.
+func (t *s3Target) do(req *http.Request, payloadHash string) (http.Header, []byte, error) {
+	t.sign(req, payloadHash, t.now())
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+	if resp.StatusCode >= 300 || bytes.Contains(body, []byte("<Error>")) {
+		var e struct {
+			Code    string `xml:"Code"`
+			Message string `xml:"Message"`
+		}
+		xml.Unmarshal(body, &e)
+		return nil, nil, fmt.Errorf("s3 %s: %s %s %s", req.Method, resp.Status, e.Code, e.Message)
+	}
+	return resp.Header, body, nil
+}
+
+// sign adds AWS Signature V4 headers, signing every header set on req.
+func (t *s3Target) sign(req *http.Request, payloadHash string, now time.Time) {
+	amzDate := now.UTC().Format("20060102T150405Z")
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	host := req.Host
+	if host == "" {
+		host = req.URL.Host
+	}
+	names := []string{"host"}
+	values := map[string]string{"host": host}
+	for k, v := range req.Header {
+		name := strings.ToLower(k)
+		names = append(names, name)
+		values[name] = strings.TrimSpace(strings.Join(v, ","))
+	}
+	sort.Strings(names)
+	var headers strings.Builder
+	for _, n := range names {
+		headers.WriteString(n + ":" + values[n] + "\n")
+	}
+	signed := strings.Join(names, ";")
+
+	canonical := strings.Join([]string{
+		req.Method,
+		awsEscape(req.URL.Path, false),
+		canonicalQuery(req.URL.Query()),
+		headers.String(),
+		signed,
+		payloadHash,
+	}, "\n")
+	scope := amzDate[:8] + "/" + t.region + "/s3/aws4_request"
+	toSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hexSHA256([]byte(canonical))
+
+	key := hmacSHA256([]byte("AWS4"+t.secretKey), amzDate[:8])
+	for _, part := range []string{t.region, "s3", "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	signature := hex.EncodeToString(hmacSHA256(key, toSign))
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s,SignedHeaders=%s,Signature=%s",
+		t.accessKey, scope, signed, signature))
+}
+
+// canonicalQuery encodes query sorted by key, escaped the SigV4 way.
+func canonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for k := range query {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var parts []string
+	for _, k := range keys {
+		for _, v := range query[k] {
+			parts = append(parts, awsEscape(k, true)+"="+awsEscape(v, true))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+// awsEscape percent-encodes everything but unreserved characters (and
+// '/' unless escapeSlash).
+func awsEscape(s string, escapeSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9',
+			c == '-', c == '.', c == '_', c == '~', c == '/' && !escapeSlash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+func hexSHA256(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+func xmlEscape(s string) string {
+	var buf bytes.Buffer
+	xml.EscapeText(&buf, []byte(s))
+	return buf.String()
+}
@@ -0,0 +1,65 @@
+package upload
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/url"
+	"os/exec"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// sftpTarget runs OpenSSH's sftp in batch mode. Authentication is by
+// key only (BatchMode), and the host key must already be known.
+type sftpTarget struct {
+	url      *url.URL // sftp://user@host[:port]/path
+	identity string
+	rate     int64
+}
+
+func (t *sftpTarget) Host() string { return hostPort(t.url) }
+
+// Upload resumes a partial remote file with put -a.
+func (t *sftpTarget) Upload(ctx context.Context, name, localPath string) error {
+	remote := path.Join("/", t.url.Path, name)
+	cmd := exec.CommandContext(ctx, "sftp", t.args()...)
+	cmd.Stdin = strings.NewReader(sftpBatch(path.Dir(remote), localPath, remote))
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("sftp: %v: %s", err, bytes.TrimSpace(out))
+	}
+	return nil
+}
+
+func (t *sftpTarget) args() []string {
+	args := []string{"-b", "-", "-o", "BatchMode=yes", "-o", "ConnectTimeout=10"}
+	if port := t.url.Port(); port != "" {
+		args = append(args, "-P", port)
+	}
+	if t.identity != "" {
+		args = append(args, "-i", t.identity)
+	}
+	if t.rate > 0 {
+		kbit := t.rate * 8 / 1000
+		if kbit < 1 {
+			kbit = 1
+		}
+		args = append(args, "-l", strconv.FormatInt(kbit, 10))
+	}
+	host := t.url.Hostname()
+	if t.url.User != nil {
+		host = t.url.User.Username() + "@" + host
+	}
+	return append(args, host)
+}
+
+// sftpBatch creates the remote directory (a leading "-" ignores the
+// error when it exists) and resumes the upload.
+func sftpBatch(dir, local, remote string) string {
+	return fmt.Sprintf("-mkdir %s\nput -a %s %s\n", sftpQuote(dir), sftpQuote(local), sftpQuote(remote))
+}
+
+func sftpQuote(s string) string {
+	return `"` + strings.ReplaceAll(strings.ReplaceAll(s, `\`, `\\`), `"`, `\"`) + `"`
+}
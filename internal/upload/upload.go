@@ -0,0 +1,168 @@
+// Package upload copies recordings to a remote target (S3-compatible
+// object storage, WebDAV or SFTP) with resumable, rate-limited transfers.
+package upload
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// =============================================================================
+// Remote upload
+// =============================================================================
+// A Target sends one local file to a remote name ("clips/<file>"),
+// picking up an interrupted transfer where the backend allows it:
+//
+//   s3      multipart upload (8 MB parts); the upload ID and finished
+//           parts are kept in the Journal, so a transfer cut off by
+//           leaving WiFi continues with the next part
+//   webdav  HTTP PUT; a partial remote file is completed with a ranged
+//           PUT where the server accepts one (Apache mod_dav), else the
+//           file is sent again
+//   sftp    OpenSSH `sftp` in batch mode, `put -a` resumes
+//
+// Uploads are throttled to a rate in bytes per second (0 = unlimited).
+// =============================================================================
+
+// Options configures a target.
+type Options struct {
+	Kind      string // "s3", "webdav" or "sftp"
+	URL       string // s3/webdav: https://host/bucket-or-path[/prefix], sftp: sftp://user@host[:port]/path
+	Username  string // webdav Basic auth
+	Password  string
+	AccessKey string // s3
+	SecretKey string
+	Region    string // s3
+	Identity  string // sftp private key file ("" = ssh defaults)
+	RateLimit int64  // Bytes per second, 0 = unlimited
+}
+
+// Target uploads files.
+type Target interface {
+	// Upload sends the local file path as name.
+	Upload(ctx context.Context, name, path string) error
+	// Host is the target's host:port, for reachability checks.
+	Host() string
+}
+
+// New returns the target for opts, keeping resume state in journal.
+func New(opts Options, journal *Journal) (Target, error) {
+	u, err := url.Parse(opts.URL)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("invalid url %q", opts.URL)
+	}
+	switch opts.Kind {
+	case "s3":
+		if opts.AccessKey == "" || opts.SecretKey == "" {
+			return nil, fmt.Errorf("s3 needs access_key and secret_key")
+		}
+		return newS3(u, opts, journal), nil
+	case "webdav":
+		return &webdavTarget{base: u, user: opts.Username, password: opts.Password, rate: opts.RateLimit, client: httpClient()}, nil
+	case "sftp":
+		if u.Scheme != "sftp" {
+			return nil, fmt.Errorf("sftp url must start with sftp://")
+		}
+		return &sftpTarget{url: u, identity: opts.Identity, rate: opts.RateLimit}, nil
+	}
+	return nil, fmt.Errorf("unknown target %q (want s3, webdav or sftp)", opts.Kind)
+}
+
+// hostPort returns u's host with the scheme's default port.
+func hostPort(u *url.URL) string {
+	if u.Port() != "" {
+		return u.Host
+	}
+	port := map[string]string{"http": "80", "https": "443", "sftp": "22"}[u.Scheme]
+	if port == "" {
+		port = "443"
+	}
+	return net.JoinHostPort(u.Hostname(), port)
+}
+
+// =============================================================================
+// Connectivity
+// =============================================================================
+
+// CurrentSSID returns the WiFi network the host is connected to, via
+// iwgetid (wireless-tools).
+func CurrentSSID() (string, error) {
+	out, err := exec.Command("iwgetid", "-r").Output()
+	if err != nil {
+		return "", fmt.Errorf("iwgetid: %w", err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// Reachable reports whether a TCP connection to addr (host:port) can be
+// opened within timeout.
+func Reachable(addr string, timeout time.Duration) bool {
+	conn, err := net.DialTimeout("tcp", addr, timeout)
+	if err != nil {
+		return false
+	}
+	conn.Close()
+	return true
+}
+
+// =============================================================================
+// Rate limiting
+// =============================================================================
+
+// rateReader throttles reads to bps bytes per second on average.
+type rateReader struct {
+	r     io.Reader
+	bps   int64
+	start time.Time
+	n     int64
+	now   func() time.Time
+	sleep func(time.Duration)
+}
+
+// limitReader wraps r at bps bytes per second (0 = r unchanged).
+func limitReader(r io.Reader, bps int64) io.Reader {
+	if bps <= 0 {
+		return r
+	}
+	return &rateReader{r: r, bps: bps, now: time.Now, sleep: time.Sleep}
+}
+
+func (rr *rateReader) Read(p []byte) (int, error) {
+	if rr.start.IsZero() {
+		rr.start = rr.now()
+	}
+	if max := rr.bps / 4; max > 0 && int64(len(p)) > max {
+		p = p[:max] // At most a quarter second's worth per read
+	}
+	n, err := rr.r.Read(p)
+	rr.n += int64(n)
+	due := time.Duration(float64(rr.n) / float64(rr.bps) * float64(time.Second))
+	if ahead := due - rr.now().Sub(rr.start); ahead > 0 {
+		rr.sleep(ahead)
+	}
+	return n, err
+}
+
+// sectionReader returns bytes start..end of f, throttled.
+func sectionReader(f *os.File, start, end, bps int64) io.Reader {
+	return limitReader(io.NewSectionReader(f, start, end-start), bps)
+}
+
+// httpClient has no overall timeout (a throttled upload can take long)
+// but gives up on unresponsive servers.
+func httpClient() *http.Client {
+	return &http.Client{Transport: &http.Transport{
+		Proxy:                 http.ProxyFromEnvironment,
+		DialContext:           (&net.Dialer{Timeout: 10 * time.Second}).DialContext,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ResponseHeaderTimeout: 60 * time.Second,
+	}}
+}
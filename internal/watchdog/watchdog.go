@@ -0,0 +1,204 @@
+// Package watchdog supervises long-running goroutines via heartbeats and
+// keeps systemd's WatchdogSec= timer fed while they are healthy.
+package watchdog
+
+import (
+	"camera-dashboard-go/internal/systemd"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// =============================================================================
+// Liveness supervisor
+// =============================================================================
+// Components (UI refresh loop, capture workers, ...) report liveness via a
+// heartbeat. Every CheckInterval the watchdog compares each heartbeat's age
+// with the component's Timeout:
+//   - Hung with a Recover func: call it (restart the subsystem), at most
+//     MaxRecoveries times per hang, one Timeout apart
+//   - Hung and Critical with no recovery left: stop pinging systemd and
+//     call OnFatal (restart the process)
+//
+// Frame staleness is handled separately by the UI's stale detection; this
+// catches goroutines that stop looping altogether (blocked pipe reads,
+// deadlocks, a wedged Refresh).
+// =============================================================================
+
+// processStart anchors Heartbeat timestamps on the monotonic clock.
+var processStart = time.Now()
+
+// Heartbeat is a lock-free liveness marker updated by a supervised loop.
+type Heartbeat struct {
+	last atomic.Int64 // Monotonic nanos since processStart; 0 = never beat
+}
+
+// Beat records that the owner is alive. Safe to call from any goroutine.
+func (h *Heartbeat) Beat() {
+	n := int64(time.Since(processStart))
+	if n <= 0 {
+		n = 1
+	}
+	h.last.Store(n)
+}
+
+// Last returns the time of the most recent Beat, or the zero Time if
+// Beat has never been called.
+func (h *Heartbeat) Last() time.Time {
+	n := h.last.Load()
+	if n == 0 {
+		return time.Time{}
+	}
+	return processStart.Add(time.Duration(n))
+}
+
+// Component describes one supervised subsystem.
+type Component struct {
+	Name     string
+	Timeout  time.Duration    // Heartbeat age after which the component is hung
+	LastBeat func() time.Time // Zero Time means "not running" and is not checked
+	Recover  func()           // Optional: restart the subsystem (run in its own goroutine)
+	Critical bool             // Escalate to OnFatal when recovery is unavailable or exhausted
+}
+
+// Options configures the watchdog.
+type Options struct {
+	CheckInterval time.Duration     // How often heartbeats are checked (default 2s)
+	MaxRecoveries int               // Recover attempts per hang before escalating (default 3)
+	OnFatal       func(name string) // Called once when a critical component can't be recovered
+}
+
+type watched struct {
+	Component
+	hung        bool
+	recoveries  int
+	lastRecover time.Time
+	recovering  atomic.Bool
+}
+
+// Watchdog checks component heartbeats and feeds the systemd watchdog.
+type Watchdog struct {
+	opts       Options
+	mu         sync.Mutex
+	components []*watched
+	fatal      atomic.Bool
+	stopCh     chan struct{}
+	stopOnce   sync.Once
+	wg         sync.WaitGroup
+}
+
+// New creates a watchdog. Call Watch to add components, then Start.
+func New(opts Options) *Watchdog {
+	if opts.CheckInterval <= 0 {
+		opts.CheckInterval = 2 * time.Second
+	}
+	if opts.MaxRecoveries <= 0 {
+		opts.MaxRecoveries = 3
+	}
+	return &Watchdog{
+		opts:   opts,
+		stopCh: make(chan struct{}),
+	}
+}
+
+// Watch adds a component to supervise. Safe to call after Start.
+func (w *Watchdog) Watch(c Component) {
+	w.mu.Lock()
+	w.components = append(w.components, &watched{Component: c})
+	w.mu.Unlock()
+}
+
+// Start begins supervising. Readiness is reported by the caller once
+// startup is done (systemd.Ready).
+func (w *Watchdog) Start() {
+	interval := w.opts.CheckInterval
+	if sd := systemd.WatchdogInterval(); sd > 0 {
+		// Ping at least twice per WatchdogSec, as sd_watchdog_enabled(3) advises
+		if sd/2 < interval {
+			interval = sd / 2
+		}
+		log.Printf("[Watchdog] systemd watchdog enabled (timeout %v)", sd)
+	}
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-w.stopCh:
+				return
+			case now := <-ticker.C:
+				if w.check(now) {
+					systemd.Watchdog()
+				}
+			}
+		}
+	}()
+}
+
+// Stop ends supervision.
+func (w *Watchdog) Stop() {
+	w.stopOnce.Do(func() {
+		close(w.stopCh)
+		w.wg.Wait()
+	})
+}
+
+// check evaluates every component at now and returns whether the
+// process is healthy enough to keep feeding the systemd watchdog.
+func (w *Watchdog) check(now time.Time) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for _, c := range w.components {
+		last := c.LastBeat()
+		if last.IsZero() || now.Sub(last) <= c.Timeout {
+			if c.hung {
+				log.Printf("[Watchdog] %s: heartbeat resumed", c.Name)
+			}
+			c.hung = false
+			c.recoveries = 0
+			c.lastRecover = time.Time{}
+			continue
+		}
+
+		age := now.Sub(last)
+		if !c.hung {
+			log.Printf("[Watchdog] WARNING: %s: no heartbeat for %.1fs (timeout %.1fs)",
+				c.Name, age.Seconds(), c.Timeout.Seconds())
+			c.hung = true
+		}
+
+		// Give each recovery a full Timeout to take effect
+		if !c.lastRecover.IsZero() && now.Sub(c.lastRecover) < c.Timeout {
+			continue
+		}
+
+		if c.Recover != nil && c.recoveries < w.opts.MaxRecoveries {
+			if !c.recovering.CompareAndSwap(false, true) {
+				continue // Previous Recover still running
+			}
+			c.recoveries++
+			c.lastRecover = now
+			log.Printf("[Watchdog] %s: recovery attempt %d/%d", c.Name, c.recoveries, w.opts.MaxRecoveries)
+			go func(c *watched) {
+				defer c.recovering.Store(false)
+				c.Recover()
+			}(c)
+			continue
+		}
+
+		if c.Critical && !w.fatal.Load() {
+			w.fatal.Store(true)
+			log.Printf("[Watchdog] CRITICAL: %s hung for %.1fs and could not be recovered, restarting process",
+				c.Name, age.Seconds())
+			if w.opts.OnFatal != nil {
+				go w.opts.OnFatal(c.Name)
+			}
+		}
+	}
+
+	return !w.fatal.Load()
+}
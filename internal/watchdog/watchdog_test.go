@@ -0,0 +1,111 @@
+package watchdog
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestHeartbeat(t *testing.T) {
+	var hb Heartbeat
+	if !hb.Last().IsZero() {
+		t.Fatal("Last() before Beat should be zero")
+	}
+	hb.Beat()
+	if age := time.Since(hb.Last()); age < 0 || age > time.Second {
+		t.Errorf("heartbeat age = %v, want within [0, 1s]", age)
+	}
+}
+
+func TestCheck_SkipsComponentsThatNeverBeat(t *testing.T) {
+	w := New(Options{})
+	w.Watch(Component{
+		Name:     "idle",
+		Timeout:  time.Second,
+		LastBeat: func() time.Time { return time.Time{} },
+		Critical: true,
+	})
+	if !w.check(time.Now().Add(time.Hour)) {
+		t.Error("component that never beat should not be treated as hung")
+	}
+}
+
+func TestCheck_RecoversThenGivesUp(t *testing.T) {
+	var recovered atomic.Int32
+	last := time.Now()
+	w := New(Options{MaxRecoveries: 2})
+	w.Watch(Component{
+		Name:     "capture-0",
+		Timeout:  time.Second,
+		LastBeat: func() time.Time { return last },
+		Recover:  func() { recovered.Add(1) },
+	})
+
+	waitRecovered := func(want int32) {
+		t.Helper()
+		deadline := time.Now().Add(time.Second)
+		for recovered.Load() != want && time.Now().Before(deadline) {
+			time.Sleep(time.Millisecond)
+		}
+		if got := recovered.Load(); got != want {
+			t.Fatalf("recoveries = %d, want %d", got, want)
+		}
+	}
+
+	now := last.Add(2 * time.Second)
+	if !w.check(now) {
+		t.Error("non-critical hang should not stop systemd pings")
+	}
+	waitRecovered(1)
+
+	// Within Timeout of the last attempt: wait for it to take effect
+	w.check(now.Add(500 * time.Millisecond))
+	waitRecovered(1)
+
+	w.check(now.Add(2 * time.Second))
+	waitRecovered(2)
+
+	// MaxRecoveries reached
+	w.check(now.Add(4 * time.Second))
+	time.Sleep(10 * time.Millisecond)
+	waitRecovered(2)
+
+	// A fresh heartbeat resets the budget
+	last = now.Add(5 * time.Second)
+	w.check(last)
+	w.check(last.Add(2 * time.Second))
+	waitRecovered(3)
+}
+
+func TestCheck_CriticalHangIsFatal(t *testing.T) {
+	fatal := make(chan string, 1)
+	last := time.Now()
+	w := New(Options{OnFatal: func(name string) { fatal <- name }})
+	w.Watch(Component{
+		Name:     "ui-refresh",
+		Timeout:  time.Second,
+		LastBeat: func() time.Time { return last },
+		Critical: true,
+	})
+
+	if !w.check(last.Add(500 * time.Millisecond)) {
+		t.Fatal("healthy component reported unhealthy")
+	}
+	if w.check(last.Add(2 * time.Second)) {
+		t.Error("critical hang should stop systemd pings")
+	}
+	select {
+	case name := <-fatal:
+		if name != "ui-refresh" {
+			t.Errorf("OnFatal(%q), want ui-refresh", name)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("OnFatal not called")
+	}
+
+	// Fatal is latched even if the heartbeat comes back
+	last = last.Add(3 * time.Second)
+	if w.check(last) {
+		t.Error("watchdog should stay fatal once escalated")
+	}
+}
@@ -1,15 +1,24 @@
 package main
 
 import (
+	"camera-dashboard-go/internal/camera"
+	"camera-dashboard-go/internal/cli"
 	"camera-dashboard-go/internal/config"
+	"camera-dashboard-go/internal/helpers"
+	"camera-dashboard-go/internal/perf"
 	"camera-dashboard-go/internal/ui"
+	"encoding/json"
 	"flag"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"runtime"
+	"strings"
 	"syscall"
+	"time"
 )
 
 // Version information - set by linker flags during build
@@ -20,26 +29,169 @@ var (
 )
 
 func main() {
-	// Command line flags
-	showVersion := flag.Bool("version", false, "Show version information")
-	flag.BoolVar(showVersion, "v", false, "Show version information (shorthand)")
-	configPath := flag.String("config", "", "Path to config.ini (default: ./config.ini or $CAMERA_DASHBOARD_CONFIG)")
-	flag.Parse()
-
-	if *showVersion {
-		fmt.Printf("Camera Dashboard %s\n", Version)
-		fmt.Printf("  Build time: %s\n", BuildTime)
-		fmt.Printf("  Go version: %s\n", GoVersion)
-		fmt.Printf("  Platform:   %s/%s\n", runtime.GOOS, runtime.GOARCH)
-		os.Exit(0)
+	var configPath string
+	var run runFlags
+	var selftestTimeout time.Duration
+	var selftestSimulate string
+	var record recordFlags
+
+	app := &cli.App{
+		Name:    "camera-dashboard",
+		Default: "run",
+		Flags: func(fs *flag.FlagSet) {
+			fs.StringVar(&configPath, "config", "", "Path to config.ini (default: ./config.ini or $CAMERA_DASHBOARD_CONFIG)")
+		},
+		Commands: []*cli.Command{
+			{
+				Name:    "run",
+				Summary: "Start the dashboard",
+				Flags:   run.register,
+				Run: func(args []string) error {
+					return runDashboard(configPath, run)
+				},
+			},
+			{
+				Name:    "query-cameras",
+				Summary: "List the attached cameras and their USB topology",
+				Run: func(args []string) error {
+					return printDiagnostics(loadConfig(configPath))
+				},
+			},
+			{
+				Name:    "selftest",
+				Summary: "Check config, tools, device access and that every camera delivers frames",
+				Flags: func(fs *flag.FlagSet) {
+					fs.DurationVar(&selftestTimeout, "timeout", 15*time.Second, "How long to wait for the cameras' first frames")
+					fs.StringVar(&selftestSimulate, "simulate", "", "Test these simulated cameras instead (see run -simulate)")
+				},
+				Run: func(args []string) error {
+					cfg, err := config.Load(configPath)
+					return selftest(cfg, err, selftestTimeout, selftestSimulate)
+				},
+			},
+			{
+				Name:    "record",
+				Summary: "Record every camera to MJPEG files without the UI",
+				Flags:   record.register,
+				Run: func(args []string) error {
+					cfg := loadConfig(configPath)
+					addSimulateSources(cfg, record.simulate)
+					return recordCameras(cfg, record)
+				},
+			},
+			{
+				Name:    "config",
+				Summary: "Config file tools",
+				Commands: []*cli.Command{
+					{
+						Name:    "validate",
+						Summary: "Check the config file; fails on problems that stop the dashboard",
+						Run: func(args []string) error {
+							return validateConfig(configPath)
+						},
+					},
+					{
+						Name:    "dump",
+						Summary: "Print the effective configuration",
+						Run: func(args []string) error {
+							return dumpConfig(configPath)
+						},
+					},
+				},
+			},
+			{
+				Name:    "version",
+				Summary: "Show version information",
+				Run: func(args []string) error {
+					printVersion()
+					return nil
+				},
+			},
+		},
+	}
+	os.Exit(app.Run(os.Args[1:]))
+}
+
+// runFlags are the run command's flags. The one-shot modes (-diagnostics,
+// bundles, thermal scenarios) predate the subcommands and stay for
+// existing scripts.
+type runFlags struct {
+	version         bool
+	refreshCaps     bool
+	diagnostics     bool
+	headless        bool
+	exportBundle    string
+	importBundle    string
+	thermalScenario string
+	layout          string
+	simulate        string
+	traceFrames     bool
+	traceCSV        string
+	traceSample     int
+}
+
+func (f *runFlags) register(fs *flag.FlagSet) {
+	fs.BoolVar(&f.version, "version", false, "Show version information")
+	fs.BoolVar(&f.version, "v", false, "Show version information (shorthand)")
+	fs.BoolVar(&f.refreshCaps, "refresh-caps", false, "Discard cached camera capabilities and re-probe with v4l2-ctl")
+	fs.BoolVar(&f.diagnostics, "diagnostics", false, "Print camera diagnostics (USB topology) and exit; same as query-cameras")
+	fs.BoolVar(&f.headless, "headless", false, "Run capture and monitoring without a display (no Fyne window)")
+	fs.StringVar(&f.exportBundle, "export-bundle", "", "Write config, camera identities and capability cache to this .tar.gz and exit")
+	fs.StringVar(&f.importBundle, "import-bundle", "", "Restore config and capability cache from a bundle made with -export-bundle and exit")
+	fs.StringVar(&f.thermalScenario, "thermal-scenario", "", "Replay a temperature/load CSV through the FPS controller with this config, print its transitions and exit")
+	fs.StringVar(&f.layout, "layout", "", "Startup layout preset from [layouts] (default: $CAMERA_DASHBOARD_LAYOUT or \"default\")")
+	fs.StringVar(&f.simulate, "simulate", "", "Comma-separated MJPEG files, videos or image directories to play in a loop instead of real cameras")
+	fs.BoolVar(&f.traceFrames, "trace-frames", false, "Log per-frame pipeline timing (read, parse, decode, buffer write, UI pick-up, refresh) of sampled frames")
+	fs.StringVar(&f.traceCSV, "trace-csv", "", "Write the -trace-frames timing to this CSV file instead of the log (implies -trace-frames)")
+	fs.IntVar(&f.traceSample, "trace-sample", 30, "Trace one frame in this many per camera")
+}
+
+func printVersion() {
+	fmt.Printf("Camera Dashboard %s\n", Version)
+	fmt.Printf("  Build time: %s\n", BuildTime)
+	fmt.Printf("  Go version: %s\n", GoVersion)
+	fmt.Printf("  Platform:   %s/%s\n", runtime.GOOS, runtime.GOARCH)
+}
+
+// loadConfig loads the config for the commands that only read it,
+// falling back to the defaults like the dashboard does.
+func loadConfig(path string) *config.Config {
+	cfg, err := config.Load(path)
+	if err != nil {
+		log.Printf("[Main] WARNING: Config load error: %v (using defaults)", err)
+		cfg = config.DefaultConfig()
+	}
+	cfg.PrepareStorage()
+	return cfg
+}
+
+// addSimulateSources adds a -simulate list to the config.
+func addSimulateSources(cfg *config.Config, list string) {
+	for _, source := range strings.Split(list, ",") {
+		if source = strings.TrimSpace(source); source != "" {
+			cfg.SimulateSources = append(cfg.SimulateSources, source)
+		}
+	}
+}
+
+// runDashboard is the run command: the dashboard until SIGINT/SIGTERM.
+func runDashboard(configPath string, flags runFlags) error {
+	if flags.version {
+		printVersion()
+		return nil
 	}
 
 	// Load configuration
-	cfg, err := config.Load(*configPath)
+	cfg, err := config.Load(configPath)
 	if err != nil {
 		log.Printf("[Main] WARNING: Config load error: %v (using defaults)", err)
 		cfg = config.DefaultConfig()
 	}
+	storageNotes := cfg.PrepareStorage()
+	addSimulateSources(cfg, flags.simulate)
+	cfg.TraceFrames = flags.traceFrames || flags.traceCSV != ""
+	cfg.TraceCSV = flags.traceCSV
+	cfg.TraceSampleEvery = flags.traceSample
 
 	// Configure logging (rotating file + optional stdout)
 	logCleanup, err := config.ConfigureLogging(cfg)
@@ -51,11 +203,21 @@ func main() {
 	}
 
 	log.Printf("[Main] Camera Dashboard %s starting...", Version)
+	for _, note := range storageNotes {
+		log.Printf("[Main] WARNING: read-only mode: %s", note)
+	}
+	if cfg.ReadOnlyRoot {
+		log.Printf("[Main] Read-only mode: logs %q, capability cache %q, snapshots %q",
+			cfg.LogFile, cfg.CapsCacheFile, cfg.SnapshotDir)
+	}
 	log.Printf("[Main] Config: %dx%d @ %d FPS, dynamic=%v, slots=%d",
 		cfg.CaptureWidth, cfg.CaptureHeight, cfg.CaptureFPS,
 		cfg.DynamicFPSEnabled, cfg.CameraSlotCount)
 
 	// Validate config
+	for _, w := range cfg.Warnings {
+		log.Printf("[Main] WARNING: %s: %s", cfg.Path, w)
+	}
 	ok, warnings := cfg.Validate()
 	if !ok {
 		log.Printf("[Main] WARNING: Config validation failed!")
@@ -64,7 +226,92 @@ func main() {
 		log.Printf("[Main] WARNING: %s", w)
 	}
 
-	app := ui.NewApp(cfg)
+	if flags.refreshCaps {
+		if err := camera.InvalidateCapabilityCache(cfg.CapsCacheFile); err != nil {
+			log.Printf("[Main] WARNING: Failed to clear capability cache: %v", err)
+		} else {
+			log.Printf("[Main] Capability cache cleared (%s)", cfg.CapsCacheFile)
+		}
+	}
+
+	if runtimeName := helpers.DetectContainer(); runtimeName != "" {
+		log.Printf("[Main] Running in a container (%s)", runtimeName)
+		for _, issue := range helpers.CheckDeviceAccess(!flags.headless) {
+			log.Printf("[Main] WARNING: %s - pass %s", issue.Problem, issue.Fix)
+		}
+	}
+
+	if flags.diagnostics {
+		return printDiagnostics(cfg)
+	}
+	if flags.thermalScenario != "" {
+		if err := printThermalScenario(cfg, flags.thermalScenario); err != nil {
+			return fmt.Errorf("thermal scenario: %w", err)
+		}
+		return nil
+	}
+	if flags.exportBundle != "" {
+		if err := exportSetupBundle(cfg, flags.exportBundle); err != nil {
+			return fmt.Errorf("export failed: %w", err)
+		}
+		return nil
+	}
+	if flags.importBundle != "" {
+		if err := importSetupBundle(cfg, flags.importBundle); err != nil {
+			return fmt.Errorf("import failed: %w", err)
+		}
+		return nil
+	}
+
+	if cfg.GoMaxProcs > 0 {
+		prev := runtime.GOMAXPROCS(cfg.GoMaxProcs)
+		log.Printf("[Main] GOMAXPROCS %d -> %d", prev, cfg.GoMaxProcs)
+	}
+
+	var app *ui.App
+	switch {
+	case flags.headless:
+		app = ui.NewHeadlessApp(cfg)
+	case cfg.DisplayBackend == "framebuffer":
+		if fb, err := ui.OpenFramebuffer(cfg.FramebufferDevice); err != nil {
+			log.Printf("[Main] WARNING: framebuffer %s: %v - using the Fyne window", cfg.FramebufferDevice, err)
+			app = ui.NewApp(cfg)
+		} else {
+			app = ui.NewRenderApp(cfg, fb)
+		}
+	default:
+		app = ui.NewApp(cfg)
+	}
+
+	// Startup layout: the launcher (normal boot, reverse-gear wake, remote
+	// request) picks a [layouts] preset
+	layoutName := flags.layout
+	if layoutName == "" {
+		layoutName = os.Getenv("CAMERA_DASHBOARD_LAYOUT")
+	}
+	app.SetStartupLayout(layoutName)
+
+	// Hot-reload runtime-changeable settings on file change or SIGHUP
+	watcher := config.NewWatcher(configPath, cfg)
+	watcher.Subscribe(func(cfg *config.Config, changed []string) {
+		for _, name := range changed {
+			if name == "LogLevel" {
+				config.SetLogLevel(cfg.LogLevel)
+			}
+		}
+	})
+	app.WatchConfig(watcher)
+	watcher.Start(2 * time.Second)
+	defer watcher.Stop()
+
+	hupCh := make(chan os.Signal, 1)
+	signal.Notify(hupCh, syscall.SIGHUP)
+	go func() {
+		for range hupCh {
+			log.Println("[Main] Received SIGHUP, reloading config...")
+			watcher.Reload()
+		}
+	}()
 
 	// Setup signal handling for clean shutdown
 	sigCh := make(chan os.Signal, 1)
@@ -74,6 +321,11 @@ func main() {
 		sig := <-sigCh
 		log.Printf("[Main] Received signal %v, cleaning up...", sig)
 		app.Cleanup()
+		// os.Exit skips the deferred calls: flush the log buffer and
+		// finish the background gzip first
+		if logCleanup != nil {
+			logCleanup()
+		}
 		os.Exit(0)
 	}()
 
@@ -81,4 +333,212 @@ func main() {
 
 	// Cleanup on normal exit
 	app.Cleanup()
+	return nil
+}
+
+// printDiagnostics discovers every attached camera and prints its modes
+// and USB topology, flagging cameras that share a USB 2.0 bus.
+func printDiagnostics(cfg *config.Config) error {
+	settings := camera.DefaultSettings()
+	settings.MaxCameras = 8
+	settings.CapsCachePath = cfg.CapsCacheFile
+	cams, err := camera.DiscoverCamerasWithSettings(settings)
+	if err != nil {
+		return fmt.Errorf("camera discovery failed: %w", err)
+	}
+	fmt.Printf("Cameras: %d\n", len(cams))
+	for _, cam := range cams {
+		caps := cam.Capabilities
+		fmt.Printf("  %-8s %-14s %s (%dx%d @ %d FPS %s)\n",
+			cam.DeviceID, cam.DevicePath, cam.Name, caps.MaxWidth, caps.MaxHeight, caps.MaxFPS, caps.Format)
+	}
+	fmt.Printf("\nUSB topology:\n")
+	for _, line := range camera.FormatUSBTopologyReport(cams) {
+		fmt.Printf("  %s\n", line)
+	}
+
+	runtimeName := helpers.DetectContainer()
+	if runtimeName == "" {
+		runtimeName = "none"
+	}
+	fmt.Printf("\nContainer: %s\n", runtimeName)
+	for _, issue := range helpers.CheckDeviceAccess(false) {
+		fmt.Printf("  MISSING: %s (pass %s)\n", issue.Problem, issue.Fix)
+	}
+	return nil
+}
+
+// printThermalScenario replays a scenario CSV through the performance
+// controller and prints every state, FPS or resolution tier change, then
+// the stability summary.
+func printThermalScenario(cfg *config.Config, path string) error {
+	scenario, err := perf.LoadScenario(path)
+	if err != nil {
+		return err
+	}
+	if !cfg.DynamicFPSEnabled {
+		fmt.Println("Note: dynamic_fps is off in this config; the controller only monitors")
+	}
+	log.SetOutput(io.Discard) // The controller's own log lines would interleave with the table
+	fmt.Printf("%8s  %6s  %5s  %-10s  %3s  %s\n", "TIME", "TEMP", "LOAD", "STATE", "FPS", "TIER")
+	samples, report := perf.Simulate(cfg, scenario)
+	for _, s := range perf.Transitions(samples) {
+		fmt.Printf("%7.0fs  %5.1fC  %5.2f  %-10s  %3d  %d\n", s.At.Seconds(), s.Temp, s.Load, s.State, s.FPS, s.Tier)
+	}
+	lines := report.Lines()
+	fmt.Printf("\n%s\n%s\n", lines[0], lines[1]) // Summary and time at FPS; the changes are listed above
+	return nil
+}
+
+// bundleCamera is one entry of a bundle's cameras.json: which physical
+// cameras the exported setup was using.
+type bundleCamera struct {
+	DeviceID   string `json:"device_id"`
+	DevicePath string `json:"device_path"`
+	Name       string `json:"name"`
+	Key        string `json:"key"` // camera.DisableKey: vendor:product:serial when available
+	USB        string `json:"usb"`
+}
+
+// exportSetupBundle writes a setup bundle (see config/bundle.go) to path.
+func exportSetupBundle(cfg *config.Config, path string) error {
+	files := make(map[string][]byte)
+	data, err := os.ReadFile(cfg.Path)
+	if err != nil {
+		return fmt.Errorf("read %s: %w", cfg.Path, err)
+	}
+	files[config.BundleConfigFile] = data
+	if data, err := os.ReadFile(cfg.CapsCacheFile); err == nil {
+		files[config.BundleCapsFile] = data
+	}
+
+	settings := camera.DefaultSettings()
+	settings.MaxCameras = 8
+	settings.CapsCachePath = cfg.CapsCacheFile
+	cams, err := camera.DiscoverCamerasWithSettings(settings)
+	if err != nil {
+		log.Printf("[Main] WARNING: camera discovery failed, bundle has no camera list: %v", err)
+	}
+	list := make([]bundleCamera, 0, len(cams))
+	for _, cam := range cams {
+		list = append(list, bundleCamera{
+			DeviceID:   cam.DeviceID,
+			DevicePath: cam.DevicePath,
+			Name:       cam.Name,
+			Key:        camera.DisableKey(cam),
+			USB:        cam.USB.String(),
+		})
+	}
+	if files[config.BundleCamerasFile], err = json.MarshalIndent(list, "", "  "); err != nil {
+		return err
+	}
+
+	if err := config.WriteBundle(path, files); err != nil {
+		return err
+	}
+	fmt.Printf("Exported %s (%d cameras) to %s\n", cfg.Path, len(list), path)
+	return nil
+}
+
+// importSetupBundle restores config.ini (the current one is kept as
+// config.ini.bak) and the capability cache from a bundle, then lists
+// which of the bundle's cameras are attached here. A config.ini with
+// warnings or failing validation is refused before anything is written.
+func importSetupBundle(cfg *config.Config, path string) error {
+	manifest, files, err := config.ReadBundle(path)
+	if err != nil {
+		return err
+	}
+	data, ok := files[config.BundleConfigFile]
+	if !ok {
+		return fmt.Errorf("%s has no %s", path, config.BundleConfigFile)
+	}
+	fmt.Printf("Bundle from %s, created %s\n", manifest.Host, manifest.Created.Format(time.RFC3339))
+
+	// The bundle's config is loaded from a temporary file next to
+	// config.ini first, so one that doesn't load cleanly leaves the
+	// current config in place
+	tmp, err := writeTempConfig(cfg.Path, data)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp) // No-op once renamed
+	// Without the environment: its overrides are this host's, and their
+	// warnings aren't the bundle's
+	imported, err := config.LoadFile(tmp)
+	if err != nil {
+		return fmt.Errorf("bundle %s: %w", config.BundleConfigFile, err)
+	}
+	if ok, _ := imported.Validate(); !ok || len(imported.Warnings) > 0 {
+		for _, w := range imported.Warnings {
+			fmt.Printf("  %s: %s\n", config.BundleConfigFile, w)
+		}
+		return fmt.Errorf("bundle %s does not load cleanly, %s left unchanged", config.BundleConfigFile, cfg.Path)
+	}
+
+	if old, err := os.ReadFile(cfg.Path); err == nil {
+		if err := os.WriteFile(cfg.Path+".bak", old, 0o644); err != nil {
+			return fmt.Errorf("back up %s: %w", cfg.Path, err)
+		}
+		fmt.Printf("Previous config saved as %s.bak\n", cfg.Path)
+	}
+	if err := os.Rename(tmp, cfg.Path); err != nil {
+		return fmt.Errorf("write %s: %w", cfg.Path, err)
+	}
+	fmt.Printf("Restored %s\n", cfg.Path)
+
+	// The imported config may move the cache
+	if caps, ok := files[config.BundleCapsFile]; ok {
+		if err := os.WriteFile(imported.CapsCacheFile, caps, 0o644); err != nil {
+			return fmt.Errorf("write %s: %w", imported.CapsCacheFile, err)
+		}
+		fmt.Printf("Restored %s\n", imported.CapsCacheFile)
+	}
+
+	var list []bundleCamera
+	if data, ok := files[config.BundleCamerasFile]; ok {
+		if err := json.Unmarshal(data, &list); err != nil {
+			return fmt.Errorf("bad %s: %w", config.BundleCamerasFile, err)
+		}
+	}
+	if len(list) > 0 {
+		settings := camera.DefaultSettings()
+		settings.MaxCameras = 8
+		cams, _ := camera.DiscoverCamerasWithSettings(settings)
+		present := make(map[string]bool)
+		for _, cam := range cams {
+			present[camera.DisableKey(cam)] = true
+		}
+		fmt.Println("\nCameras in bundle:")
+		for _, c := range list {
+			state := "not connected"
+			if present[c.Key] {
+				state = "connected"
+			}
+			fmt.Printf("  %s - %s (%s): %s\n", c.DevicePath, c.Name, c.USB, state)
+		}
+	}
+	fmt.Println("\nRestart the dashboard to apply.")
+	return nil
+}
+
+// writeTempConfig writes data to a new file in path's directory, for
+// renaming over path once it is known to load.
+func writeTempConfig(path string, data []byte) (string, error) {
+	f, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".import-*")
+	if err != nil {
+		return "", fmt.Errorf("write %s: %w", path, err)
+	}
+	_, err = f.Write(data)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Chmod(f.Name(), 0o644)
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return "", fmt.Errorf("write %s: %w", path, err)
+	}
+	return f.Name(), nil
 }
@@ -0,0 +1,239 @@
+package capture
+
+import (
+	"camera-dashboard-go/internal/camera"
+	"errors"
+	"image"
+	"time"
+)
+
+// =============================================================================
+// Capture library API
+// =============================================================================
+// The dashboard's capture stack without the Fyne UI: camera discovery,
+// FFmpeg / rpicam-vid capture with reconnects and stream health, the
+// lock-free frame buffers, clip history and the thermal FPS controller.
+// Other Go programs in this module (a headless recorder, a streaming
+// bridge) use it like this:
+//
+//   m := capture.NewManager(capture.DefaultOptions())
+//   if err := m.Start(); err != nil { ... }
+//   defer m.Stop()
+//   for _, cam := range m.Cameras() {
+//       frame := m.FrameBuffer(cam.ID).Read()
+//       ...
+//   }
+//
+// The types here are a stable facade over internal/camera and
+// internal/perf: fields and methods are only added, never changed or
+// removed, while the internals keep moving with the dashboard. The
+// packages log with the standard logger ([Discovery], [Capture], ...);
+// use log.SetOutput to redirect them.
+// =============================================================================
+
+// Options configures capture. The zero value of a field means the
+// DefaultOptions value.
+type Options struct {
+	Width, Height int    // Capture resolution
+	FPS           int    // Capture frame rate
+	Format        string // "mjpeg" or "yuyv"
+	MaxCameras    int    // Cameras used at most
+
+	Disabled       []string          // Cameras to skip: /dev/videoN, USB serial or vendor:product
+	PerCamera      []CameraOptions   // Capture settings of single cameras, first match wins
+	NetworkCameras map[string]string // Name -> RTSP/HTTP URL
+	CSICameras     bool              // Also use Pi camera modules (rpicam-vid)
+	Simulate       []string          // Play these MJPEG/video files or image directories instead of discovering cameras
+
+	Controls   map[string]int // V4L2 image controls set on every camera
+	ClipWindow time.Duration  // JPEG history kept per camera for ClipFrames (0 = none)
+
+	NoTestPattern bool // Send nothing while a camera is lost, instead of a test pattern
+}
+
+// CameraOptions replaces Width, Height, FPS and Format of Options for
+// the cameras Match selects. Zero fields keep the Options value.
+type CameraOptions struct {
+	Match         []string // /dev/videoN, videoN, vendor:product:serial, port:1-1.2 or serial:SERIAL
+	Width, Height int
+	FPS           int
+	Format        string
+}
+
+// DefaultOptions returns the dashboard's defaults: 640x480 MJPEG at
+// 25 FPS, up to 3 cameras, CSI camera modules included.
+func DefaultOptions() Options {
+	s := camera.DefaultSettings()
+	return Options{
+		Width:      s.Width,
+		Height:     s.Height,
+		FPS:        s.FPS,
+		Format:     s.Format,
+		MaxCameras: s.MaxCameras,
+		CSICameras: s.CSICameras,
+	}
+}
+
+// settings converts o into internal capture settings.
+func (o Options) settings() camera.Settings {
+	return camera.Settings{
+		Width:           o.Width,
+		Height:          o.Height,
+		FPS:             o.FPS,
+		Format:          o.Format,
+		MaxCameras:      o.MaxCameras,
+		DisabledDevices: o.Disabled,
+		PerCamera:       o.perCamera(),
+		NetworkCameras:  o.NetworkCameras,
+		CSICameras:      o.CSICameras,
+		Simulate:        o.Simulate,
+		Controls:        o.Controls,
+		ClipWindow:      o.ClipWindow,
+		NoTestPattern:   o.NoTestPattern,
+	}
+}
+
+func (o Options) perCamera() []camera.CameraOverride {
+	overrides := make([]camera.CameraOverride, len(o.PerCamera))
+	for i, c := range o.PerCamera {
+		overrides[i] = camera.CameraOverride{Match: c.Match, Width: c.Width, Height: c.Height, FPS: c.FPS, Format: c.Format}
+	}
+	return overrides
+}
+
+// Camera is a discovered camera.
+type Camera struct {
+	ID     string // "video0", "csi0", "cam0", "sim0", ...
+	Path   string // Device node, FFmpeg input or stream URL
+	Name   string // As the driver reports it
+	Width  int    // Capture mode
+	Height int
+	FPS    int
+	Format string // "mjpeg" or "yuyv"
+}
+
+func fromCamera(c camera.Camera) Camera {
+	return Camera{
+		ID:     c.DeviceID,
+		Path:   c.DevicePath,
+		Name:   c.Name,
+		Width:  c.Capabilities.MaxWidth,
+		Height: c.Capabilities.MaxHeight,
+		FPS:    c.Capabilities.MaxFPS,
+		Format: c.Capabilities.Format,
+	}
+}
+
+// FrameBuffer is the read side of a camera's latest-frame buffer. Reads
+// never block the capture goroutine.
+type FrameBuffer interface {
+	// Read returns the latest frame, or nil before the first one.
+	Read() image.Image
+	// ReadIfNew returns the latest frame and its sequence number if it
+	// is newer than lastRead.
+	ReadIfNew(lastRead uint64) (frame image.Image, seq uint64, ok bool)
+	// GetFrameCount returns the number of frames written.
+	GetFrameCount() uint64
+	// GetLastFrameTime returns when the latest frame was captured.
+	GetLastFrameTime() time.Time
+	// GetActualFPS returns the measured capture rate.
+	GetActualFPS() float64
+}
+
+var _ FrameBuffer = (*camera.FrameBuffer)(nil)
+
+// JPEGFrame is a frame from a camera's clip history.
+type JPEGFrame struct {
+	JPEG []byte
+	At   time.Time // Capture time
+}
+
+// ErrNoCamera is returned for a camera ID the manager doesn't have.
+var ErrNoCamera = errors.New("capture: no such camera")
+
+// Manager discovers the cameras and runs one capture worker per camera.
+type Manager struct {
+	m *camera.Manager
+}
+
+// NewManager creates a manager; Start discovers and starts the cameras.
+func NewManager(o Options) *Manager {
+	return &Manager{m: camera.NewManagerWithSettings(o.settings(), true)}
+}
+
+// Start discovers the cameras and starts capturing. Cameras start half
+// a second apart to spread the USB load, so Start takes a while with
+// several cameras. A camera that fails later is retried in the
+// background; its FrameBuffer shows a test pattern meanwhile.
+func (m *Manager) Start() error {
+	if err := m.m.Initialize(); err != nil {
+		return err
+	}
+	return m.m.Start()
+}
+
+// Stop stops every capture worker and its FFmpeg process.
+func (m *Manager) Stop() {
+	m.m.Stop()
+}
+
+// Cameras returns the discovered cameras.
+func (m *Manager) Cameras() []Camera {
+	cams := m.m.GetCameras()
+	out := make([]Camera, len(cams))
+	for i, c := range cams {
+		out[i] = fromCamera(c)
+	}
+	return out
+}
+
+// FrameBuffer returns the frame buffer of camera id, or nil.
+func (m *Manager) FrameBuffer(id string) FrameBuffer {
+	if fb := m.m.GetFrameBuffer(id); fb != nil {
+		return fb
+	}
+	return nil
+}
+
+// SignalLost reports whether camera id has no live picture (unplugged or
+// failing, being retried).
+func (m *Manager) SignalLost(id string) bool {
+	w := m.m.GetWorker(id)
+	return w == nil || w.SignalLost()
+}
+
+// ClipFrames returns camera id's JPEG history (Options.ClipWindow),
+// oldest first.
+func (m *Manager) ClipFrames(id string) []JPEGFrame {
+	w := m.m.GetWorker(id)
+	if w == nil {
+		return nil
+	}
+	frames := w.ClipFrames()
+	out := make([]JPEGFrame, len(frames))
+	for i, f := range frames {
+		out[i] = JPEGFrame{JPEG: f.JPEG, At: f.At}
+	}
+	return out
+}
+
+// SetFPS changes every camera's decode rate (frames are skipped; the
+// capture process keeps running). Cameras with their own frame rate are
+// scaled by fps / Config.FPS.
+func (m *Manager) SetFPS(fps int) {
+	m.m.SetFPS(fps)
+}
+
+// SetResolution caps every camera's capture resolution (0x0 = their
+// own), restarting the cameras whose size changes.
+func (m *Manager) SetResolution(width, height int) {
+	m.m.SetResolution(width, height)
+}
+
+// Restart restarts camera id's capture worker.
+func (m *Manager) Restart(id string) error {
+	if m.m.GetWorker(id) == nil {
+		return ErrNoCamera
+	}
+	return m.m.RestartCamera(id)
+}
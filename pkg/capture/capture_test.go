@@ -0,0 +1,102 @@
+package capture
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeClip(t *testing.T) string {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, 32, 24))
+	for i := range img.Pix {
+		img.Pix[i] = 0x80
+	}
+	img.Set(0, 0, color.White)
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, nil); err != nil {
+		t.Fatal(err)
+	}
+	clip := filepath.Join(t.TempDir(), "clip.mjpeg")
+	if err := os.WriteFile(clip, buf.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return clip
+}
+
+func TestOptions_Settings(t *testing.T) {
+	o := DefaultOptions()
+	o.Disabled = []string{"/dev/video2"}
+	o.ClipWindow = 5 * time.Second
+	s := o.settings()
+	if s.Width != 640 || s.Height != 480 || s.FPS != 25 || s.Format != "mjpeg" {
+		t.Errorf("settings = %dx%d@%d %s, want the 640x480@25 mjpeg defaults", s.Width, s.Height, s.FPS, s.Format)
+	}
+	if len(s.DisabledDevices) != 1 || s.DisabledDevices[0] != "/dev/video2" {
+		t.Errorf("DisabledDevices = %v", s.DisabledDevices)
+	}
+	if s.ClipWindow != 5*time.Second {
+		t.Errorf("ClipWindow = %v", s.ClipWindow)
+	}
+}
+
+func TestManager_Simulated(t *testing.T) {
+	o := DefaultOptions()
+	o.Width, o.Height, o.FPS = 32, 24, 30
+	o.Simulate = []string{writeClip(t)}
+	o.ClipWindow = time.Second
+	m := NewManager(o)
+	if err := m.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer m.Stop()
+
+	cams := m.Cameras()
+	if len(cams) != 1 || cams[0].ID != "sim0" {
+		t.Fatalf("cameras = %+v, want sim0", cams)
+	}
+	fb := m.FrameBuffer("sim0")
+	deadline := time.Now().Add(2 * time.Second)
+	for fb.GetFrameCount() < 3 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if n := fb.GetFrameCount(); n < 3 {
+		t.Fatalf("%d frames after 2s", n)
+	}
+	if frame := fb.Read(); frame == nil || frame.Bounds().Dx() != 32 {
+		t.Errorf("frame = %v, want 32x24", frame)
+	}
+	if m.SignalLost("sim0") {
+		t.Error("simulated camera reported signal lost")
+	}
+	if len(m.ClipFrames("sim0")) == 0 {
+		t.Error("no clip history with a ClipWindow")
+	}
+
+	if m.FrameBuffer("video9") != nil {
+		t.Error("FrameBuffer of an unknown camera is not nil")
+	}
+	if err := m.Restart("video9"); err != ErrNoCamera {
+		t.Errorf("Restart(unknown) = %v, want ErrNoCamera", err)
+	}
+}
+
+func TestController_FixedFPS(t *testing.T) {
+	o := DefaultOptions()
+	o.Simulate = []string{writeClip(t)}
+	m := NewManager(o)
+	if err := m.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer m.Stop()
+
+	c := NewController(m, ControllerOptions{FPS: 15})
+	if got := c.FPS(); got != 15 {
+		t.Errorf("FPS = %d, want 15", got)
+	}
+}
@@ -0,0 +1,99 @@
+package capture
+
+import (
+	"camera-dashboard-go/internal/config"
+	"camera-dashboard-go/internal/perf"
+	"time"
+)
+
+// =============================================================================
+// FPS controller
+// =============================================================================
+// The dashboard's thermal/load FPS controller (internal/perf) driving a
+// Manager. Without it the cameras run at Options.FPS.
+// =============================================================================
+
+// ControllerOptions configures the FPS controller.
+type ControllerOptions struct {
+	FPS     int  // Starting (and, when dynamic, highest) frame rate
+	MinFPS  int  // Lowest frame rate when dynamic (at least 10)
+	Dynamic bool // Adapt the frame rate to CPU temperature and load
+
+	// Thermal thresholds: "pi5" (the default) or "pi4"
+	ThermalProfile string
+	// Time between adjustments (0 = 2s)
+	CheckInterval time.Duration
+}
+
+// DefaultControllerOptions returns the dashboard's defaults: dynamic
+// FPS between 10 and 25 with the Pi 5 thresholds.
+func DefaultControllerOptions() ControllerOptions {
+	cfg := config.DefaultConfig()
+	return ControllerOptions{
+		FPS:            cfg.CaptureFPS,
+		MinFPS:         cfg.MinDynamicFPS,
+		Dynamic:        cfg.DynamicFPSEnabled,
+		ThermalProfile: "pi5",
+		CheckInterval:  time.Duration(cfg.PerfCheckIntervalMS) * time.Millisecond,
+	}
+}
+
+// Controller adjusts a Manager's frame rate to keep the board below its
+// thermal limits: it probes for the highest rate that stays cool, holds
+// it, and backs off as the temperature or load rises.
+type Controller struct {
+	sc *perf.SmartController
+}
+
+// NewController creates a controller for m. Create it after m.Start, as
+// it counts the cameras.
+func NewController(m *Manager, o ControllerOptions) *Controller {
+	cfg := config.DefaultConfig()
+	if o.FPS > 0 {
+		cfg.CaptureFPS = o.FPS
+	}
+	if o.MinFPS > 0 {
+		cfg.MinDynamicFPS = o.MinFPS
+	}
+	cfg.DynamicFPSEnabled = o.Dynamic
+	cfg.ThermalProfile = "pi5"
+	if o.ThermalProfile != "" {
+		cfg.ThermalProfile = o.ThermalProfile
+	}
+	if o.CheckInterval > 0 {
+		cfg.PerfCheckIntervalMS = int(o.CheckInterval / time.Millisecond)
+	}
+	return &Controller{sc: perf.NewSmartController(m.m, cfg)}
+}
+
+// Start applies the starting frame rate and starts adjusting.
+func (c *Controller) Start() {
+	c.sc.Start()
+}
+
+// Stop stops adjusting; the frame rate stays where it is.
+func (c *Controller) Stop() {
+	c.sc.Stop()
+}
+
+// FPS returns the current frame rate.
+func (c *Controller) FPS() int {
+	return c.sc.GetCurrentFPS()
+}
+
+// State returns "Probing", "Stable", "Recovering" or "Emergency".
+func (c *Controller) State() string {
+	return c.sc.GetState()
+}
+
+// Temperature returns the last CPU temperature read, in Celsius.
+func (c *Controller) Temperature() float64 {
+	return c.sc.GetTemperature()
+}
+
+// OnFPSChange registers fn to be told about every frame rate change and
+// its reason ("thermal", "load", "emergency", "probe", "recovery"). Call
+// it before Start; fn must return quickly.
+func (c *Controller) OnFPSChange(fn func(oldFPS, newFPS int, reason string)) {
+	c.sc.SetOnFPSChange(fn)
+}
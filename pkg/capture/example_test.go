@@ -0,0 +1,41 @@
+package capture_test
+
+import (
+	"camera-dashboard-go/pkg/capture"
+	"fmt"
+	"image/jpeg"
+	"log"
+	"os"
+	"time"
+)
+
+// A headless recorder: capture every camera and save a snapshot of each
+// every ten seconds.
+func Example() {
+	m := capture.NewManager(capture.DefaultOptions())
+	if err := m.Start(); err != nil {
+		log.Fatal(err)
+	}
+	defer m.Stop()
+
+	fps := capture.NewController(m, capture.DefaultControllerOptions())
+	fps.Start()
+	defer fps.Stop()
+
+	last := map[string]uint64{}
+	for range time.Tick(10 * time.Second) {
+		for _, cam := range m.Cameras() {
+			frame, seq, ok := m.FrameBuffer(cam.ID).ReadIfNew(last[cam.ID])
+			if !ok {
+				continue
+			}
+			last[cam.ID] = seq
+			f, err := os.Create(fmt.Sprintf("%s-%d.jpg", cam.ID, seq))
+			if err != nil {
+				log.Fatal(err)
+			}
+			jpeg.Encode(f, frame, nil)
+			f.Close()
+		}
+	}
+}